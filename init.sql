@@ -2,6 +2,7 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 CREATE EXTENSION IF NOT EXISTS "postgis";
 CREATE EXTENSION IF NOT EXISTS "vector";
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
 
 -- Create articles table with geography columns
 CREATE TABLE IF NOT EXISTS articles (
@@ -9,15 +10,29 @@ CREATE TABLE IF NOT EXISTS articles (
     title TEXT NOT NULL,
     description TEXT,
     url TEXT NOT NULL,
+    canonical_url TEXT,
     publication_date TIMESTAMP NOT NULL,
     source_name VARCHAR(255) NOT NULL,
     category TEXT[] NOT NULL,
     relevance_score FLOAT NOT NULL CHECK (relevance_score >= 0 AND relevance_score <= 1),
+    -- The feed-supplied relevance_score, kept once the score is recomputed from engagement
+    feed_relevance_score FLOAT,
     latitude FLOAT NOT NULL,
     longitude FLOAT NOT NULL,
-    created_at TIMESTAMP DEFAULT NOW(),
+    country TEXT,
+    region TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
     summary TEXT,
-    description_vector VECTOR(1536)
+    -- Readable text extracted from the article page when ENRICH_FETCH_CONTENT is enabled
+    content TEXT,
+    description_vector VECTOR(1536),
+    embedding_model VARCHAR(100),
+    embedded_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    sentiment VARCHAR(16) CHECK (sentiment IN ('positive', 'neutral', 'negative')),
+    -- The tenant (brand) the article belongs to; see TENANT_DEFAULT
+    tenant_id TEXT NOT NULL DEFAULT 'default'
 );
 
 -- Create user_events table with geography column
@@ -25,13 +40,220 @@ CREATE TABLE IF NOT EXISTS user_events (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     user_id VARCHAR(255) NOT NULL,
     article_id UUID NOT NULL,
-    event_type VARCHAR(50) NOT NULL CHECK (event_type IN ('view', 'click')),
+    event_type VARCHAR(50) NOT NULL CHECK (event_type IN ('view', 'click', 'share', 'bookmark', 'unbookmark', 'dismiss')),
+    value FLOAT,
     timestamp TIMESTAMP NOT NULL,
     latitude FLOAT NOT NULL,
     longitude FLOAT NOT NULL,
+    experiments JSONB,
+    tenant_id TEXT NOT NULL DEFAULT 'default',
     created_at TIMESTAMP DEFAULT NOW()
 );
 
+-- Create source_aliases table mapping source names used in queries ("ANI") to the source
+-- names stored on articles ("ANI English", "Asian News International")
+CREATE TABLE IF NOT EXISTS source_aliases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    alias VARCHAR(255) NOT NULL,
+    source_names TEXT[] NOT NULL CHECK (cardinality(source_names) > 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Create categories table defining the canonical category taxonomy. Incoming categories are
+-- matched against name and aliases ignoring case and stored as name.
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Create llm_audit_records table holding sampled query analysis calls for offline prompt
+-- evaluation (LLM_AUDIT_ENABLED). Queries are stored verbatim.
+CREATE TABLE IF NOT EXISTS llm_audit_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    query TEXT NOT NULL,
+    sources_hash VARCHAR(64) NOT NULL,
+    categories_hash VARCHAR(64) NOT NULL,
+    raw_output TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL,
+    analysis JSONB,
+    error TEXT,
+    latency_ms BIGINT NOT NULL,
+    prompt_tokens INT NOT NULL DEFAULT 0,
+    completion_tokens INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create saved_searches table holding searches users are alerted about when new matching
+-- articles arrive. keywords are the lowercased query terms, all of which must appear in an
+-- article's title or description for a keyword match.
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL DEFAULT 'default',
+    user_id VARCHAR(255) NOT NULL,
+    query TEXT NOT NULL,
+    keywords TEXT[] NOT NULL DEFAULT '{}',
+    category TEXT[] NOT NULL DEFAULT '{}',
+    source TEXT[] NOT NULL DEFAULT '{}',
+    query_vector VECTOR(1536),
+    embedding_model VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create saved_search_matches table recording the new articles that matched a saved search
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    similarity FLOAT,
+    keyword_match BOOLEAN NOT NULL DEFAULT FALSE,
+    matched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (search_id, article_id)
+);
+
+-- Create user_preferences table holding how much a user wants more (weight > 0) or less
+-- (weight < 0) of a category. Categories are stored lowercased; absent ones are neutral.
+-- User ids are only unique within a tenant.
+CREATE TABLE IF NOT EXISTS user_preferences (
+    tenant_id TEXT NOT NULL DEFAULT 'default',
+    user_id VARCHAR(255) NOT NULL,
+    category TEXT NOT NULL,
+    weight FLOAT NOT NULL CHECK (weight BETWEEN -1 AND 1),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT user_preferences_pkey PRIMARY KEY (tenant_id, user_id, category)
+);
+
+-- Create export_runs table recording each snapshot of the article corpus uploaded to
+-- object storage. watermark is the latest created_at exported, where the next incremental
+-- snapshot starts.
+CREATE TABLE IF NOT EXISTS export_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    mode VARCHAR(16) NOT NULL CHECK (mode IN ('full', 'incremental')),
+    include_vectors BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    object_key TEXT NOT NULL,
+    since TIMESTAMP,
+    watermark TIMESTAMP,
+    rows BIGINT NOT NULL DEFAULT 0,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_runs_started_at ON export_runs(started_at DESC);
+
+-- Create clusters table holding the story clusters recent articles are grouped into.
+-- centroid is the mean embedding of the members, which new articles are compared against;
+-- member_count is the number of members the mean was taken over. latest_at is the newest
+-- member's publication date, which decides when the cluster stops taking new members.
+CREATE TABLE IF NOT EXISTS clusters (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    centroid VECTOR(1536) NOT NULL,
+    embedding_model VARCHAR(100) NOT NULL,
+    member_count INTEGER NOT NULL DEFAULT 0,
+    latest_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create article_clusters table assigning each clustered article to one cluster
+CREATE TABLE IF NOT EXISTS article_clusters (
+    article_id UUID PRIMARY KEY REFERENCES articles(id) ON DELETE CASCADE,
+    cluster_id UUID NOT NULL REFERENCES clusters(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_clusters_latest_at ON clusters(latest_at DESC);
+CREATE INDEX IF NOT EXISTS idx_article_clusters_cluster ON article_clusters(cluster_id);
+
+-- Create query_logs table recording natural-language queries (QUERY_LOG_ENABLED). Clients are
+-- identified only by a salted hash of their user id or IP.
+CREATE TABLE IF NOT EXISTS query_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL DEFAULT 'default',
+    query TEXT NOT NULL,
+    had_location BOOLEAN NOT NULL DEFAULT FALSE,
+    result_count INT NOT NULL,
+    latency_ms BIGINT NOT NULL,
+    client_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_query_logs_created ON query_logs(created_at);
+
+-- Bring databases created before newer columns existed up to date
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS sentiment VARCHAR(16)
+    CHECK (sentiment IN ('positive', 'neutral', 'negative'));
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS embedding_model VARCHAR(100);
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS embedded_at TIMESTAMP;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS canonical_url TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS country TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS region TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS feed_relevance_score FLOAT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS content TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT NOW();
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT NOW();
+UPDATE articles SET created_at = NOW() WHERE created_at IS NULL;
+UPDATE articles SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE articles ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE articles ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE user_events ADD COLUMN IF NOT EXISTS value FLOAT;
+ALTER TABLE user_events ADD COLUMN IF NOT EXISTS experiments JSONB;
+-- Rows stored before tenants existed belong to the default tenant
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_events ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_preferences ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_preferences DROP CONSTRAINT IF EXISTS user_preferences_pkey;
+ALTER TABLE user_preferences ADD CONSTRAINT user_preferences_pkey PRIMARY KEY (tenant_id, user_id, category);
+ALTER TABLE query_logs ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE user_events DROP CONSTRAINT IF EXISTS user_events_event_type_check;
+ALTER TABLE user_events ADD CONSTRAINT user_events_event_type_check
+    CHECK (event_type IN ('view', 'click', 'share', 'bookmark', 'unbookmark', 'dismiss'));
+
+-- Create articles_archive table holding articles moved out of articles by the archive job. It
+-- mirrors the articles columns (new articles columns must be added here too) plus archived_at,
+-- and has no geography or vector index since only id lookups and filters read it.
+CREATE TABLE IF NOT EXISTS articles_archive (
+    id UUID PRIMARY KEY,
+    title TEXT NOT NULL,
+    description TEXT,
+    url TEXT NOT NULL,
+    canonical_url TEXT,
+    publication_date TIMESTAMP NOT NULL,
+    source_name VARCHAR(255) NOT NULL,
+    category TEXT[] NOT NULL,
+    relevance_score FLOAT NOT NULL,
+    feed_relevance_score FLOAT,
+    latitude FLOAT NOT NULL,
+    longitude FLOAT NOT NULL,
+    country TEXT,
+    region TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    summary TEXT,
+    content TEXT,
+    description_vector VECTOR(1536),
+    embedding_model VARCHAR(100),
+    embedded_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    sentiment VARCHAR(16),
+    tenant_id TEXT NOT NULL DEFAULT 'default',
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_articles_archive_publication_date ON articles_archive(publication_date DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_archive_category ON articles_archive USING GIN(category);
+CREATE INDEX IF NOT EXISTS idx_articles_archive_source_lower ON articles_archive(LOWER(source_name));
+CREATE INDEX IF NOT EXISTS idx_articles_archive_tenant ON articles_archive(tenant_id, publication_date DESC);
+
 -- Create indexes for articles table
 -- GIN index for array category field
 CREATE INDEX IF NOT EXISTS idx_articles_category ON articles USING GIN(category);
@@ -39,15 +261,72 @@ CREATE INDEX IF NOT EXISTS idx_articles_category ON articles USING GIN(category)
 -- B-tree index for source_name
 CREATE INDEX IF NOT EXISTS idx_articles_source ON articles(source_name);
 
+-- Expression index for case-insensitive matching of aliased source names
+CREATE INDEX IF NOT EXISTS idx_articles_source_lower ON articles(LOWER(source_name));
+
 -- B-tree index for relevance_score
 CREATE INDEX IF NOT EXISTS idx_articles_score ON articles(relevance_score DESC);
 
 -- B-tree index for publication_date
 CREATE INDEX IF NOT EXISTS idx_articles_publication_date ON articles(publication_date DESC);
 
+-- B-tree index for sentiment filters
+CREATE INDEX IF NOT EXISTS idx_articles_sentiment ON articles(sentiment);
+
+-- B-tree index for url
+CREATE INDEX IF NOT EXISTS idx_articles_url ON articles(url);
+
+-- Unique index on the canonical url so the same story cannot be stored twice under
+-- superficially different urls. Rows stored before canonicalization have NULL here. Tenants
+-- keep separate corpora, so each may store the same story once.
+DROP INDEX IF EXISTS idx_articles_canonical_url;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_tenant_canonical_url ON articles(tenant_id, canonical_url);
+
+-- B-tree index scoping article listings to a tenant
+CREATE INDEX IF NOT EXISTS idx_articles_tenant ON articles(tenant_id, publication_date DESC) WHERE deleted_at IS NULL;
+
+-- Case-insensitive indexes for country and region filters
+CREATE INDEX IF NOT EXISTS idx_articles_country ON articles(LOWER(country));
+CREATE INDEX IF NOT EXISTS idx_articles_region ON articles(LOWER(region));
+
+-- Trigram index for title similarity (duplicate detection during loads)
+CREATE INDEX IF NOT EXISTS idx_articles_title_trgm ON articles USING GIN(title gin_trgm_ops);
+
+-- Index for polling by ingestion time (ingested_after), matching its (created_at, id) order
+CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles(created_at, id);
+
 -- Index for latitude/longitude queries
 CREATE INDEX IF NOT EXISTS idx_articles_lat_lon ON articles(latitude, longitude);
 
+-- Partial index so soft-deleted rows are cheap to exclude
+CREATE INDEX IF NOT EXISTS idx_articles_not_deleted ON articles(publication_date DESC) WHERE deleted_at IS NULL;
+
+-- Keyset pagination index for GET /api/v1/news, ordered by (publication_date, id)
+CREATE INDEX IF NOT EXISTS idx_articles_page ON articles(publication_date DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- HNSW index for semantic search by cosine distance. The build parameters match the
+-- VECTOR_* defaults; POST /api/v1/admin/vector-index/reindex rebuilds it with the configured ones
+CREATE INDEX IF NOT EXISTS idx_articles_description_vector ON articles
+    USING hnsw (description_vector vector_cosine_ops) WITH (m = 16, ef_construction = 64);
+
+-- Aliases are unique ignoring case, which is how they are looked up
+CREATE UNIQUE INDEX IF NOT EXISTS idx_source_aliases_alias ON source_aliases(LOWER(alias));
+
+-- Category names are unique ignoring case; uniqueness across aliases is enforced on write
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories(LOWER(name));
+
+-- Index for paging through audit records newest first and purging old ones
+CREATE INDEX IF NOT EXISTS idx_llm_audit_records_created ON llm_audit_records(created_at DESC, id DESC);
+
+-- Index for reporting a tenant's most frequent queries
+CREATE INDEX IF NOT EXISTS idx_query_logs_tenant ON query_logs(tenant_id, created_at);
+
+-- Indexes for listing a user's saved searches and a search's latest matches
+DROP INDEX IF EXISTS idx_saved_searches_user;
+CREATE INDEX IF NOT EXISTS idx_saved_searches_tenant_user ON saved_searches(tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_search ON saved_search_matches(search_id, matched_at DESC);
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_article ON saved_search_matches(article_id);
+
 -- Create indexes for user_events table
 -- Composite index for article_id and timestamp queries
 CREATE INDEX IF NOT EXISTS idx_user_events_article ON user_events(article_id, timestamp DESC);
@@ -59,7 +338,15 @@ CREATE INDEX IF NOT EXISTS idx_user_events_lat_lon ON user_events(latitude, long
 CREATE INDEX IF NOT EXISTS idx_user_events_timestamp ON user_events(timestamp DESC);
 
 -- B-tree index for user_id queries
-CREATE INDEX IF NOT EXISTS idx_user_events_user ON user_events(user_id);
+DROP INDEX IF EXISTS idx_user_events_user;
+CREATE INDEX IF NOT EXISTS idx_user_events_tenant_user ON user_events(tenant_id, user_id);
+
+-- B-tree index for the tenant-wide event statistics
+CREATE INDEX IF NOT EXISTS idx_user_events_tenant ON user_events(tenant_id, timestamp DESC);
+
+-- Partial index for the latest bookmark state of each user and article
+CREATE INDEX IF NOT EXISTS idx_user_events_bookmarks ON user_events(user_id, article_id, timestamp DESC)
+    WHERE event_type IN ('bookmark', 'unbookmark');
 
 CREATE INDEX articles_geo_idx ON articles USING GIST (
     (ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography)
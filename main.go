@@ -3,15 +3,18 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"news-inshorts/src/grpcserver"
 	"news-inshorts/src/infra"
 	"news-inshorts/src/middleware"
 	"news-inshorts/src/routes"
 
 	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -20,7 +23,7 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	infraInstance, err := infra.NewInfrastructure(cfg)
+	infraInstance, err := infra.NewInfrastructure(cfg, infra.GetLogger())
 	if err != nil {
 		log.Fatalf("Failed to initialize infrastructure: %v", err)
 	}
@@ -28,14 +31,18 @@ func main() {
 	defer infraInstance.Close()
 
 	app := fiber.New(fiber.Config{
-		ErrorHandler:          middleware.ErrorHandler,
+		ErrorHandler:          middleware.NewErrorHandler(infraInstance.Logger),
 		ReadTimeout:           cfg.Server.ReadTimeout,
 		WriteTimeout:          cfg.Server.WriteTimeout,
+		BodyLimit:             max(cfg.Server.BodyLimit, cfg.Server.LoadBodyLimit),
 		DisableStartupMessage: false,
 		AppName:               "Inshorts API v1.0",
 	})
 
-	routes.SetupRoutes(app, infraInstance, cfg)
+	ctrls := routes.SetupRoutes(app, infraInstance, cfg)
+
+	// Start background tasks registered during route setup
+	infraInstance.Scheduler.Start()
 
 	go func() {
 		addr := fmt.Sprintf(":%s", cfg.Server.Port)
@@ -48,6 +55,26 @@ func main() {
 		}
 	}()
 
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Port != "" {
+		addr := fmt.Sprintf(":%s", cfg.GRPC.Port)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+
+		grpcServer = grpcserver.NewServer(cfg, ctrls.Services, infraInstance.Logger)
+		go func() {
+			infraInstance.Logger.Info("Starting gRPC server", map[string]interface{}{
+				"address": addr,
+			})
+
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
@@ -58,5 +85,9 @@ func main() {
 		infraInstance.Logger.Error("Server forced to shutdown", err, nil)
 	}
 
+	if grpcServer != nil {
+		grpcserver.Shutdown(grpcServer, cfg.GRPC.ShutdownTimeout, infraInstance.Logger)
+	}
+
 	infraInstance.Logger.Info("Server stopped", nil)
 }
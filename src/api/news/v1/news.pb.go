@@ -0,0 +1,1364 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: news/v1/news.proto
+
+package newsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Location is a point on the globe in decimal degrees
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_news_v1_news_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Location) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Location) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+// Article is a news article
+type Article struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description     string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Url             string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	CanonicalUrl    string                 `protobuf:"bytes,5,opt,name=canonical_url,json=canonicalUrl,proto3" json:"canonical_url,omitempty"`
+	PublicationDate *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=publication_date,json=publicationDate,proto3" json:"publication_date,omitempty"`
+	SourceName      string                 `protobuf:"bytes,7,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	Category        []string               `protobuf:"bytes,8,rep,name=category,proto3" json:"category,omitempty"`
+	RelevanceScore  float64                `protobuf:"fixed64,9,opt,name=relevance_score,json=relevanceScore,proto3" json:"relevance_score,omitempty"`
+	Latitude        float64                `protobuf:"fixed64,10,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude       float64                `protobuf:"fixed64,11,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Country         string                 `protobuf:"bytes,12,opt,name=country,proto3" json:"country,omitempty"`
+	Region          string                 `protobuf:"bytes,13,opt,name=region,proto3" json:"region,omitempty"`
+	Summary         string                 `protobuf:"bytes,14,opt,name=summary,proto3" json:"summary,omitempty"`
+	// content is only set by GetArticle
+	Content string `protobuf:"bytes,15,opt,name=content,proto3" json:"content,omitempty"`
+	// sentiment is unset for articles that were never classified
+	Sentiment     *string                `protobuf:"bytes,16,opt,name=sentiment,proto3,oneof" json:"sentiment,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Article) Reset() {
+	*x = Article{}
+	mi := &file_news_v1_news_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Article) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Article) ProtoMessage() {}
+
+func (x *Article) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Article.ProtoReflect.Descriptor instead.
+func (*Article) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Article) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Article) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Article) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Article) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Article) GetCanonicalUrl() string {
+	if x != nil {
+		return x.CanonicalUrl
+	}
+	return ""
+}
+
+func (x *Article) GetPublicationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublicationDate
+	}
+	return nil
+}
+
+func (x *Article) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *Article) GetCategory() []string {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *Article) GetRelevanceScore() float64 {
+	if x != nil {
+		return x.RelevanceScore
+	}
+	return 0
+}
+
+func (x *Article) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Article) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *Article) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Article) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Article) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Article) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Article) GetSentiment() string {
+	if x != nil && x.Sentiment != nil {
+		return *x.Sentiment
+	}
+	return ""
+}
+
+func (x *Article) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Article) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// MatchInfo explains why an article matched a query
+type MatchInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// distance_km is set when a geographic filter was applied
+	DistanceKm *float64 `protobuf:"fixed64,1,opt,name=distance_km,json=distanceKm,proto3,oneof" json:"distance_km,omitempty"`
+	// similarity is the cosine similarity to the query when semantic search ran
+	Similarity        *float64 `protobuf:"fixed64,2,opt,name=similarity,proto3,oneof" json:"similarity,omitempty"`
+	MatchedCategories []string `protobuf:"bytes,3,rep,name=matched_categories,json=matchedCategories,proto3" json:"matched_categories,omitempty"`
+	MatchedSources    []string `protobuf:"bytes,4,rep,name=matched_sources,json=matchedSources,proto3" json:"matched_sources,omitempty"`
+	// also_reported_by lists the sources of near-duplicates collapsed into this article
+	AlsoReportedBy []string `protobuf:"bytes,5,rep,name=also_reported_by,json=alsoReportedBy,proto3" json:"also_reported_by,omitempty"`
+	// preference_boost is the factor the user's category preferences scaled the article's
+	// relevance by; unset when they left it unchanged
+	PreferenceBoost *float64 `protobuf:"fixed64,6,opt,name=preference_boost,json=preferenceBoost,proto3,oneof" json:"preference_boost,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MatchInfo) Reset() {
+	*x = MatchInfo{}
+	mi := &file_news_v1_news_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchInfo) ProtoMessage() {}
+
+func (x *MatchInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchInfo.ProtoReflect.Descriptor instead.
+func (*MatchInfo) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MatchInfo) GetDistanceKm() float64 {
+	if x != nil && x.DistanceKm != nil {
+		return *x.DistanceKm
+	}
+	return 0
+}
+
+func (x *MatchInfo) GetSimilarity() float64 {
+	if x != nil && x.Similarity != nil {
+		return *x.Similarity
+	}
+	return 0
+}
+
+func (x *MatchInfo) GetMatchedCategories() []string {
+	if x != nil {
+		return x.MatchedCategories
+	}
+	return nil
+}
+
+func (x *MatchInfo) GetMatchedSources() []string {
+	if x != nil {
+		return x.MatchedSources
+	}
+	return nil
+}
+
+func (x *MatchInfo) GetAlsoReportedBy() []string {
+	if x != nil {
+		return x.AlsoReportedBy
+	}
+	return nil
+}
+
+func (x *MatchInfo) GetPreferenceBoost() float64 {
+	if x != nil && x.PreferenceBoost != nil {
+		return *x.PreferenceBoost
+	}
+	return 0
+}
+
+// QueryArticle is an article matching a query with its match metadata
+type QueryArticle struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Article *Article               `protobuf:"bytes,1,opt,name=article,proto3" json:"article,omitempty"`
+	Match   *MatchInfo             `protobuf:"bytes,2,opt,name=match,proto3" json:"match,omitempty"`
+	Rank    int32                  `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`
+	// summary_lang is the language code of the summary, set when the query asked for one
+	SummaryLang   string `protobuf:"bytes,4,opt,name=summary_lang,json=summaryLang,proto3" json:"summary_lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryArticle) Reset() {
+	*x = QueryArticle{}
+	mi := &file_news_v1_news_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryArticle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryArticle) ProtoMessage() {}
+
+func (x *QueryArticle) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryArticle.ProtoReflect.Descriptor instead.
+func (*QueryArticle) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QueryArticle) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+func (x *QueryArticle) GetMatch() *MatchInfo {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *QueryArticle) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *QueryArticle) GetSummaryLang() string {
+	if x != nil {
+		return x.SummaryLang
+	}
+	return ""
+}
+
+type QueryArticlesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// location ranks nearby articles first and enables "near me" queries
+	Location *Location `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	// limit defaults to 5, at most 50
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// min_similarity overrides the configured minimum similarity of semantic matches
+	MinSimilarity *float64 `protobuf:"fixed64,4,opt,name=min_similarity,json=minSimilarity,proto3,oneof" json:"min_similarity,omitempty"`
+	// user_id biases the ranking by the user's category preferences
+	UserId string `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// summary_lang asks for summaries in the language with this ISO 639-1 code
+	SummaryLang string `protobuf:"bytes,6,opt,name=summary_lang,json=summaryLang,proto3" json:"summary_lang,omitempty"`
+	// no_correct runs the query as typed, without spelling correction
+	NoCorrect     bool `protobuf:"varint,7,opt,name=no_correct,json=noCorrect,proto3" json:"no_correct,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryArticlesRequest) Reset() {
+	*x = QueryArticlesRequest{}
+	mi := &file_news_v1_news_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryArticlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryArticlesRequest) ProtoMessage() {}
+
+func (x *QueryArticlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryArticlesRequest.ProtoReflect.Descriptor instead.
+func (*QueryArticlesRequest) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueryArticlesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *QueryArticlesRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *QueryArticlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryArticlesRequest) GetMinSimilarity() float64 {
+	if x != nil && x.MinSimilarity != nil {
+		return *x.MinSimilarity
+	}
+	return 0
+}
+
+func (x *QueryArticlesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueryArticlesRequest) GetSummaryLang() string {
+	if x != nil {
+		return x.SummaryLang
+	}
+	return ""
+}
+
+func (x *QueryArticlesRequest) GetNoCorrect() bool {
+	if x != nil {
+		return x.NoCorrect
+	}
+	return false
+}
+
+type QueryArticlesResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Articles []*QueryArticle        `protobuf:"bytes,1,rep,name=articles,proto3" json:"articles,omitempty"`
+	// total is the number of matching articles before truncation to the limit
+	Total int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// degraded is true when the LLM was unavailable and a rule-based fallback analyzed the query
+	Degraded       bool `protobuf:"varint,3,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	QueryTruncated bool `protobuf:"varint,4,opt,name=query_truncated,json=queryTruncated,proto3" json:"query_truncated,omitempty"`
+	// corrected_query is the query that was run when misspelled words were corrected
+	CorrectedQuery string `protobuf:"bytes,5,opt,name=corrected_query,json=correctedQuery,proto3" json:"corrected_query,omitempty"`
+	// relaxed lists the constraints relaxed, in order, because the query matched nothing
+	Relaxed       []string `protobuf:"bytes,6,rep,name=relaxed,proto3" json:"relaxed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryArticlesResponse) Reset() {
+	*x = QueryArticlesResponse{}
+	mi := &file_news_v1_news_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryArticlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryArticlesResponse) ProtoMessage() {}
+
+func (x *QueryArticlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryArticlesResponse.ProtoReflect.Descriptor instead.
+func (*QueryArticlesResponse) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *QueryArticlesResponse) GetArticles() []*QueryArticle {
+	if x != nil {
+		return x.Articles
+	}
+	return nil
+}
+
+func (x *QueryArticlesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *QueryArticlesResponse) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+func (x *QueryArticlesResponse) GetQueryTruncated() bool {
+	if x != nil {
+		return x.QueryTruncated
+	}
+	return false
+}
+
+func (x *QueryArticlesResponse) GetCorrectedQuery() string {
+	if x != nil {
+		return x.CorrectedQuery
+	}
+	return ""
+}
+
+func (x *QueryArticlesResponse) GetRelaxed() []string {
+	if x != nil {
+		return x.Relaxed
+	}
+	return nil
+}
+
+type FilterArticlesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Category []string               `protobuf:"bytes,1,rep,name=category,proto3" json:"category,omitempty"`
+	// category_mode is any or all, the default
+	CategoryMode    string   `protobuf:"bytes,2,opt,name=category_mode,json=categoryMode,proto3" json:"category_mode,omitempty"`
+	Source          []string `protobuf:"bytes,3,rep,name=source,proto3" json:"source,omitempty"`
+	ExcludeCategory []string `protobuf:"bytes,4,rep,name=exclude_category,json=excludeCategory,proto3" json:"exclude_category,omitempty"`
+	ExcludeSource   []string `protobuf:"bytes,5,rep,name=exclude_source,json=excludeSource,proto3" json:"exclude_source,omitempty"`
+	Country         []string `protobuf:"bytes,6,rep,name=country,proto3" json:"country,omitempty"`
+	Region          []string `protobuf:"bytes,7,rep,name=region,proto3" json:"region,omitempty"`
+	// location and radius (km) keep the articles near a point
+	Location       *Location `protobuf:"bytes,8,opt,name=location,proto3" json:"location,omitempty"`
+	Radius         float64   `protobuf:"fixed64,9,opt,name=radius,proto3" json:"radius,omitempty"`
+	ScoreThreshold float64   `protobuf:"fixed64,10,opt,name=score_threshold,json=scoreThreshold,proto3" json:"score_threshold,omitempty"`
+	// sentiment keeps articles with any of the sentiments: positive, neutral or negative
+	Sentiment []string `protobuf:"bytes,11,rep,name=sentiment,proto3" json:"sentiment,omitempty"`
+	// ingested_after keeps articles created after the instant, oldest first
+	IngestedAfter *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=ingested_after,json=ingestedAfter,proto3" json:"ingested_after,omitempty"`
+	// ingested_after_id is the id of the last article already seen at ingested_after
+	IngestedAfterId string `protobuf:"bytes,13,opt,name=ingested_after_id,json=ingestedAfterId,proto3" json:"ingested_after_id,omitempty"`
+	// limit caps the number of articles returned, at most 1000; 0 returns every match
+	Limit           int32 `protobuf:"varint,14,opt,name=limit,proto3" json:"limit,omitempty"`
+	IncludeArchived bool  `protobuf:"varint,15,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FilterArticlesRequest) Reset() {
+	*x = FilterArticlesRequest{}
+	mi := &file_news_v1_news_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterArticlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterArticlesRequest) ProtoMessage() {}
+
+func (x *FilterArticlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterArticlesRequest.ProtoReflect.Descriptor instead.
+func (*FilterArticlesRequest) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FilterArticlesRequest) GetCategory() []string {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetCategoryMode() string {
+	if x != nil {
+		return x.CategoryMode
+	}
+	return ""
+}
+
+func (x *FilterArticlesRequest) GetSource() []string {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetExcludeCategory() []string {
+	if x != nil {
+		return x.ExcludeCategory
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetExcludeSource() []string {
+	if x != nil {
+		return x.ExcludeSource
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetCountry() []string {
+	if x != nil {
+		return x.Country
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetRegion() []string {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetRadius() float64 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+func (x *FilterArticlesRequest) GetScoreThreshold() float64 {
+	if x != nil {
+		return x.ScoreThreshold
+	}
+	return 0
+}
+
+func (x *FilterArticlesRequest) GetSentiment() []string {
+	if x != nil {
+		return x.Sentiment
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetIngestedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IngestedAfter
+	}
+	return nil
+}
+
+func (x *FilterArticlesRequest) GetIngestedAfterId() string {
+	if x != nil {
+		return x.IngestedAfterId
+	}
+	return ""
+}
+
+func (x *FilterArticlesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FilterArticlesRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type FilterArticlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Articles      []*Article             `protobuf:"bytes,1,rep,name=articles,proto3" json:"articles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterArticlesResponse) Reset() {
+	*x = FilterArticlesResponse{}
+	mi := &file_news_v1_news_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterArticlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterArticlesResponse) ProtoMessage() {}
+
+func (x *FilterArticlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterArticlesResponse.ProtoReflect.Descriptor instead.
+func (*FilterArticlesResponse) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FilterArticlesResponse) GetArticles() []*Article {
+	if x != nil {
+		return x.Articles
+	}
+	return nil
+}
+
+// TrendingArticle is a trending article with the data it was ranked by
+type TrendingArticle struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Article *Article               `protobuf:"bytes,1,opt,name=article,proto3" json:"article,omitempty"`
+	// trending_score is the score the article was ranked by, between 0 and 1
+	TrendingScore float64 `protobuf:"fixed64,2,opt,name=trending_score,json=trendingScore,proto3" json:"trending_score,omitempty"`
+	Rank          int32   `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`
+	// event_counts counts the article's user events in the scoring window by event type
+	EventCounts map[string]int32 `protobuf:"bytes,4,rep,name=event_counts,json=eventCounts,proto3" json:"event_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// distance_km is the distance from the requested location
+	DistanceKm    float64 `protobuf:"fixed64,5,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrendingArticle) Reset() {
+	*x = TrendingArticle{}
+	mi := &file_news_v1_news_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrendingArticle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrendingArticle) ProtoMessage() {}
+
+func (x *TrendingArticle) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrendingArticle.ProtoReflect.Descriptor instead.
+func (*TrendingArticle) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TrendingArticle) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+func (x *TrendingArticle) GetTrendingScore() float64 {
+	if x != nil {
+		return x.TrendingScore
+	}
+	return 0
+}
+
+func (x *TrendingArticle) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *TrendingArticle) GetEventCounts() map[string]int32 {
+	if x != nil {
+		return x.EventCounts
+	}
+	return nil
+}
+
+func (x *TrendingArticle) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+type GetTrendingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// location defaults to the location of the caller's IP address, then the configured default
+	Location *Location `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// limit defaults to 10 and is capped at 100
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrendingRequest) Reset() {
+	*x = GetTrendingRequest{}
+	mi := &file_news_v1_news_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrendingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrendingRequest) ProtoMessage() {}
+
+func (x *GetTrendingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrendingRequest.ProtoReflect.Descriptor instead.
+func (*GetTrendingRequest) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTrendingRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetTrendingRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ResolvedLocation is the location trending articles were ranked for and where it came from
+type ResolvedLocation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Latitude  float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// source is query, ip or default
+	Source        string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolvedLocation) Reset() {
+	*x = ResolvedLocation{}
+	mi := &file_news_v1_news_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolvedLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolvedLocation) ProtoMessage() {}
+
+func (x *ResolvedLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolvedLocation.ProtoReflect.Descriptor instead.
+func (*ResolvedLocation) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ResolvedLocation) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *ResolvedLocation) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *ResolvedLocation) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetTrendingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Articles      []*TrendingArticle     `protobuf:"bytes,1,rep,name=articles,proto3" json:"articles,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Location      *ResolvedLocation      `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrendingResponse) Reset() {
+	*x = GetTrendingResponse{}
+	mi := &file_news_v1_news_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrendingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrendingResponse) ProtoMessage() {}
+
+func (x *GetTrendingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrendingResponse.ProtoReflect.Descriptor instead.
+func (*GetTrendingResponse) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetTrendingResponse) GetArticles() []*TrendingArticle {
+	if x != nil {
+		return x.Articles
+	}
+	return nil
+}
+
+func (x *GetTrendingResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetTrendingResponse) GetLocation() *ResolvedLocation {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type GetArticleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArticleRequest) Reset() {
+	*x = GetArticleRequest{}
+	mi := &file_news_v1_news_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArticleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArticleRequest) ProtoMessage() {}
+
+func (x *GetArticleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArticleRequest.ProtoReflect.Descriptor instead.
+func (*GetArticleRequest) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetArticleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetArticleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Article       *Article               `protobuf:"bytes,1,opt,name=article,proto3" json:"article,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArticleResponse) Reset() {
+	*x = GetArticleResponse{}
+	mi := &file_news_v1_news_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArticleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArticleResponse) ProtoMessage() {}
+
+func (x *GetArticleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_news_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArticleResponse.ProtoReflect.Descriptor instead.
+func (*GetArticleResponse) Descriptor() ([]byte, []int) {
+	return file_news_v1_news_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetArticleResponse) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+var File_news_v1_news_proto protoreflect.FileDescriptor
+
+const file_news_v1_news_proto_rawDesc = "" +
+	"\n" +
+	"\x12news/v1/news.proto\x12\anews.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"D\n" +
+	"\bLocation\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xfc\x04\n" +
+	"\aArticle\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12#\n" +
+	"\rcanonical_url\x18\x05 \x01(\tR\fcanonicalUrl\x12E\n" +
+	"\x10publication_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0fpublicationDate\x12\x1f\n" +
+	"\vsource_name\x18\a \x01(\tR\n" +
+	"sourceName\x12\x1a\n" +
+	"\bcategory\x18\b \x03(\tR\bcategory\x12'\n" +
+	"\x0frelevance_score\x18\t \x01(\x01R\x0erelevanceScore\x12\x1a\n" +
+	"\blatitude\x18\n" +
+	" \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\v \x01(\x01R\tlongitude\x12\x18\n" +
+	"\acountry\x18\f \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\r \x01(\tR\x06region\x12\x18\n" +
+	"\asummary\x18\x0e \x01(\tR\asummary\x12\x18\n" +
+	"\acontent\x18\x0f \x01(\tR\acontent\x12!\n" +
+	"\tsentiment\x18\x10 \x01(\tH\x00R\tsentiment\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\f\n" +
+	"\n" +
+	"_sentiment\"\xbc\x02\n" +
+	"\tMatchInfo\x12$\n" +
+	"\vdistance_km\x18\x01 \x01(\x01H\x00R\n" +
+	"distanceKm\x88\x01\x01\x12#\n" +
+	"\n" +
+	"similarity\x18\x02 \x01(\x01H\x01R\n" +
+	"similarity\x88\x01\x01\x12-\n" +
+	"\x12matched_categories\x18\x03 \x03(\tR\x11matchedCategories\x12'\n" +
+	"\x0fmatched_sources\x18\x04 \x03(\tR\x0ematchedSources\x12(\n" +
+	"\x10also_reported_by\x18\x05 \x03(\tR\x0ealsoReportedBy\x12.\n" +
+	"\x10preference_boost\x18\x06 \x01(\x01H\x02R\x0fpreferenceBoost\x88\x01\x01B\x0e\n" +
+	"\f_distance_kmB\r\n" +
+	"\v_similarityB\x13\n" +
+	"\x11_preference_boost\"\x9b\x01\n" +
+	"\fQueryArticle\x12*\n" +
+	"\aarticle\x18\x01 \x01(\v2\x10.news.v1.ArticleR\aarticle\x12(\n" +
+	"\x05match\x18\x02 \x01(\v2\x12.news.v1.MatchInfoR\x05match\x12\x12\n" +
+	"\x04rank\x18\x03 \x01(\x05R\x04rank\x12!\n" +
+	"\fsummary_lang\x18\x04 \x01(\tR\vsummaryLang\"\x8b\x02\n" +
+	"\x14QueryArticlesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12-\n" +
+	"\blocation\x18\x02 \x01(\v2\x11.news.v1.LocationR\blocation\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12*\n" +
+	"\x0emin_similarity\x18\x04 \x01(\x01H\x00R\rminSimilarity\x88\x01\x01\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12!\n" +
+	"\fsummary_lang\x18\x06 \x01(\tR\vsummaryLang\x12\x1d\n" +
+	"\n" +
+	"no_correct\x18\a \x01(\bR\tnoCorrectB\x11\n" +
+	"\x0f_min_similarity\"\xe8\x01\n" +
+	"\x15QueryArticlesResponse\x121\n" +
+	"\barticles\x18\x01 \x03(\v2\x15.news.v1.QueryArticleR\barticles\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1a\n" +
+	"\bdegraded\x18\x03 \x01(\bR\bdegraded\x12'\n" +
+	"\x0fquery_truncated\x18\x04 \x01(\bR\x0equeryTruncated\x12'\n" +
+	"\x0fcorrected_query\x18\x05 \x01(\tR\x0ecorrectedQuery\x12\x18\n" +
+	"\arelaxed\x18\x06 \x03(\tR\arelaxed\"\xb2\x04\n" +
+	"\x15FilterArticlesRequest\x12\x1a\n" +
+	"\bcategory\x18\x01 \x03(\tR\bcategory\x12#\n" +
+	"\rcategory_mode\x18\x02 \x01(\tR\fcategoryMode\x12\x16\n" +
+	"\x06source\x18\x03 \x03(\tR\x06source\x12)\n" +
+	"\x10exclude_category\x18\x04 \x03(\tR\x0fexcludeCategory\x12%\n" +
+	"\x0eexclude_source\x18\x05 \x03(\tR\rexcludeSource\x12\x18\n" +
+	"\acountry\x18\x06 \x03(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\a \x03(\tR\x06region\x12-\n" +
+	"\blocation\x18\b \x01(\v2\x11.news.v1.LocationR\blocation\x12\x16\n" +
+	"\x06radius\x18\t \x01(\x01R\x06radius\x12'\n" +
+	"\x0fscore_threshold\x18\n" +
+	" \x01(\x01R\x0escoreThreshold\x12\x1c\n" +
+	"\tsentiment\x18\v \x03(\tR\tsentiment\x12A\n" +
+	"\x0eingested_after\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\ringestedAfter\x12*\n" +
+	"\x11ingested_after_id\x18\r \x01(\tR\x0fingestedAfterId\x12\x14\n" +
+	"\x05limit\x18\x0e \x01(\x05R\x05limit\x12)\n" +
+	"\x10include_archived\x18\x0f \x01(\bR\x0fincludeArchived\"F\n" +
+	"\x16FilterArticlesResponse\x12,\n" +
+	"\barticles\x18\x01 \x03(\v2\x10.news.v1.ArticleR\barticles\"\xa7\x02\n" +
+	"\x0fTrendingArticle\x12*\n" +
+	"\aarticle\x18\x01 \x01(\v2\x10.news.v1.ArticleR\aarticle\x12%\n" +
+	"\x0etrending_score\x18\x02 \x01(\x01R\rtrendingScore\x12\x12\n" +
+	"\x04rank\x18\x03 \x01(\x05R\x04rank\x12L\n" +
+	"\fevent_counts\x18\x04 \x03(\v2).news.v1.TrendingArticle.EventCountsEntryR\veventCounts\x12\x1f\n" +
+	"\vdistance_km\x18\x05 \x01(\x01R\n" +
+	"distanceKm\x1a>\n" +
+	"\x10EventCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"Y\n" +
+	"\x12GetTrendingRequest\x12-\n" +
+	"\blocation\x18\x01 \x01(\v2\x11.news.v1.LocationR\blocation\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"d\n" +
+	"\x10ResolvedLocation\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"\x98\x01\n" +
+	"\x13GetTrendingResponse\x124\n" +
+	"\barticles\x18\x01 \x03(\v2\x18.news.v1.TrendingArticleR\barticles\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x125\n" +
+	"\blocation\x18\x03 \x01(\v2\x19.news.v1.ResolvedLocationR\blocation\"#\n" +
+	"\x11GetArticleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"@\n" +
+	"\x12GetArticleResponse\x12*\n" +
+	"\aarticle\x18\x01 \x01(\v2\x10.news.v1.ArticleR\aarticle2\xc1\x02\n" +
+	"\vNewsService\x12N\n" +
+	"\rQueryArticles\x12\x1d.news.v1.QueryArticlesRequest\x1a\x1e.news.v1.QueryArticlesResponse\x12Q\n" +
+	"\x0eFilterArticles\x12\x1e.news.v1.FilterArticlesRequest\x1a\x1f.news.v1.FilterArticlesResponse\x12H\n" +
+	"\vGetTrending\x12\x1b.news.v1.GetTrendingRequest\x1a\x1c.news.v1.GetTrendingResponse\x12E\n" +
+	"\n" +
+	"GetArticle\x12\x1a.news.v1.GetArticleRequest\x1a\x1b.news.v1.GetArticleResponseB&Z$news-inshorts/src/api/news/v1;newsv1b\x06proto3"
+
+var (
+	file_news_v1_news_proto_rawDescOnce sync.Once
+	file_news_v1_news_proto_rawDescData []byte
+)
+
+func file_news_v1_news_proto_rawDescGZIP() []byte {
+	file_news_v1_news_proto_rawDescOnce.Do(func() {
+		file_news_v1_news_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_news_v1_news_proto_rawDesc), len(file_news_v1_news_proto_rawDesc)))
+	})
+	return file_news_v1_news_proto_rawDescData
+}
+
+var file_news_v1_news_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_news_v1_news_proto_goTypes = []any{
+	(*Location)(nil),               // 0: news.v1.Location
+	(*Article)(nil),                // 1: news.v1.Article
+	(*MatchInfo)(nil),              // 2: news.v1.MatchInfo
+	(*QueryArticle)(nil),           // 3: news.v1.QueryArticle
+	(*QueryArticlesRequest)(nil),   // 4: news.v1.QueryArticlesRequest
+	(*QueryArticlesResponse)(nil),  // 5: news.v1.QueryArticlesResponse
+	(*FilterArticlesRequest)(nil),  // 6: news.v1.FilterArticlesRequest
+	(*FilterArticlesResponse)(nil), // 7: news.v1.FilterArticlesResponse
+	(*TrendingArticle)(nil),        // 8: news.v1.TrendingArticle
+	(*GetTrendingRequest)(nil),     // 9: news.v1.GetTrendingRequest
+	(*ResolvedLocation)(nil),       // 10: news.v1.ResolvedLocation
+	(*GetTrendingResponse)(nil),    // 11: news.v1.GetTrendingResponse
+	(*GetArticleRequest)(nil),      // 12: news.v1.GetArticleRequest
+	(*GetArticleResponse)(nil),     // 13: news.v1.GetArticleResponse
+	nil,                            // 14: news.v1.TrendingArticle.EventCountsEntry
+	(*timestamppb.Timestamp)(nil),  // 15: google.protobuf.Timestamp
+}
+var file_news_v1_news_proto_depIdxs = []int32{
+	15, // 0: news.v1.Article.publication_date:type_name -> google.protobuf.Timestamp
+	15, // 1: news.v1.Article.created_at:type_name -> google.protobuf.Timestamp
+	15, // 2: news.v1.Article.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: news.v1.QueryArticle.article:type_name -> news.v1.Article
+	2,  // 4: news.v1.QueryArticle.match:type_name -> news.v1.MatchInfo
+	0,  // 5: news.v1.QueryArticlesRequest.location:type_name -> news.v1.Location
+	3,  // 6: news.v1.QueryArticlesResponse.articles:type_name -> news.v1.QueryArticle
+	0,  // 7: news.v1.FilterArticlesRequest.location:type_name -> news.v1.Location
+	15, // 8: news.v1.FilterArticlesRequest.ingested_after:type_name -> google.protobuf.Timestamp
+	1,  // 9: news.v1.FilterArticlesResponse.articles:type_name -> news.v1.Article
+	1,  // 10: news.v1.TrendingArticle.article:type_name -> news.v1.Article
+	14, // 11: news.v1.TrendingArticle.event_counts:type_name -> news.v1.TrendingArticle.EventCountsEntry
+	0,  // 12: news.v1.GetTrendingRequest.location:type_name -> news.v1.Location
+	8,  // 13: news.v1.GetTrendingResponse.articles:type_name -> news.v1.TrendingArticle
+	10, // 14: news.v1.GetTrendingResponse.location:type_name -> news.v1.ResolvedLocation
+	1,  // 15: news.v1.GetArticleResponse.article:type_name -> news.v1.Article
+	4,  // 16: news.v1.NewsService.QueryArticles:input_type -> news.v1.QueryArticlesRequest
+	6,  // 17: news.v1.NewsService.FilterArticles:input_type -> news.v1.FilterArticlesRequest
+	9,  // 18: news.v1.NewsService.GetTrending:input_type -> news.v1.GetTrendingRequest
+	12, // 19: news.v1.NewsService.GetArticle:input_type -> news.v1.GetArticleRequest
+	5,  // 20: news.v1.NewsService.QueryArticles:output_type -> news.v1.QueryArticlesResponse
+	7,  // 21: news.v1.NewsService.FilterArticles:output_type -> news.v1.FilterArticlesResponse
+	11, // 22: news.v1.NewsService.GetTrending:output_type -> news.v1.GetTrendingResponse
+	13, // 23: news.v1.NewsService.GetArticle:output_type -> news.v1.GetArticleResponse
+	20, // [20:24] is the sub-list for method output_type
+	16, // [16:20] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_news_v1_news_proto_init() }
+func file_news_v1_news_proto_init() {
+	if File_news_v1_news_proto != nil {
+		return
+	}
+	file_news_v1_news_proto_msgTypes[1].OneofWrappers = []any{}
+	file_news_v1_news_proto_msgTypes[2].OneofWrappers = []any{}
+	file_news_v1_news_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_news_v1_news_proto_rawDesc), len(file_news_v1_news_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_news_v1_news_proto_goTypes,
+		DependencyIndexes: file_news_v1_news_proto_depIdxs,
+		MessageInfos:      file_news_v1_news_proto_msgTypes,
+	}.Build()
+	File_news_v1_news_proto = out.File
+	file_news_v1_news_proto_goTypes = nil
+	file_news_v1_news_proto_depIdxs = nil
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: news/v1/news.proto
+
+package newsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NewsService_QueryArticles_FullMethodName  = "/news.v1.NewsService/QueryArticles"
+	NewsService_FilterArticles_FullMethodName = "/news.v1.NewsService/FilterArticles"
+	NewsService_GetTrending_FullMethodName    = "/news.v1.NewsService/GetTrending"
+	NewsService_GetArticle_FullMethodName     = "/news.v1.NewsService/GetArticle"
+)
+
+// NewsServiceClient is the client API for NewsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NewsService exposes the article query, filter and trending endpoints of the HTTP API to
+// internal callers. Requests are validated like their HTTP counterparts and fail with
+// INVALID_ARGUMENT, carrying a BadRequest detail per invalid field.
+type NewsServiceClient interface {
+	// QueryArticles answers a natural-language query, like GET /api/v1/news/query
+	QueryArticles(ctx context.Context, in *QueryArticlesRequest, opts ...grpc.CallOption) (*QueryArticlesResponse, error)
+	// FilterArticles returns the articles matching structured filters, like GET /api/v1/news/filter
+	FilterArticles(ctx context.Context, in *FilterArticlesRequest, opts ...grpc.CallOption) (*FilterArticlesResponse, error)
+	// GetTrending returns the articles trending near a location, like GET /api/v1/news/trending
+	GetTrending(ctx context.Context, in *GetTrendingRequest, opts ...grpc.CallOption) (*GetTrendingResponse, error)
+	// GetArticle returns a live article by id, including its fetched content
+	GetArticle(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*GetArticleResponse, error)
+}
+
+type newsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNewsServiceClient(cc grpc.ClientConnInterface) NewsServiceClient {
+	return &newsServiceClient{cc}
+}
+
+func (c *newsServiceClient) QueryArticles(ctx context.Context, in *QueryArticlesRequest, opts ...grpc.CallOption) (*QueryArticlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryArticlesResponse)
+	err := c.cc.Invoke(ctx, NewsService_QueryArticles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsServiceClient) FilterArticles(ctx context.Context, in *FilterArticlesRequest, opts ...grpc.CallOption) (*FilterArticlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FilterArticlesResponse)
+	err := c.cc.Invoke(ctx, NewsService_FilterArticles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsServiceClient) GetTrending(ctx context.Context, in *GetTrendingRequest, opts ...grpc.CallOption) (*GetTrendingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTrendingResponse)
+	err := c.cc.Invoke(ctx, NewsService_GetTrending_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsServiceClient) GetArticle(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*GetArticleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetArticleResponse)
+	err := c.cc.Invoke(ctx, NewsService_GetArticle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NewsServiceServer is the server API for NewsService service.
+// All implementations must embed UnimplementedNewsServiceServer
+// for forward compatibility.
+//
+// NewsService exposes the article query, filter and trending endpoints of the HTTP API to
+// internal callers. Requests are validated like their HTTP counterparts and fail with
+// INVALID_ARGUMENT, carrying a BadRequest detail per invalid field.
+type NewsServiceServer interface {
+	// QueryArticles answers a natural-language query, like GET /api/v1/news/query
+	QueryArticles(context.Context, *QueryArticlesRequest) (*QueryArticlesResponse, error)
+	// FilterArticles returns the articles matching structured filters, like GET /api/v1/news/filter
+	FilterArticles(context.Context, *FilterArticlesRequest) (*FilterArticlesResponse, error)
+	// GetTrending returns the articles trending near a location, like GET /api/v1/news/trending
+	GetTrending(context.Context, *GetTrendingRequest) (*GetTrendingResponse, error)
+	// GetArticle returns a live article by id, including its fetched content
+	GetArticle(context.Context, *GetArticleRequest) (*GetArticleResponse, error)
+	mustEmbedUnimplementedNewsServiceServer()
+}
+
+// UnimplementedNewsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNewsServiceServer struct{}
+
+func (UnimplementedNewsServiceServer) QueryArticles(context.Context, *QueryArticlesRequest) (*QueryArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryArticles not implemented")
+}
+func (UnimplementedNewsServiceServer) FilterArticles(context.Context, *FilterArticlesRequest) (*FilterArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FilterArticles not implemented")
+}
+func (UnimplementedNewsServiceServer) GetTrending(context.Context, *GetTrendingRequest) (*GetTrendingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrending not implemented")
+}
+func (UnimplementedNewsServiceServer) GetArticle(context.Context, *GetArticleRequest) (*GetArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArticle not implemented")
+}
+func (UnimplementedNewsServiceServer) mustEmbedUnimplementedNewsServiceServer() {}
+func (UnimplementedNewsServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeNewsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NewsServiceServer will
+// result in compilation errors.
+type UnsafeNewsServiceServer interface {
+	mustEmbedUnimplementedNewsServiceServer()
+}
+
+func RegisterNewsServiceServer(s grpc.ServiceRegistrar, srv NewsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedNewsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NewsService_ServiceDesc, srv)
+}
+
+func _NewsService_QueryArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServiceServer).QueryArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NewsService_QueryArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServiceServer).QueryArticles(ctx, req.(*QueryArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NewsService_FilterArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilterArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServiceServer).FilterArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NewsService_FilterArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServiceServer).FilterArticles(ctx, req.(*FilterArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NewsService_GetTrending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServiceServer).GetTrending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NewsService_GetTrending_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServiceServer).GetTrending(ctx, req.(*GetTrendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NewsService_GetArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServiceServer).GetArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NewsService_GetArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServiceServer).GetArticle(ctx, req.(*GetArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NewsService_ServiceDesc is the grpc.ServiceDesc for NewsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NewsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "news.v1.NewsService",
+	HandlerType: (*NewsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "QueryArticles",
+			Handler:    _NewsService_QueryArticles_Handler,
+		},
+		{
+			MethodName: "FilterArticles",
+			Handler:    _NewsService_FilterArticles_Handler,
+		},
+		{
+			MethodName: "GetTrending",
+			Handler:    _NewsService_GetTrending_Handler,
+		},
+		{
+			MethodName: "GetArticle",
+			Handler:    _NewsService_GetArticle_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "news/v1/news.proto",
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ArchiveController handles HTTP requests for the article archive job
+type ArchiveController struct {
+	archiveService services.ArchiveService
+	logger         infra.Logger
+}
+
+// NewArchiveController creates a new instance of ArchiveController
+func NewArchiveController(archiveService services.ArchiveService, logger infra.Logger) *ArchiveController {
+	return &ArchiveController{
+		archiveService: archiveService,
+		logger:         logger,
+	}
+}
+
+// RunArchive handles POST /api/v1/admin/archive/run. A dry run (?dry_run=true) only counts the
+// articles that would be moved and answers right away; a real run starts a background job.
+func (ac *ArchiveController) RunArchive(c *fiber.Ctx) error {
+	if c.QueryBool("dry_run") {
+		run, err := ac.archiveService.Run(c.UserContext(), true)
+		if err != nil {
+			if errors.Is(err, services.ErrArchiveRunning) {
+				return archiveRunning(c)
+			}
+
+			ac.logger.Error("Failed to run archive dry run", err, nil)
+			return middleware.NewAppError(fiber.StatusInternalServerError, "ARCHIVE_RUN_FAILED", "Failed to run archive dry run", err)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(run)
+	}
+
+	job, err := ac.archiveService.StartRun()
+	if err != nil {
+		if errors.Is(err, services.ErrArchiveRunning) {
+			return archiveRunning(c)
+		}
+
+		ac.logger.Error("Failed to start archive run", err, nil)
+		return middleware.NewAppError(fiber.StatusInternalServerError, "ARCHIVE_RUN_FAILED", "Failed to start archive run", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(types.JobResponse{
+		Job: job,
+	})
+}
+
+// archiveRunning responds with 409 when another archive run is in progress
+func archiveRunning(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(types.ErrorResponse{
+		ErrorCode: "ARCHIVE_ALREADY_RUNNING",
+		Error:     "An archive run is already in progress",
+	})
+}
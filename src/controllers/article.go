@@ -1,30 +1,49 @@
 package controllers
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
 	"news-inshorts/src/models"
+	"news-inshorts/src/renderer"
 	"news-inshorts/src/repositories"
 	"news-inshorts/src/services"
 	"news-inshorts/src/types"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // ArticleController handles news-related HTTP requests
 type ArticleController struct {
 	articleService services.ArticleService
+	statsService   services.StatsService
+	feedService    services.FeedService
+	locator        services.ClientLocationService
+	queryLog       services.QueryLogService
 	articleRepo    repositories.ArticleRepository
+	strictJSON     bool
 	logger         infra.Logger
 }
 
 // NewArticleController creates a new instance of ArticleController
-func NewArticleController(articleService services.ArticleService, articleRepo repositories.ArticleRepository) *ArticleController {
+func NewArticleController(articleService services.ArticleService, statsService services.StatsService, feedService services.FeedService, locator services.ClientLocationService, queryLog services.QueryLogService, articleRepo repositories.ArticleRepository, strictJSON bool, logger infra.Logger) *ArticleController {
 	return &ArticleController{
 		articleService: articleService,
+		statsService:   statsService,
+		feedService:    feedService,
+		locator:        locator,
+		queryLog:       queryLog,
 		articleRepo:    articleRepo,
-		logger:         infra.GetLogger(),
+		strictJSON:     strictJSON,
+		logger:         logger,
 	}
 }
 
@@ -40,29 +59,111 @@ func (ac *ArticleController) QueryArticles(c *fiber.Ctx) error {
 	}
 
 	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	start := time.Now()
+	result, err := ac.articleService.ProcessArticleQuery(c.UserContext(), req.Query, req.Location, req.Limit, req.MinSimilarity, req.UserID, req.SummaryLang, req.NoCorrect)
+	if err != nil {
+		// The service logs the normalized query; the raw one may be arbitrarily long
+		fields := map[string]interface{}{
+			"query_length": len(req.Query),
+			"location":     req.Location,
+		}
+		var filterErr *services.FilterError
+		if errors.As(err, &filterErr) {
+			for key, value := range filterErr.LogFields() {
+				fields[key] = value
+			}
+		}
+		ac.logger.Error("Failed to process article query", err, fields)
+
+		if filterErr != nil {
+			return filterFailed(filterErr, err)
+		}
+		return middleware.NewAppError(fiber.StatusInternalServerError, "QUERY_PROCESSING_FAILED", "Failed to process query", err)
+	}
+
+	ac.queryLog.Record(c.UserContext(), models.QueryLogEntry{
+		Query:       result.Query,
+		HadLocation: req.Location != nil,
+		ResultCount: result.Total,
+		LatencyMs:   time.Since(start).Milliseconds(),
+	}, req.UserID, c.IP())
+
+	if result.Degraded {
+		c.Set("X-Degraded-Mode", "llm-unavailable")
+	}
+
+	response := types.QueryArticlesResponse{
+		Articles:       result.Articles,
+		Total:          result.Total,
+		Degraded:       result.Degraded,
+		QueryTruncated: result.QueryTruncated,
+		CorrectedQuery: result.CorrectedQuery,
+		Relaxed:        result.Relaxed,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// AnalyzeQuery handles GET /api/v1/news/query/analyze. It accepts the parameters of
+// QueryArticles and reports the LLM analysis and the filter plan without running it.
+func (ac *ArticleController) AnalyzeQuery(c *fiber.Ctx) error {
+	var req types.QueryArticlesRequest
+
+	if err := c.QueryParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
-			ErrorCode: "VALIDATION_ERROR",
-			Error:     err.Error(),
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
 		})
 	}
 
-	articles, err := ac.articleService.ProcessArticleQuery(req.Query, req.Location)
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	result, err := ac.articleService.AnalyzeQuery(c.UserContext(), req.Query, req.Location, req.MinSimilarity, req.UserID, req.NoCorrect)
 	if err != nil {
-		ac.logger.Error("Failed to process article query", err, map[string]interface{}{
-			"query":    req.Query,
-			"location": req.Location,
-		})
-		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
-			ErrorCode: "QUERY_PROCESSING_FAILED",
-			Error:     "Failed to process query",
+		ac.logger.Error("Failed to analyze article query", err, map[string]interface{}{
+			"query_length": len(req.Query),
 		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "QUERY_ANALYSIS_FAILED", "Failed to analyze query", err)
 	}
 
-	response := types.QueryArticlesResponse{
-		Articles: articles,
+	if result.Degraded {
+		c.Set("X-Degraded-Mode", "llm-unavailable")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response)
+	return c.Status(fiber.StatusOK).JSON(types.AnalyzeQueryResponse{
+		Query:          result.Query,
+		QueryTruncated: result.QueryTruncated,
+		CorrectedQuery: result.CorrectedQuery,
+		Analysis:       result.Analysis,
+		Plan:           result.Plan,
+		CacheHit:       result.CacheHit,
+		Degraded:       result.Degraded,
+	})
+}
+
+// filterFailed maps a failed filter chain step to its response. The error code names the
+// filter, e.g. FILTER_SEARCH_LLM_FAILED. LLM failures are 503 when the call was refused
+// locally (circuit open, no free slot, budget spent) and 502 when the LLM failed to answer;
+// database outages are 503, timeouts 504 and anything else 500.
+func filterFailed(filterErr *services.FilterError, err error) *middleware.AppError {
+	code := "FILTER_" + strings.ToUpper(filterErr.Filter)
+
+	switch {
+	case errors.Is(err, services.ErrCircuitOpen), errors.Is(err, services.ErrLLMBusy), errors.Is(err, services.ErrLLMBudgetExceeded):
+		return middleware.NewAppError(fiber.StatusServiceUnavailable, code+"_LLM_UNAVAILABLE", "LLM service unavailable", err)
+	case errors.Is(err, services.ErrLLMUnavailable):
+		return middleware.NewAppError(fiber.StatusBadGateway, code+"_LLM_FAILED", "LLM service failed", err)
+	case errors.Is(err, repositories.ErrDatabaseUnavailable):
+		return middleware.NewAppError(fiber.StatusServiceUnavailable, code+"_DATABASE_UNAVAILABLE", "Database connection error", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return middleware.NewAppError(fiber.StatusGatewayTimeout, code+"_TIMEOUT", "Request timed out", err)
+	}
+	return middleware.NewAppError(fiber.StatusInternalServerError, code+"_FAILED", "Failed to process query", err)
 }
 
 // GetTrending handles GET /api/v1/news/trending
@@ -75,34 +176,144 @@ func (ac *ArticleController) GetTrending(c *fiber.Ctx) error {
 			Error:     "Invalid query parameters",
 		})
 	}
+	req.UserLocation = c.Get(types.UserLocationHeader)
 
 	if err := req.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
-			ErrorCode: "VALIDATION_ERROR",
-			Error:     err.Error(),
-		})
+		return validationFailed(c, err)
 	}
 
-	articles, err := ac.articleService.GetTrendingNews(req.Lat, req.Lon, req.Limit)
+	// The response depends on the header, so caches must not share it across its values
+	c.Vary(types.UserLocationHeader)
+
+	location := ac.resolveTrendingLocation(c, &req)
+	ac.logger.Info("Resolved trending location", map[string]interface{}{
+		"source": location.Source,
+		"lat":    location.Latitude,
+		"lon":    location.Longitude,
+	})
+
+	articles, err := ac.articleService.GetTrendingNews(c.UserContext(), location.Latitude, location.Longitude, req.Limit)
 	if err != nil {
 		ac.logger.Error("Failed to retrieve trending news", err, map[string]interface{}{
-			"lat":   req.Lat,
-			"lon":   req.Lon,
-			"limit": req.Limit,
+			"lat":             location.Latitude,
+			"lon":             location.Longitude,
+			"location_source": location.Source,
+			"limit":           req.Limit,
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
-			ErrorCode: "TRENDING_NEWS_FAILED",
-			Error:     "Failed to retrieve trending news",
+		return middleware.NewAppError(fiber.StatusInternalServerError, "TRENDING_NEWS_FAILED", "Failed to retrieve trending news", err)
+	}
+
+	if req.Verbose != nil && !*req.Verbose {
+		bare := make([]models.Article, len(articles))
+		for i, article := range articles {
+			bare[i] = article.Article
+		}
+		return c.Status(fiber.StatusOK).JSON(types.LegacyTrendingArticlesResponse{
+			Articles: bare,
+			Total:    len(bare),
+			Location: location,
 		})
 	}
 
-	response := types.QueryArticlesResponse{
+	response := types.TrendingArticlesResponse{
 		Articles: articles,
+		Total:    len(articles),
+		Location: location,
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// GetTrendingTopics handles GET /api/v1/news/trending/topics
+func (ac *ArticleController) GetTrendingTopics(c *fiber.Ctx) error {
+	var req types.GetTrendingTopicsRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+	req.UserLocation = c.Get(types.UserLocationHeader)
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	c.Vary(types.UserLocationHeader)
+
+	location := ac.resolveTrendingLocation(c, req.TrendingRequest())
+
+	result, err := ac.articleService.GetTrendingTopics(c.UserContext(), location.Latitude, location.Longitude, req.Articles, req.Limit)
+	if err != nil {
+		ac.logger.Error("Failed to retrieve trending topics", err, map[string]interface{}{
+			"lat":      location.Latitude,
+			"lon":      location.Longitude,
+			"articles": req.Articles,
+			"limit":    req.Limit,
+		})
+		if errors.Is(err, services.ErrLLMUnavailable) {
+			return middleware.NewAppError(fiber.StatusServiceUnavailable, "TRENDING_TOPICS_UNAVAILABLE", "Topic extraction is currently unavailable", err)
+		}
+		return middleware.NewAppError(fiber.StatusInternalServerError, "TRENDING_TOPICS_FAILED", "Failed to retrieve trending topics", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.TrendingTopicsResponse{
+		Topics:   result.Topics,
+		Total:    len(result.Topics),
+		Partial:  result.Partial,
+		Location: location,
+	})
+}
+
+// resolveTrendingLocation picks the location to rank trending articles for: the lat/lon query
+// parameters, then the X-User-Location header, then the client IP, then the default location
+func (ac *ArticleController) resolveTrendingLocation(c *fiber.Ctx, req *types.GetTrendingRequest) types.ResolvedLocation {
+	switch {
+	case req.HasCoordinates():
+		return types.ResolvedLocation{Latitude: req.Lat, Longitude: req.Lon, Source: models.LocationSourceQuery}
+	case req.HeaderLocation != nil:
+		return types.ResolvedLocation{Latitude: req.HeaderLocation.Latitude, Longitude: req.HeaderLocation.Longitude, Source: models.LocationSourceHeader}
+	}
+
+	location, source := ac.locator.Locate(c.IP())
+	return types.ResolvedLocation{Latitude: location.Latitude, Longitude: location.Longitude, Source: source}
+}
+
+// ListArticles handles GET /api/v1/news
+func (ac *ArticleController) ListArticles(c *fiber.Ctx) error {
+	var req types.ListArticlesRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	articles, nextCursor, err := ac.articleService.ListArticles(c.UserContext(), req.Cursor, req.Limit)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+				ErrorCode: "INVALID_CURSOR",
+				Error:     "Invalid cursor",
+			})
+		}
+
+		ac.logger.Error("Failed to list articles", err, nil)
+		return middleware.NewAppError(fiber.StatusInternalServerError, "LIST_ARTICLES_FAILED", "Failed to list articles", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.ListArticlesResponse{
+		Articles:   articles,
+		NextCursor: nextCursor,
+	})
+}
+
 // FilterArticles handles GET /api/v1/news/filter
 func (ac *ArticleController) FilterArticles(c *fiber.Ctx) error {
 	var req types.FilterArticlesRequest
@@ -115,21 +326,131 @@ func (ac *ArticleController) FilterArticles(c *fiber.Ctx) error {
 	}
 
 	if err := req.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
-			ErrorCode: "VALIDATION_ERROR",
-			Error:     err.Error(),
-		})
+		return validationFailed(c, err)
 	}
 
-	articles, err := ac.articleService.FilterArticles(req)
+	articles, err := ac.articleService.FilterArticles(c.UserContext(), req)
 	if err != nil {
 		ac.logger.Error("Failed to filter articles", err, map[string]interface{}{
 			"filters": req,
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
-			ErrorCode: "FILTER_ARTICLES_FAILED",
-			Error:     "Failed to filter articles",
+		return middleware.NewAppError(fiber.StatusInternalServerError, "FILTER_ARTICLES_FAILED", "Failed to filter articles", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.FilterArticlesResponse{
+		Articles: articles,
+	})
+}
+
+// ExportArticles handles GET /api/v1/news/export
+func (ac *ArticleController) ExportArticles(c *fiber.Ctx) error {
+	var req types.ExportArticlesRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	filter := req.Filter()
+
+	// Count up front so truncation can be reported in headers before the body is streamed
+	size, err := ac.articleService.ExportSize(c.UserContext(), filter)
+	if err != nil {
+		ac.logger.Error("Failed to size article export", err, map[string]interface{}{
+			"filters": filter,
 		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "EXPORT_FAILED", "Failed to export articles", err)
+	}
+
+	filename := fmt.Sprintf("articles-%s.%s", time.Now().UTC().Format("20060102T150405Z"), req.Format)
+	c.Set(fiber.HeaderContentType, renderer.ExportContentType(req.Format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Set("X-Total-Count", strconv.FormatInt(size.Total, 10))
+	c.Set("X-Export-Rows", strconv.FormatInt(size.Rows, 10))
+	c.Set("X-Export-Truncated", strconv.FormatBool(size.Truncated))
+	c.Status(fiber.StatusOK)
+
+	// The body is written after the handler returns, when the request context is no longer
+	// usable, so only the tenant is carried over
+	exportCtx := infra.WithTenant(context.Background(), infra.TenantFromContext(c.UserContext(), infra.DefaultTenant))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		written, err := ac.articleService.ExportArticles(exportCtx, filter, req.Format, w)
+		if err != nil {
+			ac.logger.Error("Article export aborted", err, map[string]interface{}{
+				"filters": filter,
+				"written": written,
+			})
+			return
+		}
+
+		ac.logger.Info("Exported articles", map[string]interface{}{
+			"format":    req.Format,
+			"written":   written,
+			"total":     size.Total,
+			"truncated": size.Truncated,
+		})
+	})
+
+	return nil
+}
+
+// GetFeed handles GET /api/v1/news/feed.rss
+func (ac *ArticleController) GetFeed(c *fiber.Ctx) error {
+	var req types.FeedRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	body, err := ac.feedService.RenderRSS(c.UserContext(), req, c.BaseURL()+c.OriginalURL())
+	if err != nil {
+		ac.logger.Error("Failed to render RSS feed", err, map[string]interface{}{
+			"category": req.Category,
+			"source":   req.Source,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "FEED_RENDER_FAILED", "Failed to render feed", err)
+	}
+
+	c.Set(fiber.HeaderContentType, renderer.RSSContentType)
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// SearchArticles handles GET /api/v1/news/search
+func (ac *ArticleController) SearchArticles(c *fiber.Ctx) error {
+	var req types.SearchArticlesRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	articles, err := ac.articleService.SearchArticles(c.UserContext(), req)
+	if err != nil {
+		ac.logger.Error("Failed to search articles", err, map[string]interface{}{
+			"q":        req.Q,
+			"category": req.Category,
+			"source":   req.Source,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "SEARCH_ARTICLES_FAILED", "Failed to search articles", err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(types.FilterArticlesResponse{
@@ -155,35 +476,52 @@ func (ac *ArticleController) LoadData(c *fiber.Ctx) error {
 		})
 	}
 
-	stats, err := ac.articleService.LoadFromJSON(req.Filepath)
+	stats, err := ac.articleService.LoadFromJSON(c.UserContext(), req.Filepath, req.DryRun, req.DetectDuplicates)
+	if err == nil && stats.DryRun {
+		// Validation errors are the result of a dry run, not a failure
+		return c.Status(fiber.StatusOK).JSON(types.LoadDataResponse{
+			Success:             true,
+			Message:             "Dry run completed; no data was written",
+			TotalArticles:       stats.TotalArticles,
+			ValidationErrors:    stats.ValidationErrors,
+			DryRun:              true,
+			WouldInsert:         &stats.WouldInsert,
+			WouldSkip:           &stats.WouldSkip,
+			DuplicateURLs:       stats.DuplicateURLs,
+			SuspectedDuplicates: stats.SuspectedDuplicates,
+		})
+	}
 	if err != nil {
 		if stats != nil && len(stats.ValidationErrors) > 0 {
 			response := types.LoadDataResponse{
 				Success:          false,
+				ErrorCode:        "VALIDATION_ERROR",
+				Error:            "Validation failed",
 				Message:          "Validation failed",
 				TotalArticles:    stats.TotalArticles,
 				SuccessCount:     stats.SuccessCount,
 				ErrorCount:       stats.ErrorCount,
 				ValidationErrors: stats.ValidationErrors,
 			}
-			return c.Status(fiber.StatusBadRequest).JSON(response)
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(response)
 		}
 
 		ac.logger.Error("Failed to load data from file", err, map[string]interface{}{
 			"filepath": req.Filepath,
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
-			ErrorCode: "DATA_LOAD_FAILED",
-			Error:     "Failed to load data from file",
-		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "DATA_LOAD_FAILED", "Failed to load data from file", err)
 	}
 
 	response := types.LoadDataResponse{
-		Success:       true,
-		Message:       "Data loaded successfully",
-		TotalArticles: stats.TotalArticles,
-		SuccessCount:  stats.SuccessCount,
-		ErrorCount:    stats.ErrorCount,
+		Success:             true,
+		Message:             "Data loaded successfully",
+		TotalArticles:       stats.TotalArticles,
+		SuccessCount:        stats.SuccessCount,
+		ErrorCount:          stats.ErrorCount,
+		EnrichmentFailures:  stats.EnrichmentFailures,
+		DuplicateURLs:       stats.DuplicateURLs,
+		SuspectedDuplicates: stats.SuspectedDuplicates,
+		FailedChunks:        stats.FailedChunks,
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -193,18 +531,12 @@ func (ac *ArticleController) LoadData(c *fiber.Ctx) error {
 func (ac *ArticleController) CreateArticle(c *fiber.Ctx) error {
 	var req types.CreateArticleRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
-			ErrorCode: "INVALID_REQUEST_BODY",
-			Error:     "Invalid request body",
-		})
+	if err := parseBody(c, &req, ac.strictJSON); err != nil {
+		return invalidBody(c, err)
 	}
 
 	if err := req.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
-			ErrorCode: "VALIDATION_ERROR",
-			Error:     err.Error(),
-		})
+		return validationFailed(c, err)
 	}
 
 	publicationDate, err := time.Parse("2006-01-02T15:04:05", req.PublicationDate)
@@ -226,18 +558,21 @@ func (ac *ArticleController) CreateArticle(c *fiber.Ctx) error {
 		Latitude:        req.Latitude,
 		Longitude:       req.Longitude,
 		Summary:         req.Summary,
+		Country:         req.Country,
+		Region:          req.Region,
 	}
 
-	if err := ac.articleService.CreateArticle(article); err != nil {
+	if err := ac.articleService.CreateArticle(c.UserContext(), article); err != nil {
+		var validationErrors types.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return validationFailed(c, err)
+		}
 		ac.logger.Error("Failed to create article", err, map[string]interface{}{
 			"title":  req.Title,
 			"source": req.SourceName,
 			"url":    req.URL,
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
-			ErrorCode: "ARTICLE_CREATION_FAILED",
-			Error:     "Failed to create article",
-		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "ARTICLE_CREATION_FAILED", "Failed to create article", err)
 	}
 
 	response := types.CreateArticleResponse{
@@ -245,6 +580,194 @@ func (ac *ArticleController) CreateArticle(c *fiber.Ctx) error {
 		Message: "Article created successfully",
 		Article: *article,
 	}
+	if len(req.Category) == 0 {
+		response.AutoAssignedCategories = article.Category
+	}
 
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
+
+// Backfill handles POST /api/v1/news/backfill
+func (ac *ArticleController) Backfill(c *fiber.Ctx) error {
+	var req types.BackfillRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+				ErrorCode: "INVALID_REQUEST_BODY",
+				Error:     "Invalid request body",
+			})
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	if req.AfterID != "" {
+		if _, err := uuid.Parse(req.AfterID); err != nil {
+			var errs types.ValidationErrors
+			errs.Add("after_id", types.ValidationCodeInvalidFormat, "after_id must be a valid UUID")
+			return validationFailed(c, errs)
+		}
+	}
+
+	job := ac.articleService.StartBackfill(c.UserContext(), req.AfterID, req.MaxArticles)
+
+	return c.Status(fiber.StatusAccepted).JSON(types.JobResponse{
+		Job: job,
+	})
+}
+
+// GetArticleStats handles GET /api/v1/news/:id/stats
+func (ac *ArticleController) GetArticleStats(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_ARTICLE_ID",
+			Error:     "Article id must be a valid UUID",
+		})
+	}
+
+	stats, err := ac.statsService.GetArticleStats(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrArticleNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+				ErrorCode: "ARTICLE_NOT_FOUND",
+				Error:     "Article not found",
+			})
+		}
+
+		ac.logger.Error("Failed to retrieve article stats", err, map[string]interface{}{
+			"id": id,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "ARTICLE_STATS_FAILED", "Failed to retrieve article stats", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
+
+// SummarizeArticle handles POST /api/v1/news/:id/summarize
+func (ac *ArticleController) SummarizeArticle(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_ARTICLE_ID",
+			Error:     "Article id must be a valid UUID",
+		})
+	}
+
+	result, err := ac.articleService.SummarizeArticle(c.UserContext(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrArticleNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+				ErrorCode: "ARTICLE_NOT_FOUND",
+				Error:     "Article not found",
+			})
+		case errors.Is(err, services.ErrSummaryInProgress):
+			return c.Status(fiber.StatusConflict).JSON(types.ErrorResponse{
+				ErrorCode: "SUMMARY_IN_PROGRESS",
+				Error:     "The article's summary is already being regenerated",
+			})
+		}
+
+		// LLM outages map to 503 LLM_UNAVAILABLE in the error handler
+		ac.logger.Error("Failed to summarize article", err, map[string]interface{}{
+			"id": id,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "ARTICLE_SUMMARIZE_FAILED", "Failed to summarize article", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.SummarizeArticleResponse{
+		ID:         result.ID,
+		OldSummary: result.OldSummary,
+		NewSummary: result.NewSummary,
+	})
+}
+
+// GetCorpusStats handles GET /api/v1/admin/stats/articles
+func (ac *ArticleController) GetCorpusStats(c *fiber.Ctx) error {
+	stats, err := ac.statsService.GetCorpusStats(c.UserContext())
+	if err != nil {
+		ac.logger.Error("Failed to retrieve corpus stats", err, nil)
+		return middleware.NewAppError(fiber.StatusInternalServerError, "CORPUS_STATS_FAILED", "Failed to retrieve corpus stats", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
+
+// GetInteractionStats handles GET /api/v1/admin/stats/interactions
+func (ac *ArticleController) GetInteractionStats(c *fiber.Ctx) error {
+	var req types.InteractionStatsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	stats, err := ac.statsService.GetInteractionStats(c.UserContext(), req.FromTime, req.ToTime, req.Bucket)
+	if err != nil {
+		ac.logger.Error("Failed to retrieve interaction stats", err, map[string]interface{}{
+			"from":   req.FromTime,
+			"to":     req.ToTime,
+			"bucket": req.Bucket,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "INTERACTION_STATS_FAILED", "Failed to retrieve interaction stats", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
+
+// DeleteArticle handles DELETE /api/v1/news/:id (soft delete)
+func (ac *ArticleController) DeleteArticle(c *fiber.Ctx) error {
+	return ac.handleArticleAction(c, ac.articleService.DeleteArticle, "ARTICLE_DELETE_FAILED", "Article deleted successfully")
+}
+
+// RestoreArticle handles POST /api/v1/admin/news/:id/restore
+func (ac *ArticleController) RestoreArticle(c *fiber.Ctx) error {
+	return ac.handleArticleAction(c, ac.articleService.RestoreArticle, "ARTICLE_RESTORE_FAILED", "Article restored successfully")
+}
+
+// PurgeArticle handles DELETE /api/v1/admin/news/:id (permanent removal)
+func (ac *ArticleController) PurgeArticle(c *fiber.Ctx) error {
+	return ac.handleArticleAction(c, ac.articleService.PurgeArticle, "ARTICLE_PURGE_FAILED", "Article purged successfully")
+}
+
+// handleArticleAction validates the :id path parameter, runs the given lifecycle action
+// and maps its result to a response
+func (ac *ArticleController) handleArticleAction(c *fiber.Ctx, action func(ctx context.Context, id string) error, failureCode, successMessage string) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_ARTICLE_ID",
+			Error:     "Article id must be a valid UUID",
+		})
+	}
+
+	if err := action(c.UserContext(), id); err != nil {
+		if errors.Is(err, repositories.ErrArticleNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+				ErrorCode: "ARTICLE_NOT_FOUND",
+				Error:     "Article not found",
+			})
+		}
+
+		ac.logger.Error("Failed to apply article action", err, map[string]interface{}{
+			"id":   id,
+			"path": c.Path(),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, failureCode, "Failed to process article", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.ArticleActionResponse{
+		Success: true,
+		Message: successMessage,
+		ID:      id,
+	})
+}
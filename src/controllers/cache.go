@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"news-inshorts/src/infra"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheController handles HTTP requests for cache administration
+type CacheController struct {
+	cacheService services.CacheService
+	logger       infra.Logger
+}
+
+// NewCacheController creates a new instance of CacheController
+func NewCacheController(cacheService services.CacheService, logger infra.Logger) *CacheController {
+	return &CacheController{
+		cacheService: cacheService,
+		logger:       logger,
+	}
+}
+
+// Flush handles POST /api/v1/admin/cache/flush
+func (cc *CacheController) Flush(c *fiber.Ctx) error {
+	var req types.FlushCacheRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	deleted, err := cc.cacheService.Flush(c.UserContext(), req.Scope)
+	if err != nil {
+		cc.logger.Error("Failed to flush cache", err, map[string]interface{}{
+			"scope":   req.Scope,
+			"deleted": deleted,
+		})
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "CACHE_FLUSH_FAILED",
+			Error:     "Failed to flush cache",
+		})
+	}
+
+	total := 0
+	for _, count := range deleted {
+		total += count
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.FlushCacheResponse{
+		Scope:   req.Scope,
+		Deleted: deleted,
+		Total:   total,
+	})
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"errors"
+	"net/url"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CategoryController handles HTTP requests for managing the category taxonomy
+type CategoryController struct {
+	categoryService services.CategoryService
+	strictJSON      bool
+	logger          infra.Logger
+}
+
+// NewCategoryController creates a new instance of CategoryController
+func NewCategoryController(categoryService services.CategoryService, strictJSON bool, logger infra.Logger) *CategoryController {
+	return &CategoryController{
+		categoryService: categoryService,
+		strictJSON:      strictJSON,
+		logger:          logger,
+	}
+}
+
+// ListCategories handles GET /api/v1/admin/categories
+func (cc *CategoryController) ListCategories(c *fiber.Ctx) error {
+	categories, err := cc.categoryService.List(c.UserContext())
+	if err != nil {
+		cc.logger.Error("Failed to list categories", err, nil)
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "CATEGORY_LIST_FAILED",
+			Error:     "Failed to list categories",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.CategoriesResponse{
+		Categories: categories,
+	})
+}
+
+// CreateCategory handles POST /api/v1/admin/categories
+func (cc *CategoryController) CreateCategory(c *fiber.Ctx) error {
+	var req types.CreateCategoryRequest
+	if err := parseBody(c, &req, cc.strictJSON); err != nil {
+		return invalidBody(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	created, err := cc.categoryService.Create(c.UserContext(), req.Name, req.Aliases)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCategoryExists) {
+			return categoryExists(c)
+		}
+
+		cc.logger.Error("Failed to create category", err, map[string]interface{}{
+			"name": req.Name,
+		})
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "CATEGORY_CREATE_FAILED",
+			Error:     "Failed to create category",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// RenameCategory handles POST /api/v1/admin/categories/:name/rename
+func (cc *CategoryController) RenameCategory(c *fiber.Ctx) error {
+	var req types.RenameCategoryRequest
+	if err := parseBody(c, &req, cc.strictJSON); err != nil {
+		return invalidBody(c, err)
+	}
+
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return invalidCategoryName(c)
+	}
+	req.Name = name
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	renamed, rewritten, err := cc.categoryService.Rename(c.UserContext(), req.Name, req.NewName)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrCategoryNotFound):
+			return categoryNotFound(c)
+		case errors.Is(err, repositories.ErrCategoryExists):
+			return categoryExists(c)
+		}
+
+		cc.logger.Error("Failed to rename category", err, map[string]interface{}{
+			"name":     req.Name,
+			"new_name": req.NewName,
+		})
+		return categoryChangeFailed(c, renamed, "CATEGORY_RENAME_FAILED", "Failed to rename category")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.CategoryChangeResponse{
+		Category:        *renamed,
+		ArticlesUpdated: rewritten,
+	})
+}
+
+// MergeCategories handles POST /api/v1/admin/categories/:name/merge
+func (cc *CategoryController) MergeCategories(c *fiber.Ctx) error {
+	var req types.MergeCategoriesRequest
+	if err := parseBody(c, &req, cc.strictJSON); err != nil {
+		return invalidBody(c, err)
+	}
+
+	target, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return invalidCategoryName(c)
+	}
+	req.Target = target
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	merged, rewritten, err := cc.categoryService.Merge(c.UserContext(), req.Sources, req.Target)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCategoryNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+				ErrorCode: "CATEGORY_NOT_FOUND",
+				Error:     err.Error(),
+			})
+		}
+
+		cc.logger.Error("Failed to merge categories", err, map[string]interface{}{
+			"sources": req.Sources,
+			"target":  req.Target,
+		})
+		return categoryChangeFailed(c, merged, "CATEGORY_MERGE_FAILED", "Failed to merge categories")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.CategoryChangeResponse{
+		Category:        *merged,
+		ArticlesUpdated: rewritten,
+	})
+}
+
+// categoryChangeFailed responds with 500 for a failed rename or merge. A category is only
+// returned alongside the error when the taxonomy was updated but rewriting the stored
+// articles failed; repeating the request finishes the rewrite.
+func categoryChangeFailed(c *fiber.Ctx, category *models.Category, code, message string) error {
+	if category != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "CATEGORY_REWRITE_FAILED",
+			Error:     "Category updated but rewriting stored articles failed; repeat the request to finish",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+		ErrorCode: code,
+		Error:     message,
+	})
+}
+
+// invalidCategoryName responds with 400 for a :name path parameter that is not valid percent-encoding
+func invalidCategoryName(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+		ErrorCode: "INVALID_CATEGORY_NAME",
+		Error:     "Category name must be URL-encoded",
+	})
+}
+
+// categoryNotFound responds with 404 for an unknown category
+func categoryNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+		ErrorCode: "CATEGORY_NOT_FOUND",
+		Error:     "Category not found",
+	})
+}
+
+// categoryExists responds with 409 for a name or alias already used by another category
+func categoryExists(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(types.ErrorResponse{
+		ErrorCode: "CATEGORY_EXISTS",
+		Error:     "Name or alias already belongs to another category",
+	})
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClusterController handles HTTP requests for story clusters
+type ClusterController struct {
+	clusteringService services.ClusteringService
+	logger            infra.Logger
+}
+
+// NewClusterController creates a new instance of ClusterController
+func NewClusterController(clusteringService services.ClusteringService, logger infra.Logger) *ClusterController {
+	return &ClusterController{
+		clusteringService: clusteringService,
+		logger:            logger,
+	}
+}
+
+// ListClusters handles GET /api/v1/news/clusters
+func (cc *ClusterController) ListClusters(c *fiber.Ctx) error {
+	var req types.ListClustersRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	clusters, err := cc.clusteringService.List(c.UserContext(), req.SinceTime, req.MinMembers, req.Limit)
+	if err != nil {
+		cc.logger.Error("Failed to list story clusters", err, map[string]interface{}{
+			"since": req.Since,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "CLUSTER_LIST_FAILED", "Failed to list story clusters", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.ListClustersResponse{
+		Clusters: clusters,
+		Total:    len(clusters),
+	})
+}
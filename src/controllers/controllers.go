@@ -1,31 +1,110 @@
 package controllers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
 	"news-inshorts/src/infra"
 	"news-inshorts/src/services"
+	"news-inshorts/src/types"
 
-	"github.com/redis/go-redis/v9"
-	"gorm.io/gorm"
+	"github.com/gofiber/fiber/v2"
 )
 
 // Controllers holds all controller instances
 type Controllers struct {
 	Article         *ArticleController
 	UserInteraction *UserInteractionController
+	UserPreference  *UserPreferenceController
+	Job             *JobController
+	Retention       *RetentionController
+	Archive         *ArchiveController
+	Export          *ExportController
+	Webhook         *WebhookController
+	SourceAlias     *SourceAliasController
+	Category        *CategoryController
+	SavedSearch     *SavedSearchController
+	LLM             *LLMController
+	VectorIndex     *VectorIndexController
+	Cache           *CacheController
+	Cluster         *ClusterController
+	QueryLog        *QueryLogController
+	Infra           *InfraController
 	Services        *services.Services
 }
 
-// NewControllers creates and returns all controller instances
+// NewControllers creates and returns all controller instances, all logging to the
+// infrastructure's logger
 func NewControllers(
 	cfg *infra.Config,
-	db *gorm.DB,
-	redisClient *redis.Client,
+	infraInstance *infra.Infrastructure,
 ) *Controllers {
-	svcs := services.NewServices(cfg, db, redisClient)
+	logger := infraInstance.Logger
+	svcs := services.NewServices(cfg, infraInstance.DB, infraInstance.Redis, infraInstance.Clock, logger)
 
 	return &Controllers{
-		Article:         NewArticleController(svcs.Article, svcs.Repos.Article),
-		UserInteraction: NewUserInteractionController(svcs.Repos.UserEvent),
+		Article:         NewArticleController(svcs.Article, svcs.Stats, svcs.Feed, svcs.Locator, svcs.QueryLog, svcs.Repos.Article, cfg.Server.StrictJSON, logger),
+		UserInteraction: NewUserInteractionController(svcs.Repos.UserEvent, svcs.Repos.Article, svcs.Idempotency, svcs.Privacy, svcs.Experiments, cfg.Server.StrictJSON, logger),
+		UserPreference:  NewUserPreferenceController(svcs.Repos.UserPreference, logger),
+		Job:             NewJobController(svcs.Jobs, logger),
+		Retention:       NewRetentionController(svcs.Retention, logger),
+		Archive:         NewArchiveController(svcs.Archive, logger),
+		Export:          NewExportController(svcs.Export, &cfg.Export, cfg.Server.StrictJSON, logger),
+		Webhook:         NewWebhookController(svcs.Webhook, logger),
+		SourceAlias:     NewSourceAliasController(svcs.SourceAlias, logger),
+		Category:        NewCategoryController(svcs.Category, cfg.Server.StrictJSON, logger),
+		SavedSearch:     NewSavedSearchController(svcs.SavedSearch, logger),
+		LLM:             NewLLMController(svcs.LLM, svcs.LLMAudit, logger),
+		VectorIndex:     NewVectorIndexController(svcs.VectorIndex, logger),
+		Cache:           NewCacheController(svcs.Cache, logger),
+		Cluster:         NewClusterController(svcs.Clustering, logger),
+		QueryLog:        NewQueryLogController(svcs.QueryLog, logger),
+		Infra:           NewInfraController(infraInstance),
 		Services:        svcs,
 	}
 }
+
+// validationFailed responds with 422 and the per-field errors of a failed request validation
+func validationFailed(c *fiber.Ctx, err error) error {
+	var validationErrors types.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		validationErrors = types.ValidationErrors{{Message: err.Error(), Code: types.ValidationCodeInvalidValue}}
+	}
+
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(types.ValidationErrorResponse{
+		ErrorCode:        "VALIDATION_ERROR",
+		Error:            validationErrors.Error(),
+		ValidationErrors: validationErrors,
+	})
+}
+
+// parseBody parses the request body into out like c.BodyParser. With strict set, JSON bodies
+// carrying fields out does not declare are rejected instead of the fields being ignored.
+func parseBody(c *fiber.Ctx, out interface{}, strict bool) error {
+	if !strict || !c.Is("json") {
+		return c.BodyParser(out)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(c.Body()))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(out)
+}
+
+// invalidBody responds with 400 for a body parseBody rejected, naming the field when it was
+// an unknown one
+func invalidBody(c *fiber.Ctx, err error) error {
+	// encoding/json reports unknown fields only through the message
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "UNKNOWN_FIELD",
+			Error:     "Unknown field " + field + " in request body",
+		})
+	}
+
+	return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+		ErrorCode: "INVALID_REQUEST_BODY",
+		Error:     "Invalid request body",
+	})
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// failingArticleService fails every summarize and delete with err
+type failingArticleService struct {
+	services.ArticleService
+	err error
+}
+
+func (s *failingArticleService) SummarizeArticle(ctx context.Context, id string) (*services.SummarizeResult, error) {
+	return nil, s.err
+}
+
+func (s *failingArticleService) DeleteArticle(ctx context.Context, id string) error {
+	return s.err
+}
+
+// TestArticleHandlerErrorClasses checks the status and error code each class of service
+// failure is answered with, whichever endpoint it surfaces from
+func TestArticleHandlerErrorClasses(t *testing.T) {
+	const id = "7f1c9a52-3c44-4d1e-9a43-0d5f8e2b6a10"
+
+	endpoints := []struct {
+		name        string
+		method      string
+		path        string
+		failureCode string
+	}{
+		{"summarize", fiber.MethodPost, "/api/v1/news/" + id + "/summarize", "ARTICLE_SUMMARIZE_FAILED"},
+		{"delete", fiber.MethodDelete, "/api/v1/news/" + id, "ARTICLE_DELETE_FAILED"},
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string // empty for the endpoint's own failure code
+	}{
+		{"LLM unavailable", fmt.Errorf("generate summary: %w", services.ErrLLMUnavailable), fiber.StatusServiceUnavailable, "LLM_UNAVAILABLE"},
+		{"database unavailable", fmt.Errorf("%w: connection refused", repositories.ErrDatabaseUnavailable), fiber.StatusServiceUnavailable, "DATABASE_UNAVAILABLE"},
+		{"deadline exceeded", fmt.Errorf("query articles: %w", context.DeadlineExceeded), fiber.StatusGatewayTimeout, "REQUEST_TIMEOUT"},
+		{"article not found", repositories.ErrArticleNotFound, fiber.StatusNotFound, "ARTICLE_NOT_FOUND"},
+		{"duplicate article", repositories.ErrDuplicateArticle, fiber.StatusConflict, "DUPLICATE_ARTICLE"},
+		{"unknown failure", errors.New("disk full"), fiber.StatusInternalServerError, ""},
+	}
+
+	for _, endpoint := range endpoints {
+		for _, tt := range tests {
+			t.Run(endpoint.name+"/"+tt.name, func(t *testing.T) {
+				logger := infra.NewRecordingLogger()
+				ctrl := NewArticleController(&failingArticleService{err: tt.err}, nil, nil, nil, nil, nil, false, logger)
+				app := fiber.New(fiber.Config{ErrorHandler: middleware.NewErrorHandler(logger)})
+				app.Post("/api/v1/news/:id/summarize", ctrl.SummarizeArticle)
+				app.Delete("/api/v1/news/:id", ctrl.DeleteArticle)
+
+				resp, err := app.Test(httptest.NewRequest(endpoint.method, endpoint.path, nil), -1)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				defer resp.Body.Close()
+
+				var body types.ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+
+				wantCode := tt.wantCode
+				if wantCode == "" {
+					wantCode = endpoint.failureCode
+				}
+				if resp.StatusCode != tt.wantStatus || body.ErrorCode != wantCode {
+					t.Errorf("got %d %s, want %d %s", resp.StatusCode, body.ErrorCode, tt.wantStatus, wantCode)
+				}
+			})
+		}
+	}
+}
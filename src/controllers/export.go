@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Bounds for the limit query parameter of GET /api/v1/admin/exports
+const (
+	defaultExportRunsLimit = 20
+	maxExportRunsLimit     = 100
+)
+
+// ExportController handles HTTP requests for corpus snapshots
+type ExportController struct {
+	exportService services.ExportService
+	cfg           *infra.ExportConfig
+	strictJSON    bool
+	logger        infra.Logger
+}
+
+// NewExportController creates a new instance of ExportController
+func NewExportController(exportService services.ExportService, cfg *infra.ExportConfig, strictJSON bool, logger infra.Logger) *ExportController {
+	return &ExportController{
+		exportService: exportService,
+		cfg:           cfg,
+		strictJSON:    strictJSON,
+		logger:        logger,
+	}
+}
+
+// ListExports handles GET /api/v1/admin/exports
+func (ec *ExportController) ListExports(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", defaultExportRunsLimit)
+	if limit < 1 || limit > maxExportRunsLimit {
+		var errs types.ValidationErrors
+		errs.Add("limit", types.ValidationCodeOutOfRange, "limit must be between 1 and 100")
+		return validationFailed(c, errs)
+	}
+
+	runs, err := ec.exportService.ListRuns(c.UserContext(), limit)
+	if err != nil {
+		ec.logger.Error("Failed to list export runs", err, nil)
+		return middleware.NewAppError(fiber.StatusInternalServerError, "EXPORT_LIST_FAILED", "Failed to list export runs", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.ExportRunsResponse{
+		Runs: runs,
+	})
+}
+
+// StartExport handles POST /api/v1/admin/exports by starting a snapshot as a background job
+func (ec *ExportController) StartExport(c *fiber.Ctx) error {
+	var req types.StartExportRequest
+
+	// The body is optional; without one the scheduled snapshot settings are used
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &req, ec.strictJSON); err != nil {
+			return invalidBody(c, err)
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = ec.cfg.SnapshotMode
+	}
+	includeVectors := ec.cfg.SnapshotIncludeVectors
+	if req.IncludeVectors != nil {
+		includeVectors = *req.IncludeVectors
+	}
+
+	job, err := ec.exportService.StartRun(mode, includeVectors)
+	if err != nil {
+		if errors.Is(err, services.ErrExportNotConfigured) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(types.ErrorResponse{
+				ErrorCode: "EXPORT_NOT_CONFIGURED",
+				Error:     "Export storage is not configured",
+			})
+		}
+		if errors.Is(err, services.ErrExportRunning) {
+			return c.Status(fiber.StatusConflict).JSON(types.ErrorResponse{
+				ErrorCode: "EXPORT_ALREADY_RUNNING",
+				Error:     "An export is already in progress",
+			})
+		}
+
+		ec.logger.Error("Failed to start export", err, nil)
+		return middleware.NewAppError(fiber.StatusInternalServerError, "EXPORT_START_FAILED", "Failed to start export", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(types.JobResponse{
+		Job: job,
+	})
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"news-inshorts/src/infra"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InfraController handles HTTP requests reporting on infrastructure resources
+type InfraController struct {
+	infraInstance *infra.Infrastructure
+	logger        infra.Logger
+}
+
+// NewInfraController creates a new instance of InfraController
+func NewInfraController(infraInstance *infra.Infrastructure) *InfraController {
+	return &InfraController{
+		infraInstance: infraInstance,
+		logger:        infraInstance.Logger,
+	}
+}
+
+// GetStats handles GET /api/v1/admin/stats
+func (ic *InfraController) GetStats(c *fiber.Ctx) error {
+	stats, err := ic.infraInstance.PoolStats()
+	if err != nil {
+		ic.logger.Error("Failed to get connection pool stats", err, nil)
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "POOL_STATS_FAILED",
+			Error:     "Failed to get connection pool stats",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"news-inshorts/src/infra"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JobController handles background job status requests
+type JobController struct {
+	jobs   *services.JobTracker
+	logger infra.Logger
+}
+
+// NewJobController creates a new instance of JobController
+func NewJobController(jobs *services.JobTracker, logger infra.Logger) *JobController {
+	return &JobController{
+		jobs:   jobs,
+		logger: logger,
+	}
+}
+
+// GetJob handles GET /api/v1/jobs/:id
+func (jc *JobController) GetJob(c *fiber.Ctx) error {
+	job, ok := jc.jobs.Get(c.Params("id"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+			ErrorCode: "JOB_NOT_FOUND",
+			Error:     "Job not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.JobResponse{
+		Job: job,
+	})
+}
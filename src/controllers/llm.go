@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LLMController handles HTTP requests for LLM administration
+type LLMController struct {
+	llmService   services.LLMService
+	auditService services.LLMAuditService
+	logger       infra.Logger
+}
+
+// NewLLMController creates a new instance of LLMController
+func NewLLMController(llmService services.LLMService, auditService services.LLMAuditService, logger infra.Logger) *LLMController {
+	return &LLMController{
+		llmService:   llmService,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// GetUsage handles GET /api/v1/admin/llm/usage
+func (lc *LLMController) GetUsage(c *fiber.Ctx) error {
+	usage, err := lc.llmService.Usage(c.UserContext())
+	if err != nil {
+		lc.logger.Error("Failed to get LLM usage", err, nil)
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "LLM_USAGE_FAILED",
+			Error:     "Failed to get LLM usage",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(usage)
+}
+
+// ListAudit handles GET /api/v1/admin/llm/audit
+func (lc *LLMController) ListAudit(c *fiber.Ctx) error {
+	var req types.ListLLMAuditRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	records, nextCursor, err := lc.auditService.List(c.UserContext(), req.Cursor, req.Limit)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+				ErrorCode: "INVALID_CURSOR",
+				Error:     "Invalid cursor",
+			})
+		}
+
+		lc.logger.Error("Failed to list LLM audit records", err, nil)
+		return middleware.NewAppError(fiber.StatusInternalServerError, "LLM_AUDIT_LIST_FAILED", "Failed to list LLM audit records", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.LLMAuditResponse{
+		Records:    records,
+		NextCursor: nextCursor,
+	})
+}
+
+// PurgeAudit handles DELETE /api/v1/admin/llm/audit
+func (lc *LLMController) PurgeAudit(c *fiber.Ctx) error {
+	var req types.PurgeLLMAuditRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	deleted, err := lc.auditService.Purge(c.UserContext(), req.BeforeTime)
+	if err != nil {
+		lc.logger.Error("Failed to purge LLM audit records", err, nil)
+		return middleware.NewAppError(fiber.StatusInternalServerError, "LLM_AUDIT_PURGE_FAILED", "Failed to purge LLM audit records", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.PurgeLLMAuditResponse{
+		Deleted: deleted,
+	})
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// QueryLogController handles HTTP requests for query log reports
+type QueryLogController struct {
+	queryLogService services.QueryLogService
+	logger          infra.Logger
+}
+
+// NewQueryLogController creates a new instance of QueryLogController
+func NewQueryLogController(queryLogService services.QueryLogService, logger infra.Logger) *QueryLogController {
+	return &QueryLogController{
+		queryLogService: queryLogService,
+		logger:          logger,
+	}
+}
+
+// TopQueries handles GET /api/v1/admin/queries/top
+func (qc *QueryLogController) TopQueries(c *fiber.Ctx) error {
+	var req types.TopQueriesRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	top, err := qc.queryLogService.Top(c.UserContext(), req.WindowDuration, req.Limit)
+	if err != nil {
+		qc.logger.Error("Failed to report top queries", err, map[string]interface{}{
+			"window": req.Window,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "TOP_QUERIES_FAILED", "Failed to report top queries", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.TopQueriesResponse{
+		Window:      req.Window,
+		Since:       top.Since,
+		Top:         top.Top,
+		ZeroResults: top.ZeroResults,
+	})
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RetentionController handles HTTP requests for the user event retention task
+type RetentionController struct {
+	retentionService services.RetentionService
+	logger           infra.Logger
+}
+
+// NewRetentionController creates a new instance of RetentionController
+func NewRetentionController(retentionService services.RetentionService, logger infra.Logger) *RetentionController {
+	return &RetentionController{
+		retentionService: retentionService,
+		logger:           logger,
+	}
+}
+
+// RunRetention handles POST /api/v1/admin/retention/run
+func (rc *RetentionController) RunRetention(c *fiber.Ctx) error {
+	job, err := rc.retentionService.StartRun()
+	if err != nil {
+		if errors.Is(err, services.ErrRetentionRunning) {
+			return c.Status(fiber.StatusConflict).JSON(types.ErrorResponse{
+				ErrorCode: "RETENTION_ALREADY_RUNNING",
+				Error:     "A retention run is already in progress",
+			})
+		}
+
+		rc.logger.Error("Failed to start retention run", err, nil)
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "RETENTION_RUN_FAILED",
+			Error:     "Failed to start retention run",
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(types.JobResponse{
+		Job: job,
+	})
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+	"news-inshorts/src/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SavedSearchController handles HTTP requests for users' saved searches and their matches
+type SavedSearchController struct {
+	savedSearchService services.SavedSearchService
+	logger             infra.Logger
+}
+
+// NewSavedSearchController creates a new instance of SavedSearchController
+func NewSavedSearchController(savedSearchService services.SavedSearchService, logger infra.Logger) *SavedSearchController {
+	return &SavedSearchController{
+		savedSearchService: savedSearchService,
+		logger:             logger,
+	}
+}
+
+// CreateSearch handles POST /api/v1/users/:user_id/searches
+func (sc *SavedSearchController) CreateSearch(c *fiber.Ctx) error {
+	var req types.CreateSavedSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_REQUEST_BODY",
+			Error:     "Invalid request body",
+		})
+	}
+	req.UserID = c.Params("user_id")
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	search := req.Model()
+	if err := sc.savedSearchService.Create(c.UserContext(), search); err != nil {
+		if errors.Is(err, services.ErrSavedSearchLimitReached) {
+			return c.Status(fiber.StatusConflict).JSON(types.ErrorResponse{
+				ErrorCode: "SAVED_SEARCH_LIMIT_REACHED",
+				Error:     "Maximum number of saved searches reached",
+			})
+		}
+
+		sc.logger.Error("Failed to create saved search", err, map[string]interface{}{
+			"user_hash": utils.HashIdentifier(req.UserID),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "SAVED_SEARCH_CREATE_FAILED", "Failed to create saved search", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(search)
+}
+
+// ListSearches handles GET /api/v1/users/:user_id/searches
+func (sc *SavedSearchController) ListSearches(c *fiber.Ctx) error {
+	userID := c.Params("user_id")
+
+	searches, err := sc.savedSearchService.List(c.UserContext(), userID)
+	if err != nil {
+		sc.logger.Error("Failed to list saved searches", err, map[string]interface{}{
+			"user_hash": utils.HashIdentifier(userID),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "SAVED_SEARCH_LIST_FAILED", "Failed to list saved searches", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.SavedSearchesResponse{
+		Searches: searches,
+		Total:    len(searches),
+	})
+}
+
+// GetSearch handles GET /api/v1/users/:user_id/searches/:id
+func (sc *SavedSearchController) GetSearch(c *fiber.Ctx) error {
+	userID, id := c.Params("user_id"), c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return invalidSavedSearchID(c)
+	}
+
+	search, err := sc.savedSearchService.Get(c.UserContext(), userID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSavedSearchNotFound) {
+			return savedSearchNotFound(c)
+		}
+
+		sc.logger.Error("Failed to get saved search", err, map[string]interface{}{
+			"id": id,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "SAVED_SEARCH_GET_FAILED", "Failed to get saved search", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(search)
+}
+
+// DeleteSearch handles DELETE /api/v1/users/:user_id/searches/:id
+func (sc *SavedSearchController) DeleteSearch(c *fiber.Ctx) error {
+	userID, id := c.Params("user_id"), c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return invalidSavedSearchID(c)
+	}
+
+	if err := sc.savedSearchService.Delete(c.UserContext(), userID, id); err != nil {
+		if errors.Is(err, repositories.ErrSavedSearchNotFound) {
+			return savedSearchNotFound(c)
+		}
+
+		sc.logger.Error("Failed to delete saved search", err, map[string]interface{}{
+			"id": id,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "SAVED_SEARCH_DELETE_FAILED", "Failed to delete saved search", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetMatches handles GET /api/v1/users/:user_id/searches/:id/matches
+func (sc *SavedSearchController) GetMatches(c *fiber.Ctx) error {
+	userID, id := c.Params("user_id"), c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return invalidSavedSearchID(c)
+	}
+
+	var req types.SavedSearchMatchesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	matches, err := sc.savedSearchService.Matches(c.UserContext(), userID, id, req.Limit)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSavedSearchNotFound) {
+			return savedSearchNotFound(c)
+		}
+
+		sc.logger.Error("Failed to get saved search matches", err, map[string]interface{}{
+			"id": id,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "SAVED_SEARCH_MATCHES_FAILED", "Failed to get saved search matches", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.SavedSearchMatchesResponse{
+		Matches: matches,
+		Total:   len(matches),
+	})
+}
+
+// invalidSavedSearchID responds with 400 for an :id path parameter that is not a UUID
+func invalidSavedSearchID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+		ErrorCode: "INVALID_SAVED_SEARCH_ID",
+		Error:     "Saved search id must be a valid UUID",
+	})
+}
+
+// savedSearchNotFound responds with 404 for a search the user does not have
+func savedSearchNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+		ErrorCode: "SAVED_SEARCH_NOT_FOUND",
+		Error:     "Saved search not found",
+	})
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"errors"
+	"net/url"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SourceAliasController handles HTTP requests for managing source aliases
+type SourceAliasController struct {
+	sourceAliasService services.SourceAliasService
+	logger             infra.Logger
+}
+
+// NewSourceAliasController creates a new instance of SourceAliasController
+func NewSourceAliasController(sourceAliasService services.SourceAliasService, logger infra.Logger) *SourceAliasController {
+	return &SourceAliasController{
+		sourceAliasService: sourceAliasService,
+		logger:             logger,
+	}
+}
+
+// ListAliases handles GET /api/v1/admin/source-aliases
+func (sc *SourceAliasController) ListAliases(c *fiber.Ctx) error {
+	aliases, err := sc.sourceAliasService.List(c.UserContext())
+	if err != nil {
+		sc.logger.Error("Failed to list source aliases", err, nil)
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "SOURCE_ALIAS_LIST_FAILED",
+			Error:     "Failed to list source aliases",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.SourceAliasesResponse{
+		Aliases: aliases,
+	})
+}
+
+// GetAlias handles GET /api/v1/admin/source-aliases/:alias
+func (sc *SourceAliasController) GetAlias(c *fiber.Ctx) error {
+	alias, err := url.PathUnescape(c.Params("alias"))
+	if err != nil {
+		return invalidSourceAlias(c)
+	}
+
+	found, err := sc.sourceAliasService.Get(c.UserContext(), alias)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSourceAliasNotFound) {
+			return sourceAliasNotFound(c)
+		}
+
+		sc.logger.Error("Failed to get source alias", err, map[string]interface{}{
+			"alias": alias,
+		})
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "SOURCE_ALIAS_GET_FAILED",
+			Error:     "Failed to get source alias",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(found)
+}
+
+// PutAlias handles PUT /api/v1/admin/source-aliases/:alias
+func (sc *SourceAliasController) PutAlias(c *fiber.Ctx) error {
+	var req types.UpsertSourceAliasRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_REQUEST_BODY",
+			Error:     "Invalid request body",
+		})
+	}
+
+	alias, err := url.PathUnescape(c.Params("alias"))
+	if err != nil {
+		return invalidSourceAlias(c)
+	}
+	req.Alias = alias
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	saved, err := sc.sourceAliasService.Upsert(c.UserContext(), req.Alias, req.SourceNames)
+	if err != nil {
+		sc.logger.Error("Failed to save source alias", err, map[string]interface{}{
+			"alias": req.Alias,
+		})
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "SOURCE_ALIAS_SAVE_FAILED",
+			Error:     "Failed to save source alias",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(saved)
+}
+
+// DeleteAlias handles DELETE /api/v1/admin/source-aliases/:alias
+func (sc *SourceAliasController) DeleteAlias(c *fiber.Ctx) error {
+	alias, err := url.PathUnescape(c.Params("alias"))
+	if err != nil {
+		return invalidSourceAlias(c)
+	}
+
+	if err := sc.sourceAliasService.Delete(c.UserContext(), alias); err != nil {
+		if errors.Is(err, repositories.ErrSourceAliasNotFound) {
+			return sourceAliasNotFound(c)
+		}
+
+		sc.logger.Error("Failed to delete source alias", err, map[string]interface{}{
+			"alias": alias,
+		})
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "SOURCE_ALIAS_DELETE_FAILED",
+			Error:     "Failed to delete source alias",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// invalidSourceAlias responds with 400 for an :alias path parameter that is not valid percent-encoding
+func invalidSourceAlias(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+		ErrorCode: "INVALID_SOURCE_ALIAS",
+		Error:     "Source alias must be URL-encoded",
+	})
+}
+
+// sourceAliasNotFound responds with 404 for an unknown alias
+func sourceAliasNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+		ErrorCode: "SOURCE_ALIAS_NOT_FOUND",
+		Error:     "Source alias not found",
+	})
+}
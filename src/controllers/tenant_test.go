@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// tenantArticleStore keeps articles per tenant like the article repository does, so services
+// backed by it only see the articles of the tenant on their context
+type tenantArticleStore struct {
+	mu       sync.Mutex
+	articles map[string]map[string]*storedArticle
+}
+
+type storedArticle struct {
+	summary string
+	deleted bool
+}
+
+func newTenantArticleStore() *tenantArticleStore {
+	return &tenantArticleStore{articles: make(map[string]map[string]*storedArticle)}
+}
+
+// find returns the article of the tenant on ctx, or ErrArticleNotFound when the tenant has no
+// article with the id in the wanted deleted state
+func (s *tenantArticleStore) find(ctx context.Context, id string, deleted bool) (*storedArticle, error) {
+	article, ok := s.articles[infra.TenantFromContext(ctx, infra.DefaultTenant)][id]
+	if !ok || article.deleted != deleted {
+		return nil, repositories.ErrArticleNotFound
+	}
+	return article, nil
+}
+
+// tenantArticleService is the article service of the controller under test
+type tenantArticleService struct {
+	services.ArticleService
+	store *tenantArticleStore
+}
+
+func (s *tenantArticleService) CreateArticle(ctx context.Context, article *models.Article) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	tenant := infra.TenantFromContext(ctx, infra.DefaultTenant)
+	if s.store.articles[tenant] == nil {
+		s.store.articles[tenant] = make(map[string]*storedArticle)
+	}
+	article.ID = uuid.New().String()
+	s.store.articles[tenant][article.ID] = &storedArticle{summary: article.Summary}
+	return nil
+}
+
+func (s *tenantArticleService) SummarizeArticle(ctx context.Context, id string) (*services.SummarizeResult, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	article, err := s.store.find(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	result := &services.SummarizeResult{ID: id, OldSummary: article.summary, NewSummary: "regenerated"}
+	article.summary = result.NewSummary
+	return result, nil
+}
+
+func (s *tenantArticleService) DeleteArticle(ctx context.Context, id string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	article, err := s.store.find(ctx, id, false)
+	if err != nil {
+		return err
+	}
+	article.deleted = true
+	return nil
+}
+
+func (s *tenantArticleService) RestoreArticle(ctx context.Context, id string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	article, err := s.store.find(ctx, id, true)
+	if err != nil {
+		return err
+	}
+	article.deleted = false
+	return nil
+}
+
+func (s *tenantArticleService) PurgeArticle(ctx context.Context, id string) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	tenant := infra.TenantFromContext(ctx, infra.DefaultTenant)
+	if _, ok := s.store.articles[tenant][id]; !ok {
+		return repositories.ErrArticleNotFound
+	}
+	delete(s.store.articles[tenant], id)
+	return nil
+}
+
+// tenantStatsService is the stats service of the controller under test
+type tenantStatsService struct {
+	services.StatsService
+	store *tenantArticleStore
+}
+
+func (s *tenantStatsService) GetArticleStats(ctx context.Context, id string) (*models.ArticleStats, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if _, err := s.store.find(ctx, id, false); err != nil {
+		return nil, err
+	}
+	return &models.ArticleStats{ArticleID: id}, nil
+}
+
+// newTenantTestApp serves the article routes of the controller behind tenant resolution, with
+// tenant-a and tenant-b selectable by X-Tenant-ID
+func newTenantTestApp() *fiber.App {
+	logger := infra.NewRecordingLogger()
+	store := newTenantArticleStore()
+	ctrl := NewArticleController(&tenantArticleService{store: store}, &tenantStatsService{store: store}, nil, nil, nil, nil, false, logger)
+
+	resolver := infra.NewTenantResolver(&infra.TenantConfig{
+		Default: infra.DefaultTenant,
+		Open:    map[string]bool{"tenant-a": true, "tenant-b": true},
+	}, "")
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.NewErrorHandler(logger)})
+	api := app.Group("/api/", middleware.ResolveTenant(resolver))
+	api.Post("/v1/news", ctrl.CreateArticle)
+	api.Get("/v1/news/:id/stats", ctrl.GetArticleStats)
+	api.Post("/v1/news/:id/summarize", ctrl.SummarizeArticle)
+	api.Delete("/v1/news/:id", ctrl.DeleteArticle)
+	api.Post("/v1/admin/news/:id/restore", ctrl.RestoreArticle)
+	api.Delete("/v1/admin/news/:id", ctrl.PurgeArticle)
+	return app
+}
+
+// doTenantRequest sends a request as tenant and returns the response status, decoding the
+// body into out when it is not nil
+func doTenantRequest(t *testing.T, app *fiber.App, method, path, tenant string, body, out interface{}) int {
+	t.Helper()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(infra.TenantHeader, tenant)
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("failed to decode response of %s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// TestArticleTenantIsolation creates an article as tenant A and checks that tenant B can
+// neither read, update, delete, restore nor purge it
+func TestArticleTenantIsolation(t *testing.T) {
+	app := newTenantTestApp()
+
+	var created types.CreateArticleResponse
+	status := doTenantRequest(t, app, fiber.MethodPost, "/api/v1/news", "tenant-a", types.CreateArticleRequest{
+		Title:           "Storm hits the coast",
+		URL:             "https://example.com/storm",
+		PublicationDate: "2026-10-15T08:00:00",
+		SourceName:      "Example",
+		Category:        []string{"world"},
+		RelevanceScore:  0.5,
+		Latitude:        12.97,
+		Longitude:       77.59,
+		Summary:         "original",
+	}, &created)
+	if status != fiber.StatusCreated {
+		t.Fatalf("tenant A create status = %d, want %d", status, fiber.StatusCreated)
+	}
+	id := created.Article.ID
+
+	otherTenant := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{"read", fiber.MethodGet, "/api/v1/news/" + id + "/stats"},
+		{"update", fiber.MethodPost, "/api/v1/news/" + id + "/summarize"},
+		{"delete", fiber.MethodDelete, "/api/v1/news/" + id},
+		{"purge", fiber.MethodDelete, "/api/v1/admin/news/" + id},
+	}
+	for _, tt := range otherTenant {
+		t.Run("tenant B cannot "+tt.name, func(t *testing.T) {
+			var body types.ErrorResponse
+			if status := doTenantRequest(t, app, tt.method, tt.path, "tenant-b", nil, &body); status != fiber.StatusNotFound {
+				t.Fatalf("status = %d, want %d", status, fiber.StatusNotFound)
+			}
+			if body.ErrorCode != "ARTICLE_NOT_FOUND" {
+				t.Errorf("error_code = %q, want ARTICLE_NOT_FOUND", body.ErrorCode)
+			}
+		})
+	}
+
+	t.Run("tenant B cannot restore", func(t *testing.T) {
+		if status := doTenantRequest(t, app, fiber.MethodDelete, "/api/v1/news/"+id, "tenant-a", nil, nil); status != fiber.StatusOK {
+			t.Fatalf("tenant A delete status = %d, want %d", status, fiber.StatusOK)
+		}
+		if status := doTenantRequest(t, app, fiber.MethodPost, "/api/v1/admin/news/"+id+"/restore", "tenant-b", nil, nil); status != fiber.StatusNotFound {
+			t.Errorf("tenant B restore status = %d, want %d", status, fiber.StatusNotFound)
+		}
+		if status := doTenantRequest(t, app, fiber.MethodPost, "/api/v1/admin/news/"+id+"/restore", "tenant-a", nil, nil); status != fiber.StatusOK {
+			t.Fatalf("tenant A restore status = %d, want %d", status, fiber.StatusOK)
+		}
+	})
+
+	t.Run("tenant A keeps its article", func(t *testing.T) {
+		var stats models.ArticleStats
+		if status := doTenantRequest(t, app, fiber.MethodGet, "/api/v1/news/"+id+"/stats", "tenant-a", nil, &stats); status != fiber.StatusOK {
+			t.Fatalf("tenant A stats status = %d, want %d", status, fiber.StatusOK)
+		}
+		var summary types.SummarizeArticleResponse
+		if status := doTenantRequest(t, app, fiber.MethodPost, "/api/v1/news/"+id+"/summarize", "tenant-a", nil, &summary); status != fiber.StatusOK {
+			t.Fatalf("tenant A summarize status = %d, want %d", status, fiber.StatusOK)
+		}
+		if summary.OldSummary != "original" {
+			t.Errorf("summary before tenant A's update = %q, want original", summary.OldSummary)
+		}
+	})
+}
+
+// TestArticleUnknownTenantRejected checks that requests for an unconfigured tenant never reach
+// the controller
+func TestArticleUnknownTenantRejected(t *testing.T) {
+	app := newTenantTestApp()
+
+	var body types.ErrorResponse
+	status := doTenantRequest(t, app, fiber.MethodGet, "/api/v1/news/"+uuid.New().String()+"/stats", "initech", nil, &body)
+	if status != fiber.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusBadRequest)
+	}
+	if body.ErrorCode != "UNKNOWN_TENANT" {
+		t.Errorf("error_code = %q, want UNKNOWN_TENANT", body.ErrorCode)
+	}
+}
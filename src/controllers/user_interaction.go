@@ -1,27 +1,43 @@
 package controllers
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
 	"news-inshorts/src/models"
 	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
 	"news-inshorts/src/types"
+	"news-inshorts/src/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // UserInteractionController handles user interaction-related HTTP requests
 type UserInteractionController struct {
 	userEventRepo repositories.UserEventRepository
+	articleRepo   repositories.ArticleRepository
+	idempotency   services.IdempotencyStore
+	privacy       services.PrivacyService
+	experiments   *infra.ExperimentAssigner
+	strictJSON    bool
 	logger        infra.Logger
 }
 
 // NewUserInteractionController creates a new instance of UserInteractionController
-func NewUserInteractionController(userEventRepo repositories.UserEventRepository) *UserInteractionController {
+func NewUserInteractionController(userEventRepo repositories.UserEventRepository, articleRepo repositories.ArticleRepository, idempotency services.IdempotencyStore, privacy services.PrivacyService, experiments *infra.ExperimentAssigner, strictJSON bool, logger infra.Logger) *UserInteractionController {
 	return &UserInteractionController{
 		userEventRepo: userEventRepo,
-		logger:        infra.GetLogger(),
+		articleRepo:   articleRepo,
+		idempotency:   idempotency,
+		privacy:       privacy,
+		experiments:   experiments,
+		strictJSON:    strictJSON,
+		logger:        logger,
 	}
 }
 
@@ -29,18 +45,35 @@ func NewUserInteractionController(userEventRepo repositories.UserEventRepository
 func (uic *UserInteractionController) RecordInteraction(c *fiber.Ctx) error {
 	var req types.RecordInteractionRequest
 
-	if err := c.BodyParser(&req); err != nil {
+	if err := parseBody(c, &req, uic.strictJSON); err != nil {
 		uic.logger.Error("Failed to parse request body", err, map[string]interface{}{
 			"path": c.Path(),
 		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return invalidBody(c, err)
 	}
 
 	if err := req.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
+		return validationFailed(c, err)
+	}
+
+	if _, err := uuid.Parse(req.ArticleID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_ARTICLE_ID",
+			Error:     "article_id must be a valid UUID",
+		})
+	}
+
+	exists, err := uic.articleRepo.Exists(c.UserContext(), req.ArticleID)
+	if err != nil {
+		uic.logger.Error("Failed to check article existence", err, map[string]interface{}{
+			"article_id": req.ArticleID,
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "INTERACTION_RECORD_FAILED", "Failed to record interaction", err)
+	}
+	if !exists {
+		return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+			ErrorCode: "ARTICLE_NOT_FOUND",
+			Error:     "Article not found",
 		})
 	}
 
@@ -50,26 +83,62 @@ func (uic *UserInteractionController) RecordInteraction(c *fiber.Ctx) error {
 		"event_type": req.EventType,
 	})
 
+	// Replays of the same idempotency key return the original event instead of inserting again.
+	// The key is scoped to the user so clients cannot collide with each other.
+	eventID := uuid.New().String()
+	idempotencyKey := c.Get("Idempotency-Key", req.ClientEventID)
+	if idempotencyKey != "" {
+		existingID, claimed, err := uic.idempotency.Claim(c.UserContext(), "interaction:"+req.UserID, idempotencyKey, eventID)
+		if err != nil {
+			// Fail open: recording the event matters more than deduplicating it
+			uic.logger.Warn("Failed to check idempotency key", map[string]interface{}{
+				"user_id": req.UserID,
+				"error":   err.Error(),
+			})
+			idempotencyKey = ""
+		} else if !claimed {
+			uic.logger.Info("Duplicate user interaction ignored", map[string]interface{}{
+				"event_id": existingID,
+				"user_id":  req.UserID,
+			})
+			return c.Status(fiber.StatusOK).JSON(types.RecordInteractionResponse{
+				Success:      true,
+				EventID:      existingID,
+				Deduplicated: true,
+			})
+		}
+	}
+
 	event := &models.UserEvent{
-		UserID:    req.UserID,
-		ArticleID: req.ArticleID,
-		EventType: req.EventType,
-		Timestamp: time.Now(),
-		Latitude:  req.Location.Latitude,
-		Longitude: req.Location.Longitude,
+		ID:          eventID,
+		UserID:      req.UserID,
+		ArticleID:   req.ArticleID,
+		EventType:   req.EventType,
+		Value:       req.Value,
+		Timestamp:   time.Now(),
+		Latitude:    req.Location.Latitude,
+		Longitude:   req.Location.Longitude,
+		Experiments: uic.experiments.Assign(infra.ExperimentSubject(req.UserID, c.IP())),
 	}
 
-	err := uic.userEventRepo.Create(event)
-	if err != nil {
+	if err := uic.userEventRepo.Create(c.UserContext(), event); err != nil {
 		uic.logger.Error("Failed to record user interaction", err, map[string]interface{}{
 			"user_id":    req.UserID,
 			"article_id": req.ArticleID,
 			"event_type": req.EventType,
 		})
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to record interaction",
-		})
+		// Let the client retry with the same key
+		if idempotencyKey != "" {
+			if err := uic.idempotency.Release(c.UserContext(), "interaction:"+req.UserID, idempotencyKey); err != nil {
+				uic.logger.Warn("Failed to release idempotency key", map[string]interface{}{
+					"user_id": req.UserID,
+					"error":   err.Error(),
+				})
+			}
+		}
+
+		return middleware.NewAppError(fiber.StatusInternalServerError, "INTERACTION_RECORD_FAILED", "Failed to record interaction", err)
 	}
 
 	response := types.RecordInteractionResponse{
@@ -86,3 +155,199 @@ func (uic *UserInteractionController) RecordInteraction(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
+
+// RecordInteractionBatch handles POST /api/v1/interactions/batch
+// Invalid events are reported by index and skipped; the valid ones are stored together.
+func (uic *UserInteractionController) RecordInteractionBatch(c *fiber.Ctx) error {
+	var req types.RecordInteractionBatchRequest
+
+	if err := parseBody(c, &req, uic.strictJSON); err != nil {
+		uic.logger.Error("Failed to parse request body", err, map[string]interface{}{
+			"path": c.Path(),
+		})
+		return invalidBody(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	// Look up all referenced articles at once so unknown ids can be rejected per event
+	articleIDs := make([]string, 0, len(req))
+	for _, item := range req {
+		if _, err := uuid.Parse(item.ArticleID); err == nil {
+			articleIDs = append(articleIDs, item.ArticleID)
+		}
+	}
+
+	articles, err := uic.articleRepo.FindByIDs(c.UserContext(), articleIDs)
+	if err != nil {
+		uic.logger.Error("Failed to look up articles for interaction batch", err, map[string]interface{}{
+			"count": len(articleIDs),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "INTERACTION_RECORD_FAILED", "Failed to record interactions", err)
+	}
+
+	existing := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		existing[article.ID] = true
+	}
+
+	now := time.Now()
+	events := make([]*models.UserEvent, 0, len(req))
+	validationErrors := types.ValidationErrors{}
+
+	for i, item := range req {
+		var itemErrors types.ValidationErrors
+		if err := item.Validate(); err != nil {
+			errors.As(err, &itemErrors)
+		} else if _, err := uuid.Parse(item.ArticleID); err != nil {
+			itemErrors.Add("article_id", types.ValidationCodeInvalidFormat, "article_id must be a valid UUID")
+		} else if !existing[strings.ToLower(item.ArticleID)] {
+			itemErrors.Add("article_id", types.ValidationCodeInvalidValue, "article not found")
+		}
+		if len(itemErrors) > 0 {
+			validationErrors = append(validationErrors, itemErrors.WithIndex(i)...)
+			continue
+		}
+
+		events = append(events, &models.UserEvent{
+			UserID:      item.UserID,
+			ArticleID:   item.ArticleID,
+			EventType:   item.EventType,
+			Value:       item.Value,
+			Timestamp:   now,
+			Latitude:    item.Location.Latitude,
+			Longitude:   item.Location.Longitude,
+			Experiments: uic.experiments.Assign(infra.ExperimentSubject(item.UserID, c.IP())),
+		})
+	}
+
+	if err := uic.userEventRepo.CreateBatch(c.UserContext(), events); err != nil {
+		uic.logger.Error("Failed to record user interaction batch", err, map[string]interface{}{
+			"total": len(req),
+			"valid": len(events),
+		})
+
+		return middleware.NewAppError(fiber.StatusInternalServerError, "INTERACTION_RECORD_FAILED", "Failed to record interactions", err)
+	}
+
+	eventIDs := make([]string, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+
+	response := types.RecordInteractionBatchResponse{
+		Success:          len(events) == len(req),
+		TotalEvents:      len(req),
+		SuccessCount:     len(events),
+		ErrorCount:       len(req) - len(events),
+		ValidationErrors: validationErrors,
+		EventIDs:         eventIDs,
+	}
+
+	uic.logger.Info("User interaction batch recorded", map[string]interface{}{
+		"total":       response.TotalEvents,
+		"success":     response.SuccessCount,
+		"error_count": response.ErrorCount,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// PurgeUserEvents handles DELETE /api/v1/interactions/users/:user_id
+// Removes every event recorded for the user along with their cached data (GDPR erasure).
+func (uic *UserInteractionController) PurgeUserEvents(c *fiber.Ctx) error {
+	userID := c.Params("user_id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "USER_ID_REQUIRED",
+			Error:     "user_id is required",
+		})
+	}
+
+	deleted, err := uic.privacy.PurgeUser(c.UserContext(), userID)
+	if err != nil {
+		uic.logger.Error("Failed to purge user events", err, map[string]interface{}{
+			"user_hash": utils.HashIdentifier(userID),
+		})
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "USER_PURGE_FAILED",
+			Error:     "Failed to purge user data",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.PurgeUserEventsResponse{
+		Deleted: deleted,
+	})
+}
+
+// GetBookmarks handles GET /api/v1/interactions/users/:user_id/bookmarks
+// Returns the articles the user currently has bookmarked, most recently bookmarked first.
+func (uic *UserInteractionController) GetBookmarks(c *fiber.Ctx) error {
+	userID := c.Params("user_id")
+	if userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "USER_ID_REQUIRED",
+			Error:     "user_id is required",
+		})
+	}
+
+	var req types.GetBookmarksRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_QUERY_PARAMS",
+			Error:     "Invalid query parameters",
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	bookmarks, total, err := uic.userEventRepo.Bookmarks(c.UserContext(), userID, req.Limit, req.Offset)
+	if err != nil {
+		uic.logger.Error("Failed to retrieve bookmarks", err, map[string]interface{}{
+			"user_hash": utils.HashIdentifier(userID),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "BOOKMARKS_FAILED", "Failed to retrieve bookmarks", err)
+	}
+
+	ids := make([]string, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		ids[i] = bookmark.ArticleID
+	}
+
+	articles, err := uic.articleRepo.FindByIDs(c.UserContext(), ids)
+	if err != nil {
+		uic.logger.Error("Failed to retrieve bookmarked articles", err, map[string]interface{}{
+			"user_hash": utils.HashIdentifier(userID),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "BOOKMARKS_FAILED", "Failed to retrieve bookmarks", err)
+	}
+
+	byID := make(map[string]models.Article, len(articles))
+	for _, article := range articles {
+		byID[strings.ToLower(article.ID)] = article
+	}
+
+	// Keep the bookmark order; an article deleted since the bookmarks were read is skipped
+	items := make([]models.BookmarkedArticle, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		article, found := byID[strings.ToLower(bookmark.ArticleID)]
+		if !found {
+			continue
+		}
+		items = append(items, models.BookmarkedArticle{
+			Article:      article,
+			BookmarkedAt: bookmark.BookmarkedAt,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.BookmarksResponse{
+		Bookmarks: items,
+		Total:     total,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	})
+}
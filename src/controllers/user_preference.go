@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/types"
+	"news-inshorts/src/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserPreferenceController handles HTTP requests for users' category preferences
+type UserPreferenceController struct {
+	userPrefRepo repositories.UserPreferenceRepository
+	logger       infra.Logger
+}
+
+// NewUserPreferenceController creates a new instance of UserPreferenceController
+func NewUserPreferenceController(userPrefRepo repositories.UserPreferenceRepository, logger infra.Logger) *UserPreferenceController {
+	return &UserPreferenceController{
+		userPrefRepo: userPrefRepo,
+		logger:       logger,
+	}
+}
+
+// GetPreferences handles GET /api/v1/users/:user_id/preferences
+func (pc *UserPreferenceController) GetPreferences(c *fiber.Ctx) error {
+	userID := c.Params("user_id")
+
+	categories, err := pc.userPrefRepo.Get(c.UserContext(), userID)
+	if err != nil {
+		pc.logger.Error("Failed to get user preferences", err, map[string]interface{}{
+			"user_hash": utils.HashIdentifier(userID),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "PREFERENCES_GET_FAILED", "Failed to get preferences", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.UserPreferences{
+		UserID:     userID,
+		Categories: categories,
+	})
+}
+
+// PutPreferences handles PUT /api/v1/users/:user_id/preferences
+// Replaces every category weight of the user; an empty map clears them.
+func (pc *UserPreferenceController) PutPreferences(c *fiber.Ctx) error {
+	var req types.PutPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+			ErrorCode: "INVALID_REQUEST_BODY",
+			Error:     "Invalid request body",
+		})
+	}
+	req.UserID = c.Params("user_id")
+
+	if err := req.Validate(); err != nil {
+		return validationFailed(c, err)
+	}
+
+	if err := pc.userPrefRepo.Replace(c.UserContext(), req.UserID, req.Categories); err != nil {
+		pc.logger.Error("Failed to save user preferences", err, map[string]interface{}{
+			"user_hash": utils.HashIdentifier(req.UserID),
+		})
+		return middleware.NewAppError(fiber.StatusInternalServerError, "PREFERENCES_SAVE_FAILED", "Failed to save preferences", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.UserPreferences{
+		UserID:     req.UserID,
+		Categories: req.Categories,
+	})
+}
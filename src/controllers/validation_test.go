@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TestCreateArticleValidationResponse checks the exact body of the 422 answered for invalid
+// articles, so clients can rely on its shape
+func TestCreateArticleValidationResponse(t *testing.T) {
+	logger := infra.NewRecordingLogger()
+	ctrl := NewArticleController(nil, nil, nil, nil, nil, nil, false, logger)
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.NewErrorHandler(logger)})
+	app.Post("/api/v1/news", ctrl.CreateArticle)
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+		want string
+	}{
+		{
+			name: "title and source_name missing",
+			body: map[string]interface{}{
+				"url":              "https://example.com/storm",
+				"publication_date": "2026-10-15T08:00:00",
+				"category":         []string{"world"},
+			},
+			want: `{"error_code":"VALIDATION_ERROR","error":"title is required; source_name is required","validation_errors":[` +
+				`{"field":"title","message":"title is required","code":"REQUIRED"},` +
+				`{"field":"source_name","message":"source_name is required","code":"REQUIRED"}]}`,
+		},
+		{
+			name: "url missing and relevance_score out of range",
+			body: map[string]interface{}{
+				"title":            "Storm hits the coast",
+				"source_name":      "Example",
+				"publication_date": "2026-10-15T08:00:00",
+				"relevance_score":  1.5,
+			},
+			want: `{"error_code":"VALIDATION_ERROR","error":"url is required; relevance_score must be between 0 and 1","validation_errors":[` +
+				`{"field":"url","message":"url is required","code":"REQUIRED"},` +
+				`{"field":"relevance_score","message":"relevance_score must be between 0 and 1","code":"OUT_OF_RANGE"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("failed to encode request: %v", err)
+			}
+			req := httptest.NewRequest(fiber.MethodPost, "/api/v1/news", bytes.NewReader(payload))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != fiber.StatusUnprocessableEntity {
+				t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusUnprocessableEntity)
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read response: %v", err)
+			}
+			if string(body) != tt.want {
+				t.Errorf("body =\n%s\nwant\n%s", body, tt.want)
+			}
+		})
+	}
+}
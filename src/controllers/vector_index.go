@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VectorIndexController handles HTTP requests for managing the vector search index
+type VectorIndexController struct {
+	vectorIndexService services.VectorIndexService
+	logger             infra.Logger
+}
+
+// NewVectorIndexController creates a new instance of VectorIndexController
+func NewVectorIndexController(vectorIndexService services.VectorIndexService, logger infra.Logger) *VectorIndexController {
+	return &VectorIndexController{
+		vectorIndexService: vectorIndexService,
+		logger:             logger,
+	}
+}
+
+// GetStatus handles GET /api/v1/admin/vector-index
+func (vc *VectorIndexController) GetStatus(c *fiber.Ctx) error {
+	status, err := vc.vectorIndexService.Status(c.UserContext())
+	if err != nil {
+		vc.logger.Error("Failed to get vector index status", err, nil)
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "VECTOR_INDEX_STATUS_FAILED",
+			Error:     "Failed to get vector index status",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(status)
+}
+
+// Reindex handles POST /api/v1/admin/vector-index/reindex
+func (vc *VectorIndexController) Reindex(c *fiber.Ctx) error {
+	job, err := vc.vectorIndexService.StartReindex()
+	if err != nil {
+		if errors.Is(err, services.ErrVectorReindexRunning) {
+			return c.Status(fiber.StatusConflict).JSON(types.ErrorResponse{
+				ErrorCode: "REINDEX_ALREADY_RUNNING",
+				Error:     "A vector reindex is already in progress",
+			})
+		}
+
+		vc.logger.Error("Failed to start vector reindex", err, nil)
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "REINDEX_FAILED",
+			Error:     "Failed to start vector reindex",
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(types.JobResponse{
+		Job: job,
+	})
+}
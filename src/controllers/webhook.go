@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookController handles HTTP requests for webhook administration
+type WebhookController struct {
+	webhookService services.WebhookService
+	logger         infra.Logger
+}
+
+// NewWebhookController creates a new instance of WebhookController
+func NewWebhookController(webhookService services.WebhookService, logger infra.Logger) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// TestDelivery handles POST /api/v1/admin/webhooks/test
+func (wc *WebhookController) TestDelivery(c *fiber.Ctx) error {
+	var req types.TestWebhookRequest
+
+	// The body is optional; without one every target is tested
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(types.ErrorResponse{
+				ErrorCode: "INVALID_REQUEST_BODY",
+				Error:     "Invalid request body",
+			})
+		}
+	}
+
+	results, err := wc.webhookService.TestDelivery(c.UserContext(), req.Target)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookTargetNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrorResponse{
+				ErrorCode: "WEBHOOK_TARGET_NOT_FOUND",
+				Error:     "Webhook target not found",
+			})
+		}
+
+		wc.logger.Error("Failed to send test webhook", err, map[string]interface{}{
+			"target": req.Target,
+		})
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrorResponse{
+			ErrorCode: "WEBHOOK_TEST_FAILED",
+			Error:     "Failed to send test webhook",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.TestWebhookResponse{
+		Results: results,
+	})
+}
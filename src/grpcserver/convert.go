@@ -0,0 +1,151 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	newsv1 "news-inshorts/src/api/news/v1"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// errorCodes maps errors raised by the service and repository layers to the status code
+// returned for them, like the error classes of the HTTP error handler. Dependency outages are
+// checked before deadlines since an LLM call that hit its own timeout is an outage.
+var errorCodes = []struct {
+	target  error
+	code    codes.Code
+	message string
+}{
+	{services.ErrCircuitOpen, codes.Unavailable, "LLM service unavailable"},
+	{services.ErrLLMBusy, codes.Unavailable, "LLM service unavailable"},
+	{services.ErrLLMBudgetExceeded, codes.Unavailable, "LLM service unavailable"},
+	{services.ErrLLMUnavailable, codes.Unavailable, "LLM service unavailable"},
+	{repositories.ErrDatabaseUnavailable, codes.Unavailable, "Database connection error"},
+	{context.DeadlineExceeded, codes.DeadlineExceeded, "Request timed out"},
+	{context.Canceled, codes.Canceled, "Request cancelled"},
+	{repositories.ErrArticleNotFound, codes.NotFound, "Article not found"},
+	{services.ErrQueryEmpty, codes.InvalidArgument, "Query contains no searchable text"},
+	{services.ErrQueryTooLong, codes.InvalidArgument, "Query is too long"},
+	{services.ErrUnsupportedSummaryLang, codes.InvalidArgument, "Summary language is not supported"},
+}
+
+// statusError converts err to a gRPC status error. Validation errors become INVALID_ARGUMENT
+// with a BadRequest detail listing the invalid fields; unknown errors become INTERNAL without
+// their message, which may leak internals.
+func statusError(err error) error {
+	var validationErrors types.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(validationErrors))
+		for i, e := range validationErrors {
+			violations[i] = &errdetails.BadRequest_FieldViolation{
+				Field:       e.Field,
+				Description: e.Message,
+				Reason:      e.Code,
+			}
+		}
+
+		st := status.New(codes.InvalidArgument, validationErrors.Error())
+		if detailed, detailErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	}
+
+	for _, class := range errorCodes {
+		if errors.Is(err, class.target) {
+			return status.Error(class.code, class.message)
+		}
+	}
+
+	return status.Error(codes.Internal, "Internal server error")
+}
+
+// peerIP returns the IP address of the caller, or an empty string when it is unknown
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// toArticle converts an article to its message
+func toArticle(article *models.Article) *newsv1.Article {
+	return &newsv1.Article{
+		Id:              article.ID,
+		Title:           article.Title,
+		Description:     article.Description,
+		Url:             article.URL,
+		CanonicalUrl:    article.CanonicalURL,
+		PublicationDate: toTimestamp(article.PublicationDate),
+		SourceName:      article.SourceName,
+		Category:        article.Category,
+		RelevanceScore:  article.RelevanceScore,
+		Latitude:        article.Latitude,
+		Longitude:       article.Longitude,
+		Country:         article.Country,
+		Region:          article.Region,
+		Summary:         article.Summary,
+		Content:         article.Content,
+		Sentiment:       article.Sentiment,
+		CreatedAt:       toTimestamp(article.CreatedAt),
+		UpdatedAt:       toTimestamp(article.UpdatedAt),
+	}
+}
+
+// toQueryArticle converts an article matching a query to its message
+func toQueryArticle(article *models.EnrichedArticle) *newsv1.QueryArticle {
+	return &newsv1.QueryArticle{
+		Article: toArticle(&article.Article),
+		Match: &newsv1.MatchInfo{
+			DistanceKm:        article.DistanceKm,
+			Similarity:        article.Similarity,
+			MatchedCategories: article.MatchedCategories,
+			MatchedSources:    article.MatchedSources,
+			AlsoReportedBy:    article.AlsoReportedBy,
+			PreferenceBoost:   article.PreferenceBoost,
+		},
+		Rank:        int32(article.Rank),
+		SummaryLang: article.SummaryLang,
+	}
+}
+
+// toTrendingArticle converts a trending article to its message
+func toTrendingArticle(article *models.TrendingArticle) *newsv1.TrendingArticle {
+	eventCounts := make(map[string]int32, len(article.EventCounts))
+	for eventType, count := range article.EventCounts {
+		eventCounts[eventType] = int32(count)
+	}
+
+	return &newsv1.TrendingArticle{
+		Article:       toArticle(&article.Article),
+		TrendingScore: article.TrendingScore,
+		Rank:          int32(article.Rank),
+		EventCounts:   eventCounts,
+		DistanceKm:    article.DistanceKm,
+	}
+}
+
+// toTimestamp converts t, leaving the zero time unset
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,197 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	newsv1 "news-inshorts/src/api/news/v1"
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/google/uuid"
+)
+
+// newsServer implements newsv1.NewsServiceServer by validating requests like the HTTP
+// controllers do and calling the same services
+type newsServer struct {
+	newsv1.UnimplementedNewsServiceServer
+	svcs   *services.Services
+	logger infra.Logger
+}
+
+// newNewsServer creates the NewsService implementation
+func newNewsServer(svcs *services.Services, logger infra.Logger) *newsServer {
+	return &newsServer{
+		svcs:   svcs,
+		logger: logger,
+	}
+}
+
+// QueryArticles answers a natural-language query. The query is logged like queries made over
+// HTTP, with the caller's address identifying the client when no user is given.
+func (s *newsServer) QueryArticles(ctx context.Context, in *newsv1.QueryArticlesRequest) (*newsv1.QueryArticlesResponse, error) {
+	req := types.QueryArticlesRequest{
+		Query:         in.GetQuery(),
+		Limit:         int(in.GetLimit()),
+		MinSimilarity: in.MinSimilarity,
+		UserID:        in.GetUserId(),
+		SummaryLang:   in.GetSummaryLang(),
+		NoCorrect:     in.GetNoCorrect(),
+	}
+	if location := in.GetLocation(); location != nil {
+		req.Lat, req.Lon = location.GetLatitude(), location.GetLongitude()
+	}
+	if err := req.Validate(); err != nil {
+		return nil, statusError(err)
+	}
+
+	start := time.Now()
+	result, err := s.svcs.Article.ProcessArticleQuery(ctx, req.Query, req.Location, req.Limit, req.MinSimilarity, req.UserID, req.SummaryLang, req.NoCorrect)
+	if err != nil {
+		s.logger.Error("Failed to process article query", err, map[string]interface{}{
+			"query_length": len(req.Query),
+			"location":     req.Location,
+			"transport":    "grpc",
+		})
+		return nil, statusError(err)
+	}
+
+	s.svcs.QueryLog.Record(ctx, models.QueryLogEntry{
+		Query:       result.Query,
+		HadLocation: req.Location != nil,
+		ResultCount: result.Total,
+		LatencyMs:   time.Since(start).Milliseconds(),
+	}, req.UserID, peerIP(ctx))
+
+	articles := make([]*newsv1.QueryArticle, len(result.Articles))
+	for i := range result.Articles {
+		articles[i] = toQueryArticle(&result.Articles[i])
+	}
+
+	return &newsv1.QueryArticlesResponse{
+		Articles:       articles,
+		Total:          int32(result.Total),
+		Degraded:       result.Degraded,
+		QueryTruncated: result.QueryTruncated,
+		CorrectedQuery: result.CorrectedQuery,
+		Relaxed:        result.Relaxed,
+	}, nil
+}
+
+// FilterArticles returns the articles matching structured filters
+func (s *newsServer) FilterArticles(ctx context.Context, in *newsv1.FilterArticlesRequest) (*newsv1.FilterArticlesResponse, error) {
+	req := types.FilterArticlesRequest{
+		Category:        in.GetCategory(),
+		CategoryMode:    in.GetCategoryMode(),
+		Source:          in.GetSource(),
+		ExcludeCategory: in.GetExcludeCategory(),
+		ExcludeSource:   in.GetExcludeSource(),
+		Country:         in.GetCountry(),
+		Region:          in.GetRegion(),
+		Radius:          in.GetRadius(),
+		ScoreThreshold:  in.GetScoreThreshold(),
+		Sentiment:       strings.Join(in.GetSentiment(), ","),
+		IngestedAfterID: in.GetIngestedAfterId(),
+		Limit:           int(in.GetLimit()),
+		IncludeArchived: in.GetIncludeArchived(),
+	}
+	if location := in.GetLocation(); location != nil {
+		req.Lat, req.Lon = location.GetLatitude(), location.GetLongitude()
+	}
+	if in.IngestedAfter != nil {
+		req.IngestedAfter = in.GetIngestedAfter().AsTime().Format(time.RFC3339Nano)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, statusError(err)
+	}
+
+	articles, err := s.svcs.Article.FilterArticles(ctx, req)
+	if err != nil {
+		s.logger.Error("Failed to filter articles", err, map[string]interface{}{
+			"filters":   req,
+			"transport": "grpc",
+		})
+		return nil, statusError(err)
+	}
+
+	out := make([]*newsv1.Article, len(articles))
+	for i := range articles {
+		out[i] = toArticle(&articles[i])
+	}
+
+	return &newsv1.FilterArticlesResponse{Articles: out}, nil
+}
+
+// GetTrending returns the articles trending near the requested location, or near the caller's
+// location when none is given
+func (s *newsServer) GetTrending(ctx context.Context, in *newsv1.GetTrendingRequest) (*newsv1.GetTrendingResponse, error) {
+	req := types.GetTrendingRequest{Limit: int(in.GetLimit())}
+	if location := in.GetLocation(); location != nil {
+		req.Lat, req.Lon = location.GetLatitude(), location.GetLongitude()
+	}
+	if err := req.Validate(); err != nil {
+		return nil, statusError(err)
+	}
+
+	location := types.ResolvedLocation{Latitude: req.Lat, Longitude: req.Lon, Source: models.LocationSourceQuery}
+	if !req.HasCoordinates() {
+		located, source := s.svcs.Locator.Locate(peerIP(ctx))
+		location = types.ResolvedLocation{Latitude: located.Latitude, Longitude: located.Longitude, Source: source}
+	}
+
+	articles, err := s.svcs.Article.GetTrendingNews(ctx, location.Latitude, location.Longitude, req.Limit)
+	if err != nil {
+		s.logger.Error("Failed to retrieve trending news", err, map[string]interface{}{
+			"lat":             location.Latitude,
+			"lon":             location.Longitude,
+			"location_source": location.Source,
+			"limit":           req.Limit,
+			"transport":       "grpc",
+		})
+		return nil, statusError(err)
+	}
+
+	out := make([]*newsv1.TrendingArticle, len(articles))
+	for i := range articles {
+		out[i] = toTrendingArticle(&articles[i])
+	}
+
+	return &newsv1.GetTrendingResponse{
+		Articles: out,
+		Total:    int32(len(out)),
+		Location: &newsv1.ResolvedLocation{
+			Latitude:  location.Latitude,
+			Longitude: location.Longitude,
+			Source:    location.Source,
+		},
+	}, nil
+}
+
+// GetArticle returns a live article by id
+func (s *newsServer) GetArticle(ctx context.Context, in *newsv1.GetArticleRequest) (*newsv1.GetArticleResponse, error) {
+	if _, err := uuid.Parse(in.GetId()); err != nil {
+		return nil, statusError(types.ValidationErrors{{
+			Field:   "id",
+			Code:    types.ValidationCodeInvalidFormat,
+			Message: "id must be a valid UUID",
+		}})
+	}
+
+	article, err := s.svcs.Repos.Article.FindByID(ctx, in.GetId())
+	if err != nil {
+		if !errors.Is(err, repositories.ErrArticleNotFound) {
+			s.logger.Error("Failed to retrieve article", err, map[string]interface{}{
+				"id":        in.GetId(),
+				"transport": "grpc",
+			})
+		}
+		return nil, statusError(err)
+	}
+
+	return &newsv1.GetArticleResponse{Article: toArticle(article)}, nil
+}
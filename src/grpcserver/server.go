@@ -0,0 +1,144 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	newsv1 "news-inshorts/src/api/news/v1"
+	"news-inshorts/src/infra"
+	"news-inshorts/src/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer creates the gRPC server exposing NewsService on top of the application services.
+// Calls get the same time budgets as the matching HTTP routes; a shorter deadline set by the
+// caller wins, and either one is carried by the context down to the database and the LLM.
+func NewServer(cfg *infra.Config, svcs *services.Services, logger infra.Logger) *grpc.Server {
+	timeouts := cfg.Server.Timeouts
+	budgets := map[string]time.Duration{
+		newsv1.NewsService_QueryArticles_FullMethodName:  timeouts.Query,
+		newsv1.NewsService_FilterArticles_FullMethodName: timeouts.Filter,
+		newsv1.NewsService_GetTrending_FullMethodName:    timeouts.Trending,
+		newsv1.NewsService_GetArticle_FullMethodName:     timeouts.Default,
+	}
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		logCalls(logger),
+		recoverPanics(logger),
+		resolveTenant(svcs.Tenants),
+		timeBudget(budgets),
+	))
+
+	newsv1.RegisterNewsServiceServer(server, newNewsServer(svcs, logger))
+
+	if cfg.GRPC.Reflection {
+		reflection.Register(server)
+		logger.Warn("gRPC reflection is enabled, disable it in production", nil)
+	}
+
+	return server
+}
+
+// Shutdown stops server, letting in-flight calls finish for at most timeout before they are
+// cancelled
+func Shutdown(server *grpc.Server, timeout time.Duration, logger infra.Logger) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		logger.Warn("Timed out waiting for gRPC calls to finish, cancelling them", map[string]interface{}{
+			"timeout": timeout.String(),
+		})
+		server.Stop()
+	}
+}
+
+// logCalls logs every call like the HTTP request log
+func logCalls(logger infra.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		logger.Info("gRPC request", map[string]interface{}{
+			"method":   info.FullMethod,
+			"code":     status.Code(err).String(),
+			"duration": time.Since(start).String(),
+			"ip":       peerIP(ctx),
+		})
+
+		return resp, err
+	}
+}
+
+// recoverPanics turns a panicking handler into an INTERNAL error instead of a crashed process
+func recoverPanics(logger infra.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("gRPC handler panicked", fmt.Errorf("%v", r), map[string]interface{}{
+					"method": info.FullMethod,
+				})
+				err = status.Error(codes.Internal, "Internal server error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// resolveTenant resolves the tenant of each call from its x-api-key and x-tenant-id metadata,
+// like the HTTP tenant middleware, and stores it on the call's context
+func resolveTenant(resolver *infra.TenantResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		tenant, err := resolver.Resolve(firstValue(md, "x-api-key"), firstValue(md, "x-tenant-id"))
+		switch {
+		case errors.Is(err, infra.ErrInvalidTenantKey), errors.Is(err, infra.ErrTenantKeyRequired):
+			return nil, status.Error(codes.Unauthenticated, "Missing or invalid API key")
+		case errors.Is(err, infra.ErrTenantMismatch):
+			return nil, status.Error(codes.PermissionDenied, "x-tenant-id does not match the API key")
+		case errors.Is(err, infra.ErrUnknownTenant):
+			return nil, status.Error(codes.InvalidArgument, "x-tenant-id names an unknown tenant")
+		case err != nil:
+			return nil, status.Error(codes.Internal, "Internal server error")
+		}
+
+		return handler(infra.WithTenant(ctx, tenant), req)
+	}
+}
+
+// firstValue returns the first value of a metadata key, or an empty string when it is unset
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// timeBudget bounds each call by the budget of its method. Methods without a budget, or with
+// a non-positive one, only have the caller's deadline.
+func timeBudget(budgets map[string]time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		budget := budgets[info.FullMethod]
+		if budget <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, budget)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
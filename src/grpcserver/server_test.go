@@ -0,0 +1,385 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	newsv1 "news-inshorts/src/api/news/v1"
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testArticleID = "0b6f1c6e-5a0e-4f57-9c55-3d1f8e0b2a11"
+
+// stubArticleService answers with canned results and remembers the tenant and arguments of
+// the last call
+type stubArticleService struct {
+	services.ArticleService
+
+	mu         sync.Mutex
+	tenant     string
+	lastQuery  string
+	lastFilter types.FilterArticlesRequest
+	lastLat    float64
+	lastLon    float64
+
+	queryResult *services.QueryResult
+	filtered    []models.Article
+	trending    []models.TrendingArticle
+	err         error
+	panics      bool
+}
+
+func (s *stubArticleService) record(ctx context.Context) {
+	s.tenant = infra.TenantFromContext(ctx, "")
+}
+
+func (s *stubArticleService) ProcessArticleQuery(ctx context.Context, query string, _ *models.Location, _ int, _ *float64, _ string, _ string, _ bool) (*services.QueryResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.panics {
+		panic("stub panic")
+	}
+	s.record(ctx)
+	s.lastQuery = query
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.queryResult, nil
+}
+
+func (s *stubArticleService) FilterArticles(ctx context.Context, params types.FilterArticlesRequest) ([]models.Article, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.record(ctx)
+	s.lastFilter = params
+	return s.filtered, s.err
+}
+
+func (s *stubArticleService) GetTrendingNews(ctx context.Context, lat, lon float64, _ int) ([]models.TrendingArticle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.record(ctx)
+	s.lastLat, s.lastLon = lat, lon
+	return s.trending, s.err
+}
+
+// stubArticleRepository finds the articles it holds
+type stubArticleRepository struct {
+	repositories.ArticleRepository
+	articles map[string]*models.Article
+}
+
+func (r *stubArticleRepository) FindByID(_ context.Context, id string) (*models.Article, error) {
+	if article, ok := r.articles[id]; ok {
+		return article, nil
+	}
+	return nil, repositories.ErrArticleNotFound
+}
+
+// stubQueryLog discards recorded queries
+type stubQueryLog struct {
+	services.QueryLogService
+}
+
+func (stubQueryLog) Record(context.Context, models.QueryLogEntry, string, string) {}
+
+// stubLocator locates every caller at a fixed place
+type stubLocator struct{}
+
+func (stubLocator) Locate(string) (models.Location, string) {
+	return models.Location{Latitude: 28.61, Longitude: 77.21}, models.LocationSourceDefault
+}
+
+// newTestClient serves NewServer over an in-memory listener and returns a client of it
+func newTestClient(t *testing.T, article *stubArticleService) newsv1.NewsServiceClient {
+	t.Helper()
+
+	svcs := &services.Services{
+		Article:  article,
+		QueryLog: stubQueryLog{},
+		Locator:  stubLocator{},
+		Repos: &repositories.Repositories{
+			Article: &stubArticleRepository{articles: map[string]*models.Article{
+				testArticleID: {ID: testArticleID, Title: "Storm hits the coast", SourceName: "Example", Category: []string{"world"}},
+			}},
+		},
+		Tenants: infra.NewTenantResolver(&infra.TenantConfig{
+			Default: infra.DefaultTenant,
+			APIKeys: map[string]string{"acme": "acme-key"},
+		}, ""),
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(&infra.Config{}, svcs, infra.NewRecordingLogger())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial the test server: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return newsv1.NewNewsServiceClient(conn)
+}
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestQueryArticles(t *testing.T) {
+	article := &stubArticleService{queryResult: &services.QueryResult{
+		Query: "storm",
+		Articles: []models.EnrichedArticle{{
+			Article: models.Article{ID: testArticleID, Title: "Storm hits the coast"},
+			Rank:    1,
+		}},
+		Total:    1,
+		Degraded: true,
+	}}
+	client := newTestClient(t, article)
+
+	resp, err := client.QueryArticles(testContext(t), &newsv1.QueryArticlesRequest{Query: "storm"})
+	if err != nil {
+		t.Fatalf("QueryArticles: %v", err)
+	}
+	if resp.GetTotal() != 1 || len(resp.GetArticles()) != 1 {
+		t.Fatalf("got %d of %d articles, want 1 of 1", len(resp.GetArticles()), resp.GetTotal())
+	}
+	if got := resp.GetArticles()[0]; got.GetArticle().GetId() != testArticleID || got.GetRank() != 1 {
+		t.Errorf("article = %s rank %d, want %s rank 1", got.GetArticle().GetId(), got.GetRank(), testArticleID)
+	}
+	if !resp.GetDegraded() {
+		t.Error("degraded flag was dropped")
+	}
+	if article.lastQuery != "storm" {
+		t.Errorf("service got query %q, want storm", article.lastQuery)
+	}
+	if article.tenant != infra.DefaultTenant {
+		t.Errorf("service got tenant %q, want %q", article.tenant, infra.DefaultTenant)
+	}
+}
+
+func TestFilterArticles(t *testing.T) {
+	article := &stubArticleService{filtered: []models.Article{{ID: testArticleID, Title: "Storm hits the coast"}}}
+	client := newTestClient(t, article)
+
+	resp, err := client.FilterArticles(testContext(t), &newsv1.FilterArticlesRequest{
+		Category:  []string{"world"},
+		Sentiment: []string{"negative", "neutral"},
+	})
+	if err != nil {
+		t.Fatalf("FilterArticles: %v", err)
+	}
+	if len(resp.GetArticles()) != 1 || resp.GetArticles()[0].GetId() != testArticleID {
+		t.Fatalf("articles = %v, want %s", resp.GetArticles(), testArticleID)
+	}
+	if got := article.lastFilter.Category; len(got) != 1 || got[0] != "world" {
+		t.Errorf("service got categories %v, want [world]", got)
+	}
+	if got := article.lastFilter.Sentiment; got != "negative,neutral" {
+		t.Errorf("service got sentiment %q, want negative,neutral", got)
+	}
+}
+
+func TestGetTrending(t *testing.T) {
+	tests := []struct {
+		name       string
+		location   *newsv1.Location
+		wantLat    float64
+		wantLon    float64
+		wantSource string
+	}{
+		{
+			name:       "requested location",
+			location:   &newsv1.Location{Latitude: 12.97, Longitude: 77.59},
+			wantLat:    12.97,
+			wantLon:    77.59,
+			wantSource: models.LocationSourceQuery,
+		},
+		{
+			name:       "caller location",
+			wantLat:    28.61,
+			wantLon:    77.21,
+			wantSource: models.LocationSourceDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := &stubArticleService{trending: []models.TrendingArticle{{
+				Article:       models.Article{ID: testArticleID},
+				TrendingScore: 0.8,
+				Rank:          1,
+				EventCounts:   map[string]int{"view": 3},
+			}}}
+			client := newTestClient(t, article)
+
+			resp, err := client.GetTrending(testContext(t), &newsv1.GetTrendingRequest{Location: tt.location, Limit: 5})
+			if err != nil {
+				t.Fatalf("GetTrending: %v", err)
+			}
+			if resp.GetTotal() != 1 || resp.GetArticles()[0].GetEventCounts()["view"] != 3 {
+				t.Fatalf("articles = %v, want one with 3 views", resp.GetArticles())
+			}
+			if article.lastLat != tt.wantLat || article.lastLon != tt.wantLon {
+				t.Errorf("ranked for %v,%v, want %v,%v", article.lastLat, article.lastLon, tt.wantLat, tt.wantLon)
+			}
+			if got := resp.GetLocation().GetSource(); got != tt.wantSource {
+				t.Errorf("location source = %q, want %q", got, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestGetArticle(t *testing.T) {
+	client := newTestClient(t, &stubArticleService{})
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode codes.Code
+	}{
+		{name: "found", id: testArticleID, wantCode: codes.OK},
+		{name: "missing", id: "6a1d3c9e-2b7f-4c1e-8a0d-9f4e5b6c7d8e", wantCode: codes.NotFound},
+		{name: "malformed id", id: "not-a-uuid", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetArticle(testContext(t), &newsv1.GetArticleRequest{Id: tt.id})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && resp.GetArticle().GetId() != tt.id {
+				t.Errorf("article id = %q, want %q", resp.GetArticle().GetId(), tt.id)
+			}
+		})
+	}
+}
+
+// TestStatusErrors checks that service errors reach clients with the status code of their
+// class, and unknown errors as INTERNAL without their message
+func TestStatusErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{"LLM unavailable", services.ErrLLMUnavailable, codes.Unavailable, "LLM service unavailable"},
+		{"circuit open", fmt.Errorf("failed to analyze query: %w", services.ErrCircuitOpen), codes.Unavailable, "LLM service unavailable"},
+		{"database unavailable", repositories.ErrDatabaseUnavailable, codes.Unavailable, "Database connection error"},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded, "Request timed out"},
+		{"not found", repositories.ErrArticleNotFound, codes.NotFound, "Article not found"},
+		{"empty query", services.ErrQueryEmpty, codes.InvalidArgument, "Query contains no searchable text"},
+		{"unsupported language", services.ErrUnsupportedSummaryLang, codes.InvalidArgument, "Summary language is not supported"},
+		{"unknown", errors.New("pq: relation does not exist"), codes.Internal, "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, &stubArticleService{err: tt.err})
+
+			_, err := client.QueryArticles(testContext(t), &newsv1.QueryArticlesRequest{Query: "storm"})
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("code = %s, want %s", st.Code(), tt.wantCode)
+			}
+			if st.Message() != tt.wantMessage {
+				t.Errorf("message = %q, want %q", st.Message(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidationErrorDetails(t *testing.T) {
+	client := newTestClient(t, &stubArticleService{})
+
+	_, err := client.QueryArticles(testContext(t), &newsv1.QueryArticlesRequest{Limit: 500})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %s, want InvalidArgument", st.Code())
+	}
+
+	fields := map[string]bool{}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields[violation.GetField()] = true
+			}
+		}
+	}
+	if !fields["query"] || !fields["limit"] {
+		t.Errorf("field violations = %v, want query and limit", fields)
+	}
+}
+
+func TestTenantMetadata(t *testing.T) {
+	tests := []struct {
+		name       string
+		md         metadata.MD
+		wantCode   codes.Code
+		wantTenant string
+	}{
+		{name: "default tenant", md: metadata.MD{}, wantCode: codes.OK, wantTenant: infra.DefaultTenant},
+		{name: "tenant key", md: metadata.Pairs("x-api-key", "acme-key"), wantCode: codes.OK, wantTenant: "acme"},
+		{name: "unknown tenant", md: metadata.Pairs("x-tenant-id", "initech"), wantCode: codes.InvalidArgument},
+		{name: "keyed tenant without key", md: metadata.Pairs("x-tenant-id", "acme"), wantCode: codes.Unauthenticated},
+		{name: "invalid key", md: metadata.Pairs("x-api-key", "wrong-key"), wantCode: codes.Unauthenticated},
+		{name: "key of another tenant", md: metadata.Pairs("x-api-key", "acme-key", "x-tenant-id", infra.DefaultTenant), wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := &stubArticleService{filtered: []models.Article{}}
+			client := newTestClient(t, article)
+
+			ctx := metadata.NewOutgoingContext(testContext(t), tt.md)
+			_, err := client.FilterArticles(ctx, &newsv1.FilterArticlesRequest{Category: []string{"world"}})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if article.tenant != tt.wantTenant {
+				t.Errorf("service got tenant %q, want %q", article.tenant, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestRecoverPanics(t *testing.T) {
+	client := newTestClient(t, &stubArticleService{panics: true})
+
+	_, err := client.QueryArticles(testContext(t), &newsv1.QueryArticlesRequest{Query: "storm"})
+	if code := status.Code(err); code != codes.Internal {
+		t.Fatalf("code = %s, want Internal", code)
+	}
+}
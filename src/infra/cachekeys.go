@@ -0,0 +1,110 @@
+package infra
+
+import "fmt"
+
+// Redis key prefixes. Every key written to Redis is built from one of these, so the cache
+// flusher's patterns and the writers cannot drift apart. Keys of results computed from a
+// tenant's articles or events include the tenant, so tenants never read each other's entries.
+const (
+	TrendingCachePrefix      = "trending:"
+	FilterCachePrefix        = "filter:"
+	FeedCachePrefix          = "feed:rss:"
+	QueryAnalysisCachePrefix = "query:analysis:"
+	StatsCachePrefix         = "stats:article:"
+	CorpusStatsCachePrefix   = "stats:corpus:"
+	GeocodeCachePrefix       = "geocode:"
+	IdempotencyPrefix        = "idempotency:"
+	LLMUsagePrefix           = "llm:usage:"
+	UserDataPrefix           = "user:"
+	SummaryLangCachePrefix   = "summary:lang:"
+	SummarizeLockPrefix      = "lock:summarize:"
+	SpellDictionaryPrefix    = "query:spell:dictionary:"
+)
+
+// FilterCacheGenerationKey holds a counter that is part of every filter cache key. Bumping it
+// on writes orphans all cached results at once; the orphans expire with their TTL.
+const FilterCacheGenerationKey = FilterCachePrefix + "generation"
+
+// TrendingCacheKey returns the key for a tenant's trending results of a geohash cell
+func TrendingCacheKey(tenant, cell string) string {
+	return TrendingCachePrefix + "cell:" + tenant + ":" + cell
+}
+
+// TrendingCellRequestsKey returns the key of the sorted set counting a tenant's trending
+// requests of each geohash cell during a UTC day formatted as YYYY-MM-DD
+func TrendingCellRequestsKey(tenant, day string) string {
+	return TrendingCachePrefix + "requests:" + tenant + ":" + day
+}
+
+// TrendingTopicsCacheKey returns the key for a tenant's trending topics around the (already
+// rounded) coordinates, extracted from the top articles trending articles
+func TrendingTopicsCacheKey(tenant string, lat, lon float64, articles, limit int) string {
+	return fmt.Sprintf("%stopics:%s:%.2f:%.2f:%d:%d", TrendingCachePrefix, tenant, lat, lon, articles, limit)
+}
+
+// TrendingEntitiesCacheKey returns the key for the entities extracted from an article's title
+func TrendingEntitiesCacheKey(articleID string) string {
+	return TrendingCachePrefix + "entities:" + articleID
+}
+
+// FilterCacheKey returns the key for a tenant's filter results with the given parameter hash
+func FilterCacheKey(generation int64, tenant, hash string) string {
+	return fmt.Sprintf("%s%d:%s:%s", FilterCachePrefix, generation, tenant, hash)
+}
+
+// FeedCacheKey returns the key for a tenant's rendered feed with the given request hash
+func FeedCacheKey(tenant, hash string) string {
+	return FeedCachePrefix + tenant + ":" + hash
+}
+
+// QueryAnalysisCacheKey returns the key for a tenant's query analysis given the hash of the
+// allowed source/category lists and the hash of the normalized query
+func QueryAnalysisCacheKey(tenant, listHash, queryHash string) string {
+	return QueryAnalysisCachePrefix + tenant + ":" + listHash + ":" + queryHash
+}
+
+// StatsCacheKey returns the key for an article's stats as seen by a tenant
+func StatsCacheKey(tenant, articleID string) string {
+	return StatsCachePrefix + tenant + ":" + articleID
+}
+
+// CorpusStatsCacheKey returns the key for the corpus stats of a tenant
+func CorpusStatsCacheKey(tenant string) string {
+	return CorpusStatsCachePrefix + tenant
+}
+
+// SpellDictionaryKey returns the key for the spelling dictionary of a tenant's corpus
+func SpellDictionaryKey(tenant string) string {
+	return SpellDictionaryPrefix + tenant
+}
+
+// SummaryLangCacheKey returns the key for an article's summary in the language with code lang
+func SummaryLangCacheKey(articleID, lang string) string {
+	return SummaryLangCachePrefix + lang + ":" + articleID
+}
+
+// SummarizeLockKey returns the key locking summary generation for an article
+func SummarizeLockKey(articleID string) string {
+	return SummarizeLockPrefix + articleID
+}
+
+// GeocodeCacheKey returns the key for a geocoded place name
+func GeocodeCacheKey(provider, place string) string {
+	return GeocodeCachePrefix + provider + ":" + place
+}
+
+// ReverseGeocodeCacheKey returns the key for the place containing the coordinates. They are
+// rounded to two decimals (about 1 km), so articles from the same city share an entry.
+func ReverseGeocodeCacheKey(provider string, lat, lon float64) string {
+	return fmt.Sprintf("%s%s:reverse:%.2f,%.2f", GeocodeCachePrefix, provider, lat, lon)
+}
+
+// IdempotencyKey returns the key for a tenant's scoped idempotency key
+func IdempotencyKey(tenant, scope, key string) string {
+	return IdempotencyPrefix + tenant + ":" + scope + ":" + key
+}
+
+// LLMUsageKey returns the key holding LLM usage for a UTC day formatted as YYYY-MM-DD
+func LLMUsageKey(day string) string {
+	return LLMUsagePrefix + day
+}
@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestTenantCacheKeysDiffer checks that every key of tenant-scoped data includes the tenant,
+// so the same parameters never share an entry across tenants
+func TestTenantCacheKeysDiffer(t *testing.T) {
+	keys := []struct {
+		name string
+		key  func(tenant string) string
+	}{
+		{"trending", func(tenant string) string { return TrendingCacheKey(tenant, "tdr1") }},
+		{"trending requests", func(tenant string) string { return TrendingCellRequestsKey(tenant, "2026-10-15") }},
+		{"trending topics", func(tenant string) string { return TrendingTopicsCacheKey(tenant, 12.97, 77.59, 50, 10) }},
+		{"filter", func(tenant string) string { return FilterCacheKey(3, tenant, "abc") }},
+		{"feed", func(tenant string) string { return FeedCacheKey(tenant, "abc") }},
+		{"query analysis", func(tenant string) string { return QueryAnalysisCacheKey(tenant, "lists", "query") }},
+		{"article stats", func(tenant string) string { return StatsCacheKey(tenant, "article-1") }},
+		{"corpus stats", CorpusStatsCacheKey},
+		{"spelling dictionary", SpellDictionaryKey},
+		{"idempotency", func(tenant string) string { return IdempotencyKey(tenant, "interaction", "user-1") }},
+	}
+
+	for _, tt := range keys {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.key("tenant-a"), tt.key("tenant-b")
+			if a == b {
+				t.Fatalf("tenants share the key %q", a)
+			}
+			if !strings.Contains(a, "tenant-a") || !strings.Contains(b, "tenant-b") {
+				t.Errorf("keys %q and %q do not name their tenant", a, b)
+			}
+		})
+	}
+}
+
+func TestTenantCacheKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"trending", TrendingCacheKey("acme", "tdr1"), "trending:cell:acme:tdr1"},
+		{"trending requests", TrendingCellRequestsKey("acme", "2026-10-15"), "trending:requests:acme:2026-10-15"},
+		{"trending topics", TrendingTopicsCacheKey("acme", 12.971, 77.594, 50, 10), "trending:topics:acme:12.97:77.59:50:10"},
+		{"filter", FilterCacheKey(3, "acme", "abc"), "filter:3:acme:abc"},
+		{"feed", FeedCacheKey("acme", "abc"), "feed:rss:acme:abc"},
+		{"query analysis", QueryAnalysisCacheKey("acme", "lists", "query"), "query:analysis:acme:lists:query"},
+		{"article stats", StatsCacheKey("acme", "article-1"), "stats:article:acme:article-1"},
+		{"corpus stats", CorpusStatsCacheKey("acme"), "stats:corpus:acme"},
+		{"spelling dictionary", SpellDictionaryKey("acme"), "query:spell:dictionary:acme"},
+		{"idempotency", IdempotencyKey("acme", "interaction", "user-1"), IdempotencyPrefix + "acme:interaction:user-1"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s key = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
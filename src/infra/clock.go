@@ -0,0 +1,25 @@
+package infra
+
+import "time"
+
+// Clock tells the current time. Time-sensitive code takes a Clock rather than calling
+// time.Now, so it can run against a fixed time, e.g. to score historical data.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now
+type SystemClock struct{}
+
+// Now implements Clock
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock is a Clock stopped at the given time
+type FixedClock time.Time
+
+// Now implements Clock
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
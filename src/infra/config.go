@@ -2,8 +2,12 @@ package infra
 
 import (
 	"fmt"
+	"math"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,12 +15,32 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	LLM      LLMConfig
-	Cache    CacheConfig
-	Redis    RedisConfig
-	Log      LogConfig
+	Database   DatabaseConfig
+	Server     ServerConfig
+	GRPC       GRPCConfig
+	LLM        LLMConfig
+	Cache      CacheConfig
+	Redis      RedisConfig
+	Log        LogConfig
+	Enrich     EnrichConfig
+	Geocoder   GeocodingConfig
+	Content    ContentConfig
+	GeoIP      GeoIPConfig
+	Query      QueryConfig
+	Retention  RetentionConfig
+	Archive    ArchiveConfig
+	Relevance  RelevanceConfig
+	Searches   SavedSearchConfig
+	CORS       CORSConfig
+	Webhook    WebhookConfig
+	Export     ExportConfig
+	Vector     VectorConfig
+	Dedupe     DedupeConfig
+	Cluster    ClusterConfig
+	QueryLog   QueryLogConfig
+	Trending   TrendingConfig
+	Experiment ExperimentConfig
+	Tenant     TenantConfig
 }
 
 // DatabaseConfig holds database connection settings
@@ -26,6 +50,12 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+	// SlowQueryThreshold is the duration above which GORM logs a query as slow; 0 disables it
+	SlowQueryThreshold time.Duration
+	// LogLevel is the GORM log level, one of the DBLogLevel* constants
+	LogLevel string
+	// LogParams includes statement values in logged SQL instead of placeholders
+	LogParams bool
 }
 
 // ServerConfig holds server settings
@@ -33,17 +63,348 @@ type ServerConfig struct {
 	Port         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// AdminAPIKey protects admin and compliance endpoints; when empty those endpoints are disabled
+	AdminAPIKey string
+	Timeouts    RequestTimeoutConfig
+	Concurrency ConcurrencyLimitConfig
+	// CompressLevel is the response compression level: -1 disabled, 0 default, 1 best speed,
+	// 2 best compression. Bodies under 200 bytes are never compressed.
+	CompressLevel int
+	// BodyLimit caps request bodies in bytes; LoadBodyLimit replaces it for POST /news/load
+	BodyLimit     int
+	LoadBodyLimit int
+	// StrictJSON rejects unknown fields in article and interaction bodies
+	StrictJSON bool
+	// JobRetention is how long finished background jobs stay pollable before they are dropped
+	JobRetention time.Duration
+}
+
+// GRPCConfig holds settings for the gRPC API served next to the HTTP API
+type GRPCConfig struct {
+	// Port is the port the gRPC server listens on; empty disables the server
+	Port string
+	// Reflection registers the server reflection service, which lets tools such as grpcurl
+	// list and call the API without the proto files. Keep it off in production.
+	Reflection bool
+	// ShutdownTimeout bounds how long in-flight calls may finish on shutdown before they are
+	// cancelled
+	ShutdownTimeout time.Duration
+}
+
+// CORSConfig holds cross-origin resource sharing settings
+type CORSConfig struct {
+	// AllowedOrigins is a comma-separated list of origins, or "*" for any origin
+	AllowedOrigins   string
+	AllowedMethods   string
+	AllowCredentials bool
+}
+
+// minS3PartSize is the smallest part S3 accepts in a multipart upload, except for the last one
+const minS3PartSize = 5 * 1024 * 1024
+
+// ExportConfig holds settings for bulk article exports and corpus snapshots
+type ExportConfig struct {
+	// MaxRows caps the number of articles in one export
+	MaxRows int
+	// S3 holds the bucket snapshots are uploaded to; snapshots are disabled without a bucket
+	S3 S3Config
+	// SnapshotInterval is how often a snapshot is taken; 0 disables the schedule
+	SnapshotInterval time.Duration
+	// SnapshotMode is the mode of scheduled snapshots: full or incremental
+	SnapshotMode string
+	// SnapshotIncludeVectors adds embeddings to scheduled snapshots
+	SnapshotIncludeVectors bool
+}
+
+// S3Config holds the settings of an S3-compatible bucket
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket as endpoint/bucket rather than bucket.endpoint, as most
+	// self-hosted S3-compatible stores require
+	PathStyle bool
+	// Prefix is prepended to object keys
+	Prefix string
+	// PartSize is the size of each part of a multipart upload; smaller objects are uploaded
+	// in a single request
+	PartSize int
+}
+
+// Enabled reports whether a bucket is configured
+func (c S3Config) Enabled() bool {
+	return c.Bucket != ""
+}
+
+// Vector index types
+const (
+	VectorIndexHNSW    = "hnsw"
+	VectorIndexIVFFlat = "ivfflat"
+)
+
+// VectorConfig holds settings for the pgvector index on article embeddings
+type VectorConfig struct {
+	// IndexType is the approximate nearest-neighbor index built on description_vector
+	IndexType string
+	// HNSWM and HNSWEfConstruction are HNSW build parameters; larger values improve recall at
+	// the cost of build time and index size
+	HNSWM              int
+	HNSWEfConstruction int
+	// IVFFlatLists is the number of IVFFlat lists
+	IVFFlatLists int
+	// EfSearch is the HNSW candidate list size per query, and Probes the number of IVFFlat
+	// lists scanned per query; higher values trade latency for recall
+	EfSearch int
+	Probes   int
+	// SearchLimit caps the nearest neighbors fetched per semantic search
+	SearchLimit int
+	// MinSimilarity is the cosine similarity below which semantic matches are dropped
+	MinSimilarity float64
+}
+
+// DedupeConfig holds settings for collapsing near-duplicate articles in responses
+type DedupeConfig struct {
+	// Similarity is the embedding cosine similarity above which two articles are duplicates;
+	// articles with matching normalized titles are duplicates regardless
+	Similarity float64
+	// Trending also collapses duplicates in trending responses
+	Trending bool
+	// TitleThreshold is the trigram similarity at which a loaded article's title marks it as a
+	// suspected duplicate of a stored article, when a load asks for duplicate detection
+	TitleThreshold float64
+}
+
+// ClusterConfig holds settings for grouping recent articles into story clusters
+type ClusterConfig struct {
+	// Interval is how often new articles are clustered; 0 disables the schedule
+	Interval time.Duration
+	// Window is how far back articles are clustered. A cluster takes new members while its
+	// newest member is inside the window and is deleted afterwards.
+	Window time.Duration
+	// Similarity is the embedding cosine similarity to a cluster's centroid at which an
+	// article joins the cluster
+	Similarity float64
+	// BatchSize caps the articles clustered per run; the rest wait for the next run
+	BatchSize int
+}
+
+// QueryLogConfig holds settings for logging natural-language queries
+type QueryLogConfig struct {
+	// Enabled records each /news/query with its result count and latency, identifying the
+	// client only by a salted hash of its user id or IP
+	Enabled bool
+	// SampleRate is the fraction of queries recorded
+	SampleRate float64
+	// Entries are written in the background through a queue of QueueSize, dropping entries
+	// while it is full, in batches of up to BatchSize at least every FlushInterval
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+	// Retention is how long entries are kept
+	Retention time.Duration
+	// RetentionInterval is how often old entries are deleted; 0 disables the schedule
+	RetentionInterval time.Duration
+	// HashSalt keys the client hash. When empty a random salt is drawn at startup, so hashes
+	// stop matching across restarts.
+	HashSalt string
+}
+
+// TrendingConfig holds settings for caching and precomputing trending articles
+type TrendingConfig struct {
+	// GeohashPrecision is the length of the geohash cells trending results are cached per:
+	// 4 is about 39x20 km, 5 about 5x5 km
+	GeohashPrecision int
+	// PrecomputeInterval is how often the most requested cells are refreshed; 0 disables it
+	PrecomputeInterval time.Duration
+	// PrecomputeCells is how many of the most requested cells each refresh covers
+	PrecomputeCells int
+	// PrecomputeWorkers caps the cells computed at once
+	PrecomputeWorkers int
+	// PrecomputeJitter is the longest random delay before each cell is computed, spreading
+	// the queries of a refresh and of instances refreshing at the same time
+	PrecomputeJitter time.Duration
+	// MaxArticleAge is how long after publication an article can trend; older articles are
+	// not scored whatever their traffic
+	MaxArticleAge time.Duration
+}
+
+// WebhookConfig holds settings for notifying downstream systems about new articles
+type WebhookConfig struct {
+	// Targets maps target names to URLs; an empty map disables webhooks
+	Targets map[string]string
+	// DisabledTargets names targets that are configured but receive no deliveries yet. They
+	// can still be exercised through the admin test-delivery endpoint.
+	DisabledTargets map[string]bool
+	// Secret signs every payload with HMAC-SHA256
+	Secret string
+	// MaxAttempts is how many times a delivery is tried before it is given up
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry; it doubles on each further retry
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+}
+
+// RequestTimeoutConfig holds per-route time budgets for request handling. Long-running
+// endpoints such as data loads are not subject to these budgets.
+type RequestTimeoutConfig struct {
+	Query    time.Duration
+	Trending time.Duration
+	Filter   time.Duration
+	// Default applies to the remaining short API endpoints (search, stats, interactions, ...)
+	Default time.Duration
+}
+
+// ConcurrencyLimitConfig caps concurrent requests to the expensive routes. A limit of 0
+// leaves the route unlimited.
+type ConcurrencyLimitConfig struct {
+	Query    int
+	Trending int
+	// MaxWait is how long a request waits for a slot before it is shed with 503
+	MaxWait time.Duration
 }
 
 // LLMConfig holds LLM API settings
 type LLMConfig struct {
+	// ChatProvider serves query analysis and summaries: openai or anthropic
+	ChatProvider string
+	// APIKey and APIURL configure the OpenAI (or OpenAI-compatible) API
 	APIKey string
 	APIURL string
+	// Anthropic Messages API settings, used when ChatProvider is anthropic
+	AnthropicAPIKey string
+	AnthropicAPIURL string
+	AnthropicModel  string
+	// EmbeddingProvider serves embeddings; only openai produces vectors matching the
+	// articles table. Its key and URL default to APIKey and APIURL.
+	EmbeddingProvider string
+	EmbeddingAPIKey   string
+	EmbeddingAPIURL   string
+	// EmbeddingModel must produce 1536-dimensional vectors. It is stored with every vector.
+	EmbeddingModel string
+	// JSONMode requests response_format json_object for query analysis; disable for
+	// OpenAI-compatible providers that don't support it
+	JSONMode bool
+	// DailyTokenBudget caps the tokens spent per UTC day; 0 means unlimited
+	DailyTokenBudget int64
+	// BreakerFailureThreshold consecutive failed calls open the circuit breaker, which then
+	// rejects calls for BreakerOpenDuration before probing the API again
+	BreakerFailureThreshold int
+	BreakerOpenDuration     time.Duration
+	// MaxInflight caps concurrent LLM API requests; bulk enrichment may use at most
+	// MaxInflightBulk of them so queries keep the rest. Requests wait up to MaxWait for a slot.
+	MaxInflight     int
+	MaxInflightBulk int
+	MaxWait         time.Duration
+	// HealthProbeInterval is how often the chat API's reachability is probed in the background;
+	// 0 disables the schedule. Each probe gives up after HealthProbeTimeout.
+	HealthProbeInterval time.Duration
+	HealthProbeTimeout  time.Duration
+	// AuditEnabled records query analysis calls (query, raw output, parsed analysis, latency,
+	// tokens) for offline evaluation. Queries are stored verbatim, so it is off by default.
+	// AuditSampleRate is the fraction of calls recorded; records are written in the background
+	// through a queue of AuditQueueSize, dropping records while it is full.
+	AuditEnabled    bool
+	AuditSampleRate float64
+	AuditQueueSize  int
+	// PromptMaxSources and PromptMaxCategories bound the allowed lists put into the query
+	// analysis prompt; 0 offers every entry. Longer lists are cut to the entries matching the
+	// query plus the PromptTopK with the most articles.
+	PromptMaxSources    int
+	PromptMaxCategories int
+	PromptTopK          int
+}
+
+// RetentionConfig holds settings for pruning old user events
+type RetentionConfig struct {
+	// EventsMaxAge is how long user events are kept
+	EventsMaxAge time.Duration
+	// Interval is how often the retention task runs; 0 disables the schedule
+	Interval  time.Duration
+	BatchSize int
+}
+
+// ArchiveConfig holds settings for moving old articles to the archive table
+type ArchiveConfig struct {
+	// MaxAge is how long after publication articles stay in the articles table
+	MaxAge time.Duration
+	// Interval is how often the archive job runs; 0 (the default) disables the schedule
+	Interval  time.Duration
+	BatchSize int
+	// DryRun makes scheduled runs only count the articles they would move
+	DryRun bool
+}
+
+// RelevanceConfig holds settings for recomputing article relevance scores from engagement
+type RelevanceConfig struct {
+	// Interval is how often scores are recomputed; 0 disables the schedule
+	Interval time.Duration
+	// Window is how far back user events count, and HalfLife how quickly their weight decays
+	Window   time.Duration
+	HalfLife time.Duration
+	// BlendFeedScore mixes the score supplied by the feed into the recomputed score, with
+	// weight FeedWeight; otherwise the score is engagement alone
+	BlendFeedScore bool
+	FeedWeight     float64
+}
+
+// QueryConfig holds settings for natural-language query processing
+type QueryConfig struct {
+	DefaultRadiusKm float64
+	// MinRadiusKm and MaxRadiusKm bound the radius the query analysis infers or the query
+	// states; DefaultRadiusKm applies when it has none
+	MinRadiusKm float64
+	MaxRadiusKm float64
+	// SoftMaxLength is the length in characters above which queries are truncated
+	SoftMaxLength int
+	// HardMaxLength is the length in characters above which queries are rejected
+	HardMaxLength int
+	// NoIntentLimit is how many of the latest articles a query without intents is answered from
+	NoIntentLimit int
+	// MinIntentConfidence maps intent types to the confidence an intent needs to become a
+	// filter; weaker intents are logged and ignored. Types not listed act at any confidence.
+	MinIntentConfidence map[string]float64
+	// SpellCorrection corrects misspelled query words against a dictionary built from the corpus
+	SpellCorrection bool
+	// SpellMaxDistance is the largest edit distance at which a word is corrected
+	SpellMaxDistance int
+	// SpellRefreshInterval is how often the dictionary is rebuilt; 0 builds it once
+	SpellRefreshInterval time.Duration
+	// SpellMinTermCount is how many titles a word must appear in to join the dictionary
+	SpellMinTermCount int
+	// SpellMaxTerms caps the number of title words in the dictionary, most frequent first
+	SpellMaxTerms int
+	// RelaxEmptyResults retries a query that matched nothing with progressively fewer constraints
+	RelaxEmptyResults bool
 }
 
 // CacheConfig holds cache settings
 type CacheConfig struct {
 	TTL time.Duration
+	// StatsTTL is how long per-article stats are cached; dashboards poll them frequently
+	StatsTTL time.Duration
+	// CorpusStatsTTL is how long the aggregate corpus stats are cached
+	CorpusStatsTTL time.Duration
+	// IdempotencyTTL is how long an interaction idempotency key is remembered
+	IdempotencyTTL time.Duration
+	// FilterTTL is how long filter endpoint results are cached; 0 disables the cache
+	FilterTTL time.Duration
+	// FeedTTL is how long rendered RSS feeds are cached, and their Cache-Control max-age
+	FeedTTL time.Duration
+	// QueryAnalysisTTL is how long LLM analyses of search queries are cached; 0 disables the cache
+	QueryAnalysisTTL time.Duration
+	// TopicsTTL is how long trending topics are cached; extracting them takes an LLM call
+	TopicsTTL time.Duration
+	// TrendingMaxAge and FilterMaxAge are the Cache-Control max-age sent to clients
+	TrendingMaxAge time.Duration
+	FilterMaxAge   time.Duration
+	// TrendingTimeout bounds each trending cache call, well inside the trending request budget.
+	// After TrendingFailureThreshold consecutive failures the cache is skipped for
+	// TrendingCooldown.
+	TrendingTimeout          time.Duration
+	TrendingFailureThreshold int
+	TrendingCooldown         time.Duration
 }
 
 // RedisConfig holds Redis connection settings
@@ -59,6 +420,128 @@ type RedisConfig struct {
 	MinIdleConns int
 }
 
+// EnrichConfig holds settings for LLM enrichment of articles (summaries and embeddings)
+type EnrichConfig struct {
+	Workers           int
+	BackfillBatchSize int
+	// Sentiment adds an LLM sentiment classification to enrichment; off by default to save tokens
+	Sentiment bool
+	// AutoCategorize lets articles be created without categories; the LLM picks them from
+	// CategoryTaxonomy, or from the stored categories when no taxonomy is configured
+	AutoCategorize   bool
+	CategoryTaxonomy []string
+	// DefaultCategory is assigned when automatic classification fails, and replaces unknown
+	// categories under the "bucket" UnknownCategoryPolicy
+	DefaultCategory string
+	// UnknownCategoryPolicy decides what happens to incoming categories missing from the
+	// categories table: "keep" stores them as they are, "reject" fails validation and "bucket"
+	// replaces them with DefaultCategory. Nothing is enforced while the table is empty.
+	UnknownCategoryPolicy string
+	// CategoryMergeBatchSize is how many articles one UPDATE rewrites when categories are
+	// renamed or merged
+	CategoryMergeBatchSize int
+	// LazySummaries generates missing summaries of query results while answering the query;
+	// at most LazySummaryWorkers calls run at once and each gives up after LazySummaryTimeout.
+	// The same bounds apply to summaries generated in a requested language.
+	LazySummaries      bool
+	LazySummaryWorkers int
+	LazySummaryTimeout time.Duration
+	// SummaryLang is the language code of stored summaries. Queries asking for another
+	// language get summaries generated in it, at most SummaryLangMaxGenerated per query, and
+	// cached for SummaryLangCacheTTL.
+	SummaryLang             string
+	SummaryLangMaxGenerated int
+	SummaryLangCacheTTL     time.Duration
+}
+
+// GeocodingConfig holds settings for resolving place names to coordinates
+type GeocodingConfig struct {
+	Provider string
+	APIKey   string
+	APIURL   string
+	CacheTTL time.Duration
+	Timeout  time.Duration
+}
+
+// SavedSearchConfig holds settings for saved searches and evaluating new articles against them
+type SavedSearchConfig struct {
+	// MaxPerUser caps the number of saved searches a user may have
+	MaxPerUser int
+	// MinSimilarity is the cosine similarity above which an article matches a search's query
+	MinSimilarity float64
+	// BatchSize and FlushInterval bound how many new articles are evaluated together and how
+	// long an article waits for its batch to fill
+	BatchSize     int
+	FlushInterval time.Duration
+	// QueueSize is the number of new articles that may wait for evaluation; beyond it
+	// articles are not evaluated
+	QueueSize int
+}
+
+// ContentConfig holds settings for fetching article pages to enrich from their full text
+type ContentConfig struct {
+	// Enabled fetches each loaded article's URL and stores the extracted text; off by default
+	Enabled   bool
+	UserAgent string
+	Timeout   time.Duration
+	// MaxBodyBytes caps the size of a fetched page; larger pages are not extracted
+	MaxBodyBytes int64
+	// DomainInterval is the minimum time between two requests to the same host
+	DomainInterval time.Duration
+	// MaxChars caps the content passed to summary and embedding generation
+	MaxChars int
+}
+
+// GeoIPConfig holds settings for locating clients that send no coordinates
+type GeoIPConfig struct {
+	// DatabasePath is a MaxMind GeoLite2 (or GeoIP2) City database; IP lookups are off when
+	// it is empty or missing
+	DatabasePath string
+	// DefaultLatitude and DefaultLongitude are used when the client IP cannot be located
+	DefaultLatitude  float64
+	DefaultLongitude float64
+}
+
+// ExperimentConfig holds the A/B experiments requests are assigned to
+type ExperimentConfig struct {
+	// Definitions maps experiment names to their variants and traffic weights, such as
+	// "control:50|engagement:50" (see ParseExperimentVariants)
+	Definitions map[string]string
+	// Disabled lists experiments that send every request to their control variant
+	Disabled map[string]bool
+}
+
+// TenantConfig holds the tenants sharing the deployment. Each tenant has its own articles and
+// user events; requests are scoped to one tenant (see TenantResolver).
+type TenantConfig struct {
+	// Default is the tenant of requests that select none, and of rows stored before tenants
+	// were introduced
+	Default string
+	// APIKeys maps tenants to the API key selecting them. A tenant with a key is only reachable
+	// with that key.
+	APIKeys map[string]string
+	// Open lists the tenants besides Default that may be selected by X-Tenant-ID without a key
+	Open map[string]bool
+}
+
+// IDs returns every configured tenant, sorted
+func (c TenantConfig) IDs() []string {
+	seen := map[string]bool{c.Default: true}
+	for tenant := range c.APIKeys {
+		seen[tenant] = true
+	}
+	for tenant := range c.Open {
+		seen[tenant] = true
+	}
+
+	ids := make([]string, 0, len(seen))
+	for tenant := range seen {
+		ids = append(ids, tenant)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // LogConfig holds logging settings
 type LogConfig struct {
 	Level string
@@ -71,25 +554,95 @@ func Load() (*Config, error) {
 	// This allows the app to work with just environment variables
 	_ = godotenv.Load()
 
+	geocoderProvider := getEnv("GEOCODER_PROVIDER", "nominatim")
+	llmAPIKey := getEnv("LLM_API_KEY", "")
+	llmAPIURL := getEnv("LLM_API_URL", "https://api.openai.com/v1")
+
 	cfg := &Config{
 		Database: DatabaseConfig{
-			URL:             getEnv("DATABASE_URL", ""),
-			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
-			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
-			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
-			ConnMaxIdleTime: getEnvAsDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			URL:                getEnv("DATABASE_URL", ""),
+			MaxOpenConns:       getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:       getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime:    getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+			ConnMaxIdleTime:    getEnvAsDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			SlowQueryThreshold: getEnvAsDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			LogLevel:           strings.ToLower(getEnv("DB_LOG_LEVEL", DBLogLevelWarn)),
+			LogParams:          getEnvAsBool("DB_LOG_PARAMS", false),
 		},
 		Server: ServerConfig{
 			Port:         getEnv("PORT", "8080"),
 			ReadTimeout:  getEnvAsDuration("SERVER_READ_TIMEOUT", 10*time.Second),
 			WriteTimeout: getEnvAsDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
+			AdminAPIKey:  getEnv("ADMIN_API_KEY", ""),
+			JobRetention: getEnvAsDuration("JOB_RETENTION", time.Hour),
+			Timeouts: RequestTimeoutConfig{
+				Query:    getEnvAsDuration("REQUEST_TIMEOUT_QUERY", 20*time.Second),
+				Trending: getEnvAsDuration("REQUEST_TIMEOUT_TRENDING", 5*time.Second),
+				Filter:   getEnvAsDuration("REQUEST_TIMEOUT_FILTER", 5*time.Second),
+				Default:  getEnvAsDuration("REQUEST_TIMEOUT_DEFAULT", 10*time.Second),
+			},
+			Concurrency: ConcurrencyLimitConfig{
+				Query:    getEnvAsInt("CONCURRENCY_LIMIT_QUERY", 20),
+				Trending: getEnvAsInt("CONCURRENCY_LIMIT_TRENDING", 50),
+				MaxWait:  getEnvAsDuration("CONCURRENCY_MAX_WAIT", 500*time.Millisecond),
+			},
+			CompressLevel: getEnvAsInt("COMPRESS_LEVEL", 0),
+			BodyLimit:     getEnvAsInt("BODY_LIMIT", 1024*1024),
+			LoadBodyLimit: getEnvAsInt("LOAD_BODY_LIMIT", 50*1024*1024),
+			StrictJSON:    getEnvAsBool("STRICT_JSON", true),
+		},
+		GRPC: GRPCConfig{
+			Port:            getEnv("GRPC_PORT", "9090"),
+			Reflection:      getEnvAsBool("GRPC_REFLECTION", false),
+			ShutdownTimeout: getEnvAsDuration("GRPC_SHUTDOWN_TIMEOUT", 10*time.Second),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnv("CORS_ALLOWED_ORIGINS", "*"),
+			AllowedMethods:   getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
+			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", false),
 		},
 		LLM: LLMConfig{
-			APIKey: getEnv("LLM_API_KEY", ""),
-			APIURL: getEnv("LLM_API_URL", "https://api.openai.com/v1"),
+			ChatProvider:            getEnv("CHAT_PROVIDER", "openai"),
+			APIKey:                  llmAPIKey,
+			APIURL:                  llmAPIURL,
+			AnthropicAPIKey:         getEnv("ANTHROPIC_API_KEY", ""),
+			AnthropicAPIURL:         getEnv("ANTHROPIC_API_URL", "https://api.anthropic.com"),
+			AnthropicModel:          getEnv("ANTHROPIC_MODEL", "claude-3-5-haiku-latest"),
+			EmbeddingProvider:       getEnv("EMBEDDING_PROVIDER", "openai"),
+			EmbeddingAPIKey:         getEnv("EMBEDDING_API_KEY", llmAPIKey),
+			EmbeddingAPIURL:         getEnv("EMBEDDING_API_URL", llmAPIURL),
+			EmbeddingModel:          getEnv("EMBEDDING_MODEL", "text-embedding-3-small"),
+			JSONMode:                getEnvAsBool("LLM_JSON_MODE", true),
+			DailyTokenBudget:        int64(getEnvAsInt("LLM_DAILY_TOKEN_BUDGET", 0)),
+			BreakerFailureThreshold: getEnvAsInt("LLM_BREAKER_FAILURE_THRESHOLD", 5),
+			BreakerOpenDuration:     getEnvAsDuration("LLM_BREAKER_OPEN_DURATION", 30*time.Second),
+			MaxInflight:             getEnvAsInt("LLM_MAX_INFLIGHT", 16),
+			MaxInflightBulk:         getEnvAsInt("LLM_MAX_INFLIGHT_BULK", 8),
+			HealthProbeInterval:     getEnvAsDuration("LLM_HEALTH_PROBE_INTERVAL", 5*time.Minute),
+			HealthProbeTimeout:      getEnvAsDuration("LLM_HEALTH_PROBE_TIMEOUT", 3*time.Second),
+			MaxWait:                 getEnvAsDuration("LLM_MAX_WAIT", 10*time.Second),
+			AuditEnabled:            getEnvAsBool("LLM_AUDIT_ENABLED", false),
+			AuditSampleRate:         getEnvAsFloat("LLM_AUDIT_SAMPLE_RATE", 1.0),
+			AuditQueueSize:          getEnvAsInt("LLM_AUDIT_QUEUE_SIZE", 1000),
+			PromptMaxSources:        getEnvAsInt("LLM_PROMPT_MAX_SOURCES", 100),
+			PromptMaxCategories:     getEnvAsInt("LLM_PROMPT_MAX_CATEGORIES", 100),
+			PromptTopK:              getEnvAsInt("LLM_PROMPT_TOP_K", 20),
 		},
 		Cache: CacheConfig{
-			TTL: getEnvAsDuration("CACHE_TTL", 5*time.Minute),
+			TTL:              getEnvAsDuration("CACHE_TTL", 5*time.Minute),
+			StatsTTL:         getEnvAsDuration("STATS_CACHE_TTL", time.Minute),
+			CorpusStatsTTL:   getEnvAsDuration("CORPUS_STATS_CACHE_TTL", 5*time.Minute),
+			IdempotencyTTL:   getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+			FilterTTL:        getEnvAsDuration("FILTER_CACHE_TTL", 30*time.Second),
+			FeedTTL:          getEnvAsDuration("FEED_CACHE_TTL", time.Minute),
+			QueryAnalysisTTL: getEnvAsDuration("QUERY_ANALYSIS_CACHE_TTL", time.Hour),
+			TopicsTTL:        getEnvAsDuration("TRENDING_TOPICS_CACHE_TTL", 10*time.Minute),
+			TrendingMaxAge:   getEnvAsDuration("HTTP_CACHE_MAX_AGE_TRENDING", 30*time.Second),
+			FilterMaxAge:     getEnvAsDuration("HTTP_CACHE_MAX_AGE_FILTER", 10*time.Second),
+
+			TrendingTimeout:          getEnvAsDuration("TRENDING_CACHE_TIMEOUT", 100*time.Millisecond),
+			TrendingFailureThreshold: getEnvAsInt("TRENDING_CACHE_FAILURE_THRESHOLD", 3),
+			TrendingCooldown:         getEnvAsDuration("TRENDING_CACHE_COOLDOWN", 30*time.Second),
 		},
 		Redis: RedisConfig{
 			Host:         getEnv("REDIS_HOST", "localhost"),
@@ -105,16 +658,177 @@ func Load() (*Config, error) {
 		Log: LogConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
+		Enrich: EnrichConfig{
+			Workers:                 getEnvAsInt("ENRICH_WORKERS", 8),
+			BackfillBatchSize:       getEnvAsInt("BACKFILL_BATCH_SIZE", 100),
+			Sentiment:               getEnvAsBool("ENRICH_SENTIMENT", false),
+			AutoCategorize:          getEnvAsBool("ENRICH_AUTO_CATEGORIZE", false),
+			CategoryTaxonomy:        getEnvAsList("CATEGORY_TAXONOMY"),
+			DefaultCategory:         getEnv("ENRICH_DEFAULT_CATEGORY", "general"),
+			UnknownCategoryPolicy:   strings.ToLower(getEnv("CATEGORY_UNKNOWN_POLICY", "keep")),
+			CategoryMergeBatchSize:  getEnvAsInt("CATEGORY_MERGE_BATCH_SIZE", 1000),
+			LazySummaries:           getEnvAsBool("ENRICH_LAZY_SUMMARIES", false),
+			LazySummaryWorkers:      getEnvAsInt("ENRICH_LAZY_SUMMARY_WORKERS", 4),
+			LazySummaryTimeout:      getEnvAsDuration("ENRICH_LAZY_SUMMARY_TIMEOUT", 3*time.Second),
+			SummaryLang:             strings.ToLower(getEnv("SUMMARY_DEFAULT_LANG", "en")),
+			SummaryLangMaxGenerated: getEnvAsInt("SUMMARY_LANG_MAX_GENERATED", 5),
+			SummaryLangCacheTTL:     getEnvAsDuration("SUMMARY_LANG_CACHE_TTL", 7*24*time.Hour),
+		},
+		Query: QueryConfig{
+			DefaultRadiusKm:      getEnvAsFloat("QUERY_DEFAULT_RADIUS_KM", 50),
+			MinRadiusKm:          getEnvAsFloat("QUERY_MIN_RADIUS_KM", 5),
+			MaxRadiusKm:          getEnvAsFloat("QUERY_MAX_RADIUS_KM", 500),
+			SoftMaxLength:        getEnvAsInt("QUERY_SOFT_MAX_LENGTH", 300),
+			HardMaxLength:        getEnvAsInt("QUERY_HARD_MAX_LENGTH", 1000),
+			NoIntentLimit:        getEnvAsInt("QUERY_NO_INTENT_LIMIT", 200),
+			MinIntentConfidence:  getEnvAsFloatMap("QUERY_MIN_INTENT_CONFIDENCE"),
+			SpellCorrection:      getEnvAsBool("QUERY_SPELL_CORRECTION", true),
+			SpellMaxDistance:     getEnvAsInt("QUERY_SPELL_MAX_DISTANCE", 2),
+			SpellRefreshInterval: getEnvAsDuration("QUERY_SPELL_REFRESH_INTERVAL", 6*time.Hour),
+			SpellMinTermCount:    getEnvAsInt("QUERY_SPELL_MIN_TERM_COUNT", 3),
+			SpellMaxTerms:        getEnvAsInt("QUERY_SPELL_MAX_TERMS", 20000),
+			RelaxEmptyResults:    getEnvAsBool("QUERY_RELAX_EMPTY_RESULTS", true),
+		},
+		Retention: RetentionConfig{
+			EventsMaxAge: getEnvAsDuration("EVENTS_RETENTION", 90*24*time.Hour),
+			Interval:     getEnvAsDuration("EVENTS_RETENTION_INTERVAL", 24*time.Hour),
+			BatchSize:    getEnvAsInt("EVENTS_RETENTION_BATCH_SIZE", 10000),
+		},
+		Archive: ArchiveConfig{
+			MaxAge:    getEnvAsDuration("ARTICLES_ARCHIVE_AFTER", 60*24*time.Hour),
+			Interval:  getEnvAsDuration("ARTICLES_ARCHIVE_INTERVAL", 0),
+			BatchSize: getEnvAsInt("ARTICLES_ARCHIVE_BATCH_SIZE", 1000),
+			DryRun:    getEnvAsBool("ARTICLES_ARCHIVE_DRY_RUN", false),
+		},
+		Relevance: RelevanceConfig{
+			Interval:       getEnvAsDuration("RELEVANCE_RESCORE_INTERVAL", time.Hour),
+			Window:         getEnvAsDuration("RELEVANCE_EVENT_WINDOW", 7*24*time.Hour),
+			HalfLife:       getEnvAsDuration("RELEVANCE_HALF_LIFE", 24*time.Hour),
+			BlendFeedScore: getEnvAsBool("RELEVANCE_BLEND_FEED_SCORE", true),
+			FeedWeight:     getEnvAsFloat("RELEVANCE_FEED_WEIGHT", 0.5),
+		},
+		Export: ExportConfig{
+			MaxRows: getEnvAsInt("EXPORT_MAX_ROWS", 100000),
+			S3: S3Config{
+				Endpoint:        getEnv("EXPORT_S3_ENDPOINT", "https://s3.amazonaws.com"),
+				Region:          getEnv("EXPORT_S3_REGION", "us-east-1"),
+				Bucket:          getEnv("EXPORT_S3_BUCKET", ""),
+				AccessKeyID:     getEnv("EXPORT_S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("EXPORT_S3_SECRET_ACCESS_KEY", ""),
+				PathStyle:       getEnvAsBool("EXPORT_S3_PATH_STYLE", true),
+				Prefix:          getEnv("EXPORT_S3_PREFIX", "snapshots/"),
+				PartSize:        getEnvAsInt("EXPORT_S3_PART_SIZE", 16*1024*1024),
+			},
+			SnapshotInterval:       getEnvAsDuration("EXPORT_SNAPSHOT_INTERVAL", 24*time.Hour),
+			SnapshotMode:           strings.ToLower(getEnv("EXPORT_SNAPSHOT_MODE", "incremental")),
+			SnapshotIncludeVectors: getEnvAsBool("EXPORT_SNAPSHOT_INCLUDE_VECTORS", false),
+		},
+		Vector: VectorConfig{
+			IndexType:          strings.ToLower(getEnv("VECTOR_INDEX_TYPE", VectorIndexHNSW)),
+			HNSWM:              getEnvAsInt("VECTOR_HNSW_M", 16),
+			HNSWEfConstruction: getEnvAsInt("VECTOR_HNSW_EF_CONSTRUCTION", 64),
+			IVFFlatLists:       getEnvAsInt("VECTOR_IVFFLAT_LISTS", 100),
+			EfSearch:           getEnvAsInt("VECTOR_HNSW_EF_SEARCH", 40),
+			Probes:             getEnvAsInt("VECTOR_IVFFLAT_PROBES", 1),
+			SearchLimit:        getEnvAsInt("VECTOR_SEARCH_LIMIT", 200),
+			MinSimilarity:      getEnvAsFloat("VECTOR_MIN_SIMILARITY", 0.25),
+		},
+		Dedupe: DedupeConfig{
+			Similarity:     getEnvAsFloat("DEDUPE_SIMILARITY", 0.92),
+			Trending:       getEnvAsBool("DEDUPE_TRENDING", false),
+			TitleThreshold: getEnvAsFloat("DEDUPE_TITLE_THRESHOLD", 0.6),
+		},
+		Cluster: ClusterConfig{
+			Interval:   getEnvAsDuration("CLUSTER_INTERVAL", 15*time.Minute),
+			Window:     getEnvAsDuration("CLUSTER_WINDOW", 48*time.Hour),
+			Similarity: getEnvAsFloat("CLUSTER_SIMILARITY", 0.8),
+			BatchSize:  getEnvAsInt("CLUSTER_BATCH_SIZE", 2000),
+		},
+		QueryLog: QueryLogConfig{
+			Enabled:           getEnvAsBool("QUERY_LOG_ENABLED", true),
+			SampleRate:        getEnvAsFloat("QUERY_LOG_SAMPLE_RATE", 1.0),
+			QueueSize:         getEnvAsInt("QUERY_LOG_QUEUE_SIZE", 10000),
+			BatchSize:         getEnvAsInt("QUERY_LOG_BATCH_SIZE", 500),
+			FlushInterval:     getEnvAsDuration("QUERY_LOG_FLUSH_INTERVAL", 5*time.Second),
+			Retention:         getEnvAsDuration("QUERY_LOG_RETENTION", 30*24*time.Hour),
+			RetentionInterval: getEnvAsDuration("QUERY_LOG_RETENTION_INTERVAL", 24*time.Hour),
+			HashSalt:          getEnv("QUERY_LOG_HASH_SALT", ""),
+		},
+		Trending: TrendingConfig{
+			GeohashPrecision:   getEnvAsInt("TRENDING_GEOHASH_PRECISION", 4),
+			PrecomputeInterval: getEnvAsDuration("TRENDING_PRECOMPUTE_INTERVAL", 4*time.Minute),
+			PrecomputeCells:    getEnvAsInt("TRENDING_PRECOMPUTE_CELLS", 20),
+			PrecomputeWorkers:  getEnvAsInt("TRENDING_PRECOMPUTE_WORKERS", 2),
+			PrecomputeJitter:   getEnvAsDuration("TRENDING_PRECOMPUTE_JITTER", 30*time.Second),
+			MaxArticleAge:      getEnvAsDuration("TRENDING_MAX_ARTICLE_AGE", 14*24*time.Hour),
+		},
+		Experiment: ExperimentConfig{
+			Definitions: getEnvAsMap("EXPERIMENTS"),
+			Disabled:    getEnvAsSet("EXPERIMENTS_DISABLED"),
+		},
+		Tenant: TenantConfig{
+			Default: getEnv("TENANT_DEFAULT", DefaultTenant),
+			APIKeys: getEnvAsMap("TENANT_API_KEYS"),
+			Open:    getEnvAsSet("TENANT_IDS"),
+		},
+		Webhook: WebhookConfig{
+			Targets:         getEnvAsMap("WEBHOOK_TARGETS"),
+			DisabledTargets: getEnvAsSet("WEBHOOK_DISABLED_TARGETS"),
+			Secret:          getEnv("WEBHOOK_SECRET", ""),
+			MaxAttempts:     getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 5),
+			RetryBackoff:    getEnvAsDuration("WEBHOOK_RETRY_BACKOFF", time.Second),
+			Timeout:         getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		},
+		Geocoder: GeocodingConfig{
+			Provider: geocoderProvider,
+			APIKey:   getEnv("GEOCODER_API_KEY", ""),
+			APIURL:   getEnv("GEOCODER_API_URL", defaultGeocoderURL(geocoderProvider)),
+			CacheTTL: getEnvAsDuration("GEOCODER_CACHE_TTL", 30*24*time.Hour),
+			Timeout:  getEnvAsDuration("GEOCODER_TIMEOUT", 5*time.Second),
+		},
+		Searches: SavedSearchConfig{
+			MaxPerUser:    getEnvAsInt("SAVED_SEARCH_MAX_PER_USER", 50),
+			MinSimilarity: getEnvAsFloat("SAVED_SEARCH_MIN_SIMILARITY", 0.5),
+			BatchSize:     getEnvAsInt("SAVED_SEARCH_BATCH_SIZE", 200),
+			FlushInterval: getEnvAsDuration("SAVED_SEARCH_FLUSH_INTERVAL", 2*time.Second),
+			QueueSize:     getEnvAsInt("SAVED_SEARCH_QUEUE_SIZE", 10000),
+		},
+		Content: ContentConfig{
+			Enabled:        getEnvAsBool("ENRICH_FETCH_CONTENT", false),
+			UserAgent:      getEnv("CONTENT_FETCH_USER_AGENT", "news-inshorts-bot/1.0"),
+			Timeout:        getEnvAsDuration("CONTENT_FETCH_TIMEOUT", 10*time.Second),
+			MaxBodyBytes:   int64(getEnvAsInt("CONTENT_FETCH_MAX_BYTES", 2<<20)),
+			DomainInterval: getEnvAsDuration("CONTENT_FETCH_DOMAIN_INTERVAL", time.Second),
+			MaxChars:       getEnvAsInt("ENRICH_CONTENT_MAX_CHARS", 4000),
+		},
+		// The default location is the centroid of India
+		GeoIP: GeoIPConfig{
+			DatabasePath:     getEnv("GEOIP_DB_PATH", ""),
+			DefaultLatitude:  getEnvAsFloat("DEFAULT_LOCATION_LAT", 20.5937),
+			DefaultLongitude: getEnvAsFloat("DEFAULT_LOCATION_LON", 78.9629),
+		},
 	}
 
-	// Validate configuration
+	// Validate configuration. Errors may quote configured values, so secrets are masked.
 	if err := cfg.Validate(); err != nil {
-		return nil, err
+		return nil, RedactError(err)
 	}
 
 	return cfg, nil
 }
 
+// defaultGeocoderURL returns the public API base URL of a geocoding provider
+func defaultGeocoderURL(provider string) string {
+	switch provider {
+	case "opencage":
+		return "https://api.opencagedata.com"
+	case "nominatim":
+		return "https://nominatim.openstreetmap.org"
+	default:
+		return ""
+	}
+}
+
 // getEnv retrieves an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -136,6 +850,83 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsFloat retrieves an environment variable as a float or returns a default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsBool retrieves an environment variable as a boolean or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsMap retrieves an environment variable holding comma-separated name=value pairs.
+// Entries without a name or value are ignored.
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		name, value, found := strings.Cut(entry, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !found || name == "" || value == "" {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// getEnvAsFloatMap retrieves an environment variable holding comma-separated name=number
+// pairs. Values that are not numbers are kept as NaN so validation can reject them.
+func getEnvAsFloatMap(key string) map[string]float64 {
+	result := make(map[string]float64)
+	for name, value := range getEnvAsMap(key) {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			number = math.NaN()
+		}
+		result[name] = number
+	}
+	return result
+}
+
+// getEnvAsList retrieves an environment variable holding a comma-separated list, keeping order
+func getEnvAsList(key string) []string {
+	result := []string{}
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvAsSet retrieves an environment variable holding a comma-separated list as a set
+func getEnvAsSet(key string) map[string]bool {
+	result := make(map[string]bool)
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result[item] = true
+		}
+	}
+	return result
+}
+
 // getEnvAsDuration retrieves an environment variable as a duration or returns a default value
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := os.Getenv(key)
@@ -156,12 +947,88 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
 
-	if c.LLM.APIKey == "" {
-		return fmt.Errorf("LLM_API_KEY is required")
+	// Validate LLM providers and the credentials they need
+	switch c.LLM.ChatProvider {
+	case "openai":
+		if c.LLM.APIKey == "" {
+			return fmt.Errorf("LLM_API_KEY is required when CHAT_PROVIDER is openai")
+		}
+		if c.LLM.APIURL == "" {
+			return fmt.Errorf("LLM_API_URL is required when CHAT_PROVIDER is openai")
+		}
+	case "anthropic":
+		if c.LLM.AnthropicAPIKey == "" {
+			return fmt.Errorf("ANTHROPIC_API_KEY is required when CHAT_PROVIDER is anthropic")
+		}
+		if c.LLM.AnthropicAPIURL == "" || c.LLM.AnthropicModel == "" {
+			return fmt.Errorf("ANTHROPIC_API_URL and ANTHROPIC_MODEL are required when CHAT_PROVIDER is anthropic")
+		}
+	default:
+		return fmt.Errorf("CHAT_PROVIDER must be one of: openai, anthropic")
+	}
+
+	if c.LLM.EmbeddingProvider != "openai" {
+		return fmt.Errorf("EMBEDDING_PROVIDER must be openai: article vectors are stored with 1536 dimensions")
+	}
+
+	if c.LLM.EmbeddingAPIKey == "" {
+		return fmt.Errorf("EMBEDDING_API_KEY or LLM_API_KEY is required for embeddings")
+	}
+
+	if c.LLM.EmbeddingAPIURL == "" {
+		return fmt.Errorf("EMBEDDING_API_URL or LLM_API_URL is required for embeddings")
+	}
+
+	if c.LLM.EmbeddingModel == "" {
+		return fmt.Errorf("EMBEDDING_MODEL is required")
+	}
+
+	if c.LLM.DailyTokenBudget < 0 {
+		return fmt.Errorf("LLM_DAILY_TOKEN_BUDGET must not be negative")
+	}
+
+	if c.LLM.BreakerFailureThreshold <= 0 {
+		return fmt.Errorf("LLM_BREAKER_FAILURE_THRESHOLD must be greater than 0")
+	}
+
+	if c.LLM.BreakerOpenDuration <= 0 {
+		return fmt.Errorf("LLM_BREAKER_OPEN_DURATION must be greater than 0")
+	}
+
+	if c.LLM.HealthProbeInterval < 0 {
+		return fmt.Errorf("LLM_HEALTH_PROBE_INTERVAL must not be negative")
+	}
+
+	if c.LLM.HealthProbeTimeout <= 0 {
+		return fmt.Errorf("LLM_HEALTH_PROBE_TIMEOUT must be greater than 0")
 	}
 
-	if c.LLM.APIURL == "" {
-		return fmt.Errorf("LLM_API_URL is required")
+	if c.LLM.MaxInflight <= 0 {
+		return fmt.Errorf("LLM_MAX_INFLIGHT must be greater than 0")
+	}
+
+	if c.LLM.MaxInflightBulk <= 0 || c.LLM.MaxInflightBulk > c.LLM.MaxInflight {
+		return fmt.Errorf("LLM_MAX_INFLIGHT_BULK must be between 1 and LLM_MAX_INFLIGHT")
+	}
+
+	if c.LLM.MaxWait <= 0 {
+		return fmt.Errorf("LLM_MAX_WAIT must be greater than 0")
+	}
+
+	if c.LLM.AuditSampleRate < 0 || c.LLM.AuditSampleRate > 1 {
+		return fmt.Errorf("LLM_AUDIT_SAMPLE_RATE must be between 0 and 1")
+	}
+
+	if c.LLM.AuditQueueSize <= 0 {
+		return fmt.Errorf("LLM_AUDIT_QUEUE_SIZE must be greater than 0")
+	}
+
+	if c.LLM.PromptMaxSources < 0 || c.LLM.PromptMaxCategories < 0 {
+		return fmt.Errorf("LLM_PROMPT_MAX_SOURCES and LLM_PROMPT_MAX_CATEGORIES must not be negative")
+	}
+
+	if c.LLM.PromptTopK < 0 {
+		return fmt.Errorf("LLM_PROMPT_TOP_K must not be negative")
 	}
 
 	// Validate database connection pool settings
@@ -177,11 +1044,59 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("DB_MAX_IDLE_CONNS cannot be greater than DB_MAX_OPEN_CONNS")
 	}
 
+	if c.Database.SlowQueryThreshold < 0 {
+		return fmt.Errorf("DB_SLOW_QUERY_THRESHOLD must be greater than or equal to 0")
+	}
+
+	switch c.Database.LogLevel {
+	case DBLogLevelSilent, DBLogLevelError, DBLogLevelWarn, DBLogLevelInfo:
+	default:
+		return fmt.Errorf("DB_LOG_LEVEL must be one of: silent, error, warn, info")
+	}
+
 	// Validate server settings
 	if c.Server.Port == "" {
 		return fmt.Errorf("PORT is required")
 	}
 
+	if c.Server.CompressLevel < -1 || c.Server.CompressLevel > 2 {
+		return fmt.Errorf("COMPRESS_LEVEL must be between -1 and 2")
+	}
+
+	if c.Server.BodyLimit <= 0 || c.Server.LoadBodyLimit <= 0 {
+		return fmt.Errorf("BODY_LIMIT and LOAD_BODY_LIMIT must be greater than 0")
+	}
+
+	if c.Server.Concurrency.Query < 0 || c.Server.Concurrency.Trending < 0 {
+		return fmt.Errorf("CONCURRENCY_LIMIT_QUERY and CONCURRENCY_LIMIT_TRENDING must not be negative")
+	}
+
+	if c.Server.JobRetention <= 0 {
+		return fmt.Errorf("JOB_RETENTION must be greater than 0")
+	}
+
+	if c.Server.Concurrency.MaxWait < 0 {
+		return fmt.Errorf("CONCURRENCY_MAX_WAIT must not be negative")
+	}
+
+	// Validate gRPC settings
+	if c.GRPC.Port != "" && c.GRPC.Port == c.Server.Port {
+		return fmt.Errorf("GRPC_PORT must differ from PORT")
+	}
+
+	if c.GRPC.ShutdownTimeout <= 0 {
+		return fmt.Errorf("GRPC_SHUTDOWN_TIMEOUT must be greater than 0")
+	}
+
+	// Validate CORS settings
+	if c.CORS.AllowedOrigins == "" {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS is required")
+	}
+
+	if c.CORS.AllowCredentials && strings.Contains(c.CORS.AllowedOrigins, "*") {
+		return fmt.Errorf("CORS_ALLOW_CREDENTIALS cannot be enabled when CORS_ALLOWED_ORIGINS is \"*\"")
+	}
+
 	// Validate log level
 	validLogLevels := map[string]bool{
 		"debug": true,
@@ -198,5 +1113,453 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("CACHE_TTL must be greater than 0")
 	}
 
+	if c.Cache.StatsTTL <= 0 {
+		return fmt.Errorf("STATS_CACHE_TTL must be greater than 0")
+	}
+
+	if c.Cache.CorpusStatsTTL <= 0 {
+		return fmt.Errorf("CORPUS_STATS_CACHE_TTL must be greater than 0")
+	}
+
+	if c.Cache.IdempotencyTTL <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL must be greater than 0")
+	}
+
+	if c.Cache.FilterTTL < 0 {
+		return fmt.Errorf("FILTER_CACHE_TTL must not be negative")
+	}
+
+	if c.Cache.QueryAnalysisTTL < 0 {
+		return fmt.Errorf("QUERY_ANALYSIS_CACHE_TTL must not be negative")
+	}
+
+	if c.Cache.FeedTTL <= 0 {
+		return fmt.Errorf("FEED_CACHE_TTL must be greater than 0")
+	}
+
+	if c.Cache.TopicsTTL <= 0 {
+		return fmt.Errorf("TRENDING_TOPICS_CACHE_TTL must be greater than 0")
+	}
+
+	if c.Cache.TrendingMaxAge < 0 || c.Cache.FilterMaxAge < 0 {
+		return fmt.Errorf("HTTP_CACHE_MAX_AGE_TRENDING and HTTP_CACHE_MAX_AGE_FILTER must not be negative")
+	}
+
+	if c.Cache.TrendingTimeout <= 0 {
+		return fmt.Errorf("TRENDING_CACHE_TIMEOUT must be greater than 0")
+	}
+
+	// Cache calls are made twice per request (read and write), both inside its budget
+	if c.Server.Timeouts.Trending > 0 && 2*c.Cache.TrendingTimeout >= c.Server.Timeouts.Trending {
+		return fmt.Errorf("TRENDING_CACHE_TIMEOUT must be less than half of REQUEST_TIMEOUT_TRENDING")
+	}
+
+	if c.Cache.TrendingFailureThreshold <= 0 {
+		return fmt.Errorf("TRENDING_CACHE_FAILURE_THRESHOLD must be greater than 0")
+	}
+
+	if c.Cache.TrendingCooldown <= 0 {
+		return fmt.Errorf("TRENDING_CACHE_COOLDOWN must be greater than 0")
+	}
+
+	// Validate enrichment settings
+	if c.Enrich.Workers <= 0 {
+		return fmt.Errorf("ENRICH_WORKERS must be greater than 0")
+	}
+
+	if c.Enrich.BackfillBatchSize <= 0 {
+		return fmt.Errorf("BACKFILL_BATCH_SIZE must be greater than 0")
+	}
+
+	if c.Enrich.AutoCategorize && strings.TrimSpace(c.Enrich.DefaultCategory) == "" {
+		return fmt.Errorf("ENRICH_DEFAULT_CATEGORY is required when ENRICH_AUTO_CATEGORIZE is enabled")
+	}
+
+	switch c.Enrich.UnknownCategoryPolicy {
+	case "keep", "reject":
+	case "bucket":
+		if strings.TrimSpace(c.Enrich.DefaultCategory) == "" {
+			return fmt.Errorf("ENRICH_DEFAULT_CATEGORY is required when CATEGORY_UNKNOWN_POLICY is bucket")
+		}
+	default:
+		return fmt.Errorf("CATEGORY_UNKNOWN_POLICY must be one of: keep, reject, bucket")
+	}
+
+	if c.Enrich.CategoryMergeBatchSize <= 0 {
+		return fmt.Errorf("CATEGORY_MERGE_BATCH_SIZE must be greater than 0")
+	}
+
+	if c.Enrich.LazySummaryWorkers <= 0 {
+		return fmt.Errorf("ENRICH_LAZY_SUMMARY_WORKERS must be greater than 0")
+	}
+
+	if c.Enrich.LazySummaryTimeout <= 0 {
+		return fmt.Errorf("ENRICH_LAZY_SUMMARY_TIMEOUT must be greater than 0")
+	}
+
+	if strings.TrimSpace(c.Enrich.SummaryLang) == "" {
+		return fmt.Errorf("SUMMARY_DEFAULT_LANG is required")
+	}
+
+	if c.Enrich.SummaryLangMaxGenerated < 0 {
+		return fmt.Errorf("SUMMARY_LANG_MAX_GENERATED must not be negative")
+	}
+
+	if c.Enrich.SummaryLangCacheTTL <= 0 {
+		return fmt.Errorf("SUMMARY_LANG_CACHE_TTL must be greater than 0")
+	}
+
+	// Validate query settings
+	if c.Query.DefaultRadiusKm <= 0 {
+		return fmt.Errorf("QUERY_DEFAULT_RADIUS_KM must be greater than 0")
+	}
+
+	if c.Query.MinRadiusKm <= 0 || c.Query.MaxRadiusKm < c.Query.MinRadiusKm {
+		return fmt.Errorf("QUERY_MIN_RADIUS_KM must be greater than 0 and at most QUERY_MAX_RADIUS_KM")
+	}
+
+	if c.Query.SoftMaxLength <= 0 || c.Query.HardMaxLength < c.Query.SoftMaxLength {
+		return fmt.Errorf("QUERY_SOFT_MAX_LENGTH must be greater than 0 and at most QUERY_HARD_MAX_LENGTH")
+	}
+
+	if c.Query.NoIntentLimit <= 0 {
+		return fmt.Errorf("QUERY_NO_INTENT_LIMIT must be greater than 0")
+	}
+
+	for intentType, confidence := range c.Query.MinIntentConfidence {
+		switch intentType {
+		case "category", "source", "nearby", "region", "score", "sentiment", "date_range":
+		default:
+			return fmt.Errorf("QUERY_MIN_INTENT_CONFIDENCE has unknown intent type %q", intentType)
+		}
+		if !(confidence >= 0 && confidence <= 1) {
+			return fmt.Errorf("QUERY_MIN_INTENT_CONFIDENCE for %s must be between 0 and 1", intentType)
+		}
+	}
+
+	if c.Query.SpellMaxDistance < 1 || c.Query.SpellMaxDistance > 2 {
+		return fmt.Errorf("QUERY_SPELL_MAX_DISTANCE must be 1 or 2")
+	}
+
+	if c.Query.SpellRefreshInterval < 0 {
+		return fmt.Errorf("QUERY_SPELL_REFRESH_INTERVAL must not be negative")
+	}
+
+	if c.Query.SpellMinTermCount <= 0 {
+		return fmt.Errorf("QUERY_SPELL_MIN_TERM_COUNT must be greater than 0")
+	}
+
+	if c.Query.SpellMaxTerms < 0 {
+		return fmt.Errorf("QUERY_SPELL_MAX_TERMS must not be negative")
+	}
+
+	// Validate retention settings
+	if c.Retention.EventsMaxAge <= 0 {
+		return fmt.Errorf("EVENTS_RETENTION must be greater than 0")
+	}
+
+	if c.Retention.Interval < 0 {
+		return fmt.Errorf("EVENTS_RETENTION_INTERVAL must not be negative")
+	}
+
+	if c.Retention.BatchSize <= 0 {
+		return fmt.Errorf("EVENTS_RETENTION_BATCH_SIZE must be greater than 0")
+	}
+
+	// Validate archive settings
+	if c.Archive.MaxAge <= 0 {
+		return fmt.Errorf("ARTICLES_ARCHIVE_AFTER must be greater than 0")
+	}
+
+	if c.Archive.Interval < 0 {
+		return fmt.Errorf("ARTICLES_ARCHIVE_INTERVAL must not be negative")
+	}
+
+	if c.Archive.BatchSize <= 0 {
+		return fmt.Errorf("ARTICLES_ARCHIVE_BATCH_SIZE must be greater than 0")
+	}
+
+	if c.Relevance.Interval < 0 {
+		return fmt.Errorf("RELEVANCE_RESCORE_INTERVAL must not be negative")
+	}
+
+	if c.Relevance.Window <= 0 {
+		return fmt.Errorf("RELEVANCE_EVENT_WINDOW must be greater than 0")
+	}
+
+	if c.Relevance.HalfLife <= 0 {
+		return fmt.Errorf("RELEVANCE_HALF_LIFE must be greater than 0")
+	}
+
+	if c.Relevance.FeedWeight < 0 || c.Relevance.FeedWeight > 1 {
+		return fmt.Errorf("RELEVANCE_FEED_WEIGHT must be between 0 and 1")
+	}
+
+	// Validate geocoding settings
+	switch c.Geocoder.Provider {
+	case "none", "nominatim":
+	case "opencage":
+		if c.Geocoder.APIKey == "" {
+			return fmt.Errorf("GEOCODER_API_KEY is required when GEOCODER_PROVIDER is opencage")
+		}
+	default:
+		return fmt.Errorf("GEOCODER_PROVIDER must be one of: none, nominatim, opencage")
+	}
+
+	if c.Geocoder.Provider != "none" && c.Geocoder.APIURL == "" {
+		return fmt.Errorf("GEOCODER_API_URL is required")
+	}
+
+	// Validate saved search settings
+	if c.Searches.MaxPerUser <= 0 {
+		return fmt.Errorf("SAVED_SEARCH_MAX_PER_USER must be greater than 0")
+	}
+
+	if c.Searches.MinSimilarity < 0 || c.Searches.MinSimilarity > 1 {
+		return fmt.Errorf("SAVED_SEARCH_MIN_SIMILARITY must be between 0 and 1")
+	}
+
+	if c.Searches.BatchSize <= 0 || c.Searches.QueueSize <= 0 {
+		return fmt.Errorf("SAVED_SEARCH_BATCH_SIZE and SAVED_SEARCH_QUEUE_SIZE must be greater than 0")
+	}
+
+	if c.Searches.FlushInterval <= 0 {
+		return fmt.Errorf("SAVED_SEARCH_FLUSH_INTERVAL must be greater than 0")
+	}
+
+	// Validate content fetching settings
+	if c.Content.Enabled {
+		if strings.TrimSpace(c.Content.UserAgent) == "" {
+			return fmt.Errorf("CONTENT_FETCH_USER_AGENT is required when ENRICH_FETCH_CONTENT is enabled")
+		}
+		if c.Content.Timeout <= 0 {
+			return fmt.Errorf("CONTENT_FETCH_TIMEOUT must be greater than 0")
+		}
+		if c.Content.MaxBodyBytes <= 0 {
+			return fmt.Errorf("CONTENT_FETCH_MAX_BYTES must be greater than 0")
+		}
+		if c.Content.DomainInterval < 0 {
+			return fmt.Errorf("CONTENT_FETCH_DOMAIN_INTERVAL must not be negative")
+		}
+		if c.Content.MaxChars <= 0 {
+			return fmt.Errorf("ENRICH_CONTENT_MAX_CHARS must be greater than 0")
+		}
+	}
+
+	if c.GeoIP.DefaultLatitude < -90 || c.GeoIP.DefaultLatitude > 90 {
+		return fmt.Errorf("DEFAULT_LOCATION_LAT must be between -90 and 90")
+	}
+
+	if c.GeoIP.DefaultLongitude < -180 || c.GeoIP.DefaultLongitude > 180 {
+		return fmt.Errorf("DEFAULT_LOCATION_LON must be between -180 and 180")
+	}
+
+	// Validate vector index settings
+	switch c.Vector.IndexType {
+	case VectorIndexHNSW, VectorIndexIVFFlat:
+	default:
+		return fmt.Errorf("VECTOR_INDEX_TYPE must be one of: hnsw, ivfflat")
+	}
+
+	if c.Vector.HNSWM < 2 || c.Vector.HNSWM > 100 {
+		return fmt.Errorf("VECTOR_HNSW_M must be between 2 and 100")
+	}
+
+	if c.Vector.HNSWEfConstruction < 2*c.Vector.HNSWM || c.Vector.HNSWEfConstruction > 1000 {
+		return fmt.Errorf("VECTOR_HNSW_EF_CONSTRUCTION must be between 2*VECTOR_HNSW_M and 1000")
+	}
+
+	if c.Vector.IVFFlatLists < 1 || c.Vector.IVFFlatLists > 32768 {
+		return fmt.Errorf("VECTOR_IVFFLAT_LISTS must be between 1 and 32768")
+	}
+
+	if c.Vector.EfSearch < 1 || c.Vector.EfSearch > 1000 {
+		return fmt.Errorf("VECTOR_HNSW_EF_SEARCH must be between 1 and 1000")
+	}
+
+	if c.Vector.Probes < 1 || c.Vector.Probes > c.Vector.IVFFlatLists {
+		return fmt.Errorf("VECTOR_IVFFLAT_PROBES must be between 1 and VECTOR_IVFFLAT_LISTS")
+	}
+
+	if c.Vector.SearchLimit < 1 || c.Vector.SearchLimit > 1000 {
+		return fmt.Errorf("VECTOR_SEARCH_LIMIT must be between 1 and 1000")
+	}
+
+	if c.Vector.MinSimilarity < -1 || c.Vector.MinSimilarity > 1 {
+		return fmt.Errorf("VECTOR_MIN_SIMILARITY must be between -1 and 1")
+	}
+
+	if c.Dedupe.Similarity <= 0 || c.Dedupe.Similarity > 1 {
+		return fmt.Errorf("DEDUPE_SIMILARITY must be greater than 0 and at most 1")
+	}
+
+	if c.Dedupe.TitleThreshold <= 0 || c.Dedupe.TitleThreshold > 1 {
+		return fmt.Errorf("DEDUPE_TITLE_THRESHOLD must be greater than 0 and at most 1")
+	}
+
+	// Validate story clustering settings
+	if c.Cluster.Interval < 0 {
+		return fmt.Errorf("CLUSTER_INTERVAL must not be negative")
+	}
+
+	if c.Cluster.Window <= 0 {
+		return fmt.Errorf("CLUSTER_WINDOW must be greater than 0")
+	}
+
+	if c.Cluster.Similarity <= 0 || c.Cluster.Similarity > 1 {
+		return fmt.Errorf("CLUSTER_SIMILARITY must be greater than 0 and at most 1")
+	}
+
+	if c.Cluster.BatchSize <= 0 {
+		return fmt.Errorf("CLUSTER_BATCH_SIZE must be greater than 0")
+	}
+
+	// Validate query log settings
+	if c.QueryLog.SampleRate < 0 || c.QueryLog.SampleRate > 1 {
+		return fmt.Errorf("QUERY_LOG_SAMPLE_RATE must be between 0 and 1")
+	}
+
+	if c.QueryLog.QueueSize <= 0 {
+		return fmt.Errorf("QUERY_LOG_QUEUE_SIZE must be greater than 0")
+	}
+
+	if c.QueryLog.BatchSize <= 0 {
+		return fmt.Errorf("QUERY_LOG_BATCH_SIZE must be greater than 0")
+	}
+
+	if c.QueryLog.FlushInterval <= 0 {
+		return fmt.Errorf("QUERY_LOG_FLUSH_INTERVAL must be greater than 0")
+	}
+
+	if c.QueryLog.Retention <= 0 {
+		return fmt.Errorf("QUERY_LOG_RETENTION must be greater than 0")
+	}
+
+	if c.QueryLog.RetentionInterval < 0 {
+		return fmt.Errorf("QUERY_LOG_RETENTION_INTERVAL must not be negative")
+	}
+
+	// Validate trending cache settings
+	if c.Trending.GeohashPrecision < 1 || c.Trending.GeohashPrecision > 12 {
+		return fmt.Errorf("TRENDING_GEOHASH_PRECISION must be between 1 and 12")
+	}
+
+	if c.Trending.PrecomputeInterval < 0 {
+		return fmt.Errorf("TRENDING_PRECOMPUTE_INTERVAL must not be negative")
+	}
+
+	if c.Trending.PrecomputeCells <= 0 {
+		return fmt.Errorf("TRENDING_PRECOMPUTE_CELLS must be greater than 0")
+	}
+
+	if c.Trending.PrecomputeWorkers <= 0 {
+		return fmt.Errorf("TRENDING_PRECOMPUTE_WORKERS must be greater than 0")
+	}
+
+	if c.Trending.PrecomputeJitter < 0 {
+		return fmt.Errorf("TRENDING_PRECOMPUTE_JITTER must not be negative")
+	}
+
+	if c.Trending.MaxArticleAge <= 0 {
+		return fmt.Errorf("TRENDING_MAX_ARTICLE_AGE must be greater than 0")
+	}
+
+	// Precomputed cells must be refreshed before their cached results expire
+	if c.Trending.PrecomputeInterval > 0 && c.Trending.PrecomputeInterval+c.Trending.PrecomputeJitter >= c.Cache.TTL {
+		return fmt.Errorf("TRENDING_PRECOMPUTE_INTERVAL plus TRENDING_PRECOMPUTE_JITTER must be shorter than CACHE_TTL")
+	}
+
+	// Validate export settings
+	if c.Export.MaxRows <= 0 {
+		return fmt.Errorf("EXPORT_MAX_ROWS must be greater than 0")
+	}
+
+	if c.Export.S3.Enabled() {
+		if endpoint, err := url.Parse(c.Export.S3.Endpoint); err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return fmt.Errorf("EXPORT_S3_ENDPOINT must be an absolute URL, e.g. https://s3.amazonaws.com")
+		}
+		if c.Export.S3.Region == "" {
+			return fmt.Errorf("EXPORT_S3_REGION is required when EXPORT_S3_BUCKET is set")
+		}
+		if c.Export.S3.AccessKeyID == "" || c.Export.S3.SecretAccessKey == "" {
+			return fmt.Errorf("EXPORT_S3_ACCESS_KEY_ID and EXPORT_S3_SECRET_ACCESS_KEY are required when EXPORT_S3_BUCKET is set")
+		}
+	}
+
+	if c.Export.S3.PartSize < minS3PartSize {
+		return fmt.Errorf("EXPORT_S3_PART_SIZE must be at least %d (5MB)", minS3PartSize)
+	}
+
+	if c.Export.SnapshotInterval < 0 {
+		return fmt.Errorf("EXPORT_SNAPSHOT_INTERVAL must not be negative")
+	}
+
+	if c.Export.SnapshotMode != "full" && c.Export.SnapshotMode != "incremental" {
+		return fmt.Errorf("EXPORT_SNAPSHOT_MODE must be one of: full, incremental")
+	}
+
+	// Validate webhook settings
+	if len(c.Webhook.Targets) > 0 && c.Webhook.Secret == "" {
+		return fmt.Errorf("WEBHOOK_SECRET is required when WEBHOOK_TARGETS is set")
+	}
+
+	for name, targetURL := range c.Webhook.Targets {
+		if !strings.HasPrefix(targetURL, "http://") && !strings.HasPrefix(targetURL, "https://") {
+			return fmt.Errorf("WEBHOOK_TARGETS entry %q must be an http or https URL", name)
+		}
+	}
+
+	for name := range c.Webhook.DisabledTargets {
+		if _, ok := c.Webhook.Targets[name]; !ok {
+			return fmt.Errorf("WEBHOOK_DISABLED_TARGETS names unknown target %q", name)
+		}
+	}
+
+	if c.Webhook.MaxAttempts <= 0 {
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be greater than 0")
+	}
+
+	if c.Webhook.RetryBackoff <= 0 || c.Webhook.Timeout <= 0 {
+		return fmt.Errorf("WEBHOOK_RETRY_BACKOFF and WEBHOOK_TIMEOUT must be greater than 0")
+	}
+
+	// Validate experiments
+	for name, spec := range c.Experiment.Definitions {
+		if _, err := ParseExperimentVariants(spec); err != nil {
+			return fmt.Errorf("EXPERIMENTS entry %q is invalid: %w", name, err)
+		}
+	}
+
+	for name := range c.Experiment.Disabled {
+		if _, ok := c.Experiment.Definitions[name]; !ok {
+			return fmt.Errorf("EXPERIMENTS_DISABLED names unknown experiment %q", name)
+		}
+	}
+
+	// Validate tenants. Tenant ids end up in cache keys and API keys must tell tenants apart.
+	for _, tenant := range c.Tenant.IDs() {
+		if !ValidTenantID(tenant) {
+			return fmt.Errorf("tenant id %q must be 1 to 63 lowercase letters, digits, '-' or '_'", tenant)
+		}
+	}
+
+	if _, ok := c.Tenant.APIKeys[c.Tenant.Default]; ok {
+		return fmt.Errorf("TENANT_API_KEYS must not have a key for TENANT_DEFAULT, which requests without a key use")
+	}
+
+	tenantsByKey := make(map[string]string, len(c.Tenant.APIKeys))
+	for tenant, key := range c.Tenant.APIKeys {
+		if c.Tenant.Open[tenant] {
+			return fmt.Errorf("tenant %q must not be in both TENANT_API_KEYS and TENANT_IDS", tenant)
+		}
+		if key == c.Server.AdminAPIKey {
+			return fmt.Errorf("TENANT_API_KEYS key of tenant %q must differ from ADMIN_API_KEY", tenant)
+		}
+		if other, ok := tenantsByKey[key]; ok {
+			return fmt.Errorf("tenants %q and %q must not share an API key", other, tenant)
+		}
+		tenantsByKey[key] = tenant
+	}
+
 	return nil
 }
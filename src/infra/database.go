@@ -5,26 +5,19 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-// InitDatabase initializes the database connection using GORM
-func InitDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
-	log := GetLogger()
-
-	// Configure GORM logger - default to Warn level
-	// GORM logging can be configured separately if needed
-	gormLogLevel := logger.Warn
-
-	// Create GORM config
+// InitDatabase initializes the database connection using GORM, logging to log
+func InitDatabase(cfg DatabaseConfig, log Logger) (*gorm.DB, error) {
+	// Create GORM config; GORM logs go through the application logger
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(gormLogLevel),
+		Logger: newGormLogger(cfg, log),
 	}
 
 	// Open database connection
 	db, err := gorm.Open(postgres.Open(cfg.URL), gormConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", RedactError(err))
 	}
 
 	// Get underlying sql.DB to configure connection pool
@@ -41,7 +34,7 @@ func InitDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
 
 	// Test the connection
 	if err := sqlDB.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, fmt.Errorf("failed to ping database: %w", RedactError(err))
 	}
 
 	log.Info("Database connection established", map[string]interface{}{
@@ -49,18 +42,19 @@ func InitDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
 		"max_idle_conns":     cfg.MaxIdleConns,
 		"conn_max_lifetime":  cfg.ConnMaxLifetime,
 		"conn_max_idle_time": cfg.ConnMaxIdleTime,
+		"log_level":          cfg.LogLevel,
+		"slow_query":         cfg.SlowQueryThreshold,
 	})
 
 	return db, nil
 }
 
 // CloseDatabase closes the database connection
-func CloseDatabase(db *gorm.DB) {
+func CloseDatabase(db *gorm.DB, log Logger) {
 	if db != nil {
 		sqlDB, err := db.DB()
 		if err == nil {
 			sqlDB.Close()
-			log := GetLogger()
 			log.Info("Database connection closed", nil)
 		}
 	}
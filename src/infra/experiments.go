@@ -0,0 +1,153 @@
+package infra
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExperimentControl is the variant every experiment must have. Requests outside an experiment,
+// or in a disabled one, get it.
+const ExperimentControl = "control"
+
+// Experiments known to the services
+const (
+	// ExperimentTrendingFormula compares trending score formulas (see services.trendingFormulas)
+	ExperimentTrendingFormula = "trending_formula"
+)
+
+// ExperimentVariant is a variant of an experiment and its share of the traffic
+type ExperimentVariant struct {
+	Name   string
+	Weight int
+}
+
+// ParseExperimentVariants parses variants given as "name:weight|name:weight". Weights are
+// non-negative integers, at least one must be positive, and a control variant is required.
+func ParseExperimentVariants(spec string) ([]ExperimentVariant, error) {
+	var variants []ExperimentVariant
+	seen := make(map[string]bool)
+	total := 0
+
+	for _, entry := range strings.Split(spec, "|") {
+		name, weightStr, found := strings.Cut(strings.TrimSpace(entry), ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("variant %q must be written as name:weight", entry)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("variant %q must have a non-negative integer weight", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("variant %q is listed more than once", name)
+		}
+		seen[name] = true
+		total += weight
+		variants = append(variants, ExperimentVariant{Name: name, Weight: weight})
+	}
+
+	if !seen[ExperimentControl] {
+		return nil, fmt.Errorf("a %q variant is required", ExperimentControl)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("at least one variant must have a positive weight")
+	}
+	return variants, nil
+}
+
+// ExperimentAssigner assigns requests to experiment variants. Assignment hashes the
+// experiment name with a stable subject, so a user keeps their variant across requests and
+// restarts, and a user's variants in different experiments are independent.
+type ExperimentAssigner struct {
+	experiments map[string][]ExperimentVariant
+}
+
+// NewExperimentAssigner creates an assigner for the enabled experiments of cfg. Definitions
+// are expected to have passed Config.Validate; invalid ones are skipped.
+func NewExperimentAssigner(cfg *ExperimentConfig) *ExperimentAssigner {
+	experiments := make(map[string][]ExperimentVariant)
+	for name, spec := range cfg.Definitions {
+		if cfg.Disabled[name] {
+			continue
+		}
+		variants, err := ParseExperimentVariants(spec)
+		if err != nil {
+			continue
+		}
+		experiments[name] = variants
+	}
+
+	return &ExperimentAssigner{experiments: experiments}
+}
+
+// ExperimentSubject returns what a request's variants are derived from: the user id when
+// the client sends one, otherwise the client IP
+func ExperimentSubject(userID, ip string) string {
+	if userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + ip
+}
+
+// Assign returns the variant of every enabled experiment for subject, keyed by experiment
+// name. It is empty when no experiment is enabled.
+func (a *ExperimentAssigner) Assign(subject string) map[string]string {
+	variants := make(map[string]string, len(a.experiments))
+	for name, experimentVariants := range a.experiments {
+		variants[name] = pickVariant(name, subject, experimentVariants)
+	}
+	return variants
+}
+
+// pickVariant maps the hash of the experiment and subject onto the variants' cumulative weights
+func pickVariant(experiment, subject string, variants []ExperimentVariant) string {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+
+	sum := sha256.Sum256([]byte(experiment + "\x00" + subject))
+	bucket := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+
+	for _, variant := range variants {
+		if bucket < variant.Weight {
+			return variant.Name
+		}
+		bucket -= variant.Weight
+	}
+	return ExperimentControl
+}
+
+// FormatExperimentVariants renders variants as "experiment=variant" pairs sorted by experiment,
+// as sent in the X-Experiment-Variant response header
+func FormatExperimentVariants(variants map[string]string) string {
+	pairs := make([]string, 0, len(variants))
+	for name, variant := range variants {
+		pairs = append(pairs, name+"="+variant)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// experimentVariantsKey is the context key under which a request's variants are stored
+type experimentVariantsKey struct{}
+
+// WithExperimentVariants returns a context carrying the request's experiment variants
+func WithExperimentVariants(ctx context.Context, variants map[string]string) context.Context {
+	return context.WithValue(ctx, experimentVariantsKey{}, variants)
+}
+
+// ExperimentVariantFromContext returns the variant of experiment assigned to the request, or
+// ExperimentControl when the experiment is not running or ctx carries no assignment
+func ExperimentVariantFromContext(ctx context.Context, experiment string) string {
+	variants, _ := ctx.Value(experimentVariantsKey{}).(map[string]string)
+	if variant, ok := variants[experiment]; ok {
+		return variant
+	}
+	return ExperimentControl
+}
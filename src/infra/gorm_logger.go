@@ -0,0 +1,132 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Database log levels accepted by DB_LOG_LEVEL
+const (
+	DBLogLevelSilent = "silent"
+	DBLogLevelError  = "error"
+	DBLogLevelWarn   = "warn"
+	DBLogLevelInfo   = "info"
+)
+
+// maxLoggedSQLLength caps the SQL included in a log entry; bulk inserts would otherwise log
+// thousands of placeholders
+const maxLoggedSQLLength = 500
+
+// gormLogger forwards GORM's logs to the application Logger so database logs share the
+// structured format of everything else. Statement values are redacted unless logParams is set.
+type gormLogger struct {
+	log           Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	logParams     bool
+}
+
+// newGormLogger creates a GORM logger for cfg forwarding to log
+func newGormLogger(cfg DatabaseConfig, log Logger) gormlogger.Interface {
+	return &gormLogger{
+		log:           log,
+		level:         gormLogLevel(cfg.LogLevel),
+		slowThreshold: cfg.SlowQueryThreshold,
+		logParams:     cfg.LogParams,
+	}
+}
+
+// gormLogLevel maps a DB_LOG_LEVEL value to the GORM log level
+func gormLogLevel(level string) gormlogger.LogLevel {
+	switch level {
+	case DBLogLevelSilent:
+		return gormlogger.Silent
+	case DBLogLevelError:
+		return gormlogger.Error
+	case DBLogLevelInfo:
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+// LogMode implements gormlogger.Interface
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+// Info implements gormlogger.Interface
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.log.Info(fmt.Sprintf(msg, data...), nil)
+	}
+}
+
+// Warn implements gormlogger.Interface
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.log.Warn(fmt.Sprintf(msg, data...), nil)
+	}
+}
+
+// Error implements gormlogger.Interface
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.log.Error(fmt.Sprintf(msg, data...), nil, nil)
+	}
+}
+
+// Trace implements gormlogger.Interface. Failed statements are logged at Error, statements
+// slower than the threshold at Warn, and at the info level every statement is logged.
+// ErrRecordNotFound is not a failure for the raw queries this service runs.
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		l.log.Error("Database query failed", err, l.traceFields(fc, elapsed))
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		fields := l.traceFields(fc, elapsed)
+		fields["threshold_ms"] = l.slowThreshold.Milliseconds()
+		l.log.Warn("Slow database query", fields)
+	case l.level >= gormlogger.Info:
+		l.log.Info("Database query", l.traceFields(fc, elapsed))
+	}
+}
+
+// ParamsFilter implements gorm.ParamsFilter. Without logParams the values are dropped, so
+// logged statements keep their $n placeholders.
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if !l.logParams {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// traceFields builds the log fields for a traced statement
+func (l *gormLogger) traceFields(fc func() (string, int64), elapsed time.Duration) map[string]interface{} {
+	sql, rows := fc()
+	if len(sql) > maxLoggedSQLLength {
+		sql = sql[:maxLoggedSQLLength] + "..."
+	}
+
+	fields := map[string]interface{}{
+		"sql":         sql,
+		"duration_ms": float64(elapsed.Microseconds()) / 1000,
+	}
+	// GORM reports -1 when the number of rows is unknown
+	if rows >= 0 {
+		fields["rows"] = rows
+	}
+	return fields
+}
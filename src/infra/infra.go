@@ -1,31 +1,38 @@
 package infra
 
 import (
+	"time"
+
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
-// Infrastructure holds all infrastructure components (DB, Redis, Logger)
+// Infrastructure holds all infrastructure components (DB, Redis, Logger, Scheduler, Clock)
 type Infrastructure struct {
-	DB     *gorm.DB
-	Redis  *redis.Client
-	Logger Logger
+	DB        *gorm.DB
+	Redis     *redis.Client
+	Logger    Logger
+	Scheduler *Scheduler
+	Clock     Clock
+
+	slowQueryThreshold time.Duration
+	// closers run on Close, before the connections they may still use are closed
+	closers []func()
 }
 
 // NewInfrastructure initializes and returns all infrastructure components
-// This includes database, Redis, and logger
-func NewInfrastructure(cfg *Config) (*Infrastructure, error) {
-	logger := GetLogger()
+// This includes database and Redis; every component logs to logger
+func NewInfrastructure(cfg *Config, logger Logger) (*Infrastructure, error) {
 	logger.Info("Initializing infrastructure components", nil)
 
 	// Initialize database connection
-	db, err := InitDatabase(cfg.Database)
+	db, err := InitDatabase(cfg.Database, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	// Initialize Redis connection
-	redisClient, err := InitRedis(cfg.Redis)
+	redisClient, err := InitRedis(cfg.Redis, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -36,21 +43,38 @@ func NewInfrastructure(cfg *Config) (*Infrastructure, error) {
 	})
 
 	infra := &Infrastructure{
-		DB:     db,
-		Redis:  redisClient,
-		Logger: GetLogger(),
+		DB:        db,
+		Redis:     redisClient,
+		Logger:    logger,
+		Scheduler: NewScheduler(logger),
+		Clock:     SystemClock{},
+
+		slowQueryThreshold: cfg.Database.SlowQueryThreshold,
 	}
+	infra.publishPoolMetrics()
 
 	return infra, nil
 }
 
+// OnClose registers fn to run on Close while the database and Redis are still open, e.g. to
+// flush buffered writes. Functions run in reverse order of registration.
+func (infra *Infrastructure) OnClose(fn func()) {
+	infra.closers = append(infra.closers, fn)
+}
+
 // Close gracefully closes all infrastructure connections
 func (infra *Infrastructure) Close() {
+	if infra.Scheduler != nil {
+		infra.Scheduler.Stop()
+	}
+	for i := len(infra.closers) - 1; i >= 0; i-- {
+		infra.closers[i]()
+	}
 	if infra.Redis != nil {
-		CloseRedis(infra.Redis)
+		CloseRedis(infra.Redis, infra.Logger)
 	}
 	if infra.DB != nil {
-		CloseDatabase(infra.DB)
+		CloseDatabase(infra.DB, infra.Logger)
 	}
 	infra.Logger.Info("Infrastructure connections closed", nil)
 }
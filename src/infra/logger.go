@@ -72,7 +72,8 @@ var (
 	once     sync.Once
 )
 
-// GetLogger returns the singleton logger instance
+// GetLogger returns the singleton logger instance. main passes it to NewInfrastructure, from
+// where it is handed to every component; nothing else should call it.
 func GetLogger() Logger {
 	once.Do(func() {
 		level := getLogLevelFromEnv()
@@ -155,11 +156,11 @@ func (l *structuredLogger) Info(msg string, fields map[string]interface{}) {
 	event.Msg(msg)
 }
 
-// Error logs an error message
+// Error logs an error message. Secrets in the error are masked (see RedactSecrets).
 func (l *structuredLogger) Error(msg string, err error, fields map[string]interface{}) {
 	event := l.logger.Error()
 	if err != nil {
-		event = event.Err(err)
+		event = event.Str(zerolog.ErrorFieldName, RedactSecrets(err.Error()))
 	}
 	l.addFields(event, fields)
 	event.Msg(msg)
@@ -179,10 +180,21 @@ func (l *structuredLogger) Debug(msg string, fields map[string]interface{}) {
 	event.Msg(msg)
 }
 
-// addFields adds fields to a zerolog event
+// addFields adds fields to a zerolog event. Errors, and strings under the "error" key where
+// callers put err.Error(), have secrets masked like the error of Error.
 func (l *structuredLogger) addFields(event *zerolog.Event, fields map[string]interface{}) {
 	for k, v := range fields {
-		event = event.Interface(k, v)
+		switch value := v.(type) {
+		case error:
+			event = event.Str(k, RedactSecrets(value.Error()))
+		case string:
+			if k == zerolog.ErrorFieldName {
+				value = RedactSecrets(value)
+			}
+			event = event.Str(k, value)
+		default:
+			event = event.Interface(k, v)
+		}
 	}
 }
 
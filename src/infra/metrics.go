@@ -0,0 +1,59 @@
+package infra
+
+import (
+	"expvar"
+)
+
+// appMetrics holds the application counters. They are published through expvar and
+// served as JSON under the "inshorts" key of GET /debug/vars.
+var appMetrics = expvar.NewMap("inshorts")
+
+// Metric names
+const (
+	MetricQueryFallbackActivations = "query_fallback_activations"
+	MetricQueryAnalysisCacheHits   = "query_analysis_cache_hits"
+	MetricQueryAnalysisCacheMisses = "query_analysis_cache_misses"
+	MetricQueryRelaxations         = "query_relaxations"
+	MetricRetentionEventsDeleted   = "retention_events_deleted"
+	MetricRetentionLastRunUnix     = "retention_last_run_unix"
+	MetricRetentionLastRunDeleted  = "retention_last_run_deleted"
+	MetricArchiveArticlesMoved     = "archive_articles_moved"
+	MetricArchiveLastRunUnix       = "archive_last_run_unix"
+	MetricClusterArticlesAssigned  = "cluster_articles_assigned"
+	MetricClusterLastRunUnix       = "cluster_last_run_unix"
+	MetricWebhookDeliveries        = "webhook_deliveries"
+	MetricWebhookDeliveryFailures  = "webhook_delivery_failures"
+	MetricLLMBudgetExceeded        = "llm_budget_exceeded"
+	MetricLLMCircuitState          = "llm_circuit_state"
+	MetricLLMCircuitRejections     = "llm_circuit_rejections"
+	MetricLLMInflight              = "llm_inflight"
+	MetricLLMInflightBulk          = "llm_inflight_bulk"
+	MetricLLMSemaphoreAcquired     = "llm_semaphore_acquired"
+	MetricLLMSemaphoreWaitMs       = "llm_semaphore_wait_ms"
+	MetricLLMSemaphoreTimeouts     = "llm_semaphore_timeouts"
+	MetricLLMAuditDropped          = "llm_audit_dropped"
+	MetricQueryLogDropped          = "query_log_dropped"
+	MetricPromptSourcesOmitted     = "llm_prompt_sources_omitted"
+	MetricPromptCategoriesOmitted  = "llm_prompt_categories_omitted"
+	MetricTrendingCircuitState     = "trending_cache_circuit_state"
+	MetricDatabasePool             = "db_pool"
+	MetricRedisPool                = "redis_pool"
+	// Token counters are per operation: the operation name is appended to the prefix
+	MetricLLMPromptTokensPrefix     = "llm_prompt_tokens_"
+	MetricLLMCompletionTokensPrefix = "llm_completion_tokens_"
+	// Concurrency limiter metrics are per limited route: the limiter name is appended
+	MetricHTTPInflightPrefix = "http_inflight_"
+	MetricHTTPShedPrefix     = "http_shed_"
+)
+
+// IncrCounter adds delta to the named counter
+func IncrCounter(name string, delta int64) {
+	appMetrics.Add(name, delta)
+}
+
+// SetGauge sets the named metric to value
+func SetGauge(name string, value int64) {
+	v := new(expvar.Int)
+	v.Set(value)
+	appMetrics.Set(name, v)
+}
@@ -0,0 +1,100 @@
+package infra
+
+import (
+	"expvar"
+	"fmt"
+)
+
+// DatabasePoolStats reports the state of the database connection pool
+type DatabasePoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+	// SlowQueryThresholdMs is the duration above which queries are logged as slow
+	SlowQueryThresholdMs int64 `json:"slow_query_threshold_ms"`
+}
+
+// RedisPoolStats reports the state of the Redis connection pool
+type RedisPoolStats struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+}
+
+// PoolStats reports the state of the database and Redis connection pools
+type PoolStats struct {
+	Database DatabasePoolStats `json:"database"`
+	Redis    RedisPoolStats    `json:"redis"`
+}
+
+// DatabaseStats returns the database connection pool statistics
+func (infra *Infrastructure) DatabaseStats() (DatabasePoolStats, error) {
+	sqlDB, err := infra.DB.DB()
+	if err != nil {
+		return DatabasePoolStats{}, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	stats := sqlDB.Stats()
+	return DatabasePoolStats{
+		MaxOpenConnections:   stats.MaxOpenConnections,
+		OpenConnections:      stats.OpenConnections,
+		InUse:                stats.InUse,
+		Idle:                 stats.Idle,
+		WaitCount:            stats.WaitCount,
+		WaitDurationMs:       stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:        stats.MaxIdleClosed,
+		MaxIdleTimeClosed:    stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:    stats.MaxLifetimeClosed,
+		SlowQueryThresholdMs: infra.slowQueryThreshold.Milliseconds(),
+	}, nil
+}
+
+// RedisStats returns the Redis connection pool statistics
+func (infra *Infrastructure) RedisStats() RedisPoolStats {
+	stats := infra.Redis.PoolStats()
+	return RedisPoolStats{
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Timeouts:   stats.Timeouts,
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		StaleConns: stats.StaleConns,
+	}
+}
+
+// PoolStats returns the statistics of both connection pools
+func (infra *Infrastructure) PoolStats() (PoolStats, error) {
+	dbStats, err := infra.DatabaseStats()
+	if err != nil {
+		return PoolStats{}, err
+	}
+
+	return PoolStats{
+		Database: dbStats,
+		Redis:    infra.RedisStats(),
+	}, nil
+}
+
+// publishPoolMetrics exposes the pool statistics under /debug/vars. They are read when the
+// variables are served, so every scrape sees current values.
+func (infra *Infrastructure) publishPoolMetrics() {
+	appMetrics.Set(MetricDatabasePool, expvar.Func(func() any {
+		stats, err := infra.DatabaseStats()
+		if err != nil {
+			return nil
+		}
+		return stats
+	}))
+	appMetrics.Set(MetricRedisPool, expvar.Func(func() any {
+		return infra.RedisStats()
+	}))
+}
@@ -0,0 +1,96 @@
+package infra
+
+import "sync"
+
+// LogEntry is a message recorded by a RecordingLogger
+type LogEntry struct {
+	Level   LogLevel
+	Message string
+	Err     error
+	Fields  map[string]interface{}
+}
+
+// RecordingLogger is a Logger that keeps every entry at or above its level in memory instead
+// of writing it out, so tests can assert on what was logged. It is safe for concurrent use.
+type RecordingLogger struct {
+	mu      sync.Mutex
+	level   LogLevel
+	entries []LogEntry
+}
+
+// NewRecordingLogger creates a RecordingLogger that records entries of every level
+func NewRecordingLogger() *RecordingLogger {
+	return &RecordingLogger{level: DEBUG}
+}
+
+// Info implements Logger
+func (l *RecordingLogger) Info(msg string, fields map[string]interface{}) {
+	l.record(INFO, msg, nil, fields)
+}
+
+// Error implements Logger
+func (l *RecordingLogger) Error(msg string, err error, fields map[string]interface{}) {
+	l.record(ERROR, msg, err, fields)
+}
+
+// Warn implements Logger
+func (l *RecordingLogger) Warn(msg string, fields map[string]interface{}) {
+	l.record(WARN, msg, nil, fields)
+}
+
+// Debug implements Logger
+func (l *RecordingLogger) Debug(msg string, fields map[string]interface{}) {
+	l.record(DEBUG, msg, nil, fields)
+}
+
+// SetLevel implements Logger
+func (l *RecordingLogger) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// Entries returns a copy of the recorded entries in the order they were logged
+func (l *RecordingLogger) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LogEntry(nil), l.entries...)
+}
+
+// EntriesWithMessage returns the recorded entries logged with msg
+func (l *RecordingLogger) EntriesWithMessage(msg string) []LogEntry {
+	var matched []LogEntry
+	for _, entry := range l.Entries() {
+		if entry.Message == msg {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// Reset discards the recorded entries
+func (l *RecordingLogger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+}
+
+// record stores an entry if level is enabled. Fields are copied so later changes by the
+// caller do not alter the record.
+func (l *RecordingLogger) record(level LogLevel, msg string, err error, fields map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	var copied map[string]interface{}
+	if fields != nil {
+		copied = make(map[string]interface{}, len(fields))
+		for key, value := range fields {
+			copied[key] = value
+		}
+	}
+	l.entries = append(l.entries, LogEntry{Level: level, Message: msg, Err: err, Fields: copied})
+}
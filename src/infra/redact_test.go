@@ -0,0 +1,48 @@
+package infra
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	const key = "sk-test-FAKEKEY0123456789abcdef"
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"provider key on its own", "Incorrect API key provided: " + key, "Incorrect API key provided: [REDACTED]"},
+		{"bearer header", "Authorization: Bearer " + key, "Authorization: Bearer [REDACTED]"},
+		{"JSON field", `{"api_key":"` + key + `"}`, `{"api_key":"[REDACTED]"}`},
+		{"query parameter", "GET https://api.example.com/v1?key=" + key + "&q=storm", "GET https://api.example.com/v1?key=[REDACTED]&q=storm"},
+		{"DSN password", "postgres://news:hunter2@db:5432/news", "postgres://news:[REDACTED]@db:5432/news"},
+		{"key/value DSN", "host=db user=news password=hunter2 dbname=news", "host=db user=news password=[REDACTED] dbname=news"},
+		{"nothing secret", "connection refused", "connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactSecrets(tt.in); got != tt.want {
+				t.Errorf("RedactSecrets(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactErrorKeepsErrorChain(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+	err := RedactError(errors.Join(errors.New("password=hunter2"), cause))
+
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("error = %q, want the password masked", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("redacted error no longer wraps its cause")
+	}
+	if plain := errors.New("timeout"); RedactError(plain) != plain {
+		t.Error("error without secrets was wrapped")
+	}
+}
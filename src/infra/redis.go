@@ -8,10 +8,8 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-// InitRedis initializes the Redis client connection
-func InitRedis(cfg RedisConfig) (*redis.Client, error) {
-	log := GetLogger()
-
+// InitRedis initializes the Redis client connection, logging to log
+func InitRedis(cfg RedisConfig, log Logger) (*redis.Client, error) {
 	// Parse Redis URL or use individual components
 	opts := &redis.Options{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
@@ -33,7 +31,7 @@ func InitRedis(cfg RedisConfig) (*redis.Client, error) {
 
 	if err := client.Ping(ctx).Err(); err != nil {
 		client.Close()
-		return nil, fmt.Errorf("failed to ping Redis: %w", err)
+		return nil, fmt.Errorf("failed to ping Redis: %w", RedactError(err))
 	}
 
 	log.Info("Redis connection established", map[string]interface{}{
@@ -48,10 +46,9 @@ func InitRedis(cfg RedisConfig) (*redis.Client, error) {
 }
 
 // CloseRedis closes the Redis client connection
-func CloseRedis(client *redis.Client) {
+func CloseRedis(client *redis.Client, log Logger) {
 	if client != nil {
 		client.Close()
-		log := GetLogger()
 		log.Info("Redis connection closed", nil)
 	}
 }
@@ -0,0 +1,120 @@
+package infra
+
+import (
+	"sync"
+	"time"
+)
+
+// scheduledTask is a function run periodically by the Scheduler
+type scheduledTask struct {
+	name     string
+	interval time.Duration
+	fn       func()
+}
+
+// Scheduler runs registered tasks at fixed intervals on background goroutines. Runs of the
+// same task never overlap: the next tick is skipped while the previous run is in progress.
+type Scheduler struct {
+	mu      sync.Mutex
+	tasks   []scheduledTask
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+	log     Logger
+}
+
+// NewScheduler creates a new Scheduler instance that reports task runs to log
+func NewScheduler(log Logger) *Scheduler {
+	return &Scheduler{
+		stop: make(chan struct{}),
+		log:  log,
+	}
+}
+
+// Every registers fn to run every interval once the scheduler is started. Tasks registered
+// with a non-positive interval are ignored, which lets callers disable a task via config.
+func (s *Scheduler) Every(name string, interval time.Duration, fn func()) {
+	if interval <= 0 {
+		s.log.Info("Scheduled task disabled", map[string]interface{}{
+			"task": name,
+		})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task := scheduledTask{name: name, interval: interval, fn: fn}
+	s.tasks = append(s.tasks, task)
+	if s.started {
+		s.run(task)
+	}
+}
+
+// Start launches all registered tasks
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, task := range s.tasks {
+		s.run(task)
+	}
+}
+
+// Stop signals all tasks to stop and waits for in-flight runs to finish
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = false
+	close(s.stop)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// run starts the ticker goroutine for a task; callers must hold s.mu
+func (s *Scheduler) run(task scheduledTask) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(task.interval)
+		defer ticker.Stop()
+
+		s.log.Info("Scheduled task started", map[string]interface{}{
+			"task":     task.name,
+			"interval": task.interval.String(),
+		})
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.runOnce(task)
+			}
+		}
+	}()
+}
+
+// runOnce executes a task, recovering from panics so one bad run does not kill the schedule
+func (s *Scheduler) runOnce(task scheduledTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("Scheduled task panicked", nil, map[string]interface{}{
+				"task":  task.name,
+				"panic": r,
+			})
+		}
+	}()
+
+	task.fn()
+}
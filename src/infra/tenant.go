@@ -0,0 +1,112 @@
+package infra
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"regexp"
+)
+
+// DefaultTenant is the default of TENANT_DEFAULT and of the tenant_id columns, so rows stored
+// by single-tenant installs belong to it
+const DefaultTenant = "default"
+
+// TenantHeader is the request header selecting a tenant that has no API key
+const TenantHeader = "X-Tenant-ID"
+
+// Errors returned by TenantResolver.Resolve
+var (
+	// ErrUnknownTenant is returned for a tenant id that is not configured
+	ErrUnknownTenant = errors.New("unknown tenant")
+	// ErrTenantKeyRequired is returned when a tenant with an API key is selected without it
+	ErrTenantKeyRequired = errors.New("tenant requires its API key")
+	// ErrInvalidTenantKey is returned for an API key that belongs to no tenant
+	ErrInvalidTenantKey = errors.New("invalid tenant API key")
+	// ErrTenantMismatch is returned when the tenant header names another tenant than the key
+	ErrTenantMismatch = errors.New("tenant header does not match the API key")
+)
+
+// tenantIDPattern matches valid tenant ids; they are used in cache keys, so they are kept short
+// and free of separators
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9_-]{1,63}$`)
+
+// ValidTenantID reports whether tenant is a well-formed tenant id
+func ValidTenantID(tenant string) bool {
+	return tenantIDPattern.MatchString(tenant)
+}
+
+// TenantResolver resolves the tenant of a request from its API key and tenant header
+type TenantResolver struct {
+	cfg         *TenantConfig
+	adminAPIKey string
+}
+
+// NewTenantResolver creates a resolver for the tenants of cfg. adminAPIKey is accepted as a key
+// of no tenant, so admin requests select their tenant with the header.
+func NewTenantResolver(cfg *TenantConfig, adminAPIKey string) *TenantResolver {
+	return &TenantResolver{cfg: cfg, adminAPIKey: adminAPIKey}
+}
+
+// Resolve returns the tenant of a request. A tenant's API key selects it, and a header naming
+// another tenant is rejected. Without a tenant key the header may select the default tenant
+// and the tenants listed in TENANT_IDS, or any tenant for admin requests; with neither the
+// request gets the default tenant.
+func (r *TenantResolver) Resolve(apiKey, header string) (string, error) {
+	admin := false
+	if apiKey != "" {
+		if tenant, ok := r.tenantOfKey(apiKey); ok {
+			if header != "" && header != tenant {
+				return "", ErrTenantMismatch
+			}
+			return tenant, nil
+		}
+
+		admin = r.adminAPIKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(r.adminAPIKey)) == 1
+		if !admin && len(r.cfg.APIKeys) > 0 {
+			return "", ErrInvalidTenantKey
+		}
+	}
+
+	switch {
+	case header == "" || header == r.cfg.Default || r.cfg.Open[header]:
+		if header == "" {
+			return r.cfg.Default, nil
+		}
+		return header, nil
+	case r.cfg.APIKeys[header] != "":
+		if admin {
+			return header, nil
+		}
+		return "", ErrTenantKeyRequired
+	default:
+		return "", ErrUnknownTenant
+	}
+}
+
+// tenantOfKey returns the tenant whose API key is apiKey, comparing every key in constant time
+func (r *TenantResolver) tenantOfKey(apiKey string) (string, bool) {
+	match := ""
+	for tenant, key := range r.cfg.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1 {
+			match = tenant
+		}
+	}
+	return match, match != ""
+}
+
+// tenantKey is the context key under which a request's tenant is stored
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the request's tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant stored on ctx, or fallback when ctx carries none, as for
+// scheduled jobs and single-tenant installs
+func TenantFromContext(ctx context.Context, fallback string) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return fallback
+}
@@ -0,0 +1,94 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTenantResolverResolve(t *testing.T) {
+	resolver := NewTenantResolver(&TenantConfig{
+		Default: DefaultTenant,
+		APIKeys: map[string]string{"acme": "acme-key", "globex": "globex-key"},
+		Open:    map[string]bool{"public": true},
+	}, "admin-key")
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{name: "no headers", want: DefaultTenant},
+		{name: "default tenant header", header: DefaultTenant, want: DefaultTenant},
+		{name: "open tenant header", header: "public", want: "public"},
+		{name: "tenant key", apiKey: "acme-key", want: "acme"},
+		{name: "tenant key with its own header", apiKey: "acme-key", header: "acme", want: "acme"},
+		{name: "tenant key with another tenant", apiKey: "acme-key", header: "globex", wantErr: ErrTenantMismatch},
+		{name: "unknown key", apiKey: "wrong-key", wantErr: ErrInvalidTenantKey},
+		{name: "keyed tenant without its key", header: "acme", wantErr: ErrTenantKeyRequired},
+		{name: "unknown tenant", header: "initech", wantErr: ErrUnknownTenant},
+		{name: "unknown tenant with tenant key of another", apiKey: "acme-key", header: "initech", wantErr: ErrTenantMismatch},
+		{name: "admin key selects keyed tenant", apiKey: "admin-key", header: "globex", want: "globex"},
+		{name: "admin key without header", apiKey: "admin-key", want: DefaultTenant},
+		{name: "admin key with unknown tenant", apiKey: "admin-key", header: "initech", wantErr: ErrUnknownTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.apiKey, tt.header)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve(%q, %q) error = %v, want %v", tt.apiKey, tt.header, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve(%q, %q) = %q, want %q", tt.apiKey, tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantResolverWithoutTenantKeys(t *testing.T) {
+	resolver := NewTenantResolver(&TenantConfig{Default: DefaultTenant}, "")
+
+	// Single-tenant installs accept any key, since the API key middleware checks it
+	if got, err := resolver.Resolve("some-key", ""); err != nil || got != DefaultTenant {
+		t.Errorf("Resolve with a key = %q, %v; want %q", got, err, DefaultTenant)
+	}
+	if _, err := resolver.Resolve("", "acme"); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("Resolve of an unconfigured tenant error = %v, want ErrUnknownTenant", err)
+	}
+}
+
+func TestValidTenantID(t *testing.T) {
+	tests := []struct {
+		tenant string
+		want   bool
+	}{
+		{"default", true},
+		{"acme-corp_2", true},
+		{"", false},
+		{"Acme", false},
+		{"acme:corp", false},
+		{"acme*", false},
+		{"a-very-long-tenant-id-that-goes-on-and-on-past-sixty-three-chars", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidTenantID(tt.tenant); got != tt.want {
+			t.Errorf("ValidTenantID(%q) = %v, want %v", tt.tenant, got, tt.want)
+		}
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	if got := TenantFromContext(context.Background(), "fallback"); got != "fallback" {
+		t.Errorf("TenantFromContext without tenant = %q, want fallback", got)
+	}
+	if got := TenantFromContext(WithTenant(context.Background(), "acme"), "fallback"); got != "acme" {
+		t.Errorf("TenantFromContext with tenant = %q, want acme", got)
+	}
+	if got := TenantFromContext(WithTenant(context.Background(), ""), "fallback"); got != "fallback" {
+		t.Errorf("TenantFromContext with empty tenant = %q, want fallback", got)
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+)
+
+// APIKeyHeader is the request header carrying the admin API key
+const APIKeyHeader = "X-API-Key"
+
+// RequireAPIKey returns a middleware that only lets requests through when the X-API-Key header
+// matches apiKey. If apiKey is empty the protected routes are disabled entirely rather than
+// left open.
+func RequireAPIKey(apiKey string) fiber.Handler {
+	if apiKey == "" {
+		return func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusForbidden).JSON(types.ErrorResponse{
+				ErrorCode: "ADMIN_API_DISABLED",
+				Error:     "Admin API is disabled; set ADMIN_API_KEY to enable it",
+			})
+		}
+	}
+
+	return keyauth.New(keyauth.Config{
+		KeyLookup: "header:" + APIKeyHeader,
+		Validator: func(c *fiber.Ctx, key string) (bool, error) {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+				return true, nil
+			}
+			return false, keyauth.ErrMissingOrMalformedAPIKey
+		},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return c.Status(fiber.StatusUnauthorized).JSON(types.ErrorResponse{
+				ErrorCode: "UNAUTHORIZED",
+				Error:     "Missing or invalid API key",
+			})
+		},
+	})
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit returns a middleware that rejects requests whose body is larger than limit bytes
+// with 413 REQUEST_TOO_LARGE. Paths in perPath get their own limit instead. The server's own
+// body limit must be at least the largest of them, since bodies above it never reach
+// middleware.
+func BodyLimit(limit int, perPath map[string]int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		pathLimit := limit
+		if override, ok := perPath[strings.TrimSuffix(c.Path(), "/")]; ok {
+			pathLimit = override
+		}
+
+		if c.Request().Header.ContentLength() > pathLimit || len(c.Body()) > pathLimit {
+			return fiber.ErrRequestEntityTooLarge
+		}
+		return c.Next()
+	}
+}
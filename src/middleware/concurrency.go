@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConcurrencyLimit returns a middleware that lets at most limit requests run the rest of the
+// handler chain at once. A request waits up to maxWait for a slot and otherwise receives 503
+// SERVER_BUSY with a Retry-After header. The number of requests holding a slot is published
+// as the http_inflight_<name> gauge and shed requests are counted in http_shed_<name>. A
+// non-positive limit disables the middleware.
+//
+// Slots are released by a deferred call, so a panicking handler frees its slot while the
+// panic unwinds towards the recover middleware registered ahead of this one.
+func ConcurrencyLimit(name string, limit int, maxWait time.Duration) fiber.Handler {
+	if limit <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	slots := make(chan struct{}, limit)
+	var inflight atomic.Int64
+	inflightMetric := infra.MetricHTTPInflightPrefix + name
+	shedMetric := infra.MetricHTTPShedPrefix + name
+	retryAfter := strconv.Itoa(int(math.Max(1, math.Ceil(maxWait.Seconds()))))
+
+	return func(c *fiber.Ctx) error {
+		if !acquireSlot(slots, maxWait) {
+			infra.IncrCounter(shedMetric, 1)
+			c.Set(fiber.HeaderRetryAfter, retryAfter)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(types.ErrorResponse{
+				ErrorCode: "SERVER_BUSY",
+				Error:     "Too many concurrent requests, try again later",
+			})
+		}
+
+		infra.SetGauge(inflightMetric, inflight.Add(1))
+		defer func() {
+			<-slots
+			infra.SetGauge(inflightMetric, inflight.Add(-1))
+		}()
+
+		return c.Next()
+	}
+}
+
+// acquireSlot takes a slot from slots, waiting at most maxWait for one to free up
+func acquireSlot(slots chan struct{}, maxWait time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if maxWait <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -1,16 +1,23 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+
 	"news-inshorts/src/infra"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // AppError represents a custom application error with HTTP status code
 type AppError struct {
-	Code    int    `json:"-"`
-	Message string `json:"error"`
-	Err     error  `json:"-"`
+	Code      int    `json:"-"`
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"error"`
+	Err       error  `json:"-"`
 }
 
 // Error implements the error interface
@@ -21,58 +28,123 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
-// NewAppError creates a new AppError
-func NewAppError(code int, message string, err error) *AppError {
+// Unwrap returns the underlying error so errors.Is/As can inspect it
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// NewAppError creates a new AppError. Controllers use it to attach their endpoint-specific
+// error code to a failure; the error handler still overrides it for known error classes.
+func NewAppError(code int, errorCode, message string, err error) *AppError {
 	return &AppError{
-		Code:    code,
-		Message: message,
-		Err:     err,
+		Code:      code,
+		ErrorCode: errorCode,
+		Message:   message,
+		Err:       err,
 	}
 }
 
 // Predefined error types
 var (
-	ErrInvalidInput   = &AppError{Code: 400, Message: "Invalid input parameters"}
-	ErrNotFound       = &AppError{Code: 404, Message: "Resource not found"}
-	ErrInternalServer = &AppError{Code: 500, Message: "Internal server error"}
-	ErrLLMUnavailable = &AppError{Code: 503, Message: "LLM service unavailable"}
-	ErrDatabaseError  = &AppError{Code: 503, Message: "Database connection error"}
+	ErrInvalidInput   = &AppError{Code: 400, ErrorCode: "INVALID_INPUT", Message: "Invalid input parameters"}
+	ErrNotFound       = &AppError{Code: 404, ErrorCode: "NOT_FOUND", Message: "Resource not found"}
+	ErrInternalServer = &AppError{Code: 500, ErrorCode: "INTERNAL_ERROR", Message: "Internal server error"}
+	ErrLLMUnavailable = &AppError{Code: 503, ErrorCode: "LLM_UNAVAILABLE", Message: "LLM service unavailable"}
+	ErrDatabaseError  = &AppError{Code: 503, ErrorCode: "DATABASE_UNAVAILABLE", Message: "Database connection error"}
+	ErrRequestTimeout = &AppError{Code: 504, ErrorCode: "REQUEST_TIMEOUT", Message: "Request timed out"}
+	ErrRouteNotFound  = &AppError{Code: 404, ErrorCode: "ROUTE_NOT_FOUND", Message: "Route not found"}
 )
 
-// ErrorHandler is a Fiber error handling middleware
-func ErrorHandler(c *fiber.Ctx, err error) error {
-	log := infra.GetLogger()
-
-	// Default to src server error
-	appErr, ok := err.(*AppError)
-	if !ok {
-		// Check if it's a Fiber error
-		if fiberErr, ok := err.(*fiber.Error); ok {
-			appErr = &AppError{
-				Code:    fiberErr.Code,
-				Message: fiberErr.Message,
-				Err:     err,
-			}
-		} else {
-			// Unknown error type
-			appErr = &AppError{
-				Code:    500,
-				Message: "Internal server error",
-				Err:     err,
-			}
-		}
+// fiberErrorCodes names the errors Fiber raises itself, such as for unmatched routes or
+// oversized bodies. Other statuses are reported as HTTP_ERROR.
+var fiberErrorCodes = map[int]string{
+	fiber.StatusBadRequest:            "INVALID_REQUEST",
+	fiber.StatusNotFound:              "ROUTE_NOT_FOUND",
+	fiber.StatusMethodNotAllowed:      "METHOD_NOT_ALLOWED",
+	fiber.StatusRequestEntityTooLarge: "REQUEST_TOO_LARGE",
+	fiber.StatusUnsupportedMediaType:  "UNSUPPORTED_MEDIA_TYPE",
+	fiber.StatusUnprocessableEntity:   "UNPROCESSABLE_REQUEST",
+}
+
+// errorClasses maps errors raised by the service and repository layers to the response
+// returned for them, regardless of which endpoint they surface from. Dependency outages are
+// checked before deadlines since an LLM call that hit its own timeout is an outage.
+var errorClasses = []struct {
+	target error
+	appErr *AppError
+}{
+	{services.ErrLLMUnavailable, ErrLLMUnavailable},
+	{repositories.ErrDatabaseUnavailable, ErrDatabaseError},
+	{context.DeadlineExceeded, ErrRequestTimeout},
+	{repositories.ErrArticleNotFound, &AppError{Code: 404, ErrorCode: "ARTICLE_NOT_FOUND", Message: "Article not found"}},
+	{repositories.ErrDuplicateArticle, &AppError{Code: 409, ErrorCode: "DUPLICATE_ARTICLE", Message: "An article with this URL already exists"}},
+	{services.ErrQueryEmpty, &AppError{Code: 400, ErrorCode: "EMPTY_QUERY", Message: "Query contains no searchable text"}},
+	{services.ErrQueryTooLong, &AppError{Code: 400, ErrorCode: "QUERY_TOO_LONG", Message: "Query is too long"}},
+	{services.ErrUnsupportedSummaryLang, &AppError{Code: 400, ErrorCode: "UNSUPPORTED_SUMMARY_LANG", Message: "Summary language is not supported"}},
+}
+
+// NewErrorHandler returns the Fiber error handler, which logs failed requests to log
+func NewErrorHandler(log infra.Logger) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		return handleError(c, err, log)
 	}
+}
+
+// handleError renders err as the JSON error response for its class
+func handleError(c *fiber.Ctx, err error, log infra.Logger) error {
+	appErr := classifyError(err)
 
 	// Log the error with context
-	log.Error("Request failed", appErr.Err, map[string]interface{}{
-		"path":   c.Path(),
-		"method": c.Method(),
-		"code":   appErr.Code,
-		"ip":     c.IP(),
+	log.Error("Request failed", err, map[string]interface{}{
+		"path":       c.Path(),
+		"method":     c.Method(),
+		"code":       appErr.Code,
+		"error_code": appErr.ErrorCode,
+		"ip":         c.IP(),
 	})
 
 	// Return JSON error response
-	return c.Status(appErr.Code).JSON(fiber.Map{
-		"error": appErr.Message,
+	return c.Status(appErr.Code).JSON(types.ErrorResponse{
+		ErrorCode: appErr.ErrorCode,
+		Error:     appErr.Message,
 	})
 }
+
+// classifyError picks the response for err. Known error classes win over the generic code a
+// controller attached, so an LLM outage is reported as 503 whichever endpoint hit it. Filter
+// chain failures are the exception: the controller already classified their cause and named
+// the failed filter in the code.
+func classifyError(err error) *AppError {
+	var appErr *AppError
+	var filterErr *services.FilterError
+	if errors.As(err, &filterErr) && errors.As(err, &appErr) {
+		return appErr
+	}
+
+	for _, class := range errorClasses {
+		if errors.Is(err, class.target) {
+			return class.appErr
+		}
+	}
+
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	// Check if it's a Fiber error
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		errorCode, ok := fiberErrorCodes[fiberErr.Code]
+		if !ok {
+			errorCode = "HTTP_ERROR"
+		}
+		return &AppError{
+			Code:      fiberErr.Code,
+			ErrorCode: errorCode,
+			Message:   fiberErr.Message,
+		}
+	}
+
+	// Unknown error type, including recovered panics
+	return ErrInternalServer
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+// TestErrorEnvelope checks that every kind of failure is answered with the same JSON
+// envelope of error_code and error, and the status of its class
+func TestErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		err         error
+		panics      bool
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{name: "unknown route", method: fiber.MethodGet, path: "/api/v1/nope", wantStatus: 404, wantCode: "ROUTE_NOT_FOUND", wantMessage: "Route not found"},
+		{name: "unknown method on a known path", method: fiber.MethodPatch, path: "/fail", wantStatus: 404, wantCode: "ROUTE_NOT_FOUND", wantMessage: "Route not found"},
+		{name: "controller error", err: NewAppError(500, "FILTER_ARTICLES_FAILED", "Failed to filter articles", errors.New("boom")), wantStatus: 500, wantCode: "FILTER_ARTICLES_FAILED", wantMessage: "Failed to filter articles"},
+		{name: "LLM outage behind a controller error", err: NewAppError(500, "ARTICLE_SUMMARIZE_FAILED", "Failed to summarize article", fmt.Errorf("summarize: %w", services.ErrLLMUnavailable)), wantStatus: 503, wantCode: "LLM_UNAVAILABLE", wantMessage: "LLM service unavailable"},
+		{name: "database outage", err: fmt.Errorf("%w: connection refused", repositories.ErrDatabaseUnavailable), wantStatus: 503, wantCode: "DATABASE_UNAVAILABLE", wantMessage: "Database connection error"},
+		{name: "deadline", err: context.DeadlineExceeded, wantStatus: 504, wantCode: "REQUEST_TIMEOUT", wantMessage: "Request timed out"},
+		{name: "article not found", err: repositories.ErrArticleNotFound, wantStatus: 404, wantCode: "ARTICLE_NOT_FOUND", wantMessage: "Article not found"},
+		{name: "filter failure keeps the controller's code", err: NewAppError(503, "FILTER_SEARCH_LLM_UNAVAILABLE", "LLM service unavailable", &services.FilterError{Filter: "search", Err: services.ErrLLMUnavailable}), wantStatus: 503, wantCode: "FILTER_SEARCH_LLM_UNAVAILABLE", wantMessage: "LLM service unavailable"},
+		{name: "oversized body", err: fiber.ErrRequestEntityTooLarge, wantStatus: 413, wantCode: "REQUEST_TOO_LARGE", wantMessage: "Request Entity Too Large"},
+		{name: "unsupported media type", err: fiber.ErrUnsupportedMediaType, wantStatus: 415, wantCode: "UNSUPPORTED_MEDIA_TYPE", wantMessage: "Unsupported Media Type"},
+		{name: "other Fiber status", err: fiber.ErrTeapot, wantStatus: 418, wantCode: "HTTP_ERROR", wantMessage: "I'm a teapot"},
+		{name: "unknown error", err: errors.New("disk full"), wantStatus: 500, wantCode: "INTERNAL_ERROR", wantMessage: "Internal server error"},
+		{name: "panic", panics: true, wantStatus: 500, wantCode: "INTERNAL_ERROR", wantMessage: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := infra.NewRecordingLogger()
+			app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(logger)})
+			app.Use(recover.New())
+			app.Get("/fail", func(c *fiber.Ctx) error {
+				if tt.panics {
+					panic("handler bug")
+				}
+				return tt.err
+			})
+			// The catch-all route registered last by SetupRoutes
+			app.Use(func(c *fiber.Ctx) error {
+				return ErrRouteNotFound
+			})
+
+			method, path := tt.method, tt.path
+			if method == "" {
+				method, path = fiber.MethodGet, "/fail"
+			}
+			resp, err := app.Test(httptest.NewRequest(method, path, nil), -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if contentType := resp.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+				t.Errorf("Content-Type = %q, want JSON", contentType)
+			}
+
+			var body map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			want := map[string]interface{}{"error_code": tt.wantCode, "error": tt.wantMessage}
+			if len(body) != len(want) || body["error_code"] != want["error_code"] || body["error"] != want["error"] {
+				t.Errorf("body = %v, want %v", body, want)
+			}
+
+			if failed := logger.EntriesWithMessage("Request failed"); len(failed) != 1 || failed[0].Fields["error_code"] != tt.wantCode {
+				t.Errorf("logged %+v, want one failure with error_code %s", failed, tt.wantCode)
+			}
+		})
+	}
+}
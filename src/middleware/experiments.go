@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"news-inshorts/src/infra"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExperimentVariantHeader reports the experiment variants a response was produced under
+const ExperimentVariantHeader = "X-Experiment-Variant"
+
+// AssignExperiments returns a middleware that assigns each request its experiment variants,
+// sticky per user_id query parameter or, without one, per client IP. The variants are stored
+// on the request's user context for services to read and reported in the
+// X-Experiment-Variant header. Nothing is set when no experiment is enabled.
+func AssignExperiments(assigner *infra.ExperimentAssigner) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		variants := assigner.Assign(infra.ExperimentSubject(c.Query("user_id"), c.IP()))
+		if len(variants) == 0 {
+			return c.Next()
+		}
+
+		c.SetUserContext(infra.WithExperimentVariants(c.UserContext(), variants))
+		c.Set(ExperimentVariantHeader, infra.FormatExperimentVariants(variants))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HTTPCache returns a middleware that lets clients revalidate successful GET responses. It
+// sets a weak ETag derived from the response body, which changes whenever any article in
+// the response changes, and answers 304 Not Modified when If-None-Match carries the same tag.
+// Responses are marked cacheable for maxAge; a non-positive maxAge sends no-cache so clients
+// always revalidate. Responses produced under experiment variants are private, so a shared
+// cache does not hand one user's variant to another. Responses vary by the headers selecting
+// the tenant, whose articles they list.
+func HTTPCache(maxAge time.Duration) fiber.Handler {
+	cacheControl := "no-cache"
+	privateCacheControl := "no-cache"
+	if maxAge > 0 {
+		cacheControl = fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+		privateCacheControl = fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))
+	}
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		sum := sha256.Sum256(c.Response().Body())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+		c.Set(fiber.HeaderETag, etag)
+		c.Vary(APIKeyHeader, infra.TenantHeader)
+		if len(c.Response().Header.Peek(ExperimentVariantHeader)) > 0 {
+			c.Set(fiber.HeaderCacheControl, privateCacheControl)
+		} else {
+			c.Set(fiber.HeaderCacheControl, cacheControl)
+		}
+
+		if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+			c.Status(fiber.StatusNotModified)
+			c.Context().ResetBody()
+			return nil
+		}
+
+		return nil
+	}
+}
+
+// etagMatches reports whether an If-None-Match header value contains etag, using the weak
+// comparison required for GET requests
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
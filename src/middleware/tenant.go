@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"errors"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// tenantErrors maps tenant resolution errors to the response returned for them
+var tenantErrors = []struct {
+	target  error
+	status  int
+	code    string
+	message string
+}{
+	{infra.ErrInvalidTenantKey, fiber.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid API key"},
+	{infra.ErrTenantKeyRequired, fiber.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid API key"},
+	{infra.ErrTenantMismatch, fiber.StatusForbidden, "TENANT_MISMATCH", "X-Tenant-ID does not match the API key"},
+	{infra.ErrUnknownTenant, fiber.StatusBadRequest, "UNKNOWN_TENANT", "X-Tenant-ID names an unknown tenant"},
+}
+
+// ResolveTenant returns a middleware that resolves the tenant of each request from its
+// X-API-Key and X-Tenant-ID headers and stores it on the request's user context, where the
+// repositories read it. Requests whose headers select no tenant they may access are rejected.
+func ResolveTenant(resolver *infra.TenantResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// The tenant outlives the request in background jobs and queued query log entries, so
+		// it must not share memory with the header, which Fiber reuses for the next request
+		tenant, err := resolver.Resolve(c.Get(APIKeyHeader), utils.CopyString(c.Get(infra.TenantHeader)))
+		if err != nil {
+			for _, class := range tenantErrors {
+				if errors.Is(err, class.target) {
+					return c.Status(class.status).JSON(types.ErrorResponse{
+						ErrorCode: class.code,
+						Error:     class.message,
+					})
+				}
+			}
+			return err
+		}
+
+		c.SetUserContext(infra.WithTenant(c.UserContext(), tenant))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestResolveTenant(t *testing.T) {
+	resolver := infra.NewTenantResolver(&infra.TenantConfig{
+		Default: infra.DefaultTenant,
+		APIKeys: map[string]string{"acme": "acme-key"},
+		Open:    map[string]bool{"public": true},
+	}, "admin-key")
+
+	app := fiber.New()
+	app.Use(ResolveTenant(resolver))
+	app.Get("/tenant", func(c *fiber.Ctx) error {
+		return c.SendString(infra.TenantFromContext(c.UserContext(), ""))
+	})
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		header     string
+		wantStatus int
+		wantTenant string
+		wantCode   string
+	}{
+		{name: "default tenant", wantStatus: fiber.StatusOK, wantTenant: infra.DefaultTenant},
+		{name: "open tenant", header: "public", wantStatus: fiber.StatusOK, wantTenant: "public"},
+		{name: "tenant key", apiKey: "acme-key", wantStatus: fiber.StatusOK, wantTenant: "acme"},
+		{name: "admin selects tenant", apiKey: "admin-key", header: "acme", wantStatus: fiber.StatusOK, wantTenant: "acme"},
+		{name: "unknown tenant", header: "initech", wantStatus: fiber.StatusBadRequest, wantCode: "UNKNOWN_TENANT"},
+		{name: "keyed tenant without key", header: "acme", wantStatus: fiber.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "invalid key", apiKey: "wrong-key", wantStatus: fiber.StatusUnauthorized, wantCode: "UNAUTHORIZED"},
+		{name: "key of another tenant", apiKey: "acme-key", header: "public", wantStatus: fiber.StatusForbidden, wantCode: "TENANT_MISMATCH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/tenant", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.header != "" {
+				req.Header.Set(infra.TenantHeader, tt.header)
+			}
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if tt.wantCode != "" {
+				var body types.ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode error response: %v", err)
+				}
+				if body.ErrorCode != tt.wantCode {
+					t.Errorf("error_code = %q, want %q", body.ErrorCode, tt.wantCode)
+				}
+				return
+			}
+
+			tenant, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read response: %v", err)
+			}
+			if got := string(tenant); got != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
+}
+
+// TestResolveTenantOutlivesRequest checks that the tenant kept after a request is not
+// overwritten when Fiber reuses the request's memory
+func TestResolveTenantOutlivesRequest(t *testing.T) {
+	resolver := infra.NewTenantResolver(&infra.TenantConfig{
+		Default: infra.DefaultTenant,
+		Open:    map[string]bool{"tenant-a": true, "tenant-b": true},
+	}, "")
+
+	var kept []string
+	app := fiber.New()
+	app.Use(ResolveTenant(resolver))
+	app.Get("/tenant", func(c *fiber.Ctx) error {
+		kept = append(kept, infra.TenantFromContext(c.UserContext(), ""))
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for _, tenant := range []string{"tenant-a", "tenant-b", "tenant-b"} {
+		req := httptest.NewRequest(fiber.MethodGet, "/tenant", nil)
+		req.Header.Set(infra.TenantHeader, tenant)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if len(kept) != 3 || kept[0] != "tenant-a" || kept[1] != "tenant-b" {
+		t.Errorf("kept tenants = %q, want [tenant-a tenant-b tenant-b]", kept)
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Timeout returns a middleware that gives the rest of the handler chain a time budget. The
+// budget is carried by the request's user context, so services and repositories that honor
+// c.UserContext() abandon their work once it expires. When the budget is exceeded the client
+// receives 504 with a REQUEST_TIMEOUT error. A non-positive budget disables the middleware.
+func Timeout(budget time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if budget <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), budget)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return c.Status(fiber.StatusGatewayTimeout).JSON(types.ErrorResponse{
+				ErrorCode: "REQUEST_TIMEOUT",
+				Error:     fmt.Sprintf("Request did not complete within %s", budget),
+			})
+		}
+
+		return err
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"slices"
 	"time"
 )
 
@@ -11,6 +12,21 @@ type Location struct {
 	Longitude float64 `json:"longitude" validate:"required,min=-180,max=180"`
 }
 
+// Place is the administrative area a location lies in. Either field may be empty, e.g. for
+// locations at sea.
+type Place struct {
+	Country string `json:"country"`
+	Region  string `json:"region"`
+}
+
+// Location sources, i.e. where the location used for a trending request came from
+const (
+	LocationSourceQuery   = "query"
+	LocationSourceHeader  = "header"
+	LocationSourceIP      = "ip"
+	LocationSourceDefault = "default"
+)
+
 // Intent type constants
 const (
 	IntentTypeCategory = "category"
@@ -18,12 +34,76 @@ const (
 	EntityTypeSearch   = "search"
 	IntentTypeSource   = "source"
 	IntentTypeNearby   = "nearby"
+	// IntentTypeDateRange values are the RFC3339 start (inclusive) and end (exclusive) of the
+	// publication date window; an empty string leaves that side open
+	IntentTypeDateRange = "date_range"
+	IntentTypeSentiment = "sentiment"
+	// Exclusion intents drop articles in any of the categories, or from any of the sources
+	IntentTypeExcludeCategory = "exclude_category"
+	IntentTypeExcludeSource   = "exclude_source"
+	// IntentTypeRegion values are country, state or province names, matched against the
+	// reverse-geocoded country and region of articles
+	IntentTypeRegion = "region"
 )
 
+// Sentiment values assigned to articles during enrichment. Articles enriched before sentiment
+// classification was enabled have no sentiment.
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+)
+
+// Sentiments lists the valid sentiment values
+var Sentiments = []string{SentimentPositive, SentimentNeutral, SentimentNegative}
+
+// IsValidSentiment reports whether value is one of the Sentiment* constants
+func IsValidSentiment(value string) bool {
+	return slices.Contains(Sentiments, value)
+}
+
+// SummaryLanguages maps the ISO 639-1 codes summaries can be requested in to the language
+// names the summary prompt uses
+var SummaryLanguages = map[string]string{
+	"en": "English",
+	"hi": "Hindi",
+	"bn": "Bengali",
+	"mr": "Marathi",
+	"te": "Telugu",
+	"ta": "Tamil",
+	"gu": "Gujarati",
+	"kn": "Kannada",
+	"ml": "Malayalam",
+	"pa": "Punjabi",
+	"or": "Odia",
+	"ur": "Urdu",
+}
+
+// IsValidSummaryLang reports whether lang is one of the SummaryLanguages codes
+func IsValidSummaryLang(lang string) bool {
+	_, ok := SummaryLanguages[lang]
+	return ok
+}
+
 // Intent represents the determined purpose or retrieval strategy for a user query
 type Intent struct {
-	Type   string      `json:"type" validate:"required,oneof=category source nearby"`
+	Type   string      `json:"type" validate:"required,oneof=category source nearby score"`
 	Values interface{} `json:"values" validate:"required,min=1"`
+	// Confidence is how sure the analysis is of the intent, in [0, 1]
+	Confidence float64 `json:"confidence"`
+}
+
+// UnmarshalJSON decodes an intent, defaulting Confidence to 1 for intents encoded before it
+// existed, such as cached analyses
+func (i *Intent) UnmarshalJSON(data []byte) error {
+	type intent Intent
+	decoded := intent{Confidence: 1}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*i = Intent(decoded)
+	return nil
 }
 
 // QueryAnalysis represents the result of LLM query processing
@@ -32,20 +112,125 @@ type QueryAnalysis struct {
 	Intents  []Intent `json:"intents" validate:"required,min=1"`
 }
 
+// FilterStep is one step of the filter pipeline a query analysis is turned into: the name of
+// the filter and the parameters it is built from
+type FilterStep struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
 // Article represents a news article stored in the database
 type Article struct {
-	ID                string    `json:"id" db:"id"`
-	Title             string    `json:"title" db:"title" validate:"required"`
-	Description       string    `json:"description" db:"description"`
-	URL               string    `json:"url" db:"url" validate:"required,url"`
-	PublicationDate   time.Time `json:"publication_date" db:"publication_date" validate:"required"`
-	SourceName        string    `json:"source_name" db:"source_name" validate:"required"`
-	Category          []string  `json:"category" db:"category" validate:"required,min=1"`
-	RelevanceScore    float64   `json:"relevance_score" db:"relevance_score" validate:"required,min=0,max=1"`
-	Latitude          float64   `json:"latitude" db:"latitude" validate:"required,min=-90,max=90"`
-	Longitude         float64   `json:"longitude" db:"longitude" validate:"required,min=-180,max=180"`
-	Summary           string    `json:"summary" db:"summary"`
-	DescriptionVector []float64 `json:"-" db:"description_vector"`
+	ID                string     `json:"id" db:"id"`
+	Title             string     `json:"title" db:"title" validate:"required"`
+	Description       string     `json:"description" db:"description"`
+	URL               string     `json:"url" db:"url" validate:"required,url"`
+	CanonicalURL      string     `json:"canonical_url,omitempty" db:"canonical_url"`
+	PublicationDate   time.Time  `json:"publication_date" db:"publication_date" validate:"required"`
+	SourceName        string     `json:"source_name" db:"source_name" validate:"required"`
+	Category          []string   `json:"category" db:"category" validate:"required,min=1"`
+	RelevanceScore    float64    `json:"relevance_score" db:"relevance_score" validate:"required,min=0,max=1"`
+	Latitude          float64    `json:"latitude" db:"latitude" validate:"required,min=-90,max=90"`
+	Longitude         float64    `json:"longitude" db:"longitude" validate:"required,min=-180,max=180"`
+	Country           string     `json:"country,omitempty" db:"country"`
+	Region            string     `json:"region,omitempty" db:"region"`
+	Summary           string     `json:"summary" db:"summary"`
+	Content           string     `json:"content,omitempty" db:"content"`
+	Sentiment         *string    `json:"sentiment" db:"sentiment"`
+	DescriptionVector []float64  `json:"-" db:"description_vector"`
+	EmbeddingModel    string     `json:"-" db:"embedding_model"`
+	EmbeddedAt        *time.Time `json:"-" db:"embedded_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// RejectedCategories lists categories the taxonomy rejected when the article was ingested;
+	// an article with any fails validation. Never stored.
+	RejectedCategories []string `json:"-" db:"-"`
+}
+
+// MatchInfo explains why an article matched a query
+type MatchInfo struct {
+	// DistanceKm is set when a geographic filter was applied
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	// Similarity is the cosine similarity to the query when semantic search ran
+	Similarity        *float64 `json:"similarity,omitempty"`
+	MatchedCategories []string `json:"matched_categories,omitempty"`
+	MatchedSources    []string `json:"matched_sources,omitempty"`
+	// AlsoReportedBy lists the sources of near-duplicates collapsed into this article
+	AlsoReportedBy []string `json:"also_reported_by,omitempty"`
+	// PreferenceBoost is the factor the user's category preferences scaled the article's
+	// relevance by when ranking; omitted when they left it unchanged
+	PreferenceBoost *float64 `json:"preference_boost,omitempty"`
+}
+
+// EnrichedArticle is an Article annotated with match metadata and its rank in the result set
+type EnrichedArticle struct {
+	Article
+	MatchInfo
+	Rank int `json:"rank"`
+	// SummaryLang is the language code of Summary, set when the query asked for a summary language
+	SummaryLang string `json:"summary_lang,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler for EnrichedArticle. Without it the promoted
+// Article.UnmarshalJSON would silently drop the match metadata, rank and summary language.
+func (e *EnrichedArticle) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.Article); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &e.MatchInfo); err != nil {
+		return err
+	}
+
+	var aux struct {
+		Rank        int    `json:"rank"`
+		SummaryLang string `json:"summary_lang"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	e.Rank = aux.Rank
+	e.SummaryLang = aux.SummaryLang
+
+	return nil
+}
+
+// TrendingArticle is an Article annotated with what its position in the trending list was
+// computed from
+type TrendingArticle struct {
+	Article
+	// TrendingScore is the score the article was ranked by, between 0 and 1
+	TrendingScore float64 `json:"trending_score"`
+	// Rank is the 1-based position in the trending list
+	Rank int `json:"rank"`
+	// EventCounts counts the article's user events in the scoring window by event type
+	EventCounts map[string]int `json:"event_counts"`
+	// DistanceKm is the distance from the requested location
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler for TrendingArticle. Without it the promoted
+// Article.UnmarshalJSON would silently drop the trending fields of cached articles.
+func (t *TrendingArticle) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Article); err != nil {
+		return err
+	}
+
+	var aux struct {
+		TrendingScore float64        `json:"trending_score"`
+		Rank          int            `json:"rank"`
+		EventCounts   map[string]int `json:"event_counts"`
+		DistanceKm    float64        `json:"distance_km"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	t.TrendingScore = aux.TrendingScore
+	t.Rank = aux.Rank
+	t.EventCounts = aux.EventCounts
+	t.DistanceKm = aux.DistanceKm
+
+	return nil
 }
 
 // UnmarshalJSON implements json.Unmarshaler for Article
@@ -63,9 +248,14 @@ func (a *Article) UnmarshalJSON(data []byte) error {
 	}
 
 	if aux.PublicationDate != "" {
+		// Seed files use a zone-less layout; articles marshalled by this service (e.g. into a
+		// cache) carry RFC 3339
 		parsedTime, err := time.Parse("2006-01-02T15:04:05", aux.PublicationDate)
 		if err != nil {
-			return err
+			var rfcErr error
+			if parsedTime, rfcErr = time.Parse(time.RFC3339Nano, aux.PublicationDate); rfcErr != nil {
+				return err
+			}
 		}
 		a.PublicationDate = parsedTime
 	}
@@ -73,15 +263,272 @@ func (a *Article) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Job status constants
+const (
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// TrendingTopic is a topic shared by trending articles. Score is the sum of the trending
+// scores of the articles mentioning it; ArticleIDs lists the highest scoring of them.
+type TrendingTopic struct {
+	Name       string   `json:"name"`
+	Score      float64  `json:"score"`
+	ArticleIDs []string `json:"article_ids"`
+}
+
+// Job represents the progress of a long-running background operation such as a backfill
+type Job struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"`
+	Processed  int        `json:"processed"`
+	Succeeded  int        `json:"succeeded"`
+	Failed     int        `json:"failed"`
+	LastID     string     `json:"last_id,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// User event types
+const (
+	EventTypeView       = "view"
+	EventTypeClick      = "click"
+	EventTypeShare      = "share"
+	EventTypeBookmark   = "bookmark"
+	EventTypeUnbookmark = "unbookmark"
+	EventTypeDismiss    = "dismiss"
+)
+
+// EventTypes lists every accepted user event type
+var EventTypes = []string{
+	EventTypeView,
+	EventTypeClick,
+	EventTypeShare,
+	EventTypeBookmark,
+	EventTypeUnbookmark,
+	EventTypeDismiss,
+}
+
+// EventTypeWeights is how much a single event of each type contributes to an article's
+// trending volume. Dismissals count against the article, and removing a bookmark takes back
+// what the bookmark added.
+var EventTypeWeights = map[string]float64{
+	EventTypeView:       1,
+	EventTypeClick:      2,
+	EventTypeShare:      4,
+	EventTypeBookmark:   3,
+	EventTypeUnbookmark: -3,
+	EventTypeDismiss:    -1,
+}
+
+// IsValidEventType reports whether eventType is one of EventTypes
+func IsValidEventType(eventType string) bool {
+	return slices.Contains(EventTypes, eventType)
+}
+
+// Cache flush scopes
+const (
+	CacheScopeTrending   = "trending"
+	CacheScopeFilters    = "filters"
+	CacheScopeSources    = "sources"
+	CacheScopeEmbeddings = "embeddings"
+	CacheScopeAll        = "all"
+)
+
+// CacheScopes lists the flushable cache scopes, in the order CacheScopeAll flushes them
+var CacheScopes = []string{CacheScopeTrending, CacheScopeFilters, CacheScopeSources, CacheScopeEmbeddings}
+
+// IsValidCacheScope reports whether scope is one of CacheScopes or CacheScopeAll
+func IsValidCacheScope(scope string) bool {
+	return scope == CacheScopeAll || slices.Contains(CacheScopes, scope)
+}
+
+// RetentionRun describes one run of the user event retention task
+type RetentionRun struct {
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Cutoff     time.Time  `json:"cutoff"`
+	Deleted    int64      `json:"deleted"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// ArchiveRun describes one run of the article archive job. A dry run only counts the articles
+// it would move, in WouldArchive.
+type ArchiveRun struct {
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	Cutoff       time.Time  `json:"cutoff"`
+	DryRun       bool       `json:"dry_run"`
+	Archived     int64      `json:"archived"`
+	WouldArchive int64      `json:"would_archive,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// ClusterRun describes one run of the story clustering job
+type ClusterRun struct {
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Clustered is the number of new articles assigned, NewClusters how many clusters they started
+	Clustered   int    `json:"clustered"`
+	NewClusters int    `json:"new_clusters"`
+	Pruned      int64  `json:"pruned"`
+	Error       string `json:"error,omitempty"`
+}
+
+// StoryCluster is a group of recent articles about the same story
+type StoryCluster struct {
+	ID string `json:"id"`
+	// MemberCount counts the members published in the requested period
+	MemberCount int       `json:"member_count"`
+	LatestAt    time.Time `json:"latest_at"`
+	// Representative is the member with the highest relevance score
+	Representative Article `json:"representative"`
+}
+
+// VectorIndexSettings describes how a vector index is built and queried
+type VectorIndexSettings struct {
+	Method string `json:"method"`
+	// Options are the build parameters, e.g. m and ef_construction for HNSW
+	Options map[string]string `json:"options"`
+	// SearchOptions are the per-query settings, e.g. hnsw.ef_search
+	SearchOptions map[string]string `json:"search_options,omitempty"`
+}
+
+// VectorIndexStatus reports the state of the approximate nearest-neighbor index on article
+// embeddings
+type VectorIndexStatus struct {
+	Name   string `json:"name"`
+	Exists bool   `json:"exists"`
+	// Valid is false while a concurrent build is in progress or after one failed
+	Valid      bool                 `json:"valid"`
+	Current    *VectorIndexSettings `json:"current,omitempty"`
+	Configured VectorIndexSettings  `json:"configured"`
+	// MatchesConfig is false when the index is missing or was built with other settings
+	MatchesConfig bool   `json:"matches_config"`
+	SizeBytes     int64  `json:"size_bytes"`
+	Definition    string `json:"definition,omitempty"`
+}
+
+// WebhookDeliveryResult is the outcome of a test delivery to one webhook target
+type WebhookDeliveryResult struct {
+	Target     string `json:"target"`
+	Enabled    bool   `json:"enabled"`
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
 // UserEvent represents a user interaction with an article
 type UserEvent struct {
 	ID        string    `json:"id" db:"id"`
 	UserID    string    `json:"user_id" db:"user_id" validate:"required"`
 	ArticleID string    `json:"article_id" db:"article_id" validate:"required"`
-	EventType string    `json:"event_type" db:"event_type" validate:"required,oneof=view click"`
+	EventType string    `json:"event_type" db:"event_type" validate:"required,oneof=view click share bookmark unbookmark dismiss"`
 	Timestamp time.Time `json:"timestamp" db:"timestamp" validate:"required"`
 	Latitude  float64   `json:"latitude" db:"latitude" validate:"required,min=-90,max=90"`
 	Longitude float64   `json:"longitude" db:"longitude" validate:"required,min=-180,max=180"`
+	Value     *float64  `json:"value,omitempty" db:"value" validate:"omitempty,min=0"` // e.g. dwell time in seconds
+	// Experiments holds the experiment variants the user was assigned when the event was recorded
+	Experiments map[string]string `json:"experiments,omitempty" db:"experiments" gorm:"serializer:json"`
+}
+
+// UserPreferences are the category weights a user set to bias query ranking, keyed by
+// lowercased category. Weights range from -1 (less) to 1 (more); unlisted categories are neutral.
+type UserPreferences struct {
+	UserID     string             `json:"user_id"`
+	Categories map[string]float64 `json:"categories"`
+}
+
+// BookmarkedArticle is an article in a user's bookmarks with the time it was last bookmarked
+type BookmarkedArticle struct {
+	Article      Article   `json:"article"`
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+}
+
+// ArticleStats summarizes user engagement with a single article
+type ArticleStats struct {
+	ArticleID   string            `json:"article_id"`
+	Views       int               `json:"views"`
+	Clicks      int               `json:"clicks"`
+	UniqueUsers int               `json:"unique_users"`
+	Daily       []DailyEventCount `json:"daily"`
+}
+
+// DailyEventCount holds the per-type event counts for one day
+type DailyEventCount struct {
+	Date   string `json:"date"`
+	Views  int    `json:"views"`
+	Clicks int    `json:"clicks"`
+}
+
+// CorpusStats summarizes the stored (not deleted) articles
+type CorpusStats struct {
+	TotalArticles int64 `json:"total_articles"`
+	// SummarizedArticles and EmbeddedArticles count articles with a summary and an embedding;
+	// the percentages are of TotalArticles, rounded to two decimals, and 0 for an empty corpus
+	SummarizedArticles int64               `json:"summarized_articles"`
+	EmbeddedArticles   int64               `json:"embedded_articles"`
+	SummaryPercent     float64             `json:"summary_percent"`
+	EmbeddingPercent   float64             `json:"embedding_percent"`
+	Categories         []CorpusCount       `json:"categories"`
+	Sources            []CorpusCount       `json:"sources"`
+	DailyAdded         []DailyArticleCount `json:"daily_added"`
+	GeneratedAt        time.Time           `json:"generated_at"`
+}
+
+// CorpusCount is the number of articles sharing a category or source
+type CorpusCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// DailyArticleCount is the number of articles added on one day
+type DailyArticleCount struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// Interaction stats bucket sizes
+const (
+	StatsBucketHour = "hour"
+	StatsBucketDay  = "day"
+)
+
+// InteractionStats summarizes user events recorded in a time window
+type InteractionStats struct {
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Bucket string    `json:"bucket"`
+	// Series holds one entry per bucket in the window, oldest first
+	Series      []InteractionBucket `json:"series"`
+	TopArticles []ArticleEngagement `json:"top_articles"`
+	TopSources  []SourceEngagement  `json:"top_sources"`
+}
+
+// InteractionBucket holds the event counts of one bucket, keyed by every event type
+type InteractionBucket struct {
+	Start  time.Time        `json:"start"`
+	Counts map[string]int64 `json:"counts"`
+}
+
+// ArticleEngagement is an article's event count and weighted engagement (see
+// EventTypeWeights) in a window
+type ArticleEngagement struct {
+	ArticleID  string  `json:"article_id"`
+	Title      string  `json:"title"`
+	Events     int64   `json:"events"`
+	Engagement float64 `json:"engagement"`
+}
+
+// SourceEngagement is a source's event count and weighted engagement in a window
+type SourceEngagement struct {
+	Source     string  `json:"source"`
+	Events     int64   `json:"events"`
+	Engagement float64 `json:"engagement"`
 }
 
 // GetLocation returns the Location for a UserEvent
@@ -119,3 +566,159 @@ func (qa *QueryAnalysis) GetIntent(intentType string) *Intent {
 	}
 	return nil
 }
+
+// LLMTokenUsage holds token counts for one kind of LLM operation
+type LLMTokenUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TotalTokens      int64 `json:"total_tokens"`
+}
+
+// LLMUsage reports the LLM tokens spent on one UTC day, per operation, against the daily budget
+type LLMUsage struct {
+	Date        string                   `json:"date"`
+	Operations  map[string]LLMTokenUsage `json:"operations"`
+	TotalTokens int64                    `json:"total_tokens"`
+	// DailyBudget is the configured token budget; 0 means unlimited
+	DailyBudget    int64 `json:"daily_budget"`
+	BudgetExceeded bool  `json:"budget_exceeded"`
+}
+
+// LLM health probe statuses
+const (
+	LLMReachable   = "reachable"
+	LLMUnreachable = "unreachable"
+)
+
+// LLMHealth is the outcome of the latest LLM reachability probe
+type LLMHealth struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	LatencyMs int64     `json:"latency_ms"`
+	// Error says why the probe failed; empty when the API was reachable
+	Error string `json:"error,omitempty"`
+}
+
+// LLMAuditRecord is one sampled query analysis call, kept for offline prompt evaluation. The
+// query is stored verbatim.
+type LLMAuditRecord struct {
+	ID    string `json:"id"`
+	Query string `json:"query"`
+	// SourcesHash and CategoriesHash identify the allowed lists offered to the model
+	SourcesHash    string `json:"sources_hash"`
+	CategoriesHash string `json:"categories_hash"`
+	// RawOutput is the model's last response; Attempts counts the calls made for the query
+	RawOutput string `json:"raw_output"`
+	Attempts  int    `json:"attempts"`
+	// Analysis is the parsed result, nil when the call failed
+	Analysis         *QueryAnalysis `json:"analysis,omitempty"`
+	Error            string         `json:"error,omitempty"`
+	LatencyMs        int64          `json:"latency_ms"`
+	PromptTokens     int            `json:"prompt_tokens"`
+	CompletionTokens int            `json:"completion_tokens"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
+// QueryLogEntry is one recorded /news/query request
+type QueryLogEntry struct {
+	// Tenant is the tenant the query was made for
+	Tenant string
+	// Query is the normalized query, before spelling correction
+	Query       string
+	HadLocation bool
+	ResultCount int
+	LatencyMs   int64
+	// ClientHash is the salted hash of the user id, or of the client IP without one
+	ClientHash string
+	CreatedAt  time.Time
+}
+
+// QueryCount is how often a query was searched in a period. Queries are compared ignoring case.
+type QueryCount struct {
+	Query string `json:"query"`
+	Count int64  `json:"count"`
+	// Clients is the number of distinct clients that searched it
+	Clients int64 `json:"clients"`
+	// AvgResults is the mean result count
+	AvgResults float64   `json:"avg_results"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// SourceAlias maps a source name used in queries, e.g. "ANI", to the source names stored on
+// articles, e.g. "ANI English" and "Asian News International". Aliases are matched ignoring case.
+type SourceAlias struct {
+	Alias       string    `json:"alias"`
+	SourceNames []string  `json:"source_names"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Category is an entry of the canonical category taxonomy. Incoming categories equal to its
+// name or one of its aliases, ignoring case, are stored as Name.
+type Category struct {
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Policies for incoming categories missing from the taxonomy
+const (
+	UnknownCategoryKeep   = "keep"
+	UnknownCategoryReject = "reject"
+	UnknownCategoryBucket = "bucket"
+)
+
+// Export run modes
+const (
+	ExportModeFull        = "full"
+	ExportModeIncremental = "incremental"
+)
+
+// Export run statuses
+const (
+	ExportStatusRunning   = "running"
+	ExportStatusSucceeded = "succeeded"
+	ExportStatusFailed    = "failed"
+)
+
+// ExportRun records one snapshot of the article corpus uploaded to object storage
+type ExportRun struct {
+	ID             string `json:"id"`
+	Mode           string `json:"mode"`
+	IncludeVectors bool   `json:"include_vectors"`
+	Status         string `json:"status"`
+	ObjectKey      string `json:"object_key"`
+	// Since is the created_at after which articles were exported; nil for a full snapshot
+	Since *time.Time `json:"since,omitempty"`
+	// Watermark is the latest created_at exported, where the next incremental snapshot starts
+	Watermark  *time.Time `json:"watermark,omitempty"`
+	Rows       int64      `json:"rows"`
+	Bytes      int64      `json:"bytes"`
+	DurationMs int64      `json:"duration_ms"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// SavedSearch is a search a user is alerted about when new matching articles are stored.
+// Category and Source optionally narrow the articles considered.
+type SavedSearch struct {
+	ID       string   `json:"id"`
+	UserID   string   `json:"user_id"`
+	Query    string   `json:"query"`
+	Category []string `json:"category"`
+	Source   []string `json:"source"`
+	// Embedded is false when the query could not be embedded; only keyword matches are found then
+	Embedded  bool      `json:"embedded"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SavedSearchMatch is a new article that matched a saved search. Similarity is the cosine
+// similarity of the article to the query, when both are embedded with the same model.
+type SavedSearchMatch struct {
+	Article      Article   `json:"article"`
+	Similarity   *float64  `json:"similarity,omitempty"`
+	KeywordMatch bool      `json:"keyword_match"`
+	MatchedAt    time.Time `json:"matched_at"`
+}
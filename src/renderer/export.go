@@ -0,0 +1,111 @@
+package renderer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-inshorts/src/models"
+)
+
+// Export formats
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportCSVHeader is the header row of CSV exports
+var exportCSVHeader = []string{
+	"id", "title", "description", "url", "publication_date", "source_name",
+	"category", "relevance_score", "latitude", "longitude", "summary",
+}
+
+// ArticleWriter encodes articles one at a time onto an underlying writer
+type ArticleWriter interface {
+	Write(article models.Article) error
+	// Flush writes any buffered data; call it once after the last article
+	Flush() error
+}
+
+// NewArticleWriter returns an ArticleWriter for format, one of the Export* constants
+func NewArticleWriter(format string, w io.Writer) (ArticleWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvArticleWriter{w: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonArticleWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ExportContentType returns the Content-Type for an export format
+func ExportContentType(format string) string {
+	if format == ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// csvArticleWriter writes articles as CSV rows. Categories are joined with "|" so each
+// article stays on a single row.
+type csvArticleWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// Write implements ArticleWriter
+func (cw *csvArticleWriter) Write(article models.Article) error {
+	if !cw.headerWritten {
+		if err := cw.w.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		cw.headerWritten = true
+	}
+
+	return cw.w.Write([]string{
+		article.ID,
+		article.Title,
+		article.Description,
+		article.URL,
+		article.PublicationDate.Format(time.RFC3339),
+		article.SourceName,
+		strings.Join(article.Category, "|"),
+		strconv.FormatFloat(article.RelevanceScore, 'f', -1, 64),
+		strconv.FormatFloat(article.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(article.Longitude, 'f', -1, 64),
+		article.Summary,
+	})
+}
+
+// Flush implements ArticleWriter. An export without articles still gets its header row.
+func (cw *csvArticleWriter) Flush() error {
+	if !cw.headerWritten {
+		if err := cw.w.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		cw.headerWritten = true
+	}
+
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// ndjsonArticleWriter writes one JSON object per line
+type ndjsonArticleWriter struct {
+	enc *json.Encoder
+}
+
+// Write implements ArticleWriter
+func (nw *ndjsonArticleWriter) Write(article models.Article) error {
+	return nw.enc.Encode(article)
+}
+
+// Flush implements ArticleWriter; the encoder does not buffer
+func (nw *ndjsonArticleWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package renderer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"news-inshorts/src/models"
+)
+
+// RSSContentType is the Content-Type of rendered RSS feeds
+const RSSContentType = "application/rss+xml; charset=utf-8"
+
+// FeedChannel describes the channel an RSS feed is published under
+type FeedChannel struct {
+	Title       string
+	Link        string
+	Description string
+}
+
+// rssDocument is the root <rss> element of an RSS 2.0 document
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel is the <channel> element
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem is an <item> element describing one article
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	Categories  []string `xml:"category"`
+}
+
+// rssGUID is the <guid> element; article ids are not URLs, so isPermaLink is false
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// RenderRSS renders articles as an RSS 2.0 document. Text is escaped by the XML encoder, so
+// titles containing & or < are safe. The article summary is used as the item description,
+// falling back to the full description for articles that have not been summarized yet.
+func RenderRSS(channel FeedChannel, articles []models.Article, builtAt time.Time) ([]byte, error) {
+	items := make([]rssItem, 0, len(articles))
+	for _, article := range articles {
+		description := article.Summary
+		if description == "" {
+			description = article.Description
+		}
+
+		item := rssItem{
+			Title:       article.Title,
+			Link:        article.URL,
+			Description: description,
+			GUID:        rssGUID{Value: article.ID},
+			Categories:  article.Category,
+		}
+		if !article.PublicationDate.IsZero() {
+			item.PubDate = article.PublicationDate.Format(time.RFC1123Z)
+		}
+
+		items = append(items, item)
+	}
+
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         channel.Title,
+			Link:          channel.Link,
+			Description:   channel.Description,
+			LastBuildDate: builtAt.Format(time.RFC1123Z),
+			Items:         items,
+		},
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal RSS feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
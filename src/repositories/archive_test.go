@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"news-inshorts/src/models"
+)
+
+// TestArticleRemovalCoversArchive checks that deleting, restoring and purging an article also
+// reach articles_archive, so archived articles are not left behind in trending and bookmarks
+func TestArticleRemovalCoversArchive(t *testing.T) {
+	tests := []struct {
+		name           string
+		call           func(ctx context.Context, r *testRepositories) error
+		wantStatements []string
+	}{
+		{
+			name:           "SoftDelete",
+			call:           func(ctx context.Context, r *testRepositories) error { return r.article.SoftDelete(ctx, testArticleID) },
+			wantStatements: []string{"UPDATE articles\n", "UPDATE articles_archive\n"},
+		},
+		{
+			name: "Restore",
+			call: func(ctx context.Context, r *testRepositories) error {
+				return r.article.WithDeleted().Restore(ctx, testArticleID)
+			},
+			wantStatements: []string{"UPDATE articles\n", "UPDATE articles_archive\n"},
+		},
+		{
+			name: "Purge",
+			call: func(ctx context.Context, r *testRepositories) error {
+				return r.article.WithDeleted().Purge(ctx, testArticleID)
+			},
+			wantStatements: []string{"DELETE FROM articles WHERE", "DELETE FROM articles_archive WHERE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, rec := newTestRepositories(t)
+
+			// The recording database matches no rows, as for an unknown id
+			if err := tt.call(context.Background(), repos); err != ErrArticleNotFound {
+				t.Fatalf("err = %v, want ErrArticleNotFound", err)
+			}
+
+			for _, want := range tt.wantStatements {
+				found := false
+				for _, stmt := range rec.Statements() {
+					if strings.Contains(stmt.Query, want) && stmt.Args[0] == testArticleID {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("no statement containing %q for the article: %v", want, rec.Statements())
+				}
+			}
+		})
+	}
+}
+
+// TestInsertChecksArchivedCanonicalURLs checks that inserts look the canonical URL up in
+// articles_archive before inserting, since its unique index only covers articles
+func TestInsertChecksArchivedCanonicalURLs(t *testing.T) {
+	const canonicalURL = "https://example.com/storm"
+	article := models.Article{
+		Title:           "Storm",
+		URL:             canonicalURL + "?utm_source=feed",
+		CanonicalURL:    canonicalURL,
+		SourceName:      "Example",
+		Category:        []string{"world"},
+		PublicationDate: time.Now(),
+	}
+
+	tests := []struct {
+		name string
+		call func(r *testRepositories)
+	}{
+		{"Insert", func(r *testRepositories) {
+			a := article
+			_ = r.article.Insert(context.Background(), &a)
+		}},
+		{"BulkInsert", func(r *testRepositories) {
+			_, _ = r.article.BulkInsert(context.Background(), []models.Article{article}, 0)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, rec := newTestRepositories(t)
+			tt.call(repos)
+
+			checked := false
+			for _, stmt := range rec.Statements() {
+				if strings.Contains(stmt.Query, "INSERT INTO articles") {
+					if !checked {
+						t.Fatalf("inserted before checking articles_archive: %v", rec.Statements())
+					}
+					return
+				}
+				if strings.Contains(stmt.Query, "FROM articles_archive WHERE canonical_url = ANY(") {
+					assertTenantBound(t, []recordedStatement{stmt}, testDefaultTenant)
+					checked = true
+				}
+			}
+			t.Fatalf("no insert into articles was sent: %v", rec.Statements())
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package repositories
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"news-inshorts/src/infra"
 	"news-inshorts/src/models"
@@ -16,72 +21,275 @@ import (
 
 // LoadStats represents statistics from loading articles
 type LoadStats struct {
-	TotalArticles    int      `json:"total_articles"`
-	SuccessCount     int      `json:"success_count"`
-	ErrorCount       int      `json:"error_count"`
-	ValidationErrors []string `json:"validation_errors,omitempty"`
+	TotalArticles    int                     `json:"total_articles"`
+	SuccessCount     int                     `json:"success_count"`
+	ErrorCount       int                     `json:"error_count"`
+	ValidationErrors []types.ValidationError `json:"validation_errors,omitempty"`
+	// ContentFetchFailures counts articles whose page could not be fetched or yielded no
+	// readable text; they were enriched from their description alone
+	ContentFetchFailures int `json:"content_fetch_failures,omitempty"`
+	// EnrichmentFailures lists ids of articles stored without a summary or embedding because
+	// the LLM call failed; the backfill job can target them later
+	EnrichmentFailures []string `json:"enrichment_failures,omitempty"`
+	// StoredIDs lists ids of articles whose insert succeeded, in input order
+	StoredIDs []string `json:"-"`
+	// DuplicateURLs lists URLs skipped (or that a dry run would skip) because their canonical
+	// form is already stored or repeated in the input
+	DuplicateURLs []string `json:"duplicate_urls,omitempty"`
+	// SuspectedDuplicates lists articles skipped because their title closely matches a stored
+	// article's; only set when title duplicate detection was requested
+	SuspectedDuplicates []types.SuspectedDuplicate `json:"suspected_duplicates,omitempty"`
+	// FailedChunks lists chunks given up on after their transaction kept failing with a
+	// serialization failure or deadlock; their articles are counted in ErrorCount
+	FailedChunks []types.FailedChunk `json:"failed_chunks,omitempty"`
+	// Dry-run results: articles that would be stored, and articles that would be skipped
+	// because they are invalid or a duplicate
+	DryRun      bool `json:"dry_run,omitempty"`
+	WouldInsert int  `json:"would_insert,omitempty"`
+	WouldSkip   int  `json:"would_skip,omitempty"`
+}
+
+// ErrArticleNotFound is returned when an article does not exist (or is already in the requested state)
+var ErrArticleNotFound = errors.New("article not found")
+
+// ErrDuplicateArticle is returned when an article with the same canonical URL is already stored
+var ErrDuplicateArticle = errors.New("article with this url already exists")
+
+// MissingEnrichment describes an article lacking a summary, an embedding and/or a sentiment
+type MissingEnrichment struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Summary      string `json:"summary"`
+	HasEmbedding bool   `json:"has_embedding"`
+	HasSentiment bool   `json:"has_sentiment"`
+}
+
+// TextSearchFilters narrows a text search; zero values leave the corresponding filter unset
+type TextSearchFilters struct {
+	Category string `json:"category,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+// VectorNeighbor is an article found by nearest-neighbor search, with its cosine similarity
+// to the query vector
+type VectorNeighbor struct {
+	ID         string  `json:"id"`
+	Similarity float64 `json:"similarity"`
+}
+
+// ArticlePair is a pair of article ids
+type ArticlePair struct {
+	FirstID  string `json:"first_id"`
+	SecondID string `json:"second_id"`
+}
+
+// TitleMatch is a stored article whose title is similar to the title at Index in the input
+type TitleMatch struct {
+	Index      int     `json:"index"`
+	ArticleID  string  `json:"article_id"`
+	Similarity float64 `json:"similarity"`
+}
+
+// SnapshotArticle is an article as written to corpus snapshots
+type SnapshotArticle struct {
+	models.Article
+	// Embedding is the description vector as a JSON array; only read when vectors are requested
+	Embedding json.RawMessage `json:"embedding,omitempty" gorm:"column:embedding"`
 }
 
 // ArticleRepository defines the interface for article data access
 type ArticleRepository interface {
-	BulkInsert(articles []models.Article) (*LoadStats, error)
-	Insert(article *models.Article) error
-	FindAll() ([]models.Article, error)
-	SearchByText(query []string) ([]models.Article, error)
-	FilterArticles(params types.FilterArticlesRequest) ([]models.Article, error)
-	FindByIDs(ids []string) ([]models.Article, error)
-	GetDistinctSourceNames() ([]string, error)
-	GetDistinctCategories() ([]string, error)
+	// BulkInsert stores articles. With a positive titleThreshold, articles whose title is at
+	// least that trigram-similar to a stored title are reported as suspected duplicates instead.
+	BulkInsert(ctx context.Context, articles []models.Article, titleThreshold float64) (*LoadStats, error)
+	DryRunBulkInsert(ctx context.Context, articles []models.Article, titleThreshold float64) (*LoadStats, error)
+	Insert(ctx context.Context, article *models.Article) error
+	// FindPage returns up to limit articles ordered by publication_date and id, newest first,
+	// starting after cursor (the first page when empty), and the cursor of the next page,
+	// empty on the last one. A malformed cursor fails with ErrInvalidCursor.
+	FindPage(ctx context.Context, cursor string, limit int) ([]models.Article, string, error)
+	SearchByText(ctx context.Context, query []string) ([]models.Article, error)
+	SearchByTextFiltered(ctx context.Context, query []string, filters TextSearchFilters) ([]models.Article, error)
+	NearestByVector(ctx context.Context, vector []float64, limit int, minSimilarity float64) ([]VectorNeighbor, error)
+	SimilarityByIDs(ctx context.Context, vector []float64, ids []string) ([]VectorNeighbor, error)
+	SimilarPairs(ctx context.Context, ids []string, minSimilarity float64) ([]ArticlePair, error)
+	FindSimilarTitles(ctx context.Context, titles []string, threshold float64) ([]TitleMatch, error)
+	FilterArticles(ctx context.Context, params types.FilterArticlesRequest) ([]models.Article, error)
+	CountFilteredArticles(ctx context.Context, params types.FilterArticlesRequest) (int64, error)
+	StreamFilteredArticles(ctx context.Context, params types.FilterArticlesRequest, limit int, fn func(models.Article) error) error
+	FindByIDs(ctx context.Context, ids []string) ([]models.Article, error)
+	// FindByIDsPublishedSince is FindByIDs restricted to articles published at or after since
+	FindByIDsPublishedSince(ctx context.Context, ids []string, since time.Time) ([]models.Article, error)
+	// FindByID returns a live (not archived) article including its fetched content, failing
+	// with ErrArticleNotFound when there is none
+	FindByID(ctx context.Context, id string) (*models.Article, error)
+	Exists(ctx context.Context, id string) (bool, error)
+	// GetDistinctSourceNames and GetDistinctCategories return the source names and categories
+	// in use, most articles first
+	GetDistinctSourceNames(ctx context.Context) ([]string, error)
+	GetDistinctCategories(ctx context.Context) ([]string, error)
+	// GetFrequentTitleTerms returns up to limit lowercased words of at least four letters that
+	// appear in at least minCount titles, most frequent first
+	GetFrequentTitleTerms(ctx context.Context, minCount, limit int) ([]string, error)
+	SoftDelete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) error
+	WithDeleted() ArticleRepository
+	FindMissingEnrichment(ctx context.Context, afterID string, limit int, includeSentiment bool) ([]MissingEnrichment, error)
+	UpdateEnrichment(ctx context.Context, id string, summary string, vector []float64, embeddingModel string, sentiment string) error
+	// UpdateSummary replaces the summary of an article, failing with ErrArticleNotFound when it
+	// does not exist or is deleted
+	UpdateSummary(ctx context.Context, id string, summary string) error
+	// FeedRelevanceScores returns, per article, the relevance score supplied by the feed
+	FeedRelevanceScores(ctx context.Context) (map[string]float64, error)
+	// UpdateRelevanceScores sets relevance scores by article id and returns how many changed
+	UpdateRelevanceScores(ctx context.Context, scores map[string]float64) (int64, error)
+	// ArchiveOlderThan moves up to batchSize articles published before cutoff, oldest first,
+	// to articles_archive and returns how many were moved
+	ArchiveOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int64, error)
+	// CountOlderThan returns how many articles published before cutoff ArchiveOlderThan would move
+	CountOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+	// ReplaceCategories rewrites up to batchSize articles, live ones before archived ones, that
+	// have a category equal to one of names ignoring case but not to category itself, replacing
+	// those categories with category. It returns how many articles were rewritten.
+	ReplaceCategories(ctx context.Context, names []string, category string, batchSize int) (int64, error)
+	// StreamSnapshot hands every stored article, archived ones included, created after the
+	// given time (every article when it is zero) to fn in created_at order
+	StreamSnapshot(ctx context.Context, after time.Time, includeVectors bool, fn func(SnapshotArticle) error) error
+	// GetCorpusStats aggregates the stored articles: totals, counts per category and source,
+	// and articles added per UTC day since the given time (days without articles are omitted)
+	GetCorpusStats(ctx context.Context, since time.Time) (*models.CorpusStats, error)
 }
 
 // articleRepository implements ArticleRepository
 type articleRepository struct {
-	db  *gorm.DB
-	log infra.Logger
+	db             *gorm.DB
+	vectorCfg      *infra.VectorConfig
+	sourceAliases  SourceAliasRepository
+	defaultTenant  string
+	log            infra.Logger
+	includeDeleted bool
 }
 
-// NewArticleRepository creates a new instance of ArticleRepository
-func NewArticleRepository(db *gorm.DB) ArticleRepository {
+// NewArticleRepository creates a new instance of ArticleRepository. Source filters are
+// expanded through sourceAliases. Queries are scoped to the tenant on their context, or to
+// defaultTenant when it carries none.
+func NewArticleRepository(db *gorm.DB, vectorCfg *infra.VectorConfig, sourceAliases SourceAliasRepository, defaultTenant string, logger infra.Logger) ArticleRepository {
 	return &articleRepository{
-		db:  db,
-		log: infra.GetLogger(),
+		db:            db,
+		vectorCfg:     vectorCfg,
+		sourceAliases: sourceAliases,
+		defaultTenant: defaultTenant,
+		log:           logger,
 	}
 }
 
-// FindAll retrieves all articles from the database
-func (r *articleRepository) FindAll() ([]models.Article, error) {
-	query := `
-		SELECT
-			id,
-			title,
-			description,
-			url,
-			publication_date,
-			source_name,
-			category,
-			relevance_score,
-			latitude,
-			longitude
+// WithDeleted returns a copy of the repository whose queries also return soft-deleted articles.
+// Intended for admin use only.
+func (r *articleRepository) WithDeleted() ArticleRepository {
+	return &articleRepository{
+		db:             r.db,
+		vectorCfg:      r.vectorCfg,
+		sourceAliases:  r.sourceAliases,
+		defaultTenant:  r.defaultTenant,
+		log:            r.log,
+		includeDeleted: true,
+	}
+}
+
+// notDeletedCondition returns the SQL condition excluding soft-deleted rows,
+// or "TRUE" when the repository was created via WithDeleted
+func (r *articleRepository) notDeletedCondition() string {
+	if r.includeDeleted {
+		return "TRUE"
+	}
+	return "deleted_at IS NULL"
+}
+
+// tenant returns the tenant the queries made with ctx are scoped to
+func (r *articleRepository) tenant(ctx context.Context) string {
+	return infra.TenantFromContext(ctx, r.defaultTenant)
+}
+
+// tenantCondition returns the SQL condition keeping the rows of the tenant of ctx, along with
+// the argument to bind to its placeholder
+func (r *articleRepository) tenantCondition(ctx context.Context) (string, string) {
+	return tenantCondition("tenant_id", r.tenant(ctx))
+}
+
+// FindPage returns the next page of articles, newest publication first
+func (r *articleRepository) FindPage(ctx context.Context, cursor string, limit int) ([]models.Article, string, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	conditions := []string{tenant, r.notDeletedCondition()}
+	args := []interface{}{tenantArg}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		// (publication_date, id) compares row-wise, so articles published at the same instant
+		// as the cursor's are split by id rather than repeated or skipped
+		conditions = append(conditions, "(publication_date, id) < (?, ?::uuid)")
+		args = append(args, after.At, after.ID)
+	}
+
+	// One extra row tells whether another page follows
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM articles
-		ORDER BY publication_date DESC
-	`
+		WHERE %s
+		ORDER BY publication_date DESC, id DESC
+		LIMIT %d
+	`, articleReadColumns, strings.Join(conditions, " AND "), limit+1)
 
 	var articles []models.Article
-	if err := r.db.Raw(query).Scan(&articles).Error; err != nil {
-		r.log.Error("Failed to query all articles", err, nil)
-		return nil, fmt.Errorf("failed to query articles: %w", err)
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&articles).Error; err != nil {
+		r.log.Error("Failed to query article page", err, map[string]interface{}{
+			"cursor": cursor,
+			"limit":  limit,
+		})
+		return nil, "", fmt.Errorf("failed to query articles: %w", wrapDBError(err))
 	}
 
-	r.log.Info("Retrieved all articles", map[string]interface{}{
-		"count": len(articles),
-	})
+	if len(articles) <= limit {
+		return articles, "", nil
+	}
 
-	return articles, nil
+	articles = articles[:limit]
+	last := articles[limit-1]
+	return articles, encodeCursor(pageCursor{At: last.PublicationDate, ID: last.ID}), nil
 }
 
-// FilterArticles filters articles based on category, source, and/or location
-func (r *articleRepository) FilterArticles(params types.FilterArticlesRequest) ([]models.Article, error) {
-	query := `
+// archivedColumns lists the articles columns moved to articles_archive, which has the same
+// columns plus archived_at
+const archivedColumns = `id, title, description, url, canonical_url, publication_date, source_name,
+	category, relevance_score, feed_relevance_score, latitude, longitude, country, region,
+	created_at, updated_at, summary, content, description_vector, embedding_model, embedded_at,
+	deleted_at, sentiment, tenant_id`
+
+// articleReadColumns lists the columns read back by the filter and id queries, which are all
+// those needed when they also read articles_archive
+const articleReadColumns = `id, title, description, url, canonical_url, publication_date,
+	source_name, category, relevance_score, latitude, longitude, country, region, created_at,
+	updated_at, summary, embedding_model, deleted_at, sentiment, tenant_id`
+
+// articlesSource returns the relation the filter queries read: articles, or with
+// includeArchived articles and articles_archive combined under the name articles
+func articlesSource(includeArchived bool) string {
+	if !includeArchived {
+		return "articles"
+	}
+	return fmt.Sprintf(`(
+		SELECT %[1]s FROM articles
+		UNION ALL
+		SELECT %[1]s FROM articles_archive
+	) AS articles`, articleReadColumns)
+}
+
+// filterArticlesSelect is the column list shared by the filter queries
+const filterArticlesSelect = `
 		SELECT
 			id,
 			title,
@@ -93,19 +301,162 @@ func (r *articleRepository) FilterArticles(params types.FilterArticlesRequest) (
 			relevance_score,
 			latitude,
 			longitude,
-			summary
-		FROM articles
+			summary,
+			sentiment,
+			COALESCE(embedding_model, '') AS embedding_model,
+			COALESCE(canonical_url, '') AS canonical_url,
+			COALESCE(country, '') AS country,
+			COALESCE(region, '') AS region,
+			created_at,
+			updated_at,
+			deleted_at
 	`
 
-	var conditions []string
+// FilterArticles filters articles based on category, source, and/or location
+func (r *articleRepository) FilterArticles(ctx context.Context, params types.FilterArticlesRequest) ([]models.Article, error) {
+	conditions, args, err := r.filterConditions(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	query := filterArticlesSelect + " FROM " + articlesSource(params.IncludeArchived) + " WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY " + filterOrderBy(params)
+	if params.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", params.Limit)
+	}
+
+	var articles []models.Article
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&articles).Error; err != nil {
+		r.log.Error("Failed to query articles", err, map[string]interface{}{
+			"query": query,
+		})
+		return nil, fmt.Errorf("failed to query articles: %w", wrapDBError(err))
+	}
 
-	if params.Category != "" {
-		quoted := utils.QuoteAndEscapeStrings(params.Category)
-		conditions = append(conditions, fmt.Sprintf(`category @> ARRAY[%s]`, strings.Join(quoted, ",")))
+	return articles, nil
+}
+
+// CountFilteredArticles returns the number of articles FilterArticles would return for params
+func (r *articleRepository) CountFilteredArticles(ctx context.Context, params types.FilterArticlesRequest) (int64, error) {
+	conditions, args, err := r.filterConditions(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	query := "SELECT COUNT(*) FROM " + articlesSource(params.IncludeArchived) + " WHERE " + strings.Join(conditions, " AND ")
+
+	var count int64
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&count).Error; err != nil {
+		r.log.Error("Failed to count filtered articles", err, map[string]interface{}{
+			"query": query,
+		})
+		return 0, fmt.Errorf("failed to count articles: %w", wrapDBError(err))
+	}
+
+	return count, nil
+}
+
+// StreamFilteredArticles runs the FilterArticles query and hands matching articles to fn one
+// at a time, in the same order, without loading the result set into memory. At most limit
+// articles are read (0 means no limit). Iteration stops at the first error returned by fn.
+func (r *articleRepository) StreamFilteredArticles(ctx context.Context, params types.FilterArticlesRequest, limit int, fn func(models.Article) error) error {
+	conditions, args, err := r.filterConditions(ctx, params)
+	if err != nil {
+		return err
+	}
+	query := filterArticlesSelect + " FROM " + articlesSource(params.IncludeArchived) + " WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY " + filterOrderBy(params)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	db := r.db.WithContext(ctx)
+	rows, err := db.Raw(query, args...).Rows()
+	if err != nil {
+		r.log.Error("Failed to stream articles", err, map[string]interface{}{
+			"query": query,
+		})
+		return fmt.Errorf("failed to query articles: %w", wrapDBError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article models.Article
+		if err := db.ScanRows(rows, &article); err != nil {
+			return fmt.Errorf("failed to scan article: %w", err)
+		}
+		if err := fn(article); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read articles: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// filterConditions builds the WHERE conditions for the filter parameters and the arguments of
+// their placeholders. Sources that are aliases match their source names exactly, ignoring case;
+// other sources match source names containing them. The tenant is the only bound value and its
+// condition comes first, so a ? inside a quoted filter value is never taken for a placeholder.
+func (r *articleRepository) filterConditions(ctx context.Context, params types.FilterArticlesRequest) ([]string, []interface{}, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	conditions := []string{tenant, r.notDeletedCondition()}
+	args := []interface{}{tenantArg}
+
+	sourceExact, sourcePartial, err := r.sourceAliases.Expand(ctx, params.Source)
+	if err != nil {
+		return nil, nil, err
+	}
+	excludeExact, excludePartial, err := r.sourceAliases.Expand(ctx, params.ExcludeSource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if quoted := utils.QuoteStrings(params.Category); len(quoted) > 0 {
+		// && matches articles sharing any category with the list, @> those having all of them
+		operator := "@>"
+		if params.CategoryMode == types.CategoryModeAny {
+			operator = "&&"
+		}
+		conditions = append(conditions, fmt.Sprintf(`category %s ARRAY[%s]`, operator, strings.Join(quoted, ",")))
+	}
+
+	var sourceMatches []string
+	if quoted := utils.QuoteStrings(lowercased(sourceExact)); len(quoted) > 0 {
+		sourceMatches = append(sourceMatches, fmt.Sprintf(`LOWER(source_name) IN (%s)`, strings.Join(quoted, ",")))
+	}
+	if patterns := likeContainsPatterns(sourcePartial); len(patterns) > 0 {
+		sourceMatches = append(sourceMatches, fmt.Sprintf(`source_name ILIKE ANY (ARRAY[%s])`, strings.Join(utils.QuoteStrings(patterns), ",")))
+	}
+	if len(sourceMatches) > 0 {
+		conditions = append(conditions, "("+strings.Join(sourceMatches, " OR ")+")")
+	}
+
+	if quoted := utils.QuoteStrings(params.ExcludeCategory); len(quoted) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`NOT (category && ARRAY[%s])`, strings.Join(quoted, ",")))
+	}
+
+	if quoted := utils.QuoteStrings(lowercased(excludeExact)); len(quoted) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`LOWER(source_name) NOT IN (%s)`, strings.Join(quoted, ",")))
+	}
+
+	if patterns := likeContainsPatterns(excludePartial); len(patterns) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`source_name NOT ILIKE ALL (ARRAY[%s])`, strings.Join(utils.QuoteStrings(patterns), ",")))
+	}
+
+	// Country and region compare lowercased to use the expression indexes on LOWER(...)
+	if quoted := utils.QuoteStrings(lowercased(params.Country)); len(quoted) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`LOWER(country) IN (%s)`, strings.Join(quoted, ",")))
+	}
+
+	if quoted := utils.QuoteStrings(lowercased(params.Region)); len(quoted) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`LOWER(region) IN (%s)`, strings.Join(quoted, ",")))
 	}
 
-	if params.Source != "" {
-		conditions = append(conditions, fmt.Sprintf(`source_name ILIKE ANY (ARRAY[%s])`, params.Source))
+	if quoted := utils.QuoteStrings(lowercased(params.Place)); len(quoted) > 0 {
+		list := strings.Join(quoted, ",")
+		conditions = append(conditions, fmt.Sprintf(`(LOWER(country) IN (%s) OR LOWER(region) IN (%s))`, list, list))
 	}
 
 	if params.Lat != 0 && params.Lon != 0 {
@@ -124,44 +475,125 @@ func (r *articleRepository) FilterArticles(params types.FilterArticlesRequest) (
 		conditions = append(conditions, fmt.Sprintf(`relevance_score >= %f`, params.ScoreThreshold))
 	}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if params.Sentiment != "" {
+		quoted := utils.QuoteAndEscapeStrings(params.Sentiment)
+		conditions = append(conditions, fmt.Sprintf(`sentiment IN (%s)`, strings.Join(quoted, ",")))
+	}
+
+	if !params.PublishedFrom.IsZero() {
+		conditions = append(conditions, fmt.Sprintf(`publication_date >= '%s'`, params.PublishedFrom.UTC().Format(time.RFC3339)))
+	}
+
+	if !params.PublishedTo.IsZero() {
+		conditions = append(conditions, fmt.Sprintf(`publication_date < '%s'`, params.PublishedTo.UTC().Format(time.RFC3339)))
+	}
+
+	// Strictly after the cursor: (created_at, id) compares row-wise, so articles created at the
+	// same instant are only kept when their id sorts after the last one seen
+	if params.IngestedAfter != "" {
+		after := utils.QuoteStrings([]string{params.IngestedAfter})[0]
+		if params.IngestedAfterID != "" {
+			afterID := utils.QuoteStrings([]string{params.IngestedAfterID})[0]
+			conditions = append(conditions, fmt.Sprintf(`(created_at, id) > (%s, %s)`, after, afterID))
+		} else {
+			conditions = append(conditions, fmt.Sprintf(`created_at > %s`, after))
+		}
+	}
+
+	return conditions, args, nil
+}
+
+// lowercased returns the values lowercased
+func lowercased(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = strings.ToLower(value)
+	}
+	return result
+}
+
+// likeContainsPatterns returns an ILIKE pattern matching values containing each non-empty name
+func likeContainsPatterns(names []string) []string {
+	patterns := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) != "" {
+			patterns = append(patterns, utils.LikeContainsPattern(name))
+		}
 	}
+	return patterns
+}
 
-	var orderBy string
+// filterOrderBy returns the ORDER BY expression for the filter parameters: oldest ingested
+// first when polling by ingestion time, nearest first for radius searches, most relevant first
+// for score filters, newest first otherwise
+func filterOrderBy(params types.FilterArticlesRequest) string {
+	if params.IngestedAfter != "" {
+		return "created_at ASC, id ASC"
+	}
 	if params.Lat != 0 && params.Lon != 0 && params.Radius > 0 {
-		orderBy = fmt.Sprintf(`ST_Distance(
+		return fmt.Sprintf(`ST_Distance(
 			ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography,
 			ST_SetSRID(ST_MakePoint(%f, %f), 4326)::geography
 		) ASC`, params.Lon, params.Lat)
 	} else if params.ScoreThreshold > 0 {
-		orderBy = "relevance_score DESC"
-	} else {
-		orderBy = "publication_date DESC"
+		return "relevance_score DESC"
 	}
+	return "publication_date DESC"
+}
 
-	var articles []models.Article
-	fmt.Println(r.db.Raw(query).Order(orderBy).Statement.ToSQL(func(tx *gorm.DB) *gorm.DB {
-		return tx.Order(orderBy)
-	}))
+// FindByID retrieves a live article by its ID
+func (r *articleRepository) FindByID(ctx context.Context, id string) (*models.Article, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT %s, COALESCE(content, '') AS content
+		FROM articles
+		WHERE id = ?::uuid AND %s AND %s
+	`, articleReadColumns, tenant, r.notDeletedCondition())
 
-	if err := r.db.Raw(query).Order(orderBy).Scan(&articles).Error; err != nil {
-		r.log.Error("Failed to query articles", err, map[string]interface{}{
-			"query": query,
+	var articles []models.Article
+	if err := r.db.WithContext(ctx).Raw(query, id, tenantArg).Scan(&articles).Error; err != nil {
+		r.log.Error("Failed to query article by ID", err, map[string]interface{}{
+			"id": id,
 		})
-		return nil, fmt.Errorf("failed to query articles: %w", err)
+		return nil, fmt.Errorf("failed to query article by ID: %w", wrapDBError(err))
 	}
 
-	return articles, nil
+	if len(articles) == 0 {
+		return nil, ErrArticleNotFound
+	}
+	return &articles[0], nil
+}
+
+// FindByIDs retrieves articles by their IDs, from articles_archive too since user events and
+// saved search matches may reference archived articles
+func (r *articleRepository) FindByIDs(ctx context.Context, ids []string) ([]models.Article, error) {
+	return r.findByIDs(ctx, ids, time.Time{})
+}
+
+// FindByIDsPublishedSince retrieves the articles with the given IDs published at or after since
+func (r *articleRepository) FindByIDsPublishedSince(ctx context.Context, ids []string, since time.Time) ([]models.Article, error) {
+	return r.findByIDs(ctx, ids, since)
 }
 
-// FindByIDs retrieves articles by their IDs
-func (r *articleRepository) FindByIDs(ids []string) ([]models.Article, error) {
+// findByIDs retrieves live and archived articles by their IDs, leaving out those published
+// before since unless it is zero
+func (r *articleRepository) findByIDs(ctx context.Context, ids []string, since time.Time) ([]models.Article, error) {
 	if len(ids) == 0 {
 		return []models.Article{}, nil
 	}
 
-	query := `
+	publishedCondition := ""
+	args := []interface{}{pq.Array(ids)}
+	if !since.IsZero() {
+		publishedCondition = "AND publication_date >= ?"
+		args = append(args, since)
+	}
+	// The condition applies to both tables
+	args = append(args, args...)
+	tenant, tenantArg := r.tenantCondition(ctx)
+	args = append(args, tenantArg)
+
+	query := fmt.Sprintf(`
 		SELECT
 			id,
 			title,
@@ -173,18 +605,31 @@ func (r *articleRepository) FindByIDs(ids []string) ([]models.Article, error) {
 			relevance_score,
 			latitude,
 			longitude,
-			summary
-		FROM articles
-		WHERE id = ANY(?)
+			summary,
+			sentiment,
+			COALESCE(embedding_model, '') AS embedding_model,
+			COALESCE(canonical_url, '') AS canonical_url,
+			COALESCE(country, '') AS country,
+			COALESCE(region, '') AS region,
+			created_at,
+			updated_at,
+			deleted_at
+		FROM (
+			SELECT %[1]s FROM articles WHERE id = ANY(?) %[3]s
+			UNION ALL
+			SELECT %[1]s FROM articles_archive WHERE id = ANY(?) %[3]s
+		) AS articles
+		WHERE %[2]s AND %[4]s
 		ORDER BY publication_date DESC
-	`
+	`, articleReadColumns, r.notDeletedCondition(), publishedCondition, tenant)
 
 	var articles []models.Article
-	if err := r.db.Raw(query, pq.Array(ids)).Scan(&articles).Error; err != nil {
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&articles).Error; err != nil {
 		r.log.Error("Failed to query articles by IDs", err, map[string]interface{}{
 			"ids_count": len(ids),
+			"since":     since,
 		})
-		return nil, fmt.Errorf("failed to query articles by IDs: %w", err)
+		return nil, fmt.Errorf("failed to query articles by IDs: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Retrieved articles by IDs", map[string]interface{}{
@@ -195,8 +640,191 @@ func (r *articleRepository) FindByIDs(ids []string) ([]models.Article, error) {
 	return articles, nil
 }
 
+// Exists reports whether a (non-deleted) article with the given id exists
+func (r *articleRepository) Exists(ctx context.Context, id string) (bool, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT 1
+		FROM articles
+		WHERE id = ?::uuid
+			AND %s
+			AND %s
+		LIMIT 1
+	`, tenant, r.notDeletedCondition())
+
+	var found []int
+	if err := r.db.WithContext(ctx).Raw(query, id, tenantArg).Scan(&found).Error; err != nil {
+		r.log.Error("Failed to check article existence", err, map[string]interface{}{
+			"id": id,
+		})
+		return false, fmt.Errorf("failed to check article existence: %w", wrapDBError(err))
+	}
+
+	return len(found) > 0, nil
+}
+
 // SearchByText performs text search on article titles and descriptions
-func (r *articleRepository) SearchByText(query []string) ([]models.Article, error) {
+func (r *articleRepository) SearchByText(ctx context.Context, query []string) ([]models.Article, error) {
+	return r.SearchByTextFiltered(ctx, query, TextSearchFilters{})
+}
+
+// NearestByVector returns up to limit articles whose embeddings are closest to vector by cosine
+// distance and at least minSimilarity similar, most similar first. The query runs in its own
+// transaction so the configured ef_search (or probes) applies to it alone.
+func (r *articleRepository) NearestByVector(ctx context.Context, vector []float64, limit int, minSimilarity float64) ([]VectorNeighbor, error) {
+	if len(vector) == 0 || limit <= 0 {
+		return []VectorNeighbor{}, nil
+	}
+
+	// pgvector's <=> is the cosine distance, 1 - similarity
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			1 - (description_vector <=> ?::vector) AS similarity
+		FROM articles
+		WHERE description_vector IS NOT NULL
+			AND description_vector <=> ?::vector <= ?
+			AND %s
+			AND %s
+		ORDER BY description_vector <=> ?::vector
+		LIMIT ?
+	`, tenant, r.notDeletedCondition())
+
+	vectorStr := formatVector(vector)
+
+	var neighbors []VectorNeighbor
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(vectorSearchSetting(r.vectorCfg, limit)).Error; err != nil {
+			return err
+		}
+		return tx.Raw(query, vectorStr, vectorStr, 1-minSimilarity, tenantArg, vectorStr, limit).Scan(&neighbors).Error
+	})
+	if err != nil {
+		r.log.Error("Failed to run nearest-neighbor search", err, map[string]interface{}{
+			"limit": limit,
+		})
+		return nil, fmt.Errorf("failed to run nearest-neighbor search: %w", wrapDBError(err))
+	}
+
+	return neighbors, nil
+}
+
+// SimilarityByIDs returns the cosine similarity between vector and the embedding of each of
+// the given articles. Articles without an embedding are left out.
+func (r *articleRepository) SimilarityByIDs(ctx context.Context, vector []float64, ids []string) ([]VectorNeighbor, error) {
+	if len(vector) == 0 || len(ids) == 0 {
+		return []VectorNeighbor{}, nil
+	}
+
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			1 - (description_vector <=> ?::vector) AS similarity
+		FROM articles
+		WHERE id = ANY(?)
+			AND description_vector IS NOT NULL
+			AND %s
+	`, tenant)
+
+	var similarities []VectorNeighbor
+	if err := r.db.WithContext(ctx).Raw(query, formatVector(vector), pq.Array(ids), tenantArg).Scan(&similarities).Error; err != nil {
+		r.log.Error("Failed to compute article similarities", err, map[string]interface{}{
+			"ids_count": len(ids),
+		})
+		return nil, fmt.Errorf("failed to compute article similarities: %w", wrapDBError(err))
+	}
+
+	return similarities, nil
+}
+
+// SimilarPairs returns the pairs among the given articles whose embeddings are at least
+// minSimilarity similar. Embeddings from different models are never compared, nor are
+// articles of different tenants.
+func (r *articleRepository) SimilarPairs(ctx context.Context, ids []string, minSimilarity float64) ([]ArticlePair, error) {
+	if len(ids) < 2 {
+		return []ArticlePair{}, nil
+	}
+
+	tenant, tenantArg := tenantCondition("a.tenant_id", r.tenant(ctx))
+	query := fmt.Sprintf(`
+		SELECT
+			a.id AS first_id,
+			b.id AS second_id
+		FROM articles a
+		JOIN articles b ON a.id < b.id
+		WHERE a.id = ANY(?)
+			AND b.id = ANY(?)
+			AND a.description_vector IS NOT NULL
+			AND b.description_vector IS NOT NULL
+			AND a.embedding_model IS NOT DISTINCT FROM b.embedding_model
+			AND %s
+			AND b.tenant_id = a.tenant_id
+			AND a.description_vector <=> b.description_vector <= ?
+	`, tenant)
+
+	var pairs []ArticlePair
+	if err := r.db.WithContext(ctx).Raw(query, pq.Array(ids), pq.Array(ids), tenantArg, 1-minSimilarity).Scan(&pairs).Error; err != nil {
+		r.log.Error("Failed to find similar article pairs", err, map[string]interface{}{
+			"ids_count": len(ids),
+		})
+		return nil, fmt.Errorf("failed to find similar article pairs: %w", wrapDBError(err))
+	}
+
+	return pairs, nil
+}
+
+// FindSimilarTitles returns, for each of titles that has one, the stored article with the most
+// similar title among those at least threshold similar by trigram similarity. All titles are
+// matched in one query using the trigram index on articles.title.
+func (r *articleRepository) FindSimilarTitles(ctx context.Context, titles []string, threshold float64) ([]TitleMatch, error) {
+	if len(titles) == 0 {
+		return []TitleMatch{}, nil
+	}
+
+	// % compares against pg_trgm.similarity_threshold, which is scoped to the transaction
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT
+			t.ord - 1 AS index,
+			m.id AS article_id,
+			m.similarity
+		FROM unnest(?::text[]) WITH ORDINALITY AS t(title, ord)
+		CROSS JOIN LATERAL (
+			SELECT
+				a.id,
+				similarity(a.title, t.title) AS similarity
+			FROM articles a
+			WHERE a.title %% t.title
+				AND %s
+				AND %s
+			ORDER BY similarity DESC
+			LIMIT 1
+		) m
+		ORDER BY t.ord
+	`, tenant, r.notDeletedCondition())
+
+	var matches []TitleMatch
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", strconv.FormatFloat(threshold, 'f', -1, 64)).Error; err != nil {
+			return err
+		}
+		return tx.Raw(query, pq.Array(titles), tenantArg).Scan(&matches).Error
+	})
+	if err != nil {
+		r.log.Error("Failed to find similar titles", err, map[string]interface{}{
+			"titles_count": len(titles),
+		})
+		return nil, fmt.Errorf("failed to find similar titles: %w", wrapDBError(err))
+	}
+
+	return matches, nil
+}
+
+// SearchByTextFiltered performs text search on article titles and descriptions, narrowed
+// by the optional category and source filters and capped at filters.Limit when set
+func (r *articleRepository) SearchByTextFiltered(ctx context.Context, query []string, filters TextSearchFilters) ([]models.Article, error) {
 	if len(query) == 0 {
 		return []models.Article{}, nil
 	}
@@ -209,7 +837,20 @@ func (r *articleRepository) SearchByText(query []string) ([]models.Article, erro
 		args = append(args, term, term)
 	}
 
-	whereClause := strings.Join(conditions, " OR ")
+	whereClause := "(" + strings.Join(conditions, " OR ") + ")"
+
+	if filters.Category != "" {
+		whereClause += " AND ? ILIKE ANY(category)"
+		args = append(args, filters.Category)
+	}
+
+	if filters.Source != "" {
+		whereClause += " AND source_name ILIKE ?"
+		args = append(args, filters.Source)
+	}
+
+	tenant, tenantArg := r.tenantCondition(ctx)
+	args = append(args, tenantArg)
 
 	sqlQuery := fmt.Sprintf(`
 		SELECT
@@ -222,20 +863,37 @@ func (r *articleRepository) SearchByText(query []string) ([]models.Article, erro
 			category,
 			relevance_score,
 			latitude,
-			longitude
+			longitude,
+			summary,
+			sentiment,
+			COALESCE(embedding_model, '') AS embedding_model,
+			COALESCE(canonical_url, '') AS canonical_url,
+			COALESCE(country, '') AS country,
+			COALESCE(region, '') AS region,
+			created_at,
+			updated_at,
+			deleted_at
 		FROM articles
 		WHERE %s
+			AND %s
+			AND %s
 		ORDER BY
 			relevance_score DESC,
 			publication_date DESC
-	`, whereClause)
+	`, whereClause, tenant, r.notDeletedCondition())
+
+	if filters.Limit > 0 {
+		sqlQuery += " LIMIT ?"
+		args = append(args, filters.Limit)
+	}
 
 	var articles []models.Article
-	if err := r.db.Raw(sqlQuery, args...).Scan(&articles).Error; err != nil {
+	if err := r.db.WithContext(ctx).Raw(sqlQuery, args...).Scan(&articles).Error; err != nil {
 		r.log.Error("Failed to search articles by text", err, map[string]interface{}{
-			"query": query,
+			"query":   query,
+			"filters": filters,
 		})
-		return nil, fmt.Errorf("failed to search articles by text: %w", err)
+		return nil, fmt.Errorf("failed to search articles by text: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Retrieved articles by text search", map[string]interface{}{
@@ -247,42 +905,44 @@ func (r *articleRepository) SearchByText(query []string) ([]models.Article, erro
 }
 
 // validateArticle validates an article structure
-func (r *articleRepository) validateArticle(article *models.Article, index int) []string {
-	var errors []string
+func (r *articleRepository) validateArticle(article *models.Article) types.ValidationErrors {
+	var errs types.ValidationErrors
 
 	if article.Title == "" {
-		errors = append(errors, fmt.Sprintf("Article %d: title is required", index))
+		errs.Add("title", types.ValidationCodeRequired, "title is required")
 	}
 
 	if article.URL == "" {
-		errors = append(errors, fmt.Sprintf("Article %d: url is required", index))
+		errs.Add("url", types.ValidationCodeRequired, "url is required")
 	}
 
 	if article.SourceName == "" {
-		errors = append(errors, fmt.Sprintf("Article %d: source_name is required", index))
+		errs.Add("source_name", types.ValidationCodeRequired, "source_name is required")
 	}
 
-	if len(article.Category) == 0 {
-		errors = append(errors, fmt.Sprintf("Article %d: at least one category is required", index))
+	if len(article.RejectedCategories) > 0 {
+		errs.Add("category", types.ValidationCodeInvalidValue, "categories not in the taxonomy: "+strings.Join(article.RejectedCategories, ", "))
+	} else if len(article.Category) == 0 {
+		errs.Add("category", types.ValidationCodeRequired, "at least one category is required")
 	}
 
 	if article.RelevanceScore < 0 || article.RelevanceScore > 1 {
-		errors = append(errors, fmt.Sprintf("Article %d: relevance_score must be between 0 and 1", index))
+		errs.Add("relevance_score", types.ValidationCodeOutOfRange, "relevance_score must be between 0 and 1")
 	}
 
 	if article.Latitude < -90 || article.Latitude > 90 {
-		errors = append(errors, fmt.Sprintf("Article %d: latitude must be between -90 and 90", index))
+		errs.Add("latitude", types.ValidationCodeOutOfRange, "latitude must be between -90 and 90")
 	}
 
 	if article.Longitude < -180 || article.Longitude > 180 {
-		errors = append(errors, fmt.Sprintf("Article %d: longitude must be between -180 and 180", index))
+		errs.Add("longitude", types.ValidationCodeOutOfRange, "longitude must be between -180 and 180")
 	}
 
 	if article.PublicationDate.IsZero() {
-		errors = append(errors, fmt.Sprintf("Article %d: publication_date is required", index))
+		errs.Add("publication_date", types.ValidationCodeRequired, "publication_date is required")
 	}
 
-	return errors
+	return errs
 }
 
 // formatVector formats a float64 slice as a pgvector string format: "[0.1,0.2,0.3]"
@@ -297,11 +957,31 @@ func formatVector(vector []float64) string {
 	return "[" + strings.Join(parts, ",") + "]"
 }
 
-// BulkInsert inserts multiple articles into the database in a single transaction
-func (r *articleRepository) BulkInsert(articles []models.Article) (*LoadStats, error) {
+// parseVector parses a vector in pgvector's text format, the inverse of formatVector
+func parseVector(text string) ([]float64, error) {
+	text = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(text), "["), "]")
+	if text == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(text, ",")
+	vector := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector component %q: %w", part, err)
+		}
+		vector[i] = value
+	}
+	return vector, nil
+}
+
+// BulkInsert inserts multiple articles into the database with multi-row INSERTs of
+// bulkInsertChunkSize articles, one transaction per chunk
+func (r *articleRepository) BulkInsert(ctx context.Context, articles []models.Article, titleThreshold float64) (*LoadStats, error) {
 	stats := &LoadStats{
 		TotalArticles:    len(articles),
-		ValidationErrors: []string{},
+		ValidationErrors: []types.ValidationError{},
 	}
 
 	if len(articles) == 0 {
@@ -318,7 +998,7 @@ func (r *articleRepository) BulkInsert(articles []models.Article) (*LoadStats, e
 	})
 
 	for i, article := range articles {
-		validationErrors := r.validateArticle(&article, i)
+		validationErrors := r.validateArticle(&article).WithIndex(i)
 		if len(validationErrors) > 0 {
 			stats.ValidationErrors = append(stats.ValidationErrors, validationErrors...)
 		}
@@ -333,112 +1013,437 @@ func (r *articleRepository) BulkInsert(articles []models.Article) (*LoadStats, e
 
 	r.log.Info("All articles validated successfully", nil)
 
-	tx := r.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	if titleThreshold > 0 {
+		suspected, err := r.detectTitleDuplicates(ctx, articles, titleThreshold)
+		if err != nil {
+			return nil, err
 		}
-	}()
-
-	insertQuery := `
-		INSERT INTO articles (
-			id,
-			title,
-			description,
-			url,
-			publication_date,
-			source_name,
-			category,
-			relevance_score,
-			latitude,
-			longitude,
-			summary,
-			description_vector
-		) VALUES (
-			COALESCE(?::uuid, uuid_generate_v4()),
-			?,
-			?,
-			?,
-			?,
-			?,
-			?,
-			?,
-			?,
-			?,
-			?,
-			?::vector
-		) ON CONFLICT (id) DO NOTHING;
-	`
+		stats.SuspectedDuplicates = suspected
+	}
+	articles, positions := withoutSuspectedDuplicates(articles, stats.SuspectedDuplicates)
 
 	successCount := 0
 	errorCount := 0
 
-	for i, article := range articles {
-		// Format vector as string for pgvector
-		var vectorStr interface{}
-		if len(article.DescriptionVector) > 0 {
-			vectorStr = formatVector(article.DescriptionVector)
-		} else {
-			vectorStr = nil
+	// Each chunk goes in as one multi-row INSERT in its own transaction, so a chunk that keeps
+	// failing does not cost the chunks already stored. If the INSERT fails it is rolled back to
+	// a savepoint and the chunk is retried row by row, so errors can still be attributed to
+	// single articles. Serialization failures and deadlocks abort the whole transaction
+	// instead; withTxRetry runs the chunk again in a fresh one.
+	for chunkStart := 0; chunkStart < len(articles); chunkStart += bulkInsertChunkSize {
+		chunkEnd := min(chunkStart+bulkInsertChunkSize, len(articles))
+		chunk := articles[chunkStart:chunkEnd]
+		chunkPositions := positions[chunkStart:chunkEnd]
+		// from and to are the chunk's range in the loaded file, so they can be matched up with
+		// the input even after suspected duplicates were skipped
+		from, to := chunkPositions[0], chunkPositions[len(chunkPositions)-1]+1
+
+		var inserted map[string]bool
+		var failed []int
+		err := withTxRetry(ctx, r.log, "bulk_insert_chunk", func() error {
+			inserted, failed = nil, nil
+			return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				var err error
+				inserted, failed, err = r.insertChunk(tx, r.tenant(ctx), chunkPositions, chunk)
+				return err
+			})
+		})
+		if err != nil {
+			sqlState, retryable := retryableSQLState(err)
+			if !retryable {
+				r.log.Error("Failed to insert chunk", err, map[string]interface{}{
+					"from": from,
+					"to":   to,
+				})
+				return nil, fmt.Errorf("failed to insert articles %d-%d: %w", from, to, wrapDBError(err))
+			}
+
+			r.log.Error("Giving up on chunk after retries", err, map[string]interface{}{
+				"from":     from,
+				"to":       to,
+				"sqlstate": sqlState,
+			})
+			errorCount += len(chunk)
+			stats.FailedChunks = append(stats.FailedChunks, types.FailedChunk{
+				From:     from,
+				To:       to,
+				SQLState: sqlState,
+				Error:    err.Error(),
+			})
+			continue
 		}
 
-		if err := tx.Exec(insertQuery,
-			article.ID,
+		// Rows that failed on their own are errors; rows the INSERT skipped on conflict
+		// duplicate a stored article's canonical URL (or id)
+		errorCount += len(failed)
+		failedRows := make(map[int]bool, len(failed))
+		for _, i := range failed {
+			failedRows[i] = true
+		}
+		for i, article := range chunk {
+			switch {
+			case failedRows[i]:
+			case inserted[strings.ToLower(article.ID)]:
+				successCount++
+				stats.StoredIDs = append(stats.StoredIDs, article.ID)
+			default:
+				stats.DuplicateURLs = append(stats.DuplicateURLs, article.URL)
+			}
+		}
+
+		r.log.Info("Bulk insert progress", map[string]interface{}{
+			"loaded": chunkEnd,
+			"total":  len(articles),
+		})
+	}
+
+	stats.SuccessCount = successCount
+	stats.ErrorCount = errorCount
+
+	r.log.Info("Completed bulk insert of articles", map[string]interface{}{
+		"total":         len(articles),
+		"success_count": successCount,
+		"error_count":   errorCount,
+		"duplicates":    len(stats.DuplicateURLs),
+		"failed_chunks": len(stats.FailedChunks),
+	})
+
+	return stats, nil
+}
+
+// DryRunBulkInsert reports what BulkInsert would do with articles without writing anything:
+// every article is validated and canonical URLs are checked against the stored articles, live
+// and archived, in one query. Articles without a canonical URL are never reported as duplicates, as on insert.
+func (r *articleRepository) DryRunBulkInsert(ctx context.Context, articles []models.Article, titleThreshold float64) (*LoadStats, error) {
+	stats := &LoadStats{
+		TotalArticles:    len(articles),
+		ValidationErrors: []types.ValidationError{},
+		DryRun:           true,
+	}
+
+	suspected := make(map[int]bool)
+	if titleThreshold > 0 {
+		matches, err := r.detectTitleDuplicates(ctx, articles, titleThreshold)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			suspected[match.Index] = true
+		}
+		stats.SuspectedDuplicates = matches
+	}
+
+	urls := make([]string, 0, len(articles))
+	for _, article := range articles {
+		if article.CanonicalURL != "" {
+			urls = append(urls, article.CanonicalURL)
+		}
+	}
+
+	var existingURLs []string
+	if len(urls) > 0 {
+		tenant, tenantArg := r.tenantCondition(ctx)
+		query := `
+			SELECT canonical_url FROM articles WHERE canonical_url = ANY(?) AND ` + tenant + `
+			UNION
+			SELECT canonical_url FROM articles_archive WHERE canonical_url = ANY(?) AND ` + tenant
+		if err := r.db.WithContext(ctx).Raw(query, pq.Array(urls), tenantArg, pq.Array(urls), tenantArg).Scan(&existingURLs).Error; err != nil {
+			r.log.Error("Failed to check for existing article URLs", err, map[string]interface{}{
+				"count": len(urls),
+			})
+			return nil, fmt.Errorf("failed to check existing urls: %w", wrapDBError(err))
+		}
+	}
+
+	seen := make(map[string]bool, len(articles)+len(existingURLs))
+	for _, url := range existingURLs {
+		seen[url] = true
+	}
+
+	for i, article := range articles {
+		validationErrors := r.validateArticle(&article).WithIndex(i)
+		if len(validationErrors) > 0 {
+			stats.ValidationErrors = append(stats.ValidationErrors, validationErrors...)
+			stats.WouldSkip++
+			continue
+		}
+
+		if suspected[i] {
+			stats.WouldSkip++
+			continue
+		}
+
+		if article.CanonicalURL != "" {
+			if seen[article.CanonicalURL] {
+				stats.DuplicateURLs = append(stats.DuplicateURLs, article.URL)
+				stats.WouldSkip++
+				continue
+			}
+			seen[article.CanonicalURL] = true
+		}
+		stats.WouldInsert++
+	}
+
+	r.log.Info("Completed dry run of bulk insert", map[string]interface{}{
+		"total":        len(articles),
+		"would_insert": stats.WouldInsert,
+		"would_skip":   stats.WouldSkip,
+	})
+
+	return stats, nil
+}
+
+// detectTitleDuplicates reports the articles whose title is at least threshold similar to the
+// title of a stored article
+func (r *articleRepository) detectTitleDuplicates(ctx context.Context, articles []models.Article, threshold float64) ([]types.SuspectedDuplicate, error) {
+	titles := make([]string, len(articles))
+	for i, article := range articles {
+		titles[i] = article.Title
+	}
+
+	matches, err := r.FindSimilarTitles(ctx, titles, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	suspected := make([]types.SuspectedDuplicate, 0, len(matches))
+	for _, match := range matches {
+		suspected = append(suspected, types.SuspectedDuplicate{
+			Index:      match.Index,
+			Title:      articles[match.Index].Title,
+			ExistingID: match.ArticleID,
+			Similarity: match.Similarity,
+		})
+	}
+
+	r.log.Info("Checked article titles for suspected duplicates", map[string]interface{}{
+		"total":     len(articles),
+		"threshold": threshold,
+		"suspected": len(suspected),
+	})
+
+	return suspected, nil
+}
+
+// withoutSuspectedDuplicates returns articles minus the suspected duplicates, along with the
+// position in articles of each article kept
+func withoutSuspectedDuplicates(articles []models.Article, suspected []types.SuspectedDuplicate) ([]models.Article, []int) {
+	skip := make(map[int]bool, len(suspected))
+	for _, duplicate := range suspected {
+		skip[duplicate.Index] = true
+	}
+
+	remaining := make([]models.Article, 0, len(articles)-len(skip))
+	positions := make([]int, 0, len(articles)-len(skip))
+	for i, article := range articles {
+		if !skip[i] {
+			remaining = append(remaining, article)
+			positions = append(positions, i)
+		}
+	}
+	return remaining, positions
+}
+
+// insertChunk stores chunk inside tx for tenant and returns the (lowercased) ids of the rows
+// stored and the positions in chunk of the rows that failed. positions holds the position of
+// each article of chunk in the loaded file, for logging. A serialization failure or deadlock is returned as the
+// error, since the transaction can no longer be used.
+func (r *articleRepository) insertChunk(tx *gorm.DB, tenant string, positions []int, chunk []models.Article) (map[string]bool, []int, error) {
+	inserted, err := r.insertArticlesWithSavepoint(tx, "bulk_chunk", tenant, chunk)
+	if err == nil {
+		return inserted, nil, nil
+	}
+	if _, retryable := retryableSQLState(err); retryable {
+		return nil, nil, err
+	}
+
+	r.log.Warn("Chunk insert failed, retrying articles one by one", map[string]interface{}{
+		"from":  positions[0],
+		"to":    positions[len(positions)-1] + 1,
+		"error": err.Error(),
+	})
+
+	inserted = make(map[string]bool, len(chunk))
+	var failed []int
+	for i := range chunk {
+		rowInserted, err := r.insertArticlesWithSavepoint(tx, "bulk_row", tenant, chunk[i:i+1])
+		if err != nil {
+			if _, retryable := retryableSQLState(err); retryable {
+				return nil, nil, err
+			}
+			failed = append(failed, i)
+			r.log.Error("Failed to insert article", err, map[string]interface{}{
+				"index": positions[i],
+				"title": chunk[i].Title,
+			})
+			continue
+		}
+		for id := range rowInserted {
+			inserted[id] = true
+		}
+	}
+	return inserted, failed, nil
+}
+
+// bulkInsertChunkSize is the number of articles per multi-row INSERT. With 20 parameters per row
+// this stays well below Postgres' limit of 65535 bind parameters per statement.
+const bulkInsertChunkSize = 500
+
+// insertArticlesWithSavepoint inserts articles of tenant with a single multi-row INSERT inside
+// tx and returns the (lowercased) ids of the rows stored; articles whose id, or canonical URL
+// within the tenant, is already stored, live or archived, are skipped. The statements run
+// behind a savepoint so a failure leaves the surrounding transaction usable.
+func (r *articleRepository) insertArticlesWithSavepoint(tx *gorm.DB, savepoint, tenant string, articles []models.Article) (map[string]bool, error) {
+	if err := tx.SavePoint(savepoint).Error; err != nil {
+		return nil, fmt.Errorf("failed to create savepoint: %w", wrapDBError(err))
+	}
+
+	archived, err := archivedCanonicalURLs(tx, tenant, articles)
+	if err != nil {
+		return nil, rollbackToSavepoint(tx, savepoint, err)
+	}
+
+	placeholders := make([]string, 0, len(articles))
+	args := make([]interface{}, 0, len(articles)*20)
+
+	for _, article := range articles {
+		if archived[article.CanonicalURL] {
+			continue
+		}
+
+		// Format vector as string for pgvector
+		var vectorStr interface{}
+		if len(article.DescriptionVector) > 0 {
+			vectorStr = formatVector(article.DescriptionVector)
+		}
+
+		placeholders = append(placeholders, "(COALESCE(?::uuid, uuid_generate_v4()), ?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?::vector, NULLIF(?, ''), ?, ?, NOW(), NOW())")
+		args = append(args,
+			article.ID,
 			article.Title,
 			article.Description,
 			article.URL,
+			article.CanonicalURL,
 			article.PublicationDate,
 			article.SourceName,
 			pq.Array(article.Category),
 			article.RelevanceScore,
 			article.Latitude,
 			article.Longitude,
+			article.Country,
+			article.Region,
 			article.Summary,
+			article.Content,
+			article.Sentiment,
 			vectorStr,
-		).Error; err != nil {
-			errorCount++
-			r.log.Error("Failed to insert article", err, map[string]interface{}{
-				"index": i,
-				"title": article.Title,
-			})
-			continue
-		}
+			article.EmbeddingModel,
+			article.EmbeddedAt,
+			tenant,
+		)
+	}
 
-		successCount++
+	if len(placeholders) == 0 {
+		return map[string]bool{}, nil
+	}
 
-		if (i+1)%100 == 0 {
-			r.log.Info("Bulk insert progress", map[string]interface{}{
-				"loaded": i + 1,
-				"total":  len(articles),
-			})
-		}
+	query := `
+		INSERT INTO articles (
+			id,
+			title,
+			description,
+			url,
+			canonical_url,
+			publication_date,
+			source_name,
+			category,
+			relevance_score,
+			latitude,
+			longitude,
+			country,
+			region,
+			summary,
+			content,
+			sentiment,
+			description_vector,
+			embedding_model,
+			embedded_at,
+			tenant_id,
+			created_at,
+			updated_at
+		) VALUES ` + strings.Join(placeholders, ", ") + `
+		ON CONFLICT DO NOTHING
+		RETURNING id`
+
+	var ids []string
+	if err := tx.Raw(query, args...).Scan(&ids).Error; err != nil {
+		return nil, rollbackToSavepoint(tx, savepoint, err)
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		r.log.Error("Failed to commit transaction", err, nil)
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	inserted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		inserted[strings.ToLower(id)] = true
 	}
+	return inserted, nil
+}
 
-	stats.SuccessCount = successCount
-	stats.ErrorCount = errorCount
+// rollbackToSavepoint rolls tx back to savepoint after err and returns err
+func rollbackToSavepoint(tx *gorm.DB, savepoint string, err error) error {
+	if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+		return fmt.Errorf("%w (rollback to savepoint failed: %v)", err, rbErr)
+	}
+	return err
+}
 
-	r.log.Info("Completed bulk insert of articles", map[string]interface{}{
-		"total":         len(articles),
-		"success_count": successCount,
-		"error_count":   errorCount,
-	})
+// archivedCanonicalURLs returns which canonical URLs of articles belong to an archived article
+// of tenant. The unique index on canonical URLs only covers articles, so inserts check
+// articles_archive with this first.
+func archivedCanonicalURLs(db *gorm.DB, tenant string, articles []models.Article) (map[string]bool, error) {
+	urls := make([]string, 0, len(articles))
+	for _, article := range articles {
+		if article.CanonicalURL != "" {
+			urls = append(urls, article.CanonicalURL)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, nil
+	}
 
-	return stats, nil
+	condition, tenantArg := tenantCondition("tenant_id", tenant)
+	var archived []string
+	query := `SELECT canonical_url FROM articles_archive WHERE canonical_url = ANY(?) AND ` + condition
+	if err := db.Raw(query, pq.Array(urls), tenantArg).Scan(&archived).Error; err != nil {
+		return nil, fmt.Errorf("failed to check archived urls: %w", err)
+	}
+
+	found := make(map[string]bool, len(archived))
+	for _, url := range archived {
+		found[url] = true
+	}
+	return found, nil
 }
 
 // Insert inserts a single article into the database
-func (r *articleRepository) Insert(article *models.Article) error {
-	validationErrors := r.validateArticle(article, 0)
-	if len(validationErrors) > 0 {
+func (r *articleRepository) Insert(ctx context.Context, article *models.Article) error {
+	if err := r.validateArticle(article).Err(); err != nil {
 		r.log.Error("Validation failed for article", nil, map[string]interface{}{
-			"errors": validationErrors,
+			"errors": err.Error(),
 		})
-		return fmt.Errorf("validation failed: %v", validationErrors)
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	archived, err := archivedCanonicalURLs(r.db.WithContext(ctx), r.tenant(ctx), []models.Article{*article})
+	if err != nil {
+		r.log.Error("Failed to check for an archived article URL", err, map[string]interface{}{
+			"title": article.Title,
+		})
+		return fmt.Errorf("failed to insert article: %w", wrapDBError(err))
+	}
+	if archived[article.CanonicalURL] {
+		r.log.Warn("Skipped duplicate of an archived article", map[string]interface{}{
+			"title":         article.Title,
+			"canonical_url": article.CanonicalURL,
+		})
+		return ErrDuplicateArticle
 	}
 
 	insertQuery := `
@@ -447,28 +1452,50 @@ func (r *articleRepository) Insert(article *models.Article) error {
 			title,
 			description,
 			url,
+			canonical_url,
 			publication_date,
 			source_name,
 			category,
 			relevance_score,
 			latitude,
 			longitude,
+			country,
+			region,
 			summary,
-			description_vector
+			content,
+			sentiment,
+			description_vector,
+			embedding_model,
+			embedded_at,
+			tenant_id,
+			created_at,
+			updated_at
 		) VALUES (
 			COALESCE(?::uuid, uuid_generate_v4()),
 			?,
 			?,
 			?,
+			NULLIF(?, ''),
 			?,
 			?,
 			?,
 			?,
 			?,
 			?,
+			NULLIF(?, ''),
+			NULLIF(?, ''),
 			?,
-			?::vector
-		) RETURNING id;
+			NULLIF(?, ''),
+			?,
+			?::vector,
+			NULLIF(?, ''),
+			?,
+			?,
+			NOW(),
+			NOW()
+		)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at, updated_at;
 	`
 
 	// Format vector as string for pgvector
@@ -479,30 +1506,53 @@ func (r *articleRepository) Insert(article *models.Article) error {
 		vectorStr = nil
 	}
 
-	var insertedID string
-	if err := r.db.Raw(insertQuery,
+	var inserted []struct {
+		ID        string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+	if err := r.db.WithContext(ctx).Raw(insertQuery,
 		article.ID,
 		article.Title,
 		article.Description,
 		article.URL,
+		article.CanonicalURL,
 		article.PublicationDate,
 		article.SourceName,
 		pq.Array(article.Category),
 		article.RelevanceScore,
 		article.Latitude,
 		article.Longitude,
+		article.Country,
+		article.Region,
 		article.Summary,
+		article.Content,
+		article.Sentiment,
 		vectorStr,
-	).Scan(&insertedID).Error; err != nil {
+		article.EmbeddingModel,
+		article.EmbeddedAt,
+		r.tenant(ctx),
+	).Scan(&inserted).Error; err != nil {
 		r.log.Error("Failed to insert article", err, map[string]interface{}{
 			"title": article.Title,
 		})
-		return fmt.Errorf("failed to insert article: %w", err)
+		return fmt.Errorf("failed to insert article: %w", wrapDBError(err))
+	}
+
+	// Nothing is returned when the insert was skipped on conflict
+	if len(inserted) == 0 {
+		r.log.Warn("Skipped duplicate article", map[string]interface{}{
+			"title":         article.Title,
+			"canonical_url": article.CanonicalURL,
+		})
+		return ErrDuplicateArticle
 	}
 
 	if article.ID == "" {
-		article.ID = insertedID
+		article.ID = inserted[0].ID
 	}
+	article.CreatedAt = inserted[0].CreatedAt
+	article.UpdatedAt = inserted[0].UpdatedAt
 
 	r.log.Info("Successfully inserted article", map[string]interface{}{
 		"id":    article.ID,
@@ -512,19 +1562,24 @@ func (r *articleRepository) Insert(article *models.Article) error {
 	return nil
 }
 
-// GetDistinctSourceNames retrieves all distinct source names from the articles table
-func (r *articleRepository) GetDistinctSourceNames() ([]string, error) {
-	query := `
-		SELECT DISTINCT source_name
+// GetDistinctSourceNames retrieves all distinct source names from the articles table, ordered
+// by article count
+func (r *articleRepository) GetDistinctSourceNames(ctx context.Context) ([]string, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT source_name
 		FROM articles
 		WHERE source_name IS NOT NULL AND source_name != ''
-		ORDER BY source_name ASC
-	`
+			AND %s
+			AND %s
+		GROUP BY source_name
+		ORDER BY COUNT(*) DESC, source_name ASC
+	`, tenant, r.notDeletedCondition())
 
 	var sourceNames []string
-	if err := r.db.Raw(query).Scan(&sourceNames).Error; err != nil {
+	if err := r.db.WithContext(ctx).Raw(query, tenantArg).Scan(&sourceNames).Error; err != nil {
 		r.log.Error("Failed to query distinct source names", err, nil)
-		return nil, fmt.Errorf("failed to query distinct source names: %w", err)
+		return nil, fmt.Errorf("failed to query distinct source names: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Retrieved distinct source names", map[string]interface{}{
@@ -534,19 +1589,24 @@ func (r *articleRepository) GetDistinctSourceNames() ([]string, error) {
 	return sourceNames, nil
 }
 
-// GetDistinctCategories retrieves all distinct categories from the articles table
-func (r *articleRepository) GetDistinctCategories() ([]string, error) {
-	query := `
-		SELECT DISTINCT unnest(category) AS category
-		FROM articles
+// GetDistinctCategories retrieves all distinct categories from the articles table, ordered by
+// article count
+func (r *articleRepository) GetDistinctCategories(ctx context.Context) ([]string, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT c AS category
+		FROM articles, unnest(category) AS c
 		WHERE category IS NOT NULL AND array_length(category, 1) > 0
-		ORDER BY category ASC
-	`
+			AND %s
+			AND %s
+		GROUP BY c
+		ORDER BY COUNT(*) DESC, c ASC
+	`, tenant, r.notDeletedCondition())
 
 	var categories []string
-	if err := r.db.Raw(query).Scan(&categories).Error; err != nil {
+	if err := r.db.WithContext(ctx).Raw(query, tenantArg).Scan(&categories).Error; err != nil {
 		r.log.Error("Failed to query distinct categories", err, nil)
-		return nil, fmt.Errorf("failed to query distinct categories: %w", err)
+		return nil, fmt.Errorf("failed to query distinct categories: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Retrieved distinct categories", map[string]interface{}{
@@ -555,3 +1615,547 @@ func (r *articleRepository) GetDistinctCategories() ([]string, error) {
 
 	return categories, nil
 }
+
+// GetFrequentTitleTerms counts the titles each word appears in, so a word repeated within a
+// title counts once
+func (r *articleRepository) GetFrequentTitleTerms(ctx context.Context, minCount, limit int) ([]string, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT t.term
+		FROM articles, LATERAL (
+			SELECT DISTINCT w AS term
+			FROM regexp_split_to_table(lower(title), '[^[:alpha:]]+') AS w
+			WHERE length(w) >= 4
+		) t
+		WHERE %s AND %s
+		GROUP BY t.term
+		HAVING COUNT(*) >= ?
+		ORDER BY COUNT(*) DESC, t.term ASC
+		LIMIT ?
+	`, tenant, r.notDeletedCondition())
+
+	var terms []string
+	if err := r.db.WithContext(ctx).Raw(query, tenantArg, minCount, limit).Scan(&terms).Error; err != nil {
+		r.log.Error("Failed to query frequent title terms", err, map[string]interface{}{
+			"min_count": minCount,
+		})
+		return nil, fmt.Errorf("failed to query frequent title terms: %w", wrapDBError(err))
+	}
+
+	return terms, nil
+}
+
+// GetCorpusStats aggregates the stored articles in one query per breakdown. Percentages are
+// left to the caller.
+func (r *articleRepository) GetCorpusStats(ctx context.Context, since time.Time) (*models.CorpusStats, error) {
+	db := r.db.WithContext(ctx)
+	stats := &models.CorpusStats{}
+	tenant, tenantArg := r.tenantCondition(ctx)
+
+	var totals struct {
+		Total      int64
+		Summarized int64
+		Embedded   int64
+	}
+	totalsQuery := fmt.Sprintf(`
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE summary IS NOT NULL AND summary <> '') AS summarized,
+			COUNT(*) FILTER (WHERE description_vector IS NOT NULL) AS embedded
+		FROM articles
+		WHERE %s AND %s
+	`, tenant, r.notDeletedCondition())
+	if err := db.Raw(totalsQuery, tenantArg).Scan(&totals).Error; err != nil {
+		r.log.Error("Failed to count articles for corpus stats", err, nil)
+		return nil, fmt.Errorf("failed to count articles: %w", wrapDBError(err))
+	}
+	stats.TotalArticles = totals.Total
+	stats.SummarizedArticles = totals.Summarized
+	stats.EmbeddedArticles = totals.Embedded
+
+	categoriesQuery := fmt.Sprintf(`
+		SELECT category AS name, COUNT(*) AS count
+		FROM articles, unnest(category) AS category
+		WHERE %s AND %s
+		GROUP BY category
+		ORDER BY count DESC, name ASC
+	`, tenant, r.notDeletedCondition())
+	if err := db.Raw(categoriesQuery, tenantArg).Scan(&stats.Categories).Error; err != nil {
+		r.log.Error("Failed to count articles per category", err, nil)
+		return nil, fmt.Errorf("failed to count articles per category: %w", wrapDBError(err))
+	}
+
+	sourcesQuery := fmt.Sprintf(`
+		SELECT source_name AS name, COUNT(*) AS count
+		FROM articles
+		WHERE %s AND %s
+		GROUP BY source_name
+		ORDER BY count DESC, name ASC
+	`, tenant, r.notDeletedCondition())
+	if err := db.Raw(sourcesQuery, tenantArg).Scan(&stats.Sources).Error; err != nil {
+		r.log.Error("Failed to count articles per source", err, nil)
+		return nil, fmt.Errorf("failed to count articles per source: %w", wrapDBError(err))
+	}
+
+	dailyQuery := fmt.Sprintf(`
+		SELECT to_char(date_trunc('day', created_at AT TIME ZONE 'UTC', 'UTC') AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS date, COUNT(*) AS count
+		FROM articles
+		WHERE created_at >= ? AND %s AND %s
+		GROUP BY date
+		ORDER BY date ASC
+	`, tenant, r.notDeletedCondition())
+	if err := db.Raw(dailyQuery, since, tenantArg).Scan(&stats.DailyAdded).Error; err != nil {
+		r.log.Error("Failed to count articles added per day", err, nil)
+		return nil, fmt.Errorf("failed to count articles added per day: %w", wrapDBError(err))
+	}
+
+	return stats, nil
+}
+
+// articleTables lists the tables an article can be stored in: articles, or articles_archive
+// once the archive job moved it
+var articleTables = []string{"articles", "articles_archive"}
+
+// SoftDelete marks an article as deleted without removing the row, so user events keep a valid
+// reference. Archived articles are marked in articles_archive.
+func (r *articleRepository) SoftDelete(ctx context.Context, id string) error {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	var affected int64
+	for _, table := range articleTables {
+		result := r.db.WithContext(ctx).Exec(fmt.Sprintf(`
+			UPDATE %s
+			SET deleted_at = NOW(), updated_at = NOW()
+			WHERE id = ?::uuid AND deleted_at IS NULL AND %s
+		`, table, tenant), id, tenantArg)
+		if result.Error != nil {
+			r.log.Error("Failed to soft delete article", result.Error, map[string]interface{}{
+				"id":    id,
+				"table": table,
+			})
+			return fmt.Errorf("failed to soft delete article: %w", result.Error)
+		}
+		affected += result.RowsAffected
+		if affected > 0 {
+			break
+		}
+	}
+
+	if affected == 0 {
+		return ErrArticleNotFound
+	}
+
+	r.log.Info("Soft deleted article", map[string]interface{}{
+		"id": id,
+	})
+
+	return nil
+}
+
+// Restore clears the deleted_at marker of a soft-deleted article, live or archived
+func (r *articleRepository) Restore(ctx context.Context, id string) error {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	var affected int64
+	for _, table := range articleTables {
+		result := r.db.WithContext(ctx).Exec(fmt.Sprintf(`
+			UPDATE %s
+			SET deleted_at = NULL, updated_at = NOW()
+			WHERE id = ?::uuid AND deleted_at IS NOT NULL AND %s
+		`, table, tenant), id, tenantArg)
+		if result.Error != nil {
+			r.log.Error("Failed to restore article", result.Error, map[string]interface{}{
+				"id":    id,
+				"table": table,
+			})
+			return fmt.Errorf("failed to restore article: %w", result.Error)
+		}
+		affected += result.RowsAffected
+		if affected > 0 {
+			break
+		}
+	}
+
+	if affected == 0 {
+		return ErrArticleNotFound
+	}
+
+	r.log.Info("Restored article", map[string]interface{}{
+		"id": id,
+	})
+
+	return nil
+}
+
+// Purge permanently removes an article, live or archived, together with the user events
+// referencing it
+func (r *articleRepository) Purge(ctx context.Context, id string) error {
+	var deleted int64
+	tenant, tenantArg := r.tenantCondition(ctx)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM user_events WHERE article_id = ?::uuid AND `+tenant, id, tenantArg).Error; err != nil {
+			return fmt.Errorf("failed to delete user events: %w", wrapDBError(err))
+		}
+
+		for _, table := range articleTables {
+			result := tx.Exec(`DELETE FROM `+table+` WHERE id = ?::uuid AND `+tenant, id, tenantArg)
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete article from %s: %w", table, result.Error)
+			}
+			deleted += result.RowsAffected
+		}
+
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to purge article", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to purge article: %w", wrapDBError(err))
+	}
+
+	if deleted == 0 {
+		return ErrArticleNotFound
+	}
+
+	r.log.Info("Purged article", map[string]interface{}{
+		"id": id,
+	})
+
+	return nil
+}
+
+// FindMissingEnrichment returns up to limit articles of the tenant of ctx with an empty summary
+// or no embedding, or no sentiment when includeSentiment is set, ordered by id and starting
+// strictly after afterID so callers can page through (and resume) the set
+func (r *articleRepository) FindMissingEnrichment(ctx context.Context, afterID string, limit int, includeSentiment bool) ([]MissingEnrichment, error) {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			title,
+			COALESCE(description, '') AS description,
+			COALESCE(summary, '') AS summary,
+			description_vector IS NOT NULL AS has_embedding,
+			sentiment IS NOT NULL AS has_sentiment
+		FROM articles
+		WHERE (description_vector IS NULL OR summary IS NULL OR summary = ''
+				OR (? AND sentiment IS NULL))
+			AND (? = '' OR id > ?::uuid)
+			AND %s
+			AND %s
+		ORDER BY id ASC
+		LIMIT ?
+	`, tenant, r.notDeletedCondition())
+
+	var rows []MissingEnrichment
+	if err := r.db.WithContext(ctx).Raw(query, includeSentiment, afterID, afterID, tenantArg, limit).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query articles missing enrichment", err, map[string]interface{}{
+			"after_id": afterID,
+			"limit":    limit,
+		})
+		return nil, fmt.Errorf("failed to query articles missing enrichment: %w", wrapDBError(err))
+	}
+
+	return rows, nil
+}
+
+// UpdateEnrichment stores a generated summary, embedding and/or sentiment for an article of the
+// tenant of ctx. Empty values leave the existing column untouched. A new embedding is recorded
+// with embeddingModel and the current time.
+func (r *articleRepository) UpdateEnrichment(ctx context.Context, id string, summary string, vector []float64, embeddingModel string, sentiment string) error {
+	var vectorStr interface{}
+	if len(vector) > 0 {
+		vectorStr = formatVector(vector)
+	}
+
+	tenant, tenantArg := r.tenantCondition(ctx)
+	query := `
+		UPDATE articles
+		SET
+			summary = COALESCE(NULLIF(?, ''), summary),
+			description_vector = COALESCE(?::vector, description_vector),
+			embedding_model = CASE WHEN ?::vector IS NULL THEN embedding_model ELSE NULLIF(?, '') END,
+			embedded_at = CASE WHEN ?::vector IS NULL THEN embedded_at ELSE NOW() END,
+			sentiment = COALESCE(NULLIF(?, ''), sentiment),
+			updated_at = NOW()
+		WHERE id = ?::uuid AND ` + tenant
+
+	if err := r.db.WithContext(ctx).Exec(query, summary, vectorStr, vectorStr, embeddingModel, vectorStr, sentiment, id, tenantArg).Error; err != nil {
+		r.log.Error("Failed to update article enrichment", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to update article enrichment: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// UpdateSummary stores a summary generated while answering a query or requested by an editor
+func (r *articleRepository) UpdateSummary(ctx context.Context, id string, summary string) error {
+	tenant, tenantArg := r.tenantCondition(ctx)
+	result := r.db.WithContext(ctx).Exec(fmt.Sprintf(`
+		UPDATE articles
+		SET summary = ?, updated_at = NOW()
+		WHERE id = ?::uuid AND %s AND %s
+	`, tenant, r.notDeletedCondition()), summary, id, tenantArg)
+	if result.Error != nil {
+		r.log.Error("Failed to update article summary", result.Error, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to update article summary: %w", wrapDBError(result.Error))
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrArticleNotFound
+	}
+
+	return nil
+}
+
+// FeedRelevanceScores returns the feed-supplied relevance score of every article. Articles
+// whose score was recomputed keep the feed score in feed_relevance_score.
+func (r *articleRepository) FeedRelevanceScores(ctx context.Context) (map[string]float64, error) {
+	query := fmt.Sprintf(`
+		SELECT id::text AS id, COALESCE(feed_relevance_score, relevance_score) AS score
+		FROM articles
+		WHERE %s
+	`, r.notDeletedCondition())
+
+	var rows []struct {
+		ID    string
+		Score float64
+	}
+	if err := r.db.WithContext(ctx).Raw(query).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query feed relevance scores", err, nil)
+		return nil, fmt.Errorf("failed to query feed relevance scores: %w", wrapDBError(err))
+	}
+
+	scores := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		scores[row.ID] = row.Score
+	}
+	return scores, nil
+}
+
+// relevanceUpdateBatchSize is how many scores one UPDATE statement sets
+const relevanceUpdateBatchSize = 1000
+
+// UpdateRelevanceScores writes scores in batches, one statement per batch. Rows whose score
+// is unchanged are not written. The first update of an article copies its feed score to
+// feed_relevance_score so it can still be blended in later.
+func (r *articleRepository) UpdateRelevanceScores(ctx context.Context, scores map[string]float64) (int64, error) {
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	// A stable order makes concurrent runs lock rows in the same order
+	sort.Strings(ids)
+
+	query := `
+		UPDATE articles AS a
+		SET
+			feed_relevance_score = COALESCE(a.feed_relevance_score, a.relevance_score),
+			relevance_score = v.score,
+			updated_at = NOW()
+		FROM (SELECT unnest(?::uuid[]) AS id, unnest(?::float8[]) AS score) AS v
+		WHERE a.id = v.id AND a.relevance_score IS DISTINCT FROM v.score
+	`
+
+	var changed int64
+	for start := 0; start < len(ids); start += relevanceUpdateBatchSize {
+		batchIDs := ids[start:min(start+relevanceUpdateBatchSize, len(ids))]
+		batchScores := make([]float64, len(batchIDs))
+		for i, id := range batchIDs {
+			batchScores[i] = scores[id]
+		}
+
+		err := withTxRetry(ctx, r.log, "relevance_scores", func() error {
+			result := r.db.WithContext(ctx).Exec(query, pq.Array(batchIDs), pq.Array(batchScores))
+			if result.Error != nil {
+				return result.Error
+			}
+			changed += result.RowsAffected
+			return nil
+		})
+		if err != nil {
+			r.log.Error("Failed to update relevance scores", err, map[string]interface{}{
+				"batch_start": start,
+				"batch_size":  len(batchIDs),
+			})
+			return changed, fmt.Errorf("failed to update relevance scores: %w", wrapDBError(err))
+		}
+	}
+
+	return changed, nil
+}
+
+// ArchiveOlderThan moves one batch in a single statement, so an article is never in both
+// tables or in neither; an id already archived fails the batch rather than losing the row. Soft-deleted articles are archived as they are. Rows locked by other
+// transactions are skipped and picked up by a later batch. Moving an article deletes its
+// saved search matches, which reference articles.
+func (r *articleRepository) ArchiveOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	query := fmt.Sprintf(`
+		WITH moved AS (
+			DELETE FROM articles
+			WHERE id IN (
+				SELECT id FROM articles
+				WHERE publication_date < ?
+				ORDER BY publication_date ASC
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING %[1]s
+		)
+		INSERT INTO articles_archive (%[1]s)
+		SELECT %[1]s FROM moved
+	`, archivedColumns)
+
+	result := r.db.WithContext(ctx).Exec(query, cutoff, batchSize)
+	if result.Error != nil {
+		r.log.Error("Failed to archive articles", result.Error, map[string]interface{}{
+			"cutoff": cutoff,
+		})
+		return 0, fmt.Errorf("failed to archive articles: %w", wrapDBError(result.Error))
+	}
+
+	return result.RowsAffected, nil
+}
+
+// CountOlderThan counts the articles ArchiveOlderThan would move for cutoff
+func (r *articleRepository) CountOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Raw(`SELECT COUNT(*) FROM articles WHERE publication_date < ?`, cutoff).Scan(&count).Error; err != nil {
+		r.log.Error("Failed to count articles to archive", err, map[string]interface{}{
+			"cutoff": cutoff,
+		})
+		return 0, fmt.Errorf("failed to count articles to archive: %w", wrapDBError(err))
+	}
+
+	return count, nil
+}
+
+// replaceCategoriesQuery rewrites a batch of %[1]s rows. Each category matching one of the
+// lowercased names becomes the new category, keeping the position of its first occurrence
+// and dropping later duplicates.
+const replaceCategoriesQuery = `
+	UPDATE %[1]s
+	SET category = ARRAY(
+			SELECT renamed.name
+			FROM (
+				SELECT CASE WHEN LOWER(c) = ANY(?) THEN ? ELSE c END AS name, MIN(ord) AS ord
+				FROM unnest(category) WITH ORDINALITY AS u(c, ord)
+				GROUP BY 1
+			) renamed
+			ORDER BY renamed.ord
+		),
+		updated_at = NOW()
+	WHERE id IN (
+		SELECT id FROM %[1]s
+		WHERE EXISTS (SELECT 1 FROM unnest(category) c WHERE LOWER(c) = ANY(?) AND c <> ?)
+		LIMIT ?
+	)
+`
+
+// ReplaceCategories rewrites the categories of up to batchSize articles, moving on to
+// articles_archive once no live article is left to rewrite
+func (r *articleRepository) ReplaceCategories(ctx context.Context, names []string, category string, batchSize int) (int64, error) {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	var rewritten int64
+	for _, table := range articleTables {
+		result := r.db.WithContext(ctx).Exec(fmt.Sprintf(replaceCategoriesQuery, table),
+			pq.Array(lowered), category, pq.Array(lowered), category, batchSize-int(rewritten))
+		if result.Error != nil {
+			r.log.Error("Failed to replace article categories", result.Error, map[string]interface{}{
+				"table":    table,
+				"category": category,
+			})
+			return rewritten, fmt.Errorf("failed to replace article categories: %w", wrapDBError(result.Error))
+		}
+
+		rewritten += result.RowsAffected
+		if rewritten >= int64(batchSize) {
+			break
+		}
+	}
+
+	return rewritten, nil
+}
+
+// snapshotColumns lists the columns written to snapshots, read from articles and
+// articles_archive alike
+const snapshotColumns = `id, title, description, url, canonical_url, publication_date,
+	source_name, category, relevance_score, latitude, longitude, country, region, summary,
+	content, sentiment, description_vector, created_at, updated_at`
+
+// StreamSnapshot reads articles and articles_archive in (created_at, id) order without loading
+// the result set into memory. Soft-deleted articles are left out.
+func (r *articleRepository) StreamSnapshot(ctx context.Context, after time.Time, includeVectors bool, fn func(SnapshotArticle) error) error {
+	embedding := "NULL"
+	if includeVectors {
+		embedding = "description_vector::text"
+	}
+
+	condition := "deleted_at IS NULL"
+	var args []interface{}
+	if !after.IsZero() {
+		condition += " AND created_at > ?"
+		args = append(args, after, after)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			title,
+			description,
+			url,
+			COALESCE(canonical_url, '') AS canonical_url,
+			publication_date,
+			source_name,
+			category,
+			relevance_score,
+			latitude,
+			longitude,
+			COALESCE(country, '') AS country,
+			COALESCE(region, '') AS region,
+			summary,
+			COALESCE(content, '') AS content,
+			sentiment,
+			%[1]s AS embedding,
+			created_at,
+			updated_at
+		FROM (
+			SELECT %[2]s, deleted_at FROM articles WHERE %[3]s
+			UNION ALL
+			SELECT %[2]s, deleted_at FROM articles_archive WHERE %[3]s
+		) AS articles
+		ORDER BY created_at ASC, id ASC
+	`, embedding, snapshotColumns, condition)
+
+	db := r.db.WithContext(ctx)
+	rows, err := db.Raw(query, args...).Rows()
+	if err != nil {
+		r.log.Error("Failed to stream snapshot articles", err, nil)
+		return fmt.Errorf("failed to query snapshot articles: %w", wrapDBError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article SnapshotArticle
+		if err := db.ScanRows(rows, &article); err != nil {
+			return fmt.Errorf("failed to scan snapshot article: %w", err)
+		}
+		if err := fn(article); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read snapshot articles: %w", wrapDBError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/types"
+
+	"github.com/google/uuid"
+)
+
+// TestWithoutSuspectedDuplicatesKeepsPositions checks that skipping suspected duplicates
+// keeps, for each remaining article, its position in the loaded file, so failures can be
+// reported against the input rather than the filtered slice
+func TestWithoutSuspectedDuplicatesKeepsPositions(t *testing.T) {
+	articles := []models.Article{{Title: "a"}, {Title: "b"}, {Title: "c"}, {Title: "d"}, {Title: "e"}}
+
+	tests := []struct {
+		name          string
+		suspected     []types.SuspectedDuplicate
+		wantTitles    []string
+		wantPositions []int
+	}{
+		{
+			name:          "no suspected duplicates",
+			wantTitles:    []string{"a", "b", "c", "d", "e"},
+			wantPositions: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:          "duplicates in the middle shift later articles",
+			suspected:     []types.SuspectedDuplicate{{Index: 1}, {Index: 2}},
+			wantTitles:    []string{"a", "d", "e"},
+			wantPositions: []int{0, 3, 4},
+		},
+		{
+			name:          "first and last skipped",
+			suspected:     []types.SuspectedDuplicate{{Index: 0}, {Index: 4}},
+			wantTitles:    []string{"b", "c", "d"},
+			wantPositions: []int{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, positions := withoutSuspectedDuplicates(articles, tt.suspected)
+
+			titles := make([]string, len(remaining))
+			for i, article := range remaining {
+				titles[i] = article.Title
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("remaining = %v, want %v", titles, tt.wantTitles)
+			}
+			if !reflect.DeepEqual(positions, tt.wantPositions) {
+				t.Errorf("positions = %v, want %v", positions, tt.wantPositions)
+			}
+		})
+	}
+}
+
+// bulkInsertBenchmarkSize is the number of articles each bulk insert benchmark loads
+const bulkInsertBenchmarkSize = 10000
+
+// syntheticArticles returns n valid articles whose URLs are unique to prefix
+func syntheticArticles(n int, prefix string) []models.Article {
+	published := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	articles := make([]models.Article, n)
+	for i := range articles {
+		url := fmt.Sprintf("https://example.com/%s/%d", prefix, i)
+		articles[i] = models.Article{
+			ID:              uuid.New().String(),
+			Title:           fmt.Sprintf("Synthetic article %d", i),
+			Description:     "A synthetic article loaded by a benchmark",
+			URL:             url,
+			CanonicalURL:    url,
+			SourceName:      "Example",
+			Category:        []string{"world"},
+			RelevanceScore:  float64(i%100) / 100,
+			Latitude:        float64(i%180) - 90,
+			Longitude:       float64(i%360) - 180,
+			PublicationDate: published.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	return articles
+}
+
+// BenchmarkBulkInsert compares loading bulkInsertBenchmarkSize articles with the multi-row
+// chunks of BulkInsert against one Insert per article on the recording database. It measures
+// the client side (building statements and binding arguments) and reports the statements
+// sent per load; BenchmarkBulkInsertDatabase measures the same against PostgreSQL.
+func BenchmarkBulkInsert(b *testing.B) {
+	articles := syntheticArticles(bulkInsertBenchmarkSize, "bench")
+
+	b.Run("chunked", func(b *testing.B) {
+		repos, rec := newTestRepositories(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := repos.article.BulkInsert(context.Background(), articles, 0); err != nil {
+				b.Fatalf("BulkInsert failed: %v", err)
+			}
+		}
+		b.ReportMetric(float64(len(rec.Statements()))/float64(b.N), "statements/op")
+	})
+
+	b.Run("per_row", func(b *testing.B) {
+		repos, rec := newTestRepositories(b)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := range articles {
+				article := articles[j]
+				// The recording database returns no rows, so every insert looks skipped
+				if err := repos.article.Insert(context.Background(), &article); err != ErrDuplicateArticle {
+					b.Fatalf("Insert failed: %v", err)
+				}
+			}
+		}
+		b.ReportMetric(float64(len(rec.Statements()))/float64(b.N), "statements/op")
+	})
+}
+
+// BenchmarkBulkInsertDatabase compares loading bulkInsertBenchmarkSize articles with
+// BulkInsert against one Insert per article on the database named by TEST_DATABASE_URL. Each
+// load goes to a tenant of its own, deleted again outside the timed section.
+func BenchmarkBulkInsertDatabase(b *testing.B) {
+	db := openTestDatabase(b)
+	repo := NewArticleRepository(db, &infra.VectorConfig{IndexType: "hnsw", EfSearch: 40, Probes: 1, SearchLimit: 10},
+		NewSourceAliasRepository(db, infra.NewRecordingLogger()), testDefaultTenant, infra.NewRecordingLogger())
+
+	// load runs insert for a fresh tenant and a fresh set of articles, timing only insert
+	load := func(b *testing.B, insert func(ctx context.Context, articles []models.Article) error) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			suffix := uuid.New().String()[:8]
+			tenant := "bench-" + suffix
+			ctx := infra.WithTenant(context.Background(), tenant)
+			articles := syntheticArticles(bulkInsertBenchmarkSize, suffix)
+			b.StartTimer()
+
+			if err := insert(ctx, articles); err != nil {
+				b.Fatal(err)
+			}
+
+			b.StopTimer()
+			if err := db.Exec("DELETE FROM articles WHERE tenant_id = ?", tenant).Error; err != nil {
+				b.Fatalf("failed to delete the benchmark articles: %v", err)
+			}
+			b.StartTimer()
+		}
+	}
+
+	b.Run("chunked", func(b *testing.B) {
+		load(b, func(ctx context.Context, articles []models.Article) error {
+			stats, err := repo.BulkInsert(ctx, articles, 0)
+			if err != nil {
+				return fmt.Errorf("BulkInsert failed: %w", err)
+			}
+			if stats.SuccessCount != len(articles) {
+				return fmt.Errorf("BulkInsert stored %d of %d articles", stats.SuccessCount, len(articles))
+			}
+			return nil
+		})
+	})
+
+	b.Run("per_row", func(b *testing.B) {
+		load(b, func(ctx context.Context, articles []models.Article) error {
+			for i := range articles {
+				if err := repo.Insert(ctx, &articles[i]); err != nil {
+					return fmt.Errorf("Insert failed: %w", err)
+				}
+			}
+			return nil
+		})
+	})
+}
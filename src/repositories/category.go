@@ -0,0 +1,328 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ErrCategoryNotFound is returned when no category with the given name exists
+var ErrCategoryNotFound = errors.New("category not found")
+
+// ErrCategoryExists is returned when a name or alias is already the name or an alias of
+// another category
+var ErrCategoryExists = errors.New("category already exists")
+
+// CategoryRepository stores the canonical category taxonomy. Names and aliases are matched
+// ignoring case and are unique across all categories.
+type CategoryRepository interface {
+	List(ctx context.Context) ([]models.Category, error)
+	Create(ctx context.Context, name string, aliases []string) (*models.Category, error)
+	// Rename renames the category and keeps the old name as an alias. Renaming a category that
+	// was already renamed to newName returns it unchanged, so a rename can be repeated.
+	Rename(ctx context.Context, name, newName string) (*models.Category, error)
+	// Merge deletes the source categories and adds their names and aliases to the aliases of
+	// target. Sources that already are aliases of target are skipped, so a merge can be
+	// repeated.
+	Merge(ctx context.Context, sources []string, target string) (*models.Category, error)
+}
+
+// categoryRepository implements CategoryRepository
+type categoryRepository struct {
+	db  *gorm.DB
+	log infra.Logger
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository(db *gorm.DB, logger infra.Logger) CategoryRepository {
+	return &categoryRepository{
+		db:  db,
+		log: logger,
+	}
+}
+
+// categoryRow is a categories row as scanned from the database
+type categoryRow struct {
+	ID        string
+	Name      string
+	Aliases   pq.StringArray
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (row categoryRow) model() models.Category {
+	return models.Category{
+		Name:      row.Name,
+		Aliases:   []string(row.Aliases),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+// hasName reports whether name is the row's name or one of its aliases, ignoring case
+func (row categoryRow) hasName(name string) bool {
+	return strings.EqualFold(row.Name, name) || slices.ContainsFunc(row.Aliases, func(alias string) bool {
+		return strings.EqualFold(alias, name)
+	})
+}
+
+// lockCategories serializes taxonomy writes for the rest of the transaction, so the
+// uniqueness of aliases checked by conflicting cannot be raced
+func lockCategories(tx *gorm.DB) error {
+	return tx.Exec(`LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`).Error
+}
+
+// findCategory returns the category named name, ignoring case, or nil
+func findCategory(tx *gorm.DB, name string) (*categoryRow, error) {
+	var rows []categoryRow
+	if err := tx.Raw(`
+		SELECT id, name, aliases, created_at, updated_at
+		FROM categories
+		WHERE LOWER(name) = LOWER(?)
+	`, name).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// conflicting reports whether any of names is the name or an alias of a category other than
+// the one with id exceptID
+func conflicting(tx *gorm.DB, names []string, exceptID string) (bool, error) {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	var count int64
+	if err := tx.Raw(`
+		SELECT COUNT(*)
+		FROM categories
+		WHERE id::text <> ?
+			AND (LOWER(name) = ANY(?) OR EXISTS (SELECT 1 FROM unnest(aliases) a WHERE LOWER(a) = ANY(?)))
+	`, exceptID, pq.Array(lowered), pq.Array(lowered)).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// List returns every category, ordered by name
+func (r *categoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	var rows []categoryRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT name, aliases, created_at, updated_at
+		FROM categories
+		ORDER BY LOWER(name) ASC
+	`).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query categories", err, nil)
+		return nil, fmt.Errorf("failed to query categories: %w", wrapDBError(err))
+	}
+
+	categories := make([]models.Category, len(rows))
+	for i, row := range rows {
+		categories[i] = row.model()
+	}
+	return categories, nil
+}
+
+// Create adds a category, or returns ErrCategoryExists when its name or an alias is taken
+func (r *categoryRepository) Create(ctx context.Context, name string, aliases []string) (*models.Category, error) {
+	var created categoryRow
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategories(tx); err != nil {
+			return err
+		}
+
+		taken, err := conflicting(tx, append([]string{name}, aliases...), "")
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrCategoryExists
+		}
+
+		return tx.Raw(`
+			INSERT INTO categories (name, aliases)
+			VALUES (?, ?)
+			RETURNING id, name, aliases, created_at, updated_at
+		`, name, pq.Array(aliases)).Scan(&created).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrCategoryExists) {
+			return nil, err
+		}
+		r.log.Error("Failed to create category", err, map[string]interface{}{
+			"name": name,
+		})
+		return nil, fmt.Errorf("failed to create category: %w", wrapDBError(err))
+	}
+
+	r.log.Info("Created category", map[string]interface{}{
+		"name":    name,
+		"aliases": aliases,
+	})
+
+	category := created.model()
+	return &category, nil
+}
+
+// Rename renames the category, or returns ErrCategoryNotFound or ErrCategoryExists
+func (r *categoryRepository) Rename(ctx context.Context, name, newName string) (*models.Category, error) {
+	var renamed categoryRow
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategories(tx); err != nil {
+			return err
+		}
+
+		current, err := findCategory(tx, name)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			// A repeated rename finds the category under its new name, with the old one as an alias
+			done, err := findCategory(tx, newName)
+			if err != nil {
+				return err
+			}
+			if done == nil || !done.hasName(name) {
+				return ErrCategoryNotFound
+			}
+			renamed = *done
+			return nil
+		}
+
+		taken, err := conflicting(tx, []string{newName}, current.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrCategoryExists
+		}
+
+		aliases := slices.DeleteFunc(slices.Clone([]string(current.Aliases)), func(alias string) bool {
+			return strings.EqualFold(alias, newName)
+		})
+		if !strings.EqualFold(current.Name, newName) {
+			aliases = append(aliases, current.Name)
+		}
+
+		return tx.Raw(`
+			UPDATE categories
+			SET name = ?, aliases = ?, updated_at = NOW()
+			WHERE id = ?
+			RETURNING id, name, aliases, created_at, updated_at
+		`, newName, pq.Array(aliases), current.ID).Scan(&renamed).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) || errors.Is(err, ErrCategoryExists) {
+			return nil, err
+		}
+		r.log.Error("Failed to rename category", err, map[string]interface{}{
+			"name":     name,
+			"new_name": newName,
+		})
+		return nil, fmt.Errorf("failed to rename category: %w", wrapDBError(err))
+	}
+
+	r.log.Info("Renamed category", map[string]interface{}{
+		"name":     name,
+		"new_name": renamed.Name,
+	})
+
+	category := renamed.model()
+	return &category, nil
+}
+
+// Merge folds the sources into target, or returns ErrCategoryNotFound when target or a
+// source does not exist
+func (r *categoryRepository) Merge(ctx context.Context, sources []string, target string) (*models.Category, error) {
+	var merged categoryRow
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategories(tx); err != nil {
+			return err
+		}
+
+		into, err := findCategory(tx, target)
+		if err != nil {
+			return err
+		}
+		if into == nil {
+			return fmt.Errorf("%w: %s", ErrCategoryNotFound, target)
+		}
+
+		aliases := slices.Clone([]string(into.Aliases))
+		addAlias := func(alias string) {
+			if !strings.EqualFold(alias, into.Name) && !slices.ContainsFunc(aliases, func(a string) bool { return strings.EqualFold(a, alias) }) {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		var sourceIDs []string
+		for _, source := range sources {
+			if into.hasName(source) {
+				continue
+			}
+
+			from, err := findCategory(tx, source)
+			if err != nil {
+				return err
+			}
+			if from == nil {
+				return fmt.Errorf("%w: %s", ErrCategoryNotFound, source)
+			}
+
+			sourceIDs = append(sourceIDs, from.ID)
+			addAlias(from.Name)
+			for _, alias := range from.Aliases {
+				addAlias(alias)
+			}
+		}
+
+		if len(sourceIDs) == 0 {
+			merged = *into
+			return nil
+		}
+
+		if err := tx.Exec(`DELETE FROM categories WHERE id::text = ANY(?)`, pq.Array(sourceIDs)).Error; err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+			UPDATE categories
+			SET aliases = ?, updated_at = NOW()
+			WHERE id = ?
+			RETURNING id, name, aliases, created_at, updated_at
+		`, pq.Array(aliases), into.ID).Scan(&merged).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return nil, err
+		}
+		r.log.Error("Failed to merge categories", err, map[string]interface{}{
+			"sources": sources,
+			"target":  target,
+		})
+		return nil, fmt.Errorf("failed to merge categories: %w", wrapDBError(err))
+	}
+
+	r.log.Info("Merged categories", map[string]interface{}{
+		"sources": sources,
+		"target":  merged.Name,
+	})
+
+	category := merged.model()
+	return &category, nil
+}
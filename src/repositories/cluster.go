@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"news-inshorts/src/infra"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ClusterCandidate is a recent article with an embedding that is not in any cluster yet
+type ClusterCandidate struct {
+	ID              string
+	Embedding       []float64
+	PublicationDate time.Time
+}
+
+// ClusterCentroid is a story cluster as the clustering pass compares articles against it
+type ClusterCentroid struct {
+	ID             string
+	Centroid       []float64
+	EmbeddingModel string
+	MemberCount    int
+	// LatestAt is the publication date of the newest member
+	LatestAt time.Time
+}
+
+// ClusterAssignment places an article in a cluster
+type ClusterAssignment struct {
+	ArticleID string
+	ClusterID string
+}
+
+// ClusterSummary is a cluster's size over a period and its most relevant member in it
+type ClusterSummary struct {
+	ID               string
+	MemberCount      int
+	LatestAt         time.Time
+	RepresentativeID string
+}
+
+// ClusterRepository stores story clusters and the articles assigned to them
+type ClusterRepository interface {
+	// Unclustered returns up to limit live articles published since the given time, embedded
+	// with embeddingModel and in no cluster, oldest first
+	Unclustered(ctx context.Context, since time.Time, embeddingModel string, limit int) ([]ClusterCandidate, error)
+	// Active returns the clusters of embeddingModel whose newest member was published since
+	// the given time
+	Active(ctx context.Context, since time.Time, embeddingModel string) ([]ClusterCentroid, error)
+	// Save creates or updates clusters and stores assignments in one transaction. Articles
+	// that were assigned in the meantime keep their cluster.
+	Save(ctx context.Context, clusters []ClusterCentroid, assignments []ClusterAssignment) error
+	// Prune deletes the clusters whose newest member was published before the given time,
+	// with their assignments, and returns how many were deleted
+	Prune(ctx context.Context, before time.Time) (int64, error)
+	// List returns up to limit clusters with at least minMembers live members published since
+	// the given time, largest first. Only members of the tenant on ctx are counted.
+	List(ctx context.Context, since time.Time, minMembers, limit int) ([]ClusterSummary, error)
+}
+
+// clusterRepository implements ClusterRepository
+type clusterRepository struct {
+	db            *gorm.DB
+	defaultTenant string
+	log           infra.Logger
+}
+
+// NewClusterRepository creates a new instance of ClusterRepository. Listings are scoped to the
+// tenant on their context, or to defaultTenant when it carries none.
+func NewClusterRepository(db *gorm.DB, defaultTenant string, logger infra.Logger) ClusterRepository {
+	return &clusterRepository{
+		db:            db,
+		defaultTenant: defaultTenant,
+		log:           logger,
+	}
+}
+
+// Unclustered returns the articles waiting to be clustered
+func (r *clusterRepository) Unclustered(ctx context.Context, since time.Time, embeddingModel string, limit int) ([]ClusterCandidate, error) {
+	query := `
+		SELECT a.id::text AS id, a.description_vector::text AS embedding, a.publication_date
+		FROM articles a
+		WHERE a.publication_date >= ?
+			AND a.deleted_at IS NULL
+			AND a.description_vector IS NOT NULL
+			AND a.embedding_model = ?
+			AND NOT EXISTS (SELECT 1 FROM article_clusters ac WHERE ac.article_id = a.id)
+		ORDER BY a.publication_date ASC, a.id ASC
+		LIMIT ?
+	`
+
+	var rows []struct {
+		ID              string
+		Embedding       string
+		PublicationDate time.Time
+	}
+	if err := r.db.WithContext(ctx).Raw(query, since, embeddingModel, limit).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query unclustered articles", err, map[string]interface{}{
+			"since": since,
+			"limit": limit,
+		})
+		return nil, fmt.Errorf("failed to query unclustered articles: %w", wrapDBError(err))
+	}
+
+	candidates := make([]ClusterCandidate, 0, len(rows))
+	for _, row := range rows {
+		embedding, err := parseVector(row.Embedding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse embedding of article %s: %w", row.ID, err)
+		}
+		candidates = append(candidates, ClusterCandidate{
+			ID:              row.ID,
+			Embedding:       embedding,
+			PublicationDate: row.PublicationDate,
+		})
+	}
+	return candidates, nil
+}
+
+// Active returns the clusters still taking new members
+func (r *clusterRepository) Active(ctx context.Context, since time.Time, embeddingModel string) ([]ClusterCentroid, error) {
+	query := `
+		SELECT id::text AS id, centroid::text AS centroid, embedding_model, member_count, latest_at
+		FROM clusters
+		WHERE latest_at >= ? AND embedding_model = ?
+		ORDER BY latest_at DESC
+	`
+
+	var rows []struct {
+		ID             string
+		Centroid       string
+		EmbeddingModel string
+		MemberCount    int
+		LatestAt       time.Time
+	}
+	if err := r.db.WithContext(ctx).Raw(query, since, embeddingModel).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query active clusters", err, map[string]interface{}{
+			"since": since,
+		})
+		return nil, fmt.Errorf("failed to query active clusters: %w", wrapDBError(err))
+	}
+
+	clusters := make([]ClusterCentroid, 0, len(rows))
+	for _, row := range rows {
+		centroid, err := parseVector(row.Centroid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse centroid of cluster %s: %w", row.ID, err)
+		}
+		clusters = append(clusters, ClusterCentroid{
+			ID:             row.ID,
+			Centroid:       centroid,
+			EmbeddingModel: row.EmbeddingModel,
+			MemberCount:    row.MemberCount,
+			LatestAt:       row.LatestAt,
+		})
+	}
+	return clusters, nil
+}
+
+// Save upserts the clusters and inserts the assignments
+func (r *clusterRepository) Save(ctx context.Context, clusters []ClusterCentroid, assignments []ClusterAssignment) error {
+	if len(clusters) == 0 && len(assignments) == 0 {
+		return nil
+	}
+
+	articleIDs := make([]string, len(assignments))
+	clusterIDs := make([]string, len(assignments))
+	for i, assignment := range assignments {
+		articleIDs[i] = assignment.ArticleID
+		clusterIDs[i] = assignment.ClusterID
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, cluster := range clusters {
+			if err := tx.Exec(`
+				INSERT INTO clusters (id, centroid, embedding_model, member_count, latest_at)
+				VALUES (?::uuid, ?::vector, ?, ?, ?)
+				ON CONFLICT (id) DO UPDATE SET
+					centroid = EXCLUDED.centroid,
+					member_count = EXCLUDED.member_count,
+					latest_at = EXCLUDED.latest_at,
+					updated_at = NOW()
+			`, cluster.ID, formatVector(cluster.Centroid), cluster.EmbeddingModel, cluster.MemberCount, cluster.LatestAt).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(assignments) == 0 {
+			return nil
+		}
+		return tx.Exec(`
+			INSERT INTO article_clusters (article_id, cluster_id)
+			SELECT * FROM UNNEST(?::uuid[], ?::uuid[])
+			ON CONFLICT (article_id) DO NOTHING
+		`, pq.Array(articleIDs), pq.Array(clusterIDs)).Error
+	})
+	if err != nil {
+		r.log.Error("Failed to save clusters", err, map[string]interface{}{
+			"clusters":    len(clusters),
+			"assignments": len(assignments),
+		})
+		return fmt.Errorf("failed to save clusters: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// Prune deletes clusters that stopped taking new members
+func (r *clusterRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`DELETE FROM clusters WHERE latest_at < ?`, before)
+	if result.Error != nil {
+		r.log.Error("Failed to prune clusters", result.Error, map[string]interface{}{
+			"before": before,
+		})
+		return 0, fmt.Errorf("failed to prune clusters: %w", wrapDBError(result.Error))
+	}
+
+	return result.RowsAffected, nil
+}
+
+// List returns the largest clusters of the period. Sizes and representatives are computed
+// from the members published in it, so relevance rescoring and deletions show up at once.
+func (r *clusterRepository) List(ctx context.Context, since time.Time, minMembers, limit int) ([]ClusterSummary, error) {
+	tenant, tenantArg := tenantCondition("a.tenant_id", infra.TenantFromContext(ctx, r.defaultTenant))
+	query := fmt.Sprintf(`
+		WITH members AS (
+			SELECT ac.cluster_id, a.id, a.relevance_score, a.publication_date
+			FROM article_clusters ac
+			JOIN articles a ON a.id = ac.article_id
+			WHERE a.publication_date >= ? AND a.deleted_at IS NULL AND %s
+		),
+		sizes AS (
+			SELECT cluster_id, COUNT(*) AS member_count, MAX(publication_date) AS latest_at
+			FROM members
+			GROUP BY cluster_id
+			HAVING COUNT(*) >= ?
+		),
+		representatives AS (
+			SELECT DISTINCT ON (cluster_id) cluster_id, id
+			FROM members
+			ORDER BY cluster_id, relevance_score DESC, publication_date DESC, id
+		)
+		SELECT s.cluster_id::text AS id, s.member_count, s.latest_at, r.id::text AS representative_id
+		FROM sizes s
+		JOIN representatives r ON r.cluster_id = s.cluster_id
+		ORDER BY s.member_count DESC, s.latest_at DESC, s.cluster_id
+		LIMIT ?
+	`, tenant)
+
+	var summaries []ClusterSummary
+	if err := r.db.WithContext(ctx).Raw(query, since, tenantArg, minMembers, limit).Scan(&summaries).Error; err != nil {
+		r.log.Error("Failed to list clusters", err, map[string]interface{}{
+			"since": since,
+			"limit": limit,
+		})
+		return nil, fmt.Errorf("failed to list clusters: %w", wrapDBError(err))
+	}
+
+	return summaries, nil
+}
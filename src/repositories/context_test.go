@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"news-inshorts/src/types"
+)
+
+// TestCancelledContextAbortsSlowQuery checks that repository calls stop waiting for a slow
+// query as soon as their context is cancelled, and report the cancellation
+func TestCancelledContextAbortsSlowQuery(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, repos *testRepositories) error
+	}{
+		{"filter articles", func(ctx context.Context, repos *testRepositories) error {
+			_, err := repos.article.FilterArticles(ctx, types.FilterArticlesRequest{Category: []string{"world"}})
+			return err
+		}},
+		{"text search", func(ctx context.Context, repos *testRepositories) error {
+			_, err := repos.article.SearchByText(ctx, []string{"storm"})
+			return err
+		}},
+		{"nearest neighbors", func(ctx context.Context, repos *testRepositories) error {
+			_, err := repos.article.NearestByVector(ctx, []float64{0.1, 0.2, 0.3}, 10, 0.5)
+			return err
+		}},
+		{"find by ids", func(ctx context.Context, repos *testRepositories) error {
+			_, err := repos.article.FindByIDs(ctx, []string{testArticleID})
+			return err
+		}},
+		{"soft delete", func(ctx context.Context, repos *testRepositories) error {
+			return repos.article.SoftDelete(ctx, testArticleID)
+		}},
+		{"events per day", func(ctx context.Context, repos *testRepositories) error {
+			_, err := repos.userEvent.CountByArticleGroupedByTypeAndDay(ctx, testArticleID, since)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, rec := newTestRepositories(t)
+			rec.slow = true
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			done := make(chan error, 1)
+			go func() { done <- tt.call(ctx, repos) }()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("error = %v, want context.Canceled", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("call still running 5s after its context was cancelled")
+			}
+			if len(rec.Statements()) == 0 {
+				t.Error("no statement reached the database")
+			}
+		})
+	}
+}
+
+// TestCancelledContextSkipsQuery checks that a context cancelled before the call never
+// reaches the database
+func TestCancelledContextSkipsQuery(t *testing.T) {
+	repos, rec := newTestRepositories(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repos.article.SearchByText(ctx, []string{"storm"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if statements := rec.Statements(); len(statements) != 0 {
+		t.Errorf("sent %d statements, want none", len(statements))
+	}
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned for a pagination cursor that was not issued by a page query
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor is the position of the last row of a page in a (timestamp DESC, id DESC) order,
+// such as articles by publication_date
+type pageCursor struct {
+	At time.Time
+	ID string
+}
+
+// encodeCursor renders c as an opaque token: URL-safe base64 of "<at RFC3339Nano>|<id>"
+func encodeCursor(c pageCursor) string {
+	raw := c.At.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a token made by encodeCursor
+func decodeCursor(token string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return pageCursor{}, ErrInvalidCursor
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return pageCursor{}, ErrInvalidCursor
+	}
+
+	return pageCursor{At: timestamp.UTC(), ID: id}, nil
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrDatabaseUnavailable is wrapped around errors caused by the database being unreachable,
+// as opposed to errors in the query itself
+var ErrDatabaseUnavailable = errors.New("database unavailable")
+
+// wrapDBError marks connection-level failures with ErrDatabaseUnavailable and returns every
+// other error unchanged
+func wrapDBError(err error) error {
+	// Context errors satisfy net.Error but say nothing about the database
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", ErrDatabaseUnavailable, err)
+	}
+
+	return err
+}
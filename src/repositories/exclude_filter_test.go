@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"news-inshorts/src/types"
+)
+
+// TestFilterArticlesIncludesAndExcludes checks that include and exclude filters are sent as
+// conditions that must all hold
+func TestFilterArticlesIncludesAndExcludes(t *testing.T) {
+	const (
+		includeCategory = `category && ARRAY['technology','science']`
+		includeSource   = `(source_name ILIKE ANY (ARRAY['%reuters%']))`
+		excludeCategory = `NOT (category && ARRAY['sports'])`
+		excludeSource   = `source_name NOT ILIKE ALL (ARRAY['%tabloid%'])`
+	)
+
+	tests := []struct {
+		name        string
+		params      types.FilterArticlesRequest
+		wantPresent []string
+		wantAbsent  []string
+	}{
+		{
+			name:        "includes only",
+			params:      types.FilterArticlesRequest{Category: []string{"technology", "science"}, CategoryMode: types.CategoryModeAny, Source: []string{"reuters"}},
+			wantPresent: []string{includeCategory, includeSource},
+			wantAbsent:  []string{excludeCategory, excludeSource},
+		},
+		{
+			name:        "excludes only",
+			params:      types.FilterArticlesRequest{ExcludeCategory: []string{"sports"}, ExcludeSource: []string{"tabloid"}},
+			wantPresent: []string{excludeCategory, excludeSource},
+			wantAbsent:  []string{includeCategory, includeSource},
+		},
+		{
+			name: "includes and excludes",
+			params: types.FilterArticlesRequest{
+				Category:        []string{"technology", "science"},
+				CategoryMode:    types.CategoryModeAny,
+				Source:          []string{"reuters"},
+				ExcludeCategory: []string{"sports"},
+				ExcludeSource:   []string{"tabloid"},
+			},
+			wantPresent: []string{includeCategory, includeSource, excludeCategory, excludeSource},
+		},
+		{
+			name: "category included, source excluded",
+			params: types.FilterArticlesRequest{
+				Category:      []string{"technology", "science"},
+				CategoryMode:  types.CategoryModeAny,
+				ExcludeSource: []string{"tabloid"},
+			},
+			wantPresent: []string{includeCategory, excludeSource},
+			wantAbsent:  []string{includeSource, excludeCategory},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, rec := newTestRepositories(t)
+
+			if _, err := repos.article.FilterArticles(context.Background(), tt.params); err != nil {
+				t.Fatalf("FilterArticles failed: %v", err)
+			}
+
+			statements := rec.Statements()
+			if len(statements) == 0 {
+				t.Fatal("no statement was sent")
+			}
+			// Source names are resolved through the aliases first; the filter is the last query
+			query := statements[len(statements)-1].Query
+			where, _, ok := strings.Cut(query[strings.Index(query, " WHERE ")+len(" WHERE "):], " ORDER BY ")
+			if !ok {
+				t.Fatalf("query has no WHERE ... ORDER BY: %s", query)
+			}
+
+			for _, condition := range tt.wantPresent {
+				if !strings.Contains(where, condition) {
+					t.Errorf("WHERE clause misses %s:\n%s", condition, where)
+				}
+			}
+			for _, condition := range tt.wantAbsent {
+				if strings.Contains(where, condition) {
+					t.Errorf("WHERE clause has %s:\n%s", condition, where)
+				}
+			}
+			// Every condition must hold, so none may be OR-ed with another at the top level
+			for _, condition := range tt.wantPresent {
+				if strings.Contains(where, condition+" OR ") || strings.Contains(where, " OR "+condition) {
+					t.Errorf("%s is OR-ed with another condition:\n%s", condition, where)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"gorm.io/gorm"
+)
+
+// ExportRunRepository records the corpus snapshots uploaded to object storage
+type ExportRunRepository interface {
+	// Create stores a new run and sets its ID and StartedAt
+	Create(ctx context.Context, run *models.ExportRun) error
+	// Finish stores the outcome of a run: status, counts, watermark, error and finish time
+	Finish(ctx context.Context, run *models.ExportRun) error
+	// List returns the most recent runs, newest first
+	List(ctx context.Context, limit int) ([]models.ExportRun, error)
+	// LastSucceeded returns the most recent successful run, or nil if there is none
+	LastSucceeded(ctx context.Context) (*models.ExportRun, error)
+}
+
+// exportRunRepository implements ExportRunRepository
+type exportRunRepository struct {
+	db  *gorm.DB
+	log infra.Logger
+}
+
+// NewExportRunRepository creates a new instance of ExportRunRepository
+func NewExportRunRepository(db *gorm.DB, logger infra.Logger) ExportRunRepository {
+	return &exportRunRepository{
+		db:  db,
+		log: logger,
+	}
+}
+
+// exportRunColumns is the column list read back for runs
+const exportRunColumns = `id, mode, include_vectors, status, object_key, since, watermark, rows,
+	bytes, duration_ms, COALESCE(error, '') AS error, started_at, finished_at`
+
+// Create inserts a run in the running state
+func (r *exportRunRepository) Create(ctx context.Context, run *models.ExportRun) error {
+	row := r.db.WithContext(ctx).Raw(`
+		INSERT INTO export_runs (mode, include_vectors, status, object_key, since)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id::text, started_at
+	`, run.Mode, run.IncludeVectors, run.Status, run.ObjectKey, run.Since).Row()
+	if err := row.Scan(&run.ID, &run.StartedAt); err != nil {
+		r.log.Error("Failed to create export run", err, map[string]interface{}{
+			"object_key": run.ObjectKey,
+		})
+		return fmt.Errorf("failed to create export run: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// Finish updates the run's outcome
+func (r *exportRunRepository) Finish(ctx context.Context, run *models.ExportRun) error {
+	var runError interface{}
+	if run.Error != "" {
+		runError = run.Error
+	}
+
+	if err := r.db.WithContext(ctx).Exec(`
+		UPDATE export_runs
+		SET status = ?, watermark = ?, rows = ?, bytes = ?, duration_ms = ?, error = ?, finished_at = ?
+		WHERE id = ?::uuid
+	`, run.Status, run.Watermark, run.Rows, run.Bytes, run.DurationMs, runError, run.FinishedAt, run.ID).Error; err != nil {
+		r.log.Error("Failed to finish export run", err, map[string]interface{}{
+			"id": run.ID,
+		})
+		return fmt.Errorf("failed to finish export run: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// List returns the latest runs
+func (r *exportRunRepository) List(ctx context.Context, limit int) ([]models.ExportRun, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM export_runs
+		ORDER BY started_at DESC
+		LIMIT ?
+	`, exportRunColumns)
+
+	var runs []models.ExportRun
+	if err := r.db.WithContext(ctx).Raw(query, limit).Scan(&runs).Error; err != nil {
+		r.log.Error("Failed to query export runs", err, nil)
+		return nil, fmt.Errorf("failed to query export runs: %w", wrapDBError(err))
+	}
+
+	return runs, nil
+}
+
+// LastSucceeded returns the latest successful run
+func (r *exportRunRepository) LastSucceeded(ctx context.Context) (*models.ExportRun, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM export_runs
+		WHERE status = ?
+		ORDER BY started_at DESC
+		LIMIT 1
+	`, exportRunColumns)
+
+	var runs []models.ExportRun
+	if err := r.db.WithContext(ctx).Raw(query, models.ExportStatusSucceeded).Scan(&runs).Error; err != nil {
+		r.log.Error("Failed to query last export run", err, nil)
+		return nil, fmt.Errorf("failed to query last export run: %w", wrapDBError(err))
+	}
+
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return &runs[0], nil
+}
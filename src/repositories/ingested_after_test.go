@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"news-inshorts/src/types"
+)
+
+// TestFilterArticlesIngestedAfter checks that polling by ingestion time keeps only articles
+// created strictly after the cursor, oldest first, and that the limit applies to that order
+func TestFilterArticlesIngestedAfter(t *testing.T) {
+	const cursorID = "00000000-0000-0000-0000-0000000000aa"
+
+	tests := []struct {
+		name          string
+		req           types.FilterArticlesRequest
+		wantCondition string
+		wantTail      string
+	}{
+		{
+			name:          "strictly after the instant",
+			req:           types.FilterArticlesRequest{IngestedAfter: "2026-10-15T08:00:00Z", Limit: 2},
+			wantCondition: `created_at > '2026-10-15T08:00:00Z'`,
+			wantTail:      " ORDER BY created_at ASC, id ASC LIMIT 2",
+		},
+		{
+			name:          "offset timestamps are compared in UTC",
+			req:           types.FilterArticlesRequest{IngestedAfter: "2026-10-15T13:30:00.25+05:30", Limit: 50},
+			wantCondition: `created_at > '2026-10-15T08:00:00.25Z'`,
+			wantTail:      " ORDER BY created_at ASC, id ASC LIMIT 50",
+		},
+		{
+			name:          "ties at the instant are broken by id",
+			req:           types.FilterArticlesRequest{IngestedAfter: "2026-10-15T08:00:00Z", IngestedAfterID: cursorID, Limit: 2},
+			wantCondition: `(created_at, id) > ('2026-10-15T08:00:00Z', '` + cursorID + `')`,
+			wantTail:      " ORDER BY created_at ASC, id ASC LIMIT 2",
+		},
+		{
+			name:          "without a limit every later article is returned",
+			req:           types.FilterArticlesRequest{IngestedAfter: "2026-10-15T08:00:00Z"},
+			wantCondition: `created_at > '2026-10-15T08:00:00Z'`,
+			wantTail:      " ORDER BY created_at ASC, id ASC",
+		},
+		{
+			name:          "ingestion order wins over the score order",
+			req:           types.FilterArticlesRequest{IngestedAfter: "2026-10-15T08:00:00Z", ScoreThreshold: 0.5, Limit: 10},
+			wantCondition: `created_at > '2026-10-15T08:00:00Z'`,
+			wantTail:      " ORDER BY created_at ASC, id ASC LIMIT 10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if err := req.Validate(); err != nil {
+				t.Fatalf("request is invalid: %v", err)
+			}
+
+			repos, rec := newTestRepositories(t)
+			if _, err := repos.article.FilterArticles(context.Background(), req); err != nil {
+				t.Fatalf("FilterArticles failed: %v", err)
+			}
+
+			statements := rec.Statements()
+			if len(statements) == 0 {
+				t.Fatal("no statement was sent")
+			}
+			query := statements[len(statements)-1].Query
+
+			if !strings.Contains(query, tt.wantCondition) {
+				t.Errorf("query misses %s:\n%s", tt.wantCondition, query)
+			}
+			if strings.Contains(query, "created_at >=") {
+				t.Errorf("query keeps articles created at the cursor:\n%s", query)
+			}
+			if !strings.HasSuffix(query, tt.wantTail) {
+				t.Errorf("query does not end with %q:\n%s", tt.wantTail, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"gorm.io/gorm"
+)
+
+// LLMAuditRepository stores sampled query analysis calls for offline prompt evaluation
+type LLMAuditRepository interface {
+	// Insert stores a record and sets its ID and CreatedAt
+	Insert(ctx context.Context, record *models.LLMAuditRecord) error
+	// List returns up to limit records, newest first, starting after cursor (the first page
+	// when empty), and the cursor of the next page, empty on the last one. A malformed cursor
+	// fails with ErrInvalidCursor.
+	List(ctx context.Context, cursor string, limit int) ([]models.LLMAuditRecord, string, error)
+	// Purge deletes the records created before the given time, every record when it is zero,
+	// and returns how many were deleted
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// llmAuditRepository implements LLMAuditRepository
+type llmAuditRepository struct {
+	db  *gorm.DB
+	log infra.Logger
+}
+
+// NewLLMAuditRepository creates a new instance of LLMAuditRepository
+func NewLLMAuditRepository(db *gorm.DB, logger infra.Logger) LLMAuditRepository {
+	return &llmAuditRepository{
+		db:  db,
+		log: logger,
+	}
+}
+
+// llmAuditRow is a llm_audit_records row as scanned from the database; the analysis is JSONB
+type llmAuditRow struct {
+	ID               string
+	Query            string
+	SourcesHash      string
+	CategoriesHash   string
+	RawOutput        string
+	Attempts         int
+	Analysis         []byte
+	Error            string
+	LatencyMs        int64
+	PromptTokens     int
+	CompletionTokens int
+	CreatedAt        time.Time
+}
+
+func (row llmAuditRow) model() models.LLMAuditRecord {
+	record := models.LLMAuditRecord{
+		ID:               row.ID,
+		Query:            row.Query,
+		SourcesHash:      row.SourcesHash,
+		CategoriesHash:   row.CategoriesHash,
+		RawOutput:        row.RawOutput,
+		Attempts:         row.Attempts,
+		Error:            row.Error,
+		LatencyMs:        row.LatencyMs,
+		PromptTokens:     row.PromptTokens,
+		CompletionTokens: row.CompletionTokens,
+		CreatedAt:        row.CreatedAt,
+	}
+
+	if len(row.Analysis) > 0 {
+		var analysis models.QueryAnalysis
+		if err := json.Unmarshal(row.Analysis, &analysis); err == nil {
+			record.Analysis = &analysis
+		}
+	}
+	return record
+}
+
+// Insert stores the record
+func (r *llmAuditRepository) Insert(ctx context.Context, record *models.LLMAuditRecord) error {
+	var analysis, recordError interface{}
+	if record.Analysis != nil {
+		encoded, err := json.Marshal(record.Analysis)
+		if err != nil {
+			return fmt.Errorf("failed to encode query analysis: %w", err)
+		}
+		analysis = string(encoded)
+	}
+	if record.Error != "" {
+		recordError = record.Error
+	}
+
+	row := r.db.WithContext(ctx).Raw(`
+		INSERT INTO llm_audit_records (query, sources_hash, categories_hash, raw_output, attempts,
+			analysis, error, latency_ms, prompt_tokens, completion_tokens)
+		VALUES (?, ?, ?, ?, ?, ?::jsonb, ?, ?, ?, ?)
+		RETURNING id::text, created_at
+	`, record.Query, record.SourcesHash, record.CategoriesHash, record.RawOutput, record.Attempts,
+		analysis, recordError, record.LatencyMs, record.PromptTokens, record.CompletionTokens).Row()
+	if err := row.Scan(&record.ID, &record.CreatedAt); err != nil {
+		r.log.Error("Failed to insert LLM audit record", err, nil)
+		return fmt.Errorf("failed to insert LLM audit record: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// List returns the next page of records, newest first
+func (r *llmAuditRepository) List(ctx context.Context, cursor string, limit int) ([]models.LLMAuditRecord, string, error) {
+	where := "TRUE"
+	var args []interface{}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		where = "(created_at, id) < (?, ?::uuid)"
+		args = append(args, after.At, after.ID)
+	}
+
+	// One extra row tells whether another page follows
+	query := fmt.Sprintf(`
+		SELECT id::text, query, sources_hash, categories_hash, raw_output, attempts,
+			analysis::text AS analysis, COALESCE(error, '') AS error, latency_ms, prompt_tokens,
+			completion_tokens, created_at
+		FROM llm_audit_records
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT %d
+	`, where, limit+1)
+
+	var rows []llmAuditRow
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query LLM audit records", err, map[string]interface{}{
+			"cursor": cursor,
+			"limit":  limit,
+		})
+		return nil, "", fmt.Errorf("failed to query LLM audit records: %w", wrapDBError(err))
+	}
+
+	next := ""
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		next = encodeCursor(pageCursor{At: last.CreatedAt, ID: last.ID})
+	}
+
+	records := make([]models.LLMAuditRecord, len(rows))
+	for i, row := range rows {
+		records[i] = row.model()
+	}
+	return records, next, nil
+}
+
+// Purge deletes the records created before the given time
+func (r *llmAuditRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	db := r.db.WithContext(ctx)
+	var result *gorm.DB
+	if before.IsZero() {
+		result = db.Exec(`DELETE FROM llm_audit_records`)
+	} else {
+		result = db.Exec(`DELETE FROM llm_audit_records WHERE created_at < ?`, before)
+	}
+
+	if result.Error != nil {
+		r.log.Error("Failed to purge LLM audit records", result.Error, map[string]interface{}{
+			"before": before,
+		})
+		return 0, fmt.Errorf("failed to purge LLM audit records: %w", wrapDBError(result.Error))
+	}
+
+	r.log.Info("Purged LLM audit records", map[string]interface{}{
+		"before":  before,
+		"deleted": result.RowsAffected,
+	})
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"gorm.io/gorm"
+)
+
+// QueryLogRepository stores recorded natural-language queries
+type QueryLogRepository interface {
+	// InsertBatch stores entries in one statement, each under its own tenant
+	InsertBatch(ctx context.Context, entries []models.QueryLogEntry) error
+	// Top returns up to limit queries searched in the tenant of ctx since the given time, most
+	// frequent first. With zeroResults only searches that returned no article are counted.
+	Top(ctx context.Context, since time.Time, limit int, zeroResults bool) ([]models.QueryCount, error)
+	// Purge deletes the entries of every tenant created before the given time and returns how
+	// many were deleted
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// queryLogRepository implements QueryLogRepository
+type queryLogRepository struct {
+	db            *gorm.DB
+	defaultTenant string
+	log           infra.Logger
+}
+
+// NewQueryLogRepository creates a new instance of QueryLogRepository. Entries and reports
+// naming no tenant belong to defaultTenant.
+func NewQueryLogRepository(db *gorm.DB, defaultTenant string, logger infra.Logger) QueryLogRepository {
+	return &queryLogRepository{
+		db:            db,
+		defaultTenant: defaultTenant,
+		log:           logger,
+	}
+}
+
+// InsertBatch stores the entries with a multi-row insert
+func (r *queryLogRepository) InsertBatch(ctx context.Context, entries []models.QueryLogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(entries))
+	args := make([]interface{}, 0, len(entries)*7)
+	for i, entry := range entries {
+		tenant := entry.Tenant
+		if tenant == "" {
+			tenant = r.defaultTenant
+		}
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, tenant, entry.Query, entry.HadLocation, entry.ResultCount, entry.LatencyMs, entry.ClientHash, entry.CreatedAt)
+	}
+
+	query := `
+		INSERT INTO query_logs (tenant_id, query, had_location, result_count, latency_ms, client_hash, created_at)
+		VALUES ` + strings.Join(placeholders, ", ")
+
+	if err := r.db.WithContext(ctx).Exec(query, args...).Error; err != nil {
+		r.log.Error("Failed to insert query log entries", err, map[string]interface{}{
+			"entries": len(entries),
+		})
+		return fmt.Errorf("failed to insert query log entries: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// Top groups the entries of the period by query, ignoring case. The most recent spelling of
+// a query is reported.
+func (r *queryLogRepository) Top(ctx context.Context, since time.Time, limit int, zeroResults bool) ([]models.QueryCount, error) {
+	condition := "TRUE"
+	if zeroResults {
+		condition = "result_count = 0"
+	}
+
+	tenant, tenantArg := tenantCondition("tenant_id", infra.TenantFromContext(ctx, r.defaultTenant))
+	query := fmt.Sprintf(`
+		SELECT (ARRAY_AGG(query ORDER BY created_at DESC))[1] AS query,
+			COUNT(*) AS count,
+			COUNT(DISTINCT client_hash) AS clients,
+			AVG(result_count)::float8 AS avg_results,
+			MAX(created_at) AS last_seen_at
+		FROM query_logs
+		WHERE %s AND created_at >= ? AND %s
+		GROUP BY lower(query)
+		ORDER BY count DESC, last_seen_at DESC
+		LIMIT ?
+	`, tenant, condition)
+
+	counts := make([]models.QueryCount, 0, limit)
+	if err := r.db.WithContext(ctx).Raw(query, tenantArg, since, limit).Scan(&counts).Error; err != nil {
+		r.log.Error("Failed to query top queries", err, map[string]interface{}{
+			"since":        since,
+			"zero_results": zeroResults,
+		})
+		return nil, fmt.Errorf("failed to query top queries: %w", wrapDBError(err))
+	}
+
+	return counts, nil
+}
+
+// Purge deletes the entries created before the given time
+func (r *queryLogRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`DELETE FROM query_logs WHERE created_at < ?`, before)
+	if result.Error != nil {
+		r.log.Error("Failed to purge query log entries", result.Error, map[string]interface{}{
+			"before": before,
+		})
+		return 0, fmt.Errorf("failed to purge query log entries: %w", wrapDBError(result.Error))
+	}
+
+	r.log.Info("Purged query log entries", map[string]interface{}{
+		"before":  before,
+		"deleted": result.RowsAffected,
+	})
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordedStatement is a statement sent to a recordingDriver, with the values bound to its
+// $n placeholders
+type recordedStatement struct {
+	Query string
+	Args  []interface{}
+}
+
+// recordingDriver is a database/sql driver that records every statement instead of running
+// it. Queries return no rows and statements affect none, so repositories can be exercised
+// without a database to check the SQL they send. A slow driver blocks every statement until
+// its context is done, like a long-running query.
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []recordedStatement
+	slow       bool
+}
+
+var recordingDriverCount atomic.Int64
+
+// newRecordingDB returns a GORM handle whose statements are recorded by the returned driver
+func newRecordingDB(t testing.TB) (*gorm.DB, *recordingDriver) {
+	t.Helper()
+
+	rec := &recordingDriver{}
+	name := fmt.Sprintf("recording-%d", recordingDriverCount.Add(1))
+	sql.Register(name, rec)
+
+	sqlDB, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("failed to open recording database: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open GORM on the recording database: %v", err)
+	}
+	return db, rec
+}
+
+// Statements returns the statements recorded so far
+func (d *recordingDriver) Statements() []recordedStatement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]recordedStatement(nil), d.statements...)
+}
+
+// run records a statement and, for a slow driver, waits for ctx to be done
+func (d *recordingDriver) run(ctx context.Context, query string, args []driver.NamedValue) error {
+	d.record(query, args)
+	if !d.slow {
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (d *recordingDriver) record(query string, args []driver.NamedValue) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, recordedStatement{Query: query, Args: values})
+}
+
+// Open implements driver.Driver
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+// recordingConn is a connection of a recordingDriver
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{conn: c, query: query}, nil
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.driver.run(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return emptyRows{}, nil
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.driver.run(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// recordingStmt is a prepared statement of a recordingConn
+type recordingStmt struct {
+	conn  *recordingConn
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// recordingTx is a transaction of a recordingConn; it has nothing to commit
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+// emptyRows is a result without rows
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
@@ -1,19 +1,42 @@
 package repositories
 
 import (
+	"news-inshorts/src/infra"
+
 	"gorm.io/gorm"
 )
 
 // Repositories holds all repository instances
 type Repositories struct {
-	Article   ArticleRepository
-	UserEvent UserEventRepository
+	Article        ArticleRepository
+	SourceAlias    SourceAliasRepository
+	Category       CategoryRepository
+	SavedSearch    SavedSearchRepository
+	UserEvent      UserEventRepository
+	UserPreference UserPreferenceRepository
+	VectorIndex    VectorIndexRepository
+	ExportRun      ExportRunRepository
+	LLMAudit       LLMAuditRepository
+	Cluster        ClusterRepository
+	QueryLog       QueryLogRepository
 }
 
-// NewRepositories creates and returns all repository instances
-func NewRepositories(db *gorm.DB) *Repositories {
+// NewRepositories creates and returns all repository instances, all logging to logger.
+// Queries of tenant-scoped data are scoped to defaultTenant when their context names no tenant.
+func NewRepositories(db *gorm.DB, vectorCfg *infra.VectorConfig, defaultTenant string, logger infra.Logger) *Repositories {
+	sourceAliases := NewSourceAliasRepository(db, logger)
+
 	return &Repositories{
-		Article:   NewArticleRepository(db),
-		UserEvent: NewUserEventRepository(db),
+		Article:        NewArticleRepository(db, vectorCfg, sourceAliases, defaultTenant, logger),
+		SourceAlias:    sourceAliases,
+		Category:       NewCategoryRepository(db, logger),
+		SavedSearch:    NewSavedSearchRepository(db, defaultTenant, logger),
+		UserEvent:      NewUserEventRepository(db, defaultTenant, logger),
+		UserPreference: NewUserPreferenceRepository(db, defaultTenant, logger),
+		VectorIndex:    NewVectorIndexRepository(db, vectorCfg, logger),
+		ExportRun:      NewExportRunRepository(db, logger),
+		LLMAudit:       NewLLMAuditRepository(db, logger),
+		Cluster:        NewClusterRepository(db, defaultTenant, logger),
+		QueryLog:       NewQueryLogRepository(db, defaultTenant, logger),
 	}
 }
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"news-inshorts/src/infra"
+)
+
+// SQLSTATE codes of transaction failures that succeed when the transaction is run again
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// Retry settings for transactions aborted by a serialization failure or deadlock
+const (
+	maxTxRetries     = 3
+	txRetryBaseDelay = 50 * time.Millisecond
+)
+
+// retryableSQLState returns the SQLSTATE of err when it is a serialization failure or deadlock.
+// Both pgx and lib/pq errors expose SQLState, so the check does not depend on the driver.
+func retryableSQLState(err error) (string, bool) {
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return "", false
+	}
+
+	code := pgErr.SQLState()
+	return code, code == sqlStateSerializationFailure || code == sqlStateDeadlockDetected
+}
+
+// withTxRetry runs fn and runs it again, up to maxTxRetries times, while it fails with a
+// serialization failure or deadlock. fn must run a whole transaction: Postgres aborts the
+// transaction on these errors, so only a fresh one can succeed. The wait before each retry
+// doubles, with jitter so that the transactions that collided do not collide again.
+func withTxRetry(ctx context.Context, log infra.Logger, operation string, fn func() error) error {
+	delay := txRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > maxTxRetries {
+			return err
+		}
+
+		sqlState, ok := retryableSQLState(err)
+		if !ok {
+			return err
+		}
+
+		wait := delay/2 + rand.N(delay)
+		log.Warn("Retrying transaction", map[string]interface{}{
+			"operation": operation,
+			"attempt":   attempt,
+			"sqlstate":  sqlState,
+			"wait_ms":   wait.Milliseconds(),
+			"error":     err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
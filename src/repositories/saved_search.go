@@ -0,0 +1,268 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ErrSavedSearchNotFound is returned when a user has no saved search with the given id
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+// SavedSearchRepository stores saved searches and the articles that matched them. Searches
+// belong to the tenant of the context they are created with, and are only visible to it.
+type SavedSearchRepository interface {
+	// Create stores search with the lowercased keywords of its query and the query embedding,
+	// which may be nil, and sets its ID and CreatedAt
+	Create(ctx context.Context, search *models.SavedSearch, keywords []string, queryVector []float64, embeddingModel string) error
+	List(ctx context.Context, userID string) ([]models.SavedSearch, error)
+	Get(ctx context.Context, userID, id string) (*models.SavedSearch, error)
+	Delete(ctx context.Context, userID, id string) error
+	CountByUserID(ctx context.Context, userID string) (int64, error)
+	// DeleteByUserID removes every saved search of the user with its matches
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+	// MatchArticles evaluates the articles against every saved search of their tenant and
+	// records new matches. An article matches when it passes the search's category and source
+	// filters and either contains every keyword or is at least minSimilarity similar to the query.
+	MatchArticles(ctx context.Context, articleIDs []string, minSimilarity float64) (int64, error)
+	// Matches returns the latest matches of a search, newest first
+	Matches(ctx context.Context, searchID string, limit int) ([]StoredMatch, error)
+}
+
+// StoredMatch is a saved_search_matches row
+type StoredMatch struct {
+	ArticleID    string
+	Similarity   *float64
+	KeywordMatch bool
+	MatchedAt    time.Time
+}
+
+// savedSearchRepository implements SavedSearchRepository
+type savedSearchRepository struct {
+	db            *gorm.DB
+	defaultTenant string
+	log           infra.Logger
+}
+
+// NewSavedSearchRepository creates a new instance of SavedSearchRepository. Queries are scoped
+// to the tenant of their context, or defaultTenant when it carries none.
+func NewSavedSearchRepository(db *gorm.DB, defaultTenant string, logger infra.Logger) SavedSearchRepository {
+	return &savedSearchRepository{
+		db:            db,
+		defaultTenant: defaultTenant,
+		log:           logger,
+	}
+}
+
+// tenant returns the tenant the queries made with ctx are scoped to
+func (r *savedSearchRepository) tenant(ctx context.Context) string {
+	return infra.TenantFromContext(ctx, r.defaultTenant)
+}
+
+// savedSearchRow is a saved_searches row as scanned from the database
+type savedSearchRow struct {
+	ID        string
+	UserID    string
+	Query     string
+	Category  pq.StringArray
+	Source    pq.StringArray
+	Embedded  bool
+	CreatedAt time.Time
+}
+
+func (row savedSearchRow) model() models.SavedSearch {
+	return models.SavedSearch{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Query:     row.Query,
+		Category:  []string(row.Category),
+		Source:    []string(row.Source),
+		Embedded:  row.Embedded,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+const savedSearchColumns = `id, user_id, query, category, source, query_vector IS NOT NULL AS embedded, created_at`
+
+// Create stores a saved search
+func (r *savedSearchRepository) Create(ctx context.Context, search *models.SavedSearch, keywords []string, queryVector []float64, embeddingModel string) error {
+	var vectorStr interface{}
+	if len(queryVector) > 0 {
+		vectorStr = formatVector(queryVector)
+	}
+
+	var rows []savedSearchRow
+	if err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO saved_searches (tenant_id, user_id, query, keywords, category, source, query_vector, embedding_model)
+		VALUES (?, ?, ?, ?, ?, ?, ?::vector, NULLIF(?, ''))
+		RETURNING `+savedSearchColumns,
+		r.tenant(ctx),
+		search.UserID,
+		search.Query,
+		pq.Array(keywords),
+		pq.Array(search.Category),
+		pq.Array(search.Source),
+		vectorStr,
+		embeddingModel,
+	).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to save search", err, nil)
+		return fmt.Errorf("failed to save search: %w", wrapDBError(err))
+	}
+
+	if len(rows) == 0 {
+		return fmt.Errorf("failed to save search: no row returned")
+	}
+
+	*search = rows[0].model()
+	return nil
+}
+
+// List returns the user's saved searches, newest first
+func (r *savedSearchRepository) List(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	var rows []savedSearchRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT `+savedSearchColumns+`
+		FROM saved_searches
+		WHERE user_id = ? AND tenant_id = ?
+		ORDER BY created_at DESC, id ASC
+	`, userID, r.tenant(ctx)).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query saved searches", err, nil)
+		return nil, fmt.Errorf("failed to query saved searches: %w", wrapDBError(err))
+	}
+
+	searches := make([]models.SavedSearch, len(rows))
+	for i, row := range rows {
+		searches[i] = row.model()
+	}
+	return searches, nil
+}
+
+// Get returns the user's saved search, or ErrSavedSearchNotFound
+func (r *savedSearchRepository) Get(ctx context.Context, userID, id string) (*models.SavedSearch, error) {
+	var rows []savedSearchRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT `+savedSearchColumns+`
+		FROM saved_searches
+		WHERE id = ? AND user_id = ? AND tenant_id = ?
+	`, id, userID, r.tenant(ctx)).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query saved search", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to query saved search: %w", wrapDBError(err))
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrSavedSearchNotFound
+	}
+
+	found := rows[0].model()
+	return &found, nil
+}
+
+// Delete removes the user's saved search and its matches, or returns ErrSavedSearchNotFound
+func (r *savedSearchRepository) Delete(ctx context.Context, userID, id string) error {
+	result := r.db.WithContext(ctx).Exec(`DELETE FROM saved_searches WHERE id = ? AND user_id = ? AND tenant_id = ?`, id, userID, r.tenant(ctx))
+	if result.Error != nil {
+		r.log.Error("Failed to delete saved search", result.Error, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete saved search: %w", wrapDBError(result.Error))
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// CountByUserID returns the number of saved searches of the user
+func (r *savedSearchRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Raw(`SELECT COUNT(*) FROM saved_searches WHERE user_id = ? AND tenant_id = ?`, userID, r.tenant(ctx)).Scan(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count saved searches: %w", wrapDBError(err))
+	}
+	return count, nil
+}
+
+// DeleteByUserID removes every saved search of the user
+func (r *savedSearchRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`DELETE FROM saved_searches WHERE user_id = ? AND tenant_id = ?`, userID, r.tenant(ctx))
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete saved searches: %w", wrapDBError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
+
+// MatchArticles records the saved searches the articles match in a single statement. Only
+// searches of the article's tenant are considered, so it covers articles of every tenant.
+// Sources match when the article's source name contains them, ignoring case, like the filter
+// endpoint; categories when the article shares any of them. Existing matches are kept.
+func (r *savedSearchRepository) MatchArticles(ctx context.Context, articleIDs []string, minSimilarity float64) (int64, error) {
+	if len(articleIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO saved_search_matches (search_id, article_id, similarity, keyword_match)
+		SELECT search_id, article_id, similarity, keyword_match
+		FROM (
+			SELECT
+				s.id AS search_id,
+				a.id AS article_id,
+				CASE
+					WHEN s.query_vector IS NOT NULL AND a.description_vector IS NOT NULL AND a.embedding_model = s.embedding_model
+					THEN 1 - (a.description_vector <=> s.query_vector)
+				END AS similarity,
+				cardinality(s.keywords) > 0 AND NOT EXISTS (
+					SELECT 1 FROM unnest(s.keywords) AS keyword
+					WHERE strpos(LOWER(a.title || ' ' || COALESCE(a.description, '')), keyword) = 0
+				) AS keyword_match
+			FROM articles a
+			JOIN saved_searches s
+				ON s.tenant_id = a.tenant_id
+				AND (cardinality(s.category) = 0 OR a.category && s.category)
+				AND (cardinality(s.source) = 0 OR EXISTS (
+					SELECT 1 FROM unnest(s.source) AS source
+					WHERE strpos(LOWER(a.source_name), LOWER(source)) > 0
+				))
+			WHERE a.id = ANY(?::uuid[]) AND a.deleted_at IS NULL
+		) candidates
+		WHERE keyword_match OR similarity >= ?
+		ON CONFLICT (search_id, article_id) DO NOTHING
+	`, pq.Array(articleIDs), minSimilarity)
+	if result.Error != nil {
+		r.log.Error("Failed to match articles against saved searches", result.Error, map[string]interface{}{
+			"articles": len(articleIDs),
+		})
+		return 0, fmt.Errorf("failed to match saved searches: %w", wrapDBError(result.Error))
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Matches returns the latest matches of a search of the tenant of ctx
+func (r *savedSearchRepository) Matches(ctx context.Context, searchID string, limit int) ([]StoredMatch, error) {
+	var matches []StoredMatch
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT m.article_id, m.similarity, m.keyword_match, m.matched_at
+		FROM saved_search_matches m
+		JOIN saved_searches s ON s.id = m.search_id
+		WHERE m.search_id = ? AND s.tenant_id = ?
+		ORDER BY m.matched_at DESC, m.article_id ASC
+		LIMIT ?
+	`, searchID, r.tenant(ctx), limit).Scan(&matches).Error; err != nil {
+		r.log.Error("Failed to query saved search matches", err, map[string]interface{}{
+			"search_id": searchID,
+		})
+		return nil, fmt.Errorf("failed to query saved search matches: %w", wrapDBError(err))
+	}
+
+	return matches, nil
+}
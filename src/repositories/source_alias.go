@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ErrSourceAliasNotFound is returned when no alias with the given name exists
+var ErrSourceAliasNotFound = errors.New("source alias not found")
+
+// SourceAliasRepository stores the aliases that map source names used in queries to the
+// source names stored on articles
+type SourceAliasRepository interface {
+	List(ctx context.Context) ([]models.SourceAlias, error)
+	Get(ctx context.Context, alias string) (*models.SourceAlias, error)
+	// Upsert creates the alias or replaces its source names. An existing alias whose name
+	// differs only in case is renamed to alias.
+	Upsert(ctx context.Context, alias string, sourceNames []string) (*models.SourceAlias, error)
+	Delete(ctx context.Context, alias string) error
+	// Expand resolves requested source names through the aliases. Names that are aliases are
+	// replaced by their source names, returned in exact; the others are returned unchanged in
+	// partial, to be matched as substrings of source names.
+	Expand(ctx context.Context, sources []string) (exact []string, partial []string, err error)
+}
+
+// sourceAliasRepository implements SourceAliasRepository
+type sourceAliasRepository struct {
+	db  *gorm.DB
+	log infra.Logger
+}
+
+// NewSourceAliasRepository creates a new instance of SourceAliasRepository
+func NewSourceAliasRepository(db *gorm.DB, logger infra.Logger) SourceAliasRepository {
+	return &sourceAliasRepository{
+		db:  db,
+		log: logger,
+	}
+}
+
+// sourceAliasRow is a source_aliases row as scanned from the database
+type sourceAliasRow struct {
+	Alias       string
+	SourceNames pq.StringArray
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (row sourceAliasRow) model() models.SourceAlias {
+	return models.SourceAlias{
+		Alias:       row.Alias,
+		SourceNames: []string(row.SourceNames),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+// List returns every alias, ordered by name
+func (r *sourceAliasRepository) List(ctx context.Context) ([]models.SourceAlias, error) {
+	var rows []sourceAliasRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT alias, source_names, created_at, updated_at
+		FROM source_aliases
+		ORDER BY LOWER(alias) ASC
+	`).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query source aliases", err, nil)
+		return nil, fmt.Errorf("failed to query source aliases: %w", wrapDBError(err))
+	}
+
+	aliases := make([]models.SourceAlias, len(rows))
+	for i, row := range rows {
+		aliases[i] = row.model()
+	}
+	return aliases, nil
+}
+
+// Get returns the alias, ignoring case, or ErrSourceAliasNotFound
+func (r *sourceAliasRepository) Get(ctx context.Context, alias string) (*models.SourceAlias, error) {
+	var rows []sourceAliasRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT alias, source_names, created_at, updated_at
+		FROM source_aliases
+		WHERE LOWER(alias) = LOWER(?)
+	`, alias).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query source alias", err, map[string]interface{}{
+			"alias": alias,
+		})
+		return nil, fmt.Errorf("failed to query source alias: %w", wrapDBError(err))
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrSourceAliasNotFound
+	}
+
+	found := rows[0].model()
+	return &found, nil
+}
+
+// Upsert creates the alias or replaces its source names
+func (r *sourceAliasRepository) Upsert(ctx context.Context, alias string, sourceNames []string) (*models.SourceAlias, error) {
+	var rows []sourceAliasRow
+	if err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO source_aliases (alias, source_names)
+		VALUES (?, ?)
+		ON CONFLICT (LOWER(alias)) DO UPDATE
+		SET alias = EXCLUDED.alias, source_names = EXCLUDED.source_names, updated_at = NOW()
+		RETURNING alias, source_names, created_at, updated_at
+	`, alias, pq.Array(sourceNames)).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to save source alias", err, map[string]interface{}{
+			"alias": alias,
+		})
+		return nil, fmt.Errorf("failed to save source alias: %w", wrapDBError(err))
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("failed to save source alias: no row returned")
+	}
+
+	r.log.Info("Saved source alias", map[string]interface{}{
+		"alias":        alias,
+		"source_names": sourceNames,
+	})
+
+	saved := rows[0].model()
+	return &saved, nil
+}
+
+// Delete removes the alias, ignoring case, or returns ErrSourceAliasNotFound
+func (r *sourceAliasRepository) Delete(ctx context.Context, alias string) error {
+	result := r.db.WithContext(ctx).Exec(`DELETE FROM source_aliases WHERE LOWER(alias) = LOWER(?)`, alias)
+	if result.Error != nil {
+		r.log.Error("Failed to delete source alias", result.Error, map[string]interface{}{
+			"alias": alias,
+		})
+		return fmt.Errorf("failed to delete source alias: %w", wrapDBError(result.Error))
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSourceAliasNotFound
+	}
+
+	r.log.Info("Deleted source alias", map[string]interface{}{
+		"alias": alias,
+	})
+
+	return nil
+}
+
+// Expand resolves requested source names through the aliases in one query
+func (r *sourceAliasRepository) Expand(ctx context.Context, sources []string) ([]string, []string, error) {
+	if len(sources) == 0 {
+		return nil, nil, nil
+	}
+
+	lowered := make([]string, len(sources))
+	for i, source := range sources {
+		lowered[i] = strings.ToLower(strings.TrimSpace(source))
+	}
+
+	var rows []sourceAliasRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT alias, source_names
+		FROM source_aliases
+		WHERE LOWER(alias) = ANY(?)
+	`, pq.Array(lowered)).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to expand source aliases", err, map[string]interface{}{
+			"sources": sources,
+		})
+		return nil, nil, fmt.Errorf("failed to expand source aliases: %w", wrapDBError(err))
+	}
+
+	aliased := make(map[string]bool, len(rows))
+	var exact []string
+	for _, row := range rows {
+		aliased[strings.ToLower(row.Alias)] = true
+		exact = append(exact, row.SourceNames...)
+	}
+
+	var partial []string
+	for i, source := range sources {
+		if !aliased[lowered[i]] {
+			partial = append(partial, source)
+		}
+	}
+
+	return exact, partial, nil
+}
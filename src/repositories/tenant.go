@@ -0,0 +1,7 @@
+package repositories
+
+// tenantCondition returns the SQL condition keeping the rows of tenant, where column holds the
+// row's tenant, along with the argument to bind to its placeholder
+func tenantCondition(column, tenant string) (string, string) {
+	return column + " = ?", tenant
+}
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDatabase connects to the database named by TEST_DATABASE_URL, which must have been
+// initialized with init.sql, and skips the test or benchmark when it is not set
+func openTestDatabase(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	return db
+}
+
+// TestTenantIsolation writes data under tenant A and checks that tenant B can neither read
+// nor change it
+func TestTenantIsolation(t *testing.T) {
+	db := openTestDatabase(t)
+	log := infra.NewRecordingLogger()
+	repos := NewRepositories(db, &infra.VectorConfig{IndexType: "hnsw", EfSearch: 40, Probes: 1, SearchLimit: 10}, testDefaultTenant, log)
+
+	suffix := uuid.New().String()[:8]
+	ctxA := infra.WithTenant(context.Background(), "tenant-a-"+suffix)
+	ctxB := infra.WithTenant(context.Background(), "tenant-b-"+suffix)
+	userID := "user-" + suffix
+
+	article := &models.Article{
+		ID:              uuid.New().String(),
+		Title:           "Tenant isolation " + suffix,
+		Description:     "Written by tenant A",
+		URL:             "https://example.com/tenant-isolation/" + suffix,
+		SourceName:      "Example",
+		Category:        []string{"world"},
+		RelevanceScore:  0.5,
+		PublicationDate: time.Now().UTC(),
+	}
+	if err := repos.Article.Insert(ctxA, article); err != nil {
+		t.Fatalf("tenant A failed to insert an article: %v", err)
+	}
+	t.Cleanup(func() { _ = repos.Article.WithDeleted().Purge(ctxA, article.ID) })
+
+	t.Run("articles", func(t *testing.T) {
+		if _, err := repos.Article.FindByID(ctxB, article.ID); !errors.Is(err, ErrArticleNotFound) {
+			t.Errorf("tenant B FindByID error = %v, want ErrArticleNotFound", err)
+		}
+		if exists, err := repos.Article.Exists(ctxB, article.ID); err != nil || exists {
+			t.Errorf("tenant B Exists = %v, %v; want false", exists, err)
+		}
+		if found, err := repos.Article.FindByIDs(ctxB, []string{article.ID}); err != nil || len(found) != 0 {
+			t.Errorf("tenant B FindByIDs = %d articles, %v; want none", len(found), err)
+		}
+		if err := repos.Article.UpdateSummary(ctxB, article.ID, "changed by B"); !errors.Is(err, ErrArticleNotFound) {
+			t.Errorf("tenant B UpdateSummary error = %v, want ErrArticleNotFound", err)
+		}
+		if err := repos.Article.SoftDelete(ctxB, article.ID); !errors.Is(err, ErrArticleNotFound) {
+			t.Errorf("tenant B SoftDelete error = %v, want ErrArticleNotFound", err)
+		}
+		if err := repos.Article.WithDeleted().Purge(ctxB, article.ID); !errors.Is(err, ErrArticleNotFound) {
+			t.Errorf("tenant B Purge error = %v, want ErrArticleNotFound", err)
+		}
+
+		// A deleted article cannot be restored by another tenant either
+		if err := repos.Article.SoftDelete(ctxA, article.ID); err != nil {
+			t.Fatalf("tenant A SoftDelete: %v", err)
+		}
+		if err := repos.Article.WithDeleted().Restore(ctxB, article.ID); !errors.Is(err, ErrArticleNotFound) {
+			t.Errorf("tenant B Restore error = %v, want ErrArticleNotFound", err)
+		}
+		if err := repos.Article.WithDeleted().Restore(ctxA, article.ID); err != nil {
+			t.Fatalf("tenant A Restore: %v", err)
+		}
+
+		stored, err := repos.Article.FindByID(ctxA, article.ID)
+		if err != nil {
+			t.Fatalf("tenant A FindByID: %v", err)
+		}
+		if stored.Summary == "changed by B" {
+			t.Error("tenant B changed the summary of tenant A's article")
+		}
+	})
+
+	t.Run("events", func(t *testing.T) {
+		event := &models.UserEvent{
+			UserID:    userID,
+			ArticleID: article.ID,
+			EventType: "view",
+			Timestamp: time.Now().UTC(),
+			Latitude:  12.97,
+			Longitude: 77.59,
+		}
+		if err := repos.UserEvent.Create(ctxA, event); err != nil {
+			t.Fatalf("tenant A failed to record an event: %v", err)
+		}
+		t.Cleanup(func() { _, _ = repos.UserEvent.DeleteByUserID(ctxA, userID) })
+
+		since := time.Now().Add(-time.Hour)
+		if events, err := repos.UserEvent.FindByArticleID(ctxB, article.ID, since); err != nil || len(events) != 0 {
+			t.Errorf("tenant B FindByArticleID = %d events, %v; want none", len(events), err)
+		}
+		if deleted, err := repos.UserEvent.DeleteByUserID(ctxB, userID); err != nil || deleted != 0 {
+			t.Errorf("tenant B DeleteByUserID = %d, %v; want 0", deleted, err)
+		}
+		if events, err := repos.UserEvent.FindByArticleID(ctxA, article.ID, since); err != nil || len(events) != 1 {
+			t.Errorf("tenant A FindByArticleID = %d events, %v; want 1", len(events), err)
+		}
+	})
+
+	t.Run("saved searches", func(t *testing.T) {
+		search := &models.SavedSearch{UserID: userID, Query: "tenant isolation"}
+		if err := repos.SavedSearch.Create(ctxA, search, []string{"tenant", "isolation"}, nil, ""); err != nil {
+			t.Fatalf("tenant A failed to save a search: %v", err)
+		}
+		t.Cleanup(func() { _, _ = repos.SavedSearch.DeleteByUserID(ctxA, userID) })
+
+		if searches, err := repos.SavedSearch.List(ctxB, userID); err != nil || len(searches) != 0 {
+			t.Errorf("tenant B List = %d searches, %v; want none", len(searches), err)
+		}
+		if _, err := repos.SavedSearch.Get(ctxB, userID, search.ID); !errors.Is(err, ErrSavedSearchNotFound) {
+			t.Errorf("tenant B Get error = %v, want ErrSavedSearchNotFound", err)
+		}
+		if err := repos.SavedSearch.Delete(ctxB, userID, search.ID); !errors.Is(err, ErrSavedSearchNotFound) {
+			t.Errorf("tenant B Delete error = %v, want ErrSavedSearchNotFound", err)
+		}
+		if deleted, err := repos.SavedSearch.DeleteByUserID(ctxB, userID); err != nil || deleted != 0 {
+			t.Errorf("tenant B DeleteByUserID = %d, %v; want 0", deleted, err)
+		}
+		if count, err := repos.SavedSearch.CountByUserID(ctxA, userID); err != nil || count != 1 {
+			t.Errorf("tenant A CountByUserID = %d, %v; want 1", count, err)
+		}
+	})
+
+	t.Run("preferences", func(t *testing.T) {
+		if err := repos.UserPreference.Replace(ctxA, userID, map[string]float64{"sports": 1}); err != nil {
+			t.Fatalf("tenant A failed to set preferences: %v", err)
+		}
+		t.Cleanup(func() { _, _ = repos.UserPreference.DeleteByUserID(ctxA, userID) })
+
+		if prefs, err := repos.UserPreference.Get(ctxB, userID); err != nil || len(prefs) != 0 {
+			t.Errorf("tenant B Get = %v, %v; want none", prefs, err)
+		}
+		// The same user id in tenant B is another user, with preferences of its own
+		if err := repos.UserPreference.Replace(ctxB, userID, map[string]float64{"politics": -1}); err != nil {
+			t.Fatalf("tenant B failed to set preferences: %v", err)
+		}
+		if _, err := repos.UserPreference.DeleteByUserID(ctxB, userID); err != nil {
+			t.Fatalf("tenant B DeleteByUserID: %v", err)
+		}
+
+		prefs, err := repos.UserPreference.Get(ctxA, userID)
+		if err != nil {
+			t.Fatalf("tenant A Get: %v", err)
+		}
+		if len(prefs) != 1 || prefs["sports"] != 1 {
+			t.Errorf("tenant A preferences = %v, want map[sports:1]", prefs)
+		}
+	})
+}
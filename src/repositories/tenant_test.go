@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/types"
+)
+
+const (
+	testDefaultTenant = "default"
+	testTenant        = "tenant-b"
+	testArticleID     = "00000000-0000-0000-0000-000000000001"
+)
+
+func TestTenantCondition(t *testing.T) {
+	tests := []struct {
+		column, tenant string
+		wantCondition  string
+	}{
+		{column: "tenant_id", tenant: "acme", wantCondition: "tenant_id = ?"},
+		{column: "a.tenant_id", tenant: "acme", wantCondition: "a.tenant_id = ?"},
+		// The tenant is bound, never spliced into the SQL
+		{column: "tenant_id", tenant: "x' OR '1'='1", wantCondition: "tenant_id = ?"},
+	}
+
+	for _, tt := range tests {
+		condition, arg := tenantCondition(tt.column, tt.tenant)
+		if condition != tt.wantCondition {
+			t.Errorf("tenantCondition(%q, %q) condition = %q, want %q", tt.column, tt.tenant, condition, tt.wantCondition)
+		}
+		if arg != tt.tenant {
+			t.Errorf("tenantCondition(%q, %q) arg = %q, want %q", tt.column, tt.tenant, arg, tt.tenant)
+		}
+	}
+}
+
+// tenantPlaceholder matches a tenant condition as sent to the database, e.g. a.tenant_id = $3
+var tenantPlaceholder = regexp.MustCompile(`(?:\w+\.)?tenant_id = \$(\d+)`)
+
+// testRepositories holds repositories sharing a recording database
+type testRepositories struct {
+	article     ArticleRepository
+	userEvent   UserEventRepository
+	savedSearch SavedSearchRepository
+	userPref    UserPreferenceRepository
+	cluster     ClusterRepository
+	queryLog    QueryLogRepository
+}
+
+func newTestRepositories(t testing.TB) (*testRepositories, *recordingDriver) {
+	t.Helper()
+
+	db, rec := newRecordingDB(t)
+	logger := infra.NewRecordingLogger()
+	vectorCfg := &infra.VectorConfig{IndexType: "hnsw", EfSearch: 40, Probes: 1, SearchLimit: 10}
+	return &testRepositories{
+		article:     NewArticleRepository(db, vectorCfg, NewSourceAliasRepository(db, logger), testDefaultTenant, logger),
+		userEvent:   NewUserEventRepository(db, testDefaultTenant, logger),
+		savedSearch: NewSavedSearchRepository(db, testDefaultTenant, logger),
+		userPref:    NewUserPreferenceRepository(db, testDefaultTenant, logger),
+		cluster:     NewClusterRepository(db, testDefaultTenant, logger),
+		queryLog:    NewQueryLogRepository(db, testDefaultTenant, logger),
+	}, rec
+}
+
+// TestTenantScopedQueries checks that every query of tenant-scoped data binds the tenant of
+// its context to each tenant condition it sends
+func TestTenantScopedQueries(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+	vector := []float64{0.1, 0.2, 0.3}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, r *testRepositories)
+	}{
+		{"article FindPage", func(ctx context.Context, r *testRepositories) { _, _, _ = r.article.FindPage(ctx, "", 10) }},
+		{"article SearchByText", func(ctx context.Context, r *testRepositories) { _, _ = r.article.SearchByText(ctx, []string{"storm"}) }},
+		{"article SearchByTextFiltered", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.SearchByTextFiltered(ctx, []string{"storm"}, TextSearchFilters{Category: "world", Limit: 5})
+		}},
+		{"article NearestByVector", func(ctx context.Context, r *testRepositories) { _, _ = r.article.NearestByVector(ctx, vector, 5, 0.5) }},
+		{"article SimilarityByIDs", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.SimilarityByIDs(ctx, vector, []string{testArticleID})
+		}},
+		{"article SimilarPairs", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.SimilarPairs(ctx, []string{testArticleID, "00000000-0000-0000-0000-000000000002"}, 0.9)
+		}},
+		{"article FindSimilarTitles", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.FindSimilarTitles(ctx, []string{"Storm hits the coast"}, 0.8)
+		}},
+		{"article FilterArticles", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.FilterArticles(ctx, types.FilterArticlesRequest{Category: []string{"world"}})
+		}},
+		{"article CountFilteredArticles", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.CountFilteredArticles(ctx, types.FilterArticlesRequest{Category: []string{"world"}})
+		}},
+		{"article StreamFilteredArticles", func(ctx context.Context, r *testRepositories) {
+			_ = r.article.StreamFilteredArticles(ctx, types.FilterArticlesRequest{}, 10, func(models.Article) error { return nil })
+		}},
+		{"article FindByIDs", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.FindByIDs(ctx, []string{testArticleID})
+		}},
+		{"article FindByIDsPublishedSince", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.FindByIDsPublishedSince(ctx, []string{testArticleID}, since)
+		}},
+		{"article FindByID", func(ctx context.Context, r *testRepositories) { _, _ = r.article.FindByID(ctx, testArticleID) }},
+		{"article Exists", func(ctx context.Context, r *testRepositories) { _, _ = r.article.Exists(ctx, testArticleID) }},
+		{"article GetDistinctSourceNames", func(ctx context.Context, r *testRepositories) { _, _ = r.article.GetDistinctSourceNames(ctx) }},
+		{"article GetDistinctCategories", func(ctx context.Context, r *testRepositories) { _, _ = r.article.GetDistinctCategories(ctx) }},
+		{"article GetFrequentTitleTerms", func(ctx context.Context, r *testRepositories) { _, _ = r.article.GetFrequentTitleTerms(ctx, 2, 10) }},
+		{"article SoftDelete", func(ctx context.Context, r *testRepositories) { _ = r.article.SoftDelete(ctx, testArticleID) }},
+		{"article Restore", func(ctx context.Context, r *testRepositories) {
+			_ = r.article.WithDeleted().Restore(ctx, testArticleID)
+		}},
+		{"article Purge", func(ctx context.Context, r *testRepositories) { _ = r.article.WithDeleted().Purge(ctx, testArticleID) }},
+		{"article FindMissingEnrichment", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.article.FindMissingEnrichment(ctx, "", 10, true)
+		}},
+		{"article UpdateEnrichment", func(ctx context.Context, r *testRepositories) {
+			_ = r.article.UpdateEnrichment(ctx, testArticleID, "summary", vector, "test-model", "neutral")
+		}},
+		{"article UpdateSummary", func(ctx context.Context, r *testRepositories) {
+			_ = r.article.UpdateSummary(ctx, testArticleID, "summary")
+		}},
+		{"article GetCorpusStats", func(ctx context.Context, r *testRepositories) { _, _ = r.article.GetCorpusStats(ctx, since) }},
+		{"event FindByArticleID", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.userEvent.FindByArticleID(ctx, testArticleID, since)
+		}},
+		{"event FindByLocation", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.userEvent.FindByLocation(ctx, 12.97, 77.59, 10, since)
+		}},
+		{"event GetArticleIDsWithEventsSince", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.userEvent.GetArticleIDsWithEventsSince(ctx, since)
+		}},
+		{"event CountByArticle", func(ctx context.Context, r *testRepositories) { _, _ = r.userEvent.CountByArticle(ctx, testArticleID) }},
+		{"event DeleteByUserID", func(ctx context.Context, r *testRepositories) { _, _ = r.userEvent.DeleteByUserID(ctx, "user-1") }},
+		{"event CountByArticleGroupedByTypeAndDay", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.userEvent.CountByArticleGroupedByTypeAndDay(ctx, testArticleID, since)
+		}},
+		{"event Bookmarks", func(ctx context.Context, r *testRepositories) { _, _, _ = r.userEvent.Bookmarks(ctx, "user-1", 10, 0) }},
+		{"event CountByTypeAndBucket", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.userEvent.CountByTypeAndBucket(ctx, since, time.Now(), "day")
+		}},
+		{"event TopArticlesByEngagement", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.userEvent.TopArticlesByEngagement(ctx, since, time.Now(), 5)
+		}},
+		{"event TopSourcesByEngagement", func(ctx context.Context, r *testRepositories) {
+			_, _ = r.userEvent.TopSourcesByEngagement(ctx, since, time.Now(), 5)
+		}},
+		{"saved search List", func(ctx context.Context, r *testRepositories) { _, _ = r.savedSearch.List(ctx, "user-1") }},
+		{"saved search Get", func(ctx context.Context, r *testRepositories) { _, _ = r.savedSearch.Get(ctx, "user-1", testArticleID) }},
+		{"saved search Delete", func(ctx context.Context, r *testRepositories) { _ = r.savedSearch.Delete(ctx, "user-1", testArticleID) }},
+		{"saved search CountByUserID", func(ctx context.Context, r *testRepositories) { _, _ = r.savedSearch.CountByUserID(ctx, "user-1") }},
+		{"saved search DeleteByUserID", func(ctx context.Context, r *testRepositories) { _, _ = r.savedSearch.DeleteByUserID(ctx, "user-1") }},
+		{"saved search Matches", func(ctx context.Context, r *testRepositories) { _, _ = r.savedSearch.Matches(ctx, testArticleID, 10) }},
+		{"preference Get", func(ctx context.Context, r *testRepositories) { _, _ = r.userPref.Get(ctx, "user-1") }},
+		{"preference Replace", func(ctx context.Context, r *testRepositories) {
+			_ = r.userPref.Replace(ctx, "user-1", map[string]float64{"sports": 1})
+		}},
+		{"preference DeleteByUserID", func(ctx context.Context, r *testRepositories) { _, _ = r.userPref.DeleteByUserID(ctx, "user-1") }},
+		{"cluster List", func(ctx context.Context, r *testRepositories) { _, _ = r.cluster.List(ctx, since, 2, 10) }},
+		{"query log Top", func(ctx context.Context, r *testRepositories) { _, _ = r.queryLog.Top(ctx, since, 10, false) }},
+	}
+
+	contexts := []struct {
+		name   string
+		ctx    context.Context
+		tenant string
+	}{
+		{name: "tenant on context", ctx: infra.WithTenant(context.Background(), testTenant), tenant: testTenant},
+		{name: "no tenant on context", ctx: context.Background(), tenant: testDefaultTenant},
+	}
+
+	for _, c := range contexts {
+		for _, tt := range tests {
+			t.Run(c.name+"/"+tt.name, func(t *testing.T) {
+				repos, rec := newTestRepositories(t)
+				tt.call(c.ctx, repos)
+				assertTenantBound(t, rec.Statements(), c.tenant)
+			})
+		}
+	}
+}
+
+// TestTenantScopedInserts checks that rows are stored under the tenant of the context
+func TestTenantScopedInserts(t *testing.T) {
+	ctx := infra.WithTenant(context.Background(), testTenant)
+
+	tests := []struct {
+		name  string
+		table string
+		call  func(r *testRepositories)
+	}{
+		{"article Insert", "articles", func(r *testRepositories) {
+			_ = r.article.Insert(ctx, &models.Article{
+				Title:           "Storm",
+				URL:             "https://example.com/storm",
+				SourceName:      "Example",
+				Category:        []string{"world"},
+				PublicationDate: time.Now(),
+			})
+		}},
+		{"event Create", "user_events", func(r *testRepositories) {
+			_ = r.userEvent.Create(ctx, &models.UserEvent{UserID: "user-1", ArticleID: testArticleID, EventType: "view", Timestamp: time.Now()})
+		}},
+		{"saved search Create", "saved_searches", func(r *testRepositories) {
+			_ = r.savedSearch.Create(ctx, &models.SavedSearch{UserID: "user-1", Query: "storm"}, []string{"storm"}, nil, "")
+		}},
+		{"preference Replace", "user_preferences", func(r *testRepositories) {
+			_ = r.userPref.Replace(ctx, "user-1", map[string]float64{"sports": 1})
+		}},
+		{"query log InsertBatch", "query_logs", func(r *testRepositories) {
+			_ = r.queryLog.InsertBatch(ctx, []models.QueryLogEntry{{Tenant: testTenant, Query: "storm", CreatedAt: time.Now()}})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, rec := newTestRepositories(t)
+			tt.call(repos)
+
+			for _, stmt := range rec.Statements() {
+				if !strings.Contains(stmt.Query, "INSERT INTO "+tt.table) {
+					continue
+				}
+				if !strings.Contains(stmt.Query, "tenant_id") {
+					t.Fatalf("insert into %s stores no tenant: %s", tt.table, stmt.Query)
+				}
+				for _, arg := range stmt.Args {
+					if arg == testTenant {
+						return
+					}
+				}
+				t.Fatalf("insert into %s does not bind tenant %q; args %v", tt.table, testTenant, stmt.Args)
+			}
+			t.Fatalf("no insert into %s was sent", tt.table)
+		})
+	}
+}
+
+// TestQueryLogInsertDefaultsTenant checks that entries recorded without a tenant are stored
+// under the default tenant
+func TestQueryLogInsertDefaultsTenant(t *testing.T) {
+	repos, rec := newTestRepositories(t)
+	_ = repos.queryLog.InsertBatch(context.Background(), []models.QueryLogEntry{{Query: "storm", CreatedAt: time.Now()}})
+
+	statements := rec.Statements()
+	if len(statements) != 1 {
+		t.Fatalf("got %d statements, want 1", len(statements))
+	}
+	if got := statements[0].Args[0]; got != testDefaultTenant {
+		t.Errorf("tenant_id bound to %v, want %q", got, testDefaultTenant)
+	}
+}
+
+// assertTenantBound fails unless the statements contain a tenant condition and every tenant
+// condition binds tenant
+func assertTenantBound(t *testing.T, statements []recordedStatement, tenant string) {
+	t.Helper()
+
+	conditions := 0
+	for _, stmt := range statements {
+		for _, match := range tenantPlaceholder.FindAllStringSubmatch(stmt.Query, -1) {
+			conditions++
+			n, _ := strconv.Atoi(match[1])
+			if n < 1 || n > len(stmt.Args) {
+				t.Errorf("%s refers to missing argument $%d: %s", match[0], n, stmt.Query)
+				continue
+			}
+			if got := stmt.Args[n-1]; got != tenant {
+				t.Errorf("%s bound to %v, want %q: %s", match[0], got, tenant, stmt.Query)
+			}
+		}
+	}
+	if conditions == 0 {
+		t.Errorf("no tenant condition in %d statements: %v", len(statements), statements)
+	}
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// trendingBenchmarkArticles and trendingBenchmarkEvents are the numbers of articles and
+	// user events BenchmarkTrendingCandidates spreads over trendingBenchmarkSpan
+	trendingBenchmarkArticles = 20000
+	trendingBenchmarkEvents   = 200000
+	trendingBenchmarkSpan     = 90 * 24 * time.Hour
+
+	// trendingBenchmarkWindow and trendingBenchmarkMaxAge match the trending window of the
+	// trending service and the default TRENDING_MAX_ARTICLE_AGE
+	trendingBenchmarkWindow = 7 * 24 * time.Hour
+	trendingBenchmarkMaxAge = 14 * 24 * time.Hour
+)
+
+// BenchmarkTrendingCandidates compares how trending candidates are loaded, on the database
+// named by TEST_DATABASE_URL: before, every article with any user event was read back; after,
+// only articles with events in the trending window and published within the maximum article
+// age are. Articles and events are published and recorded uniformly over 90 days under a
+// tenant of their own and deleted when the benchmark ends.
+func BenchmarkTrendingCandidates(b *testing.B) {
+	db := openTestDatabase(b)
+	tenant := "bench-" + uuid.New().String()[:8]
+	ctx := infra.WithTenant(context.Background(), tenant)
+	log := infra.NewRecordingLogger()
+	articleRepo := NewArticleRepository(db, &infra.VectorConfig{IndexType: "hnsw", EfSearch: 40, Probes: 1, SearchLimit: 10},
+		NewSourceAliasRepository(db, log), testDefaultTenant, log)
+	eventRepo := NewUserEventRepository(db, testDefaultTenant, log)
+	userID := "user-" + tenant
+
+	b.Cleanup(func() {
+		_, _ = eventRepo.DeleteByUserID(ctx, userID)
+		_ = db.Exec("DELETE FROM articles WHERE tenant_id = ?", tenant).Error
+	})
+
+	now := time.Now().UTC()
+	articles := syntheticArticles(trendingBenchmarkArticles, tenant)
+	for i := range articles {
+		articles[i].PublicationDate = now.Add(-trendingBenchmarkSpan * time.Duration(i) / trendingBenchmarkArticles)
+	}
+	stats, err := articleRepo.BulkInsert(ctx, articles, 0)
+	if err != nil {
+		b.Fatalf("failed to store the benchmark articles: %v", err)
+	}
+	if stats.SuccessCount != len(articles) {
+		b.Fatalf("stored %d of %d benchmark articles", stats.SuccessCount, len(articles))
+	}
+
+	// Each article gets its events at its publication, so older articles have older events
+	const eventBatch = 1000
+	for start := 0; start < trendingBenchmarkEvents; start += eventBatch {
+		events := make([]*models.UserEvent, eventBatch)
+		for i := range events {
+			n := start + i
+			events[i] = &models.UserEvent{
+				UserID:    userID,
+				ArticleID: articles[n*len(articles)/trendingBenchmarkEvents].ID,
+				EventType: "view",
+				Timestamp: now.Add(-trendingBenchmarkSpan * time.Duration(n) / trendingBenchmarkEvents),
+				Latitude:  28.6,
+				Longitude: 77.2,
+			}
+		}
+		if err := eventRepo.CreateBatch(ctx, events); err != nil {
+			b.Fatalf("failed to store the benchmark events: %v", err)
+		}
+	}
+	if err := db.Exec("ANALYZE articles").Error; err != nil {
+		b.Fatalf("failed to analyze articles: %v", err)
+	}
+	if err := db.Exec("ANALYZE user_events").Error; err != nil {
+		b.Fatalf("failed to analyze user_events: %v", err)
+	}
+
+	b.Run("before", func(b *testing.B) {
+		candidates := 0
+		for i := 0; i < b.N; i++ {
+			// The candidate query before the trending window applied
+			var ids []string
+			if err := db.WithContext(ctx).Raw(`SELECT DISTINCT article_id FROM user_events ORDER BY article_id`).Scan(&ids).Error; err != nil {
+				b.Fatalf("failed to read candidate ids: %v", err)
+			}
+			found, err := articleRepo.FindByIDs(ctx, ids)
+			if err != nil {
+				b.Fatalf("FindByIDs failed: %v", err)
+			}
+			candidates += len(found)
+		}
+		b.ReportMetric(float64(candidates)/float64(b.N), "candidates/op")
+	})
+
+	b.Run("after", func(b *testing.B) {
+		candidates := 0
+		for i := 0; i < b.N; i++ {
+			ids, err := eventRepo.GetArticleIDsWithEventsSince(ctx, now.Add(-trendingBenchmarkWindow))
+			if err != nil {
+				b.Fatalf("GetArticleIDsWithEventsSince failed: %v", err)
+			}
+			found, err := articleRepo.FindByIDsPublishedSince(ctx, ids, now.Add(-trendingBenchmarkMaxAge))
+			if err != nil {
+				b.Fatalf("FindByIDsPublishedSince failed: %v", err)
+			}
+			candidates += len(found)
+		}
+		b.ReportMetric(float64(candidates)/float64(b.N), "candidates/op")
+	})
+}
@@ -1,7 +1,10 @@
 package repositories
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"news-inshorts/src/infra"
@@ -11,30 +14,94 @@ import (
 	"gorm.io/gorm"
 )
 
+// EventTotals holds all-time engagement totals for an article
+type EventTotals struct {
+	Views       int `json:"views"`
+	Clicks      int `json:"clicks"`
+	UniqueUsers int `json:"unique_users"`
+}
+
+// EventDayCount is the number of events of one type recorded for an article on one day
+type EventDayCount struct {
+	Day       time.Time `json:"day"`
+	EventType string    `json:"event_type"`
+	Count     int       `json:"count"`
+}
+
+// EventBucketCount is the number of events of one type recorded in one time bucket
+type EventBucketCount struct {
+	Bucket    time.Time `json:"bucket"`
+	EventType string    `json:"event_type"`
+	Count     int64     `json:"count"`
+}
+
 // UserEventRepository defines the interface for user event data access
 type UserEventRepository interface {
-	Create(event *models.UserEvent) error
-	FindByArticleID(articleID string, since time.Time) ([]models.UserEvent, error)
-	FindByLocation(lat, lon, radiusKm float64, since time.Time) ([]models.UserEvent, error)
-	GetArticlesFromUserEvents() ([]string, error)
+	Create(ctx context.Context, event *models.UserEvent) error
+	CreateBatch(ctx context.Context, events []*models.UserEvent) error
+	FindByArticleID(ctx context.Context, articleID string, since time.Time) ([]models.UserEvent, error)
+	FindByLocation(ctx context.Context, lat, lon, radiusKm float64, since time.Time) ([]models.UserEvent, error)
+	// GetArticleIDsWithEventsSince returns the distinct ids of the articles with events at or
+	// after since
+	GetArticleIDsWithEventsSince(ctx context.Context, since time.Time) ([]string, error)
+	CountByArticle(ctx context.Context, articleID string) (*EventTotals, error)
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int64, error)
+	CountByArticleGroupedByTypeAndDay(ctx context.Context, articleID string, since time.Time) ([]EventDayCount, error)
+	// DecayedEngagement returns, per article with events since the given time, the sum of
+	// its event weights, each halved for every halfLife elapsed between the event and now.
+	// It covers every tenant, since relevance rescoring runs over all articles.
+	DecayedEngagement(ctx context.Context, since, now time.Time, halfLife time.Duration) (map[string]float64, error)
+	// Bookmarks returns a page of the articles the user currently has bookmarked, most recently
+	// bookmarked first, and the size of the whole set
+	Bookmarks(ctx context.Context, userID string, limit, offset int) ([]Bookmark, int64, error)
+	// CountByTypeAndBucket returns event counts in [from, to) bucketed by bucket (a date_trunc
+	// field: hour or day) and event type. Buckets without events are omitted.
+	CountByTypeAndBucket(ctx context.Context, from, to time.Time, bucket string) ([]EventBucketCount, error)
+	// TopArticlesByEngagement returns the limit articles with the highest weighted engagement
+	// in [from, to)
+	TopArticlesByEngagement(ctx context.Context, from, to time.Time, limit int) ([]models.ArticleEngagement, error)
+	// TopSourcesByEngagement returns the limit sources whose articles had the highest weighted
+	// engagement in [from, to)
+	TopSourcesByEngagement(ctx context.Context, from, to time.Time, limit int) ([]models.SourceEngagement, error)
+}
+
+// Bookmark is an article in a user's current bookmark set with the time of its bookmark event
+type Bookmark struct {
+	ArticleID    string
+	BookmarkedAt time.Time
 }
 
 // userEventRepository implements UserEventRepository
 type userEventRepository struct {
-	db  *gorm.DB
-	log infra.Logger
+	db            *gorm.DB
+	defaultTenant string
+	log           infra.Logger
 }
 
-// NewUserEventRepository creates a new instance of UserEventRepository
-func NewUserEventRepository(db *gorm.DB) UserEventRepository {
+// NewUserEventRepository creates a new instance of UserEventRepository. Queries are scoped to
+// the tenant on their context, or to defaultTenant when it carries none.
+func NewUserEventRepository(db *gorm.DB, defaultTenant string, logger infra.Logger) UserEventRepository {
 	return &userEventRepository{
-		db:  db,
-		log: infra.GetLogger(),
+		db:            db,
+		defaultTenant: defaultTenant,
+		log:           logger,
 	}
 }
 
+// tenant returns the tenant the queries made with ctx are scoped to
+func (r *userEventRepository) tenant(ctx context.Context) string {
+	return infra.TenantFromContext(ctx, r.defaultTenant)
+}
+
+// tenantCondition returns the SQL condition keeping the rows of the tenant of ctx, where
+// column holds the row's tenant, along with the argument to bind to its placeholder
+func (r *userEventRepository) tenantCondition(ctx context.Context, column string) (string, string) {
+	return tenantCondition(column, r.tenant(ctx))
+}
+
 // Create stores a new user event in the database
-func (r *userEventRepository) Create(event *models.UserEvent) error {
+func (r *userEventRepository) Create(ctx context.Context, event *models.UserEvent) error {
 	// Generate UUID if not provided
 	if event.ID == "" {
 		event.ID = uuid.New().String()
@@ -51,9 +118,12 @@ func (r *userEventRepository) Create(event *models.UserEvent) error {
 			user_id,
 			article_id,
 			event_type,
+			value,
 			timestamp,
 			latitude,
-			longitude
+			longitude,
+			experiments,
+			tenant_id
 		) VALUES (
 			COALESCE(?::uuid, uuid_generate_v4()),
 			?,
@@ -61,25 +131,31 @@ func (r *userEventRepository) Create(event *models.UserEvent) error {
 			?,
 			?,
 			?,
+			?,
+			?,
+			?::jsonb,
 			?
 		)
 	`
 
-	if err := r.db.Exec(query,
+	if err := r.db.WithContext(ctx).Exec(query,
 		event.ID,
 		event.UserID,
 		event.ArticleID,
 		event.EventType,
+		event.Value,
 		event.Timestamp,
 		event.Latitude,
 		event.Longitude,
+		experimentsJSON(event.Experiments),
+		r.tenant(ctx),
 	).Error; err != nil {
 		r.log.Error("Failed to create user event", err, map[string]interface{}{
 			"user_id":    event.UserID,
 			"article_id": event.ArticleID,
 			"event_type": event.EventType,
 		})
-		return fmt.Errorf("failed to create user event: %w", err)
+		return fmt.Errorf("failed to create user event: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Created user event", map[string]interface{}{
@@ -92,30 +168,102 @@ func (r *userEventRepository) Create(event *models.UserEvent) error {
 	return nil
 }
 
-// FindByArticleID retrieves user events for a specific article with time filtering
-func (r *userEventRepository) FindByArticleID(articleID string, since time.Time) ([]models.UserEvent, error) {
+// CreateBatch stores multiple user events with a single multi-row INSERT inside a transaction.
+// Either all events are stored or none are.
+func (r *userEventRepository) CreateBatch(ctx context.Context, events []*models.UserEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	placeholders := make([]string, 0, len(events))
+	tenant := r.tenant(ctx)
+	args := make([]interface{}, 0, len(events)*10)
+
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+		if event.Timestamp.IsZero() {
+			event.Timestamp = now
+		}
+
+		placeholders = append(placeholders, "(COALESCE(?::uuid, uuid_generate_v4()), ?, ?::uuid, ?, ?, ?, ?, ?, ?::jsonb, ?)")
+		args = append(args,
+			event.ID,
+			event.UserID,
+			event.ArticleID,
+			event.EventType,
+			event.Value,
+			event.Timestamp,
+			event.Latitude,
+			event.Longitude,
+			experimentsJSON(event.Experiments),
+			tenant,
+		)
+	}
+
 	query := `
+		INSERT INTO user_events (
+			id,
+			user_id,
+			article_id,
+			event_type,
+			value,
+			timestamp,
+			latitude,
+			longitude,
+			experiments,
+			tenant_id
+		) VALUES ` + strings.Join(placeholders, ", ")
+
+	// Concurrent batches touching the same articles can deadlock; the loser is run again
+	err := withTxRetry(ctx, r.log, "user_events_batch", func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return tx.Exec(query, args...).Error
+		})
+	})
+	if err != nil {
+		r.log.Error("Failed to create user events batch", err, map[string]interface{}{
+			"count": len(events),
+		})
+		return fmt.Errorf("failed to create user events batch: %w", wrapDBError(err))
+	}
+
+	r.log.Info("Created user events batch", map[string]interface{}{
+		"count": len(events),
+	})
+
+	return nil
+}
+
+// FindByArticleID retrieves user events for a specific article with time filtering
+func (r *userEventRepository) FindByArticleID(ctx context.Context, articleID string, since time.Time) ([]models.UserEvent, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	query := fmt.Sprintf(`
 		SELECT
 			id,
 			user_id,
 			article_id,
 			event_type,
+			value,
 			timestamp,
 			latitude,
 			longitude
 		FROM user_events
 		WHERE article_id = ?::uuid
 			AND timestamp >= ?
+			AND %s
 		ORDER BY timestamp DESC
-	`
+	`, tenant)
 
 	var events []models.UserEvent
-	if err := r.db.Raw(query, articleID, since).Scan(&events).Error; err != nil {
+	if err := r.db.WithContext(ctx).Raw(query, articleID, since, tenantArg).Scan(&events).Error; err != nil {
 		r.log.Error("Failed to query user events by article ID", err, map[string]interface{}{
 			"article_id": articleID,
 			"since":      since,
 		})
-		return nil, fmt.Errorf("failed to query user events by article ID: %w", err)
+		return nil, fmt.Errorf("failed to query user events by article ID: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Retrieved user events by article ID", map[string]interface{}{
@@ -128,13 +276,15 @@ func (r *userEventRepository) FindByArticleID(articleID string, since time.Time)
 }
 
 // FindByLocation retrieves user events within a specified radius using PostGIS spatial queries
-func (r *userEventRepository) FindByLocation(lat, lon, radiusKm float64, since time.Time) ([]models.UserEvent, error) {
-	query := `
+func (r *userEventRepository) FindByLocation(ctx context.Context, lat, lon, radiusKm float64, since time.Time) ([]models.UserEvent, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	query := fmt.Sprintf(`
 		SELECT
 			id,
 			user_id,
 			article_id,
 			event_type,
+			value,
 			timestamp,
 			latitude,
 			longitude,
@@ -149,18 +299,19 @@ func (r *userEventRepository) FindByLocation(lat, lon, radiusKm float64, since t
 			? * 1000
 		)
 		AND timestamp >= ?
+		AND %s
 		ORDER BY timestamp DESC
-	`
+	`, tenant)
 
 	var events []models.UserEvent
-	if err := r.db.Raw(query, lon, lat, lon, lat, radiusKm, since).Scan(&events).Error; err != nil {
+	if err := r.db.WithContext(ctx).Raw(query, lon, lat, lon, lat, radiusKm, since, tenantArg).Scan(&events).Error; err != nil {
 		r.log.Error("Failed to query user events by location", err, map[string]interface{}{
 			"latitude":  lat,
 			"longitude": lon,
 			"radius_km": radiusKm,
 			"since":     since,
 		})
-		return nil, fmt.Errorf("failed to query user events by location: %w", err)
+		return nil, fmt.Errorf("failed to query user events by location: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Retrieved user events by location", map[string]interface{}{
@@ -174,23 +325,340 @@ func (r *userEventRepository) FindByLocation(lat, lon, radiusKm float64, since t
 	return events, nil
 }
 
-// GetArticlesFromUserEvents retrieves all distinct article IDs from user_events
-func (r *userEventRepository) GetArticlesFromUserEvents() ([]string, error) {
-	query := `
+// GetArticleIDsWithEventsSince retrieves the distinct article IDs of recent user events,
+// through the timestamp index
+func (r *userEventRepository) GetArticleIDsWithEventsSince(ctx context.Context, since time.Time) ([]string, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	query := fmt.Sprintf(`
 		SELECT DISTINCT article_id
 		FROM user_events
+		WHERE timestamp >= ? AND %s
 		ORDER BY article_id
-	`
+	`, tenant)
 
 	var articleIDs []string
-	if err := r.db.Raw(query).Scan(&articleIDs).Error; err != nil {
-		r.log.Error("Failed to get distinct article IDs from user events", err, nil)
-		return nil, fmt.Errorf("failed to get distinct article IDs: %w", err)
+	if err := r.db.WithContext(ctx).Raw(query, since, tenantArg).Scan(&articleIDs).Error; err != nil {
+		r.log.Error("Failed to get distinct article IDs from user events", err, map[string]interface{}{
+			"since": since,
+		})
+		return nil, fmt.Errorf("failed to get distinct article IDs: %w", wrapDBError(err))
 	}
 
 	r.log.Info("Retrieved distinct article IDs from user events", map[string]interface{}{
+		"since": since,
 		"count": len(articleIDs),
 	})
 
 	return articleIDs, nil
 }
+
+// CountByArticle returns all-time view, click and unique user counts for an article
+func (r *userEventRepository) CountByArticle(ctx context.Context, articleID string) (*EventTotals, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	query := fmt.Sprintf(`
+		SELECT
+			COUNT(*) FILTER (WHERE event_type = 'view') AS views,
+			COUNT(*) FILTER (WHERE event_type = 'click') AS clicks,
+			COUNT(DISTINCT user_id) AS unique_users
+		FROM user_events
+		WHERE article_id = ?::uuid AND %s
+	`, tenant)
+
+	var totals EventTotals
+	if err := r.db.WithContext(ctx).Raw(query, articleID, tenantArg).Scan(&totals).Error; err != nil {
+		r.log.Error("Failed to count user events by article ID", err, map[string]interface{}{
+			"article_id": articleID,
+		})
+		return nil, fmt.Errorf("failed to count user events by article ID: %w", wrapDBError(err))
+	}
+
+	return &totals, nil
+}
+
+// CountByArticleGroupedByTypeAndDay returns event counts for an article bucketed by day and
+// event type, for events recorded since the given time. Days are UTC days, whatever the session
+// time zone. Days without events are omitted.
+func (r *userEventRepository) CountByArticleGroupedByTypeAndDay(ctx context.Context, articleID string, since time.Time) ([]EventDayCount, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	query := fmt.Sprintf(`
+		SELECT
+			date_trunc('day', timestamp AT TIME ZONE 'UTC', 'UTC') AS day,
+			event_type,
+			COUNT(*) AS count
+		FROM user_events
+		WHERE article_id = ?::uuid
+			AND timestamp >= ?
+			AND %s
+		GROUP BY day, event_type
+		ORDER BY day ASC
+	`, tenant)
+
+	var counts []EventDayCount
+	if err := r.db.WithContext(ctx).Raw(query, articleID, since, tenantArg).Scan(&counts).Error; err != nil {
+		r.log.Error("Failed to count user events by day", err, map[string]interface{}{
+			"article_id": articleID,
+			"since":      since,
+		})
+		return nil, fmt.Errorf("failed to count user events by day: %w", wrapDBError(err))
+	}
+
+	return counts, nil
+}
+
+// DecayedEngagement weights events by type (see models.EventTypeWeights) with exponential
+// time decay, so a view from an hour ago counts more than a view from last week
+func (r *userEventRepository) DecayedEngagement(ctx context.Context, since, now time.Time, halfLife time.Duration) (map[string]float64, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			article_id::text AS article_id,
+			SUM(%s * POWER(0.5, EXTRACT(EPOCH FROM (?::timestamp - timestamp)) / ?)) AS engagement
+		FROM user_events
+		WHERE timestamp >= ?
+		GROUP BY article_id
+	`, eventWeightCase())
+
+	var rows []struct {
+		ArticleID  string
+		Engagement float64
+	}
+	if err := r.db.WithContext(ctx).Raw(query, now, halfLife.Seconds(), since).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to compute article engagement", err, map[string]interface{}{
+			"since": since,
+		})
+		return nil, fmt.Errorf("failed to compute article engagement: %w", wrapDBError(err))
+	}
+
+	engagement := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		engagement[row.ArticleID] = row.Engagement
+	}
+	return engagement, nil
+}
+
+// Bookmarks computes the user's bookmark set from their latest bookmark or unbookmark event per
+// article: articles whose latest such event is a bookmark are bookmarked. When both events share
+// a timestamp the unbookmark wins. Deleted articles are left out of the page and the total.
+func (r *userEventRepository) Bookmarks(ctx context.Context, userID string, limit, offset int) ([]Bookmark, int64, error) {
+	eventTenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	articleTenant, _ := r.tenantCondition(ctx, "a.tenant_id")
+	query := fmt.Sprintf(`
+		WITH latest AS (
+			SELECT DISTINCT ON (article_id) article_id, event_type, timestamp
+			FROM user_events
+			WHERE user_id = ? AND event_type IN (?, ?) AND %[1]s
+			ORDER BY article_id, timestamp DESC, event_type = ? DESC
+		),
+		current AS (
+			SELECT l.article_id, l.timestamp
+			FROM latest l
+			JOIN articles a ON a.id = l.article_id AND a.deleted_at IS NULL AND %[2]s
+			WHERE l.event_type = ?
+		)
+		SELECT page.article_id, page.bookmarked_at, totals.total
+		FROM (SELECT COUNT(*) AS total FROM current) totals
+		LEFT JOIN LATERAL (
+			SELECT article_id::text AS article_id, timestamp AS bookmarked_at
+			FROM current
+			ORDER BY timestamp DESC, article_id ASC
+			LIMIT ? OFFSET ?
+		) page ON true
+	`, eventTenant, articleTenant)
+
+	var rows []struct {
+		ArticleID    *string
+		BookmarkedAt *time.Time
+		Total        int64
+	}
+	if err := r.db.WithContext(ctx).Raw(query,
+		userID,
+		models.EventTypeBookmark, models.EventTypeUnbookmark,
+		tenantArg,
+		models.EventTypeUnbookmark,
+		tenantArg,
+		models.EventTypeBookmark,
+		limit, offset,
+	).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query user bookmarks", err, nil)
+		return nil, 0, fmt.Errorf("failed to query user bookmarks: %w", wrapDBError(err))
+	}
+
+	bookmarks := make([]Bookmark, 0, len(rows))
+	var total int64
+	for _, row := range rows {
+		total = row.Total
+		// A page past the end is a single row carrying only the total
+		if row.ArticleID == nil || row.BookmarkedAt == nil {
+			continue
+		}
+		bookmarks = append(bookmarks, Bookmark{ArticleID: *row.ArticleID, BookmarkedAt: *row.BookmarkedAt})
+	}
+	return bookmarks, total, nil
+}
+
+// CountByTypeAndBucket counts events per date_trunc bucket and event type
+func (r *userEventRepository) CountByTypeAndBucket(ctx context.Context, from, to time.Time, bucket string) ([]EventBucketCount, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	query := fmt.Sprintf(`
+		SELECT
+			date_trunc(?, timestamp) AS bucket,
+			event_type,
+			COUNT(*) AS count
+		FROM user_events
+		WHERE timestamp >= ? AND timestamp < ? AND %s
+		GROUP BY bucket, event_type
+		ORDER BY bucket ASC
+	`, tenant)
+
+	var counts []EventBucketCount
+	if err := r.db.WithContext(ctx).Raw(query, bucket, from, to, tenantArg).Scan(&counts).Error; err != nil {
+		r.log.Error("Failed to count user events by bucket", err, map[string]interface{}{
+			"from":   from,
+			"to":     to,
+			"bucket": bucket,
+		})
+		return nil, fmt.Errorf("failed to count user events by bucket: %w", wrapDBError(err))
+	}
+
+	return counts, nil
+}
+
+// TopArticlesByEngagement ranks articles by the sum of their event weights, ties broken by
+// event count. Articles deleted since keep their events but report their stored title.
+func (r *userEventRepository) TopArticlesByEngagement(ctx context.Context, from, to time.Time, limit int) ([]models.ArticleEngagement, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	query := fmt.Sprintf(`
+		SELECT
+			e.article_id::text AS article_id,
+			COALESCE(a.title, '') AS title,
+			e.events,
+			e.engagement
+		FROM (
+			SELECT article_id, COUNT(*) AS events, SUM(%s) AS engagement
+			FROM user_events
+			WHERE timestamp >= ? AND timestamp < ? AND %s
+			GROUP BY article_id
+			ORDER BY engagement DESC, events DESC, article_id ASC
+			LIMIT ?
+		) e
+		LEFT JOIN articles a ON a.id = e.article_id
+		ORDER BY e.engagement DESC, e.events DESC, e.article_id ASC
+	`, eventWeightCase(), tenant)
+
+	var articles []models.ArticleEngagement
+	if err := r.db.WithContext(ctx).Raw(query, from, to, tenantArg, limit).Scan(&articles).Error; err != nil {
+		r.log.Error("Failed to rank articles by engagement", err, map[string]interface{}{
+			"from": from,
+			"to":   to,
+		})
+		return nil, fmt.Errorf("failed to rank articles by engagement: %w", wrapDBError(err))
+	}
+
+	return articles, nil
+}
+
+// TopSourcesByEngagement ranks sources by the summed event weights of their articles, ties
+// broken by event count
+func (r *userEventRepository) TopSourcesByEngagement(ctx context.Context, from, to time.Time, limit int) ([]models.SourceEngagement, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "user_events.tenant_id")
+	query := fmt.Sprintf(`
+		SELECT
+			a.source_name AS source,
+			COUNT(*) AS events,
+			SUM(%s) AS engagement
+		FROM user_events
+		JOIN articles a ON a.id = user_events.article_id
+		WHERE timestamp >= ? AND timestamp < ? AND %s
+		GROUP BY a.source_name
+		ORDER BY engagement DESC, events DESC, source ASC
+		LIMIT ?
+	`, eventWeightCase(), tenant)
+
+	var sources []models.SourceEngagement
+	if err := r.db.WithContext(ctx).Raw(query, from, to, tenantArg, limit).Scan(&sources).Error; err != nil {
+		r.log.Error("Failed to rank sources by engagement", err, map[string]interface{}{
+			"from": from,
+			"to":   to,
+		})
+		return nil, fmt.Errorf("failed to rank sources by engagement: %w", wrapDBError(err))
+	}
+
+	return sources, nil
+}
+
+// eventWeightCase renders models.EventTypeWeights as a SQL CASE over event_type
+func eventWeightCase() string {
+	var b strings.Builder
+	b.WriteString("CASE event_type")
+	for _, eventType := range models.EventTypes {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %g", eventType, models.EventTypeWeights[eventType])
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
+
+// DeleteByUserID permanently removes every event recorded for a user of the tenant of ctx and
+// returns how many rows were deleted. User ids are only unique within a tenant.
+func (r *userEventRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	tenant, tenantArg := r.tenantCondition(ctx, "tenant_id")
+	result := r.db.WithContext(ctx).Exec(`DELETE FROM user_events WHERE user_id = ? AND `+tenant, userID, tenantArg)
+	if result.Error != nil {
+		r.log.Error("Failed to delete user events by user ID", result.Error, nil)
+		return 0, fmt.Errorf("failed to delete user events by user ID: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteOlderThan deletes at most batchSize events of every tenant recorded before cutoff and
+// returns how many rows were removed. Callers repeat until fewer than batchSize rows are
+// deleted; bounding each statement keeps row locks short on a large table. The latest bookmark
+// or unbookmark event of each user and article is kept at any age, since it holds the user's
+// current bookmark state.
+func (r *userEventRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	query := `
+		DELETE FROM user_events
+		WHERE id IN (
+			SELECT e.id
+			FROM user_events e
+			WHERE e.timestamp < ?
+				AND (e.event_type NOT IN (?, ?) OR EXISTS (
+					SELECT 1
+					FROM user_events later
+					WHERE later.user_id = e.user_id
+						AND later.article_id = e.article_id
+						AND later.event_type IN (?, ?)
+						AND later.timestamp > e.timestamp
+				))
+			LIMIT ?
+		)
+	`
+
+	result := r.db.WithContext(ctx).Exec(query,
+		cutoff,
+		models.EventTypeBookmark, models.EventTypeUnbookmark,
+		models.EventTypeBookmark, models.EventTypeUnbookmark,
+		batchSize,
+	)
+	if result.Error != nil {
+		r.log.Error("Failed to delete old user events", result.Error, map[string]interface{}{
+			"cutoff":     cutoff,
+			"batch_size": batchSize,
+		})
+		return 0, fmt.Errorf("failed to delete old user events: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// experimentsJSON encodes an event's experiment variants for the experiments JSONB column,
+// or NULL when the event was recorded outside any experiment
+func experimentsJSON(experiments map[string]string) interface{} {
+	if len(experiments) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(experiments)
+	if err != nil {
+		return nil
+	}
+	return string(encoded)
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"news-inshorts/src/infra"
+
+	"gorm.io/gorm"
+)
+
+// UserPreferenceRepository stores users' category preferences, separately for each tenant
+type UserPreferenceRepository interface {
+	// Get returns the user's category weights keyed by lowercased category; a user who set
+	// none gets an empty map
+	Get(ctx context.Context, userID string) (map[string]float64, error)
+	// Replace swaps the user's category weights for categories in a single transaction
+	Replace(ctx context.Context, userID string, categories map[string]float64) error
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+}
+
+// userPreferenceRepository implements UserPreferenceRepository
+type userPreferenceRepository struct {
+	db            *gorm.DB
+	defaultTenant string
+	log           infra.Logger
+}
+
+// NewUserPreferenceRepository creates a new instance of UserPreferenceRepository. Queries are
+// scoped to the tenant of their context, or defaultTenant when it carries none.
+func NewUserPreferenceRepository(db *gorm.DB, defaultTenant string, logger infra.Logger) UserPreferenceRepository {
+	return &userPreferenceRepository{
+		db:            db,
+		defaultTenant: defaultTenant,
+		log:           logger,
+	}
+}
+
+// tenant returns the tenant the queries made with ctx are scoped to
+func (r *userPreferenceRepository) tenant(ctx context.Context) string {
+	return infra.TenantFromContext(ctx, r.defaultTenant)
+}
+
+// Get returns the user's category weights
+func (r *userPreferenceRepository) Get(ctx context.Context, userID string) (map[string]float64, error) {
+	var rows []struct {
+		Category string
+		Weight   float64
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT category, weight
+		FROM user_preferences
+		WHERE user_id = ? AND tenant_id = ?
+	`, userID, r.tenant(ctx)).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query user preferences", err, nil)
+		return nil, fmt.Errorf("failed to query user preferences: %w", wrapDBError(err))
+	}
+
+	categories := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		categories[row.Category] = row.Weight
+	}
+	return categories, nil
+}
+
+// Replace deletes the user's category weights and inserts categories
+func (r *userPreferenceRepository) Replace(ctx context.Context, userID string, categories map[string]float64) error {
+	tenant := r.tenant(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM user_preferences WHERE user_id = ? AND tenant_id = ?`, userID, tenant).Error; err != nil {
+			return err
+		}
+		for category, weight := range categories {
+			if err := tx.Exec(`
+				INSERT INTO user_preferences (tenant_id, user_id, category, weight)
+				VALUES (?, ?, ?, ?)
+			`, tenant, userID, category, weight).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.log.Error("Failed to save user preferences", err, nil)
+		return fmt.Errorf("failed to save user preferences: %w", wrapDBError(err))
+	}
+	return nil
+}
+
+// DeleteByUserID removes every category weight of the user
+func (r *userPreferenceRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`DELETE FROM user_preferences WHERE user_id = ? AND tenant_id = ?`, userID, r.tenant(ctx))
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete user preferences: %w", wrapDBError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"gorm.io/gorm"
+)
+
+// VectorIndexName is the name of the approximate nearest-neighbor index on description_vector
+const VectorIndexName = "idx_articles_description_vector"
+
+// vectorIndexBuildName is the temporary name a rebuilt index gets until it replaces the old one
+const vectorIndexBuildName = VectorIndexName + "_new"
+
+// VectorIndexRepository inspects and rebuilds the vector index on article embeddings
+type VectorIndexRepository interface {
+	Status(ctx context.Context) (*models.VectorIndexStatus, error)
+	Rebuild(ctx context.Context) error
+}
+
+// vectorIndexRepository implements VectorIndexRepository
+type vectorIndexRepository struct {
+	db  *gorm.DB
+	cfg *infra.VectorConfig
+	log infra.Logger
+}
+
+// NewVectorIndexRepository creates a new instance of VectorIndexRepository
+func NewVectorIndexRepository(db *gorm.DB, cfg *infra.VectorConfig, logger infra.Logger) VectorIndexRepository {
+	return &vectorIndexRepository{
+		db:  db,
+		cfg: cfg,
+		log: logger,
+	}
+}
+
+// Status reports whether the index exists, how it was built and whether that matches the
+// configured settings
+func (r *vectorIndexRepository) Status(ctx context.Context) (*models.VectorIndexStatus, error) {
+	query := `
+		SELECT
+			am.amname AS method,
+			i.indisvalid AS valid,
+			pg_relation_size(c.oid) AS size_bytes,
+			pg_get_indexdef(c.oid) AS definition,
+			COALESCE(array_to_string(c.reloptions, ','), '') AS options
+		FROM pg_class c
+		JOIN pg_index i ON i.indexrelid = c.oid
+		JOIN pg_am am ON am.oid = c.relam
+		WHERE c.relname = ? AND c.relkind = 'i'
+	`
+
+	var rows []struct {
+		Method     string
+		Valid      bool
+		SizeBytes  int64
+		Definition string
+		Options    string
+	}
+	if err := r.db.WithContext(ctx).Raw(query, VectorIndexName).Scan(&rows).Error; err != nil {
+		r.log.Error("Failed to query vector index status", err, nil)
+		return nil, fmt.Errorf("failed to query vector index status: %w", wrapDBError(err))
+	}
+
+	configured := vectorIndexSettings(r.cfg)
+	status := &models.VectorIndexStatus{
+		Name:       VectorIndexName,
+		Configured: configured,
+	}
+	if len(rows) == 0 {
+		return status, nil
+	}
+
+	row := rows[0]
+	current := models.VectorIndexSettings{
+		Method:  row.Method,
+		Options: parseIndexOptions(row.Options),
+	}
+	status.Exists = true
+	status.Valid = row.Valid
+	status.Current = &current
+	status.SizeBytes = row.SizeBytes
+	status.Definition = row.Definition
+	status.MatchesConfig = row.Valid && current.Method == configured.Method && sameIndexOptions(current.Options, configured.Options)
+
+	return status, nil
+}
+
+// Rebuild builds a fresh index with the configured settings and swaps it in for the old one.
+// The index is built concurrently, so writes continue during the build and searches keep using
+// the old index until the swap.
+func (r *vectorIndexRepository) Rebuild(ctx context.Context) error {
+	db := r.db.WithContext(ctx)
+
+	// A failed earlier build leaves an invalid index behind under the temporary name
+	if err := db.Exec("DROP INDEX CONCURRENTLY IF EXISTS " + vectorIndexBuildName).Error; err != nil {
+		return fmt.Errorf("failed to drop leftover vector index: %w", wrapDBError(err))
+	}
+
+	create := fmt.Sprintf("CREATE INDEX CONCURRENTLY %s ON articles USING %s (description_vector vector_cosine_ops) WITH (%s)",
+		vectorIndexBuildName, r.cfg.IndexType, vectorIndexWithClause(r.cfg))
+
+	r.log.Info("Building vector index", map[string]interface{}{
+		"statement": create,
+	})
+
+	if err := db.Exec(create).Error; err != nil {
+		r.log.Error("Failed to build vector index", err, nil)
+		return fmt.Errorf("failed to build vector index: %w", wrapDBError(err))
+	}
+
+	if err := db.Exec("DROP INDEX CONCURRENTLY IF EXISTS " + VectorIndexName).Error; err != nil {
+		return fmt.Errorf("failed to drop old vector index: %w", wrapDBError(err))
+	}
+
+	if err := db.Exec(fmt.Sprintf("ALTER INDEX %s RENAME TO %s", vectorIndexBuildName, VectorIndexName)).Error; err != nil {
+		return fmt.Errorf("failed to rename vector index: %w", wrapDBError(err))
+	}
+
+	return nil
+}
+
+// vectorIndexSettings returns the index cfg describes
+func vectorIndexSettings(cfg *infra.VectorConfig) models.VectorIndexSettings {
+	if cfg.IndexType == infra.VectorIndexIVFFlat {
+		return models.VectorIndexSettings{
+			Method:        infra.VectorIndexIVFFlat,
+			Options:       map[string]string{"lists": strconv.Itoa(cfg.IVFFlatLists)},
+			SearchOptions: map[string]string{"ivfflat.probes": strconv.Itoa(cfg.Probes)},
+		}
+	}
+
+	return models.VectorIndexSettings{
+		Method: infra.VectorIndexHNSW,
+		Options: map[string]string{
+			"m":               strconv.Itoa(cfg.HNSWM),
+			"ef_construction": strconv.Itoa(cfg.HNSWEfConstruction),
+		},
+		SearchOptions: map[string]string{"hnsw.ef_search": strconv.Itoa(cfg.EfSearch)},
+	}
+}
+
+// vectorIndexWithClause renders the configured build parameters for CREATE INDEX ... WITH
+func vectorIndexWithClause(cfg *infra.VectorConfig) string {
+	if cfg.IndexType == infra.VectorIndexIVFFlat {
+		return fmt.Sprintf("lists = %d", cfg.IVFFlatLists)
+	}
+	return fmt.Sprintf("m = %d, ef_construction = %d", cfg.HNSWM, cfg.HNSWEfConstruction)
+}
+
+// vectorSearchSetting returns the SET LOCAL statement applying the configured recall/latency
+// trade-off to a nearest-neighbor query returning up to limit rows. HNSW never returns more
+// than ef_search rows, so ef_search is raised to limit when needed.
+func vectorSearchSetting(cfg *infra.VectorConfig, limit int) string {
+	if cfg.IndexType == infra.VectorIndexIVFFlat {
+		return fmt.Sprintf("SET LOCAL ivfflat.probes = %d", cfg.Probes)
+	}
+	return fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", max(cfg.EfSearch, limit))
+}
+
+// parseIndexOptions parses pg_class.reloptions joined with commas, e.g. "m=16,ef_construction=64"
+func parseIndexOptions(value string) map[string]string {
+	options := make(map[string]string)
+	for _, option := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(option, "=")
+		if ok {
+			options[key] = val
+		}
+	}
+	return options
+}
+
+// sameIndexOptions reports whether two option sets are equal
+func sameIndexOptions(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, val := range a {
+		if b[key] != val {
+			return false
+		}
+	}
+	return true
+}
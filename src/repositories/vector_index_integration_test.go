@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"news-inshorts/src/infra"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// vectorBenchmarkSize is the number of embedded articles BenchmarkNearestByVector searches
+	vectorBenchmarkSize = 50000
+	// vectorBenchmarkDimensions matches the description_vector column in init.sql
+	vectorBenchmarkDimensions = 1536
+)
+
+// randomUnitVector returns a vector of vectorBenchmarkDimensions components with unit length
+func randomUnitVector(rng *rand.Rand) []float64 {
+	vector := make([]float64, vectorBenchmarkDimensions)
+	var norm float64
+	for i := range vector {
+		vector[i] = rng.NormFloat64()
+		norm += vector[i] * vector[i]
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+	return vector
+}
+
+// BenchmarkNearestByVector compares NearestByVector over vectorBenchmarkSize embedded articles
+// with the vector index of init.sql against a sequential scan, on the database named by
+// TEST_DATABASE_URL. The scan runs on a connection with index scans turned off, so the shared
+// index is never dropped. The articles are stored under a tenant of their own and deleted
+// when the benchmark ends.
+func BenchmarkNearestByVector(b *testing.B) {
+	db := openTestDatabase(b)
+	ctx := infra.WithTenant(context.Background(), "bench-"+uuid.New().String()[:8])
+	vectorCfg := &infra.VectorConfig{IndexType: "hnsw", EfSearch: 40, Probes: 1, SearchLimit: 10, HNSWM: 16, HNSWEfConstruction: 64}
+	log := infra.NewRecordingLogger()
+	repo := NewArticleRepository(db, vectorCfg, NewSourceAliasRepository(db, log), testDefaultTenant, log)
+
+	status, err := NewVectorIndexRepository(db, vectorCfg, log).Status(ctx)
+	if err != nil {
+		b.Fatalf("failed to read the vector index status: %v", err)
+	}
+	if !status.Exists || !status.Valid {
+		b.Fatalf("vector index %s is missing or invalid; initialize the database with init.sql", VectorIndexName)
+	}
+
+	tenant := infra.TenantFromContext(ctx, testDefaultTenant)
+	b.Cleanup(func() { _ = db.Exec("DELETE FROM articles WHERE tenant_id = ?", tenant).Error })
+
+	// Articles are generated and stored a batch at a time to keep memory bounded
+	rng := rand.New(rand.NewSource(1))
+	const seedBatch = 1000
+	for start := 0; start < vectorBenchmarkSize; start += seedBatch {
+		articles := syntheticArticles(seedBatch, tenant+"/"+uuid.New().String()[:8])
+		for i := range articles {
+			articles[i].DescriptionVector = randomUnitVector(rng)
+		}
+		stats, err := repo.BulkInsert(ctx, articles, 0)
+		if err != nil {
+			b.Fatalf("failed to store the benchmark articles: %v", err)
+		}
+		if stats.SuccessCount != len(articles) {
+			b.Fatalf("stored %d of %d benchmark articles", stats.SuccessCount, len(articles))
+		}
+	}
+	if err := db.Exec("ANALYZE articles").Error; err != nil {
+		b.Fatalf("failed to analyze articles: %v", err)
+	}
+
+	queries := make([][]float64, 100)
+	for i := range queries {
+		queries[i] = randomUnitVector(rng)
+	}
+
+	search := func(b *testing.B, repo ArticleRepository) {
+		found := 0
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			neighbors, err := repo.NearestByVector(ctx, queries[i%len(queries)], vectorCfg.SearchLimit, 0)
+			if err != nil {
+				b.Fatalf("NearestByVector failed: %v", err)
+			}
+			found += len(neighbors)
+		}
+		b.ReportMetric(float64(found)/float64(b.N), "neighbors/op")
+	}
+
+	b.Run("index", func(b *testing.B) {
+		search(b, repo)
+	})
+
+	b.Run("no_index", func(b *testing.B) {
+		// A single connection keeps the session setting on every query
+		scanDB := openTestDatabase(b)
+		sqlDB, err := scanDB.DB()
+		if err != nil {
+			b.Fatalf("failed to get the database handle: %v", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+		b.Cleanup(func() { _ = sqlDB.Close() })
+		if err := scanDB.Exec("SET enable_indexscan = off").Error; err != nil {
+			b.Fatalf("failed to turn off index scans: %v", err)
+		}
+
+		search(b, NewArticleRepository(scanDB, vectorCfg, NewSourceAliasRepository(scanDB, log), testDefaultTenant, log))
+	})
+}
@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"news-inshorts/src/controllers"
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/services"
+	"news-inshorts/src/types"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// filterArticleService answers every filter with its articles
+type filterArticleService struct {
+	services.ArticleService
+	articles []models.Article
+}
+
+func (s *filterArticleService) FilterArticles(ctx context.Context, req types.FilterArticlesRequest) ([]models.Article, error) {
+	return s.articles, nil
+}
+
+// filterArticles returns n articles with enough text to make a large response
+func filterArticles(n int) []models.Article {
+	articles := make([]models.Article, n)
+	for i := range articles {
+		articles[i] = models.Article{
+			ID:          fmt.Sprintf("article-%d", i),
+			Title:       "Monsoon reaches the coast",
+			Description: "The monsoon reached the coast a week early, bringing heavy rain to the region.",
+			URL:         fmt.Sprintf("https://example.com/monsoon-%d", i),
+			SourceName:  "Example",
+			Category:    []string{"world"},
+		}
+	}
+	return articles
+}
+
+func TestFilterResponseCompression(t *testing.T) {
+	tests := []struct {
+		name           string
+		level          int
+		acceptEncoding string
+		articles       int
+		wantEncoding   string
+	}{
+		{name: "default level", level: 0, acceptEncoding: "gzip", articles: 200, wantEncoding: "gzip"},
+		{name: "best speed", level: 1, acceptEncoding: "gzip", articles: 200, wantEncoding: "gzip"},
+		{name: "best compression", level: 2, acceptEncoding: "gzip", articles: 200, wantEncoding: "gzip"},
+		{name: "disabled", level: -1, acceptEncoding: "gzip", articles: 200},
+		{name: "client without compression", level: 0, articles: 200},
+		{name: "small response", level: 0, acceptEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := infra.NewRecordingLogger()
+			ctrl := controllers.NewArticleController(&filterArticleService{articles: filterArticles(tt.articles)}, nil, nil, nil, nil, nil, false, logger)
+			app := fiber.New()
+			app.Use(compression(tt.level))
+			app.Get("/api/v1/news/filter", ctrl.FilterArticles)
+
+			req := httptest.NewRequest(fiber.MethodGet, "/api/v1/news/filter?category=world", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set(fiber.HeaderAcceptEncoding, tt.acceptEncoding)
+			}
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+			}
+			if got := resp.Header.Get(fiber.HeaderContentEncoding); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+
+			body := io.Reader(resp.Body)
+			if tt.wantEncoding == "gzip" {
+				gz, err := gzip.NewReader(resp.Body)
+				if err != nil {
+					t.Fatalf("response is not gzip: %v", err)
+				}
+				defer gz.Close()
+				body = gz
+			}
+			var decoded types.FilterArticlesResponse
+			if err := json.NewDecoder(body).Decode(&decoded); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(decoded.Articles) != tt.articles {
+				t.Errorf("got %d articles, want %d", len(decoded.Articles), tt.articles)
+			}
+		})
+	}
+}
@@ -1,19 +1,25 @@
 package routes
 
 import (
+	"context"
+
 	"news-inshorts/src/controllers"
 	"news-inshorts/src/infra"
+	"news-inshorts/src/middleware"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
-// SetupRoutes configures all routes and middleware for the application
-func SetupRoutes(app *fiber.App, infraInstance *infra.Infrastructure, cfg *infra.Config) {
-	appLogger := infra.GetLogger()
+// SetupRoutes configures all routes and middleware for the application and returns the
+// controllers, whose services other transports share
+func SetupRoutes(app *fiber.App, infraInstance *infra.Infrastructure, cfg *infra.Config) *controllers.Controllers {
+	appLogger := infraInstance.Logger
 
-	ctrls := controllers.NewControllers(cfg, infraInstance.DB, infraInstance.Redis)
+	ctrls := controllers.NewControllers(cfg, infraInstance)
 	appLogger.Info("Controllers initialized", nil)
 
 	// Register recover middleware (panic recovery)
@@ -21,17 +27,31 @@ func SetupRoutes(app *fiber.App, infraInstance *infra.Infrastructure, cfg *infra
 
 	// Register CORS middleware
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
+		AllowMethods:     cfg.CORS.AllowedMethods,
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,Idempotency-Key,X-API-Key,X-Tenant-ID,If-None-Match",
+		ExposeHeaders:    "ETag,Content-Disposition,X-Total-Count,X-Export-Rows,X-Export-Truncated,X-Experiment-Variant",
+		AllowCredentials: cfg.CORS.AllowCredentials,
+	}))
+
+	// Reject oversized bodies; loads get their own, larger limit
+	app.Use(middleware.BodyLimit(cfg.Server.BodyLimit, map[string]int{
+		"/api/v1/news/load": cfg.Server.LoadBodyLimit,
 	}))
 
+	// Register response compression middleware
+	app.Use(compression(cfg.Server.CompressLevel))
+
 	// Register logging middleware
 	app.Use(func(c *fiber.Ctx) error {
 		start := c.Context().Time()
 
-		// Process request
+		// Process request. Errors are rendered here rather than after the chain unwinds so
+		// the logged status matches the response.
 		err := c.Next()
+		if err != nil {
+			err = c.App().ErrorHandler(c, err)
+		}
 
 		// Log request details
 		duration := c.Context().Time().Sub(start)
@@ -46,26 +66,224 @@ func SetupRoutes(app *fiber.App, infraInstance *infra.Infrastructure, cfg *infra
 		return err
 	})
 
-	// Health check endpoint
+	// Assign A/B experiment variants before any handler reads them
+	app.Use(middleware.AssignExperiments(ctrls.Services.Experiments))
+
+	// Run the user event retention task on its schedule
+	infraInstance.Scheduler.Every("events-retention", cfg.Retention.Interval, func() {
+		if _, err := ctrls.Services.Retention.Run(context.Background()); err != nil {
+			appLogger.Warn("Scheduled retention run did not complete", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	})
+
+	// Move old articles to the archive table on their schedule
+	infraInstance.Scheduler.Every("articles-archive", cfg.Archive.Interval, func() {
+		if _, err := ctrls.Services.Archive.Run(context.Background(), cfg.Archive.DryRun); err != nil {
+			appLogger.Warn("Scheduled archive run did not complete", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	})
+
+	// Upload corpus snapshots to object storage on their schedule, when a bucket is configured
+	if ctrls.Services.Export.Enabled() {
+		infraInstance.Scheduler.Every("articles-snapshot", cfg.Export.SnapshotInterval, func() {
+			if _, err := ctrls.Services.Export.Run(context.Background(), cfg.Export.SnapshotMode, cfg.Export.SnapshotIncludeVectors); err != nil {
+				appLogger.Warn("Scheduled article snapshot did not complete", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		})
+	}
+
+	// Recompute relevance scores from recent engagement on their schedule
+	infraInstance.Scheduler.Every("relevance-rescore", cfg.Relevance.Interval, func() {
+		if _, err := ctrls.Services.Relevance.Rescore(context.Background()); err != nil {
+			appLogger.Warn("Scheduled relevance rescore did not complete", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	})
+
+	// Store queued query log entries on shutdown, before the database is closed
+	infraInstance.OnClose(ctrls.Services.QueryLog.Close)
+
+	// Delete expired query log entries on their schedule
+	infraInstance.Scheduler.Every("query-log-retention", cfg.QueryLog.RetentionInterval, func() {
+		if _, err := ctrls.Services.QueryLog.Prune(context.Background()); err != nil {
+			appLogger.Warn("Scheduled query log retention did not complete", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	})
+
+	// Rebuild each tenant's query spelling dictionary as its corpus vocabulary changes
+	if cfg.Query.SpellCorrection {
+		infraInstance.Scheduler.Every("spell-dictionary", cfg.Query.SpellRefreshInterval, func() {
+			for _, tenant := range cfg.Tenant.IDs() {
+				if _, err := ctrls.Services.Spelling.Rebuild(infra.WithTenant(context.Background(), tenant)); err != nil {
+					appLogger.Warn("Scheduled spelling dictionary rebuild did not complete", map[string]interface{}{
+						"tenant": tenant,
+						"error":  err.Error(),
+					})
+				}
+			}
+		})
+	}
+
+	// Group new articles into story clusters on their schedule
+	infraInstance.Scheduler.Every("story-clustering", cfg.Cluster.Interval, func() {
+		if _, err := ctrls.Services.Clustering.Run(context.Background()); err != nil {
+			appLogger.Warn("Scheduled story clustering run did not complete", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	})
+
+	// Keep the trending results of each tenant's most requested locations warm in the cache
+	infraInstance.Scheduler.Every("trending-precompute", cfg.Trending.PrecomputeInterval, func() {
+		for _, tenant := range cfg.Tenant.IDs() {
+			if _, err := ctrls.Services.Article.PrecomputeTrending(infra.WithTenant(context.Background(), tenant)); err != nil {
+				appLogger.Warn("Scheduled trending precompute did not complete", map[string]interface{}{
+					"tenant": tenant,
+					"error":  err.Error(),
+				})
+			}
+		}
+	})
+
+	// Probe the LLM API in the background so health reports a revoked key or an outage
+	infraInstance.Scheduler.Every("llm-health-probe", cfg.LLM.HealthProbeInterval, func() {
+		ctrls.Services.LLM.CheckHealth(context.Background())
+	})
+
+	// Report a missing or outdated vector index at startup
+	ctrls.Services.VectorIndex.CheckIndex(context.Background())
+
+	// Health check endpoint. check_llm=true probes the LLM API now instead of reporting the
+	// last background probe; probes are rate-limited either way.
 	app.Get("/health", func(c *fiber.Ctx) error {
+		llmHealth := ctrls.Services.LLM.LastHealth()
+		if c.QueryBool("check_llm") {
+			health := ctrls.Services.LLM.CheckHealth(c.UserContext())
+			llmHealth = &health
+		}
+
 		return c.JSON(fiber.Map{
-			"status":  "healthy",
-			"service": "inshorts-api",
+			"status":    "healthy",
+			"service":   "inshorts-api",
+			"retention": ctrls.Services.Retention.LastRun(),
+			"archive":   ctrls.Services.Archive.LastRun(),
+			"llm": fiber.Map{
+				"circuit": ctrls.Services.LLM.CircuitState(),
+				"probe":   llmHealth,
+			},
 		})
 	})
 
-	// Define route groups for /api/v1/news and /api/v1/interactions
-	apiV1 := app.Group("/api/")
+	// Define route groups for /api/v1/news and /api/v1/interactions. API requests are scoped
+	// to their tenant before any handler queries articles or events.
+	apiV1 := app.Group("/api/", middleware.ResolveTenant(ctrls.Services.Tenants))
+
+	// Per-route time budgets; bulk loads and exports are exempt since they are expected to run long
+	timeouts := cfg.Server.Timeouts
+	defaultTimeout := middleware.Timeout(timeouts.Default)
+
+	// Concurrency caps for the routes that fan out to the LLM and the database. They run
+	// before the time budget so waiting for a slot does not use it up.
+	concurrency := cfg.Server.Concurrency
+	queryLimit := middleware.ConcurrencyLimit("query", concurrency.Query, concurrency.MaxWait)
+	trendingLimit := middleware.ConcurrencyLimit("trending", concurrency.Trending, concurrency.MaxWait)
+
+	// API-key protection for admin, compliance and debugging endpoints
+	requireAPIKey := middleware.RequireAPIKey(cfg.Server.AdminAPIKey)
 
 	// News routes
 	newsRoutes := apiV1.Group("v1/news")
-	newsRoutes.Post("/", ctrls.Article.CreateArticle)
-	newsRoutes.Get("/query", ctrls.Article.QueryArticles)
-	newsRoutes.Get("/trending", ctrls.Article.GetTrending)
-	newsRoutes.Get("/filter", ctrls.Article.FilterArticles)
+	newsRoutes.Get("/", defaultTimeout, ctrls.Article.ListArticles)
+	newsRoutes.Post("/", defaultTimeout, ctrls.Article.CreateArticle)
+	newsRoutes.Get("/query", queryLimit, middleware.Timeout(timeouts.Query), ctrls.Article.QueryArticles)
+	newsRoutes.Get("/query/analyze", requireAPIKey, queryLimit, middleware.Timeout(timeouts.Query), ctrls.Article.AnalyzeQuery)
+	newsRoutes.Get("/trending", trendingLimit, middleware.Timeout(timeouts.Trending), middleware.HTTPCache(cfg.Cache.TrendingMaxAge), ctrls.Article.GetTrending)
+	newsRoutes.Get("/trending/topics", middleware.Timeout(timeouts.Query), middleware.HTTPCache(cfg.Cache.TrendingMaxAge), ctrls.Article.GetTrendingTopics)
+	newsRoutes.Get("/filter", middleware.Timeout(timeouts.Filter), middleware.HTTPCache(cfg.Cache.FilterMaxAge), ctrls.Article.FilterArticles)
+	newsRoutes.Get("/search", defaultTimeout, ctrls.Article.SearchArticles)
+	newsRoutes.Get("/clusters", defaultTimeout, ctrls.Cluster.ListClusters)
+	newsRoutes.Get("/export", ctrls.Article.ExportArticles)
+	newsRoutes.Get("/feed.rss", defaultTimeout, middleware.HTTPCache(cfg.Cache.FeedTTL), ctrls.Article.GetFeed)
 	newsRoutes.Post("/load", ctrls.Article.LoadData)
+	newsRoutes.Post("/backfill", requireAPIKey, ctrls.Article.Backfill)
+	newsRoutes.Get("/:id/stats", defaultTimeout, ctrls.Article.GetArticleStats)
+	newsRoutes.Post("/:id/summarize", requireAPIKey, middleware.Timeout(timeouts.Query), ctrls.Article.SummarizeArticle)
+	newsRoutes.Delete("/:id", requireAPIKey, defaultTimeout, ctrls.Article.DeleteArticle)
+
+	// Background job routes
+	jobRoutes := apiV1.Group("v1/jobs")
+	jobRoutes.Get("/:id", ctrls.Job.GetJob)
+
+	// Admin routes
+	adminRoutes := apiV1.Group("v1/admin", requireAPIKey)
+	adminRoutes.Delete("/news/:id", ctrls.Article.PurgeArticle)
+	adminRoutes.Post("/news/:id/restore", ctrls.Article.RestoreArticle)
+	adminRoutes.Post("/retention/run", ctrls.Retention.RunRetention)
+	adminRoutes.Post("/archive/run", ctrls.Archive.RunArchive)
+	adminRoutes.Get("/exports", ctrls.Export.ListExports)
+	adminRoutes.Post("/exports", ctrls.Export.StartExport)
+	adminRoutes.Post("/webhooks/test", ctrls.Webhook.TestDelivery)
+	adminRoutes.Get("/source-aliases", ctrls.SourceAlias.ListAliases)
+	adminRoutes.Get("/source-aliases/:alias", ctrls.SourceAlias.GetAlias)
+	adminRoutes.Put("/source-aliases/:alias", ctrls.SourceAlias.PutAlias)
+	adminRoutes.Delete("/source-aliases/:alias", ctrls.SourceAlias.DeleteAlias)
+	adminRoutes.Get("/categories", ctrls.Category.ListCategories)
+	adminRoutes.Post("/categories", ctrls.Category.CreateCategory)
+	adminRoutes.Post("/categories/:name/rename", ctrls.Category.RenameCategory)
+	adminRoutes.Post("/categories/:name/merge", ctrls.Category.MergeCategories)
+	adminRoutes.Get("/llm/usage", ctrls.LLM.GetUsage)
+	adminRoutes.Get("/llm/audit", ctrls.LLM.ListAudit)
+	adminRoutes.Delete("/llm/audit", ctrls.LLM.PurgeAudit)
+	adminRoutes.Get("/queries/top", ctrls.QueryLog.TopQueries)
+	adminRoutes.Get("/vector-index", ctrls.VectorIndex.GetStatus)
+	adminRoutes.Post("/vector-index/reindex", ctrls.VectorIndex.Reindex)
+	adminRoutes.Post("/cache/flush", ctrls.Cache.Flush)
+	adminRoutes.Get("/stats", ctrls.Infra.GetStats)
+	adminRoutes.Get("/stats/articles", ctrls.Article.GetCorpusStats)
+	adminRoutes.Get("/stats/interactions", ctrls.Article.GetInteractionStats)
+
+	// Application metrics at GET /debug/vars, admin-only like the routes above
+	app.Get("/debug/vars", requireAPIKey, expvar.New())
 
 	// User interaction routes
 	interactionRoutes := apiV1.Group("v1/interactions")
-	interactionRoutes.Post("/record", ctrls.UserInteraction.RecordInteraction)
+	interactionRoutes.Post("/record", defaultTimeout, ctrls.UserInteraction.RecordInteraction)
+	interactionRoutes.Post("/batch", defaultTimeout, ctrls.UserInteraction.RecordInteractionBatch)
+	interactionRoutes.Get("/users/:user_id/bookmarks", defaultTimeout, ctrls.UserInteraction.GetBookmarks)
+	interactionRoutes.Delete("/users/:user_id", requireAPIKey, ctrls.UserInteraction.PurgeUserEvents)
+
+	// User preference routes
+	preferenceRoutes := apiV1.Group("v1/users/:user_id/preferences", defaultTimeout)
+	preferenceRoutes.Get("/", ctrls.UserPreference.GetPreferences)
+	preferenceRoutes.Put("/", ctrls.UserPreference.PutPreferences)
+
+	// Saved search routes
+	searchRoutes := apiV1.Group("v1/users/:user_id/searches", defaultTimeout)
+	searchRoutes.Post("/", ctrls.SavedSearch.CreateSearch)
+	searchRoutes.Get("/", ctrls.SavedSearch.ListSearches)
+	searchRoutes.Get("/:id", ctrls.SavedSearch.GetSearch)
+	searchRoutes.Delete("/:id", ctrls.SavedSearch.DeleteSearch)
+	searchRoutes.Get("/:id/matches", ctrls.SavedSearch.GetMatches)
+
+	// Unmatched routes get the JSON error envelope instead of Fiber's plain-text 404
+	app.Use(func(c *fiber.Ctx) error {
+		return middleware.ErrRouteNotFound
+	})
+
+	return ctrls
+}
+
+// compression returns the response compression middleware at level, as documented on
+// infra.ServerConfig.CompressLevel
+func compression(level int) fiber.Handler {
+	return compress.New(compress.Config{Level: compress.Level(level)})
 }
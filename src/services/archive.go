@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrArchiveRunning is returned when an archive run is requested while another is in progress
+var ErrArchiveRunning = errors.New("archive run already in progress")
+
+// ArchiveService moves articles older than the configured age to the archive table, keeping
+// the articles table to the recent articles most queries touch
+type ArchiveService interface {
+	// Run archives in batches and blocks until done. With dryRun it only counts the articles
+	// it would move.
+	Run(ctx context.Context, dryRun bool) (models.ArchiveRun, error)
+	StartRun() (models.Job, error)
+	LastRun() *models.ArchiveRun
+}
+
+// archiveService implements ArchiveService
+type archiveService struct {
+	articleRepo repositories.ArticleRepository
+	jobs        *JobTracker
+	filterCache *filterCache
+	cfg         *infra.ArchiveConfig
+	clock       infra.Clock
+	log         infra.Logger
+
+	running atomic.Bool
+	mu      sync.RWMutex
+	lastRun *models.ArchiveRun
+}
+
+// NewArchiveService creates a new instance of ArchiveService
+func NewArchiveService(articleRepo repositories.ArticleRepository, jobs *JobTracker, cfg *infra.ArchiveConfig, redisClient *redis.Client, filterCacheTTL time.Duration, clock infra.Clock, logger infra.Logger) ArchiveService {
+	return &archiveService{
+		articleRepo: articleRepo,
+		jobs:        jobs,
+		filterCache: newFilterCache(redisClient, filterCacheTTL, logger),
+		cfg:         cfg,
+		clock:       clock,
+		log:         logger,
+	}
+}
+
+// Run archives old articles, or counts them with dryRun
+func (s *archiveService) Run(ctx context.Context, dryRun bool) (models.ArchiveRun, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return models.ArchiveRun{}, ErrArchiveRunning
+	}
+	defer s.running.Store(false)
+
+	if dryRun {
+		return s.count(ctx)
+	}
+	return s.archive(ctx, nil)
+}
+
+// StartRun launches a run in the background and returns a job that can be polled for progress
+func (s *archiveService) StartRun() (models.Job, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return models.Job{}, ErrArchiveRunning
+	}
+
+	job := s.jobs.Start("archive")
+
+	go func() {
+		defer s.running.Store(false)
+
+		// The run outlives the request that started it, so it gets its own context
+		_, err := s.archive(context.Background(), func(archived int64) {
+			s.jobs.Update(job.ID, func(j *models.Job) {
+				j.Processed = int(archived)
+				j.Succeeded = int(archived)
+			})
+		})
+		s.jobs.Finish(job.ID, err)
+	}()
+
+	return job, nil
+}
+
+// LastRun returns the most recent completed run, or nil if none has finished yet
+func (s *archiveService) LastRun() *models.ArchiveRun {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.lastRun == nil {
+		return nil
+	}
+	run := *s.lastRun
+	return &run
+}
+
+// count reports how many articles a run would archive now, without moving any
+func (s *archiveService) count(ctx context.Context) (models.ArchiveRun, error) {
+	startedAt := s.clock.Now()
+	run := models.ArchiveRun{
+		StartedAt: startedAt,
+		Cutoff:    startedAt.Add(-s.cfg.MaxAge),
+		DryRun:    true,
+	}
+
+	wouldArchive, err := s.articleRepo.CountOlderThan(ctx, run.Cutoff)
+	if err != nil {
+		run.Error = err.Error()
+		return run, err
+	}
+	run.WouldArchive = wouldArchive
+
+	finishedAt := s.clock.Now()
+	run.FinishedAt = &finishedAt
+
+	s.mu.Lock()
+	s.lastRun = &run
+	s.mu.Unlock()
+
+	s.log.Info("Completed article archive dry run", map[string]interface{}{
+		"cutoff":        run.Cutoff,
+		"would_archive": run.WouldArchive,
+	})
+
+	return run, nil
+}
+
+// archive moves old articles batch by batch, logging and reporting the running total to
+// progress after each batch, and records the outcome as the last run
+func (s *archiveService) archive(ctx context.Context, progress func(archived int64)) (models.ArchiveRun, error) {
+	startedAt := s.clock.Now()
+	run := models.ArchiveRun{
+		StartedAt: startedAt,
+		Cutoff:    startedAt.Add(-s.cfg.MaxAge),
+	}
+
+	s.log.Info("Starting article archive run", map[string]interface{}{
+		"cutoff":     run.Cutoff,
+		"batch_size": s.cfg.BatchSize,
+	})
+
+	var runErr error
+	for {
+		archived, err := s.articleRepo.ArchiveOlderThan(ctx, run.Cutoff, s.cfg.BatchSize)
+		if err != nil {
+			runErr = err
+			run.Error = err.Error()
+			break
+		}
+
+		run.Archived += archived
+		infra.IncrCounter(infra.MetricArchiveArticlesMoved, archived)
+		if progress != nil {
+			progress(run.Archived)
+		}
+
+		s.log.Info("Archived article batch", map[string]interface{}{
+			"batch":    archived,
+			"archived": run.Archived,
+		})
+
+		if archived < int64(s.cfg.BatchSize) {
+			break
+		}
+	}
+
+	finishedAt := s.clock.Now()
+	run.FinishedAt = &finishedAt
+
+	infra.SetGauge(infra.MetricArchiveLastRunUnix, finishedAt.Unix())
+
+	// Archived articles drop out of filter results that do not include the archive
+	if run.Archived > 0 {
+		s.filterCache.invalidate(ctx)
+	}
+
+	s.mu.Lock()
+	s.lastRun = &run
+	s.mu.Unlock()
+
+	if runErr != nil {
+		s.log.Error("Article archive run failed", runErr, map[string]interface{}{
+			"archived": run.Archived,
+		})
+		return run, runErr
+	}
+
+	s.log.Info("Completed article archive run", map[string]interface{}{
+		"archived": run.Archived,
+		"duration": finishedAt.Sub(run.StartedAt).String(),
+	})
+
+	return run, nil
+}
@@ -1,25 +1,105 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"news-inshorts/src/infra"
 	"news-inshorts/src/models"
+	"news-inshorts/src/renderer"
 	"news-inshorts/src/repositories"
 	"news-inshorts/src/types"
+	"news-inshorts/src/utils"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Enrichment operations run for each article loaded from JSON
+const (
+	enrichSummary   = "summary"
+	enrichEmbedding = "embedding"
+	enrichSentiment = "sentiment"
+	enrichPlace     = "place"
 )
 
 // ArticleService defines the interface for news operations
 type ArticleService interface {
-	ProcessArticleQuery(query string, location *models.Location) ([]models.Article, error)
-	GetTrendingNews(lat, lon float64, limit int) ([]models.Article, error)
-	FilterArticles(params types.FilterArticlesRequest) ([]models.Article, error)
-	LoadFromJSON(filepath string) (*repositories.LoadStats, error)
-	CreateArticle(article *models.Article) error
+	// ProcessArticleQuery biases the ranking by the category preferences of userID when set,
+	// returns summaries in the language with code summaryLang when set, and corrects misspelled
+	// query words unless noCorrect is set
+	ProcessArticleQuery(ctx context.Context, query string, location *models.Location, limit int, minSimilarity *float64, userID string, summaryLang string, noCorrect bool) (*QueryResult, error)
+	// AnalyzeQuery returns what ProcessArticleQuery would do for the query without running any filter
+	AnalyzeQuery(ctx context.Context, query string, location *models.Location, minSimilarity *float64, userID string, noCorrect bool) (*QueryAnalysisResult, error)
+	GetTrendingNews(ctx context.Context, lat, lon float64, limit int) ([]models.TrendingArticle, error)
+	// PrecomputeTrending refreshes the cached trending articles of the most requested geohash
+	// cells and returns how many cells were refreshed
+	PrecomputeTrending(ctx context.Context) (int, error)
+	GetTrendingTopics(ctx context.Context, lat, lon float64, articleLimit, limit int) (*TrendingTopics, error)
+	FilterArticles(ctx context.Context, params types.FilterArticlesRequest) ([]models.Article, error)
+	// ListArticles pages through all articles, newest publication first
+	ListArticles(ctx context.Context, cursor string, limit int) ([]models.Article, string, error)
+	SearchArticles(ctx context.Context, params types.SearchArticlesRequest) ([]models.Article, error)
+	ExportSize(ctx context.Context, params types.FilterArticlesRequest) (*ExportSize, error)
+	ExportArticles(ctx context.Context, params types.FilterArticlesRequest, format string, w io.Writer) (int, error)
+	LoadFromJSON(ctx context.Context, filepath string, dryRun bool, detectDuplicates bool) (*repositories.LoadStats, error)
+	CreateArticle(ctx context.Context, article *models.Article) error
+	StartBackfill(ctx context.Context, afterID string, maxArticles int) models.Job
+	DeleteArticle(ctx context.Context, id string) error
+	RestoreArticle(ctx context.Context, id string) error
+	PurgeArticle(ctx context.Context, id string) error
+	// SummarizeArticle regenerates the stored summary of a live article. Concurrent calls for
+	// the same article fail with ErrSummaryInProgress.
+	SummarizeArticle(ctx context.Context, id string) (*SummarizeResult, error)
+}
+
+// QueryResult is the outcome of a natural-language article query
+type QueryResult struct {
+	// Query is the normalized query, before spelling correction
+	Query    string
+	Articles []models.EnrichedArticle
+	// Total is the number of matching articles before truncation to the requested limit
+	Total int
+	// Degraded is true when the LLM was unavailable and the rule-based fallback analyzed the query
+	Degraded bool
+	// QueryTruncated is true when the query exceeded the soft length limit and was shortened
+	QueryTruncated bool
+	// CorrectedQuery is the query that was run when misspelled words were corrected, empty otherwise
+	CorrectedQuery string
+	// Relaxed lists the constraints relaxed, in order, because the query as stated matched nothing
+	Relaxed []string
+}
+
+// QueryAnalysisResult is the analysis of a natural-language query and the filter plan it
+// turns into, for inspecting what the LLM decided
+type QueryAnalysisResult struct {
+	// Query is the normalized query that was analyzed
+	Query          string
+	QueryTruncated bool
+	CorrectedQuery string
+	Analysis       models.QueryAnalysis
+	Plan           []models.FilterStep
+	CacheHit       bool
+	Degraded       bool
+}
+
+// ExportSize describes how many articles an export will contain
+type ExportSize struct {
+	// Total is the number of matching articles
+	Total int64
+	// Rows is the number of articles the export will contain, at most MaxRows
+	Rows      int64
+	MaxRows   int
+	Truncated bool
 }
 
 // articleService implements ArticleService
@@ -27,85 +107,439 @@ type articleService struct {
 	llmService      LLMService
 	filterChain     *FilterChain
 	trendingService TrendingService
+	webhookService  WebhookService
+	savedSearches   SavedSearchService
+	geocoder        GeocodingService
+	sourceAliases   SourceAliasService
+	categories      CategoryService
+	spelling        SpellingService
+	contentFetcher  ContentFetcher
 	articleRepo     repositories.ArticleRepository
 	userEventRepo   repositories.UserEventRepository
+	userPrefRepo    repositories.UserPreferenceRepository
+	jobs            *JobTracker
+	enrichCfg       *infra.EnrichConfig
+	contentCfg      *infra.ContentConfig
+	exportCfg       *infra.ExportConfig
+	queryCfg        *infra.QueryConfig
+	dedupeCfg       *infra.DedupeConfig
+	trendingCfg     *infra.TrendingConfig
+	defaultTenant   string
+	clock           infra.Clock
+	filterCache     *filterCache
+	queryCache      *queryAnalysisCache
+	topicsCache     *trendingTopicsCache
+	summaryCache    *summaryLangCache
+	summarizeLock   *summarizeLock
 	logger          infra.Logger
 }
 
-// NewArticleService creates a new instance of ArticleService
+// NewArticleService creates a new instance of ArticleService. geocoder may be nil, in which
+// case articles are stored without a country and region unless the caller provides them, and
+// so may contentFetcher, in which case loaded articles are enriched from their description.
+// Cached results are kept per tenant, with defaultTenant used for contexts carrying none.
 func NewArticleService(
 	llmService LLMService,
 	filterChain *FilterChain,
 	trendingService TrendingService,
+	webhookService WebhookService,
+	savedSearches SavedSearchService,
+	geocoder GeocodingService,
+	sourceAliases SourceAliasService,
+	categories CategoryService,
+	spelling SpellingService,
+	contentFetcher ContentFetcher,
 	articleRepo repositories.ArticleRepository,
 	userEventRepo repositories.UserEventRepository,
+	userPrefRepo repositories.UserPreferenceRepository,
+	jobs *JobTracker,
+	enrichCfg *infra.EnrichConfig,
+	contentCfg *infra.ContentConfig,
+	exportCfg *infra.ExportConfig,
+	queryCfg *infra.QueryConfig,
+	dedupeCfg *infra.DedupeConfig,
+	trendingCfg *infra.TrendingConfig,
+	defaultTenant string,
+	redisClient *redis.Client,
+	filterCacheTTL time.Duration,
+	queryCacheTTL time.Duration,
+	topicsCacheTTL time.Duration,
+	clock infra.Clock,
+	logger infra.Logger,
 ) ArticleService {
 	return &articleService{
 		llmService:      llmService,
 		filterChain:     filterChain,
 		trendingService: trendingService,
+		webhookService:  webhookService,
+		savedSearches:   savedSearches,
+		geocoder:        geocoder,
+		sourceAliases:   sourceAliases,
+		categories:      categories,
+		spelling:        spelling,
+		contentFetcher:  contentFetcher,
 		articleRepo:     articleRepo,
 		userEventRepo:   userEventRepo,
-		logger:          infra.GetLogger(),
+		userPrefRepo:    userPrefRepo,
+		jobs:            jobs,
+		enrichCfg:       enrichCfg,
+		contentCfg:      contentCfg,
+		exportCfg:       exportCfg,
+		queryCfg:        queryCfg,
+		dedupeCfg:       dedupeCfg,
+		trendingCfg:     trendingCfg,
+		defaultTenant:   defaultTenant,
+		clock:           clock,
+		filterCache:     newFilterCache(redisClient, filterCacheTTL, logger),
+		queryCache:      newQueryAnalysisCache(redisClient, queryCacheTTL, clock, logger),
+		topicsCache:     newTrendingTopicsCache(redisClient, topicsCacheTTL, logger),
+		summaryCache:    newSummaryLangCache(redisClient, enrichCfg.SummaryLangCacheTTL, logger),
+		summarizeLock:   newSummarizeLock(redisClient, logger),
+		logger:          logger,
 	}
 }
 
 // ProcessArticleQuery orchestrates LLM query analysis and filter chain execution
 // to retrieve and enrich relevant news articles
-func (s *articleService) ProcessArticleQuery(query string, location *models.Location) ([]models.Article, error) {
-	allowedSources, err := s.articleRepo.GetDistinctSourceNames()
+func (s *articleService) ProcessArticleQuery(ctx context.Context, rawQuery string, location *models.Location, limit int, minSimilarity *float64, userID string, summaryLang string, noCorrect bool) (*QueryResult, error) {
+	if summaryLang != "" && !models.IsValidSummaryLang(summaryLang) {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSummaryLang, summaryLang)
+	}
+
+	prepared, err := preprocessQuery(rawQuery, s.queryCfg.SoftMaxLength, s.queryCfg.HardMaxLength)
+	if err != nil {
+		s.logger.Debug("Rejected query", map[string]interface{}{
+			"raw_query": rawQuery,
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+
+	query := prepared.Text
+	if prepared.Truncated {
+		s.logger.Info("Query truncated to the soft length limit", map[string]interface{}{
+			"query":      query,
+			"max_length": s.queryCfg.SoftMaxLength,
+		})
+	}
+	s.logger.Debug("Processing query", map[string]interface{}{
+		"raw_query": rawQuery,
+		"query":     query,
+	})
+
+	correctedQuery := s.correctQuery(ctx, query, noCorrect)
+	if correctedQuery != "" {
+		query = correctedQuery
+	}
+
+	analyzed, err := s.analyzeQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	analysis := analyzed.Analysis
+
+	preferences := s.userPreferences(ctx, userID)
+
+	// Relaxed retries run the same text search, which then reuses the query embedding
+	ctx = withEmbeddingMemo(ctx)
+	plan := s.filterChain.Plan(analysis.Intents, analysis.Entities, location, minSimilarity, preferences)
+	filteredArticles, err := s.filterChain.ExecutePlan(ctx, plan)
+	if err != nil {
+		var fields map[string]interface{}
+		var filterErr *FilterError
+		if errors.As(err, &filterErr) {
+			fields = filterErr.LogFields()
+		}
+		s.logger.Error("Failed to execute filter chain", err, fields)
+		return nil, fmt.Errorf("failed to filter articles: %w", err)
+	}
+
+	var relaxed []string
+	if len(filteredArticles) == 0 && s.queryCfg.RelaxEmptyResults {
+		if articles, constraints := s.relax(ctx, plan); len(articles) > 0 {
+			filteredArticles, relaxed = articles, constraints
+		}
+	}
+
+	// The chain ends with the relevance-ordered score filter, so truncating here keeps the best matches
+	total := len(filteredArticles)
+	if len(filteredArticles) > limit {
+		filteredArticles = filteredArticles[:limit]
+	}
+
+	if s.enrichCfg.LazySummaries {
+		s.fillMissingSummaries(ctx, filteredArticles)
+	}
+	if summaryLang != "" {
+		s.localizeSummaries(ctx, filteredArticles, summaryLang)
+	}
+
+	return &QueryResult{
+		Query:          prepared.Text,
+		Articles:       filteredArticles,
+		Total:          total,
+		Degraded:       analyzed.Degraded,
+		QueryTruncated: prepared.Truncated,
+		CorrectedQuery: correctedQuery,
+		Relaxed:        relaxed,
+	}, nil
+}
+
+// correctQuery returns the query with misspelled words corrected, or an empty string when
+// nothing was corrected or the caller opted out
+func (s *articleService) correctQuery(ctx context.Context, query string, noCorrect bool) string {
+	if noCorrect {
+		return ""
+	}
+
+	corrected, ok := s.spelling.Correct(ctx, query)
+	if !ok {
+		return ""
+	}
+
+	s.logger.Info("Corrected query spelling", map[string]interface{}{
+		"query":     query,
+		"corrected": corrected,
+	})
+	return corrected
+}
+
+// fillMissingSummaries generates summaries for the result articles that have none and stores
+// them, so later queries return them without another LLM call. Each call is bounded by the lazy
+// summary timeout; an article whose summary could not be generated keeps an empty one.
+func (s *articleService) fillMissingSummaries(ctx context.Context, articles []models.EnrichedArticle) {
+	var missing []int
+	for i := range articles {
+		if articles[i].Summary == "" {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	var mu sync.Mutex
+	stored := 0
+
+	runBounded(len(missing), s.enrichCfg.LazySummaryWorkers, func(task int) {
+		article := &articles[missing[task]].Article
+
+		summaryCtx, cancel := context.WithTimeout(ctx, s.enrichCfg.LazySummaryTimeout)
+		summary, err := s.llmService.GenerateSummary(summaryCtx, article.Title, s.enrichmentText(article))
+		cancel()
+		if err != nil {
+			s.logger.Warn("Failed to generate summary for query result", map[string]interface{}{
+				"id":    article.ID,
+				"title": article.Title,
+				"error": err.Error(),
+			})
+			return
+		}
+		if summary == "" {
+			return
+		}
+
+		// Each task owns its article, so only the counter needs the lock
+		article.Summary = summary
+		if err := s.articleRepo.UpdateSummary(ctx, article.ID, summary); err != nil {
+			return
+		}
+		mu.Lock()
+		stored++
+		mu.Unlock()
+	})
+
+	// Filter results carry summaries, so stored ones make them stale
+	if stored > 0 {
+		s.filterCache.invalidate(ctx)
+	}
+}
+
+// AnalyzeQuery runs the query analysis step of ProcessArticleQuery and derives the filter plan
+// it would execute, without running any filter
+func (s *articleService) AnalyzeQuery(ctx context.Context, rawQuery string, location *models.Location, minSimilarity *float64, userID string, noCorrect bool) (*QueryAnalysisResult, error) {
+	prepared, err := preprocessQuery(rawQuery, s.queryCfg.SoftMaxLength, s.queryCfg.HardMaxLength)
+	if err != nil {
+		return nil, err
+	}
+
+	query := prepared.Text
+	correctedQuery := s.correctQuery(ctx, query, noCorrect)
+	if correctedQuery != "" {
+		query = correctedQuery
+	}
+
+	analyzed, err := s.analyzeQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := s.filterChain.Plan(analyzed.Analysis.Intents, analyzed.Analysis.Entities, location, minSimilarity, s.userPreferences(ctx, userID))
+
+	return &QueryAnalysisResult{
+		Query:          prepared.Text,
+		QueryTruncated: prepared.Truncated,
+		CorrectedQuery: correctedQuery,
+		Analysis:       *analyzed.Analysis,
+		Plan:           plan,
+		CacheHit:       analyzed.CacheHit,
+		Degraded:       analyzed.Degraded,
+	}, nil
+}
+
+// analyzedQuery is the analysis of a normalized query and how it was obtained
+type analyzedQuery struct {
+	Analysis *models.QueryAnalysis
+	// CacheHit is true when the analysis came from the query analysis cache
+	CacheHit bool
+	// Degraded is true when the LLM was unavailable and the rule-based fallback analyzed the query
+	Degraded bool
+}
+
+// analyzeQuery analyzes a normalized query against the stored sources and categories, from
+// the cache when possible, falling back to the rule-based parser when the LLM fails
+func (s *articleService) analyzeQuery(ctx context.Context, query string) (*analyzedQuery, error) {
+	allowedSources, err := s.articleRepo.GetDistinctSourceNames(ctx)
 	if err != nil {
 		s.logger.Error("Failed to get allowed sources", err, nil)
 		return nil, fmt.Errorf("failed to get allowed sources: %w", err)
 	}
 
-	allowedCategories, err := s.articleRepo.GetDistinctCategories()
+	// The LLM picks from canonical names; source filters expand aliases back to stored names
+	if canonical, err := s.sourceAliases.CanonicalSourceNames(ctx, allowedSources); err != nil {
+		s.logger.Warn("Failed to apply source aliases, offering stored source names", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		allowedSources = canonical
+	}
+
+	allowedCategories, err := s.articleRepo.GetDistinctCategories(ctx)
 	if err != nil {
 		s.logger.Error("Failed to get allowed categories", err, nil)
 		return nil, fmt.Errorf("failed to get allowed categories: %w", err)
 	}
 
-	analysis, err := s.llmService.ProcessQuery(query, allowedSources, allowedCategories)
+	result := &analyzedQuery{}
+	cacheKey := ""
+	if s.queryCache.enabled() {
+		cacheKey = queryAnalysisCacheKey(s.tenant(ctx), query, allowedSources, allowedCategories)
+		result.Analysis, result.CacheHit = s.queryCache.get(ctx, cacheKey)
+	}
+	if result.CacheHit {
+		return result, nil
+	}
+
+	result.Analysis, err = s.llmService.ProcessQuery(ctx, query, allowedSources, allowedCategories)
 	if err != nil {
-		s.logger.Error("Failed to analyze query with LLM", err, map[string]interface{}{
+		// A cancelled request is not an LLM outage; don't degrade, just stop
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		s.logger.Warn("LLM query analysis failed, using rule-based fallback", map[string]interface{}{
 			"query": query,
+			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to analyze query: %w", err)
+		infra.IncrCounter(infra.MetricQueryFallbackActivations, 1)
+		result.Analysis = FallbackQueryAnalysis(query, allowedSources, allowedCategories)
+		result.Degraded = true
+	} else if cacheKey != "" {
+		// Only LLM analyses are cached so a recovered LLM is used again straight away
+		s.queryCache.set(ctx, cacheKey, result.Analysis)
 	}
 
-	filteredArticles, err := s.filterChain.Execute(analysis.Intents, analysis.Entities, location)
-	if err != nil {
-		s.logger.Error("Failed to execute filter chain", err, nil)
-		return nil, fmt.Errorf("failed to filter articles: %w", err)
-	}
+	return result, nil
+}
 
-	if len(filteredArticles) > 5 {
-		filteredArticles = filteredArticles[:5]
+// userPreferences returns the category weights of userID, or nil without a user. Preferences
+// only re-rank, so a lookup failure degrades to the unpersonalized ranking.
+func (s *articleService) userPreferences(ctx context.Context, userID string) map[string]float64 {
+	if userID == "" {
+		return nil
 	}
 
-	return filteredArticles, nil
+	preferences, err := s.userPrefRepo.Get(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to load user preferences, ranking without them", map[string]interface{}{
+			"user_hash": utils.HashIdentifier(userID),
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return preferences
 }
 
-// GetTrendingNews retrieves trending articles based on location
-func (s *articleService) GetTrendingNews(lat, lon float64, limit int) ([]models.Article, error) {
+// GetTrendingNews retrieves trending articles based on location. Results are cached per
+// geohash cell and ranked for the center of the cell, so every location in a cell gets the
+// same articles whether they come from the cache or not; only their distance is measured
+// from lat/lon.
+func (s *articleService) GetTrendingNews(ctx context.Context, lat, lon float64, limit int) ([]models.TrendingArticle, error) {
+	cell := s.trendingService.Cell(lat, lon)
 	s.logger.Info("Getting trending news", map[string]interface{}{
 		"latitude":  lat,
 		"longitude": lon,
+		"cell":      cell,
 		"limit":     limit,
 	})
 
-	location := models.Location{
-		Latitude:  lat,
-		Longitude: lon,
+	s.trendingService.RecordRequest(ctx, cell)
+
+	trendingArticles, found := s.trendingService.GetCachedTrending(ctx, cell, limit)
+	if !found {
+		var err error
+		trendingArticles, err = s.refreshTrendingCell(ctx, cell)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(trendingArticles) > limit {
+			trendingArticles = trendingArticles[:limit]
+		}
+
+		s.logger.Info("Computed trending articles", map[string]interface{}{
+			"count": len(trendingArticles),
+		})
+	}
+
+	for i := range trendingArticles {
+		article := &trendingArticles[i]
+		article.DistanceKm = haversineDistance(lat, lon, article.Latitude, article.Longitude)
 	}
 
-	// cachedArticles, found := s.trendingService.GetCachedTrending(lat, lon, limit)
-	// if found {
-	// 	return cachedArticles, nil
-	// }
+	return trendingArticles, nil
+}
+
+// refreshTrendingCell ranks the trending articles for the center of a geohash cell and caches
+// the best trendingCacheSize of them, which covers every limit a request may ask for
+func (s *articleService) refreshTrendingCell(ctx context.Context, cell string) ([]models.TrendingArticle, error) {
+	lat, lon, ok := utils.GeohashCenter(cell)
+	if !ok {
+		return nil, fmt.Errorf("invalid geohash cell %q", cell)
+	}
+
+	trendingArticles, err := s.rankTrending(ctx, models.Location{Latitude: lat, Longitude: lon})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(trendingArticles) > trendingCacheSize {
+		trendingArticles = trendingArticles[:trendingCacheSize]
+	}
+
+	s.trendingService.CacheTrending(ctx, cell, trendingArticles)
+	return trendingArticles, nil
+}
+
+// rankTrending returns every article with user events in the trending window and published
+// within the maximum article age, highest trending score for location first, with
+// near-duplicates collapsed when configured, annotated with its score, rank, event counts and
+// distance from location
+func (s *articleService) rankTrending(ctx context.Context, location models.Location) ([]models.TrendingArticle, error) {
+	now := s.clock.Now()
 
-	// Get distinct article IDs from user_events
-	articleIDs, err := s.userEventRepo.GetArticlesFromUserEvents()
+	// Articles without events in the trending window score no volume and cannot trend
+	articleIDs, err := s.userEventRepo.GetArticleIDsWithEventsSince(ctx, now.Add(-trendingWindow))
 	if err != nil {
 		s.logger.Error("Failed to get distinct article IDs from user events", err, nil)
 		return nil, fmt.Errorf("failed to get distinct article IDs: %w", err)
@@ -113,71 +547,159 @@ func (s *articleService) GetTrendingNews(lat, lon float64, limit int) ([]models.
 
 	if len(articleIDs) == 0 {
 		s.logger.Info("No articles found in user events", nil)
-		return []models.Article{}, nil
+		return []models.TrendingArticle{}, nil
 	}
 
-	// Get articles by IDs
-	articles, err := s.articleRepo.FindByIDs(articleIDs)
+	// Old articles still drawing traffic (often bots) are not worth scoring
+	articles, err := s.articleRepo.FindByIDsPublishedSince(ctx, articleIDs, now.Add(-s.trendingCfg.MaxArticleAge))
 	if err != nil {
 		s.logger.Error("Failed to retrieve articles for trending", err, nil)
 		return nil, fmt.Errorf("failed to retrieve articles: %w", err)
 	}
 
-	type articleWithScore struct {
-		article models.Article
-		score   float64
-	}
-
-	articlesWithScores := make([]articleWithScore, 0, len(articles))
+	scores := make(map[string]TrendingScore, len(articles))
+	scoredArticles := make([]models.Article, 0, len(articles))
 
 	for _, article := range articles {
-		score, err := s.trendingService.ComputeTrendingScore(article, location)
+		score, err := s.trendingService.ComputeTrendingScore(ctx, article, location)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			s.logger.Error("Failed to compute trending score for article", err, map[string]interface{}{
 				"article_id": article.ID,
 			})
 			continue
 		}
 
-		articlesWithScores = append(articlesWithScores, articleWithScore{
-			article: article,
-			score:   score,
-		})
+		scores[article.ID] = score
+		scoredArticles = append(scoredArticles, article)
 	}
 
-	sort.Slice(articlesWithScores, func(i, j int) bool {
-		return articlesWithScores[i].score > articlesWithScores[j].score
+	sort.SliceStable(scoredArticles, func(i, j int) bool {
+		return scores[scoredArticles[i].ID].Score > scores[scoredArticles[j].ID].Score
 	})
 
 	s.logger.Debug("Sorted articles by trending score", map[string]interface{}{
-		"total_scored": len(articlesWithScores),
+		"total_scored": len(scoredArticles),
 	})
 
-	if len(articlesWithScores) > limit {
-		articlesWithScores = articlesWithScores[:limit]
+	if s.dedupeCfg.Trending {
+		deduped, err := FilterDuplicates(s.articleRepo, s.dedupeCfg.Similarity)(ctx, &scoredArticles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collapse duplicate trending articles: %w", err)
+		}
+		scoredArticles = *deduped
 	}
 
-	trendingArticles := make([]models.Article, 0, len(articlesWithScores))
-	for _, aws := range articlesWithScores {
-		trendingArticles = append(trendingArticles, aws.article)
+	trendingArticles := make([]models.TrendingArticle, len(scoredArticles))
+	for i, article := range scoredArticles {
+		score := scores[article.ID]
+		trendingArticles[i] = models.TrendingArticle{
+			Article:       article,
+			TrendingScore: math.Round(score.Score*10000) / 10000,
+			Rank:          i + 1,
+			EventCounts:   score.EventCounts,
+			DistanceKm:    score.DistanceKm,
+		}
 	}
 
-	s.trendingService.CacheTrending(lat, lon, trendingArticles)
+	return trendingArticles, nil
+}
+
+// FilterArticles filters articles based on provided parameters. Results are cached when the
+// filter cache is enabled, unless the request asks to bypass it.
+func (s *articleService) FilterArticles(ctx context.Context, params types.FilterArticlesRequest) ([]models.Article, error) {
+	if !s.filterCache.enabled() || params.CacheBypass {
+		return s.articleRepo.FilterArticles(ctx, params)
+	}
 
-	s.logger.Info("Computed trending articles", map[string]interface{}{
-		"count": len(trendingArticles),
+	cached, cacheKey, found := s.filterCache.get(ctx, s.tenant(ctx), params)
+	if found {
+		return cached, nil
+	}
+
+	articles, err := s.articleRepo.FilterArticles(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheKey != "" {
+		s.filterCache.set(ctx, cacheKey, articles)
+	}
+
+	return articles, nil
+}
+
+// ListArticles returns one page of articles and the cursor of the next page, empty on the last
+func (s *articleService) ListArticles(ctx context.Context, cursor string, limit int) ([]models.Article, string, error) {
+	return s.articleRepo.FindPage(ctx, cursor, limit)
+}
+
+// SearchArticles performs a keyword search without involving the LLM. Terms are split on
+// whitespace with quoted phrases kept intact; an article matches if any term appears in its
+// title or description.
+func (s *articleService) SearchArticles(ctx context.Context, params types.SearchArticlesRequest) ([]models.Article, error) {
+	terms := utils.SplitSearchTerms(params.Q)
+	if len(terms) == 0 {
+		return []models.Article{}, nil
+	}
+
+	return s.articleRepo.SearchByTextFiltered(ctx, terms, repositories.TextSearchFilters{
+		Category: params.Category,
+		Source:   params.Source,
+		Limit:    params.Limit,
 	})
+}
 
-	return trendingArticles, nil
+// ExportSize counts the articles matching params and how many of them an export will contain
+func (s *articleService) ExportSize(ctx context.Context, params types.FilterArticlesRequest) (*ExportSize, error) {
+	total, err := s.articleRepo.CountFilteredArticles(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRows := s.exportCfg.MaxRows
+	return &ExportSize{
+		Total:     total,
+		Rows:      min(total, int64(maxRows)),
+		MaxRows:   maxRows,
+		Truncated: total > int64(maxRows),
+	}, nil
 }
 
-// FilterArticles filters articles based on provided parameters
-func (s *articleService) FilterArticles(params types.FilterArticlesRequest) ([]models.Article, error) {
-	return s.articleRepo.FilterArticles(params)
+// ExportArticles streams up to the configured maximum number of articles matching params to w
+// in the given format and returns how many were written. Articles are read from the database
+// one at a time, so large exports don't have to fit in memory.
+func (s *articleService) ExportArticles(ctx context.Context, params types.FilterArticlesRequest, format string, w io.Writer) (int, error) {
+	articleWriter, err := renderer.NewArticleWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	err = s.articleRepo.StreamFilteredArticles(ctx, params, s.exportCfg.MaxRows, func(article models.Article) error {
+		if err := articleWriter.Write(article); err != nil {
+			return fmt.Errorf("failed to write article: %w", err)
+		}
+		written++
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+
+	if err := articleWriter.Flush(); err != nil {
+		return written, fmt.Errorf("failed to flush export: %w", err)
+	}
+
+	return written, nil
 }
 
-// LoadFromJSON loads articles from a JSON file, enriches them with LLM summaries, and inserts them into the database
-func (s *articleService) LoadFromJSON(filepath string) (*repositories.LoadStats, error) {
+// LoadFromJSON loads articles from a JSON file, enriches them with LLM summaries, and inserts them into the database.
+// With dryRun the articles are only validated and checked for duplicate URLs; nothing is enriched or stored.
+// With detectDuplicates, articles whose title closely matches a stored article's are skipped as well.
+func (s *articleService) LoadFromJSON(ctx context.Context, filepath string, dryRun bool, detectDuplicates bool) (*repositories.LoadStats, error) {
 	s.logger.Info("Starting to load articles from JSON", map[string]interface{}{
 		"filepath": filepath,
 	})
@@ -213,99 +735,146 @@ func (s *articleService) LoadFromJSON(filepath string) (*repositories.LoadStats,
 		})
 		return &repositories.LoadStats{
 			TotalArticles: 0,
+			DryRun:        dryRun,
 		}, nil
 	}
 
 	s.logger.Info("Parsed articles from JSON", map[string]interface{}{
-		"total": len(articles),
+		"total":   len(articles),
+		"dry_run": dryRun,
 	})
 
+	for i := range articles {
+		articles[i].CanonicalURL = s.canonicalURL(articles[i].URL)
+	}
+
+	// Articles with categories the taxonomy rejects fail validation like any other invalid article
+	if err := s.categories.NormalizeArticles(ctx, articles); err != nil {
+		s.logger.Error("Failed to normalize article categories", err, map[string]interface{}{
+			"filepath": filepath,
+		})
+		return nil, fmt.Errorf("failed to normalize categories: %w", err)
+	}
+
+	// A zero threshold skips title matching entirely
+	titleThreshold := 0.0
+	if detectDuplicates {
+		titleThreshold = s.dedupeCfg.TitleThreshold
+	}
+
+	if dryRun {
+		return s.articleRepo.DryRunBulkInsert(ctx, articles, titleThreshold)
+	}
+
 	s.logger.Info("Enriching articles with LLM summaries and embeddings", map[string]interface{}{
 		"total": len(articles),
 	})
 
-	var wg sync.WaitGroup
+	// Assign ids up front so enrichment failures can be attributed to stored articles
+	for i := range articles {
+		if articles[i].ID == "" {
+			articles[i].ID = uuid.New().String()
+		}
+	}
+
 	var mu sync.Mutex
 	completedCount := 0
+	enrichmentFailed := make([]bool, len(articles))
 
-	for i := range articles {
-		wg.Add(2)
+	// Enrichment yields LLM capacity to live queries
+	enrichCtx := withBulkPriority(ctx)
 
-		// Goroutine 1: Generate summary
-		go func(idx int) {
-			defer wg.Done()
-			summary, err := s.llmService.GenerateSummary(articles[idx].Title, articles[idx].Description)
+	// Full text has to be in place before summaries and embeddings are generated from it
+	contentFetchFailures := s.fetchContent(ctx, articles)
+
+	// Each article needs a summary and an embedding, plus sentiment and the country/region when
+	// enabled; task index t runs operation t%ops of article t/ops
+	operations := []string{enrichSummary, enrichEmbedding}
+	if s.enrichCfg.Sentiment {
+		operations = append(operations, enrichSentiment)
+	}
+	if s.geocoder != nil {
+		operations = append(operations, enrichPlace)
+	}
+	ops := len(operations)
+
+	runBounded(len(articles)*ops, s.enrichCfg.Workers, func(task int) {
+		idx := task / ops
+
+		switch operations[task%ops] {
+		case enrichSummary:
+			summary, err := s.llmService.GenerateSummary(enrichCtx, articles[idx].Title, s.enrichmentText(&articles[idx]))
 			if err != nil {
 				s.logger.Warn("Failed to generate summary for article", map[string]interface{}{
 					"index": idx,
 					"title": articles[idx].Title,
 					"error": err.Error(),
 				})
-				mu.Lock()
-				articles[idx].Summary = ""
-				mu.Unlock()
-			} else {
-				mu.Lock()
-				articles[idx].Summary = summary
-				mu.Unlock()
+				summary = ""
 			}
-
-			// Track progress
 			mu.Lock()
-			completedCount++
-			currentCount := completedCount
-			mu.Unlock()
-
-			if currentCount%50 == 0 {
-				s.logger.Info("Enrichment progress", map[string]interface{}{
-					"completed": currentCount,
-					"total":     len(articles) * 2, // 2 operations per article
-				})
+			articles[idx].Summary = summary
+			if err != nil {
+				enrichmentFailed[idx] = true
 			}
-		}(i)
-
-		// Goroutine 2: Generate embedding
-		go func(idx int) {
-			defer wg.Done()
-			embedding, err := s.llmService.GenerateEmbedding(articles[idx].Description)
+			mu.Unlock()
+		case enrichEmbedding:
+			embedding, err := s.llmService.GenerateEmbedding(enrichCtx, embeddingText(articles[idx].Title, s.enrichmentText(&articles[idx])))
 			if err != nil {
 				s.logger.Warn("Failed to generate embedding for article", map[string]interface{}{
 					"index": idx,
 					"title": articles[idx].Title,
 					"error": err.Error(),
 				})
-				mu.Lock()
-				articles[idx].DescriptionVector = nil
-				mu.Unlock()
+				embedding = nil
+			}
+			embeddedAt := s.clock.Now()
+			mu.Lock()
+			articles[idx].DescriptionVector = embedding
+			if err != nil {
+				enrichmentFailed[idx] = true
 			} else {
-				mu.Lock()
-				articles[idx].DescriptionVector = embedding
-				mu.Unlock()
+				articles[idx].EmbeddingModel = s.llmService.EmbeddingModel()
+				articles[idx].EmbeddedAt = &embeddedAt
 			}
-
-			// Track progress
+			mu.Unlock()
+		case enrichSentiment:
+			sentiment, err := s.classifySentiment(enrichCtx, articles[idx].Title, articles[idx].Description)
 			mu.Lock()
-			completedCount++
-			currentCount := completedCount
+			articles[idx].Sentiment = sentiment
+			if err != nil {
+				enrichmentFailed[idx] = true
+			}
 			mu.Unlock()
-
-			if currentCount%50 == 0 {
-				s.logger.Info("Enrichment progress", map[string]interface{}{
-					"completed": currentCount,
-					"total":     len(articles) * 2, // 2 operations per article
-				})
+		case enrichPlace:
+			// A missing country/region does not count as an enrichment failure
+			if place := s.reverseGeocode(enrichCtx, &articles[idx]); place != nil {
+				mu.Lock()
+				articles[idx].Country = place.Country
+				articles[idx].Region = place.Region
+				mu.Unlock()
 			}
-		}(i)
-	}
+		}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
+		// Track progress
+		mu.Lock()
+		completedCount++
+		currentCount := completedCount
+		mu.Unlock()
+
+		if currentCount%50 == 0 {
+			s.logger.Info("Enrichment progress", map[string]interface{}{
+				"completed": currentCount,
+				"total":     len(articles) * ops,
+			})
+		}
+	})
 
 	s.logger.Info("Completed enriching articles with summaries and embeddings", map[string]interface{}{
 		"total": len(articles),
 	})
 
-	stats, err := s.articleRepo.BulkInsert(articles)
+	stats, err := s.articleRepo.BulkInsert(ctx, articles, titleThreshold)
 	if err != nil {
 		s.logger.Error("Failed to bulk insert articles", err, map[string]interface{}{
 			"filepath": filepath,
@@ -313,31 +882,146 @@ func (s *articleService) LoadFromJSON(filepath string) (*repositories.LoadStats,
 		return stats, fmt.Errorf("failed to bulk insert articles: %w", err)
 	}
 
+	if stats.SuccessCount > 0 {
+		s.filterCache.invalidate(ctx)
+		s.webhookService.NotifyArticlesCreated(storedArticles(articles, stats.StoredIDs))
+		s.savedSearches.EvaluateArticles(stats.StoredIDs)
+	}
+
+	for i, failed := range enrichmentFailed {
+		if failed {
+			stats.EnrichmentFailures = append(stats.EnrichmentFailures, articles[i].ID)
+		}
+	}
+	stats.ContentFetchFailures = contentFetchFailures
+
 	s.logger.Info("Completed loading articles from JSON", map[string]interface{}{
-		"filepath":      filepath,
-		"total":         stats.TotalArticles,
-		"success_count": stats.SuccessCount,
-		"error_count":   stats.ErrorCount,
+		"filepath":             filepath,
+		"total":                stats.TotalArticles,
+		"success_count":        stats.SuccessCount,
+		"error_count":          stats.ErrorCount,
+		"duplicates":           len(stats.DuplicateURLs),
+		"suspected_duplicates": len(stats.SuspectedDuplicates),
+		"enrichment_failures":  len(stats.EnrichmentFailures),
+		"content_failures":     stats.ContentFetchFailures,
 	})
 
 	return stats, nil
 }
 
+// fetchContent fetches the page of every article without content and stores the extracted
+// text on it. Failures are logged and counted; their articles are enriched from the
+// description alone.
+func (s *articleService) fetchContent(ctx context.Context, articles []models.Article) int {
+	if s.contentFetcher == nil {
+		return 0
+	}
+
+	var mu sync.Mutex
+	failures := 0
+
+	runBounded(len(articles), s.enrichCfg.Workers, func(idx int) {
+		if articles[idx].Content != "" {
+			return
+		}
+
+		content, err := s.contentFetcher.Fetch(ctx, articles[idx].URL)
+		if err != nil {
+			s.logger.Warn("Failed to fetch article content", map[string]interface{}{
+				"index": idx,
+				"url":   articles[idx].URL,
+				"error": err.Error(),
+			})
+			mu.Lock()
+			failures++
+			mu.Unlock()
+			return
+		}
+
+		mu.Lock()
+		articles[idx].Content = content
+		mu.Unlock()
+	})
+
+	return failures
+}
+
+// enrichmentText returns the text summaries and embeddings are generated from: the
+// description, followed by the article content truncated to the configured length when
+// content was fetched
+func (s *articleService) enrichmentText(article *models.Article) string {
+	if article.Content == "" {
+		return article.Description
+	}
+	return strings.TrimSpace(article.Description + "\n\n" + truncateQuery(article.Content, s.contentCfg.MaxChars))
+}
+
+// storedArticles returns the articles whose ids are in storedIDs, keeping their order
+func storedArticles(articles []models.Article, storedIDs []string) []models.Article {
+	stored := make(map[string]bool, len(storedIDs))
+	for _, id := range storedIDs {
+		stored[id] = true
+	}
+
+	result := make([]models.Article, 0, len(storedIDs))
+	for _, article := range articles {
+		if stored[article.ID] {
+			result = append(result, article)
+		}
+	}
+	return result
+}
+
 // CreateArticle creates a single article in the database
-func (s *articleService) CreateArticle(article *models.Article) error {
+func (s *articleService) CreateArticle(ctx context.Context, article *models.Article) error {
 	s.logger.Info("Creating article", map[string]interface{}{
 		"title": article.Title,
 	})
 
+	article.CanonicalURL = s.canonicalURL(article.URL)
+
+	// Without automatic classification a category is mandatory
+	if len(article.Category) == 0 && !s.enrichCfg.AutoCategorize {
+		var errs types.ValidationErrors
+		errs.Add("category", types.ValidationCodeRequired, "at least one category is required")
+		return errs.Err()
+	}
+
+	// Reject unknown categories before spending LLM calls on the article
+	if len(article.Category) > 0 {
+		categories, rejected, err := s.categories.Normalize(ctx, article.Category)
+		if err != nil {
+			return fmt.Errorf("failed to normalize categories: %w", err)
+		}
+		if len(rejected) > 0 {
+			var errs types.ValidationErrors
+			errs.Add("category", types.ValidationCodeInvalidValue, "categories not in the taxonomy: "+strings.Join(rejected, ", "))
+			return errs.Err()
+		}
+		article.Category = categories
+	}
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
+	// Classify categories if none were provided
+	if len(article.Category) == 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			categories := s.classifyCategories(ctx, article.Title, article.Description)
+			mu.Lock()
+			article.Category = categories
+			mu.Unlock()
+		}()
+	}
+
 	// Generate summary if not provided
 	if article.Summary == "" {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, err := s.llmService.GenerateSummary(article.Title, article.Description)
+			summary, err := s.llmService.GenerateSummary(ctx, article.Title, article.Description)
 			if err != nil {
 				s.logger.Warn("Failed to generate summary for article", map[string]interface{}{
 					"title": article.Title,
@@ -359,7 +1043,7 @@ func (s *articleService) CreateArticle(article *models.Article) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			embedding, err := s.llmService.GenerateEmbedding(article.Description)
+			embedding, err := s.llmService.GenerateEmbedding(ctx, embeddingText(article.Title, article.Description))
 			if err != nil {
 				s.logger.Warn("Failed to generate embedding for article", map[string]interface{}{
 					"title": article.Title,
@@ -369,23 +1053,56 @@ func (s *articleService) CreateArticle(article *models.Article) error {
 				article.DescriptionVector = nil
 				mu.Unlock()
 			} else {
+				embeddedAt := s.clock.Now()
 				mu.Lock()
 				article.DescriptionVector = embedding
+				article.EmbeddingModel = s.llmService.EmbeddingModel()
+				article.EmbeddedAt = &embeddedAt
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Classify sentiment if enabled and not provided
+	if s.enrichCfg.Sentiment && article.Sentiment == nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sentiment, _ := s.classifySentiment(ctx, article.Title, article.Description)
+			mu.Lock()
+			article.Sentiment = sentiment
+			mu.Unlock()
+		}()
+	}
+
+	// Derive the country and region from the coordinates if neither was provided
+	if s.geocoder != nil && article.Country == "" && article.Region == "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if place := s.reverseGeocode(ctx, article); place != nil {
+				mu.Lock()
+				article.Country = place.Country
+				article.Region = place.Region
 				mu.Unlock()
 			}
 		}()
 	}
 
-	// Wait for both goroutines to complete
+	// Wait for the enrichment goroutines to complete
 	wg.Wait()
 
-	if err := s.articleRepo.Insert(article); err != nil {
+	if err := s.articleRepo.Insert(ctx, article); err != nil {
 		s.logger.Error("Failed to create article", err, map[string]interface{}{
 			"title": article.Title,
 		})
 		return fmt.Errorf("failed to create article: %w", err)
 	}
 
+	s.filterCache.invalidate(ctx)
+	s.webhookService.NotifyArticlesCreated([]models.Article{*article})
+	s.savedSearches.EvaluateArticles([]string{article.ID})
+
 	s.logger.Info("Successfully created article", map[string]interface{}{
 		"id":    article.ID,
 		"title": article.Title,
@@ -393,3 +1110,325 @@ func (s *articleService) CreateArticle(article *models.Article) error {
 
 	return nil
 }
+
+// StartBackfill launches an asynchronous job that generates missing summaries and embeddings.
+// Articles are processed in id-ordered pages starting after afterID, so a failed or interrupted
+// job can be resumed from its last_id. maxArticles limits the total processed (0 means no limit).
+// Only the articles of the tenant on ctx are enriched.
+func (s *articleService) StartBackfill(ctx context.Context, afterID string, maxArticles int) models.Job {
+	job := s.jobs.Start("backfill")
+	tenant := s.tenant(ctx)
+
+	s.logger.Info("Starting enrichment backfill", map[string]interface{}{
+		"job_id":       job.ID,
+		"tenant":       tenant,
+		"after_id":     afterID,
+		"max_articles": maxArticles,
+	})
+
+	// The job outlives the request that started it, so it gets its own context carrying only
+	// the tenant
+	go func() {
+		err := s.runBackfill(withBulkPriority(infra.WithTenant(context.Background(), tenant)), job.ID, afterID, maxArticles)
+		if err != nil {
+			s.logger.Error("Enrichment backfill failed", err, map[string]interface{}{
+				"job_id": job.ID,
+			})
+		}
+		s.jobs.Finish(job.ID, err)
+
+		finished, _ := s.jobs.Get(job.ID)
+		s.logger.Info("Enrichment backfill finished", map[string]interface{}{
+			"job_id":    job.ID,
+			"status":    finished.Status,
+			"processed": finished.Processed,
+			"succeeded": finished.Succeeded,
+			"failed":    finished.Failed,
+			"last_id":   finished.LastID,
+		})
+	}()
+
+	return job
+}
+
+// runBackfill pages through articles missing enrichment and fills them in using the bounded worker pool
+func (s *articleService) runBackfill(ctx context.Context, jobID, afterID string, maxArticles int) error {
+	processed := 0
+
+	for maxArticles == 0 || processed < maxArticles {
+		pageSize := s.enrichCfg.BackfillBatchSize
+		if maxArticles > 0 && maxArticles-processed < pageSize {
+			pageSize = maxArticles - processed
+		}
+
+		page, err := s.articleRepo.FindMissingEnrichment(ctx, afterID, pageSize, s.enrichCfg.Sentiment)
+		if err != nil {
+			return fmt.Errorf("failed to fetch articles missing enrichment: %w", err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		var mu sync.Mutex
+		succeeded, failed := 0, 0
+
+		runBounded(len(page), s.enrichCfg.Workers, func(i int) {
+			ok := s.backfillArticle(ctx, page[i])
+
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				succeeded++
+			} else {
+				failed++
+			}
+		})
+
+		afterID = page[len(page)-1].ID
+		processed += len(page)
+
+		// Filter results carry summaries, so each enriched page makes them stale
+		if succeeded > 0 {
+			s.filterCache.invalidate(ctx)
+		}
+
+		s.jobs.Update(jobID, func(job *models.Job) {
+			job.Processed += len(page)
+			job.Succeeded += succeeded
+			job.Failed += failed
+			job.LastID = afterID
+		})
+
+		s.logger.Info("Backfill progress", map[string]interface{}{
+			"job_id":    jobID,
+			"processed": processed,
+			"last_id":   afterID,
+		})
+	}
+
+	return nil
+}
+
+// backfillArticle generates whatever enrichment the article is missing and persists it.
+// It returns false when any generation or the update failed.
+func (s *articleService) backfillArticle(ctx context.Context, article repositories.MissingEnrichment) bool {
+	ok := true
+	var summary string
+	var embedding []float64
+
+	if article.Summary == "" {
+		generated, err := s.llmService.GenerateSummary(ctx, article.Title, article.Description)
+		if err != nil {
+			s.logger.Warn("Failed to generate summary during backfill", map[string]interface{}{
+				"id":    article.ID,
+				"error": err.Error(),
+			})
+			ok = false
+		} else {
+			summary = generated
+		}
+	}
+
+	if !article.HasEmbedding {
+		generated, err := s.llmService.GenerateEmbedding(ctx, embeddingText(article.Title, article.Description))
+		if err != nil {
+			s.logger.Warn("Failed to generate embedding during backfill", map[string]interface{}{
+				"id":    article.ID,
+				"error": err.Error(),
+			})
+			ok = false
+		} else {
+			embedding = generated
+		}
+	}
+
+	var sentiment string
+	if s.enrichCfg.Sentiment && !article.HasSentiment {
+		classified, err := s.classifySentiment(ctx, article.Title, article.Description)
+		if err != nil {
+			ok = false
+		} else {
+			sentiment = *classified
+		}
+	}
+
+	if summary == "" && len(embedding) == 0 && sentiment == "" {
+		return false
+	}
+
+	var embeddingModel string
+	if len(embedding) > 0 {
+		embeddingModel = s.llmService.EmbeddingModel()
+	}
+
+	if err := s.articleRepo.UpdateEnrichment(ctx, article.ID, summary, embedding, embeddingModel, sentiment); err != nil {
+		return false
+	}
+
+	return ok
+}
+
+// classifyCategories asks the LLM to pick categories for an article from the categories
+// table, else the configured taxonomy, else the stored categories. Any failure is logged and
+// falls back to the configured default category, so creating the article never fails over it.
+func (s *articleService) classifyCategories(ctx context.Context, title, description string) []string {
+	fallback := []string{s.enrichCfg.DefaultCategory}
+
+	var allowed []string
+	taxonomy, err := s.categories.List(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to get category taxonomy for classification, using default category", map[string]interface{}{
+			"title":    title,
+			"category": s.enrichCfg.DefaultCategory,
+			"error":    err.Error(),
+		})
+		return fallback
+	}
+	for _, category := range taxonomy {
+		allowed = append(allowed, category.Name)
+	}
+
+	if len(allowed) == 0 {
+		allowed = s.enrichCfg.CategoryTaxonomy
+	}
+	if len(allowed) == 0 {
+		stored, err := s.articleRepo.GetDistinctCategories(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to get categories for classification, using default category", map[string]interface{}{
+				"title":    title,
+				"category": s.enrichCfg.DefaultCategory,
+				"error":    err.Error(),
+			})
+			return fallback
+		}
+		allowed = stored
+	}
+
+	if len(allowed) == 0 {
+		s.logger.Warn("No categories to classify against, using default category", map[string]interface{}{
+			"title":    title,
+			"category": s.enrichCfg.DefaultCategory,
+		})
+		return fallback
+	}
+
+	categories, err := s.llmService.ClassifyCategories(ctx, title, description, allowed)
+	if err != nil {
+		s.logger.Warn("Failed to classify categories for article, using default category", map[string]interface{}{
+			"title":    title,
+			"category": s.enrichCfg.DefaultCategory,
+			"error":    err.Error(),
+		})
+		return fallback
+	}
+
+	return categories
+}
+
+// reverseGeocode returns the country and region containing the article's coordinates, or nil
+// when the article already has them, has no coordinates, or the lookup fails
+func (s *articleService) reverseGeocode(ctx context.Context, article *models.Article) *models.Place {
+	if article.Country != "" || article.Region != "" {
+		return nil
+	}
+	if article.Latitude == 0 && article.Longitude == 0 {
+		return nil
+	}
+
+	place, err := s.geocoder.ReverseGeocode(ctx, article.Latitude, article.Longitude)
+	if err != nil {
+		s.logger.Warn("Failed to reverse geocode article location", map[string]interface{}{
+			"title":     article.Title,
+			"latitude":  article.Latitude,
+			"longitude": article.Longitude,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return place
+}
+
+// classifySentiment asks the LLM for the article's sentiment. Failures are logged and return a
+// nil sentiment so the article is stored unclassified.
+func (s *articleService) classifySentiment(ctx context.Context, title, description string) (*string, error) {
+	sentiment, err := s.llmService.ClassifySentiment(ctx, title, description)
+	if err != nil {
+		s.logger.Warn("Failed to classify sentiment for article", map[string]interface{}{
+			"title": title,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	return &sentiment, nil
+}
+
+// DeleteArticle soft-deletes an article so it disappears from all read paths
+func (s *articleService) DeleteArticle(ctx context.Context, id string) error {
+	s.logger.Info("Deleting article", map[string]interface{}{
+		"id": id,
+	})
+
+	if err := s.articleRepo.SoftDelete(ctx, id); err != nil {
+		return err
+	}
+
+	s.filterCache.invalidate(ctx)
+	return nil
+}
+
+// RestoreArticle reverts a soft delete
+func (s *articleService) RestoreArticle(ctx context.Context, id string) error {
+	s.logger.Info("Restoring article", map[string]interface{}{
+		"id": id,
+	})
+
+	if err := s.articleRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	s.filterCache.invalidate(ctx)
+	return nil
+}
+
+// PurgeArticle permanently removes an article and its user events
+func (s *articleService) PurgeArticle(ctx context.Context, id string) error {
+	s.logger.Warn("Purging article permanently", map[string]interface{}{
+		"id": id,
+	})
+
+	if err := s.articleRepo.Purge(ctx, id); err != nil {
+		return err
+	}
+
+	s.filterCache.invalidate(ctx)
+	return nil
+}
+
+// embeddingText composes the text an article is embedded from. The title is included since it
+// often carries the key entities that a short description leaves out.
+func embeddingText(title, description string) string {
+	return strings.TrimSpace(title + "\n" + description)
+}
+
+// canonicalURL returns the canonical form of rawURL that duplicate detection keys on. A URL
+// that cannot be canonicalized yields "" and its article is stored without duplicate protection.
+func (s *articleService) canonicalURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+
+	canonical, err := utils.CanonicalizeURL(rawURL)
+	if err != nil {
+		s.logger.Warn("Failed to canonicalize article URL", map[string]interface{}{
+			"url":   rawURL,
+			"error": err.Error(),
+		})
+		return ""
+	}
+	return canonical
+}
+
+// tenant returns the tenant of ctx, whose cached results are kept apart from other tenants'
+func (s *articleService) tenant(ctx context.Context) string {
+	return infra.TenantFromContext(ctx, s.defaultTenant)
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// newMockOpenAI serves chat completions answering chatContent and embeddings like the OpenAI
+// API, answering 500 on the paths listed in failing
+func newMockOpenAI(t *testing.T, chatContent string, failing ...string) *httptest.Server {
+	t.Helper()
+
+	fail := make(map[string]bool, len(failing))
+	for _, path := range failing {
+		fail[path] = true
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail[r.URL.Path] {
+			http.Error(w, `{"error":{"message":"internal error"}}`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/chat/completions":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": chatContent}}},
+				"usage":   map[string]int{"prompt_tokens": 10, "completion_tokens": 5},
+			})
+		case "/embeddings":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data":  []map[string]interface{}{{"embedding": make([]float64, 1536)}},
+				"usage": map[string]int{"prompt_tokens": 10},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestLLMService returns an LLM service calling the OpenAI API at url
+func newTestLLMService(url string) LLMService {
+	return newTestLLMServiceWith(url, nil, infra.NewRecordingLogger())
+}
+
+// newTestLLMServiceWith is newTestLLMService resolving places with geocoder and logging to
+// logger
+func newTestLLMServiceWith(url string, geocoder GeocodingService, logger infra.Logger) LLMService {
+	cfg := &infra.LLMConfig{
+		ChatProvider:            LLMProviderOpenAI,
+		APIKey:                  "test-key",
+		APIURL:                  url,
+		EmbeddingProvider:       LLMProviderOpenAI,
+		EmbeddingAPIKey:         "test-key",
+		EmbeddingAPIURL:         url,
+		EmbeddingModel:          "text-embedding-3-small",
+		BreakerFailureThreshold: 100,
+		BreakerOpenDuration:     time.Minute,
+		MaxInflight:             4,
+		MaxInflightBulk:         4,
+		MaxWait:                 time.Second,
+	}
+	return NewLLMService(cfg, geocoder, nil, NewLLMAuditService(nil, cfg, logger), infra.SystemClock{}, logger)
+}
+
+// loadArticleRepo stores every bulk-inserted article and keeps them for inspection
+type loadArticleRepo struct {
+	repositories.ArticleRepository
+	inserted []models.Article
+}
+
+func (r *loadArticleRepo) BulkInsert(ctx context.Context, articles []models.Article, titleThreshold float64) (*repositories.LoadStats, error) {
+	r.inserted = append(r.inserted, articles...)
+	stats := &repositories.LoadStats{TotalArticles: len(articles), SuccessCount: len(articles)}
+	for _, article := range articles {
+		stats.StoredIDs = append(stats.StoredIDs, article.ID)
+	}
+	return stats, nil
+}
+
+// passthroughCategories accepts every category as given
+type passthroughCategories struct {
+	CategoryService
+}
+
+func (passthroughCategories) NormalizeArticles(ctx context.Context, articles []models.Article) error {
+	return nil
+}
+
+// noopWebhooks drops article notifications
+type noopWebhooks struct {
+	WebhookService
+}
+
+func (noopWebhooks) NotifyArticlesCreated(articles []models.Article) {}
+
+// noopSavedSearches skips saved search evaluation
+type noopSavedSearches struct {
+	SavedSearchService
+}
+
+func (noopSavedSearches) EvaluateArticles(ids []string) {}
+
+// newLoadTestService returns an article service loading through repo, enriching with llm and
+// logging to logger
+func newLoadTestService(llm LLMService, repo repositories.ArticleRepository, logger infra.Logger) ArticleService {
+	return NewArticleService(
+		llm, nil, nil, noopWebhooks{}, noopSavedSearches{}, nil, nil, passthroughCategories{}, nil, nil,
+		repo, nil, nil, NewJobTracker(time.Hour, infra.SystemClock{}),
+		&infra.EnrichConfig{Workers: 2}, &infra.ContentConfig{}, &infra.ExportConfig{}, &infra.QueryConfig{},
+		&infra.DedupeConfig{}, &infra.TrendingConfig{}, infra.DefaultTenant,
+		nil, 0, 0, 0, infra.SystemClock{}, logger,
+	)
+}
+
+// writeArticlesFile writes articles as a load file and returns its path
+func writeArticlesFile(t *testing.T, articles []models.Article) string {
+	t.Helper()
+
+	data, err := json.Marshal(articles)
+	if err != nil {
+		t.Fatalf("failed to encode articles: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "articles.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write articles: %v", err)
+	}
+	return path
+}
+
+// testSummary is the summary the mock OpenAI API generates
+const testSummary = "A generated summary."
+
+func TestLoadFromJSONRecordsEnrichmentFailures(t *testing.T) {
+	tests := []struct {
+		name          string
+		failing       []string
+		wantFailure   bool
+		wantSummary   string
+		wantEmbedding bool
+	}{
+		{name: "summary API error", failing: []string{"/chat/completions"}, wantFailure: true, wantEmbedding: true},
+		{name: "embedding API error", failing: []string{"/embeddings"}, wantFailure: true, wantSummary: testSummary},
+		{name: "healthy API", wantSummary: testSummary, wantEmbedding: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockOpenAI(t, testSummary, tt.failing...)
+			repo := &loadArticleRepo{}
+			svc := newLoadTestService(newTestLLMService(server.URL), repo, infra.NewRecordingLogger())
+
+			path := writeArticlesFile(t, []models.Article{{
+				Title:       "Storm hits the coast",
+				Description: "A storm made landfall overnight.",
+				URL:         "https://example.com/storm",
+				Category:    []string{"world"},
+			}})
+
+			stats, err := svc.LoadFromJSON(context.Background(), path, false, false)
+			if err != nil {
+				t.Fatalf("LoadFromJSON failed: %v", err)
+			}
+			if len(repo.inserted) != 1 {
+				t.Fatalf("inserted %d articles, want 1", len(repo.inserted))
+			}
+			article := repo.inserted[0]
+
+			if tt.wantFailure {
+				if len(stats.EnrichmentFailures) != 1 || stats.EnrichmentFailures[0] != article.ID {
+					t.Errorf("enrichment_failures = %v, want [%s]", stats.EnrichmentFailures, article.ID)
+				}
+			} else if len(stats.EnrichmentFailures) != 0 {
+				t.Errorf("enrichment_failures = %v, want none", stats.EnrichmentFailures)
+			}
+			if article.Summary != tt.wantSummary {
+				t.Errorf("summary = %q, want %q", article.Summary, tt.wantSummary)
+			}
+			if got := len(article.DescriptionVector) > 0; got != tt.wantEmbedding {
+				t.Errorf("embedded = %v, want %v", got, tt.wantEmbedding)
+			}
+		})
+	}
+}
+
+func TestGenerateSummaryReturnsAPIErrors(t *testing.T) {
+	server := newMockOpenAI(t, testSummary, "/chat/completions")
+	llm := newTestLLMService(server.URL)
+
+	summary, err := llm.GenerateSummary(context.Background(), "Storm hits the coast", "A storm made landfall overnight.")
+	if !errors.Is(err, ErrLLMUnavailable) {
+		t.Fatalf("GenerateSummary error = %v, want ErrLLMUnavailable", err)
+	}
+	if summary != "" {
+		t.Errorf("summary = %q, want empty", summary)
+	}
+}
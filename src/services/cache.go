@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// cacheFlushScanCount is the COUNT hint for each SCAN batch while flushing
+const cacheFlushScanCount = 500
+
+// cacheScopePatterns maps each flush scope to the SCAN patterns of the keys it covers. Feeds
+// list trending or filtered articles, so both scopes clear them. Query analyses are matched
+// against the stored source and category lists. Embeddings are not cached yet, so that scope
+// is empty.
+var cacheScopePatterns = map[string][]string{
+	models.CacheScopeTrending:   {infra.TrendingCachePrefix + "*", infra.FeedCachePrefix + "*"},
+	models.CacheScopeFilters:    {infra.FilterCachePrefix + "*", infra.FeedCachePrefix + "*"},
+	models.CacheScopeSources:    {infra.QueryAnalysisCachePrefix + "*"},
+	models.CacheScopeEmbeddings: {},
+}
+
+// CacheService clears cached data so direct database fixes show up before the caches expire
+type CacheService interface {
+	// Flush deletes the keys of scope (one of models.CacheScopes, or models.CacheScopeAll) and
+	// returns the number of keys deleted per scope
+	Flush(ctx context.Context, scope string) (map[string]int, error)
+}
+
+// cacheService implements CacheService
+type cacheService struct {
+	redisClient *redis.Client
+	log         infra.Logger
+}
+
+// NewCacheService creates a new instance of CacheService
+func NewCacheService(redisClient *redis.Client, logger infra.Logger) CacheService {
+	return &cacheService{
+		redisClient: redisClient,
+		log:         logger,
+	}
+}
+
+// Flush implements CacheService. Keys are found with SCAN, never KEYS, so a large keyspace
+// does not block Redis.
+func (s *cacheService) Flush(ctx context.Context, scope string) (map[string]int, error) {
+	scopes := []string{scope}
+	if scope == models.CacheScopeAll {
+		scopes = models.CacheScopes
+	}
+
+	deleted := make(map[string]int, len(scopes))
+	for _, name := range scopes {
+		patterns, ok := cacheScopePatterns[name]
+		if !ok {
+			return deleted, fmt.Errorf("unknown cache scope %q", name)
+		}
+
+		// Results computed before the flush may still be written afterwards; bumping the
+		// generation first files them under a generation nobody reads
+		if name == models.CacheScopeFilters {
+			if err := s.redisClient.Incr(ctx, infra.FilterCacheGenerationKey).Err(); err != nil {
+				return deleted, fmt.Errorf("failed to bump filter cache generation: %w", err)
+			}
+		}
+
+		count := 0
+		for _, pattern := range patterns {
+			n, err := s.deleteMatching(ctx, pattern)
+			count += n
+			if err != nil {
+				deleted[name] = count
+				return deleted, err
+			}
+		}
+		deleted[name] = count
+	}
+
+	s.log.Info("Flushed caches", map[string]interface{}{
+		"scope":   scope,
+		"deleted": deleted,
+	})
+
+	return deleted, nil
+}
+
+// deleteMatching deletes every key matching pattern except the filter cache generation
+// counter, batch by batch as SCAN returns them, and returns the number deleted
+func (s *cacheService) deleteMatching(ctx context.Context, pattern string) (int, error) {
+	deleted := 0
+	var cursor uint64
+	for {
+		keys, next, err := s.redisClient.Scan(ctx, cursor, pattern, cacheFlushScanCount).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to scan %s: %w", pattern, err)
+		}
+
+		batch := keys[:0]
+		for _, key := range keys {
+			if key != infra.FilterCacheGenerationKey {
+				batch = append(batch, key)
+			}
+		}
+
+		if len(batch) > 0 {
+			n, err := s.redisClient.Del(ctx, batch...).Result()
+			deleted += int(n)
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete keys matching %s: %w", pattern, err)
+			}
+		}
+
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
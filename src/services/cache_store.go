@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"news-inshorts/src/infra"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheStore is a best-effort key-value cache. Callers treat every error as a miss and carry
+// on with freshly computed results.
+type CacheStore interface {
+	// Get returns the value stored under key, with found false on a miss
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// IncrMember adds delta to the score of member in the sorted set under key, which
+	// expires ttl after it is created
+	IncrMember(ctx context.Context, key, member string, delta float64, ttl time.Duration) error
+	// TopMembers returns up to n members of the sorted sets under keys with the highest summed
+	// scores, highest first
+	TopMembers(ctx context.Context, keys []string, n int) ([]string, error)
+}
+
+// NewCacheStore returns a Redis-backed CacheStore, or one that caches nothing when
+// redisClient is nil. Each call is bounded by timeout, and after failureThreshold
+// consecutive failures the store stops calling Redis for cooldown, reporting misses
+// instead of adding a timeout to every request while Redis is down.
+func NewCacheStore(name string, redisClient *redis.Client, timeout time.Duration, failureThreshold int, cooldown time.Duration, metric string, logger infra.Logger) CacheStore {
+	if redisClient == nil {
+		logger.Warn("Redis is not configured, cache disabled", map[string]interface{}{
+			"cache": name,
+		})
+		return noopCacheStore{}
+	}
+
+	return &redisCacheStore{
+		client:  redisClient,
+		timeout: timeout,
+		breaker: newCircuitBreaker(name, failureThreshold, cooldown, metric, logger),
+	}
+}
+
+// redisCacheStore implements CacheStore on Redis
+type redisCacheStore struct {
+	client  *redis.Client
+	timeout time.Duration
+	breaker *circuitBreaker
+}
+
+// Get implements CacheStore
+func (s *redisCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := s.call(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = s.client.Get(ctx, key).Bytes()
+		return err
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements CacheStore
+func (s *redisCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.call(ctx, func(ctx context.Context) error {
+		return s.client.Set(ctx, key, value, ttl).Err()
+	})
+}
+
+// Delete implements CacheStore
+func (s *redisCacheStore) Delete(ctx context.Context, key string) error {
+	return s.call(ctx, func(ctx context.Context) error {
+		return s.client.Del(ctx, key).Err()
+	})
+}
+
+// IncrMember implements CacheStore
+func (s *redisCacheStore) IncrMember(ctx context.Context, key, member string, delta float64, ttl time.Duration) error {
+	return s.call(ctx, func(ctx context.Context) error {
+		pipe := s.client.Pipeline()
+		pipe.ZIncrBy(ctx, key, delta, member)
+		pipe.ExpireNX(ctx, key, ttl)
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+}
+
+// TopMembers implements CacheStore
+func (s *redisCacheStore) TopMembers(ctx context.Context, keys []string, n int) ([]string, error) {
+	var members []string
+	err := s.call(ctx, func(ctx context.Context) error {
+		scores, err := s.client.ZUnionWithScores(ctx, redis.ZStore{Keys: keys, Aggregate: "SUM"}).Result()
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(scores, func(i, j int) bool {
+			return scores[i].Score > scores[j].Score
+		})
+		for _, score := range scores[:min(n, len(scores))] {
+			if member, ok := score.Member.(string); ok {
+				members = append(members, member)
+			}
+		}
+		return nil
+	})
+	return members, err
+}
+
+// call runs fn through the circuit breaker with the store's timeout. A miss counts as a
+// success; the caller's own cancellation says nothing about Redis and is ignored.
+func (s *redisCacheStore) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := s.breaker.allow(); err != nil {
+		return err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	err := fn(callCtx)
+	switch {
+	case err == nil || errors.Is(err, redis.Nil):
+		s.breaker.done(callSucceeded)
+	case ctx.Err() != nil:
+		s.breaker.done(callIgnored)
+	default:
+		s.breaker.done(callFailed)
+	}
+	return err
+}
+
+// noopCacheStore implements CacheStore without storing anything
+type noopCacheStore struct{}
+
+// Get implements CacheStore
+func (noopCacheStore) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+// Set implements CacheStore
+func (noopCacheStore) Set(context.Context, string, []byte, time.Duration) error {
+	return nil
+}
+
+// Delete implements CacheStore
+func (noopCacheStore) Delete(context.Context, string) error {
+	return nil
+}
+
+// IncrMember implements CacheStore
+func (noopCacheStore) IncrMember(context.Context, string, string, float64, time.Duration) error {
+	return nil
+}
+
+// TopMembers implements CacheStore
+func (noopCacheStore) TopMembers(context.Context, []string, int) ([]string, error) {
+	return nil, nil
+}
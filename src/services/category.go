@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CategoryService manages the canonical category taxonomy and maps incoming categories
+// through it
+type CategoryService interface {
+	List(ctx context.Context) ([]models.Category, error)
+	Create(ctx context.Context, name string, aliases []string) (*models.Category, error)
+	// Rename renames the category and rewrites the stored articles carrying the old name,
+	// returning the category and how many articles were rewritten
+	Rename(ctx context.Context, name, newName string) (*models.Category, int64, error)
+	// Merge folds the sources into target and rewrites the stored articles carrying a source,
+	// returning target and how many articles were rewritten
+	Merge(ctx context.Context, sources []string, target string) (*models.Category, int64, error)
+	// Normalize maps categories through the taxonomy, returning the categories to store and
+	// those rejected under the "reject" policy
+	Normalize(ctx context.Context, categories []string) (normalized []string, rejected []string, err error)
+	// NormalizeArticles normalizes the categories of every article in place, recording the
+	// rejected ones in RejectedCategories
+	NormalizeArticles(ctx context.Context, articles []models.Article) error
+}
+
+// categoryService implements CategoryService
+type categoryService struct {
+	repo        repositories.CategoryRepository
+	articleRepo repositories.ArticleRepository
+	cfg         *infra.EnrichConfig
+	filterCache *filterCache
+	log         infra.Logger
+}
+
+// NewCategoryService creates a new instance of CategoryService. Renames and merges change
+// which articles category filters match, so they invalidate the filter result cache; query
+// analyses are keyed by the stored category list and need no invalidation.
+func NewCategoryService(repo repositories.CategoryRepository, articleRepo repositories.ArticleRepository, cfg *infra.EnrichConfig, redisClient *redis.Client, filterCacheTTL time.Duration, logger infra.Logger) CategoryService {
+	return &categoryService{
+		repo:        repo,
+		articleRepo: articleRepo,
+		cfg:         cfg,
+		filterCache: newFilterCache(redisClient, filterCacheTTL, logger),
+		log:         logger,
+	}
+}
+
+// List returns every category
+func (s *categoryService) List(ctx context.Context) ([]models.Category, error) {
+	return s.repo.List(ctx)
+}
+
+// Create adds a category, or returns repositories.ErrCategoryExists
+func (s *categoryService) Create(ctx context.Context, name string, aliases []string) (*models.Category, error) {
+	return s.repo.Create(ctx, name, aliases)
+}
+
+// Rename renames the category, or returns repositories.ErrCategoryNotFound or
+// repositories.ErrCategoryExists
+func (s *categoryService) Rename(ctx context.Context, name, newName string) (*models.Category, int64, error) {
+	renamed, err := s.repo.Rename(ctx, name, newName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rewritten, err := s.rewriteArticles(ctx, renamed)
+	return renamed, rewritten, err
+}
+
+// Merge folds the sources into target, or returns repositories.ErrCategoryNotFound
+func (s *categoryService) Merge(ctx context.Context, sources []string, target string) (*models.Category, int64, error) {
+	merged, err := s.repo.Merge(ctx, sources, target)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rewritten, err := s.rewriteArticles(ctx, merged)
+	return merged, rewritten, err
+}
+
+// rewriteArticles replaces every stored category matching the name or an alias of category
+// with its name, in batches of CategoryMergeBatchSize articles. The taxonomy is already
+// updated, so a failed rewrite is finished by repeating the rename or merge.
+func (s *categoryService) rewriteArticles(ctx context.Context, category *models.Category) (int64, error) {
+	names := append([]string{category.Name}, category.Aliases...)
+
+	var total int64
+	for {
+		rewritten, err := s.articleRepo.ReplaceCategories(ctx, names, category.Name, s.cfg.CategoryMergeBatchSize)
+		total += rewritten
+		if err != nil {
+			if total > 0 {
+				s.filterCache.invalidate(ctx)
+			}
+			return total, err
+		}
+
+		s.log.Info("Rewrote article category batch", map[string]interface{}{
+			"category":  category.Name,
+			"batch":     rewritten,
+			"rewritten": total,
+		})
+
+		if rewritten < int64(s.cfg.CategoryMergeBatchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.filterCache.invalidate(ctx)
+	}
+	return total, nil
+}
+
+// Normalize maps categories through the taxonomy
+func (s *categoryService) Normalize(ctx context.Context, categories []string) ([]string, []string, error) {
+	canonical, err := s.canonicalNames(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	normalized, rejected := s.normalize(canonical, categories)
+	return normalized, rejected, nil
+}
+
+// NormalizeArticles maps the categories of every article through the taxonomy, which is
+// read once for all of them
+func (s *categoryService) NormalizeArticles(ctx context.Context, articles []models.Article) error {
+	canonical, err := s.canonicalNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range articles {
+		articles[i].Category, articles[i].RejectedCategories = s.normalize(canonical, articles[i].Category)
+	}
+	return nil
+}
+
+// canonicalNames maps every lowercased category name and alias to its category name. It is
+// nil while the taxonomy is empty.
+func (s *categoryService) canonicalNames(ctx context.Context) (map[string]string, error) {
+	categories, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, nil
+	}
+
+	canonical := make(map[string]string)
+	for _, category := range categories {
+		canonical[strings.ToLower(category.Name)] = category.Name
+		for _, alias := range category.Aliases {
+			canonical[strings.ToLower(alias)] = category.Name
+		}
+	}
+	return canonical, nil
+}
+
+// normalize replaces each category by its canonical name and applies UnknownCategoryPolicy to
+// the others, dropping duplicates. Without a taxonomy categories are returned unchanged.
+func (s *categoryService) normalize(canonical map[string]string, categories []string) ([]string, []string) {
+	if canonical == nil {
+		return categories, nil
+	}
+
+	normalized := make([]string, 0, len(categories))
+	var rejected []string
+	for _, category := range categories {
+		name, known := canonical[strings.ToLower(strings.TrimSpace(category))]
+		if !known {
+			switch s.cfg.UnknownCategoryPolicy {
+			case models.UnknownCategoryReject:
+				rejected = append(rejected, category)
+				continue
+			case models.UnknownCategoryBucket:
+				name = s.cfg.DefaultCategory
+				if bucket, ok := canonical[strings.ToLower(name)]; ok {
+					name = bucket
+				}
+			default:
+				name = category
+			}
+		}
+
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+
+	return normalized, rejected
+}
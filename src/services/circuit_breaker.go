@@ -0,0 +1,144 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"news-inshorts/src/infra"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// ErrCircuitOpen is returned without contacting the dependency while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// callOutcome classifies a finished call for the circuit breaker
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	// callIgnored is for outcomes that say nothing about the dependency's health, such as
+	// the caller giving up or the request being rejected as invalid
+	callIgnored
+)
+
+// circuitStateGauge maps states to the values published in metrics
+var circuitStateGauge = map[string]int64{
+	CircuitClosed:   0,
+	CircuitHalfOpen: 1,
+	CircuitOpen:     2,
+}
+
+// circuitBreaker stops calls to a failing dependency. After failureThreshold consecutive
+// failures it opens and rejects calls for openDuration; then it lets a single probe through
+// (half-open) and closes again if the probe succeeds, or reopens if it fails.
+type circuitBreaker struct {
+	name             string
+	failureThreshold int
+	openDuration     time.Duration
+	metric           string
+	log              infra.Logger
+
+	mu            sync.Mutex
+	state         string
+	failures      int
+	openedAt      time.Time
+	probeInFlight bool
+}
+
+// newCircuitBreaker creates a closed circuit breaker whose state is published under metric
+func newCircuitBreaker(name string, failureThreshold int, openDuration time.Duration, metric string, logger infra.Logger) *circuitBreaker {
+	infra.SetGauge(metric, circuitStateGauge[CircuitClosed])
+
+	return &circuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+		metric:           metric,
+		log:              logger,
+		state:            CircuitClosed,
+	}
+}
+
+// allow reports whether a call may proceed, returning ErrCircuitOpen if not. Every allowed
+// call must be followed by exactly one call to done.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.openDuration {
+			return ErrCircuitOpen
+		}
+		cb.setState(CircuitHalfOpen)
+		cb.probeInFlight = true
+		return nil
+	case CircuitHalfOpen:
+		if cb.probeInFlight {
+			return ErrCircuitOpen
+		}
+		cb.probeInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// done records the outcome of an allowed call
+func (cb *circuitBreaker) done(outcome callOutcome) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitHalfOpen {
+		cb.probeInFlight = false
+	}
+
+	switch outcome {
+	case callIgnored:
+		return
+	case callSucceeded:
+		cb.failures = 0
+		if cb.state != CircuitClosed {
+			cb.setState(CircuitClosed)
+			cb.log.Info("Circuit breaker closed", map[string]interface{}{
+				"dependency": cb.name,
+			})
+		}
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.failureThreshold {
+		if cb.state != CircuitOpen {
+			cb.log.Warn("Circuit breaker opened", map[string]interface{}{
+				"dependency":           cb.name,
+				"consecutive_failures": cb.failures,
+				"open_duration":        cb.openDuration.String(),
+			})
+		}
+		cb.setState(CircuitOpen)
+		cb.openedAt = time.Now()
+	}
+}
+
+// State returns the current state. An open breaker whose open duration has elapsed still
+// reports open until the next call probes the dependency.
+func (cb *circuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// setState changes the state and publishes it; the caller must hold mu
+func (cb *circuitBreaker) setState(state string) {
+	cb.state = state
+	infra.SetGauge(cb.metric, circuitStateGauge[state])
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// ClientLocationService approximates the location of clients that send no coordinates
+type ClientLocationService interface {
+	// Locate returns the location of ip from the GeoIP database, or the configured default
+	// location when the database is not configured or has no match, along with the source used
+	// (models.LocationSourceIP or models.LocationSourceDefault)
+	Locate(ip string) (models.Location, string)
+}
+
+// clientLocationService implements ClientLocationService with a MaxMind City database
+type clientLocationService struct {
+	reader   *geoip2.Reader
+	fallback models.Location
+	log      infra.Logger
+}
+
+// NewClientLocationService creates a new instance of ClientLocationService. A missing or
+// unreadable database is logged and turns IP lookups off rather than failing startup.
+func NewClientLocationService(cfg *infra.GeoIPConfig, logger infra.Logger) ClientLocationService {
+	s := &clientLocationService{
+		fallback: models.Location{
+			Latitude:  cfg.DefaultLatitude,
+			Longitude: cfg.DefaultLongitude,
+		},
+		log: logger,
+	}
+
+	if cfg.DatabasePath == "" {
+		return s
+	}
+
+	reader, err := geoip2.Open(cfg.DatabasePath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		s.log.Warn("GeoIP database not found; IP geolocation is disabled", map[string]interface{}{
+			"path": cfg.DatabasePath,
+		})
+	case err != nil:
+		s.log.Error("Failed to open GeoIP database; IP geolocation is disabled", err, map[string]interface{}{
+			"path": cfg.DatabasePath,
+		})
+	default:
+		s.reader = reader
+		s.log.Info("GeoIP database loaded", map[string]interface{}{
+			"path":  cfg.DatabasePath,
+			"type":  reader.Metadata().DatabaseType,
+			"build": reader.Metadata().BuildEpoch,
+		})
+	}
+
+	return s
+}
+
+// Locate implements ClientLocationService
+func (s *clientLocationService) Locate(ip string) (models.Location, string) {
+	if location, ok := s.lookup(ip); ok {
+		return location, models.LocationSourceIP
+	}
+	return s.fallback, models.LocationSourceDefault
+}
+
+// lookup resolves ip in the GeoIP database. Private and loopback addresses are never in it.
+func (s *clientLocationService) lookup(ip string) (models.Location, bool) {
+	if s.reader == nil {
+		return models.Location{}, false
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() {
+		return models.Location{}, false
+	}
+
+	record, err := s.reader.City(addr)
+	if err != nil {
+		s.log.Warn("GeoIP lookup failed", map[string]interface{}{
+			"ip":    ip,
+			"error": err.Error(),
+		})
+		return models.Location{}, false
+	}
+
+	// Addresses the database does not know come back with a zero location
+	if record.Location.AccuracyRadius == 0 && record.Location.Latitude == 0 && record.Location.Longitude == 0 {
+		return models.Location{}, false
+	}
+
+	return models.Location{
+		Latitude:  record.Location.Latitude,
+		Longitude: record.Location.Longitude,
+	}, true
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrClusteringRunning is returned when a clustering run is requested while another is in progress
+var ErrClusteringRunning = errors.New("clustering run already in progress")
+
+// ClusteringService groups recent articles into story clusters by embedding similarity
+type ClusteringService interface {
+	// Run assigns the articles of the clustering window that are in no cluster yet, and
+	// deletes the clusters that left the window
+	Run(ctx context.Context) (models.ClusterRun, error)
+	// List returns up to limit clusters with at least minMembers members published since the
+	// given time, the start of the clustering window when zero, largest first, each with its
+	// most relevant member
+	List(ctx context.Context, since time.Time, minMembers, limit int) ([]models.StoryCluster, error)
+}
+
+// clusteringService implements ClusteringService
+type clusteringService struct {
+	clusterRepo repositories.ClusterRepository
+	articleRepo repositories.ArticleRepository
+	llmService  LLMService
+	cfg         *infra.ClusterConfig
+	clock       infra.Clock
+	log         infra.Logger
+
+	running atomic.Bool
+}
+
+// NewClusteringService creates a new instance of ClusteringService. Only articles embedded
+// with the LLM service's current embedding model are clustered, since vectors of different
+// models cannot be compared.
+func NewClusteringService(
+	clusterRepo repositories.ClusterRepository,
+	articleRepo repositories.ArticleRepository,
+	llmService LLMService,
+	cfg *infra.ClusterConfig,
+	clock infra.Clock,
+	logger infra.Logger,
+) ClusteringService {
+	return &clusteringService{
+		clusterRepo: clusterRepo,
+		articleRepo: articleRepo,
+		llmService:  llmService,
+		cfg:         cfg,
+		clock:       clock,
+		log:         logger,
+	}
+}
+
+// Run clusters new articles incrementally: articles that already have a cluster are never
+// revisited, and each run only compares the new ones with the clusters still in the window
+func (s *clusteringService) Run(ctx context.Context) (models.ClusterRun, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return models.ClusterRun{}, ErrClusteringRunning
+	}
+	defer s.running.Store(false)
+
+	run := models.ClusterRun{StartedAt: s.clock.Now()}
+	cutoff := run.StartedAt.Add(-s.cfg.Window)
+	embeddingModel := s.llmService.EmbeddingModel()
+
+	if err := s.cluster(ctx, &run, cutoff, embeddingModel); err != nil {
+		run.Error = err.Error()
+		s.log.Error("Story clustering run failed", err, map[string]interface{}{
+			"clustered": run.Clustered,
+		})
+		return run, err
+	}
+
+	finishedAt := s.clock.Now()
+	run.FinishedAt = &finishedAt
+
+	infra.IncrCounter(infra.MetricClusterArticlesAssigned, int64(run.Clustered))
+	infra.SetGauge(infra.MetricClusterLastRunUnix, finishedAt.Unix())
+
+	s.log.Info("Completed story clustering run", map[string]interface{}{
+		"clustered":    run.Clustered,
+		"new_clusters": run.NewClusters,
+		"pruned":       run.Pruned,
+		"duration":     finishedAt.Sub(run.StartedAt).String(),
+	})
+
+	return run, nil
+}
+
+// cluster runs the steps of a clustering run, recording their outcome in run
+func (s *clusteringService) cluster(ctx context.Context, run *models.ClusterRun, cutoff time.Time, embeddingModel string) error {
+	pruned, err := s.clusterRepo.Prune(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	run.Pruned = pruned
+
+	candidates, err := s.clusterRepo.Unclustered(ctx, cutoff, embeddingModel, s.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	clusters, err := s.clusterRepo.Active(ctx, cutoff, embeddingModel)
+	if err != nil {
+		return err
+	}
+
+	changed, assignments, created := assignClusters(clusters, candidates, s.cfg.Similarity, embeddingModel)
+	if err := s.clusterRepo.Save(ctx, changed, assignments); err != nil {
+		return err
+	}
+
+	run.Clustered = len(assignments)
+	run.NewClusters = created
+	return nil
+}
+
+// List returns the largest clusters of the period with their representative articles
+func (s *clusteringService) List(ctx context.Context, since time.Time, minMembers, limit int) ([]models.StoryCluster, error) {
+	if since.IsZero() {
+		since = s.clock.Now().Add(-s.cfg.Window)
+	}
+
+	summaries, err := s.clusterRepo.List(ctx, since, minMembers, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return []models.StoryCluster{}, nil
+	}
+
+	ids := make([]string, len(summaries))
+	for i, summary := range summaries {
+		ids[i] = summary.RepresentativeID
+	}
+	articles, err := s.articleRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up representative articles: %w", err)
+	}
+	byID := make(map[string]models.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	clusters := make([]models.StoryCluster, 0, len(summaries))
+	for _, summary := range summaries {
+		// The representative may have been deleted since the clusters were read
+		article, ok := byID[summary.RepresentativeID]
+		if !ok {
+			continue
+		}
+		clusters = append(clusters, models.StoryCluster{
+			ID:             summary.ID,
+			MemberCount:    summary.MemberCount,
+			LatestAt:       summary.LatestAt,
+			Representative: article,
+		})
+	}
+	return clusters, nil
+}
+
+// assignClusters clusters candidates greedily, oldest first: each joins the cluster whose
+// centroid it is most similar to when that similarity reaches threshold, and otherwise starts
+// a new cluster. A centroid is the mean embedding of its members and moves as members join,
+// so later candidates are compared with the clusters as they grew. It returns the clusters
+// that were created or changed, the assignments and the number of clusters created.
+func assignClusters(clusters []repositories.ClusterCentroid, candidates []repositories.ClusterCandidate, threshold float64, embeddingModel string) ([]repositories.ClusterCentroid, []repositories.ClusterAssignment, int) {
+	changed := make(map[int]bool)
+	assignments := make([]repositories.ClusterAssignment, 0, len(candidates))
+	created := 0
+
+	for _, candidate := range candidates {
+		best, bestSimilarity := -1, threshold
+		for i := range clusters {
+			if similarity := cosineSimilarity(candidate.Embedding, clusters[i].Centroid); similarity >= bestSimilarity {
+				best, bestSimilarity = i, similarity
+			}
+		}
+
+		if best < 0 {
+			clusters = append(clusters, repositories.ClusterCentroid{
+				ID:             uuid.New().String(),
+				Centroid:       append([]float64(nil), candidate.Embedding...),
+				EmbeddingModel: embeddingModel,
+				MemberCount:    1,
+				LatestAt:       candidate.PublicationDate,
+			})
+			best = len(clusters) - 1
+			created++
+		} else {
+			cluster := &clusters[best]
+			n := float64(cluster.MemberCount)
+			for i := range cluster.Centroid {
+				cluster.Centroid[i] = (cluster.Centroid[i]*n + candidate.Embedding[i]) / (n + 1)
+			}
+			cluster.MemberCount++
+			if candidate.PublicationDate.After(cluster.LatestAt) {
+				cluster.LatestAt = candidate.PublicationDate
+			}
+		}
+
+		changed[best] = true
+		assignments = append(assignments, repositories.ClusterAssignment{
+			ArticleID: candidate.ID,
+			ClusterID: clusters[best].ID,
+		})
+	}
+
+	result := make([]repositories.ClusterCentroid, 0, len(changed))
+	for i := range clusters {
+		if changed[i] {
+			result = append(result, clusters[i])
+		}
+	}
+	return result, assignments, created
+}
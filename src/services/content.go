@@ -0,0 +1,370 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"news-inshorts/src/infra"
+)
+
+// Errors returned by ContentFetcher.Fetch
+var (
+	ErrRobotsDisallowed  = errors.New("fetching the page is disallowed by robots.txt")
+	ErrContentTooLarge   = errors.New("page exceeds the maximum body size")
+	ErrNoReadableContent = errors.New("no readable content found on the page")
+)
+
+// robotsTTL is how long a host's robots.txt rules are reused before being fetched again
+const robotsTTL = 24 * time.Hour
+
+// ContentFetcher defines the interface for fetching the readable text of article pages
+type ContentFetcher interface {
+	// Fetch downloads the page at rawURL and returns its main text
+	Fetch(ctx context.Context, rawURL string) (string, error)
+}
+
+// contentFetcher implements ContentFetcher. Requests to a host are spaced by the configured
+// interval and checked against the host's robots.txt first.
+type contentFetcher struct {
+	config     *infra.ContentConfig
+	httpClient *http.Client
+	logger     infra.Logger
+
+	mu sync.Mutex
+	// nextRequest is the earliest time the next request to each host may start
+	nextRequest map[string]time.Time
+	robots      map[string]*hostRobots
+}
+
+// hostRobots holds the robots.txt rules of a host. ready is closed once rules is set, so
+// concurrent fetches for the same host wait for a single robots.txt request.
+type hostRobots struct {
+	ready     chan struct{}
+	rules     *robotsRules
+	fetchedAt time.Time
+}
+
+// NewContentFetcher creates a new content fetcher, or returns nil when content fetching is disabled
+func NewContentFetcher(cfg *infra.ContentConfig, logger infra.Logger) ContentFetcher {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return &contentFetcher{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		logger:      logger,
+		nextRequest: make(map[string]time.Time),
+		robots:      make(map[string]*hostRobots),
+	}
+}
+
+// Fetch downloads the page at rawURL and extracts its main text
+func (f *contentFetcher) Fetch(ctx context.Context, rawURL string) (string, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return "", fmt.Errorf("invalid article url %q", rawURL)
+	}
+
+	rules, err := f.robotsRules(ctx, pageURL)
+	if err != nil {
+		return "", err
+	}
+	if !rules.allowed(pageURL.RequestURI()) {
+		return "", ErrRobotsDisallowed
+	}
+
+	body, err := f.get(ctx, pageURL, "text/html")
+	if err != nil {
+		return "", err
+	}
+
+	content := extractReadableText(body)
+	if content == "" {
+		return "", ErrNoReadableContent
+	}
+	return content, nil
+}
+
+// robotsRules returns the robots.txt rules of the page's host, fetching them when not cached
+func (f *contentFetcher) robotsRules(ctx context.Context, pageURL *url.URL) (*robotsRules, error) {
+	host := strings.ToLower(pageURL.Host)
+
+	f.mu.Lock()
+	entry, found := f.robots[host]
+	if found {
+		select {
+		case <-entry.ready:
+			if time.Since(entry.fetchedAt) > robotsTTL {
+				found = false
+			}
+		default:
+		}
+	}
+	if !found {
+		entry = &hostRobots{ready: make(chan struct{})}
+		f.robots[host] = entry
+	}
+	f.mu.Unlock()
+
+	if found {
+		select {
+		case <-entry.ready:
+			return entry.rules, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	robotsURL := &url.URL{Scheme: pageURL.Scheme, Host: pageURL.Host, Path: "/robots.txt"}
+	entry.rules = f.fetchRobots(ctx, robotsURL)
+	entry.fetchedAt = time.Now()
+	close(entry.ready)
+
+	// Rules that failed only because the caller gave up are not kept for later fetches
+	if ctx.Err() != nil {
+		f.mu.Lock()
+		if f.robots[host] == entry {
+			delete(f.robots, host)
+		}
+		f.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	return entry.rules, nil
+}
+
+// fetchRobots downloads and parses robots.txt. A missing file allows everything; a file that
+// cannot be read disallows everything, so an unreachable host is not crawled blindly.
+func (f *contentFetcher) fetchRobots(ctx context.Context, robotsURL *url.URL) *robotsRules {
+	body, err := f.get(ctx, robotsURL, "")
+	if err != nil {
+		var statusErr *httpStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 {
+			return &robotsRules{}
+		}
+		f.logger.Warn("Failed to fetch robots.txt, treating host as disallowed", map[string]interface{}{
+			"url":   robotsURL.String(),
+			"error": err.Error(),
+		})
+		return &robotsRules{disallowAll: true}
+	}
+	return parseRobots(string(body), f.config.UserAgent)
+}
+
+// httpStatusError is returned by get for non-2xx responses
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+}
+
+// get performs a rate-limited GET and returns the body, which must not exceed the configured
+// size. When mediaType is set, responses of another content type are rejected.
+func (f *contentFetcher) get(ctx context.Context, target *url.URL, mediaType string) ([]byte, error) {
+	if err := f.waitForHost(ctx, target.Host); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.config.UserAgent)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", target.String(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &httpStatusError{StatusCode: resp.StatusCode}
+	}
+
+	if mediaType != "" {
+		contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if contentType != mediaType && contentType != "application/xhtml+xml" {
+			return nil, fmt.Errorf("unexpected content type %q", contentType)
+		}
+	}
+
+	if resp.ContentLength > f.config.MaxBodyBytes {
+		return nil, ErrContentTooLarge
+	}
+
+	// Read one byte past the limit to tell a body of exactly the limit from a longer one
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > f.config.MaxBodyBytes {
+		return nil, ErrContentTooLarge
+	}
+	return body, nil
+}
+
+// waitForHost blocks until a request to host may start, reserving the next slot for it
+func (f *contentFetcher) waitForHost(ctx context.Context, host string) error {
+	host = strings.ToLower(host)
+
+	f.mu.Lock()
+	now := time.Now()
+	start := f.nextRequest[host]
+	if start.Before(now) {
+		start = now
+	}
+	f.nextRequest[host] = start.Add(f.config.DomainInterval)
+	f.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// robotsRules are the Allow and Disallow rules of the robots.txt group that applies to us
+type robotsRules struct {
+	disallowAll bool
+	allow       []string
+	disallow    []string
+}
+
+// allowed reports whether path may be fetched. The longest matching rule wins, and Allow wins
+// a tie, as specified by RFC 9309.
+func (r *robotsRules) allowed(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	allowLen, disallowLen := -1, -1
+	for _, pattern := range r.allow {
+		if len(pattern) > allowLen && robotsPatternMatches(pattern, path) {
+			allowLen = len(pattern)
+		}
+	}
+	for _, pattern := range r.disallow {
+		if len(pattern) > disallowLen && robotsPatternMatches(pattern, path) {
+			disallowLen = len(pattern)
+		}
+	}
+	return disallowLen < 0 || allowLen >= disallowLen
+}
+
+// robotsPatternMatches matches a robots.txt path pattern, where * matches any sequence of
+// characters and a trailing $ anchors the pattern at the end of the path
+func robotsPatternMatches(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		// The last part of an anchored pattern has to end the path
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
+
+// parseRobots parses robots.txt, keeping the rules of the group naming userAgent's product
+// token, or of the * group when no group names it
+func parseRobots(body, userAgent string) *robotsRules {
+	token := strings.ToLower(strings.SplitN(userAgent, "/", 2)[0])
+
+	var specific, wildcard *robotsRules
+	var current []*robotsRules
+	inAgents := false
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive User-agent lines share the group that follows them
+			if !inAgents {
+				current = nil
+				inAgents = true
+			}
+			agent := strings.ToLower(value)
+			switch {
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = &robotsRules{}
+				}
+				current = append(current, wildcard)
+			case agent != "" && strings.Contains(token, agent):
+				if specific == nil {
+					specific = &robotsRules{}
+				}
+				current = append(current, specific)
+			}
+		case "allow", "disallow":
+			inAgents = false
+			// An empty Disallow allows everything and adds no rule
+			if value == "" {
+				continue
+			}
+			for _, rules := range current {
+				if key == "allow" {
+					rules.allow = append(rules.allow, value)
+				} else {
+					rules.disallow = append(rules.disallow, value)
+				}
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	if specific != nil {
+		return specific
+	}
+	if wildcard != nil {
+		return wildcard
+	}
+	return &robotsRules{}
+}
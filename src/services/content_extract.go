@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// minParagraphLength is the length below which a paragraph is treated as page furniture
+// (captions, bylines, share prompts) rather than article text
+const minParagraphLength = 40
+
+// Elements whose text is never part of the article body
+var skippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+	atom.Button:   true,
+	atom.Figure:   true,
+}
+
+// unlikelyContainer matches class and id values of page sections that are not article text
+var unlikelyContainer = regexp.MustCompile(`(?i)comment|footer|sidebar|related|share|social|promo|advert|newsletter|subscribe|cookie|banner|menu|breadcrumb`)
+
+// extractReadableText returns the main text of an HTML page, readability-style: paragraphs are
+// scored by length and punctuation, each paragraph's score is credited to its parent (and half
+// to its grandparent), and the paragraphs of the best scoring container form the text.
+// Paragraphs are separated by blank lines. An empty string means no article text was found.
+func extractReadableText(page []byte) string {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return ""
+	}
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if skippedElements[n.DataAtom] || isUnlikelyContainer(n) {
+				return
+			}
+			if n.DataAtom == atom.P {
+				text := nodeText(n)
+				if len(text) >= minParagraphLength && n.Parent != nil {
+					score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+					if _, ok := scores[n.Parent]; !ok {
+						candidates = append(candidates, n.Parent)
+					}
+					scores[n.Parent] += score
+					if grandparent := n.Parent.Parent; grandparent != nil {
+						if _, ok := scores[grandparent]; !ok {
+							candidates = append(candidates, grandparent)
+						}
+						scores[grandparent] += score / 2
+					}
+				}
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	// Candidates are kept in document order so ties go to the first container
+	var best *html.Node
+	for _, candidate := range candidates {
+		if best == nil || scores[candidate] > scores[best] {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return ""
+	}
+
+	var paragraphs []string
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if skippedElements[n.DataAtom] || isUnlikelyContainer(n) {
+				return
+			}
+			switch n.DataAtom {
+			case atom.P, atom.H2, atom.H3, atom.Li, atom.Blockquote:
+				if text := nodeText(n); text != "" && (n.DataAtom != atom.P || len(text) >= minParagraphLength) {
+					paragraphs = append(paragraphs, text)
+				}
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(best)
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// isUnlikelyContainer reports whether the element's class or id marks it as page furniture
+func isUnlikelyContainer(n *html.Node) bool {
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Article {
+		return false
+	}
+	for _, attr := range n.Attr {
+		if (attr.Key == "class" || attr.Key == "id") && unlikelyContainer.MatchString(attr.Val) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns the text inside n with whitespace collapsed
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+			return
+		}
+		if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
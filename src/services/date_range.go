@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Relative periods the LLM may return for a date range intent
+const (
+	DatePeriodToday     = "today"
+	DatePeriodYesterday = "yesterday"
+	DatePeriodThisWeek  = "this_week"
+	DatePeriodLastWeek  = "last_week"
+	DatePeriodThisMonth = "this_month"
+	DatePeriodLastMonth = "last_month"
+	DatePeriodLastNDays = "last_n_days"
+)
+
+// maxDateRangeDays caps last_n_days so a stray number does not turn into a decades-long window
+const maxDateRangeDays = 366
+
+// dateRangeSpec is the date range as the LLM describes it: either a relative period or
+// explicit calendar dates (YYYY-MM-DD, both inclusive)
+type dateRangeSpec struct {
+	Period string `json:"period"`
+	Days   *int   `json:"days"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	// Confidence is the model's confidence in the date range; see intentConfidence
+	Confidence *float64 `json:"confidence"`
+}
+
+// empty reports whether the query carried no time expression
+func (d dateRangeSpec) empty() bool {
+	return strings.TrimSpace(d.Period) == "" && strings.TrimSpace(d.From) == "" && strings.TrimSpace(d.To) == ""
+}
+
+// resolveDateRange turns spec into an absolute [from, to) window of whole UTC days relative
+// to now. Windows that end in the current day run to the start of tomorrow, so the result is
+// stable for the rest of the day. Explicit dates may leave one side open, which is returned as
+// the zero time. An error is returned for unknown periods, malformed dates and empty or
+// inverted ranges.
+func resolveDateRange(spec dateRangeSpec, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	tomorrow := today.AddDate(0, 0, 1)
+	// Weeks start on Monday
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var from, to time.Time
+	switch period := strings.ToLower(strings.TrimSpace(spec.Period)); period {
+	case DatePeriodToday:
+		from, to = today, tomorrow
+	case DatePeriodYesterday:
+		from, to = today.AddDate(0, 0, -1), today
+	case DatePeriodThisWeek:
+		from, to = weekStart, tomorrow
+	case DatePeriodLastWeek:
+		from, to = weekStart.AddDate(0, 0, -7), weekStart
+	case DatePeriodThisMonth:
+		from, to = monthStart, tomorrow
+	case DatePeriodLastMonth:
+		from, to = monthStart.AddDate(0, -1, 0), monthStart
+	case DatePeriodLastNDays:
+		if spec.Days == nil || *spec.Days < 1 || *spec.Days > maxDateRangeDays {
+			return time.Time{}, time.Time{}, fmt.Errorf("last_n_days needs days between 1 and %d", maxDateRangeDays)
+		}
+		from, to = today.AddDate(0, 0, -*spec.Days), tomorrow
+	case "":
+		var err error
+		if from, err = parseSpecDate(spec.From); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date: %w", err)
+		}
+		if to, err = parseSpecDate(spec.To); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date: %w", err)
+		}
+		if from.IsZero() && to.IsZero() {
+			return time.Time{}, time.Time{}, fmt.Errorf("date range has no bounds")
+		}
+		// The LLM gives an inclusive end date
+		if !to.IsZero() {
+			to = to.AddDate(0, 0, 1)
+		}
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown period %q", period)
+	}
+
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range ends before it starts")
+	}
+
+	return from, to, nil
+}
+
+// parseSpecDate parses a YYYY-MM-DD date, returning the zero time for an empty string
+func parseSpecDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// formatDateRangeValues encodes a resolved window as date range intent values. An open bound
+// is encoded as an empty string.
+func formatDateRangeValues(from, to time.Time) []string {
+	values := []string{"", ""}
+	if !from.IsZero() {
+		values[0] = from.UTC().Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		values[1] = to.UTC().Format(time.RFC3339)
+	}
+	return values
+}
+
+// parseDateRangeValues decodes date range intent values produced by formatDateRangeValues
+func parseDateRangeValues(values interface{}) (time.Time, time.Time, bool) {
+	list, ok := values.([]string)
+	if !ok || len(list) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	var bounds [2]time.Time
+	for i, value := range list {
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+		bounds[i] = t
+	}
+
+	if bounds[0].IsZero() && bounds[1].IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	return bounds[0], bounds[1], true
+}
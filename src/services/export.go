@@ -0,0 +1,243 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// ErrExportRunning is returned when a snapshot is requested while another is in progress
+var ErrExportRunning = errors.New("export already in progress")
+
+// ErrExportNotConfigured is returned when snapshots are requested without a configured bucket
+var ErrExportNotConfigured = errors.New("export storage is not configured")
+
+// exportProgressInterval is how many rows are written between job progress updates
+const exportProgressInterval = 1000
+
+// snapshotContentType is the Content-Type of uploaded snapshots
+const snapshotContentType = "application/gzip"
+
+// ExportService uploads snapshots of the article corpus to object storage as gzipped NDJSON
+// and records each run
+type ExportService interface {
+	// Enabled reports whether a bucket is configured
+	Enabled() bool
+	// Run takes a snapshot and blocks until it is uploaded. An incremental snapshot holds the
+	// articles created since the last successful snapshot, or every article if there is none.
+	Run(ctx context.Context, mode string, includeVectors bool) (models.ExportRun, error)
+	StartRun(mode string, includeVectors bool) (models.Job, error)
+	ListRuns(ctx context.Context, limit int) ([]models.ExportRun, error)
+}
+
+// exportService implements ExportService
+type exportService struct {
+	articleRepo   repositories.ArticleRepository
+	exportRunRepo repositories.ExportRunRepository
+	store         ObjectStore
+	jobs          *JobTracker
+	cfg           *infra.ExportConfig
+	clock         infra.Clock
+	log           infra.Logger
+
+	running atomic.Bool
+}
+
+// NewExportService creates a new instance of ExportService. A nil store disables snapshots.
+func NewExportService(articleRepo repositories.ArticleRepository, exportRunRepo repositories.ExportRunRepository, store ObjectStore, jobs *JobTracker, cfg *infra.ExportConfig, clock infra.Clock, logger infra.Logger) ExportService {
+	return &exportService{
+		articleRepo:   articleRepo,
+		exportRunRepo: exportRunRepo,
+		store:         store,
+		jobs:          jobs,
+		cfg:           cfg,
+		clock:         clock,
+		log:           logger,
+	}
+}
+
+// Enabled implements ExportService
+func (s *exportService) Enabled() bool {
+	return s.store != nil
+}
+
+// Run takes a snapshot in the calling goroutine
+func (s *exportService) Run(ctx context.Context, mode string, includeVectors bool) (models.ExportRun, error) {
+	if !s.Enabled() {
+		return models.ExportRun{}, ErrExportNotConfigured
+	}
+	if !s.running.CompareAndSwap(false, true) {
+		return models.ExportRun{}, ErrExportRunning
+	}
+	defer s.running.Store(false)
+
+	return s.snapshot(ctx, mode, includeVectors, nil)
+}
+
+// StartRun launches a snapshot in the background and returns a job that can be polled for progress
+func (s *exportService) StartRun(mode string, includeVectors bool) (models.Job, error) {
+	if !s.Enabled() {
+		return models.Job{}, ErrExportNotConfigured
+	}
+	if !s.running.CompareAndSwap(false, true) {
+		return models.Job{}, ErrExportRunning
+	}
+
+	job := s.jobs.Start("export")
+
+	go func() {
+		defer s.running.Store(false)
+
+		// The snapshot outlives the request that started it, so it gets its own context
+		_, err := s.snapshot(context.Background(), mode, includeVectors, func(rows int64) {
+			s.jobs.Update(job.ID, func(j *models.Job) {
+				j.Processed = int(rows)
+				j.Succeeded = int(rows)
+			})
+		})
+		s.jobs.Finish(job.ID, err)
+	}()
+
+	return job, nil
+}
+
+// ListRuns returns the most recent runs, newest first
+func (s *exportService) ListRuns(ctx context.Context, limit int) ([]models.ExportRun, error) {
+	return s.exportRunRepo.List(ctx, limit)
+}
+
+// snapshot records a run, streams the articles through gzip into the object store and records
+// the outcome. Rows are encoded while earlier output is uploaded, so the snapshot is never
+// held in memory beyond one upload part.
+func (s *exportService) snapshot(ctx context.Context, mode string, includeVectors bool, progress func(rows int64)) (models.ExportRun, error) {
+	startedAt := s.clock.Now().UTC()
+	run := models.ExportRun{
+		Mode:           mode,
+		IncludeVectors: includeVectors,
+		Status:         models.ExportStatusRunning,
+		ObjectKey:      fmt.Sprintf("%sarticles-%s-%s.ndjson.gz", s.cfg.S3.Prefix, mode, startedAt.Format("20060102T150405Z")),
+	}
+
+	if mode == models.ExportModeIncremental {
+		last, err := s.exportRunRepo.LastSucceeded(ctx)
+		if err != nil {
+			return run, err
+		}
+		if last != nil {
+			run.Since = last.Watermark
+			if run.Since == nil {
+				run.Since = last.Since
+			}
+		}
+	}
+
+	if err := s.exportRunRepo.Create(ctx, &run); err != nil {
+		return run, err
+	}
+
+	s.log.Info("Starting article snapshot", map[string]interface{}{
+		"id":              run.ID,
+		"mode":            run.Mode,
+		"since":           run.Since,
+		"include_vectors": run.IncludeVectors,
+		"object_key":      run.ObjectKey,
+	})
+
+	var after time.Time
+	if run.Since != nil {
+		after = *run.Since
+	}
+
+	reader, writer := io.Pipe()
+	var rows int64
+	var watermark *time.Time
+	writeDone := make(chan error, 1)
+
+	go func() {
+		gz := gzip.NewWriter(writer)
+		enc := json.NewEncoder(gz)
+		err := s.articleRepo.StreamSnapshot(ctx, after, includeVectors, func(article repositories.SnapshotArticle) error {
+			if err := enc.Encode(article); err != nil {
+				return fmt.Errorf("failed to write article: %w", err)
+			}
+			rows++
+			createdAt := article.CreatedAt
+			watermark = &createdAt
+			if progress != nil && rows%exportProgressInterval == 0 {
+				progress(rows)
+			}
+			return nil
+		})
+		if err == nil {
+			err = gz.Close()
+		}
+		// Closing with an error makes the upload fail instead of storing a truncated snapshot
+		writer.CloseWithError(err)
+		writeDone <- err
+	}()
+
+	size, uploadErr := s.store.Upload(ctx, run.ObjectKey, reader, snapshotContentType)
+	// Unblock the writer if the upload stopped reading early
+	reader.CloseWithError(errors.New("upload finished"))
+	writeErr := <-writeDone
+
+	// A failed upload also fails the writer, so its error is the cause
+	runErr := uploadErr
+	if runErr == nil {
+		runErr = writeErr
+	}
+
+	finishedAt := s.clock.Now().UTC()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(startedAt).Milliseconds()
+	run.Rows = rows
+	run.Bytes = size
+	run.Status = models.ExportStatusSucceeded
+	run.Watermark = watermark
+	if run.Watermark == nil {
+		// An empty incremental snapshot starts the next one at the same place
+		run.Watermark = run.Since
+	}
+	if runErr != nil {
+		run.Status = models.ExportStatusFailed
+		run.Error = runErr.Error()
+		run.Bytes = 0
+	}
+	if progress != nil {
+		progress(rows)
+	}
+
+	// The run is recorded even when ctx was cancelled mid-snapshot
+	if err := s.exportRunRepo.Finish(context.Background(), &run); err != nil {
+		if runErr == nil {
+			runErr = err
+		}
+	}
+
+	if runErr != nil {
+		s.log.Error("Article snapshot failed", runErr, map[string]interface{}{
+			"id":   run.ID,
+			"rows": rows,
+		})
+		return run, runErr
+	}
+
+	s.log.Info("Completed article snapshot", map[string]interface{}{
+		"id":         run.ID,
+		"rows":       run.Rows,
+		"bytes":      run.Bytes,
+		"duration":   finishedAt.Sub(startedAt).String(),
+		"object_key": run.ObjectKey,
+	})
+
+	return run, nil
+}
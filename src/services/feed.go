@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/renderer"
+	"news-inshorts/src/types"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// FeedService renders article listings as syndication feeds
+type FeedService interface {
+	// RenderRSS renders trending articles, or filtered articles when the request names a
+	// category or source, as RSS 2.0. link is the URL the channel is published at.
+	RenderRSS(ctx context.Context, req types.FeedRequest, link string) ([]byte, error)
+}
+
+// feedService implements FeedService, caching rendered feeds in Redis since feed readers
+// poll aggressively
+type feedService struct {
+	articleService ArticleService
+	redisClient    *redis.Client
+	cacheTTL       time.Duration
+	defaultTenant  string
+	log            infra.Logger
+}
+
+// NewFeedService creates a new instance of FeedService. Feeds are cached per tenant, with
+// defaultTenant used for contexts carrying none.
+func NewFeedService(articleService ArticleService, redisClient *redis.Client, cacheTTL time.Duration, defaultTenant string, logger infra.Logger) FeedService {
+	return &feedService{
+		articleService: articleService,
+		redisClient:    redisClient,
+		cacheTTL:       cacheTTL,
+		defaultTenant:  defaultTenant,
+		log:            logger,
+	}
+}
+
+// RenderRSS implements FeedService
+func (s *feedService) RenderRSS(ctx context.Context, req types.FeedRequest, link string) ([]byte, error) {
+	cacheKey := feedCacheKey(infra.TenantFromContext(ctx, s.defaultTenant), req, link, infra.ExperimentVariantFromContext(ctx, infra.ExperimentTrendingFormula))
+
+	if val, err := s.redisClient.Get(ctx, cacheKey).Bytes(); err == nil {
+		return val, nil
+	} else if err != redis.Nil {
+		s.log.Warn("Failed to get feed from Redis", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+	}
+
+	channel := renderer.FeedChannel{
+		Title:       "Inshorts - Trending",
+		Link:        link,
+		Description: "Trending news articles",
+	}
+
+	var articles []models.Article
+	var err error
+	if req.Category != "" || req.Source != "" {
+		channel.Title = "Inshorts - " + strings.Join(nonEmpty(req.Category, req.Source), " - ")
+		channel.Description = "News articles filtered by " + strings.Join(nonEmpty(
+			labelled("category", req.Category),
+			labelled("source", req.Source),
+		), " and ")
+
+		articles, err = s.articleService.FilterArticles(ctx, types.FilterArticlesRequest{
+			Category: types.SplitList(req.Category),
+			Source:   types.SplitList(req.Source),
+		})
+		if len(articles) > req.Limit {
+			articles = articles[:req.Limit]
+		}
+	} else {
+		var trending []models.TrendingArticle
+		trending, err = s.articleService.GetTrendingNews(ctx, req.Lat, req.Lon, req.Limit)
+		for _, article := range trending {
+			articles = append(articles, article.Article)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed articles: %w", err)
+	}
+
+	body, err := renderer.RenderRSS(channel, articles, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.redisClient.Set(ctx, cacheKey, body, s.cacheTTL).Err(); err != nil {
+		s.log.Warn("Failed to cache feed in Redis", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+	}
+
+	return body, nil
+}
+
+// feedCacheKey builds the cache key for a tenant's feed request, normalizing lists the same way the
+// filter cache does. variant is the request's trending_formula variant, which orders
+// trending feeds.
+func feedCacheKey(tenant string, req types.FeedRequest, link, variant string) string {
+	canonical := fmt.Sprintf("link=%s|category=%s|source=%s|lat=%g|lon=%g|limit=%d|trending=%s",
+		link,
+		canonicalList(req.Category, false),
+		canonicalList(req.Source, true),
+		req.Lat,
+		req.Lon,
+		req.Limit,
+		variant,
+	)
+
+	sum := sha256.Sum256([]byte(canonical))
+	return infra.FeedCacheKey(tenant, hex.EncodeToString(sum[:16]))
+}
+
+// labelled returns "label value", or an empty string when value is empty
+func labelled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + " " + value
+}
+
+// nonEmpty returns the non-empty values
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/types"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// filterCache is a read-through Redis cache for GET /news/filter results
+type filterCache struct {
+	redisClient *redis.Client
+	ttl         time.Duration
+	log         infra.Logger
+}
+
+// newFilterCache creates a filterCache. A nil client or non-positive ttl disables caching.
+func newFilterCache(redisClient *redis.Client, ttl time.Duration, logger infra.Logger) *filterCache {
+	return &filterCache{
+		redisClient: redisClient,
+		ttl:         ttl,
+		log:         logger,
+	}
+}
+
+// enabled reports whether results should be cached
+func (fc *filterCache) enabled() bool {
+	return fc.redisClient != nil && fc.ttl > 0
+}
+
+// get returns the tenant's cached result for params along with the key to store a fresh result
+// under. An empty key means the cache could not be consulted and the result should not be stored.
+func (fc *filterCache) get(ctx context.Context, tenant string, params types.FilterArticlesRequest) ([]models.Article, string, bool) {
+	generation, err := fc.redisClient.Get(ctx, infra.FilterCacheGenerationKey).Int64()
+	if err != nil && err != redis.Nil {
+		fc.log.Warn("Failed to read filter cache generation", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, "", false
+	}
+
+	cacheKey := filterCacheKey(generation, tenant, params)
+
+	val, err := fc.redisClient.Get(ctx, cacheKey).Result()
+	if err != nil {
+		if err != redis.Nil {
+			fc.log.Warn("Failed to get filter results from Redis", map[string]interface{}{
+				"cache_key": cacheKey,
+				"error":     err.Error(),
+			})
+		}
+		return nil, cacheKey, false
+	}
+
+	var articles []models.Article
+	if err := json.Unmarshal([]byte(val), &articles); err != nil {
+		fc.log.Warn("Failed to unmarshal cached filter results", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+		fc.redisClient.Del(ctx, cacheKey)
+		return nil, cacheKey, false
+	}
+
+	fc.log.Debug("Cache hit for filter results", map[string]interface{}{
+		"cache_key": cacheKey,
+		"count":     len(articles),
+	})
+
+	return articles, cacheKey, true
+}
+
+// set stores articles under cacheKey
+func (fc *filterCache) set(ctx context.Context, cacheKey string, articles []models.Article) {
+	data, err := json.Marshal(articles)
+	if err != nil {
+		fc.log.Warn("Failed to marshal filter results for cache", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	if err := fc.redisClient.Set(ctx, cacheKey, data, fc.ttl).Err(); err != nil {
+		fc.log.Warn("Failed to cache filter results in Redis", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// invalidate makes every cached filter result stale by bumping the generation counter
+func (fc *filterCache) invalidate(ctx context.Context) {
+	if !fc.enabled() {
+		return
+	}
+
+	if err := fc.redisClient.Incr(ctx, infra.FilterCacheGenerationKey).Err(); err != nil {
+		fc.log.Warn("Failed to invalidate filter cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// filterCacheKey builds the cache key for the tenant's params. List values are trimmed and
+// sorted so equivalent requests share an entry. Sources, countries and regions are lowercased
+// since they match case-insensitively; categories keep their case because category matching is
+// exact.
+func filterCacheKey(generation int64, tenant string, params types.FilterArticlesRequest) string {
+	categoryMode := params.CategoryMode
+	if categoryMode == "" {
+		categoryMode = types.CategoryModeAll
+	}
+
+	canonical := fmt.Sprintf("category=%s|category_mode=%s|source=%s|exclude_category=%s|exclude_source=%s|country=%s|region=%s|place=%s|lat=%g|lon=%g|radius=%g|score=%g|sentiment=%s|from=%d|to=%d|ingested_after=%s|ingested_after_id=%s|limit=%d|archived=%t",
+		canonicalValues(params.Category, false),
+		categoryMode,
+		canonicalValues(params.Source, true),
+		canonicalValues(params.ExcludeCategory, false),
+		canonicalValues(params.ExcludeSource, true),
+		canonicalValues(params.Country, true),
+		canonicalValues(params.Region, true),
+		canonicalValues(params.Place, true),
+		params.Lat,
+		params.Lon,
+		params.Radius,
+		params.ScoreThreshold,
+		canonicalList(params.Sentiment, true),
+		params.PublishedFrom.Unix(),
+		params.PublishedTo.Unix(),
+		params.IngestedAfter,
+		strings.ToLower(params.IngestedAfterID),
+		params.Limit,
+		params.IncludeArchived,
+	)
+
+	sum := sha256.Sum256([]byte(canonical))
+	return infra.FilterCacheKey(generation, tenant, hex.EncodeToString(sum[:16]))
+}
+
+// canonicalList trims, optionally lowercases, de-duplicates and sorts a comma-separated list
+func canonicalList(value string, lowercase bool) string {
+	return canonicalValues(strings.Split(value, ","), lowercase)
+}
+
+// canonicalValues trims, optionally lowercases, de-duplicates, sorts and joins values
+func canonicalValues(values []string, lowercase bool) string {
+	seen := make(map[string]bool)
+	items := []string{}
+	for _, item := range values {
+		item = strings.TrimSpace(item)
+		if lowercase {
+			item = strings.ToLower(item)
+		}
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
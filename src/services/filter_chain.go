@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
 	"strconv"
+	"time"
 
 	"news-inshorts/src/infra"
 	"news-inshorts/src/models"
@@ -13,17 +16,68 @@ import (
 // Filter defines the function type for filtering articles
 type Filter func(ctx context.Context, in *[]models.Article) (*[]models.Article, error)
 
-// Chain composes multiple filters into a single filter pipeline
-func Chain(ctx context.Context, filters ...Filter) ([]models.Article, error) {
+// NamedFilter pairs a Filter with the name it is reported under in logs
+type NamedFilter struct {
+	Name   string
+	Filter Filter
+}
+
+// FilterError reports the step of a filter pipeline that failed
+type FilterError struct {
+	// Filter is the name of the failed step, as in NamedFilter
+	Filter string
+	// Stage is the position of the step in the pipeline, starting at 0
+	Stage int
+	// InputCount is the number of articles the step was given
+	InputCount int
+	Err        error
+}
+
+// Error implements the error interface
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s filter (stage %d, %d input articles) failed: %v", e.Filter, e.Stage, e.InputCount, e.Err)
+}
+
+// Unwrap returns the cause so errors.Is/As can inspect it
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
+
+// LogFields returns the fields identifying the failed step in logs
+func (e *FilterError) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"filter":      e.Filter,
+		"stage":       e.Stage,
+		"input_count": e.InputCount,
+	}
+}
+
+// Chain composes multiple filters into a single filter pipeline. Each step is logged to logger
+// at debug level with its input and output counts and how long it took. A failing step is
+// returned as a *FilterError.
+func Chain(ctx context.Context, logger infra.Logger, filters ...NamedFilter) ([]models.Article, error) {
 	articles := []models.Article{}
 
-	for _, filter := range filters {
-		filteredArticles, err := filter(ctx, &articles)
-		if err != nil {
+	for stage, filter := range filters {
+		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
+
+		inputCount := len(articles)
+		start := time.Now()
+
+		filteredArticles, err := filter.Filter(ctx, &articles)
+		if err != nil {
+			return nil, &FilterError{Filter: filter.Name, Stage: stage, InputCount: inputCount, Err: err}
+		}
 		articles = *filteredArticles
-		fmt.Println("Retrived articles after filter: ", len(articles), "with filter: ", filter)
+
+		logger.Debug("Filter applied", map[string]interface{}{
+			"filter":       filter.Name,
+			"input_count":  inputCount,
+			"output_count": len(articles),
+			"elapsed":      time.Since(start).String(),
+		})
 	}
 	return articles, nil
 }
@@ -35,17 +89,29 @@ type FilterFactory func(params map[string]interface{}) Filter
 type FilterChain struct {
 	filterRegistry map[string]FilterFactory
 	articleRepo    repositories.ArticleRepository
+	sourceAliases  repositories.SourceAliasRepository
 	llmService     LLMService
+	queryCfg       *infra.QueryConfig
+	vectorCfg      *infra.VectorConfig
+	dedupeCfg      *infra.DedupeConfig
 	logger         infra.Logger
 }
 
-// NewFilterChain creates a new FilterChain instance
-func NewFilterChain(articleRepo repositories.ArticleRepository, llmService LLMService) *FilterChain {
+// NewFilterChain creates a new FilterChain instance. queryCfg sets the radius of nearby
+// filters and the range radii from the query are clamped to, how many recent articles a query
+// without intents is answered from, and the confidence intents need to become filters;
+// vectorCfg sets how many nearest neighbors semantic search considers and how similar they
+// must be, and dedupeCfg when results are near-duplicates.
+func NewFilterChain(articleRepo repositories.ArticleRepository, sourceAliases repositories.SourceAliasRepository, llmService LLMService, queryCfg *infra.QueryConfig, vectorCfg *infra.VectorConfig, dedupeCfg *infra.DedupeConfig, logger infra.Logger) *FilterChain {
 	chain := &FilterChain{
 		filterRegistry: make(map[string]FilterFactory),
 		articleRepo:    articleRepo,
+		sourceAliases:  sourceAliases,
 		llmService:     llmService,
-		logger:         infra.GetLogger(),
+		queryCfg:       queryCfg,
+		vectorCfg:      vectorCfg,
+		dedupeCfg:      dedupeCfg,
+		logger:         logger,
 	}
 
 	if articleRepo != nil {
@@ -71,7 +137,7 @@ func (fc *FilterChain) RegisterDefaultFilters() {
 		if s, ok := params["source"].([]string); ok {
 			sources = s
 		}
-		return FilterBySource(fc.articleRepo, sources)
+		return FilterBySource(fc.articleRepo, fc.sourceAliases, sources)
 
 	}
 	fc.filterRegistry[models.IntentTypeScore] = func(params map[string]interface{}) Filter {
@@ -105,12 +171,25 @@ func (fc *FilterChain) RegisterDefaultFilters() {
 				}
 			}
 		}
-		return FilterByTextSearch(fc.articleRepo, fc.llmService, query)
+		minSimilarity := fc.vectorCfg.MinSimilarity
+		if m, ok := params["min_similarity"].(float64); ok {
+			minSimilarity = m
+		}
+		return FilterByTextSearch(fc.articleRepo, fc.llmService, query, fc.vectorCfg.SearchLimit, minSimilarity, fc.logger)
 	}
 	fc.filterRegistry[models.IntentTypeNearby] = func(params map[string]interface{}) Filter {
 		lat := 0.0
 		lon := 0.0
-		radius := 50.0
+		radius := fc.queryCfg.DefaultRadiusKm
+
+		if _, ok := params["radius"]; ok {
+			if r, err := strconv.ParseFloat(params["radius"].(string), 64); err == nil {
+				radius = r
+			}
+		}
+		if points, ok := params["points"].([]models.Location); ok {
+			return FilterByMultiRadius(fc.articleRepo, points, radius)
+		}
 
 		if latitude, err := strconv.ParseFloat(params["latitude"].(string), 64); err == nil {
 			lat = latitude
@@ -118,26 +197,113 @@ func (fc *FilterChain) RegisterDefaultFilters() {
 		if longitude, err := strconv.ParseFloat(params["longitude"].(string), 64); err == nil {
 			lon = longitude
 		}
-		if _, ok := params["radius"]; ok {
-			if r, err := strconv.ParseFloat(params["radius"].(string), 64); err == nil {
-				radius = r
-			}
-		}
 		return FilterByRadius(fc.articleRepo, lat, lon, radius)
 	}
+	fc.filterRegistry[models.IntentTypeSentiment] = func(params map[string]interface{}) Filter {
+		sentiments, _ := params["sentiment"].([]string)
+		return FilterBySentiment(fc.articleRepo, sentiments)
+	}
+	fc.filterRegistry[models.IntentTypeRegion] = func(params map[string]interface{}) Filter {
+		places, _ := params["place"].([]string)
+		return FilterByRegion(fc.articleRepo, places)
+	}
+	fc.filterRegistry[models.IntentTypeExcludeCategory] = func(params map[string]interface{}) Filter {
+		categories, _ := params["category"].([]string)
+		return ExcludeByCategory(fc.articleRepo, categories)
+	}
+	fc.filterRegistry[models.IntentTypeExcludeSource] = func(params map[string]interface{}) Filter {
+		sources, _ := params["source"].([]string)
+		return ExcludeBySource(fc.articleRepo, fc.sourceAliases, sources)
+	}
+	fc.filterRegistry[models.IntentTypeDateRange] = func(params map[string]interface{}) Filter {
+		from, _ := params["from"].(time.Time)
+		to, _ := params["to"].(time.Time)
+		return FilterByDateRange(fc.articleRepo, from, to)
+	}
+}
+
+// Filter steps that do not come from an intent
+const (
+	// filterStepRecent stands for the most recently published articles a query without
+	// intents, entities or location ranks
+	filterStepRecent      = "recent"
+	filterStepPreferences = "preferences"
+	filterStepDedupe      = "dedupe"
+)
+
+// Execute applies all applicable filters based on the provided intents and returns the
+// ranked articles annotated with the metadata explaining each match. minSimilarity overrides
+// the configured minimum similarity of semantic matches when not nil. preferences are the
+// user's category weights the ranking is biased by; nil leaves the ranking unpersonalized.
+func (fc *FilterChain) Execute(ctx context.Context, intents []models.Intent, entities []string, location *models.Location, minSimilarity *float64, preferences map[string]float64) ([]models.EnrichedArticle, error) {
+	fc.logger.Debug("Executing filter chain", map[string]interface{}{
+		"intents":  intents,
+		"entities": entities,
+	})
+
+	return fc.ExecutePlan(ctx, fc.Plan(intents, entities, location, minSimilarity, preferences))
+}
+
+// ExecutePlan runs the steps of a plan derived by Plan, possibly altered since, and returns
+// the ranked articles annotated with the metadata explaining each match
+func (fc *FilterChain) ExecutePlan(ctx context.Context, plan []models.FilterStep) ([]models.EnrichedArticle, error) {
+	ctx, recorder := withMatchRecorder(ctx)
+
+	if len(plan) > 0 && plan[0].Name == filterStepRecent {
+		articles, _, err := fc.articleRepo.FindPage(ctx, "", fc.queryCfg.NoIntentLimit)
+		if err != nil {
+			return nil, err
+		}
+		var weights map[string]float64
+		if len(plan) > 1 && plan[1].Name == filterStepPreferences {
+			weights, _ = plan[1].Params["weights"].(map[string]float64)
+		}
+		ranked, err := RankByPreference(weights)(ctx, &articles)
+		if err != nil {
+			return nil, err
+		}
+		return recorder.enrich(*ranked), nil
+	}
+
+	filters := make([]NamedFilter, len(plan))
+	for i, step := range plan {
+		filters[i] = NamedFilter{Name: step.Name, Filter: fc.build(step)}
+	}
+
+	articles, err := Chain(ctx, fc.logger, filters...)
+	if err != nil {
+		return nil, err
+	}
+	return recorder.enrich(articles), nil
 }
 
-// Execute applies all applicable filters based on the provided intents
-func (fc *FilterChain) Execute(intents []models.Intent, entities []string, location *models.Location) ([]models.Article, error) {
+// Plan derives the ordered filter steps Execute runs for the intents, entities and location,
+// without touching the database. Intents below the minimum confidence for their type, of
+// unknown types or with invalid values are logged and left out. A query without intents,
+// entities or location yields a single recent step (followed by a preferences step when
+// there are preferences).
+func (fc *FilterChain) Plan(intents []models.Intent, entities []string, location *models.Location, minSimilarity *float64, preferences map[string]float64) []models.FilterStep {
+	intents = fc.confidentIntents(intents)
+	if location == nil {
+		// A nearby intent without places ("news near me") has nothing to be near
+		intents = slices.DeleteFunc(slices.Clone(intents), func(intent models.Intent) bool {
+			points, _, valid := parseNearbyValues(intent.Values)
+			return intent.Type == models.IntentTypeNearby && valid && len(points) == 0
+		})
+	}
 	if len(intents) == 0 && len(entities) == 0 && location == nil {
-		return fc.articleRepo.FindAll()
+		plan := []models.FilterStep{{Name: filterStepRecent, Params: map[string]interface{}{"limit": fc.queryCfg.NoIntentLimit}}}
+		if len(preferences) > 0 {
+			plan = append(plan, models.FilterStep{Name: filterStepPreferences, Params: map[string]interface{}{"weights": preferences}})
+		}
+		return plan
 	}
 
-	var filters []Filter
+	var plan []models.FilterStep
+	hasNearbyIntent := false
 
 	for _, intent := range intents {
-		factory, exists := fc.filterRegistry[intent.Type]
-		if !exists {
+		if _, exists := fc.filterRegistry[intent.Type]; !exists {
 			fc.logger.Error("Unknown intent type", nil, map[string]interface{}{"intent": intent.Type})
 			continue
 		}
@@ -163,22 +329,167 @@ func (fc *FilterChain) Execute(intents []models.Intent, entities []string, locat
 				fc.logger.Error("Invalid source values", nil, map[string]interface{}{"intent": intent.Type})
 			}
 		case models.IntentTypeNearby:
-			values, ok := intent.Values.([]string)
-			if !ok || len(values) < 2 {
+			hasNearbyIntent = true
+			points, radius, valid := parseNearbyValues(intent.Values)
+			if !valid {
 				fc.logger.Error("Invalid nearby values", nil, map[string]interface{}{"intent": intent.Type})
 				continue
 			}
-			params["latitude"] = values[0]
-			params["longitude"] = values[1]
+			params["radius"] = strconv.FormatFloat(fc.nearbyRadius(radius), 'f', -1, 64)
+			// An explicit caller location always wins over the places the LLM inferred
+			if location != nil {
+				params["latitude"] = strconv.FormatFloat(location.Latitude, 'f', -1, 64)
+				params["longitude"] = strconv.FormatFloat(location.Longitude, 'f', -1, 64)
+				break
+			}
+			// "near me" carries only a radius, which applies to the caller's location alone
+			if len(points) == 0 {
+				continue
+			}
+			// A single place, by far the most common, keeps the plain latitude/longitude step
+			if len(points) > 1 {
+				params["points"] = points
+				break
+			}
+			params["latitude"] = strconv.FormatFloat(points[0].Latitude, 'f', -1, 64)
+			params["longitude"] = strconv.FormatFloat(points[0].Longitude, 'f', -1, 64)
+		case models.IntentTypeScore:
+			threshold, ok := parseThreshold(intent.Values)
+			if !ok {
+				fc.logger.Error("Invalid score values", nil, map[string]interface{}{"intent": intent.Type})
+				continue
+			}
+			params["threshold"] = threshold
+		case models.IntentTypeSentiment:
+			sentiments, ok := intent.Values.([]string)
+			if !ok {
+				fc.logger.Error("Invalid sentiment values", nil, map[string]interface{}{"intent": intent.Type})
+				continue
+			}
+			params["sentiment"] = sentiments
+		case models.IntentTypeRegion:
+			places, ok := intent.Values.([]string)
+			if !ok {
+				fc.logger.Error("Invalid region values", nil, map[string]interface{}{"intent": intent.Type})
+				continue
+			}
+			params["place"] = places
+		case models.IntentTypeExcludeCategory:
+			categories, ok := intent.Values.([]string)
+			if !ok {
+				fc.logger.Error("Invalid category exclusion values", nil, map[string]interface{}{"intent": intent.Type})
+				continue
+			}
+			params["category"] = categories
+		case models.IntentTypeExcludeSource:
+			sources, ok := intent.Values.([]string)
+			if !ok {
+				fc.logger.Error("Invalid source exclusion values", nil, map[string]interface{}{"intent": intent.Type})
+				continue
+			}
+			params["source"] = sources
+		case models.IntentTypeDateRange:
+			from, to, ok := parseDateRangeValues(intent.Values)
+			if !ok {
+				fc.logger.Error("Invalid date range values", nil, map[string]interface{}{"intent": intent.Type})
+				continue
+			}
+			params["from"] = from
+			params["to"] = to
+		}
+
+		plan = append(plan, models.FilterStep{Name: intent.Type, Params: params})
+	}
+
+	// The caller supplied a location but the query had no nearby intent: restrict to it anyway
+	if location != nil && !hasNearbyIntent {
+		plan = append(plan, models.FilterStep{Name: models.IntentTypeNearby, Params: map[string]interface{}{
+			"latitude":  strconv.FormatFloat(location.Latitude, 'f', -1, 64),
+			"longitude": strconv.FormatFloat(location.Longitude, 'f', -1, 64),
+			"radius":    strconv.FormatFloat(fc.queryCfg.DefaultRadiusKm, 'f', -1, 64),
+		}})
+	}
+	// Entities alone are enough to run a search: the text search filter seeds the pipeline
+	if len(plan) > 0 || len(entities) > 0 {
+		threshold := fc.vectorCfg.MinSimilarity
+		if minSimilarity != nil {
+			threshold = *minSimilarity
 		}
+		plan = append(plan,
+			models.FilterStep{Name: models.EntityTypeSearch, Params: map[string]interface{}{"query": entities, "min_similarity": threshold}},
+			models.FilterStep{Name: models.IntentTypeScore, Params: map[string]interface{}{"threshold": 0.1}},
+		)
+	}
+	// Re-rank by the user's preferences once the score filter has ordered by relevance
+	if len(plan) > 0 && len(preferences) > 0 {
+		plan = append(plan, models.FilterStep{Name: filterStepPreferences, Params: map[string]interface{}{"weights": preferences}})
+	}
+	// Collapse near-duplicates last so the kept member of each group is chosen from the final ranking
+	if len(plan) > 0 {
+		plan = append(plan, models.FilterStep{Name: filterStepDedupe, Params: map[string]interface{}{"similarity": fc.dedupeCfg.Similarity}})
+	}
+	return plan
+}
 
-		filter := factory(params)
-		filters = append(filters, filter)
+// nearbyRadius returns the radius in km for a nearby step: the radius from the query clamped
+// to [MinRadiusKm, MaxRadiusKm], or DefaultRadiusKm when the query gave none or it is invalid
+func (fc *FilterChain) nearbyRadius(radius string) float64 {
+	r, err := strconv.ParseFloat(radius, 64)
+	if radius == "" || err != nil || !(r > 0) {
+		return fc.queryCfg.DefaultRadiusKm
 	}
-	if len(filters) > 0 {
-		filters = append(filters, FilterByTextSearch(fc.articleRepo, fc.llmService, entities))
-		filters = append(filters, FilterByScore(fc.articleRepo, 0.1))
+	return math.Max(fc.queryCfg.MinRadiusKm, math.Min(fc.queryCfg.MaxRadiusKm, r))
+}
+
+// confidentIntents drops the intents whose confidence is below the minimum for their type.
+// Their values usually also appear among the entities, so they still feed the text search.
+func (fc *FilterChain) confidentIntents(intents []models.Intent) []models.Intent {
+	confident := make([]models.Intent, 0, len(intents))
+	for _, intent := range intents {
+		if minimum, ok := fc.queryCfg.MinIntentConfidence[intent.Type]; ok && intent.Confidence < minimum {
+			fc.logger.Info("Ignoring low-confidence intent", map[string]interface{}{
+				"intent":         intent.Type,
+				"values":         intent.Values,
+				"confidence":     intent.Confidence,
+				"min_confidence": minimum,
+			})
+			continue
+		}
+		confident = append(confident, intent)
+	}
+	return confident
+}
+
+// build creates the filter for a step of a plan. Intent steps go through the filter registry.
+func (fc *FilterChain) build(step models.FilterStep) Filter {
+	switch step.Name {
+	case filterStepPreferences:
+		weights, _ := step.Params["weights"].(map[string]float64)
+		return RankByPreference(weights)
+	case filterStepDedupe:
+		similarity, _ := step.Params["similarity"].(float64)
+		return FilterDuplicates(fc.articleRepo, similarity)
+	}
+	return fc.filterRegistry[step.Name](step.Params)
+}
+
+// parseThreshold reads a score threshold from intent values, accepting a number, a numeric
+// string or a list whose first element is a numeric string
+func parseThreshold(values interface{}) (float64, bool) {
+	switch v := values.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		threshold, err := strconv.ParseFloat(v, 64)
+		return threshold, err == nil
+	case []string:
+		if len(v) == 0 {
+			return 0, false
+		}
+		threshold, err := strconv.ParseFloat(v[0], 64)
+		return threshold, err == nil
 	}
-	ctx := context.Background()
-	return Chain(ctx, filters...)
+	return 0, false
 }
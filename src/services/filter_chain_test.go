@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/types"
+)
+
+// chainArticleRepo answers every database filter with its articles and records the requests
+type chainArticleRepo struct {
+	repositories.ArticleRepository
+	articles []models.Article
+	filters  []types.FilterArticlesRequest
+	searches [][]string
+	vectors  int
+	pages    int
+}
+
+func (r *chainArticleRepo) FilterArticles(ctx context.Context, params types.FilterArticlesRequest) ([]models.Article, error) {
+	r.filters = append(r.filters, params)
+	return append([]models.Article(nil), r.articles...), nil
+}
+
+func (r *chainArticleRepo) FindPage(ctx context.Context, cursor string, limit int) ([]models.Article, string, error) {
+	r.pages++
+	return append([]models.Article(nil), r.articles...), "", nil
+}
+
+func (r *chainArticleRepo) SearchByText(ctx context.Context, query []string) ([]models.Article, error) {
+	r.searches = append(r.searches, query)
+	return append([]models.Article(nil), r.articles...), nil
+}
+
+func (r *chainArticleRepo) NearestByVector(ctx context.Context, vector []float64, limit int, minSimilarity float64) ([]repositories.VectorNeighbor, error) {
+	r.vectors++
+	return nil, nil
+}
+
+func (r *chainArticleRepo) FindByIDs(ctx context.Context, ids []string) ([]models.Article, error) {
+	return nil, nil
+}
+
+func (r *chainArticleRepo) SimilarityByIDs(ctx context.Context, vector []float64, ids []string) ([]repositories.VectorNeighbor, error) {
+	return nil, nil
+}
+
+func (r *chainArticleRepo) SimilarPairs(ctx context.Context, ids []string, minSimilarity float64) ([]repositories.ArticlePair, error) {
+	return nil, nil
+}
+
+// embeddingLLM embeds every text as the same vector
+type embeddingLLM struct {
+	LLMService
+}
+
+func (embeddingLLM) GenerateEmbedding(ctx context.Context, text string) ([]float64, error) {
+	return []float64{1, 0}, nil
+}
+
+func (embeddingLLM) EmbeddingModel() string {
+	return "test-embedding"
+}
+
+// newTestFilterChain returns a filter chain over repo with a 50km default radius clamped to
+// [5, 500]
+func newTestFilterChain(repo repositories.ArticleRepository) *FilterChain {
+	return NewFilterChain(repo, nil, embeddingLLM{},
+		&infra.QueryConfig{DefaultRadiusKm: 50, MinRadiusKm: 5, MaxRadiusKm: 500, NoIntentLimit: 20},
+		&infra.VectorConfig{SearchLimit: 10, MinSimilarity: 0.5},
+		&infra.DedupeConfig{Similarity: 0.95},
+		infra.NewRecordingLogger(),
+	)
+}
+
+// nearbyRequests returns the database filters of repo that searched around a location
+func nearbyRequests(repo *chainArticleRepo) []types.FilterArticlesRequest {
+	var nearby []types.FilterArticlesRequest
+	for _, req := range repo.filters {
+		if req.Radius > 0 {
+			nearby = append(nearby, req)
+		}
+	}
+	return nearby
+}
+
+func TestExecuteCallerLocation(t *testing.T) {
+	caller := &models.Location{Latitude: 12.97, Longitude: 77.59}
+	nearbyIntent := models.Intent{Type: models.IntentTypeNearby, Values: []string{"18.52", "73.85"}, Confidence: 1}
+	nearbyIntentWithRadius := models.Intent{Type: models.IntentTypeNearby, Values: []string{"18.52", "73.85", "10"}, Confidence: 1}
+
+	tests := []struct {
+		name       string
+		intents    []models.Intent
+		location   *models.Location
+		wantNearby *types.FilterArticlesRequest
+		wantRecent bool
+	}{
+		{
+			name:       "neither location nor intent",
+			wantRecent: true,
+		},
+		{
+			name:       "location only searches around the caller with the default radius",
+			location:   caller,
+			wantNearby: &types.FilterArticlesRequest{Lat: 12.97, Lon: 77.59, Radius: 50},
+		},
+		{
+			name:       "intent only searches around the place",
+			intents:    []models.Intent{nearbyIntent},
+			wantNearby: &types.FilterArticlesRequest{Lat: 18.52, Lon: 73.85, Radius: 50},
+		},
+		{
+			name:       "explicit location wins over the intent",
+			intents:    []models.Intent{nearbyIntent},
+			location:   caller,
+			wantNearby: &types.FilterArticlesRequest{Lat: 12.97, Lon: 77.59, Radius: 50},
+		},
+		{
+			name:       "explicit location keeps the radius of the intent",
+			intents:    []models.Intent{nearbyIntentWithRadius},
+			location:   caller,
+			wantNearby: &types.FilterArticlesRequest{Lat: 12.97, Lon: 77.59, Radius: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &chainArticleRepo{articles: []models.Article{{ID: "a1", Latitude: 12.97, Longitude: 77.59, RelevanceScore: 0.5}}}
+
+			if _, err := newTestFilterChain(repo).Execute(context.Background(), tt.intents, nil, tt.location, nil, nil); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if got := repo.pages > 0; got != tt.wantRecent {
+				t.Errorf("answered from recent articles = %v, want %v", got, tt.wantRecent)
+			}
+
+			nearby := nearbyRequests(repo)
+			if tt.wantNearby == nil {
+				if len(nearby) != 0 {
+					t.Errorf("nearby searches = %+v, want none", nearby)
+				}
+				return
+			}
+			if len(nearby) != 1 {
+				t.Fatalf("nearby searches = %+v, want one", nearby)
+			}
+			if got := nearby[0]; got.Lat != tt.wantNearby.Lat || got.Lon != tt.wantNearby.Lon || got.Radius != tt.wantNearby.Radius {
+				t.Errorf("nearby search at (%v, %v) within %vkm, want (%v, %v) within %vkm",
+					got.Lat, got.Lon, got.Radius, tt.wantNearby.Lat, tt.wantNearby.Lon, tt.wantNearby.Radius)
+			}
+		})
+	}
+}
+
+func TestExecuteIntentTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		intents    []models.Intent
+		entities   []string
+		wantFilter *types.FilterArticlesRequest
+		wantSearch []string
+	}{
+		{
+			name:       "category",
+			intents:    []models.Intent{{Type: models.IntentTypeCategory, Values: []string{"technology"}, Confidence: 1}},
+			wantFilter: &types.FilterArticlesRequest{Category: []string{"technology"}},
+		},
+		{
+			name:       "source",
+			intents:    []models.Intent{{Type: models.IntentTypeSource, Values: []string{"Reuters"}, Confidence: 1}},
+			wantFilter: &types.FilterArticlesRequest{Source: []string{"Reuters"}},
+		},
+		{
+			name:       "nearby without radius",
+			intents:    []models.Intent{{Type: models.IntentTypeNearby, Values: []string{"18.52", "73.85"}, Confidence: 1}},
+			wantFilter: &types.FilterArticlesRequest{Lat: 18.52, Lon: 73.85, Radius: 50},
+		},
+		{
+			name:       "nearby with radius",
+			intents:    []models.Intent{{Type: models.IntentTypeNearby, Values: []string{"18.52", "73.85", "25"}, Confidence: 1}},
+			wantFilter: &types.FilterArticlesRequest{Lat: 18.52, Lon: 73.85, Radius: 25},
+		},
+		{
+			name:       "score",
+			intents:    []models.Intent{{Type: models.IntentTypeScore, Values: 0.8, Confidence: 1}},
+			wantFilter: &types.FilterArticlesRequest{ScoreThreshold: 0.8},
+		},
+		{
+			name:       "score as text",
+			intents:    []models.Intent{{Type: models.IntentTypeScore, Values: []string{"0.6"}, Confidence: 1}},
+			wantFilter: &types.FilterArticlesRequest{ScoreThreshold: 0.6},
+		},
+		{
+			name:       "search",
+			entities:   []string{"ISRO", "Chandrayaan-3"},
+			wantSearch: []string{"ISRO", "Chandrayaan-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &chainArticleRepo{articles: []models.Article{{
+				ID:             "a1",
+				SourceName:     "Reuters",
+				Category:       []string{"technology"},
+				Latitude:       18.52,
+				Longitude:      73.85,
+				RelevanceScore: 0.9,
+			}}}
+
+			results, err := newTestFilterChain(repo).Execute(context.Background(), tt.intents, tt.entities, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Errorf("got %d results, want 1", len(results))
+			}
+
+			if tt.wantSearch != nil {
+				if len(repo.searches) != 1 || !slices.Equal(repo.searches[0], tt.wantSearch) {
+					t.Errorf("text searches = %v, want [%v]", repo.searches, tt.wantSearch)
+				}
+				return
+			}
+
+			// The intent's filter runs first, so it is the one seeding the pipeline
+			if len(repo.filters) == 0 {
+				t.Fatal("no database filter was run")
+			}
+			got := repo.filters[0]
+			if !slices.Equal(got.Category, tt.wantFilter.Category) || !slices.Equal(got.Source, tt.wantFilter.Source) ||
+				got.Lat != tt.wantFilter.Lat || got.Lon != tt.wantFilter.Lon || got.Radius != tt.wantFilter.Radius ||
+				got.ScoreThreshold != tt.wantFilter.ScoreThreshold {
+				t.Errorf("database filter = %+v, want %+v", got, *tt.wantFilter)
+			}
+		})
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
 
+	"news-inshorts/src/infra"
 	"news-inshorts/src/models"
 	"news-inshorts/src/repositories"
 	"news-inshorts/src/types"
-	"news-inshorts/src/utils"
 )
 
 // FilterByCategory creates a filter that filters articles by category
@@ -35,11 +37,149 @@ func FilterByCategory(repo repositories.ArticleRepository, categories []string)
 				}
 			}
 		} else {
-			dbResults, err := repo.FilterArticles(types.FilterArticlesRequest{
-				Category: strings.Join(categories, ","),
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+				Category: categories,
 			})
 			if err != nil {
-				return nil, fmt.Errorf("category filter failed: %w", err)
+				return nil, err
+			}
+			filteredArticles = dbResults
+		}
+
+		for _, article := range filteredArticles {
+			matched := []string{}
+			for _, articleCategory := range article.Category {
+				if slices.Contains(categories, articleCategory) {
+					matched = append(matched, articleCategory)
+				}
+			}
+			recordMatch(ctx, article.ID, func(info *models.MatchInfo) {
+				info.MatchedCategories = matched
+			})
+		}
+
+		return &filteredArticles, nil
+	}
+}
+
+// FilterBySource creates a filter that filters articles by source name. Sources that are
+// aliases are expanded to their source names first.
+func FilterBySource(repo repositories.ArticleRepository, aliases repositories.SourceAliasRepository, sources []string) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		if len(sources) == 0 {
+			return in, nil
+		}
+
+		articles := *in
+		filteredArticles := []models.Article{}
+
+		if len(articles) > 0 {
+			exact, partial, err := aliases.Expand(ctx, sources)
+			if err != nil {
+				return nil, err
+			}
+			for _, article := range articles {
+				if matchesSource(article.SourceName, exact, partial) {
+					filteredArticles = append(filteredArticles, article)
+				}
+			}
+		} else {
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+				Source: sources,
+			})
+			if err != nil {
+				return nil, err
+			}
+			filteredArticles = dbResults
+		}
+
+		for _, article := range filteredArticles {
+			source := article.SourceName
+			recordMatch(ctx, article.ID, func(info *models.MatchInfo) {
+				info.MatchedSources = []string{source}
+			})
+		}
+
+		return &filteredArticles, nil
+	}
+}
+
+// matchesSource reports whether articleSource is one of the exact source names or contains
+// any of the partial ones, ignoring case. This mirrors the matching of the database path.
+func matchesSource(articleSource string, exact, partial []string) bool {
+	if slices.ContainsFunc(exact, func(name string) bool { return strings.EqualFold(name, articleSource) }) {
+		return true
+	}
+
+	source := strings.ToLower(articleSource)
+	for _, w := range partial {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w != "" && strings.Contains(source, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByRegion creates a filter that keeps articles whose country or region is any of the
+// places, ignoring case
+func FilterByRegion(repo repositories.ArticleRepository, places []string) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		if len(places) == 0 {
+			return in, nil
+		}
+
+		articles := *in
+		filteredArticles := []models.Article{}
+
+		if len(articles) > 0 {
+			for _, article := range articles {
+				matches := slices.ContainsFunc(places, func(place string) bool {
+					return strings.EqualFold(place, article.Country) || strings.EqualFold(place, article.Region)
+				})
+				if matches {
+					filteredArticles = append(filteredArticles, article)
+				}
+			}
+		} else {
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+				Place: places,
+			})
+			if err != nil {
+				return nil, err
+			}
+			filteredArticles = dbResults
+		}
+
+		return &filteredArticles, nil
+	}
+}
+
+// ExcludeByCategory creates a filter that drops articles in any of the categories
+func ExcludeByCategory(repo repositories.ArticleRepository, categories []string) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		if len(categories) == 0 {
+			return in, nil
+		}
+
+		articles := *in
+		filteredArticles := []models.Article{}
+
+		if len(articles) > 0 {
+			for _, article := range articles {
+				excluded := slices.ContainsFunc(article.Category, func(category string) bool {
+					return slices.Contains(categories, category)
+				})
+				if !excluded {
+					filteredArticles = append(filteredArticles, article)
+				}
+			}
+		} else {
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+				ExcludeCategory: categories,
+			})
+			if err != nil {
+				return nil, err
 			}
 			filteredArticles = dbResults
 		}
@@ -48,8 +188,9 @@ func FilterByCategory(repo repositories.ArticleRepository, categories []string)
 	}
 }
 
-// FilterBySource creates a filter that filters articles by source name
-func FilterBySource(repo repositories.ArticleRepository, sources []string) Filter {
+// ExcludeBySource creates a filter that drops articles whose source name contains any of
+// the sources, ignoring case. Sources that are aliases drop their source names.
+func ExcludeBySource(repo repositories.ArticleRepository, aliases repositories.SourceAliasRepository, sources []string) Filter {
 	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
 		if len(sources) == 0 {
 			return in, nil
@@ -59,17 +200,21 @@ func FilterBySource(repo repositories.ArticleRepository, sources []string) Filte
 		filteredArticles := []models.Article{}
 
 		if len(articles) > 0 {
+			exact, partial, err := aliases.Expand(ctx, sources)
+			if err != nil {
+				return nil, err
+			}
 			for _, article := range articles {
-				if slices.Contains(sources, article.SourceName) {
+				if !matchesSource(article.SourceName, exact, partial) {
 					filteredArticles = append(filteredArticles, article)
 				}
 			}
 		} else {
-			dbResults, err := repo.FilterArticles(types.FilterArticlesRequest{
-				Source: utils.FormatStringsForLikeQuery(sources),
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+				ExcludeSource: sources,
 			})
 			if err != nil {
-				return nil, fmt.Errorf("source filter failed: %w", err)
+				return nil, err
 			}
 			filteredArticles = dbResults
 		}
@@ -90,15 +235,15 @@ func FilterByScore(repo repositories.ArticleRepository, threshold float64) Filte
 					filteredArticles = append(filteredArticles, article)
 				}
 			}
-			sort.Slice(filteredArticles, func(i, j int) bool {
-				return articles[i].RelevanceScore > articles[j].RelevanceScore
+			sort.SliceStable(filteredArticles, func(i, j int) bool {
+				return filteredArticles[i].RelevanceScore > filteredArticles[j].RelevanceScore
 			})
 		} else {
-			dbResults, err := repo.FilterArticles(types.FilterArticlesRequest{
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
 				ScoreThreshold: threshold,
 			})
 			if err != nil {
-				return nil, fmt.Errorf("score filter failed: %w", err)
+				return nil, err
 			}
 			filteredArticles = dbResults
 		}
@@ -107,63 +252,154 @@ func FilterByScore(repo repositories.ArticleRepository, threshold float64) Filte
 	}
 }
 
-// FilterByTextSearch creates a filter that filters articles using cosine similarity search
-func FilterByTextSearch(repo repositories.ArticleRepository, llmService LLMService, query []string) Filter {
+// FilterByTextSearch creates a filter that ranks articles by cosine similarity to the query and
+// drops those less than minSimilarity similar. When it runs first in the chain, the pipeline is
+// seeded from a database text search plus the neighborLimit nearest neighbors found through the
+// vector index. Similarities are computed in memory for articles that carry their vector and
+// in the database otherwise.
+func FilterByTextSearch(repo repositories.ArticleRepository, llmService LLMService, query []string, neighborLimit int, minSimilarity float64, logger infra.Logger) Filter {
 	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
 		if len(query) == 0 {
 			return in, nil
 		}
 
-		articles := *in
-		if len(articles) == 0 {
-			return in, nil
-		}
-
 		// Join query strings into a single query string
 		queryString := strings.Join(query, " ")
-		if queryString == "" {
+		if strings.TrimSpace(queryString) == "" {
 			return in, nil
 		}
 
-		// Generate embedding for the query
-		queryVector, err := llmService.GenerateEmbedding(queryString)
+		// Generate embedding for the query, or reuse the one a relaxed retry's first run generated
+		queryVector, err := queryEmbedding(ctx, llmService, queryString)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 		}
 
-		// Calculate cosine similarity for each article with DescriptionVector
+		articles := *in
+		similarities := map[string]float64{}
+		textMatched := map[string]bool{}
+		seeded := false
+		if len(articles) == 0 {
+			dbResults, err := repo.SearchByText(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+			for _, article := range dbResults {
+				textMatched[article.ID] = true
+			}
+
+			neighbors, err := repo.NearestByVector(ctx, queryVector, neighborLimit, minSimilarity)
+			if err != nil {
+				return nil, err
+			}
+			semanticIDs := []string{}
+			for _, neighbor := range neighbors {
+				similarities[neighbor.ID] = neighbor.Similarity
+				if !textMatched[neighbor.ID] {
+					semanticIDs = append(semanticIDs, neighbor.ID)
+				}
+			}
+
+			// Close neighbors that do not contain the query text are matches too
+			semanticMatches, err := repo.FindByIDs(ctx, semanticIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			articles = append(dbResults, semanticMatches...)
+			seeded = true
+		}
+
+		// Articles loaded without their vector are compared in the database
+		missing := []string{}
+		for _, article := range articles {
+			if _, ok := similarities[article.ID]; !ok && len(article.DescriptionVector) == 0 {
+				missing = append(missing, article.ID)
+			}
+		}
+		if len(missing) > 0 {
+			computed, err := repo.SimilarityByIDs(ctx, queryVector, missing)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range computed {
+				similarities[c.ID] = c.Similarity
+			}
+		}
+
 		type articleWithSimilarity struct {
 			article    models.Article
 			similarity float64
 		}
 
 		articlesWithSimilarity := make([]articleWithSimilarity, 0, len(articles))
+		unranked := []models.Article{}
+		queryModel := llmService.EmbeddingModel()
+		mismatched := 0
+		belowThreshold := 0
 
 		for _, article := range articles {
-			// Skip articles without DescriptionVector
-			if len(article.DescriptionVector) == 0 {
-				continue
+			similarity, found := similarities[article.ID]
+			if !found && len(article.DescriptionVector) > 0 {
+				similarity = cosineSimilarity(queryVector, article.DescriptionVector)
+				found = true
 			}
 
-			// Calculate cosine similarity
-			similarity := cosineSimilarity(queryVector, article.DescriptionVector)
+			// Vectors from a different model live in a different space, so comparing them with
+			// the query vector is meaningless. Rows with no recorded model predate tracking and
+			// are assumed to match.
+			stale := article.EmbeddingModel != "" && article.EmbeddingModel != queryModel
+			if stale && found {
+				mismatched++
+			}
+
+			switch {
+			case !found || stale:
+				// Without a usable similarity, database text matches are kept unranked since
+				// they already matched the query text
+				if textMatched[article.ID] {
+					unranked = append(unranked, article)
+				}
+			case similarity < minSimilarity:
+				belowThreshold++
+			default:
+				articlesWithSimilarity = append(articlesWithSimilarity, articleWithSimilarity{
+					article:    article,
+					similarity: similarity,
+				})
+			}
+		}
 
-			articlesWithSimilarity = append(articlesWithSimilarity, articleWithSimilarity{
-				article:    article,
-				similarity: similarity,
+		if mismatched > 0 {
+			logger.Warn("Skipped article embeddings from a different model", map[string]interface{}{
+				"query_model": queryModel,
+				"count":       mismatched,
 			})
 		}
 
+		logger.Debug("Semantic search ranked articles", map[string]interface{}{
+			"seeded":          seeded,
+			"ranked":          len(articlesWithSimilarity),
+			"unranked":        len(unranked),
+			"below_threshold": belowThreshold,
+			"min_similarity":  minSimilarity,
+		})
+
 		// Sort by similarity score (descending)
 		sort.Slice(articlesWithSimilarity, func(i, j int) bool {
 			return articlesWithSimilarity[i].similarity > articlesWithSimilarity[j].similarity
 		})
 
 		// Extract articles in sorted order
-		filteredArticles := make([]models.Article, 0, len(articlesWithSimilarity))
+		filteredArticles := make([]models.Article, 0, len(articlesWithSimilarity)+len(unranked))
 		for _, aws := range articlesWithSimilarity {
 			filteredArticles = append(filteredArticles, aws.article)
+			similarity := aws.similarity
+			recordMatch(ctx, aws.article.ID, func(info *models.MatchInfo) {
+				info.Similarity = &similarity
+			})
 		}
+		filteredArticles = append(filteredArticles, unranked...)
 
 		return &filteredArticles, nil
 	}
@@ -211,26 +447,323 @@ func FilterByRadius(repo repositories.ArticleRepository, lat, lon, radius float6
 				}
 			}
 			sort.Slice(filteredArticles, func(i, j int) bool {
-				distI := haversineDistance(lat, lon, articles[i].Latitude, articles[i].Longitude)
-				distJ := haversineDistance(lat, lon, articles[j].Latitude, articles[j].Longitude)
+				distI := haversineDistance(lat, lon, filteredArticles[i].Latitude, filteredArticles[i].Longitude)
+				distJ := haversineDistance(lat, lon, filteredArticles[j].Latitude, filteredArticles[j].Longitude)
 				return distI < distJ
 			})
 		} else {
-			nearbyResults, err := repo.FilterArticles(types.FilterArticlesRequest{
+			nearbyResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
 				Lat:    lat,
 				Lon:    lon,
 				Radius: radius,
 			})
 			if err != nil {
-				return nil, fmt.Errorf("nearby filter failed: %w", err)
+				return nil, err
 			}
 			filteredArticles = nearbyResults
 		}
 
+		for _, article := range filteredArticles {
+			distance := haversineDistance(lat, lon, article.Latitude, article.Longitude)
+			recordMatch(ctx, article.ID, func(info *models.MatchInfo) {
+				info.DistanceKm = &distance
+			})
+		}
+
 		return &filteredArticles, nil
 	}
 }
 
+// FilterByMultiRadius creates a filter that keeps articles within radius km of any of points,
+// ordered by the distance to the nearest one. Without input articles each point is searched
+// in the database and the results are merged.
+func FilterByMultiRadius(repo repositories.ArticleRepository, points []models.Location, radius float64) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		if len(points) == 0 {
+			return in, nil
+		}
+
+		candidates := *in
+		if len(candidates) == 0 {
+			seen := make(map[string]bool)
+			for _, point := range points {
+				nearbyResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+					Lat:    point.Latitude,
+					Lon:    point.Longitude,
+					Radius: radius,
+				})
+				if err != nil {
+					return nil, err
+				}
+				for _, article := range nearbyResults {
+					if !seen[article.ID] {
+						seen[article.ID] = true
+						candidates = append(candidates, article)
+					}
+				}
+			}
+		}
+
+		nearest := make(map[string]float64, len(candidates))
+		filteredArticles := []models.Article{}
+		for _, article := range candidates {
+			distance := math.Inf(1)
+			for _, point := range points {
+				distance = math.Min(distance, haversineDistance(point.Latitude, point.Longitude, article.Latitude, article.Longitude))
+			}
+			if distance <= radius {
+				nearest[article.ID] = distance
+				filteredArticles = append(filteredArticles, article)
+			}
+		}
+
+		sort.SliceStable(filteredArticles, func(i, j int) bool {
+			return nearest[filteredArticles[i].ID] < nearest[filteredArticles[j].ID]
+		})
+
+		for _, article := range filteredArticles {
+			distance := nearest[article.ID]
+			recordMatch(ctx, article.ID, func(info *models.MatchInfo) {
+				info.DistanceKm = &distance
+			})
+		}
+
+		return &filteredArticles, nil
+	}
+}
+
+// FilterBySentiment creates a filter that keeps articles classified with one of sentiments.
+// Articles that were never classified do not match.
+func FilterBySentiment(repo repositories.ArticleRepository, sentiments []string) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		if len(sentiments) == 0 {
+			return in, nil
+		}
+
+		articles := *in
+		filteredArticles := []models.Article{}
+
+		if len(articles) > 0 {
+			for _, article := range articles {
+				if article.Sentiment != nil && slices.Contains(sentiments, *article.Sentiment) {
+					filteredArticles = append(filteredArticles, article)
+				}
+			}
+		} else {
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+				Sentiment: strings.Join(sentiments, ","),
+			})
+			if err != nil {
+				return nil, err
+			}
+			filteredArticles = dbResults
+		}
+
+		return &filteredArticles, nil
+	}
+}
+
+// FilterByDateRange creates a filter that keeps articles published in [from, to). A zero
+// bound leaves that side of the window open.
+func FilterByDateRange(repo repositories.ArticleRepository, from, to time.Time) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		if from.IsZero() && to.IsZero() {
+			return in, nil
+		}
+
+		articles := *in
+		filteredArticles := []models.Article{}
+
+		if len(articles) > 0 {
+			for _, article := range articles {
+				if !from.IsZero() && article.PublicationDate.Before(from) {
+					continue
+				}
+				if !to.IsZero() && !article.PublicationDate.Before(to) {
+					continue
+				}
+				filteredArticles = append(filteredArticles, article)
+			}
+		} else {
+			dbResults, err := repo.FilterArticles(ctx, types.FilterArticlesRequest{
+				PublishedFrom: from,
+				PublishedTo:   to,
+			})
+			if err != nil {
+				return nil, err
+			}
+			filteredArticles = dbResults
+		}
+
+		return &filteredArticles, nil
+	}
+}
+
+// RankByPreference creates a ranking step that re-orders articles by relevance score scaled by
+// the user's category weights (-1 to 1, keyed by lowercased category). The weights of an
+// article's categories are averaged, with categories the user did not weigh counting as 0, and
+// the score is multiplied by 2^average: a fully preferred article counts double, a fully
+// disliked one half, and articles in unweighted categories keep their score. No article is
+// removed, and the stored relevance scores are left unchanged.
+func RankByPreference(weights map[string]float64) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		articles := *in
+		if len(weights) == 0 || len(articles) == 0 {
+			return in, nil
+		}
+
+		boosts := make(map[string]float64, len(articles))
+		for _, article := range articles {
+			boost := preferenceBoost(article.Category, weights)
+			boosts[article.ID] = boost
+			if boost != 1 {
+				recordMatch(ctx, article.ID, func(info *models.MatchInfo) {
+					rounded := math.Round(boost*10000) / 10000
+					info.PreferenceBoost = &rounded
+				})
+			}
+		}
+
+		ranked := slices.Clone(articles)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ranked[i].RelevanceScore*boosts[ranked[i].ID] > ranked[j].RelevanceScore*boosts[ranked[j].ID]
+		})
+
+		return &ranked, nil
+	}
+}
+
+// preferenceBoost returns the factor RankByPreference scales an article's score by
+func preferenceBoost(categories []string, weights map[string]float64) float64 {
+	if len(categories) == 0 {
+		return 1
+	}
+
+	sum := 0.0
+	for _, category := range categories {
+		sum += weights[strings.ToLower(category)]
+	}
+	return math.Pow(2, sum/float64(len(categories)))
+}
+
+// duplicateWindow caps how many leading articles FilterDuplicates compares, since comparing
+// every pair grows quadratically. Later articles lie beyond any response limit.
+const duplicateWindow = 200
+
+// FilterDuplicates creates a filter that collapses near-duplicate articles, such as the same
+// wire story published by several sources. Articles are duplicates when their embeddings are
+// at least minSimilarity similar or their normalized titles match; articles without vectors
+// are compared by title only. Each group is replaced, at the position of its first member, by
+// its highest-relevance member, which lists the other members' sources as also_reported_by.
+func FilterDuplicates(repo repositories.ArticleRepository, minSimilarity float64) Filter {
+	return func(ctx context.Context, in *[]models.Article) (*[]models.Article, error) {
+		articles := *in
+		if len(articles) < 2 {
+			return in, nil
+		}
+
+		window := articles[:min(len(articles), duplicateWindow)]
+
+		// parent links each article to another member of its group (union-find)
+		parent := make([]int, len(window))
+		index := make(map[string]int, len(window))
+		byTitle := make(map[string]int, len(window))
+		ids := make([]string, 0, len(window))
+		for i, article := range window {
+			parent[i] = i
+			index[article.ID] = i
+			ids = append(ids, article.ID)
+
+			title := normalizeTitle(article.Title)
+			if title == "" {
+				continue
+			}
+			if j, ok := byTitle[title]; ok {
+				unionGroups(parent, i, j)
+			} else {
+				byTitle[title] = i
+			}
+		}
+
+		pairs, err := repo.SimilarPairs(ctx, ids, minSimilarity)
+		if err != nil {
+			return nil, err
+		}
+		for _, pair := range pairs {
+			first, ok1 := index[pair.FirstID]
+			second, ok2 := index[pair.SecondID]
+			if ok1 && ok2 {
+				unionGroups(parent, first, second)
+			}
+		}
+
+		// Collect the members of each group in rank order
+		groups := make(map[int][]int)
+		roots := []int{}
+		for i := range window {
+			root := findGroup(parent, i)
+			if _, ok := groups[root]; !ok {
+				roots = append(roots, root)
+			}
+			groups[root] = append(groups[root], i)
+		}
+
+		filteredArticles := make([]models.Article, 0, len(articles))
+		for _, root := range roots {
+			members := groups[root]
+			best := members[0]
+			for _, i := range members[1:] {
+				if window[i].RelevanceScore > window[best].RelevanceScore {
+					best = i
+				}
+			}
+			filteredArticles = append(filteredArticles, window[best])
+
+			if len(members) == 1 {
+				continue
+			}
+			sources := []string{}
+			for _, i := range members {
+				source := window[i].SourceName
+				if i != best && source != window[best].SourceName && !slices.Contains(sources, source) {
+					sources = append(sources, source)
+				}
+			}
+			recordMatch(ctx, window[best].ID, func(info *models.MatchInfo) {
+				info.AlsoReportedBy = sources
+			})
+		}
+		filteredArticles = append(filteredArticles, articles[len(window):]...)
+
+		return &filteredArticles, nil
+	}
+}
+
+// normalizeTitle lowercases a title and reduces it to its words, so titles differing only in
+// case, punctuation or spacing compare equal
+func normalizeTitle(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// findGroup returns the representative of the union-find group containing i
+func findGroup(parent []int, i int) int {
+	for parent[i] != i {
+		parent[i] = parent[parent[i]]
+		i = parent[i]
+	}
+	return i
+}
+
+// unionGroups merges the union-find groups containing i and j
+func unionGroups(parent []int, i, j int) {
+	if ri, rj := findGroup(parent, i), findGroup(parent, j); ri != rj {
+		parent[rj] = ri
+	}
+}
+
 // haversineDistance calculates the distance between two geographic coordinates in kilometers
 func haversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	const earthRadiusKm = 6371.0
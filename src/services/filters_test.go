@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+func TestFilterByTextSearchSeedsEmptyPipeline(t *testing.T) {
+	stored := []models.Article{{ID: "a1", Title: "ISRO launches Chandrayaan-3"}}
+
+	tests := []struct {
+		name         string
+		in           []models.Article
+		query        []string
+		wantSearched bool
+		wantIDs      []string
+	}{
+		{name: "empty pipeline searches the database", query: []string{"ISRO"}, wantSearched: true, wantIDs: []string{"a1"}},
+		{name: "articles in the pipeline are ranked without a search", in: []models.Article{{ID: "a2"}}, query: []string{"ISRO"}},
+		{name: "no query leaves the pipeline alone", wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &chainArticleRepo{articles: stored}
+			in := append([]models.Article{}, tt.in...)
+
+			out, err := FilterByTextSearch(repo, embeddingLLM{}, tt.query, 10, 0.5, infra.NewRecordingLogger())(context.Background(), &in)
+			if err != nil {
+				t.Fatalf("filter failed: %v", err)
+			}
+
+			searched := len(repo.searches) > 0 && repo.vectors > 0
+			if searched != tt.wantSearched {
+				t.Errorf("searched the database = %v, want %v (text searches %v, vector searches %d)", searched, tt.wantSearched, repo.searches, repo.vectors)
+			}
+			ids := make([]string, 0, len(*out))
+			for _, article := range *out {
+				ids = append(ids, article.ID)
+			}
+			if tt.wantIDs != nil && !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("articles = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// TestExecuteEntitiesOnly checks that a query whose only signal is its entities is answered
+// by a database search rather than from the latest articles
+func TestExecuteEntitiesOnly(t *testing.T) {
+	repo := &chainArticleRepo{articles: []models.Article{{ID: "a1", Title: "ISRO launches Chandrayaan-3", RelevanceScore: 0.9}}}
+
+	results, err := newTestFilterChain(repo).Execute(context.Background(), nil, []string{"ISRO"}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(repo.searches) != 1 || !slices.Equal(repo.searches[0], []string{"ISRO"}) {
+		t.Errorf("text searches = %v, want [[ISRO]]", repo.searches)
+	}
+	if repo.vectors != 1 {
+		t.Errorf("vector searches = %d, want 1", repo.vectors)
+	}
+	if repo.pages != 0 || len(repo.filters) != 0 {
+		t.Errorf("read %d pages and ran %d filters, want neither", repo.pages, len(repo.filters))
+	}
+	if len(results) != 1 || results[0].ID != "a1" {
+		t.Errorf("results = %+v, want a1", results)
+	}
+}
+
+func TestMatchesSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		exact   []string
+		partial []string
+		want    bool
+	}{
+		{name: "exact name", source: "Reuters", exact: []string{"Reuters"}, want: true},
+		{name: "exact name in another case", source: "REUTERS", exact: []string{"reuters"}, want: true},
+		{name: "exact name does not match a part", source: "Reuters India", exact: []string{"Reuters"}, want: false},
+		{name: "exact name does not match a longer name", source: "Reuters", exact: []string{"Reuters India"}, want: false},
+		{name: "partial word inside the name", source: "The Hindu BusinessLine", partial: []string{"hindu"}, want: true},
+		{name: "partial word in another case", source: "the hindu", partial: []string{"HINDU"}, want: true},
+		{name: "partial word is trimmed", source: "Times of India", partial: []string{"  times "}, want: true},
+		{name: "blank partial word matches nothing", source: "Times of India", partial: []string{" "}, want: false},
+		{name: "partial word not in the name", source: "Times of India", partial: []string{"hindu"}, want: false},
+		{name: "any of several names", source: "NDTV", exact: []string{"Reuters", "ndtv"}, partial: []string{"hindu"}, want: true},
+		{name: "no names", source: "Reuters", want: false},
+		{name: "empty source", source: "", exact: []string{"Reuters"}, partial: []string{"reuters"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesSource(tt.source, tt.exact, tt.partial); got != tt.want {
+				t.Errorf("matchesSource(%q, %q, %q) = %v, want %v", tt.source, tt.exact, tt.partial, got, tt.want)
+			}
+		})
+	}
+}
+
+// noAliases knows no source aliases, so every requested source matches by name
+type noAliases struct {
+	repositories.SourceAliasRepository
+}
+
+func (noAliases) Expand(ctx context.Context, sources []string) ([]string, []string, error) {
+	return nil, sources, nil
+}
+
+// TestIncludeAndExcludeFiltersCombine runs the include and exclude filters over the same
+// articles in either order and checks only articles passing all of them are kept
+func TestIncludeAndExcludeFiltersCombine(t *testing.T) {
+	articles := []models.Article{
+		{ID: "tech-reuters", SourceName: "Reuters", Category: []string{"technology"}},
+		{ID: "tech-sports-reuters", SourceName: "Reuters", Category: []string{"technology", "sports"}},
+		{ID: "science-tabloid", SourceName: "Daily Tabloid", Category: []string{"science"}},
+		{ID: "science-reuters-india", SourceName: "Reuters India", Category: []string{"science"}},
+		{ID: "science-reuters-tabloid", SourceName: "Reuters Tabloid Desk", Category: []string{"science"}},
+		{ID: "politics-reuters", SourceName: "Reuters", Category: []string{"politics"}},
+	}
+
+	repo := &chainArticleRepo{}
+	include := []Filter{
+		FilterByCategory(repo, []string{"technology", "science"}),
+		FilterBySource(repo, noAliases{}, []string{"reuters"}),
+	}
+	exclude := []Filter{
+		ExcludeByCategory(repo, []string{"sports"}),
+		ExcludeBySource(repo, noAliases{}, []string{"tabloid"}),
+	}
+
+	tests := []struct {
+		name    string
+		filters []Filter
+		want    []string
+	}{
+		{name: "includes only", filters: include, want: []string{"tech-reuters", "tech-sports-reuters", "science-reuters-india", "science-reuters-tabloid"}},
+		{name: "excludes only", filters: exclude, want: []string{"tech-reuters", "science-reuters-india", "politics-reuters"}},
+		{name: "includes then excludes", filters: append(slices.Clone(include), exclude...), want: []string{"tech-reuters", "science-reuters-india"}},
+		{name: "excludes then includes", filters: append(slices.Clone(exclude), include...), want: []string{"tech-reuters", "science-reuters-india"}},
+		{name: "interleaved", filters: []Filter{exclude[1], include[0], exclude[0], include[1]}, want: []string{"tech-reuters", "science-reuters-india"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := append([]models.Article{}, articles...)
+			pipeline := &out
+			for _, filter := range tt.filters {
+				var err error
+				if pipeline, err = filter(context.Background(), pipeline); err != nil {
+					t.Fatalf("filter failed: %v", err)
+				}
+			}
+
+			ids := []string{}
+			for _, article := range *pipeline {
+				ids = append(ids, article.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("articles = %v, want %v", ids, tt.want)
+			}
+			if len(repo.filters) != 0 {
+				t.Errorf("ran %d database filters, want none", len(repo.filters))
+			}
+		})
+	}
+}
+
+// TestRankByPreferenceOppositeUsers runs the same query for two users with opposite
+// preferences through one filter chain and checks each gets its own ordering
+func TestRankByPreferenceOppositeUsers(t *testing.T) {
+	repo := &chainArticleRepo{articles: []models.Article{
+		{ID: "sports", Category: []string{"sports"}, RelevanceScore: 0.8},
+		{ID: "technology", Category: []string{"technology"}, RelevanceScore: 0.7},
+		{ID: "politics", Category: []string{"politics"}, RelevanceScore: 0.6},
+	}}
+	chain := newTestFilterChain(repo)
+
+	tests := []struct {
+		name    string
+		weights map[string]float64
+		want    []string
+	}{
+		{name: "sports fan", weights: map[string]float64{"sports": 1, "technology": -1}, want: []string{"sports", "politics", "technology"}},
+		{name: "technology fan", weights: map[string]float64{"sports": -1, "technology": 1}, want: []string{"technology", "politics", "sports"}},
+		{name: "no preferences", want: []string{"sports", "technology", "politics"}},
+		{name: "sports fan again", weights: map[string]float64{"sports": 1, "technology": -1}, want: []string{"sports", "politics", "technology"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := chain.Execute(context.Background(), nil, nil, nil, nil, tt.weights)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			ids := make([]string, 0, len(results))
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("order = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	// Ranking must not rewrite the stored scores the next user is ranked from
+	if got := repo.articles[0].RelevanceScore; got != 0.8 {
+		t.Errorf("stored relevance score = %v, want 0.8", got)
+	}
+}
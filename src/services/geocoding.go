@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Geocoding provider names
+const (
+	GeocoderProviderNone      = "none"
+	GeocoderProviderNominatim = "nominatim"
+	GeocoderProviderOpenCage  = "opencage"
+)
+
+// ErrPlaceNotFound is returned when the geocoding provider has no match for a place name
+var ErrPlaceNotFound = errors.New("place not found")
+
+// GeocodingService defines the interface for resolving place names to coordinates
+type GeocodingService interface {
+	Geocode(ctx context.Context, place string) (*models.Location, error)
+	// ReverseGeocode returns the country and region containing the coordinates
+	ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Place, error)
+}
+
+// geocodingService implements GeocodingService against Nominatim or OpenCage with a Redis cache
+type geocodingService struct {
+	config      *infra.GeocodingConfig
+	httpClient  *http.Client
+	redisClient *redis.Client
+	logger      infra.Logger
+}
+
+// NewGeocodingService creates a new geocoding service, or returns nil when geocoding is disabled
+func NewGeocodingService(cfg *infra.GeocodingConfig, redisClient *redis.Client, logger infra.Logger) GeocodingService {
+	if cfg.Provider == GeocoderProviderNone {
+		return nil
+	}
+
+	return &geocodingService{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+// Geocode resolves a place name to coordinates, consulting the Redis cache first
+func (s *geocodingService) Geocode(ctx context.Context, place string) (*models.Location, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(place), " "))
+	if normalized == "" {
+		return nil, ErrPlaceNotFound
+	}
+
+	cacheKey := infra.GeocodeCacheKey(s.config.Provider, normalized)
+
+	if val, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
+		var location models.Location
+		if err := json.Unmarshal([]byte(val), &location); err == nil {
+			return &location, nil
+		}
+	} else if err != redis.Nil {
+		s.logger.Warn("Failed to read geocoding cache", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+	}
+
+	var location *models.Location
+	var err error
+	switch s.config.Provider {
+	case GeocoderProviderOpenCage:
+		location, err = s.geocodeOpenCage(ctx, normalized)
+	default:
+		location, err = s.geocodeNominatim(ctx, normalized)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(location); err == nil {
+		if err := s.redisClient.Set(ctx, cacheKey, data, s.config.CacheTTL).Err(); err != nil {
+			s.logger.Warn("Failed to cache geocoding result", map[string]interface{}{
+				"cache_key": cacheKey,
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	s.logger.Debug("Geocoded place", map[string]interface{}{
+		"place":     place,
+		"latitude":  location.Latitude,
+		"longitude": location.Longitude,
+	})
+
+	return location, nil
+}
+
+// ReverseGeocode resolves coordinates to their country and region, consulting the Redis cache
+// first. Articles from the same area repeat coordinates, so results are cached for the
+// geocoding cache TTL under coordinates rounded to about 1 km, including empty results.
+func (s *geocodingService) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Place, error) {
+	cacheKey := infra.ReverseGeocodeCacheKey(s.config.Provider, lat, lon)
+
+	if val, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
+		var place models.Place
+		if err := json.Unmarshal([]byte(val), &place); err == nil {
+			return &place, nil
+		}
+	} else if err != redis.Nil {
+		s.logger.Warn("Failed to read geocoding cache", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+	}
+
+	var place *models.Place
+	var err error
+	switch s.config.Provider {
+	case GeocoderProviderOpenCage:
+		place, err = s.reverseOpenCage(ctx, lat, lon)
+	default:
+		place, err = s.reverseNominatim(ctx, lat, lon)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(place); err == nil {
+		if err := s.redisClient.Set(ctx, cacheKey, data, s.config.CacheTTL).Err(); err != nil {
+			s.logger.Warn("Failed to cache reverse geocoding result", map[string]interface{}{
+				"cache_key": cacheKey,
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	s.logger.Debug("Reverse geocoded location", map[string]interface{}{
+		"latitude":  lat,
+		"longitude": lon,
+		"country":   place.Country,
+		"region":    place.Region,
+	})
+
+	return place, nil
+}
+
+// geocodeNominatim resolves a place through the Nominatim search API
+func (s *geocodingService) geocodeNominatim(ctx context.Context, place string) (*models.Location, error) {
+	params := url.Values{}
+	params.Set("q", place)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+
+	body, err := s.get(ctx, fmt.Sprintf("%s/search?%s", s.config.APIURL, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal geocoding response: %w", err)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrPlaceNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude in geocoding response: %w", err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude in geocoding response: %w", err)
+	}
+
+	return &models.Location{Latitude: lat, Longitude: lon}, nil
+}
+
+// geocodeOpenCage resolves a place through the OpenCage geocoding API
+func (s *geocodingService) geocodeOpenCage(ctx context.Context, place string) (*models.Location, error) {
+	params := url.Values{}
+	params.Set("q", place)
+	params.Set("key", s.config.APIKey)
+	params.Set("limit", "1")
+	params.Set("no_annotations", "1")
+
+	body, err := s.get(ctx, fmt.Sprintf("%s/geocode/v1/json?%s", s.config.APIURL, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Results []struct {
+			Geometry struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal geocoding response: %w", err)
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, ErrPlaceNotFound
+	}
+
+	return &models.Location{
+		Latitude:  resp.Results[0].Geometry.Lat,
+		Longitude: resp.Results[0].Geometry.Lng,
+	}, nil
+}
+
+// reverseNominatim resolves coordinates through the Nominatim reverse API. Zoom 5 asks for
+// state-level detail; names are requested in English so filters see one spelling.
+func (s *geocodingService) reverseNominatim(ctx context.Context, lat, lon float64) (*models.Place, error) {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+	params.Set("format", "json")
+	params.Set("zoom", "5")
+	params.Set("accept-language", "en")
+
+	body, err := s.get(ctx, fmt.Sprintf("%s/reverse?%s", s.config.APIURL, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Locations outside any country come back as {"error": "Unable to geocode"}
+	var result struct {
+		Address struct {
+			Country string `json:"country"`
+			State   string `json:"state"`
+		} `json:"address"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reverse geocoding response: %w", err)
+	}
+
+	return &models.Place{
+		Country: result.Address.Country,
+		Region:  result.Address.State,
+	}, nil
+}
+
+// reverseOpenCage resolves coordinates through the OpenCage geocoding API
+func (s *geocodingService) reverseOpenCage(ctx context.Context, lat, lon float64) (*models.Place, error) {
+	params := url.Values{}
+	params.Set("q", fmt.Sprintf("%s+%s", strconv.FormatFloat(lat, 'f', -1, 64), strconv.FormatFloat(lon, 'f', -1, 64)))
+	params.Set("key", s.config.APIKey)
+	params.Set("limit", "1")
+	params.Set("no_annotations", "1")
+	params.Set("language", "en")
+
+	body, err := s.get(ctx, fmt.Sprintf("%s/geocode/v1/json?%s", s.config.APIURL, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Results []struct {
+			Components struct {
+				Country string `json:"country"`
+				State   string `json:"state"`
+			} `json:"components"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reverse geocoding response: %w", err)
+	}
+
+	if len(resp.Results) == 0 {
+		return &models.Place{}, nil
+	}
+
+	return &models.Place{
+		Country: resp.Results[0].Components.Country,
+		Region:  resp.Results[0].Components.State,
+	}, nil
+}
+
+// get performs a GET request against the geocoding provider and returns the response body
+func (s *geocodingService) get(ctx context.Context, requestURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create geocoding request: %w", err)
+	}
+
+	// Nominatim's usage policy requires an identifying User-Agent
+	req.Header.Set("User-Agent", "inshorts-api/1.0")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call geocoding API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read geocoding response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding API returned status %d", resp.StatusCode)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"news-inshorts/src/infra"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyStore remembers the result of a request under a client-supplied key so that
+// retries of the same request can be answered without repeating its side effects. Keys are
+// kept per tenant of the context.
+type IdempotencyStore interface {
+	// Claim atomically associates key with value. If the key was already claimed, it returns
+	// the value stored by the first claim and claimed=false.
+	Claim(ctx context.Context, scope, key, value string) (existing string, claimed bool, err error)
+	// Release forgets a claim, e.g. when the request it guarded failed and may be retried
+	Release(ctx context.Context, scope, key string) error
+}
+
+// redisIdempotencyStore implements IdempotencyStore on top of Redis SETNX
+type redisIdempotencyStore struct {
+	redisClient   *redis.Client
+	ttl           time.Duration
+	defaultTenant string
+}
+
+// NewIdempotencyStore creates a new instance of IdempotencyStore whose claims expire after ttl.
+// Claims made with a context naming no tenant belong to defaultTenant.
+func NewIdempotencyStore(redisClient *redis.Client, ttl time.Duration, defaultTenant string) IdempotencyStore {
+	return &redisIdempotencyStore{
+		redisClient:   redisClient,
+		ttl:           ttl,
+		defaultTenant: defaultTenant,
+	}
+}
+
+// Claim implements IdempotencyStore. SETNX arbitrates concurrent claims, so only one caller
+// ever sees claimed=true for a given key.
+func (s *redisIdempotencyStore) Claim(ctx context.Context, scope, key, value string) (string, bool, error) {
+	cacheKey := s.cacheKey(ctx, scope, key)
+
+	ok, err := s.redisClient.SetNX(ctx, cacheKey, value, s.ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if ok {
+		return value, true, nil
+	}
+
+	existing, err := s.redisClient.Get(ctx, cacheKey).Result()
+	if err == redis.Nil {
+		// The claim expired between SETNX and GET; try once more
+		return s.Claim(ctx, scope, key, value)
+	} else if err != nil {
+		return "", false, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	return existing, false, nil
+}
+
+// Release implements IdempotencyStore
+func (s *redisIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	if err := s.redisClient.Del(ctx, s.cacheKey(ctx, scope, key)).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// cacheKey builds the Redis key for a scoped idempotency key of the tenant of ctx
+func (s *redisIdempotencyStore) cacheKey(ctx context.Context, scope, key string) string {
+	return infra.IdempotencyKey(infra.TenantFromContext(ctx, s.defaultTenant), scope, key)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/google/uuid"
+)
+
+// JobTracker keeps the status of background jobs in memory so clients can poll their progress.
+// Finished jobs are dropped once they are older than the retention.
+type JobTracker struct {
+	mu        sync.RWMutex
+	jobs      map[string]*models.Job
+	retention time.Duration
+	clock     infra.Clock
+}
+
+// NewJobTracker creates a new JobTracker instance
+func NewJobTracker(retention time.Duration, clock infra.Clock) *JobTracker {
+	return &JobTracker{
+		jobs:      make(map[string]*models.Job),
+		retention: retention,
+		clock:     clock,
+	}
+}
+
+// Start registers a new running job of the given type and returns a snapshot of it
+func (t *JobTracker) Start(jobType string) models.Job {
+	job := &models.Job{
+		ID:        uuid.New().String(),
+		Type:      jobType,
+		Status:    models.JobStatusRunning,
+		StartedAt: t.clock.Now(),
+	}
+
+	t.mu.Lock()
+	t.prune(job.StartedAt)
+	t.jobs[job.ID] = job
+	t.mu.Unlock()
+
+	return *job
+}
+
+// Update applies fn to the job with the given id under the tracker lock
+func (t *JobTracker) Update(id string, fn func(job *models.Job)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if job, ok := t.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+// Finish marks a job as completed, or failed when err is not nil
+func (t *JobTracker) Finish(id string, err error) {
+	t.Update(id, func(job *models.Job) {
+		now := t.clock.Now()
+		job.FinishedAt = &now
+		job.Status = models.JobStatusCompleted
+		if err != nil {
+			job.Status = models.JobStatusFailed
+			job.Error = err.Error()
+		}
+	})
+}
+
+// Get returns a snapshot of the job with the given id
+func (t *JobTracker) Get(id string) (models.Job, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	job, ok := t.jobs[id]
+	if !ok {
+		return models.Job{}, false
+	}
+	return *job, true
+}
+
+// prune drops the jobs that finished more than the retention before now. Callers hold the lock.
+func (t *JobTracker) prune(now time.Time) {
+	for id, job := range t.jobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > t.retention {
+			delete(t.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+)
+
+// steppedClock is a Clock the test moves forward by hand
+type steppedClock struct {
+	now time.Time
+}
+
+func (c *steppedClock) Now() time.Time {
+	return c.now
+}
+
+var _ infra.Clock = (*steppedClock)(nil)
+
+func TestJobTrackerPrunesFinishedJobs(t *testing.T) {
+	clock := &steppedClock{now: time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)}
+	tracker := NewJobTracker(time.Hour, clock)
+
+	running := tracker.Start("backfill")
+	completed := tracker.Start("backfill")
+	failed := tracker.Start("backfill")
+	tracker.Finish(completed.ID, nil)
+	clock.now = clock.now.Add(30 * time.Minute)
+	tracker.Finish(failed.ID, errors.New("llm unavailable"))
+
+	// 61 minutes after the first finish: only the completed job is past the retention
+	clock.now = clock.now.Add(31 * time.Minute)
+	tracker.Start("backfill")
+
+	tests := []struct {
+		name   string
+		id     string
+		want   bool
+		status string
+	}{
+		{"running job is kept", running.ID, true, models.JobStatusRunning},
+		{"job finished before the retention is dropped", completed.ID, false, ""},
+		{"job finished within the retention is kept", failed.ID, true, models.JobStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, ok := tracker.Get(tt.id)
+			if ok != tt.want {
+				t.Fatalf("Get found = %v, want %v", ok, tt.want)
+			}
+			if ok && job.Status != tt.status {
+				t.Errorf("status = %q, want %q", job.Status, tt.status)
+			}
+		})
+	}
+}
@@ -1,104 +1,242 @@
 package services
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"news-inshorts/src/infra"
 	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
 )
 
+// ErrLLMUnavailable is returned when the LLM cannot be reached or keeps producing unusable output
+var ErrLLMUnavailable = errors.New("LLM service unavailable")
+
 // LLMService defines the interface for LLM operations
 type LLMService interface {
-	ProcessQuery(query string, sources []string, categories []string) (*models.QueryAnalysis, error)
-	GenerateSummary(title, description string) (string, error)
-	GenerateEmbedding(text string) ([]float64, error)
+	ProcessQuery(ctx context.Context, query string, sources []string, categories []string) (*models.QueryAnalysis, error)
+	GenerateSummary(ctx context.Context, title, description string) (string, error)
+	// GenerateSummaryIn summarizes an article in the language with the models.SummaryLanguages
+	// code lang, whatever the language of the article
+	GenerateSummaryIn(ctx context.Context, lang, title, description string) (string, error)
+	GenerateEmbedding(ctx context.Context, text string) ([]float64, error)
+	// EmbeddingModel names the model GenerateEmbedding uses
+	EmbeddingModel() string
+	ClassifySentiment(ctx context.Context, title, description string) (string, error)
+	ClassifyCategories(ctx context.Context, title, description string, allowedCategories []string) ([]string, error)
+	// ExtractEntities names the topics (people, organizations, places, events) of each title.
+	// The result is keyed by the position of the title; titles the model skipped are absent.
+	ExtractEntities(ctx context.Context, titles []string) (map[int][]string, error)
+	Usage(ctx context.Context) (*models.LLMUsage, error)
+	CircuitState() string
+	// CheckHealth probes whether the chat API is reachable with the configured key. Probes run
+	// at most once per llmProbeMinInterval; more frequent calls get the last result.
+	CheckHealth(ctx context.Context) models.LLMHealth
+	// LastHealth returns the result of the latest probe, or nil if none has run yet
+	LastHealth() *models.LLMHealth
 }
 
 // llmService implements the LLMService interface
 type llmService struct {
 	config     *infra.LLMConfig
 	httpClient *http.Client
+	geocoder   GeocodingService
+	usage      *llmUsageTracker
+	chat       chatProvider
+	embedder   embeddingProvider
+	breaker    *circuitBreaker
+	limiter    *llmLimiter
+	audit      LLMAuditService
+	clock      infra.Clock
 	logger     infra.Logger
+
+	// probeMu serializes health probes; healthMu guards lastHealth
+	probeMu    sync.Mutex
+	healthMu   sync.RWMutex
+	lastHealth *models.LLMHealth
 }
 
-// NewLLMService creates a new LLM service instance
-func NewLLMService(cfg *infra.LLMConfig) LLMService {
+// NewLLMService creates a new LLM service instance. geocoder may be nil, in which case
+// nearby coordinates come from the LLM's own hint. Token usage is tracked in Redis. Relative
+// date ranges in queries are resolved against clock. Query analysis calls are handed to audit.
+func NewLLMService(cfg *infra.LLMConfig, geocoder GeocodingService, redisClient *redis.Client, audit LLMAuditService, clock infra.Clock, logger infra.Logger) LLMService {
 	return &llmService{
 		config: cfg,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		logger: infra.GetLogger(),
-	}
-}
-
-// openAIRequest represents the request structure for OpenAI API
-type openAIRequest struct {
-	Model       string          `json:"model"`
-	Messages    []openAIMessage `json:"messages"`
-	Temperature float64         `json:"temperature"`
-	MaxTokens   int             `json:"max_tokens,omitempty"`
-}
-
-// openAIMessage represents a message in the OpenAI API request
-type openAIMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// openAIResponse represents the response structure from OpenAI API
-type openAIResponse struct {
-	ID      string `json:"id"`
-	Object  string `json:"object"`
-	Created int64  `json:"created"`
-	Model   string `json:"model"`
-	Choices []struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
-	Error *struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-		Code    string `json:"code"`
-	} `json:"error,omitempty"`
-}
-
-// ProcessQuery analyzes a user query using LLM to extract entities and intents
-func (s *llmService) ProcessQuery(query string, sources []string, categories []string) (*models.QueryAnalysis, error) {
-	prompt := s.buildQueryAnalysisPrompt(query, sources, categories)
-
-	response, err := s.callOpenAI(prompt, 500)
+		geocoder: geocoder,
+		chat:     newChatProvider(cfg),
+		embedder: newEmbeddingProvider(cfg),
+		usage:    newLLMUsageTracker(redisClient, cfg.DailyTokenBudget, logger),
+		breaker:  newCircuitBreaker("llm", cfg.BreakerFailureThreshold, cfg.BreakerOpenDuration, infra.MetricLLMCircuitState, logger),
+		limiter:  newLLMLimiter(cfg.MaxInflight, cfg.MaxInflightBulk, cfg.MaxWait),
+		audit:    audit,
+		clock:    clock,
+		logger:   logger,
+	}
+}
+
+// CircuitState returns the state of the circuit breaker guarding LLM API calls
+func (s *llmService) CircuitState() string {
+	return s.breaker.State()
+}
+
+// send performs an LLM API request through the circuit breaker and the concurrency limiter
+// and returns the response status and body. While the breaker is open it fails immediately
+// with ErrCircuitOpen. Transport errors, timeouts, 429 and 5xx responses count as failures.
+func (s *llmService) send(req *http.Request) (int, []byte, error) {
+	if err := s.breaker.allow(); err != nil {
+		infra.IncrCounter(infra.MetricLLMCircuitRejections, 1)
+		return 0, nil, err
+	}
+
+	release, err := s.limiter.acquire(req.Context())
 	if err != nil {
-		s.logger.Error("Failed to process query with LLM", err, map[string]interface{}{
-			"query": query,
-		})
-		return nil, fmt.Errorf("LLM service unavailable: %w", err)
+		s.breaker.done(callIgnored)
+		return 0, nil, err
+	}
+	defer release()
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(req.Context().Err(), context.Canceled) {
+			s.breaker.done(callIgnored)
+		} else {
+			s.breaker.done(callFailed)
+		}
+		return 0, nil, withoutRequestURL(err)
 	}
+	defer resp.Body.Close()
 
-	analysis, err := s.parseQueryAnalysis(response)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		s.logger.Error("Failed to parse LLM response", err, map[string]interface{}{
-			"response": response,
+		s.breaker.done(callFailed)
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		s.breaker.done(callFailed)
+	} else {
+		s.breaker.done(callSucceeded)
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+// maxLLMErrorBodyLength caps how much of an error response body is quoted in the error
+const maxLLMErrorBodyLength = 512
+
+// llmErrorBody returns the part of an LLM API error response quoted in errors: at most
+// maxLLMErrorBodyLength bytes, with secrets the API may echo back masked
+func llmErrorBody(body []byte) string {
+	text := string(body)
+	if len(text) > maxLLMErrorBodyLength {
+		text = strings.ToValidUTF8(text[:maxLLMErrorBodyLength], "") + "...(truncated)"
+	}
+	return infra.RedactSecrets(text)
+}
+
+// withoutRequestURL drops the request URL from an HTTP client error, which may carry
+// credentials in its query string. Requests never reach errors with their headers.
+func withoutRequestURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s request failed: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// Usage returns today's token usage per operation and the budget state
+func (s *llmService) Usage(ctx context.Context) (*models.LLMUsage, error) {
+	return s.usage.usage(ctx)
+}
+
+// checkBudget returns an error wrapping ErrLLMUnavailable once the daily token budget is spent,
+// so callers degrade exactly as they would during an outage
+func (s *llmService) checkBudget(ctx context.Context) error {
+	if s.usage.budgetExceeded(ctx) {
+		return fmt.Errorf("%w: %w", ErrLLMUnavailable, ErrLLMBudgetExceeded)
+	}
+	return nil
+}
+
+// ProcessQuery analyzes a user query using LLM to extract entities and intents.
+// Malformed model output is retried once before giving up with ErrLLMUnavailable.
+// Every call that reaches the model is offered to the audit sink.
+func (s *llmService) ProcessQuery(ctx context.Context, query string, sources []string, categories []string) (*models.QueryAnalysis, error) {
+	if err := s.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	// Long allowed lists are cut for the prompt; the output is validated against the full lists
+	promptSources := boundPromptList(query, sources, s.config.PromptMaxSources, s.config.PromptTopK)
+	promptCategories := boundPromptList(query, categories, s.config.PromptMaxCategories, s.config.PromptTopK)
+	infra.IncrCounter(infra.MetricPromptSourcesOmitted, int64(len(sources)-len(promptSources)))
+	infra.IncrCounter(infra.MetricPromptCategoriesOmitted, int64(len(categories)-len(promptCategories)))
+
+	prompt := s.buildQueryAnalysisPrompt(query, promptSources, promptCategories)
+
+	record := models.LLMAuditRecord{
+		Query:          normalizeAnalysisQuery(query),
+		SourcesHash:    allowedListHash(sources),
+		CategoriesHash: allowedListHash(categories),
+	}
+	start := time.Now()
+	defer func() {
+		record.LatencyMs = time.Since(start).Milliseconds()
+		s.audit.Record(record)
+	}()
+
+	var analysis *models.QueryAnalysis
+	var parseErr error
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		record.Attempts = attempt
+		result, err := s.chatCompletion(ctx, LLMOperationQueryAnalysis, prompt, 500, s.config.JSONMode)
+		if result != nil {
+			record.RawOutput = result.Content
+			record.PromptTokens += result.PromptTokens
+			record.CompletionTokens += result.CompletionTokens
+		}
+		if err != nil {
+			s.logger.Error("Failed to process query with LLM", err, map[string]interface{}{
+				"query": query,
+			})
+			record.Error = err.Error()
+			return nil, fmt.Errorf("%w: %w", ErrLLMUnavailable, err)
+		}
+
+		analysis, parseErr = s.parseQueryAnalysis(ctx, result.Content, sources, categories)
+		if parseErr == nil {
+			break
+		}
+
+		s.logger.Warn("Failed to parse LLM response", map[string]interface{}{
+			"attempt":  attempt,
+			"response": result.Content,
+			"error":    parseErr.Error(),
 		})
-		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
 	}
 
+	if parseErr != nil {
+		record.Error = parseErr.Error()
+		return nil, fmt.Errorf("%w: malformed query analysis: %w", ErrLLMUnavailable, parseErr)
+	}
+
+	record.Analysis = analysis
 	s.logger.Info("Successfully processed query", map[string]interface{}{
 		"query":          query,
 		"entities_count": len(analysis.Entities),
@@ -109,16 +247,16 @@ func (s *llmService) ProcessQuery(query string, sources []string, categories []s
 }
 
 // GenerateSummary generates a summary for an article using LLM
-func (s *llmService) GenerateSummary(title, description string) (string, error) {
+func (s *llmService) GenerateSummary(ctx context.Context, title, description string) (string, error) {
+	if err := s.checkBudget(ctx); err != nil {
+		return "", err
+	}
+
 	prompt := s.buildSummaryPrompt(title, description)
 
-	response, err := s.callOpenAI(prompt, 150)
+	response, err := s.callChat(ctx, LLMOperationSummary, prompt, 150, false)
 	if err != nil {
-		s.logger.Warn("Failed to generate summary with LLM", map[string]interface{}{
-			"title": title,
-			"error": err.Error(),
-		})
-		return "", nil
+		return "", fmt.Errorf("%w: failed to generate summary: %w", ErrLLMUnavailable, err)
 	}
 
 	s.logger.Debug("Successfully generated summary", map[string]interface{}{
@@ -128,76 +266,195 @@ func (s *llmService) GenerateSummary(title, description string) (string, error)
 	return response, nil
 }
 
-// GenerateEmbedding generates an embedding vector for the given text using OpenAI embeddings API
-func (s *llmService) GenerateEmbedding(text string) ([]float64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
-	defer cancel()
+// GenerateSummaryIn generates a summary in the given language
+func (s *llmService) GenerateSummaryIn(ctx context.Context, lang, title, description string) (string, error) {
+	language, ok := models.SummaryLanguages[lang]
+	if !ok {
+		return "", fmt.Errorf("unsupported summary language %q", lang)
+	}
 
-	embeddingRequest := struct {
-		Model string `json:"model"`
-		Input string `json:"input"`
-	}{
-		Model: "text-embedding-3-small", // or "text-embedding-ada-002" for 1536 dimensions
-		Input: text,
+	if err := s.checkBudget(ctx); err != nil {
+		return "", err
 	}
 
-	jsonData, err := json.Marshal(embeddingRequest)
+	prompt := s.buildSummaryInPrompt(language, title, description)
+
+	// Scripts other than Latin take more tokens per word
+	response, err := s.callChat(ctx, LLMOperationSummary, prompt, 300, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+		return "", fmt.Errorf("%w: failed to generate %s summary: %w", ErrLLMUnavailable, language, err)
 	}
 
-	url := fmt.Sprintf("%s/embeddings", s.config.APIURL)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	s.logger.Debug("Successfully generated summary", map[string]interface{}{
+		"title": title,
+		"lang":  lang,
+	})
+
+	return response, nil
+}
+
+// ClassifySentiment classifies the overall tone of an article as one of the models.Sentiment*
+// values. A response that is not one of them is an error.
+func (s *llmService) ClassifySentiment(ctx context.Context, title, description string) (string, error) {
+	if err := s.checkBudget(ctx); err != nil {
+		return "", err
+	}
+
+	prompt := s.buildSentimentPrompt(title, description)
+
+	response, err := s.callChat(ctx, LLMOperationSentiment, prompt, 5, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create embedding request: %w", err)
+		return "", fmt.Errorf("%w: failed to classify sentiment: %w", ErrLLMUnavailable, err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.APIKey))
+	sentiment := strings.ToLower(strings.Trim(strings.TrimSpace(response), ".\"'"))
+	if !models.IsValidSentiment(sentiment) {
+		return "", fmt.Errorf("unexpected sentiment %q", response)
+	}
 
-	resp, err := s.httpClient.Do(req)
+	s.logger.Debug("Successfully classified sentiment", map[string]interface{}{
+		"title":     title,
+		"sentiment": sentiment,
+	})
+
+	return sentiment, nil
+}
+
+// ClassifyCategories picks one or more categories for an article from allowedCategories.
+// Values outside the list are dropped; an error is returned if none remain.
+func (s *llmService) ClassifyCategories(ctx context.Context, title, description string, allowedCategories []string) ([]string, error) {
+	if err := s.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	prompt := s.buildCategorizePrompt(title, description, allowedCategories)
+
+	response, err := s.callChat(ctx, LLMOperationCategorize, prompt, 100, s.config.JSONMode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call OpenAI embeddings API: %w", err)
+		return nil, fmt.Errorf("%w: failed to classify categories: %w", ErrLLMUnavailable, err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	var result struct {
+		Categories []string `json:"categories"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(response)), &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal category classification: %w", err)
+	}
+
+	categories := s.filterAllowed("category", result.Categories, allowedCategories)
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("no allowed category in classification %q", response)
+	}
+
+	s.logger.Debug("Successfully classified categories", map[string]interface{}{
+		"title":      title,
+		"categories": categories,
+	})
+
+	return categories, nil
+}
+
+// ExtractEntities extracts the named topics of several titles in one call. Entries for
+// unknown positions are dropped and entity names are trimmed and de-duplicated per title.
+func (s *llmService) ExtractEntities(ctx context.Context, titles []string) (map[int][]string, error) {
+	if len(titles) == 0 {
+		return map[int][]string{}, nil
+	}
+	if err := s.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	prompt := s.buildEntitiesPrompt(titles)
+
+	// About 20 tokens per title leaves room for a few entities each
+	response, err := s.callChat(ctx, LLMOperationEntities, prompt, 100+20*len(titles), s.config.JSONMode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read embedding response body: %w", err)
+		return nil, fmt.Errorf("%w: failed to extract entities: %w", ErrLLMUnavailable, err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI embeddings API returned status %d: %s", resp.StatusCode, string(body))
+	var result struct {
+		Articles []struct {
+			Index    int      `json:"index"`
+			Entities []string `json:"entities"`
+		} `json:"articles"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(response)), &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal entity extraction: %w", err)
 	}
 
-	var embeddingResp struct {
-		Data []struct {
-			Embedding []float64 `json:"embedding"`
-		} `json:"data"`
-		Error *struct {
-			Message string `json:"message"`
-			Type    string `json:"type"`
-			Code    string `json:"code"`
-		} `json:"error,omitempty"`
+	entities := make(map[int][]string, len(result.Articles))
+	for _, article := range result.Articles {
+		if article.Index < 0 || article.Index >= len(titles) {
+			continue
+		}
+		for _, entity := range article.Entities {
+			if entity = strings.Join(strings.Fields(entity), " "); entity != "" {
+				entities[article.Index] = appendUnique(entities[article.Index], entity)
+			}
+		}
 	}
 
-	if err := json.Unmarshal(body, &embeddingResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+	s.logger.Debug("Successfully extracted entities", map[string]interface{}{
+		"titles":    len(titles),
+		"extracted": len(entities),
+	})
+
+	return entities, nil
+}
+
+// EmbeddingModel returns the name of the model GenerateEmbedding uses
+func (s *llmService) EmbeddingModel() string {
+	return s.embedder.Model()
+}
+
+// GenerateEmbedding generates an embedding vector for the given text using OpenAI embeddings API.
+// Text beyond the model's input limit is cut off.
+func (s *llmService) GenerateEmbedding(ctx context.Context, text string) ([]float64, error) {
+	if err := s.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	text = truncateEmbeddingInput(text, s.embedder.MaxInputTokens())
+
+	ctx, cancel := context.WithTimeout(ctx, 25*time.Second)
+	defer cancel()
+
+	req, err := s.embedder.NewRequest(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding request: %w", err)
+	}
+
+	statusCode, body, err := s.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to call %s embeddings API: %w", ErrLLMUnavailable, s.embedder.Name(), err)
 	}
 
-	if embeddingResp.Error != nil {
-		return nil, fmt.Errorf("OpenAI embeddings API error: %s", embeddingResp.Error.Message)
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s embeddings API returned status %d: %s", ErrLLMUnavailable, s.embedder.Name(), statusCode, llmErrorBody(body))
 	}
 
-	if len(embeddingResp.Data) == 0 {
-		return nil, fmt.Errorf("no embedding data in OpenAI response")
+	embedding, tokens, err := s.embedder.ParseResponse(body)
+	s.usage.record(ctx, LLMOperationEmbedding, tokens, 0)
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Debug("Successfully generated embedding", map[string]interface{}{
-		"dimensions": len(embeddingResp.Data[0].Embedding),
+		"dimensions": len(embedding),
 	})
 
-	return embeddingResp.Data[0].Embedding, nil
+	return embedding, nil
+}
+
+// truncateEmbeddingInput cuts text so it fits in maxTokens. Without the model's tokenizer the
+// cut is made at maxTokens/2 characters, since a character costs at most about two tokens in
+// common scripts.
+func truncateEmbeddingInput(text string, maxTokens int) string {
+	maxChars := maxTokens / 2
+	if utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+	return string([]rune(text)[:maxChars])
 }
 
 // buildQueryAnalysisPrompt creates the prompt for query analysis
@@ -221,9 +478,13 @@ Respond with ONLY a single valid JSON object, nothing else:
 {
 "entities": [],
 "intent": {
-"category": { "values": [] },
-"source": { "values": [] },
-"nearby": { "lat": null, "lon": null }
+"category": { "values": [], "confidence": null },
+"source": { "values": [], "confidence": null },
+"nearby": { "places": [], "radius_km": null, "confidence": null },
+"region": { "values": [], "confidence": null },
+"score": { "threshold": null, "confidence": null },
+"sentiment": { "values": [], "confidence": null },
+"date_range": { "period": null, "days": null, "from": null, "to": null, "confidence": null }
 }
 }
 
@@ -247,25 +508,57 @@ Generic nouns like "news", "updates", "articles" must be ignored and excluded fr
 
 5. LOCATION / NEARBY INTENT (Updated)
 
-If the query contains any real place name (city, region, country, landmark), you must:
+If the query contains a real place name that is a city, town or landmark, you must:
 
 Activate the nearby intent.
 
 Insert that place name into entities[].
 
-Generate approximate latitude & longitude of that place and populate nearby.lat and nearby.lon.
+Add an object { "place": ..., "lat": ..., "lon": ... } to nearby.places with the place name as written in the query. Coordinates are resolved by the server from place.
+
+Only as a fallback hint, you may populate lat and lon with the rough coordinates of that place; leave them null when unsure.
+
+Example: "Delhi" → { "place": "Delhi", "lat": 28.61, "lon": 77.23 }
+
+If the query asks about several places (e.g. "floods in Chennai and Bengaluru"), add one object per place to nearby.places, at most 5, and include all of them in entities.
+
+Set nearby.radius_km to the search radius in kilometers:
+
+If the query states a distance (e.g. "within 10 km of Delhi"), use it.
+
+Otherwise infer it from the size of the place and the phrasing: about 5 for a neighborhood or landmark ("Koramangala", "near India Gate"), about 25 for a city ("Pune"), about 300 for a state or province searched around ("around Maharashtra"), about 500 for a country searched around. Use a smaller value for "right near", "in my area" and a larger one for "around", "in the region of".
+
+If the query asks for news near the user without naming a place ("news near me", "what's happening nearby"), leave nearby.places empty and set only nearby.radius_km, about 10.
+
+Leave nearby.radius_km null when the query gives no hint about the size of the area.
+
+5a. QUALITY / SCORE INTENT
+
+If the query explicitly asks for only highly relevant, top or most important stories, set score.threshold to a number between 0 and 1 (e.g. "top stories" → 0.8). Otherwise leave it null.
+
+5b. SENTIMENT INTENT
 
-Example: "Delhi" → 28.61, 77.23
+If the query explicitly asks for news of a particular tone (e.g. "good news", "positive stories", "uplifting news"), set sentiment.values to the matching values from: "positive", "neutral", "negative". Otherwise leave it empty.
 
-"Mumbai" → 19.07, 72.88
+5c. DATE RANGE INTENT
 
-"Palo Alto" → 37.44, -122.14
+If the query restricts when the news was published, fill date_range. Never compute dates for relative expressions; the server resolves them.
 
-"Paris" → 48.85, 2.34
+For relative expressions set date_range.period to one of: "today", "yesterday", "this_week", "last_week", "this_month", "last_month", "last_n_days". For "last_n_days" also set date_range.days (e.g. "past 3 days" → period "last_n_days", days 3).
 
-If multiple places are present, choose the most relevant one for proximity and still include all in entities.
+Only when the query names explicit calendar dates, leave period null and set date_range.from and/or date_range.to as YYYY-MM-DD (both inclusive).
 
-You may approximate; slight offsets are acceptable, do not be exact.
+Leave every date_range field null when the query has no time expression.
+
+5d. REGION INTENT
+
+If the query names a whole country, state or province (e.g. "news from Maharashtra", "India politics"), put its common English name into region.values and insert it into entities[]. Do not activate the nearby intent for it.
+
+If the query states a distance from a country, state or province, or searches around one ("news around Maharashtra"), use the nearby intent with a wide radius instead.
+
+5e. CONFIDENCE
+
+For every intent you fill, set its confidence to a number between 0 and 1: how sure you are that the query asks for exactly that filter. Use a high value (0.9 or more) when the query names it explicitly (e.g. "news from Delhi" → nearby confidence 0.95). Use a low value when you had to guess or resolve an indirect reference (e.g. "news from the capital" → nearby confidence 0.4). Leave confidence null for intents you leave empty.
 
 6. ENTITY EXTRACTION RULES
 
@@ -282,7 +575,7 @@ Allowed Sources: %s
 
 Do NOT emit new strings in category or source values that do not exist in the allowed lists.
 
-Only the nearby lat/lon may be approximated when a place name is present.
+Only the nearby lat/lon hint may be approximated when a place name is present.
 
 9. EXAMPLES (Follow strictly)
 
@@ -295,8 +588,12 @@ Output:
 "entities": ["Paris","ANI","paris"],
 "intent": {
 "category": { "values": [] },
-"source": { "values": ["ANI"] },
-"nearby": { "lat": 48.85, "lon": 2.34 }
+"source": { "values": ["ANI"], "confidence": 0.95 },
+"nearby": { "places": [{ "place": "Paris", "lat": 48.85, "lon": 2.34 }], "radius_km": null, "confidence": 0.9 },
+"region": { "values": [] },
+"score": { "threshold": null },
+"sentiment": { "values": [] },
+"date_range": { "period": null, "days": null, "from": null, "to": null }
 }
 }
 
@@ -308,9 +605,121 @@ Output:
 {
 "entities": ["News18","Mumbai","technology"],
 "intent": {
-"category": { "values": ["technology"] },
-"source": { "values": ["News18"] },
-"nearby": { "lat": 19.07, "lon": 72.88 }
+"category": { "values": ["technology"], "confidence": 0.95 },
+"source": { "values": ["News18"], "confidence": 0.95 },
+"nearby": { "places": [{ "place": "Mumbai", "lat": 19.07, "lon": 72.88 }], "radius_km": null, "confidence": 0.7 },
+"region": { "values": [] },
+"score": { "threshold": null },
+"sentiment": { "values": [] },
+"date_range": { "period": null, "days": null, "from": null, "to": null }
+}
+}
+
+Input Query: "cricket news from last week"
+Allowed Sources: ["ESPN","BBC","Times of India"]
+Allowed Categories: ["sports","world","business"]
+
+Output:
+{
+"entities": ["cricket"],
+"intent": {
+"category": { "values": ["sports"], "confidence": 0.85 },
+"source": { "values": [] },
+"nearby": { "places": [], "radius_km": null },
+"region": { "values": [] },
+"score": { "threshold": null },
+"sentiment": { "values": [] },
+"date_range": { "period": "last_week", "days": null, "from": null, "to": null, "confidence": 0.95 }
+}
+}
+
+Input Query: "good news from Maharashtra"
+Allowed Sources: ["ANI","Times of India","NDTV"]
+Allowed Categories: ["world","national","business"]
+
+Output:
+{
+"entities": ["Maharashtra"],
+"intent": {
+"category": { "values": [] },
+"source": { "values": [] },
+"nearby": { "places": [], "radius_km": null },
+"region": { "values": ["Maharashtra"], "confidence": 0.95 },
+"score": { "threshold": null },
+"sentiment": { "values": ["positive"], "confidence": 0.9 },
+"date_range": { "period": null, "days": null, "from": null, "to": null }
+}
+}
+
+Input Query: "floods in Chennai and Bengaluru"
+Allowed Sources: ["ANI","The Hindu","NDTV"]
+Allowed Categories: ["national","weather","world"]
+
+Output:
+{
+"entities": ["floods","Chennai","Bengaluru"],
+"intent": {
+"category": { "values": ["weather"], "confidence": 0.6 },
+"source": { "values": [] },
+"nearby": { "places": [{ "place": "Chennai", "lat": 13.08, "lon": 80.27 }, { "place": "Bengaluru", "lat": 12.97, "lon": 77.59 }], "radius_km": null, "confidence": 0.95 },
+"region": { "values": [] },
+"score": { "threshold": null },
+"sentiment": { "values": [] },
+"date_range": { "period": null, "days": null, "from": null, "to": null }
+}
+}
+
+Input Query: "news near me"
+Allowed Sources: ["ANI","BBC","NDTV"]
+Allowed Categories: ["world","national","sports"]
+
+Output:
+{
+"entities": [],
+"intent": {
+"category": { "values": [] },
+"source": { "values": [] },
+"nearby": { "places": [], "radius_km": 10, "confidence": 0.9 },
+"region": { "values": [] },
+"score": { "threshold": null },
+"sentiment": { "values": [] },
+"date_range": { "period": null, "days": null, "from": null, "to": null }
+}
+}
+
+Input Query: "restaurant openings in Koramangala"
+Allowed Sources: ["The Hindu","Deccan Herald"]
+Allowed Categories: ["business","lifestyle","national"]
+
+Output:
+{
+"entities": ["restaurant openings","Koramangala"],
+"intent": {
+"category": { "values": ["lifestyle"], "confidence": 0.6 },
+"source": { "values": [] },
+"nearby": { "places": [{ "place": "Koramangala", "lat": 12.93, "lon": 77.62 }], "radius_km": 5, "confidence": 0.95 },
+"region": { "values": [] },
+"score": { "threshold": null },
+"sentiment": { "values": [] },
+"date_range": { "period": null, "days": null, "from": null, "to": null }
+}
+}
+
+Input Query: "news around Maharashtra"
+Allowed Sources: ["ANI","Times of India","NDTV"]
+Allowed Categories: ["world","national","business"]
+
+Output:
+{
+"entities": ["Maharashtra"],
+"intent": {
+"category": { "values": [] },
+"source": { "values": [] },
+"nearby": { "places": [{ "place": "Maharashtra", "lat": 19.75, "lon": 75.71 }], "radius_km": 300, "confidence": 0.85 },
+"region": { "values": [] },
+"score": { "threshold": null },
+"sentiment": { "values": [] },
+"date_range": { "period": null, "days": null, "from": null, "to": null }
 }
 }
 
@@ -330,97 +739,169 @@ Description: %s
 Summary:`, title, description)
 }
 
-// callOpenAI makes a request to the OpenAI API
-func (s *llmService) callOpenAI(prompt string, maxTokens int) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
-	defer cancel()
+// buildSummaryInPrompt creates the prompt for summary generation in a given language
+func (s *llmService) buildSummaryInPrompt(language, title, description string) string {
+	return fmt.Sprintf(`Summarize the following news article in 2-3 sentences, written entirely in %[1]s.
+Whatever the language of the article, respond in %[1]s only: no other language, no transliteration and no translation notes.
+Keep names of people, places and organizations as they are commonly written in %[1]s.
 
-	reqBody := openAIRequest{
-		Model: "gpt-3.5-turbo",
-		Messages: []openAIMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-		Temperature: 0.7,
-		MaxTokens:   maxTokens,
-	}
+Title: %[2]s
+Description: %[3]s
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
+Summary in %[1]s:`, language, title, description)
+}
 
-	url := fmt.Sprintf("%s/chat/completions", s.config.APIURL)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
+// buildSentimentPrompt creates the prompt for sentiment classification
+func (s *llmService) buildSentimentPrompt(title, description string) string {
+	return fmt.Sprintf(`Classify the overall tone of the following news article for a reader as positive, neutral or negative.
+Answer with exactly one word: positive, neutral or negative.
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.APIKey))
+Title: %s
+Description: %s
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to call OpenAI API: %w", err)
+Sentiment:`, title, description)
+}
+
+// buildCategorizePrompt creates the prompt for category classification
+func (s *llmService) buildCategorizePrompt(title, description string, categories []string) string {
+	return fmt.Sprintf(`Assign the following news article to one or more categories from the Valid Categories list.
+Use only values from the list, spelled exactly as listed, most relevant first, and at most three.
+
+Respond with ONLY a single valid JSON object, nothing else:
+{"categories": []}
+
+Valid Categories: %s
+
+Title: %s
+Description: %s
+`, strings.Join(categories, ", "), title, description)
+}
+
+// buildEntitiesPrompt creates the prompt for batched entity extraction
+func (s *llmService) buildEntitiesPrompt(titles []string) string {
+	var sb strings.Builder
+	for i, title := range titles {
+		fmt.Fprintf(&sb, "%d. %s\n", i, title)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	return fmt.Sprintf(`For each numbered news headline below, list the specific topics it is about: named people,
+organizations, places, products and events (e.g. "Budget 2025", "ISRO", "Chandrayaan-3").
+Use the common short name of each topic, at most three per headline. Leave out generic words
+such as "government", "market" or "report".
+
+Respond with ONLY a single valid JSON object, nothing else:
+{"articles": [{"index": 0, "entities": []}]}
+
+Headlines:
+%s`, sb.String())
+}
+
+// callChat sends prompt to the configured chat provider and records its token usage under
+// operation. When jsonMode is set the model is constrained to emit a single JSON object.
+func (s *llmService) callChat(ctx context.Context, operation, prompt string, maxTokens int, jsonMode bool) (string, error) {
+	result, err := s.chatCompletion(ctx, operation, prompt, maxTokens, jsonMode)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, string(body))
-	}
+	return result.Content, nil
+}
+
+// chatCompletion is callChat returning the whole completion. The result is also returned with
+// an error when the provider answered but its content was unusable, so the tokens spent and
+// the raw output remain available.
+func (s *llmService) chatCompletion(ctx context.Context, operation, prompt string, maxTokens int, jsonMode bool) (*chatResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 25*time.Second)
+	defer cancel()
 
-	var apiResp openAIResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	req, err := s.chat.NewRequest(ctx, prompt, maxTokens, jsonMode)
+	if err != nil {
+		return nil, err
 	}
 
-	if apiResp.Error != nil {
-		return "", fmt.Errorf("OpenAI API error: %s", apiResp.Error.Message)
+	statusCode, body, err := s.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s API: %w", s.chat.Name(), err)
 	}
 
-	if len(apiResp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in OpenAI response")
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s API returned status %d: %s", s.chat.Name(), statusCode, llmErrorBody(body))
 	}
 
-	return apiResp.Choices[0].Message.Content, nil
+	result, err := s.chat.ParseResponse(body, jsonMode)
+	if result != nil {
+		s.usage.record(ctx, operation, result.PromptTokens, result.CompletionTokens)
+	}
+	return result, err
 }
 
-// llmQueryResponse represents the raw JSON response structure from LLM
+// llmQueryResponse represents the raw JSON response structure from LLM. Every intent may carry
+// the model's confidence in it; see intentConfidence.
 type llmQueryResponse struct {
 	Entities []string `json:"entities"`
 	Intent   struct {
 		Category struct {
-			Values []string `json:"values"`
+			Values     []string `json:"values"`
+			Confidence *float64 `json:"confidence"`
 		} `json:"category"`
 		Source struct {
-			Values []string `json:"values"`
+			Values     []string `json:"values"`
+			Confidence *float64 `json:"confidence"`
 		} `json:"source"`
 		Nearby struct {
-			Lat *float64 `json:"lat"`
-			Lon *float64 `json:"lon"`
+			Places []llmNearbyPlace `json:"places"`
+			// Place, Lat and Lon are the single-place form of earlier prompts
+			Place      string   `json:"place"`
+			Lat        *float64 `json:"lat"`
+			Lon        *float64 `json:"lon"`
+			RadiusKm   *float64 `json:"radius_km"`
+			Confidence *float64 `json:"confidence"`
 		} `json:"nearby"`
+		Region struct {
+			Values     []string `json:"values"`
+			Confidence *float64 `json:"confidence"`
+		} `json:"region"`
+		Score struct {
+			Threshold  *float64 `json:"threshold"`
+			Confidence *float64 `json:"confidence"`
+		} `json:"score"`
+		Sentiment struct {
+			Values     []string `json:"values"`
+			Confidence *float64 `json:"confidence"`
+		} `json:"sentiment"`
+		DateRange dateRangeSpec `json:"date_range"`
 	} `json:"intent"`
 }
 
-// parseQueryAnalysis parses the LLM response into QueryAnalysis
-func (s *llmService) parseQueryAnalysis(response string) (*models.QueryAnalysis, error) {
-	var llmResp llmQueryResponse
-
-	startIdx := bytes.IndexByte([]byte(response), '{')
-	endIdx := bytes.LastIndexByte([]byte(response), '}')
+// llmNearbyPlace is one place of the nearby intent with the LLM's coordinate hint
+type llmNearbyPlace struct {
+	Place string   `json:"place"`
+	Lat   *float64 `json:"lat"`
+	Lon   *float64 `json:"lon"`
+}
 
-	if startIdx == -1 || endIdx == -1 || startIdx > endIdx {
-		return nil, fmt.Errorf("no valid JSON found in response")
+// intentConfidence clamps the confidence the model gave an intent to [0, 1]. Intents without
+// one, as returned by prompts predating confidences, are fully trusted.
+func intentConfidence(confidence *float64) float64 {
+	if confidence == nil || math.IsNaN(*confidence) {
+		return 1
 	}
+	return math.Max(0, math.Min(1, *confidence))
+}
 
-	jsonStr := response[startIdx : endIdx+1]
+// parseQueryAnalysis parses the LLM response into QueryAnalysis and validates it against the
+// allowed lists. In JSON mode the whole response must be a JSON object; otherwise the first
+// '{' to last '}' span is extracted from free text.
+func (s *llmService) parseQueryAnalysis(ctx context.Context, response string, sources []string, categories []string) (*models.QueryAnalysis, error) {
+	var llmResp llmQueryResponse
+
+	jsonStr := strings.TrimSpace(response)
+	if !s.config.JSONMode {
+		jsonStr = extractJSONObject(response)
+		if jsonStr == "" {
+			return nil, fmt.Errorf("no valid JSON found in response")
+		}
+	}
 
 	if err := json.Unmarshal([]byte(jsonStr), &llmResp); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
@@ -431,26 +912,180 @@ func (s *llmService) parseQueryAnalysis(response string) (*models.QueryAnalysis,
 		Intents:  make([]models.Intent, 0),
 	}
 
-	if len(llmResp.Intent.Category.Values) > 0 {
+	if matched := s.filterAllowed("category", llmResp.Intent.Category.Values, categories); len(matched) > 0 {
+		analysis.Intents = append(analysis.Intents, models.Intent{
+			Type:       models.IntentTypeCategory,
+			Values:     matched,
+			Confidence: intentConfidence(llmResp.Intent.Category.Confidence),
+		})
+	}
+
+	if matched := s.filterAllowed("source", llmResp.Intent.Source.Values, sources); len(matched) > 0 {
+		analysis.Intents = append(analysis.Intents, models.Intent{
+			Type:       models.IntentTypeSource,
+			Values:     matched,
+			Confidence: intentConfidence(llmResp.Intent.Source.Confidence),
+		})
+	}
+
+	// The radius is clamped when the filter is planned; without places it applies to the
+	// caller's location ("news near me")
+	var radius *float64
+	if r := llmResp.Intent.Nearby.RadiusKm; r != nil && *r > 0 {
+		radius = r
+	}
+	if points := s.resolveNearbyPlaces(ctx, llmResp.Intent.Nearby.Places, llmResp.Intent.Nearby.Place, llmResp.Intent.Nearby.Lat, llmResp.Intent.Nearby.Lon); len(points) > 0 || radius != nil {
+		analysis.Intents = append(analysis.Intents, models.Intent{
+			Type:       models.IntentTypeNearby,
+			Values:     formatNearbyValues(points, radius),
+			Confidence: intentConfidence(llmResp.Intent.Nearby.Confidence),
+		})
+	}
+
+	// Region names are free text; articles store English names from the reverse geocoder
+	var places []string
+	for _, place := range llmResp.Intent.Region.Values {
+		if place = strings.TrimSpace(place); place != "" {
+			places = append(places, place)
+		}
+	}
+	if len(places) > 0 {
 		analysis.Intents = append(analysis.Intents, models.Intent{
-			Type:   models.IntentTypeCategory,
-			Values: llmResp.Intent.Category.Values,
+			Type:       models.IntentTypeRegion,
+			Values:     places,
+			Confidence: intentConfidence(llmResp.Intent.Region.Confidence),
 		})
 	}
 
-	if len(llmResp.Intent.Source.Values) > 0 {
+	if threshold := llmResp.Intent.Score.Threshold; threshold != nil {
 		analysis.Intents = append(analysis.Intents, models.Intent{
-			Type:   models.IntentTypeSource,
-			Values: llmResp.Intent.Source.Values,
+			Type:       models.IntentTypeScore,
+			Values:     math.Max(0, math.Min(1, *threshold)),
+			Confidence: intentConfidence(llmResp.Intent.Score.Confidence),
 		})
 	}
 
-	if llmResp.Intent.Nearby.Lat != nil && llmResp.Intent.Nearby.Lon != nil {
+	if matched := s.filterAllowed("sentiment", llmResp.Intent.Sentiment.Values, models.Sentiments); len(matched) > 0 {
 		analysis.Intents = append(analysis.Intents, models.Intent{
-			Type:   models.IntentTypeNearby,
-			Values: []string{fmt.Sprintf("%f", *llmResp.Intent.Nearby.Lat), fmt.Sprintf("%f", *llmResp.Intent.Nearby.Lon)},
+			Type:       models.IntentTypeSentiment,
+			Values:     matched,
+			Confidence: intentConfidence(llmResp.Intent.Sentiment.Confidence),
 		})
 	}
 
+	// Time expressions are resolved here against the request time; the LLM only names the period
+	if spec := llmResp.Intent.DateRange; !spec.empty() {
+		from, to, err := resolveDateRange(spec, s.clock.Now())
+		if err != nil {
+			s.logger.Warn("Dropping invalid date range from query analysis", map[string]interface{}{
+				"period": spec.Period,
+				"from":   spec.From,
+				"to":     spec.To,
+				"error":  err.Error(),
+			})
+		} else {
+			analysis.Intents = append(analysis.Intents, models.Intent{
+				Type:       models.IntentTypeDateRange,
+				Values:     formatDateRangeValues(from, to),
+				Confidence: intentConfidence(spec.Confidence),
+			})
+		}
+	}
+
 	return analysis, nil
 }
+
+// extractJSONObject returns the span from the first '{' to the last '}' of response, or an
+// empty string when there is none. Models without JSON mode often wrap the object in prose.
+func extractJSONObject(response string) string {
+	startIdx := strings.IndexByte(response, '{')
+	endIdx := strings.LastIndexByte(response, '}')
+
+	if startIdx == -1 || endIdx == -1 || startIdx > endIdx {
+		return ""
+	}
+
+	return response[startIdx : endIdx+1]
+}
+
+// resolveNearbyPlaces resolves the places of the nearby intent to coordinates, in order and
+// without duplicates. A response in the single-place form is treated as one place. Places
+// beyond maxNearbyPlaces and places that cannot be resolved are dropped.
+func (s *llmService) resolveNearbyPlaces(ctx context.Context, places []llmNearbyPlace, place string, hintLat, hintLon *float64) []models.Location {
+	if len(places) == 0 && (strings.TrimSpace(place) != "" || (hintLat != nil && hintLon != nil)) {
+		places = []llmNearbyPlace{{Place: place, Lat: hintLat, Lon: hintLon}}
+	}
+	if len(places) > maxNearbyPlaces {
+		s.logger.Warn("Dropping nearby places beyond the limit", map[string]interface{}{
+			"places": len(places),
+			"limit":  maxNearbyPlaces,
+		})
+		places = places[:maxNearbyPlaces]
+	}
+
+	var points []models.Location
+	for _, p := range places {
+		location := s.resolveNearby(ctx, p.Place, p.Lat, p.Lon)
+		if location == nil {
+			continue
+		}
+
+		point := models.Location{
+			Latitude:  math.Max(-90, math.Min(90, location.Latitude)),
+			Longitude: math.Max(-180, math.Min(180, location.Longitude)),
+		}
+		if !slices.Contains(points, point) {
+			points = append(points, point)
+		}
+	}
+	return points
+}
+
+// resolveNearby turns one place of the nearby intent into coordinates. The place name is geocoded when a
+// geocoder is configured; the LLM's lat/lon hint is only used when geocoding is unavailable or fails.
+func (s *llmService) resolveNearby(ctx context.Context, place string, hintLat, hintLon *float64) *models.Location {
+	var hint *models.Location
+	if hintLat != nil && hintLon != nil {
+		hint = &models.Location{Latitude: *hintLat, Longitude: *hintLon}
+	}
+
+	place = strings.TrimSpace(place)
+	if place == "" || s.geocoder == nil {
+		return hint
+	}
+
+	location, err := s.geocoder.Geocode(ctx, place)
+	if err != nil {
+		s.logger.Warn("Failed to geocode place, falling back to LLM coordinates", map[string]interface{}{
+			"place":    place,
+			"has_hint": hint != nil,
+			"error":    err.Error(),
+		})
+		return hint
+	}
+
+	return location
+}
+
+// filterAllowed keeps only the values that exist in the allowed list (case-insensitive),
+// returning them in their canonical allowed spelling. Rejected values are logged.
+func (s *llmService) filterAllowed(kind string, values []string, allowed []string) []string {
+	canonical := make(map[string]string, len(allowed))
+	for _, a := range allowed {
+		canonical[strings.ToLower(a)] = a
+	}
+
+	matched := make([]string, 0, len(values))
+	for _, v := range values {
+		if a, ok := canonical[strings.ToLower(strings.TrimSpace(v))]; ok {
+			matched = appendUnique(matched, a)
+			continue
+		}
+		s.logger.Warn("Dropping value not present in allowed list", map[string]interface{}{
+			"kind":  kind,
+			"value": v,
+		})
+	}
+
+	return matched
+}
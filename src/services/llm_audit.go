@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// llmAuditInsertTimeout bounds the insert of one audit record
+const llmAuditInsertTimeout = 5 * time.Second
+
+// LLMAuditService records sampled query analysis calls for offline prompt evaluation
+type LLMAuditService interface {
+	// Record queues a record for storage and returns immediately. It does nothing while
+	// auditing is disabled or when the record is not sampled.
+	Record(record models.LLMAuditRecord)
+	// List returns a page of records, newest first, and the cursor of the next page
+	List(ctx context.Context, cursor string, limit int) ([]models.LLMAuditRecord, string, error)
+	// Purge deletes the records created before the given time, every record when it is zero
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// llmAuditService implements LLMAuditService. A single background goroutine drains the queue,
+// so audit inserts never add latency to queries.
+type llmAuditService struct {
+	repo   repositories.LLMAuditRepository
+	cfg    *infra.LLMConfig
+	queue  chan models.LLMAuditRecord
+	logger infra.Logger
+}
+
+// NewLLMAuditService creates a new instance of LLMAuditService and, when auditing is enabled,
+// starts storing queued records. Listing and purging work either way, so records kept from
+// an earlier run can still be read or removed.
+func NewLLMAuditService(repo repositories.LLMAuditRepository, cfg *infra.LLMConfig, logger infra.Logger) LLMAuditService {
+	s := &llmAuditService{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+	}
+
+	if cfg.AuditEnabled {
+		s.queue = make(chan models.LLMAuditRecord, cfg.AuditQueueSize)
+		go s.run()
+	}
+
+	return s
+}
+
+// Record queues a sampled record. When the queue is full the record is dropped rather than
+// holding up the query.
+func (s *llmAuditService) Record(record models.LLMAuditRecord) {
+	if s.queue == nil || rand.Float64() >= s.cfg.AuditSampleRate {
+		return
+	}
+
+	select {
+	case s.queue <- record:
+	default:
+		infra.IncrCounter(infra.MetricLLMAuditDropped, 1)
+		s.logger.Warn("LLM audit queue full, record dropped", map[string]interface{}{
+			"queue_size": s.cfg.AuditQueueSize,
+		})
+	}
+}
+
+// List returns a page of records
+func (s *llmAuditService) List(ctx context.Context, cursor string, limit int) ([]models.LLMAuditRecord, string, error) {
+	return s.repo.List(ctx, cursor, limit)
+}
+
+// Purge deletes old records
+func (s *llmAuditService) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return s.repo.Purge(ctx, before)
+}
+
+// run stores queued records one at a time
+func (s *llmAuditService) run() {
+	for record := range s.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), llmAuditInsertTimeout)
+		// The repository logs failures; a lost record only thins the sample
+		_ = s.repo.Insert(ctx, &record)
+		cancel()
+	}
+}
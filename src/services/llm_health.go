@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"news-inshorts/src/models"
+)
+
+// llmProbeMinInterval is the shortest time between two health probes, however often health
+// is polled
+const llmProbeMinInterval = time.Minute
+
+// CheckHealth implements LLMService. The probe lists the provider's models, so it spends no
+// tokens and is neither recorded as usage nor refused by the token budget. It also bypasses
+// the circuit breaker and concurrency limiter: it reports on the API, not on our traffic.
+func (s *llmService) CheckHealth(ctx context.Context) models.LLMHealth {
+	s.probeMu.Lock()
+	defer s.probeMu.Unlock()
+
+	if last := s.LastHealth(); last != nil && s.clock.Now().Sub(last.CheckedAt) < llmProbeMinInterval {
+		return *last
+	}
+
+	health := s.probe(ctx)
+
+	s.healthMu.Lock()
+	s.lastHealth = &health
+	s.healthMu.Unlock()
+
+	if health.Status != models.LLMReachable {
+		s.logger.Warn("LLM health probe failed", map[string]interface{}{
+			"provider": s.chat.Name(),
+			"error":    health.Error,
+		})
+	}
+
+	return health
+}
+
+// LastHealth implements LLMService
+func (s *llmService) LastHealth() *models.LLMHealth {
+	s.healthMu.RLock()
+	defer s.healthMu.RUnlock()
+
+	if s.lastHealth == nil {
+		return nil
+	}
+	health := *s.lastHealth
+	return &health
+}
+
+// probe sends one probe request within the configured timeout
+func (s *llmService) probe(ctx context.Context) models.LLMHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.config.HealthProbeTimeout)
+	defer cancel()
+
+	start := s.clock.Now()
+	health := models.LLMHealth{
+		Status:    models.LLMUnreachable,
+		CheckedAt: start.UTC(),
+	}
+
+	req, err := s.chat.NewProbeRequest(ctx)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+
+	resp, err := s.httpClient.Do(req)
+	health.LatencyMs = s.clock.Now().Sub(start).Milliseconds()
+	if err != nil {
+		health.Error = fmt.Sprintf("failed to call %s API: %s", s.chat.Name(), withoutRequestURL(err))
+		return health
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxLLMErrorBodyLength+1))
+		health.Error = fmt.Sprintf("%s API returned status %d: %s", s.chat.Name(), resp.StatusCode, llmErrorBody(body))
+		return health
+	}
+
+	health.Status = models.LLMReachable
+	return health
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"news-inshorts/src/infra"
+)
+
+// ErrLLMBusy is returned when no LLM request slot frees up within the maximum wait
+var ErrLLMBusy = errors.New("too many in-flight LLM requests")
+
+// bulkPriorityKey marks a context as carrying bulk enrichment work
+type bulkPriorityKey struct{}
+
+// withBulkPriority marks LLM calls made with ctx as bulk work (loads, backfills), which
+// yields to interactive traffic such as queries
+func withBulkPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bulkPriorityKey{}, true)
+}
+
+// isBulkPriority reports whether ctx was marked by withBulkPriority
+func isBulkPriority(ctx context.Context) bool {
+	bulk, _ := ctx.Value(bulkPriorityKey{}).(bool)
+	return bulk
+}
+
+// llmLimiter bounds concurrent outbound LLM requests. Every request takes one of maxInflight
+// global slots; bulk requests must first take one of the smaller pool of bulk slots, so the
+// remaining global slots are always available to interactive requests.
+type llmLimiter struct {
+	global  chan struct{}
+	bulk    chan struct{}
+	maxWait time.Duration
+
+	inflight     atomic.Int64
+	bulkInflight atomic.Int64
+}
+
+// newLLMLimiter creates an llmLimiter
+func newLLMLimiter(maxInflight, maxBulkInflight int, maxWait time.Duration) *llmLimiter {
+	return &llmLimiter{
+		global:  make(chan struct{}, maxInflight),
+		bulk:    make(chan struct{}, maxBulkInflight),
+		maxWait: maxWait,
+	}
+}
+
+// acquire waits for a request slot until ctx is done or maxWait elapses. On success it
+// returns a function that releases the slot.
+func (l *llmLimiter) acquire(ctx context.Context) (func(), error) {
+	bulk := isBulkPriority(ctx)
+	start := time.Now()
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+
+	if bulk {
+		if err := l.wait(ctx, l.bulk, timer); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.wait(ctx, l.global, timer); err != nil {
+		if bulk {
+			<-l.bulk
+		}
+		return nil, err
+	}
+
+	infra.IncrCounter(infra.MetricLLMSemaphoreAcquired, 1)
+	infra.IncrCounter(infra.MetricLLMSemaphoreWaitMs, time.Since(start).Milliseconds())
+	infra.SetGauge(infra.MetricLLMInflight, l.inflight.Add(1))
+	if bulk {
+		infra.SetGauge(infra.MetricLLMInflightBulk, l.bulkInflight.Add(1))
+	}
+
+	return func() {
+		<-l.global
+		infra.SetGauge(infra.MetricLLMInflight, l.inflight.Add(-1))
+		if bulk {
+			<-l.bulk
+			infra.SetGauge(infra.MetricLLMInflightBulk, l.bulkInflight.Add(-1))
+		}
+	}, nil
+}
+
+// wait takes a slot from sem, giving up when ctx is done or timer fires
+func (l *llmLimiter) wait(ctx context.Context, sem chan struct{}, timer *time.Timer) error {
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		infra.IncrCounter(infra.MetricLLMSemaphoreTimeouts, 1)
+		return ErrLLMBusy
+	}
+}
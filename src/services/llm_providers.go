@@ -0,0 +1,408 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"news-inshorts/src/infra"
+)
+
+// Chat and embedding provider names
+const (
+	LLMProviderOpenAI    = "openai"
+	LLMProviderAnthropic = "anthropic"
+)
+
+// anthropicVersion is the Messages API version requested from Anthropic
+const anthropicVersion = "2023-06-01"
+
+// chatResult is the text and token usage of one chat completion
+type chatResult struct {
+	Content          string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// chatProvider translates single-turn prompts to and from a chat API. Sending, retries,
+// circuit breaking and usage tracking are handled by llmService, so providers only deal
+// with the wire format.
+type chatProvider interface {
+	Name() string
+	NewRequest(ctx context.Context, prompt string, maxTokens int, jsonMode bool) (*http.Request, error)
+	ParseResponse(body []byte, jsonMode bool) (*chatResult, error)
+	// NewProbeRequest builds an authenticated request that spends no tokens, for checking
+	// that the API is reachable and accepts the configured key
+	NewProbeRequest(ctx context.Context) (*http.Request, error)
+}
+
+// embeddingProvider translates texts to and from an embeddings API
+type embeddingProvider interface {
+	Name() string
+	// Model names the model producing the vectors, so stored vectors can be traced to it
+	Model() string
+	// MaxInputTokens is the longest input the model accepts
+	MaxInputTokens() int
+	NewRequest(ctx context.Context, text string) (*http.Request, error)
+	// ParseResponse returns the embedding and the number of tokens it consumed
+	ParseResponse(body []byte) ([]float64, int, error)
+}
+
+// newChatProvider returns the chat provider selected by cfg.ChatProvider
+func newChatProvider(cfg *infra.LLMConfig) chatProvider {
+	if cfg.ChatProvider == LLMProviderAnthropic {
+		return &anthropicChat{
+			apiURL: cfg.AnthropicAPIURL,
+			apiKey: cfg.AnthropicAPIKey,
+			model:  cfg.AnthropicModel,
+		}
+	}
+
+	return &openAIChat{
+		apiURL: cfg.APIURL,
+		apiKey: cfg.APIKey,
+	}
+}
+
+// newEmbeddingProvider returns the embedding provider selected by cfg.EmbeddingProvider.
+// OpenAI is the only one: the articles table stores 1536-dimensional vectors.
+func newEmbeddingProvider(cfg *infra.LLMConfig) embeddingProvider {
+	return &openAIEmbeddings{
+		apiURL: cfg.EmbeddingAPIURL,
+		apiKey: cfg.EmbeddingAPIKey,
+		model:  cfg.EmbeddingModel,
+	}
+}
+
+// newJSONRequest builds a POST request carrying payload as JSON
+func newJSONRequest(ctx context.Context, url string, payload interface{}) (*http.Request, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// openAIRequest represents the request structure for OpenAI API
+type openAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// openAIResponseFormat constrains the model output format (JSON mode)
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// openAIMessage represents a message in the OpenAI API request
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIResponse represents the response structure from OpenAI API
+type openAIResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index   int `json:"index"`
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// openAIChat implements chatProvider for the OpenAI chat completions API and compatible APIs
+type openAIChat struct {
+	apiURL string
+	apiKey string
+}
+
+// Name implements chatProvider
+func (p *openAIChat) Name() string {
+	return LLMProviderOpenAI
+}
+
+// NewRequest implements chatProvider. When jsonMode is set the model is constrained to emit
+// a single JSON object.
+func (p *openAIChat) NewRequest(ctx context.Context, prompt string, maxTokens int, jsonMode bool) (*http.Request, error) {
+	reqBody := openAIRequest{
+		Model: "gpt-3.5-turbo",
+		Messages: []openAIMessage{
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+		Temperature: 0.7,
+		MaxTokens:   maxTokens,
+	}
+
+	if jsonMode {
+		reqBody.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+	}
+
+	req, err := newJSONRequest(ctx, fmt.Sprintf("%s/chat/completions", p.apiURL), reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	return req, nil
+}
+
+// NewProbeRequest implements chatProvider by listing the available models
+func (p *openAIChat) NewProbeRequest(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/models", p.apiURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	return req, nil
+}
+
+// ParseResponse implements chatProvider
+func (p *openAIChat) ParseResponse(body []byte, jsonMode bool) (*chatResult, error) {
+	var apiResp openAIResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return nil, fmt.Errorf("OpenAI API error: %s", apiResp.Error.Message)
+	}
+
+	result := &chatResult{
+		PromptTokens:     apiResp.Usage.PromptTokens,
+		CompletionTokens: apiResp.Usage.CompletionTokens,
+	}
+
+	if len(apiResp.Choices) == 0 {
+		return result, fmt.Errorf("no choices in OpenAI response")
+	}
+
+	result.Content = apiResp.Choices[0].Message.Content
+	return result, nil
+}
+
+// anthropicRequest represents the request structure for the Anthropic Messages API
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature"`
+	Messages    []anthropicMessage `json:"messages"`
+}
+
+// anthropicMessage represents a message in the Anthropic Messages API request
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicResponse represents the response structure from the Anthropic Messages API
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// anthropicChat implements chatProvider for the Anthropic Messages API
+type anthropicChat struct {
+	apiURL string
+	apiKey string
+	model  string
+}
+
+// Name implements chatProvider
+func (p *anthropicChat) Name() string {
+	return LLMProviderAnthropic
+}
+
+// NewRequest implements chatProvider. The Messages API has no JSON mode, so in jsonMode the
+// assistant turn is prefilled with "{", which makes the model continue a JSON object.
+func (p *anthropicChat) NewRequest(ctx context.Context, prompt string, maxTokens int, jsonMode bool) (*http.Request, error) {
+	reqBody := anthropicRequest{
+		Model:       p.model,
+		MaxTokens:   maxTokens,
+		Temperature: 0.7,
+		Messages: []anthropicMessage{
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+	}
+
+	if jsonMode {
+		reqBody.Messages = append(reqBody.Messages, anthropicMessage{Role: "assistant", Content: "{"})
+	}
+
+	req, err := newJSONRequest(ctx, fmt.Sprintf("%s/v1/messages", p.apiURL), reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	return req, nil
+}
+
+// NewProbeRequest implements chatProvider by listing the available models
+func (p *anthropicChat) NewProbeRequest(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v1/models", p.apiURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	return req, nil
+}
+
+// ParseResponse implements chatProvider
+func (p *anthropicChat) ParseResponse(body []byte, jsonMode bool) (*chatResult, error) {
+	var apiResp anthropicResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return nil, fmt.Errorf("Anthropic API error: %s", apiResp.Error.Message)
+	}
+
+	result := &chatResult{
+		PromptTokens:     apiResp.Usage.InputTokens,
+		CompletionTokens: apiResp.Usage.OutputTokens,
+	}
+
+	var text strings.Builder
+	for _, block := range apiResp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return result, fmt.Errorf("no text content in Anthropic response")
+	}
+
+	result.Content = text.String()
+	if jsonMode {
+		// Restore the prefilled opening brace
+		result.Content = "{" + result.Content
+	}
+
+	return result, nil
+}
+
+// openAIEmbeddingMaxTokens is the input limit of the OpenAI embedding models
+const openAIEmbeddingMaxTokens = 8191
+
+// openAIEmbeddings implements embeddingProvider for the OpenAI embeddings API
+type openAIEmbeddings struct {
+	apiURL string
+	apiKey string
+	model  string
+}
+
+// Name implements embeddingProvider
+func (p *openAIEmbeddings) Name() string {
+	return LLMProviderOpenAI
+}
+
+// Model implements embeddingProvider
+func (p *openAIEmbeddings) Model() string {
+	return p.model
+}
+
+// MaxInputTokens implements embeddingProvider
+func (p *openAIEmbeddings) MaxInputTokens() int {
+	return openAIEmbeddingMaxTokens
+}
+
+// NewRequest implements embeddingProvider
+func (p *openAIEmbeddings) NewRequest(ctx context.Context, text string) (*http.Request, error) {
+	embeddingRequest := struct {
+		Model string `json:"model"`
+		Input string `json:"input"`
+	}{
+		Model: p.model,
+		Input: text,
+	}
+
+	req, err := newJSONRequest(ctx, fmt.Sprintf("%s/embeddings", p.apiURL), embeddingRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	return req, nil
+}
+
+// ParseResponse implements embeddingProvider
+func (p *openAIEmbeddings) ParseResponse(body []byte) ([]float64, int, error) {
+	var embeddingResp struct {
+		Data []struct {
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+		Usage struct {
+			PromptTokens int `json:"prompt_tokens"`
+		} `json:"usage"`
+		Error *struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+		} `json:"error,omitempty"`
+	}
+
+	if err := json.Unmarshal(body, &embeddingResp); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+	}
+
+	if embeddingResp.Error != nil {
+		return nil, 0, fmt.Errorf("%w: OpenAI embeddings API error: %s", ErrLLMUnavailable, embeddingResp.Error.Message)
+	}
+
+	if len(embeddingResp.Data) == 0 {
+		return nil, embeddingResp.Usage.PromptTokens, fmt.Errorf("no embedding data in OpenAI response")
+	}
+
+	return embeddingResp.Data[0].Embedding, embeddingResp.Usage.PromptTokens, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+)
+
+// fakeAPIKey is the LLM API key of the redaction tests; it must never be logged
+const fakeAPIKey = "sk-test-FAKEKEY0123456789abcdef"
+
+// newKeyEchoingAPI answers every call with a 500 whose body echoes the credentials of the
+// request, like some providers do in their error messages
+func newKeyEchoingAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"error":{"message":"Incorrect API key provided: %s","authorization":%q,"api_key":%q}}`,
+			strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), r.Header.Get("Authorization"), fakeAPIKey)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newKeyedLLMService returns an LLM service calling the API at url with fakeAPIKey
+func newKeyedLLMService(url string, logger infra.Logger) LLMService {
+	cfg := &infra.LLMConfig{
+		ChatProvider:            LLMProviderOpenAI,
+		APIKey:                  fakeAPIKey,
+		APIURL:                  url,
+		EmbeddingProvider:       LLMProviderOpenAI,
+		EmbeddingAPIKey:         fakeAPIKey,
+		EmbeddingAPIURL:         url,
+		EmbeddingModel:          "text-embedding-3-small",
+		BreakerFailureThreshold: 100,
+		BreakerOpenDuration:     time.Minute,
+		MaxInflight:             4,
+		MaxInflightBulk:         4,
+		MaxWait:                 time.Second,
+	}
+	return NewLLMService(cfg, nil, nil, NewLLMAuditService(nil, cfg, logger), infra.SystemClock{}, logger)
+}
+
+// TestLLMFailuresDoNotLogAPIKey makes LLM calls fail in ways that put the API key in the
+// error and checks the key appears nowhere in what was logged
+func TestLLMFailuresDoNotLogAPIKey(t *testing.T) {
+	server := newKeyEchoingAPI(t)
+
+	load := func(t *testing.T, llm LLMService, logger infra.Logger) {
+		svc := newLoadTestService(llm, &loadArticleRepo{}, logger)
+		path := writeArticlesFile(t, []models.Article{{
+			Title:       "Storm hits the coast",
+			Description: "A storm made landfall overnight.",
+			URL:         "https://example.com/storm",
+			Category:    []string{"world"},
+		}})
+		if _, err := svc.LoadFromJSON(context.Background(), path, false, false); err != nil {
+			t.Fatalf("LoadFromJSON failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		run        func(t *testing.T, llm LLMService, logger infra.Logger)
+		wantLogged []string
+	}{
+		{
+			name:       "enrichment with the key echoed in the error body",
+			url:        server.URL,
+			run:        load,
+			wantLogged: []string{"Failed to generate summary for article", "Failed to generate embedding for article"},
+		},
+		{
+			name:       "enrichment against an API with the key in its URL",
+			url:        "http://127.0.0.1:1/v1?api_key=" + fakeAPIKey,
+			run:        load,
+			wantLogged: []string{"Failed to generate summary for article", "Failed to generate embedding for article"},
+		},
+		{
+			name: "query analysis with the key echoed in the error body",
+			url:  server.URL,
+			run: func(t *testing.T, llm LLMService, logger infra.Logger) {
+				if _, err := llm.ProcessQuery(context.Background(), "storm news", nil, nil); err == nil {
+					t.Fatal("ProcessQuery succeeded, want an error")
+				} else if strings.Contains(err.Error(), fakeAPIKey) {
+					t.Errorf("returned error contains the API key: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := infra.NewRecordingLogger()
+			tt.run(t, newKeyedLLMService(tt.url, logger), logger)
+
+			entries := logger.Entries()
+			if len(entries) == 0 {
+				t.Fatal("nothing was logged")
+			}
+			for _, message := range tt.wantLogged {
+				if len(logger.EntriesWithMessage(message)) == 0 {
+					t.Errorf("%q was not logged", message)
+				}
+			}
+			for _, entry := range entries {
+				logged := entry.Message + " " + fmt.Sprint(entry.Fields)
+				if entry.Err != nil {
+					logged += " " + entry.Err.Error()
+				}
+				if strings.Contains(logged, fakeAPIKey) {
+					t.Errorf("%s entry %q contains the API key: %s", entry.Level, entry.Message, logged)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+)
+
+// fakeGeocoder resolves the places it knows and fails for the rest
+type fakeGeocoder struct {
+	places map[string]models.Location
+	calls  []string
+}
+
+func (g *fakeGeocoder) Geocode(ctx context.Context, place string) (*models.Location, error) {
+	g.calls = append(g.calls, place)
+	location, ok := g.places[place]
+	if !ok {
+		return nil, errors.New("geocoder unavailable")
+	}
+	return &location, nil
+}
+
+func (g *fakeGeocoder) ReverseGeocode(ctx context.Context, lat, lon float64) (*models.Place, error) {
+	return nil, errors.New("not implemented")
+}
+
+// nearbyValues returns the values of the nearby intent of analysis, or nil when it has none
+func nearbyValues(analysis *models.QueryAnalysis) []string {
+	for _, intent := range analysis.Intents {
+		if intent.Type == models.IntentTypeNearby {
+			return intent.Values.([]string)
+		}
+	}
+	return nil
+}
+
+func TestProcessQueryGeocodesNearbyPlaces(t *testing.T) {
+	const geocodeWarning = "Failed to geocode place, falling back to LLM coordinates"
+
+	tests := []struct {
+		name        string
+		response    string
+		geocoder    map[string]models.Location
+		wantNearby  []string
+		wantCalls   []string
+		wantWarning bool
+	}{
+		{
+			name:       "geocoded coordinates replace the hint",
+			response:   `{"entities":["Pune"],"intent":{"nearby":{"places":[{"place":"Pune","lat":10,"lon":10}]}}}`,
+			geocoder:   map[string]models.Location{"Pune": {Latitude: 18.52, Longitude: 73.85}},
+			wantNearby: []string{"18.520000", "73.850000"},
+			wantCalls:  []string{"Pune"},
+		},
+		{
+			name:        "geocoding failure falls back to the hint",
+			response:    `{"entities":["Pune"],"intent":{"nearby":{"places":[{"place":"Pune","lat":18.5,"lon":73.9}]}}}`,
+			wantNearby:  []string{"18.500000", "73.900000"},
+			wantCalls:   []string{"Pune"},
+			wantWarning: true,
+		},
+		{
+			name:        "geocoding failure without a hint drops the place",
+			response:    `{"entities":["Atlantis"],"intent":{"nearby":{"places":[{"place":"Atlantis"}]}}}`,
+			wantCalls:   []string{"Atlantis"},
+			wantWarning: true,
+		},
+		{
+			name:     "several places are geocoded in order",
+			response: `{"entities":["Chennai","Bengaluru"],"intent":{"nearby":{"places":[{"place":"Chennai"},{"place":"Bengaluru"}]}}}`,
+			geocoder: map[string]models.Location{
+				"Chennai":   {Latitude: 13.08, Longitude: 80.27},
+				"Bengaluru": {Latitude: 12.97, Longitude: 77.59},
+			},
+			wantNearby: []string{"13.080000", "80.270000", "12.970000", "77.590000"},
+			wantCalls:  []string{"Chennai", "Bengaluru"},
+		},
+		{
+			name:     "coordinates without a place name are not geocoded",
+			response: `{"entities":[],"intent":{"nearby":{"places":[{"place":"","lat":12.97,"lon":77.59}]}}}`,
+			// The hint is all there is, so it is used as is
+			wantNearby: []string{"12.970000", "77.590000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockOpenAI(t, tt.response)
+			geocoder := &fakeGeocoder{places: tt.geocoder}
+			logger := infra.NewRecordingLogger()
+			llm := newTestLLMServiceWith(server.URL, geocoder, logger)
+
+			analysis, err := llm.ProcessQuery(context.Background(), "news near the place", nil, nil)
+			if err != nil {
+				t.Fatalf("ProcessQuery failed: %v", err)
+			}
+
+			if got := nearbyValues(analysis); !reflect.DeepEqual(got, tt.wantNearby) {
+				t.Errorf("nearby values = %v, want %v", got, tt.wantNearby)
+			}
+			if !reflect.DeepEqual(geocoder.calls, tt.wantCalls) {
+				t.Errorf("geocoded %v, want %v", geocoder.calls, tt.wantCalls)
+			}
+			if got := len(logger.EntriesWithMessage(geocodeWarning)) > 0; got != tt.wantWarning {
+				t.Errorf("geocoding warning logged = %v, want %v", got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+// TestProcessQueryWithoutGeocoder checks that the LLM's coordinates are used as is when no
+// geocoder is configured
+func TestProcessQueryWithoutGeocoder(t *testing.T) {
+	server := newMockOpenAI(t, `{"entities":["Pune"],"intent":{"nearby":{"places":[{"place":"Pune","lat":18.5,"lon":73.9}]}}}`)
+	llm := newTestLLMServiceWith(server.URL, nil, infra.NewRecordingLogger())
+
+	analysis, err := llm.ProcessQuery(context.Background(), "news in Pune", nil, nil)
+	if err != nil {
+		t.Fatalf("ProcessQuery failed: %v", err)
+	}
+	if got, want := nearbyValues(analysis), []string{"18.500000", "73.900000"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nearby values = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LLM operation types that token usage is tracked for
+const (
+	LLMOperationQueryAnalysis = "query_analysis"
+	LLMOperationSummary       = "summary"
+	LLMOperationEmbedding     = "embedding"
+	LLMOperationSentiment     = "sentiment"
+	LLMOperationCategorize    = "categorize"
+	LLMOperationEntities      = "entities"
+)
+
+// llmUsageRetention is how long a day's usage is kept in Redis after it was last updated
+const llmUsageRetention = 8 * 24 * time.Hour
+
+// ErrLLMBudgetExceeded is returned instead of calling the LLM once the daily token budget is spent
+var ErrLLMBudgetExceeded = errors.New("daily LLM token budget exceeded")
+
+// llmUsageTracker accumulates token usage per UTC day in Redis, so counts and the budget
+// survive restarts and are shared by all instances. Each day is a hash of
+// "<operation>:prompt" / "<operation>:completion" counters plus a "total" counter.
+type llmUsageTracker struct {
+	redisClient *redis.Client
+	dailyBudget int64
+	log         infra.Logger
+}
+
+// newLLMUsageTracker creates an llmUsageTracker; a dailyBudget of 0 means unlimited
+func newLLMUsageTracker(redisClient *redis.Client, dailyBudget int64, logger infra.Logger) *llmUsageTracker {
+	return &llmUsageTracker{
+		redisClient: redisClient,
+		dailyBudget: dailyBudget,
+		log:         logger,
+	}
+}
+
+// llmUsageKey returns the Redis key holding usage for the UTC day containing t
+func llmUsageKey(t time.Time) string {
+	return infra.LLMUsageKey(t.UTC().Format("2006-01-02"))
+}
+
+// record adds the tokens used by one call. Tracking failures are logged and otherwise ignored.
+func (t *llmUsageTracker) record(ctx context.Context, operation string, promptTokens, completionTokens int) {
+	infra.IncrCounter(infra.MetricLLMPromptTokensPrefix+operation, int64(promptTokens))
+	infra.IncrCounter(infra.MetricLLMCompletionTokensPrefix+operation, int64(completionTokens))
+
+	if t.redisClient == nil {
+		return
+	}
+
+	// Usage must be recorded even when the request that caused it was cancelled
+	ctx = context.WithoutCancel(ctx)
+	key := llmUsageKey(time.Now())
+	spent := int64(promptTokens + completionTokens)
+
+	pipe := t.redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, key, operation+":prompt", int64(promptTokens))
+	pipe.HIncrBy(ctx, key, operation+":completion", int64(completionTokens))
+	total := pipe.HIncrBy(ctx, key, "total", spent)
+	pipe.Expire(ctx, key, llmUsageRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.log.Warn("Failed to record LLM token usage", map[string]interface{}{
+			"operation": operation,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	// Warn once, on the call that crosses the budget
+	if t.dailyBudget > 0 && total.Val() >= t.dailyBudget && total.Val()-spent < t.dailyBudget {
+		infra.SetGauge(infra.MetricLLMBudgetExceeded, 1)
+		t.log.Warn("Daily LLM token budget exceeded: enrichment is disabled and query analysis uses the rule-based parser until the budget resets at 00:00 UTC", map[string]interface{}{
+			"daily_budget": t.dailyBudget,
+			"total_tokens": total.Val(),
+			"operation":    operation,
+		})
+	}
+}
+
+// budgetExceeded reports whether today's budget is spent. If Redis cannot be read the
+// budget is not enforced.
+func (t *llmUsageTracker) budgetExceeded(ctx context.Context) bool {
+	if t.dailyBudget <= 0 || t.redisClient == nil {
+		return false
+	}
+
+	total, err := t.redisClient.HGet(ctx, llmUsageKey(time.Now()), "total").Int64()
+	if err != nil {
+		if err != redis.Nil {
+			t.log.Warn("Failed to read LLM token usage", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		infra.SetGauge(infra.MetricLLMBudgetExceeded, 0)
+		return false
+	}
+
+	exceeded := total >= t.dailyBudget
+	if exceeded {
+		infra.SetGauge(infra.MetricLLMBudgetExceeded, 1)
+	} else {
+		infra.SetGauge(infra.MetricLLMBudgetExceeded, 0)
+	}
+	return exceeded
+}
+
+// usage returns today's usage per operation
+func (t *llmUsageTracker) usage(ctx context.Context) (*models.LLMUsage, error) {
+	now := time.Now()
+	result := &models.LLMUsage{
+		Date: now.UTC().Format("2006-01-02"),
+		Operations: map[string]models.LLMTokenUsage{
+			LLMOperationQueryAnalysis: {},
+			LLMOperationSummary:       {},
+			LLMOperationEmbedding:     {},
+			LLMOperationSentiment:     {},
+			LLMOperationCategorize:    {},
+			LLMOperationEntities:      {},
+		},
+		DailyBudget: t.dailyBudget,
+	}
+
+	if t.redisClient == nil {
+		return result, nil
+	}
+
+	fields, err := t.redisClient.HGetAll(ctx, llmUsageKey(now)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LLM token usage: %w", err)
+	}
+
+	for field, value := range fields {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if field == "total" {
+			result.TotalTokens = count
+			continue
+		}
+
+		operation, kind, found := strings.Cut(field, ":")
+		if !found {
+			continue
+		}
+
+		usage := result.Operations[operation]
+		switch kind {
+		case "prompt":
+			usage.PromptTokens = count
+		case "completion":
+			usage.CompletionTokens = count
+		}
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+		result.Operations[operation] = usage
+	}
+
+	result.BudgetExceeded = t.dailyBudget > 0 && result.TotalTokens >= t.dailyBudget
+
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"news-inshorts/src/models"
+)
+
+// matchRecorderKey is the context key under which FilterChain stores its matchRecorder
+type matchRecorderKey struct{}
+
+// matchRecorder collects per-article match metadata (distance, similarity, matched values)
+// produced by the filters of a single chain execution
+type matchRecorder struct {
+	mu   sync.Mutex
+	byID map[string]*models.MatchInfo
+}
+
+// withMatchRecorder returns a context carrying a fresh matchRecorder
+func withMatchRecorder(ctx context.Context) (context.Context, *matchRecorder) {
+	recorder := &matchRecorder{
+		byID: make(map[string]*models.MatchInfo),
+	}
+	return context.WithValue(ctx, matchRecorderKey{}, recorder), recorder
+}
+
+// recordMatch applies fn to the match metadata of an article. It is a no-op when the
+// context carries no recorder, so filters can be used outside a FilterChain.
+func recordMatch(ctx context.Context, articleID string, fn func(info *models.MatchInfo)) {
+	recorder, ok := ctx.Value(matchRecorderKey{}).(*matchRecorder)
+	if !ok {
+		return
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	info, exists := recorder.byID[articleID]
+	if !exists {
+		info = &models.MatchInfo{}
+		recorder.byID[articleID] = info
+	}
+	fn(info)
+}
+
+// enrich wraps articles with their recorded match metadata and 1-based rank
+func (r *matchRecorder) enrich(articles []models.Article) []models.EnrichedArticle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	enriched := make([]models.EnrichedArticle, 0, len(articles))
+	for i, article := range articles {
+		e := models.EnrichedArticle{
+			Article: article,
+			Rank:    i + 1,
+		}
+		if info, ok := r.byID[article.ID]; ok {
+			e.MatchInfo = *info
+		}
+		enriched = append(enriched, e)
+	}
+
+	return enriched
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+
+	"news-inshorts/src/models"
+)
+
+// maxNearbyPlaces caps how many places of one query are geocoded and searched around
+const maxNearbyPlaces = 5
+
+// formatNearbyValues encodes the points of a nearby intent as its values: latitude and
+// longitude of each point, followed by the radius in km when the query stated one. A single
+// point without radius encodes as [lat, lon], as nearby intents always did; a query such as
+// "news near me" names no place and encodes as just the radius.
+func formatNearbyValues(points []models.Location, radiusKm *float64) []string {
+	values := make([]string, 0, 2*len(points)+1)
+	for _, point := range points {
+		values = append(values, fmt.Sprintf("%f", point.Latitude), fmt.Sprintf("%f", point.Longitude))
+	}
+	if radiusKm != nil {
+		values = append(values, fmt.Sprintf("%f", *radiusKm))
+	}
+	return values
+}
+
+// parseNearbyValues decodes nearby intent values produced by formatNearbyValues. radius is
+// empty when the values carry none; points is empty when they carry only a radius.
+func parseNearbyValues(values interface{}) (points []models.Location, radius string, ok bool) {
+	list, isList := values.([]string)
+	if !isList || len(list) == 0 {
+		return nil, "", false
+	}
+
+	// An odd count ends with the radius
+	if len(list)%2 == 1 {
+		radius = list[len(list)-1]
+		list = list[:len(list)-1]
+	}
+
+	points = make([]models.Location, 0, len(list)/2)
+	for i := 0; i < len(list); i += 2 {
+		lat, err := strconv.ParseFloat(list[i], 64)
+		if err != nil {
+			return nil, "", false
+		}
+		lon, err := strconv.ParseFloat(list[i+1], 64)
+		if err != nil {
+			return nil, "", false
+		}
+		points = append(points, models.Location{Latitude: lat, Longitude: lon})
+	}
+	return points, radius, true
+}
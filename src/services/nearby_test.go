@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+)
+
+func TestNearbyRadiusClamped(t *testing.T) {
+	chain := newTestFilterChain(&chainArticleRepo{})
+
+	tests := []struct {
+		radius string
+		want   float64
+	}{
+		{"", 50},
+		{"1", 5},
+		{"4.99", 5},
+		{"5", 5},
+		{"10", 10},
+		{"500", 500},
+		{"500.01", 500},
+		{"20000", 500},
+		{"0", 50},
+		{"-3", 50},
+		{"NaN", 50},
+		{"+Inf", 500},
+		{"ten", 50},
+	}
+
+	for _, tt := range tests {
+		if got := chain.nearbyRadius(tt.radius); got != tt.want {
+			t.Errorf("nearbyRadius(%q) = %v, want %v", tt.radius, got, tt.want)
+		}
+	}
+}
+
+// TestQueryRadiusClamped runs the radius_km the LLM answers through query analysis and the
+// filter chain and checks the database is searched within [5, 500] km
+func TestQueryRadiusClamped(t *testing.T) {
+	caller := &models.Location{Latitude: 12.97, Longitude: 77.59}
+
+	tests := []struct {
+		name     string
+		response string
+		location *models.Location
+		want     float64
+	}{
+		{name: "place with a small radius", response: `{"entities":["Pune"],"intent":{"nearby":{"places":[{"place":"Pune","lat":18.52,"lon":73.85}],"radius_km":1,"confidence":0.9}}}`, want: 5},
+		{name: "place within bounds", response: `{"entities":["Pune"],"intent":{"nearby":{"places":[{"place":"Pune","lat":18.52,"lon":73.85}],"radius_km":25,"confidence":0.9}}}`, want: 25},
+		{name: "place with a huge radius", response: `{"entities":["India"],"intent":{"nearby":{"places":[{"place":"India","lat":20.59,"lon":78.96}],"radius_km":3000,"confidence":0.9}}}`, want: 500},
+		{name: "place without a radius", response: `{"entities":["Pune"],"intent":{"nearby":{"places":[{"place":"Pune","lat":18.52,"lon":73.85}],"radius_km":null,"confidence":0.9}}}`, want: 50},
+		{name: "place with a negative radius", response: `{"entities":["Pune"],"intent":{"nearby":{"places":[{"place":"Pune","lat":18.52,"lon":73.85}],"radius_km":-10,"confidence":0.9}}}`, want: 50},
+		{name: "near me with a small radius", response: `{"entities":[],"intent":{"nearby":{"places":[],"radius_km":0.5,"confidence":0.9}}}`, location: caller, want: 5},
+		{name: "near me with a huge radius", response: `{"entities":[],"intent":{"nearby":{"places":[],"radius_km":1000,"confidence":0.9}}}`, location: caller, want: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockOpenAI(t, tt.response)
+			llm := newTestLLMServiceWith(server.URL, nil, infra.NewRecordingLogger())
+
+			analysis, err := llm.ProcessQuery(context.Background(), "news nearby", nil, nil)
+			if err != nil {
+				t.Fatalf("ProcessQuery failed: %v", err)
+			}
+
+			repo := &chainArticleRepo{}
+			if _, err := newTestFilterChain(repo).Execute(context.Background(), analysis.Intents, nil, tt.location, nil, nil); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			nearby := nearbyRequests(repo)
+			if len(nearby) != 1 {
+				t.Fatalf("nearby searches = %+v, want one", nearby)
+			}
+			if nearby[0].Radius != tt.want {
+				t.Errorf("radius = %v km, want %v km", nearby[0].Radius, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+)
+
+// objectStoreTimeout bounds each request to the object store; a part upload is one request
+const objectStoreTimeout = 5 * time.Minute
+
+// maxObjectStoreErrorBody is how much of an error response is kept in the returned error
+const maxObjectStoreErrorBody = 512
+
+// ObjectStore uploads objects to a bucket
+type ObjectStore interface {
+	// Upload streams r to key and returns the number of bytes stored. Content that does not
+	// fit in one part is sent as a multipart upload, which is aborted if the upload fails.
+	Upload(ctx context.Context, key string, r io.Reader, contentType string) (int64, error)
+}
+
+// s3ObjectStore implements ObjectStore against the S3 API, signing requests with AWS
+// Signature Version 4. It works with AWS S3 and S3-compatible stores such as MinIO.
+type s3ObjectStore struct {
+	cfg        *infra.S3Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	clock      infra.Clock
+}
+
+// NewS3ObjectStore creates an ObjectStore for the configured bucket, or returns nil when no
+// bucket is configured. The endpoint is expected to have passed Config.Validate.
+func NewS3ObjectStore(cfg *infra.S3Config, clock infra.Clock) ObjectStore {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil
+	}
+
+	return &s3ObjectStore{
+		cfg:        cfg,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: objectStoreTimeout},
+		clock:      clock,
+	}
+}
+
+// Upload reads the first part; content that ends within it is stored with a single PUT
+func (s *s3ObjectStore) Upload(ctx context.Context, key string, r io.Reader, contentType string) (int64, error) {
+	part := make([]byte, s.cfg.PartSize)
+	n, err := io.ReadFull(r, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		if _, err := s.do(ctx, http.MethodPut, key, nil, part[:n], contentType); err != nil {
+			return 0, err
+		}
+		return int64(n), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read object content: %w", err)
+	}
+
+	return s.uploadMultipart(ctx, key, r, contentType, part)
+}
+
+// uploadMultipart uploads first and the rest of r as parts of a multipart upload
+func (s *s3ObjectStore) uploadMultipart(ctx context.Context, key string, r io.Reader, contentType string, first []byte) (int64, error) {
+	body, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, contentType)
+	if err != nil {
+		return 0, err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &initiated); err != nil || initiated.UploadID == "" {
+		return 0, fmt.Errorf("failed to start multipart upload: unexpected response")
+	}
+
+	size, err := s.uploadParts(ctx, key, initiated.UploadID, r, first)
+	if err != nil {
+		// Abort with a fresh context so a cancelled upload still frees the stored parts
+		abortCtx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+		defer cancel()
+		s.do(abortCtx, http.MethodDelete, key, url.Values{"uploadId": {initiated.UploadID}}, nil, "")
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// s3CompletedPart is a part listed in a CompleteMultipartUpload request
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadParts sends first and then every following PartSize chunk of r, and completes the upload
+func (s *s3ObjectStore) uploadParts(ctx context.Context, key, uploadID string, r io.Reader, first []byte) (int64, error) {
+	var parts []s3CompletedPart
+	var size int64
+
+	part, last := first, false
+	for {
+		etag, err := s.doForETag(ctx, key, url.Values{
+			"partNumber": {strconv.Itoa(len(parts) + 1)},
+			"uploadId":   {uploadID},
+		}, part)
+		if err != nil {
+			return 0, err
+		}
+		parts = append(parts, s3CompletedPart{PartNumber: len(parts) + 1, ETag: etag})
+		size += int64(len(part))
+		if last {
+			break
+		}
+
+		part = make([]byte, s.cfg.PartSize)
+		n, err := io.ReadFull(r, part)
+		part = part[:n]
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			// A short read is the last part
+			last = true
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to read object content: %w", err)
+		}
+	}
+
+	complete, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode multipart completion: %w", err)
+	}
+
+	body, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, complete, "application/xml")
+	if err != nil {
+		return 0, err
+	}
+	// S3 may answer 200 with an error document once the parts have been combined
+	if bytes.Contains(body, []byte("<Error>")) {
+		return 0, fmt.Errorf("failed to complete multipart upload: %s", objectStoreErrorBody(body))
+	}
+
+	return size, nil
+}
+
+// doForETag uploads one part and returns its ETag
+func (s *s3ObjectStore) doForETag(ctx context.Context, key string, query url.Values, part []byte) (string, error) {
+	req, err := s.newRequest(ctx, http.MethodPut, key, query, part, "")
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.send(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", errors.New("object store returned no ETag for uploaded part")
+	}
+	return etag, nil
+}
+
+// do sends a signed request and returns the response body
+func (s *s3ObjectStore) do(ctx context.Context, method, key string, query url.Values, body []byte, contentType string) ([]byte, error) {
+	req, err := s.newRequest(ctx, method, key, query, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object store response: %w", err)
+	}
+	return respBody, nil
+}
+
+// send performs req and turns non-2xx responses into errors
+func (s *s3ObjectStore) send(req *http.Request) (*http.Response, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("object store request failed: %w", withoutRequestURL(err))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxObjectStoreErrorBody))
+		return nil, fmt.Errorf("object store returned status %d: %s", resp.StatusCode, objectStoreErrorBody(body))
+	}
+
+	return resp, nil
+}
+
+// objectStoreErrorBody returns an error response body trimmed for inclusion in an error
+func objectStoreErrorBody(body []byte) string {
+	if len(body) > maxObjectStoreErrorBody {
+		body = body[:maxObjectStoreErrorBody]
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// newRequest builds a request for key in the bucket and signs it
+func (s *s3ObjectStore) newRequest(ctx context.Context, method, key string, query url.Values, body []byte, contentType string) (*http.Request, error) {
+	u := *s.endpoint
+	if s.cfg.PathStyle {
+		u.Path = "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	u.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object store request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(req, body)
+	return req, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req
+func (s *s3ObjectStore) sign(req *http.Request, body []byte) {
+	now := s.clock.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	payloadSum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payloadSum[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	requestSum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestSum[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), day)
+	signingKey = hmacSHA256(signingKey, s.cfg.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath percent-encodes every byte of path except unreserved characters and '/', as
+// Signature Version 4 requires
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || s3Unreserved(c) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery encodes query sorted by name with Signature Version 4 escaping
+func s3CanonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, s3EscapeQuery(name)+"="+s3EscapeQuery(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3EscapeQuery percent-encodes every byte of s except unreserved characters
+func s3EscapeQuery(s string) string {
+	return strings.ReplaceAll(s3EscapePath(s), "/", "%2F")
+}
+
+// s3Unreserved reports whether c is an unreserved URI character
+func s3Unreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '~'
+}
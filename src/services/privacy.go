@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PrivacyService handles data-subject requests such as erasing everything held about a user.
+// User ids are only unique within a tenant, so requests cover the tenant of their context.
+type PrivacyService interface {
+	PurgeUser(ctx context.Context, userID string) (int64, error)
+}
+
+// privacyService implements PrivacyService
+type privacyService struct {
+	userEventRepo   repositories.UserEventRepository
+	savedSearchRepo repositories.SavedSearchRepository
+	userPrefRepo    repositories.UserPreferenceRepository
+	redisClient     *redis.Client
+	defaultTenant   string
+	log             infra.Logger
+}
+
+// NewPrivacyService creates a new instance of PrivacyService. Requests whose context names no
+// tenant cover defaultTenant.
+func NewPrivacyService(userEventRepo repositories.UserEventRepository, savedSearchRepo repositories.SavedSearchRepository, userPrefRepo repositories.UserPreferenceRepository, redisClient *redis.Client, defaultTenant string, logger infra.Logger) PrivacyService {
+	return &privacyService{
+		userEventRepo:   userEventRepo,
+		savedSearchRepo: savedSearchRepo,
+		userPrefRepo:    userPrefRepo,
+		redisClient:     redisClient,
+		defaultTenant:   defaultTenant,
+		log:             logger,
+	}
+}
+
+// userCachePatterns returns the Redis key patterns holding data derived from a user of a
+// tenant: personalization data under user:<id>:* and the tenant's interaction idempotency keys
+func userCachePatterns(tenant, userID string) []string {
+	escaped := escapeRedisPattern(userID)
+	return []string{
+		infra.UserDataPrefix + escaped + ":*",
+		infra.IdempotencyKey(escapeRedisPattern(tenant), "interaction", escaped) + ":*",
+	}
+}
+
+// PurgeUser deletes all events, saved searches and preferences recorded for userID in the
+// tenant of ctx and clears the user's cached data. It returns the number of events removed.
+func (s *privacyService) PurgeUser(ctx context.Context, userID string) (int64, error) {
+	deleted, err := s.userEventRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	searchesDeleted, err := s.savedSearchRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return deleted, err
+	}
+
+	if _, err := s.userPrefRepo.DeleteByUserID(ctx, userID); err != nil {
+		return deleted, err
+	}
+
+	clearedKeys := 0
+	for _, pattern := range userCachePatterns(infra.TenantFromContext(ctx, s.defaultTenant), userID) {
+		iter := s.redisClient.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			if err := s.redisClient.Del(ctx, iter.Val()).Err(); err != nil {
+				return deleted, fmt.Errorf("failed to delete cached user data: %w", err)
+			}
+			clearedKeys++
+		}
+		if err := iter.Err(); err != nil {
+			return deleted, fmt.Errorf("failed to scan cached user data: %w", err)
+		}
+	}
+
+	// Never log the raw user id of an erasure request
+	s.log.Info("Purged user data", map[string]interface{}{
+		"user_hash":        utils.HashIdentifier(userID),
+		"events_deleted":   deleted,
+		"searches_deleted": searchesDeleted,
+		"cache_keys":       clearedKeys,
+	})
+
+	return deleted, nil
+}
+
+// escapeRedisPattern escapes glob metacharacters so value matches literally in SCAN MATCH
+func escapeRedisPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return replacer.Replace(value)
+}
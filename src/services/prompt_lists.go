@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minPromptMatchTokenLength is the shortest query token or compacted name matched between the
+// query and allowed list entries; shorter ones match too many names by accident
+const minPromptMatchTokenLength = 3
+
+// boundPromptList selects the entries of an allowed list offered to the LLM for query. Lists
+// of at most limit entries (or any list when limit is 0) are offered whole. Longer ones are
+// cut to the entries matching a query token, followed by the first topK entries, which
+// callers order by article count, capped at limit. The model's output is still validated
+// against the whole list.
+func boundPromptList(query string, values []string, limit, topK int) []string {
+	if limit <= 0 || len(values) <= limit {
+		return values
+	}
+
+	compactQuery := compactName(query)
+	var tokens []string
+	for _, token := range strings.FieldsFunc(strings.ToLower(query), isNameSeparator) {
+		if len(token) >= minPromptMatchTokenLength && !fallbackStopWords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+
+	bounded := make([]string, 0, limit)
+	for _, value := range values {
+		if len(bounded) == limit {
+			return bounded
+		}
+		if matchesQuery(value, compactQuery, tokens) {
+			bounded = append(bounded, value)
+		}
+	}
+
+	for _, value := range values[:min(topK, len(values))] {
+		if len(bounded) == limit {
+			break
+		}
+		bounded = appendUnique(bounded, value)
+	}
+	return bounded
+}
+
+// matchesQuery reports whether an allowed list entry fuzzy-matches the query: the whole name
+// appears in the query ignoring case, spacing and punctuation ("the hindu" for "The Hindu"),
+// or a query token appears in the name, which covers prefixes ("tech" for "technology")
+func matchesQuery(value, compactQuery string, tokens []string) bool {
+	compact := compactName(value)
+	if len(compact) >= minPromptMatchTokenLength && strings.Contains(compactQuery, compact) {
+		return true
+	}
+
+	for _, token := range tokens {
+		if strings.Contains(compact, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// compactName lowercases s and drops everything but letters and digits
+func compactName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isNameSeparator(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// isNameSeparator reports whether r separates the words of a name or query
+func isNameSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
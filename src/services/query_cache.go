@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// queryAnalysisCache is a Redis cache of LLM query analyses. Entries are keyed by the
+// tenant, the normalized query and the allowed source/category lists the analysis was matched
+// against, so new sources or categories in the corpus naturally start a fresh set of entries.
+type queryAnalysisCache struct {
+	redisClient *redis.Client
+	ttl         time.Duration
+	clock       infra.Clock
+	log         infra.Logger
+}
+
+// newQueryAnalysisCache creates a queryAnalysisCache. A nil client or non-positive ttl
+// disables caching. clock tells when the current day ends for analyses with date ranges.
+func newQueryAnalysisCache(redisClient *redis.Client, ttl time.Duration, clock infra.Clock, logger infra.Logger) *queryAnalysisCache {
+	return &queryAnalysisCache{
+		redisClient: redisClient,
+		ttl:         ttl,
+		clock:       clock,
+		log:         logger,
+	}
+}
+
+// enabled reports whether analyses should be cached
+func (qc *queryAnalysisCache) enabled() bool {
+	return qc.redisClient != nil && qc.ttl > 0
+}
+
+// get returns the cached analysis stored under cacheKey
+func (qc *queryAnalysisCache) get(ctx context.Context, cacheKey string) (*models.QueryAnalysis, bool) {
+	val, err := qc.redisClient.Get(ctx, cacheKey).Result()
+	if err != nil {
+		if err != redis.Nil {
+			qc.log.Warn("Failed to get query analysis from Redis", map[string]interface{}{
+				"cache_key": cacheKey,
+				"error":     err.Error(),
+			})
+		}
+		infra.IncrCounter(infra.MetricQueryAnalysisCacheMisses, 1)
+		return nil, false
+	}
+
+	var analysis models.QueryAnalysis
+	if err := json.Unmarshal([]byte(val), &analysis); err != nil {
+		qc.log.Warn("Failed to unmarshal cached query analysis", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+		qc.redisClient.Del(ctx, cacheKey)
+		infra.IncrCounter(infra.MetricQueryAnalysisCacheMisses, 1)
+		return nil, false
+	}
+
+	// JSON decodes intent values into []interface{}; the filter chain expects []string
+	for i := range analysis.Intents {
+		analysis.Intents[i].Values = restoreIntentValues(analysis.Intents[i].Values)
+	}
+
+	infra.IncrCounter(infra.MetricQueryAnalysisCacheHits, 1)
+	qc.log.Debug("Cache hit for query analysis", map[string]interface{}{
+		"cache_key": cacheKey,
+	})
+
+	return &analysis, true
+}
+
+// set stores analysis under cacheKey
+func (qc *queryAnalysisCache) set(ctx context.Context, cacheKey string, analysis *models.QueryAnalysis) {
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		qc.log.Warn("Failed to marshal query analysis for cache", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	// Date ranges are resolved against the current day, so they must not outlive it
+	ttl := qc.ttl
+	if analysis.HasIntent(models.IntentTypeDateRange) {
+		now := qc.clock.Now().UTC()
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		ttl = min(ttl, midnight.Sub(now))
+	}
+
+	if err := qc.redisClient.Set(ctx, cacheKey, data, ttl).Err(); err != nil {
+		qc.log.Warn("Failed to cache query analysis in Redis", map[string]interface{}{
+			"cache_key": cacheKey,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// queryAnalysisCacheKey builds the cache key for query analysed against the tenant's allowed lists.
+// The query is lowercased and its whitespace collapsed so trivially different spellings share
+// an entry; the lists are sorted since their order does not affect the analysis.
+func queryAnalysisCacheKey(tenant, query string, allowedSources, allowedCategories []string) string {
+	listSum := sha256.Sum256([]byte(sortedList(allowedSources) + "\x01" + sortedList(allowedCategories)))
+	querySum := sha256.Sum256([]byte(normalizeAnalysisQuery(query)))
+
+	return infra.QueryAnalysisCacheKey(tenant, hex.EncodeToString(listSum[:8]), hex.EncodeToString(querySum[:16]))
+}
+
+// normalizeAnalysisQuery lowercases query and collapses its whitespace, so queries differing
+// only in case or spacing share an analysis
+func normalizeAnalysisQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// sortedList joins a sorted copy of values, so a list hashes the same in any order
+func sortedList(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
+
+// allowedListHash identifies an allowed source or category list regardless of its order
+func allowedListHash(values []string) string {
+	sum := sha256.Sum256([]byte(sortedList(values)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// restoreIntentValues converts a decoded list of strings back into []string and returns any
+// other value unchanged
+func restoreIntentValues(values interface{}) interface{} {
+	list, ok := values.([]interface{})
+	if !ok {
+		return values
+	}
+
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return values
+		}
+		strs = append(strs, s)
+	}
+	return strs
+}
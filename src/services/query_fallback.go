@@ -0,0 +1,93 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"news-inshorts/src/models"
+)
+
+// fallbackStopWords are generic query words that must never be matched against sources or
+// categories nor treated as search entities
+var fallbackStopWords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "any": true, "articles": true,
+	"at": true, "by": true, "for": true, "from": true, "get": true, "in": true,
+	"latest": true, "me": true, "near": true, "new": true, "news": true, "of": true,
+	"on": true, "recent": true, "show": true, "stories": true, "the": true, "to": true,
+	"today": true, "top": true, "updates": true, "what": true, "with": true,
+}
+
+// FallbackQueryAnalysis builds a QueryAnalysis without calling the LLM. Query tokens are matched
+// case-insensitively against the known sources and categories (substring match); tokens that
+// match neither are treated as search entities so the FilterChain can still run.
+func FallbackQueryAnalysis(query string, sources []string, categories []string) *models.QueryAnalysis {
+	tokens := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	matchedCategories := []string{}
+	matchedSources := []string{}
+	entities := []string{}
+
+	for _, token := range tokens {
+		if fallbackStopWords[token] || len(token) < 2 {
+			continue
+		}
+
+		matched := false
+
+		for _, category := range categories {
+			if strings.Contains(strings.ToLower(category), token) {
+				matchedCategories = appendUnique(matchedCategories, category)
+				matched = true
+			}
+		}
+
+		// Very short tokens produce too many accidental substring hits against source names
+		if len(token) >= 3 {
+			for _, source := range sources {
+				if strings.Contains(strings.ToLower(source), token) {
+					matchedSources = appendUnique(matchedSources, source)
+					matched = true
+				}
+			}
+		}
+
+		if !matched {
+			entities = appendUnique(entities, token)
+		}
+	}
+
+	analysis := &models.QueryAnalysis{
+		Entities: entities,
+		Intents:  make([]models.Intent, 0),
+	}
+
+	if len(matchedCategories) > 0 {
+		analysis.Intents = append(analysis.Intents, models.Intent{
+			Type:       models.IntentTypeCategory,
+			Values:     matchedCategories,
+			Confidence: 1,
+		})
+	}
+
+	if len(matchedSources) > 0 {
+		analysis.Intents = append(analysis.Intents, models.Intent{
+			Type:       models.IntentTypeSource,
+			Values:     matchedSources,
+			Confidence: 1,
+		})
+	}
+
+	return analysis
+}
+
+// appendUnique appends value to values unless it is already present
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
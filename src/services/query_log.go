@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// Query log write settings
+const (
+	// queryLogWriteTimeout bounds the insert of one batch
+	queryLogWriteTimeout = 10 * time.Second
+	// queryLogCloseTimeout bounds how long Close waits for the queued entries to be stored
+	queryLogCloseTimeout = 15 * time.Second
+)
+
+// QueryLogTop is the most frequent queries of a period
+type QueryLogTop struct {
+	Since time.Time
+	Top   []models.QueryCount
+	// ZeroResults counts only the searches that returned no article
+	ZeroResults []models.QueryCount
+}
+
+// QueryLogService records natural-language queries and reports what is searched for
+type QueryLogService interface {
+	// Record queues an entry for storage under the tenant of ctx and returns immediately. The
+	// client is identified by userID, or clientIP without one; only a salted hash of it is
+	// stored. It does nothing while query logging is disabled or when the entry is not sampled.
+	Record(ctx context.Context, entry models.QueryLogEntry, userID, clientIP string)
+	// Top returns up to limit of the most frequent queries of the tenant of ctx in the last
+	// window, and of its most frequent queries that found nothing
+	Top(ctx context.Context, window time.Duration, limit int) (*QueryLogTop, error)
+	// Prune deletes the entries older than the retention period and returns how many were deleted
+	Prune(ctx context.Context) (int64, error)
+	// Close stores the queued entries and stops the writer; call it once requests have drained
+	Close()
+}
+
+// queryLogService implements QueryLogService. A single background goroutine drains the queue
+// in batches, so logging never adds latency to queries.
+type queryLogService struct {
+	repo          repositories.QueryLogRepository
+	cfg           *infra.QueryLogConfig
+	defaultTenant string
+	clock         infra.Clock
+	salt          []byte
+	queue         chan models.QueryLogEntry
+	stop          chan struct{}
+	done          chan struct{}
+	once          sync.Once
+	logger        infra.Logger
+}
+
+// NewQueryLogService creates a new instance of QueryLogService and, when query logging is
+// enabled, starts storing queued entries. Reports and pruning work either way, so entries
+// kept from an earlier run can still be read and expire.
+func NewQueryLogService(repo repositories.QueryLogRepository, cfg *infra.QueryLogConfig, defaultTenant string, clock infra.Clock, logger infra.Logger) QueryLogService {
+	s := &queryLogService{
+		repo:          repo,
+		cfg:           cfg,
+		defaultTenant: defaultTenant,
+		clock:         clock,
+		salt:          []byte(cfg.HashSalt),
+		logger:        logger,
+	}
+
+	if cfg.Enabled {
+		if len(s.salt) == 0 {
+			s.salt = make([]byte, 32)
+			// crypto/rand does not fail on supported platforms
+			_, _ = cryptorand.Read(s.salt)
+			logger.Warn("QUERY_LOG_HASH_SALT is not set, client hashes will not match across restarts", nil)
+		}
+
+		s.queue = make(chan models.QueryLogEntry, cfg.QueueSize)
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.run()
+	}
+
+	return s
+}
+
+// Record queues a sampled entry. When the queue is full the entry is dropped rather than
+// holding up the query.
+func (s *queryLogService) Record(ctx context.Context, entry models.QueryLogEntry, userID, clientIP string) {
+	if s.queue == nil || rand.Float64() >= s.cfg.SampleRate {
+		return
+	}
+
+	entry.Tenant = infra.TenantFromContext(ctx, s.defaultTenant)
+	entry.ClientHash = s.clientHash(userID, clientIP)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = s.clock.Now()
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		infra.IncrCounter(infra.MetricQueryLogDropped, 1)
+		s.logger.Warn("Query log queue full, entry dropped", map[string]interface{}{
+			"queue_size": s.cfg.QueueSize,
+		})
+	}
+}
+
+// Top returns the most frequent queries and zero-result queries of the period
+func (s *queryLogService) Top(ctx context.Context, window time.Duration, limit int) (*QueryLogTop, error) {
+	since := s.clock.Now().Add(-window)
+
+	top, err := s.repo.Top(ctx, since, limit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	zeroResults, err := s.repo.Top(ctx, since, limit, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryLogTop{Since: since, Top: top, ZeroResults: zeroResults}, nil
+}
+
+// Prune deletes expired entries
+func (s *queryLogService) Prune(ctx context.Context) (int64, error) {
+	return s.repo.Purge(ctx, s.clock.Now().Add(-s.cfg.Retention))
+}
+
+// Close signals the writer to store what is queued and waits for it, at most
+// queryLogCloseTimeout
+func (s *queryLogService) Close() {
+	if s.queue == nil {
+		return
+	}
+
+	s.once.Do(func() {
+		close(s.stop)
+
+		select {
+		case <-s.done:
+			s.logger.Info("Query log flushed", nil)
+		case <-time.After(queryLogCloseTimeout):
+			s.logger.Warn("Timed out flushing the query log, queued entries are lost", map[string]interface{}{
+				"queued": len(s.queue),
+			})
+		}
+	})
+}
+
+// clientHash returns the hex HMAC-SHA256 of the client's identity under the salt
+func (s *queryLogService) clientHash(userID, clientIP string) string {
+	mac := hmac.New(sha256.New, s.salt)
+	mac.Write([]byte(infra.ExperimentSubject(userID, clientIP)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// run collects queued entries and stores them once a batch is full or the flush interval
+// passes with entries waiting. On stop it stores everything still queued.
+func (s *queryLogService) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.QueryLogEntry, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-s.stop:
+			s.drain(batch)
+			return
+		}
+
+		s.write(batch)
+		batch = batch[:0]
+	}
+}
+
+// drain stores batch and every entry left in the queue
+func (s *queryLogService) drain(batch []models.QueryLogEntry) {
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) < s.cfg.BatchSize {
+				continue
+			}
+			s.write(batch)
+			batch = batch[:0]
+		default:
+			s.write(batch)
+			return
+		}
+	}
+}
+
+// write stores one batch
+func (s *queryLogService) write(batch []models.QueryLogEntry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryLogWriteTimeout)
+	defer cancel()
+
+	// The repository logs failures; a lost batch only thins the log
+	if err := s.repo.InsertBatch(ctx, batch); err != nil {
+		return
+	}
+
+	s.logger.Debug("Stored query log entries", map[string]interface{}{
+		"entries": len(batch),
+	})
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Errors returned for queries rejected by the pre-processor
+var (
+	ErrQueryEmpty   = errors.New("query is empty after normalization")
+	ErrQueryTooLong = errors.New("query is too long")
+)
+
+// preparedQuery is a query after pre-processing
+type preparedQuery struct {
+	// Text is the normalized query that is cached, logged and sent to the LLM
+	Text string
+	// Truncated is true when the query was cut down to the soft length limit
+	Truncated bool
+}
+
+// preprocessQuery normalizes a raw search query: it applies Unicode NFC normalization so
+// equivalent spellings compare equal, drops control and invisible formatting characters,
+// and collapses runs of whitespace into single spaces. Queries longer than hardMax runes are
+// rejected with ErrQueryTooLong; queries longer than softMax runes are cut at the last word
+// boundary before the limit. A query with nothing left is rejected with ErrQueryEmpty.
+func preprocessQuery(raw string, softMax, hardMax int) (preparedQuery, error) {
+	text := norm.NFC.String(strings.ToValidUTF8(raw, " "))
+
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		// Zero-width joiners are kept since they are part of emoji sequences
+		case unicode.Is(unicode.Cf, r) && r != '\u200d':
+			return -1
+		}
+		return r
+	}, text)
+
+	text = strings.Join(strings.Fields(text), " ")
+
+	length := utf8.RuneCountInString(text)
+	if length == 0 {
+		return preparedQuery{}, ErrQueryEmpty
+	}
+	if hardMax > 0 && length > hardMax {
+		return preparedQuery{}, fmt.Errorf("%w: %d characters, at most %d allowed", ErrQueryTooLong, length, hardMax)
+	}
+	if softMax > 0 && length > softMax {
+		return preparedQuery{Text: truncateQuery(text, softMax), Truncated: true}, nil
+	}
+
+	return preparedQuery{Text: text}, nil
+}
+
+// truncateQuery cuts text to at most max runes, backing up to the last space so words are
+// not split. A single word longer than max is cut mid-word.
+func truncateQuery(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+
+	cut := string(runes[:max])
+	if runes[max] != ' ' {
+		if i := strings.LastIndexByte(cut, ' '); i > 0 {
+			cut = cut[:i]
+		}
+	}
+
+	return strings.TrimRight(cut, " ")
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPreprocessQueryUnicode(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		softMax       int
+		hardMax       int
+		want          string
+		wantTruncated bool
+		wantErr       error
+	}{
+		{name: "decomposed accent is composed", raw: "Cafe\u0301 culture", want: "Café culture"},
+		{name: "zero-width space is dropped", raw: "mum\u200bbai rains", want: "mumbai rains"},
+		{name: "byte order mark is dropped", raw: "\ufeffelection results", want: "election results"},
+		{name: "direction marks are dropped", raw: "\u200fisrael\u200e news", want: "israel news"},
+		{name: "zero-width joiner in emoji is kept", raw: "\U0001F468\u200d\U0001F469\u200d\U0001F467 family", want: "\U0001F468\u200d\U0001F469\u200d\U0001F467 family"},
+		{name: "unicode spaces collapse", raw: "New\u00a0Delhi\u3000 news", want: "New Delhi news"},
+		{name: "tabs and newlines collapse", raw: "  stock\t\tmarket\r\nnews  ", want: "stock market news"},
+		{name: "control characters are dropped", raw: "ai\x00 pol\x07icy", want: "ai policy"},
+		{name: "invalid UTF-8 becomes a space", raw: "budget\xff\xfe2026", want: "budget 2026"},
+		{name: "combining marks of Indic scripts are kept", raw: "नई  दिल्ली", want: "नई दिल्ली"},
+		{name: "only invisible characters", raw: "\u200b\ufeff\u200e \t", wantErr: ErrQueryEmpty},
+		{name: "length counts runes, not bytes", raw: "日本語ニュース", hardMax: 7, want: "日本語ニュース"},
+		{name: "too many runes", raw: "日本語ニュース", hardMax: 6, wantErr: ErrQueryTooLong},
+		{name: "emoji count as single runes", raw: "🔥🔥🔥", hardMax: 3, want: "🔥🔥🔥"},
+		{name: "truncation keeps whole multibyte words", raw: "東京 大阪 名古屋", softMax: 6, want: "東京 大阪", wantTruncated: true},
+		{name: "single long word is cut between runes", raw: "ニュースニュース", softMax: 4, want: "ニュース", wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := preprocessQuery(tt.raw, tt.softMax, tt.hardMax)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("preprocessQuery failed: %v", err)
+			}
+			if got.Text != tt.want || got.Truncated != tt.wantTruncated {
+				t.Errorf("got %+q (truncated %v), want %+q (truncated %v)", got.Text, got.Truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
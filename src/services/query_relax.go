@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+)
+
+// Constraints a query that matched nothing is relaxed by, as reported in QueryResult.Relaxed
+const (
+	relaxRadius   = "radius"
+	relaxSource   = "source"
+	relaxCategory = "category"
+)
+
+// relaxOrder is the order constraints are relaxed in, the least specific to the query first
+var relaxOrder = []string{relaxRadius, relaxSource, relaxCategory}
+
+// relaxRadiusFactor is how many times wider the radius of nearby steps gets when relaxed
+const relaxRadiusFactor = 3
+
+// relaxPlan returns a copy of plan with the constraint relaxed, and false when the plan has no
+// such constraint to relax. The radius is widened up to maxRadiusKm; source and category
+// steps are dropped. Exclusions are never relaxed: the user asked for them explicitly.
+func relaxPlan(plan []models.FilterStep, constraint string, maxRadiusKm float64) ([]models.FilterStep, bool) {
+	switch constraint {
+	case relaxRadius:
+		relaxed := slices.Clone(plan)
+		changed := false
+		for i, step := range relaxed {
+			if step.Name != models.IntentTypeNearby {
+				continue
+			}
+			value, _ := step.Params["radius"].(string)
+			radius, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			widened := math.Min(radius*relaxRadiusFactor, maxRadiusKm)
+			if widened <= radius {
+				continue
+			}
+			// Params are shared with the plan being relaxed, which must stay as it is
+			params := maps.Clone(step.Params)
+			params["radius"] = strconv.FormatFloat(widened, 'f', -1, 64)
+			relaxed[i] = models.FilterStep{Name: step.Name, Params: params}
+			changed = true
+		}
+		return relaxed, changed
+	case relaxSource, relaxCategory:
+		name := models.IntentTypeSource
+		if constraint == relaxCategory {
+			name = models.IntentTypeCategory
+		}
+		relaxed := slices.DeleteFunc(slices.Clone(plan), func(step models.FilterStep) bool {
+			return step.Name == name
+		})
+		return relaxed, len(relaxed) < len(plan)
+	}
+	return plan, false
+}
+
+// relax retries a plan that matched nothing with its constraints relaxed cumulatively, in
+// relaxOrder, and returns the articles of the first retry that matched any along with the
+// constraints relaxed for it. It returns no articles when no retry matched, a retry failed
+// or ctx ended, and the query keeps its empty result.
+func (s *articleService) relax(ctx context.Context, plan []models.FilterStep) ([]models.EnrichedArticle, []string) {
+	var relaxed []string
+	for _, constraint := range relaxOrder {
+		if err := ctx.Err(); err != nil {
+			s.logger.Warn("Request ended while relaxing an empty query result", map[string]interface{}{
+				"relaxed": relaxed,
+				"error":   err.Error(),
+			})
+			return nil, nil
+		}
+
+		next, ok := relaxPlan(plan, constraint, s.queryCfg.MaxRadiusKm)
+		if !ok {
+			continue
+		}
+		plan = next
+		relaxed = append(relaxed, constraint)
+
+		articles, err := s.filterChain.ExecutePlan(ctx, plan)
+		if err != nil {
+			s.logger.Warn("Failed to execute relaxed filter chain", map[string]interface{}{
+				"relaxed": relaxed,
+				"error":   err.Error(),
+			})
+			return nil, nil
+		}
+		if len(articles) > 0 {
+			infra.IncrCounter(infra.MetricQueryRelaxations, 1)
+			s.logger.Info("Relaxed query constraints after an empty result", map[string]interface{}{
+				"relaxed":  relaxed,
+				"articles": len(articles),
+			})
+			return articles, relaxed
+		}
+	}
+	return nil, nil
+}
+
+// embeddingMemoKey is the context key under which a query stores its embeddingMemo
+type embeddingMemoKey struct{}
+
+// embeddingMemo holds the query embeddings generated while answering a single query, so the
+// retries of a relaxed query do not embed the same text again
+type embeddingMemo struct {
+	mu      sync.Mutex
+	vectors map[string][]float64
+}
+
+// withEmbeddingMemo returns a context carrying a fresh embeddingMemo
+func withEmbeddingMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, embeddingMemoKey{}, &embeddingMemo{vectors: make(map[string][]float64)})
+}
+
+// queryEmbedding returns the embedding of text, from the context's embeddingMemo when it was
+// generated before. Without a memo in the context it always calls the LLM.
+func queryEmbedding(ctx context.Context, llmService LLMService, text string) ([]float64, error) {
+	memo, ok := ctx.Value(embeddingMemoKey{}).(*embeddingMemo)
+	if !ok {
+		return llmService.GenerateEmbedding(ctx, text)
+	}
+
+	memo.mu.Lock()
+	vector, found := memo.vectors[text]
+	memo.mu.Unlock()
+	if found {
+		return vector, nil
+	}
+
+	vector, err := llmService.GenerateEmbedding(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	memo.mu.Lock()
+	memo.vectors[text] = vector
+	memo.mu.Unlock()
+	return vector, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RelevanceService recomputes article relevance scores from user engagement
+type RelevanceService interface {
+	// Rescore recomputes every article's relevance score and returns how many changed
+	Rescore(ctx context.Context) (int64, error)
+}
+
+// relevanceService implements RelevanceService
+type relevanceService struct {
+	articleRepo   repositories.ArticleRepository
+	userEventRepo repositories.UserEventRepository
+	cfg           *infra.RelevanceConfig
+	filterCache   *filterCache
+	log           infra.Logger
+}
+
+// NewRelevanceService creates a new instance of RelevanceService. Changed scores change the
+// results of score filters, so a rescore that changes any invalidates the filter result cache.
+func NewRelevanceService(
+	articleRepo repositories.ArticleRepository,
+	userEventRepo repositories.UserEventRepository,
+	cfg *infra.RelevanceConfig,
+	redisClient *redis.Client,
+	filterCacheTTL time.Duration,
+	logger infra.Logger,
+) RelevanceService {
+	return &relevanceService{
+		articleRepo:   articleRepo,
+		userEventRepo: userEventRepo,
+		cfg:           cfg,
+		filterCache:   newFilterCache(redisClient, filterCacheTTL, logger),
+		log:           logger,
+	}
+}
+
+// Rescore scores each article by its decayed engagement over the configured window, scaled
+// to 0-1 against the most engaged article, and blends in the feed score when configured.
+// Articles without recent events score 0 for engagement.
+func (s *relevanceService) Rescore(ctx context.Context) (int64, error) {
+	start := time.Now()
+
+	engagement, err := s.userEventRepo.DecayedEngagement(ctx, start.Add(-s.cfg.Window), start, s.cfg.HalfLife)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute engagement: %w", err)
+	}
+
+	feedScores, err := s.articleRepo.FeedRelevanceScores(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read feed relevance scores: %w", err)
+	}
+
+	maxEngagement := 0.0
+	for _, value := range engagement {
+		maxEngagement = math.Max(maxEngagement, value)
+	}
+
+	scores := make(map[string]float64, len(feedScores))
+	for id, feedScore := range feedScores {
+		score := scaleEngagement(engagement[id], maxEngagement)
+		if s.cfg.BlendFeedScore {
+			score = s.cfg.FeedWeight*feedScore + (1-s.cfg.FeedWeight)*score
+		}
+		// Rounding keeps tiny decay differences from rewriting every row on each run
+		scores[id] = math.Round(score*1e4) / 1e4
+	}
+
+	changed, err := s.articleRepo.UpdateRelevanceScores(ctx, scores)
+	if err != nil {
+		return changed, err
+	}
+
+	if changed > 0 {
+		s.filterCache.invalidate(ctx)
+	}
+
+	s.log.Info("Recomputed relevance scores", map[string]interface{}{
+		"articles":   len(scores),
+		"engaged":    len(engagement),
+		"changed":    changed,
+		"blend_feed": s.cfg.BlendFeedScore,
+		"elapsed":    time.Since(start).String(),
+	})
+
+	return changed, nil
+}
+
+// scaleEngagement maps engagement to 0-1 on a log scale relative to the highest engagement,
+// so a handful of viral articles do not flatten everything else to 0. Net-negative
+// engagement (mostly dismissals) scores 0.
+func scaleEngagement(engagement, maxEngagement float64) float64 {
+	if engagement <= 0 || maxEngagement <= 0 {
+		return 0
+	}
+	return math.Log1p(engagement) / math.Log1p(maxEngagement)
+}
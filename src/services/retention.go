@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// ErrRetentionRunning is returned when a retention run is requested while another is in progress
+var ErrRetentionRunning = errors.New("retention run already in progress")
+
+// RetentionService prunes user events older than the configured retention period
+type RetentionService interface {
+	Run(ctx context.Context) (models.RetentionRun, error)
+	StartRun() (models.Job, error)
+	LastRun() *models.RetentionRun
+}
+
+// retentionService implements RetentionService
+type retentionService struct {
+	userEventRepo repositories.UserEventRepository
+	jobs          *JobTracker
+	cfg           *infra.RetentionConfig
+	clock         infra.Clock
+	log           infra.Logger
+
+	running atomic.Bool
+	mu      sync.RWMutex
+	lastRun *models.RetentionRun
+}
+
+// NewRetentionService creates a new instance of RetentionService
+func NewRetentionService(userEventRepo repositories.UserEventRepository, jobs *JobTracker, cfg *infra.RetentionConfig, clock infra.Clock, logger infra.Logger) RetentionService {
+	return &retentionService{
+		userEventRepo: userEventRepo,
+		jobs:          jobs,
+		cfg:           cfg,
+		clock:         clock,
+		log:           logger,
+	}
+}
+
+// Run deletes events older than the retention period in batches and blocks until done
+func (s *retentionService) Run(ctx context.Context) (models.RetentionRun, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return models.RetentionRun{}, ErrRetentionRunning
+	}
+	defer s.running.Store(false)
+
+	return s.prune(ctx, nil)
+}
+
+// StartRun launches a run in the background and returns a job that can be polled for progress
+func (s *retentionService) StartRun() (models.Job, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return models.Job{}, ErrRetentionRunning
+	}
+
+	job := s.jobs.Start("retention")
+
+	go func() {
+		defer s.running.Store(false)
+
+		// The run outlives the request that started it, so it gets its own context
+		_, err := s.prune(context.Background(), func(deleted int64) {
+			s.jobs.Update(job.ID, func(j *models.Job) {
+				j.Processed = int(deleted)
+				j.Succeeded = int(deleted)
+			})
+		})
+		s.jobs.Finish(job.ID, err)
+	}()
+
+	return job, nil
+}
+
+// LastRun returns the most recent completed run, or nil if none has finished yet
+func (s *retentionService) LastRun() *models.RetentionRun {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.lastRun == nil {
+		return nil
+	}
+	run := *s.lastRun
+	return &run
+}
+
+// prune deletes old events batch by batch, reporting the running total to progress after
+// each batch, and records the outcome as the last run
+func (s *retentionService) prune(ctx context.Context, progress func(deleted int64)) (models.RetentionRun, error) {
+	startedAt := s.clock.Now()
+	run := models.RetentionRun{
+		StartedAt: startedAt,
+		Cutoff:    startedAt.Add(-s.cfg.EventsMaxAge),
+	}
+
+	s.log.Info("Starting user event retention run", map[string]interface{}{
+		"cutoff":     run.Cutoff,
+		"batch_size": s.cfg.BatchSize,
+	})
+
+	var runErr error
+	for {
+		deleted, err := s.userEventRepo.DeleteOlderThan(ctx, run.Cutoff, s.cfg.BatchSize)
+		if err != nil {
+			runErr = err
+			run.Error = err.Error()
+			break
+		}
+
+		run.Deleted += deleted
+		infra.IncrCounter(infra.MetricRetentionEventsDeleted, deleted)
+		if progress != nil {
+			progress(run.Deleted)
+		}
+
+		if deleted < int64(s.cfg.BatchSize) {
+			break
+		}
+	}
+
+	finishedAt := s.clock.Now()
+	run.FinishedAt = &finishedAt
+
+	infra.SetGauge(infra.MetricRetentionLastRunUnix, finishedAt.Unix())
+	infra.SetGauge(infra.MetricRetentionLastRunDeleted, run.Deleted)
+
+	s.mu.Lock()
+	s.lastRun = &run
+	s.mu.Unlock()
+
+	if runErr != nil {
+		s.log.Error("User event retention run failed", runErr, map[string]interface{}{
+			"deleted": run.Deleted,
+		})
+		return run, runErr
+	}
+
+	s.log.Info("Completed user event retention run", map[string]interface{}{
+		"deleted":  run.Deleted,
+		"duration": finishedAt.Sub(run.StartedAt).String(),
+	})
+
+	return run, nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/utils"
+)
+
+// savedSearchMatchTimeout bounds the matching statement run for one batch of new articles
+const savedSearchMatchTimeout = 30 * time.Second
+
+// ErrSavedSearchLimitReached is returned when a user already has the maximum number of saved searches
+var ErrSavedSearchLimitReached = errors.New("saved search limit reached")
+
+// SavedSearchService manages saved searches and matches newly stored articles against them
+type SavedSearchService interface {
+	// Create embeds the query of search and stores it. A query that cannot be embedded is
+	// stored anyway and only finds keyword matches.
+	Create(ctx context.Context, search *models.SavedSearch) error
+	List(ctx context.Context, userID string) ([]models.SavedSearch, error)
+	Get(ctx context.Context, userID, id string) (*models.SavedSearch, error)
+	Delete(ctx context.Context, userID, id string) error
+	// Matches returns the latest articles that matched the user's saved search, newest first
+	Matches(ctx context.Context, userID, id string, limit int) ([]models.SavedSearchMatch, error)
+	// EvaluateArticles queues newly stored articles for matching and returns immediately.
+	// Queued articles are evaluated in batches in the background.
+	EvaluateArticles(articleIDs []string)
+}
+
+// savedSearchService implements SavedSearchService. A single background goroutine drains the
+// queue, so matching statements never run concurrently with each other.
+type savedSearchService struct {
+	searchRepo  repositories.SavedSearchRepository
+	articleRepo repositories.ArticleRepository
+	llmService  LLMService
+	cfg         *infra.SavedSearchConfig
+	queue       chan string
+	logger      infra.Logger
+}
+
+// NewSavedSearchService creates a new instance of SavedSearchService and starts evaluating
+// queued articles
+func NewSavedSearchService(searchRepo repositories.SavedSearchRepository, articleRepo repositories.ArticleRepository, llmService LLMService, cfg *infra.SavedSearchConfig, logger infra.Logger) SavedSearchService {
+	s := &savedSearchService{
+		searchRepo:  searchRepo,
+		articleRepo: articleRepo,
+		llmService:  llmService,
+		cfg:         cfg,
+		queue:       make(chan string, cfg.QueueSize),
+		logger:      logger,
+	}
+
+	go s.run()
+
+	return s
+}
+
+// Create stores a saved search for search.UserID
+func (s *savedSearchService) Create(ctx context.Context, search *models.SavedSearch) error {
+	count, err := s.searchRepo.CountByUserID(ctx, search.UserID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(s.cfg.MaxPerUser) {
+		return ErrSavedSearchLimitReached
+	}
+
+	terms := utils.SplitSearchTerms(search.Query)
+	keywords := make([]string, 0, len(terms))
+	for _, term := range terms {
+		keywords = appendUnique(keywords, strings.ToLower(term))
+	}
+
+	var embeddingModel string
+	vector, err := s.llmService.GenerateEmbedding(ctx, search.Query)
+	if err != nil {
+		s.logger.Warn("Failed to embed saved search query, keeping keyword matching only", map[string]interface{}{
+			"user_hash": utils.HashIdentifier(search.UserID),
+			"error":     err.Error(),
+		})
+		vector = nil
+	} else {
+		embeddingModel = s.llmService.EmbeddingModel()
+	}
+
+	return s.searchRepo.Create(ctx, search, keywords, vector, embeddingModel)
+}
+
+// List returns the user's saved searches
+func (s *savedSearchService) List(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	return s.searchRepo.List(ctx, userID)
+}
+
+// Get returns the user's saved search
+func (s *savedSearchService) Get(ctx context.Context, userID, id string) (*models.SavedSearch, error) {
+	return s.searchRepo.Get(ctx, userID, id)
+}
+
+// Delete removes the user's saved search with its matches
+func (s *savedSearchService) Delete(ctx context.Context, userID, id string) error {
+	return s.searchRepo.Delete(ctx, userID, id)
+}
+
+// Matches returns the latest matches of the user's saved search with their articles.
+// Matches whose article has since been deleted are left out.
+func (s *savedSearchService) Matches(ctx context.Context, userID, id string, limit int) ([]models.SavedSearchMatch, error) {
+	if _, err := s.searchRepo.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.searchRepo.Matches(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		return []models.SavedSearchMatch{}, nil
+	}
+
+	ids := make([]string, len(stored))
+	for i, match := range stored {
+		ids[i] = match.ArticleID
+	}
+
+	articles, err := s.articleRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]models.Article, len(articles))
+	for _, article := range articles {
+		byID[strings.ToLower(article.ID)] = article
+	}
+
+	matches := make([]models.SavedSearchMatch, 0, len(stored))
+	for _, match := range stored {
+		article, found := byID[strings.ToLower(match.ArticleID)]
+		if !found {
+			continue
+		}
+		matches = append(matches, models.SavedSearchMatch{
+			Article:      article,
+			Similarity:   match.Similarity,
+			KeywordMatch: match.KeywordMatch,
+			MatchedAt:    match.MatchedAt,
+		})
+	}
+
+	return matches, nil
+}
+
+// EvaluateArticles queues articles for matching. When the queue is full the remaining
+// articles are dropped rather than holding up the caller.
+func (s *savedSearchService) EvaluateArticles(articleIDs []string) {
+	dropped := 0
+	for _, id := range articleIDs {
+		select {
+		case s.queue <- id:
+		default:
+			dropped++
+		}
+	}
+
+	if dropped > 0 {
+		s.logger.Warn("Saved search queue full, articles will not be matched", map[string]interface{}{
+			"dropped":    dropped,
+			"queue_size": s.cfg.QueueSize,
+		})
+	}
+}
+
+// run collects queued articles and matches them once a batch is full or the flush interval
+// passes with articles waiting
+func (s *savedSearchService) run() {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]string, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case id := <-s.queue:
+			batch = append(batch, id)
+			if len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		s.match(batch)
+		batch = batch[:0]
+	}
+}
+
+// match evaluates one batch of articles against every saved search
+func (s *savedSearchService) match(articleIDs []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), savedSearchMatchTimeout)
+	defer cancel()
+
+	start := time.Now()
+	matched, err := s.searchRepo.MatchArticles(ctx, articleIDs, s.cfg.MinSimilarity)
+	if err != nil {
+		s.logger.Error("Failed to match articles against saved searches", err, map[string]interface{}{
+			"articles": len(articleIDs),
+		})
+		return
+	}
+
+	s.logger.Info("Matched new articles against saved searches", map[string]interface{}{
+		"articles":    len(articleIDs),
+		"matches":     matched,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+}
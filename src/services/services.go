@@ -11,9 +11,32 @@ import (
 // Services holds all service instances
 type Services struct {
 	LLM         LLMService
+	LLMAudit    LLMAuditService
+	Geocoder    GeocodingService
+	Locator     ClientLocationService
 	Trending    TrendingService
+	Stats       StatsService
+	Idempotency IdempotencyStore
+	Privacy     PrivacyService
+	Retention   RetentionService
+	Archive     ArchiveService
+	Export      ExportService
+	Relevance   RelevanceService
+	Clustering  ClusteringService
+	QueryLog    QueryLogService
+	Webhook     WebhookService
+	SavedSearch SavedSearchService
+	SourceAlias SourceAliasService
+	Category    CategoryService
+	Spelling    SpellingService
+	VectorIndex VectorIndexService
+	Cache       CacheService
 	Article     ArticleService
+	Feed        FeedService
 	FilterChain *FilterChain
+	Jobs        *JobTracker
+	Experiments *infra.ExperimentAssigner
+	Tenants     *infra.TenantResolver
 	Repos       *repositories.Repositories
 }
 
@@ -22,28 +45,126 @@ func NewServices(
 	cfg *infra.Config,
 	db *gorm.DB,
 	redisClient *redis.Client,
+	clock infra.Clock,
+	logger infra.Logger,
 ) *Services {
 	// Initialize repositories
-	repos := repositories.NewRepositories(db)
-	infra.GetLogger().Info("Repositories initialized", nil)
+	repos := repositories.NewRepositories(db, &cfg.Vector, cfg.Tenant.Default, logger)
+	logger.Info("Repositories initialized", nil)
+
+	// Initialize geocoding service (nil when GEOCODER_PROVIDER=none)
+	geocoder := NewGeocodingService(&cfg.Geocoder, redisClient, logger)
+
+	// Initialize article page fetching for full-text enrichment (nil when disabled)
+	contentFetcher := NewContentFetcher(&cfg.Content, logger)
+
+	// Initialize client IP geolocation for trending requests without coordinates
+	locator := NewClientLocationService(&cfg.GeoIP, logger)
+
+	// Initialize the audit sink for query analysis calls (a no-op unless LLM_AUDIT_ENABLED)
+	llmAudit := NewLLMAuditService(repos.LLMAudit, &cfg.LLM, logger)
 
 	// Initialize LLM service
-	llmService := NewLLMService(&cfg.LLM)
+	llmService := NewLLMService(&cfg.LLM, geocoder, redisClient, llmAudit, clock, logger)
 
 	// Initialize filter chain with all filters
-	filterChain := NewFilterChain(repos.Article, llmService)
+	filterChain := NewFilterChain(repos.Article, repos.SourceAlias, llmService, &cfg.Query, &cfg.Vector, &cfg.Dedupe, logger)
 
 	// Initialize trending service
-	trendingService := NewTrendingService(repos.UserEvent, redisClient, cfg.Cache.TTL)
+	trendingCache := NewCacheStore("trending-cache", redisClient, cfg.Cache.TrendingTimeout, cfg.Cache.TrendingFailureThreshold, cfg.Cache.TrendingCooldown, infra.MetricTrendingCircuitState, logger)
+	trendingService := NewTrendingService(repos.UserEvent, trendingCache, cfg.Cache.TTL, &cfg.Trending, cfg.Tenant.Default, clock, logger)
+
+	// Initialize article stats service
+	statsService := NewStatsService(repos.Article, repos.UserEvent, redisClient, cfg.Cache.StatsTTL, cfg.Cache.CorpusStatsTTL, cfg.Tenant.Default, clock, logger)
+
+	// Initialize idempotency store for interaction recording
+	idempotency := NewIdempotencyStore(redisClient, cfg.Cache.IdempotencyTTL, cfg.Tenant.Default)
+
+	// Initialize privacy service for data-subject requests
+	privacyService := NewPrivacyService(repos.UserEvent, repos.SavedSearch, repos.UserPreference, redisClient, cfg.Tenant.Default, logger)
+
+	// Initialize background job tracker
+	jobs := NewJobTracker(cfg.Server.JobRetention, clock)
+
+	// Initialize user event retention service
+	retentionService := NewRetentionService(repos.UserEvent, jobs, &cfg.Retention, clock, logger)
+
+	// Initialize the job moving old articles to the archive table
+	archiveService := NewArchiveService(repos.Article, jobs, &cfg.Archive, redisClient, cfg.Cache.FilterTTL, clock, logger)
+
+	// Initialize corpus snapshots to object storage (disabled when EXPORT_S3_BUCKET is unset)
+	exportService := NewExportService(repos.Article, repos.ExportRun, NewS3ObjectStore(&cfg.Export.S3, clock), jobs, &cfg.Export, clock, logger)
+
+	// Initialize engagement-based relevance rescoring
+	relevanceService := NewRelevanceService(repos.Article, repos.UserEvent, &cfg.Relevance, redisClient, cfg.Cache.FilterTTL, logger)
+
+	// Initialize story clustering of recent articles
+	clusteringService := NewClusteringService(repos.Cluster, repos.Article, llmService, &cfg.Cluster, clock, logger)
+
+	// Initialize the query log (records nothing unless QUERY_LOG_ENABLED)
+	queryLogService := NewQueryLogService(repos.QueryLog, &cfg.QueryLog, cfg.Tenant.Default, clock, logger)
+
+	// Initialize webhook notifications for new articles
+	webhookService := NewWebhookService(&cfg.Webhook, logger)
+
+	// Initialize saved searches, matched asynchronously against newly stored articles
+	savedSearchService := NewSavedSearchService(repos.SavedSearch, repos.Article, llmService, &cfg.Searches, logger)
+
+	// Initialize vector index management for semantic search
+	vectorIndexService := NewVectorIndexService(repos.VectorIndex, jobs, logger)
+
+	// Initialize cache administration
+	cacheService := NewCacheService(redisClient, logger)
+
+	// Initialize source alias management for source filters and query analysis
+	sourceAliasService := NewSourceAliasService(repos.SourceAlias, redisClient, cfg.Cache.FilterTTL, logger)
+
+	// Initialize the category taxonomy that incoming categories are normalized through
+	categoryService := NewCategoryService(repos.Category, repos.Article, &cfg.Enrich, redisClient, cfg.Cache.FilterTTL, logger)
+
+	// Initialize query spelling correction against the corpus vocabulary
+	spellingService := NewSpellingService(repos.Article, &cfg.Query, redisClient, cfg.Tenant.Default, logger)
 
 	// Initialize news service
-	newsService := NewArticleService(llmService, filterChain, trendingService, repos.Article, repos.UserEvent)
+	newsService := NewArticleService(llmService, filterChain, trendingService, webhookService, savedSearchService, geocoder, sourceAliasService, categoryService, spellingService, contentFetcher, repos.Article, repos.UserEvent, repos.UserPreference, jobs, &cfg.Enrich, &cfg.Content, &cfg.Export, &cfg.Query, &cfg.Dedupe, &cfg.Trending, cfg.Tenant.Default, redisClient, cfg.Cache.FilterTTL, cfg.Cache.QueryAnalysisTTL, cfg.Cache.TopicsTTL, clock, logger)
+
+	// Initialize A/B experiment assignment
+	experiments := infra.NewExperimentAssigner(&cfg.Experiment)
+
+	// Initialize tenant resolution for requests
+	tenants := infra.NewTenantResolver(&cfg.Tenant, cfg.Server.AdminAPIKey)
+
+	// Initialize RSS feed rendering on top of the news service
+	feedService := NewFeedService(newsService, redisClient, cfg.Cache.FeedTTL, cfg.Tenant.Default, logger)
 
 	return &Services{
 		LLM:         llmService,
+		LLMAudit:    llmAudit,
+		Geocoder:    geocoder,
+		Locator:     locator,
 		Trending:    trendingService,
+		Stats:       statsService,
+		Idempotency: idempotency,
+		Privacy:     privacyService,
+		Retention:   retentionService,
+		Archive:     archiveService,
+		Export:      exportService,
+		Relevance:   relevanceService,
+		Clustering:  clusteringService,
+		QueryLog:    queryLogService,
+		Webhook:     webhookService,
+		SavedSearch: savedSearchService,
+		SourceAlias: sourceAliasService,
+		Category:    categoryService,
+		Spelling:    spellingService,
+		VectorIndex: vectorIndexService,
+		Cache:       cacheService,
 		Article:     newsService,
+		Feed:        feedService,
 		FilterChain: filterChain,
+		Jobs:        jobs,
+		Experiments: experiments,
+		Tenants:     tenants,
 		Repos:       repos,
 	}
 }
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SourceAliasService manages the aliases that map source names used in queries to the source
+// names stored on articles
+type SourceAliasService interface {
+	List(ctx context.Context) ([]models.SourceAlias, error)
+	Get(ctx context.Context, alias string) (*models.SourceAlias, error)
+	Upsert(ctx context.Context, alias string, sourceNames []string) (*models.SourceAlias, error)
+	Delete(ctx context.Context, alias string) error
+	// CanonicalSourceNames returns the source names to offer the LLM: each alias in place of
+	// the stored source names it covers, followed by the stored names no alias covers
+	CanonicalSourceNames(ctx context.Context, sourceNames []string) ([]string, error)
+}
+
+// sourceAliasService implements SourceAliasService
+type sourceAliasService struct {
+	repo        repositories.SourceAliasRepository
+	filterCache *filterCache
+	log         infra.Logger
+}
+
+// NewSourceAliasService creates a new instance of SourceAliasService. Changing an alias
+// changes which articles source filters match, so it invalidates the filter result cache.
+func NewSourceAliasService(repo repositories.SourceAliasRepository, redisClient *redis.Client, filterCacheTTL time.Duration, logger infra.Logger) SourceAliasService {
+	return &sourceAliasService{
+		repo:        repo,
+		filterCache: newFilterCache(redisClient, filterCacheTTL, logger),
+		log:         logger,
+	}
+}
+
+// List returns every alias
+func (s *sourceAliasService) List(ctx context.Context) ([]models.SourceAlias, error) {
+	return s.repo.List(ctx)
+}
+
+// Get returns the alias, or repositories.ErrSourceAliasNotFound
+func (s *sourceAliasService) Get(ctx context.Context, alias string) (*models.SourceAlias, error) {
+	return s.repo.Get(ctx, alias)
+}
+
+// Upsert creates the alias or replaces its source names
+func (s *sourceAliasService) Upsert(ctx context.Context, alias string, sourceNames []string) (*models.SourceAlias, error) {
+	saved, err := s.repo.Upsert(ctx, alias, sourceNames)
+	if err != nil {
+		return nil, err
+	}
+
+	s.filterCache.invalidate(ctx)
+	return saved, nil
+}
+
+// Delete removes the alias, or returns repositories.ErrSourceAliasNotFound
+func (s *sourceAliasService) Delete(ctx context.Context, alias string) error {
+	if err := s.repo.Delete(ctx, alias); err != nil {
+		return err
+	}
+
+	s.filterCache.invalidate(ctx)
+	return nil
+}
+
+// CanonicalSourceNames replaces the stored source names covered by an alias with the alias.
+// Aliases none of whose source names are stored are left out, like any other unused source.
+func (s *sourceAliasService) CanonicalSourceNames(ctx context.Context, sourceNames []string) ([]string, error) {
+	aliases, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(aliases) == 0 {
+		return sourceNames, nil
+	}
+
+	stored := make(map[string]bool, len(sourceNames))
+	for _, name := range sourceNames {
+		stored[strings.ToLower(name)] = true
+	}
+
+	covered := make(map[string]bool)
+	canonical := make([]string, 0, len(sourceNames))
+	for _, alias := range aliases {
+		used := false
+		for _, name := range alias.SourceNames {
+			if stored[strings.ToLower(name)] {
+				covered[strings.ToLower(name)] = true
+				used = true
+			}
+		}
+		if used {
+			canonical = append(canonical, alias.Alias)
+		}
+	}
+
+	for _, name := range sourceNames {
+		if !covered[strings.ToLower(name)] && !slices.ContainsFunc(canonical, func(c string) bool { return strings.EqualFold(c, name) }) {
+			canonical = append(canonical, name)
+		}
+	}
+
+	return canonical, nil
+}
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// spellMinWordLength is the length in letters below which words are never corrected. Short
+// words are within two edits of too many others for a correction to be trustworthy.
+const spellMinWordLength = 4
+
+// commonNewsTerms are words frequent in news queries that the corpus titles may not contain
+// often enough to join the dictionary
+var commonNewsTerms = []string{
+	"accident", "agriculture", "airport", "army", "attack", "award", "bank", "bollywood",
+	"budget", "business", "cabinet", "campaign", "celebrity", "climate", "court", "cricket",
+	"crime", "crypto", "defence", "disaster", "economy", "education", "election", "elections",
+	"employment", "energy", "entertainment", "environment", "exports", "farmers", "film",
+	"finance", "flood", "floods", "football", "government", "health", "hockey", "hospital",
+	"industry", "inflation", "investment", "launch", "market", "markets", "match", "minister",
+	"monsoon", "movie", "music", "olympics", "parliament", "petrol", "police", "policy",
+	"politics", "president", "prices", "protest", "railway", "railways", "science", "security",
+	"series", "shares", "space", "sports", "startup", "startups", "stock", "stocks", "strike",
+	"summit", "supreme", "technology", "tennis", "tournament", "trade", "traffic", "travel",
+	"weather", "world",
+}
+
+// SpellingService corrects misspelled words of natural-language queries against a dictionary
+// of the corpus vocabulary: source names, categories, frequent title words and common news terms
+type SpellingService interface {
+	// Correct returns the query with each misspelled word replaced by the closest dictionary
+	// word, and whether any word was replaced. Words inside quoted phrases are left alone. The
+	// query is returned unchanged when correction is disabled or no dictionary is available.
+	Correct(ctx context.Context, query string) (string, bool)
+	// Rebuild builds the dictionary of the tenant on ctx from its corpus, caches it in Redis
+	// and returns its size
+	Rebuild(ctx context.Context) (int, error)
+}
+
+// spellingService implements SpellingService
+type spellingService struct {
+	articleRepo   repositories.ArticleRepository
+	cfg           *infra.QueryConfig
+	redisClient   *redis.Client
+	defaultTenant string
+	log           infra.Logger
+
+	// dictionaries maps each tenant to its *spellDictionary, so no tenant's vocabulary
+	// corrects another's queries
+	dictionaries sync.Map
+	// loadMu makes concurrent first queries wait for one dictionary load
+	loadMu sync.Mutex
+}
+
+// NewSpellingService creates a new instance of SpellingService. Each tenant's dictionary is
+// loaded on first use, from Redis when another instance cached it and from the database
+// otherwise, and replaced by every Rebuild. defaultTenant is used for contexts carrying no
+// tenant. redisClient may be nil, in which case each instance builds its own.
+func NewSpellingService(articleRepo repositories.ArticleRepository, cfg *infra.QueryConfig, redisClient *redis.Client, defaultTenant string, logger infra.Logger) SpellingService {
+	return &spellingService{
+		articleRepo:   articleRepo,
+		cfg:           cfg,
+		redisClient:   redisClient,
+		defaultTenant: defaultTenant,
+		log:           logger,
+	}
+}
+
+// Correct implements SpellingService
+func (s *spellingService) Correct(ctx context.Context, query string) (string, bool) {
+	if !s.cfg.SpellCorrection {
+		return query, false
+	}
+
+	dictionary := s.load(ctx)
+	if dictionary == nil {
+		return query, false
+	}
+
+	return dictionary.correct(query, s.cfg.SpellMaxDistance)
+}
+
+// Rebuild implements SpellingService
+func (s *spellingService) Rebuild(ctx context.Context) (int, error) {
+	words, err := s.collectWords(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tenant := s.tenant(ctx)
+	s.dictionaries.Store(tenant, newSpellDictionary(words))
+	s.cache(ctx, words)
+
+	s.log.Info("Rebuilt spelling dictionary", map[string]interface{}{
+		"tenant": tenant,
+		"words":  len(words),
+	})
+
+	return len(words), nil
+}
+
+// load returns the current dictionary of the tenant on ctx, loading it first when there is
+// none yet. Failures are logged and leave queries uncorrected until the next attempt.
+func (s *spellingService) load(ctx context.Context) *spellDictionary {
+	tenant := s.tenant(ctx)
+	if dictionary := s.dictionary(tenant); dictionary != nil {
+		return dictionary
+	}
+
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+
+	if dictionary := s.dictionary(tenant); dictionary != nil {
+		return dictionary
+	}
+
+	if words := s.cached(ctx); len(words) > 0 {
+		dictionary := newSpellDictionary(words)
+		s.dictionaries.Store(tenant, dictionary)
+		return dictionary
+	}
+
+	if _, err := s.Rebuild(ctx); err != nil {
+		s.log.Warn("Failed to build spelling dictionary, queries are not corrected", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	return s.dictionary(tenant)
+}
+
+// dictionary returns the loaded dictionary of tenant, or nil when there is none yet
+func (s *spellingService) dictionary(tenant string) *spellDictionary {
+	if dictionary, ok := s.dictionaries.Load(tenant); ok {
+		return dictionary.(*spellDictionary)
+	}
+	return nil
+}
+
+// tenant returns the tenant of ctx, whose corpus the dictionary is built from
+func (s *spellingService) tenant(ctx context.Context) string {
+	return infra.TenantFromContext(ctx, s.defaultTenant)
+}
+
+// collectWords gathers the dictionary words, most trusted first: when two words are equally
+// close to a misspelling, the earlier one wins
+func (s *spellingService) collectWords(ctx context.Context) ([]string, error) {
+	sources, err := s.articleRepo.GetDistinctSourceNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source names: %w", err)
+	}
+
+	categories, err := s.articleRepo.GetDistinctCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	terms, err := s.articleRepo.GetFrequentTitleTerms(ctx, s.cfg.SpellMinTermCount, s.cfg.SpellMaxTerms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frequent title terms: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var words []string
+	add := func(text string) {
+		for _, word := range spellWords(text) {
+			if !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+
+	for _, source := range sources {
+		add(source)
+	}
+	for _, category := range categories {
+		add(category)
+	}
+	for _, term := range commonNewsTerms {
+		add(term)
+	}
+	// Stop words are known words too, so they are never corrected into something else
+	for word := range fallbackStopWords {
+		add(word)
+	}
+	for _, term := range terms {
+		add(term)
+	}
+
+	return words, nil
+}
+
+// cached returns the dictionary words of the tenant on ctx cached in Redis, or nil when there
+// are none
+func (s *spellingService) cached(ctx context.Context) []string {
+	if s.redisClient == nil {
+		return nil
+	}
+
+	data, err := s.redisClient.Get(ctx, infra.SpellDictionaryKey(s.tenant(ctx))).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			s.log.Warn("Failed to read cached spelling dictionary", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		return nil
+	}
+
+	var words []string
+	if err := json.Unmarshal(data, &words); err != nil {
+		s.log.Warn("Failed to decode cached spelling dictionary", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	return words
+}
+
+// cache stores the dictionary words in Redis until the next rebuild is due, so instances
+// starting in the meantime skip the corpus scan
+func (s *spellingService) cache(ctx context.Context, words []string) {
+	if s.redisClient == nil {
+		return
+	}
+
+	data, err := json.Marshal(words)
+	if err != nil {
+		return
+	}
+	if err := s.redisClient.Set(ctx, infra.SpellDictionaryKey(s.tenant(ctx)), data, s.cfg.SpellRefreshInterval).Err(); err != nil {
+		s.log.Warn("Failed to cache spelling dictionary", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// spellWords splits text into lowercased words of letters
+func spellWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// spellDictionary is an immutable set of known words, indexed by length in runes so a lookup
+// only compares words that can be within the allowed distance
+type spellDictionary struct {
+	known    map[string]bool
+	byLength map[int][]string
+}
+
+// newSpellDictionary indexes words, which are kept in order within each length
+func newSpellDictionary(words []string) *spellDictionary {
+	dictionary := &spellDictionary{
+		known:    make(map[string]bool, len(words)),
+		byLength: make(map[int][]string),
+	}
+	for _, word := range words {
+		dictionary.known[word] = true
+		length := utf8.RuneCountInString(word)
+		dictionary.byLength[length] = append(dictionary.byLength[length], word)
+	}
+	return dictionary
+}
+
+// correct replaces the misspelled words of query. A word is corrected when, without the
+// punctuation around it, it consists of letters only, is at least spellMinWordLength long and
+// unknown, and a dictionary word lies within its allowed distance: maxDistance, but 1 for
+// words shorter than five letters. The punctuation is kept, as is a leading capital. Quoted
+// phrases, delimited by straight or curly double quotes, are copied unchanged.
+func (d *spellDictionary) correct(query string, maxDistance int) (string, bool) {
+	words := strings.Split(query, " ")
+	corrected := false
+	inQuote := false
+
+	for i, word := range words {
+		quotes := strings.Count(word, `"`) + strings.Count(word, "“") + strings.Count(word, "”")
+		protected := inQuote || quotes > 0
+		if quotes%2 == 1 {
+			inQuote = !inQuote
+		}
+		if protected {
+			continue
+		}
+
+		core := strings.TrimLeftFunc(word, unicode.IsPunct)
+		start := len(word) - len(core)
+		core = strings.TrimRightFunc(core, unicode.IsPunct)
+		end := start + len(core)
+
+		replacement, ok := d.lookup(core, maxDistance)
+		if !ok {
+			continue
+		}
+		words[i] = word[:start] + replacement + word[end:]
+		corrected = true
+	}
+
+	if !corrected {
+		return query, false
+	}
+	return strings.Join(words, " "), true
+}
+
+// lookup returns the dictionary word closest to an unknown word, in the word's capitalization
+func (d *spellDictionary) lookup(word string, maxDistance int) (string, bool) {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) < spellMinWordLength || d.known[string(runes)] {
+		return "", false
+	}
+	for _, r := range runes {
+		if !unicode.IsLetter(r) {
+			return "", false
+		}
+	}
+
+	allowed := maxDistance
+	if len(runes) < 5 {
+		allowed = 1
+	}
+
+	best, bestDistance := "", allowed+1
+	for length := len(runes) - allowed; length <= len(runes)+allowed; length++ {
+		for _, candidate := range d.byLength[length] {
+			// Candidates are in priority order, so only a strictly closer one replaces the best
+			if distance := levenshtein(runes, []rune(candidate), bestDistance-1); distance < bestDistance {
+				best, bestDistance = candidate, distance
+			}
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+
+	if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+		bestRunes := []rune(best)
+		bestRunes[0] = unicode.ToUpper(bestRunes[0])
+		best = string(bestRunes)
+	}
+	return best, true
+}
+
+// levenshtein returns the edit distance between a and b, or limit+1 as soon as it is known to
+// exceed limit
+func levenshtein(a, b []rune, limit int) int {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
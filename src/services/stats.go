@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+	"news-inshorts/src/types"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// statsWindowDays is the number of days covered by the per-day time series
+const statsWindowDays = 7
+
+// corpusStatsWindowDays is the number of days covered by the articles-added series
+const corpusStatsWindowDays = 30
+
+// StatsService defines the interface for article engagement statistics
+type StatsService interface {
+	GetArticleStats(ctx context.Context, articleID string) (*models.ArticleStats, error)
+	// GetCorpusStats returns aggregate statistics over all stored articles
+	GetCorpusStats(ctx context.Context) (*models.CorpusStats, error)
+	// GetInteractionStats returns event counts per bucket and event type in [from, to), and
+	// the top articles and sources by engagement in that window
+	GetInteractionStats(ctx context.Context, from, to time.Time, bucket string) (*models.InteractionStats, error)
+}
+
+// statsService implements StatsService
+type statsService struct {
+	articleRepo   repositories.ArticleRepository
+	userEventRepo repositories.UserEventRepository
+	log           infra.Logger
+	redisClient   *redis.Client
+	cacheTTL      time.Duration
+	corpusTTL     time.Duration
+	defaultTenant string
+	clock         infra.Clock
+}
+
+// NewStatsService creates a new instance of StatsService. Stats are cached per tenant, with
+// defaultTenant used for contexts carrying none. Daily series cover UTC days ending today
+// according to clock.
+func NewStatsService(articleRepo repositories.ArticleRepository, userEventRepo repositories.UserEventRepository, redisClient *redis.Client, cacheTTL, corpusTTL time.Duration, defaultTenant string, clock infra.Clock, logger infra.Logger) StatsService {
+	return &statsService{
+		articleRepo:   articleRepo,
+		userEventRepo: userEventRepo,
+		log:           logger,
+		redisClient:   redisClient,
+		cacheTTL:      cacheTTL,
+		corpusTTL:     corpusTTL,
+		defaultTenant: defaultTenant,
+		clock:         clock,
+	}
+}
+
+// GetArticleStats returns view/click totals, unique users and a per-day series for the last
+// statsWindowDays days. Articles without events yield zeros; unknown articles yield
+// repositories.ErrArticleNotFound.
+func (s *statsService) GetArticleStats(ctx context.Context, articleID string) (*models.ArticleStats, error) {
+	if stats, ok := s.getCached(ctx, articleID); ok {
+		return stats, nil
+	}
+
+	articles, err := s.articleRepo.FindByIDs(ctx, []string{articleID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up article: %w", err)
+	}
+	if len(articles) == 0 {
+		return nil, repositories.ErrArticleNotFound
+	}
+
+	totals, err := s.userEventRepo.CountByArticle(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := truncateToBucket(s.clock.Now().UTC(), models.StatsBucketDay)
+	since := today.AddDate(0, 0, -(statsWindowDays - 1))
+	counts, err := s.userEventRepo.CountByArticleGroupedByTypeAndDay(ctx, articleID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pre-fill every day in the window so days without events are reported as zeros
+	daily := make([]models.DailyEventCount, statsWindowDays)
+	index := make(map[string]int, statsWindowDays)
+	for i := range daily {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		daily[i].Date = date
+		index[date] = i
+	}
+
+	for _, count := range counts {
+		i, ok := index[count.Day.UTC().Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		switch count.EventType {
+		case models.EventTypeView:
+			daily[i].Views += count.Count
+		case models.EventTypeClick:
+			daily[i].Clicks += count.Count
+		}
+	}
+
+	stats := &models.ArticleStats{
+		ArticleID:   articleID,
+		Views:       totals.Views,
+		Clicks:      totals.Clicks,
+		UniqueUsers: totals.UniqueUsers,
+		Daily:       daily,
+	}
+
+	s.cache(ctx, stats)
+
+	return stats, nil
+}
+
+// getCached retrieves cached stats for an article
+func (s *statsService) getCached(ctx context.Context, articleID string) (*models.ArticleStats, bool) {
+	val, err := s.redisClient.Get(ctx, s.cacheKey(ctx, articleID)).Result()
+	if err != nil {
+		if err != redis.Nil {
+			s.log.Warn("Failed to get article stats from Redis", map[string]interface{}{
+				"article_id": articleID,
+				"error":      err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	var stats models.ArticleStats
+	if err := json.Unmarshal([]byte(val), &stats); err != nil {
+		s.redisClient.Del(ctx, s.cacheKey(ctx, articleID))
+		return nil, false
+	}
+
+	return &stats, true
+}
+
+// cache stores article stats with the configured TTL
+func (s *statsService) cache(ctx context.Context, stats *models.ArticleStats) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+
+	if err := s.redisClient.Set(ctx, s.cacheKey(ctx, stats.ArticleID), data, s.cacheTTL).Err(); err != nil {
+		s.log.Warn("Failed to cache article stats in Redis", map[string]interface{}{
+			"article_id": stats.ArticleID,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// cacheKey returns the Redis key for an article's stats in the tenant of ctx
+func (s *statsService) cacheKey(ctx context.Context, articleID string) string {
+	return infra.StatsCacheKey(infra.TenantFromContext(ctx, s.defaultTenant), articleID)
+}
+
+// corpusCacheKey returns the Redis key for the corpus stats of the tenant of ctx
+func (s *statsService) corpusCacheKey(ctx context.Context) string {
+	return infra.CorpusStatsCacheKey(infra.TenantFromContext(ctx, s.defaultTenant))
+}
+
+// GetCorpusStats returns article totals, counts per category and source, the share of
+// articles with a summary and an embedding, and articles added per day for the last
+// corpusStatsWindowDays days (oldest first, zeros for days without articles). Results are
+// cached for the configured corpus TTL.
+func (s *statsService) GetCorpusStats(ctx context.Context) (*models.CorpusStats, error) {
+	if stats, ok := s.getCachedCorpus(ctx); ok {
+		return stats, nil
+	}
+
+	now := s.clock.Now().UTC()
+	today := truncateToBucket(now, models.StatsBucketDay)
+	since := today.AddDate(0, 0, -(corpusStatsWindowDays - 1))
+
+	stats, err := s.articleRepo.GetCorpusStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pre-fill every day in the window so days without articles are reported as zeros
+	counts := make(map[string]int64, len(stats.DailyAdded))
+	for _, day := range stats.DailyAdded {
+		counts[day.Date] = day.Count
+	}
+	daily := make([]models.DailyArticleCount, corpusStatsWindowDays)
+	for i := range daily {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		daily[i] = models.DailyArticleCount{Date: date, Count: counts[date]}
+	}
+	stats.DailyAdded = daily
+
+	// Empty breakdowns are rendered as [] rather than null so the response shape is stable
+	if stats.Categories == nil {
+		stats.Categories = []models.CorpusCount{}
+	}
+	if stats.Sources == nil {
+		stats.Sources = []models.CorpusCount{}
+	}
+
+	stats.SummaryPercent = percentOf(stats.SummarizedArticles, stats.TotalArticles)
+	stats.EmbeddingPercent = percentOf(stats.EmbeddedArticles, stats.TotalArticles)
+	stats.GeneratedAt = now.UTC()
+
+	s.cacheCorpus(ctx, stats)
+
+	return stats, nil
+}
+
+// percentOf returns part as a percentage of total rounded to two decimals, or 0 when total is 0
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+// getCachedCorpus retrieves cached corpus stats
+func (s *statsService) getCachedCorpus(ctx context.Context) (*models.CorpusStats, bool) {
+	val, err := s.redisClient.Get(ctx, s.corpusCacheKey(ctx)).Result()
+	if err != nil {
+		if err != redis.Nil {
+			s.log.Warn("Failed to get corpus stats from Redis", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	var stats models.CorpusStats
+	if err := json.Unmarshal([]byte(val), &stats); err != nil {
+		s.redisClient.Del(ctx, s.corpusCacheKey(ctx))
+		return nil, false
+	}
+
+	return &stats, true
+}
+
+// cacheCorpus stores corpus stats with the configured corpus TTL
+func (s *statsService) cacheCorpus(ctx context.Context, stats *models.CorpusStats) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+
+	if err := s.redisClient.Set(ctx, s.corpusCacheKey(ctx), data, s.corpusTTL).Err(); err != nil {
+		s.log.Warn("Failed to cache corpus stats in Redis", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// GetInteractionStats builds a series with an entry for every bucket overlapping [from, to),
+// each counting every event type so buckets and types without events report zeros. The
+// caller bounds the window, which bounds the series.
+func (s *statsService) GetInteractionStats(ctx context.Context, from, to time.Time, bucket string) (*models.InteractionStats, error) {
+	counts, err := s.userEventRepo.CountByTypeAndBucket(ctx, from, to, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	topArticles, err := s.userEventRepo.TopArticlesByEngagement(ctx, from, to, types.InteractionStatsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	topSources, err := s.userEventRepo.TopSourcesByEngagement(ctx, from, to, types.InteractionStatsTopLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []models.InteractionBucket
+	index := make(map[time.Time]int)
+	for start := truncateToBucket(from, bucket); start.Before(to); start = nextBucket(start, bucket) {
+		bucketCounts := make(map[string]int64, len(models.EventTypes))
+		for _, eventType := range models.EventTypes {
+			bucketCounts[eventType] = 0
+		}
+		index[start] = len(series)
+		series = append(series, models.InteractionBucket{Start: start, Counts: bucketCounts})
+	}
+
+	for _, count := range counts {
+		i, ok := index[truncateToBucket(count.Bucket.UTC(), bucket)]
+		if !ok {
+			continue
+		}
+		series[i].Counts[count.EventType] += count.Count
+	}
+
+	// Empty rankings are rendered as [] rather than null so the response shape is stable
+	if topArticles == nil {
+		topArticles = []models.ArticleEngagement{}
+	}
+	if topSources == nil {
+		topSources = []models.SourceEngagement{}
+	}
+
+	return &models.InteractionStats{
+		From:        from,
+		To:          to,
+		Bucket:      bucket,
+		Series:      series,
+		TopArticles: topArticles,
+		TopSources:  topSources,
+	}, nil
+}
+
+// truncateToBucket returns the start of the hour or day (in t's location) containing t
+func truncateToBucket(t time.Time, bucket string) time.Time {
+	if bucket == models.StatsBucketHour {
+		return t.Truncate(time.Hour)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// nextBucket returns the start of the bucket after the one starting at start
+func nextBucket(start time.Time, bucket string) time.Time {
+	if bucket == models.StatsBucketHour {
+		return start.Add(time.Hour)
+	}
+	return start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// unreachableRedis returns a client whose every call fails at once, which the services treat
+// as a cache miss
+func unreachableRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// statsArticleRepo knows one article and records the window start of corpus stats
+type statsArticleRepo struct {
+	repositories.ArticleRepository
+	since time.Time
+	daily []models.DailyArticleCount
+}
+
+func (r *statsArticleRepo) FindByIDs(ctx context.Context, ids []string) ([]models.Article, error) {
+	return []models.Article{{ID: ids[0]}}, nil
+}
+
+func (r *statsArticleRepo) GetCorpusStats(ctx context.Context, since time.Time) (*models.CorpusStats, error) {
+	r.since = since
+	return &models.CorpusStats{DailyAdded: r.daily}, nil
+}
+
+// statsEventRepo returns fixed per-day counts and records the window start
+type statsEventRepo struct {
+	repositories.UserEventRepository
+	since time.Time
+	days  []repositories.EventDayCount
+}
+
+func (r *statsEventRepo) CountByArticle(ctx context.Context, articleID string) (*repositories.EventTotals, error) {
+	return &repositories.EventTotals{}, nil
+}
+
+func (r *statsEventRepo) CountByArticleGroupedByTypeAndDay(ctx context.Context, articleID string, since time.Time) ([]repositories.EventDayCount, error) {
+	r.since = since
+	return r.days, nil
+}
+
+// TestStatsDailyWindowsUseUTC runs the stats at 00:30 in UTC+05:30, which is still the
+// previous day in UTC, and checks both windows end on the UTC day
+func TestStatsDailyWindowsUseUTC(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+30*60)
+	clock := infra.FixedClock(time.Date(2026, 10, 15, 0, 30, 0, 0, ist))
+	utcToday := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+
+	articles := &statsArticleRepo{daily: []models.DailyArticleCount{{Date: "2026-10-14", Count: 3}}}
+	events := &statsEventRepo{days: []repositories.EventDayCount{
+		// The repository reports day starts as instants; the zone they are read in must not matter
+		{Day: utcToday.In(ist), EventType: models.EventTypeView, Count: 2},
+	}}
+	svc := NewStatsService(articles, events, unreachableRedis(t), time.Minute, time.Minute, infra.DefaultTenant, clock, infra.NewRecordingLogger())
+
+	t.Run("article stats", func(t *testing.T) {
+		stats, err := svc.GetArticleStats(context.Background(), "article-1")
+		if err != nil {
+			t.Fatalf("GetArticleStats failed: %v", err)
+		}
+		if want := utcToday.AddDate(0, 0, -(statsWindowDays - 1)); !events.since.Equal(want) {
+			t.Errorf("since = %v, want %v", events.since, want)
+		}
+		last := stats.Daily[len(stats.Daily)-1]
+		if last.Date != "2026-10-14" || last.Views != 2 {
+			t.Errorf("last day = %+v, want 2026-10-14 with 2 views", last)
+		}
+	})
+
+	t.Run("corpus stats", func(t *testing.T) {
+		stats, err := svc.GetCorpusStats(context.Background())
+		if err != nil {
+			t.Fatalf("GetCorpusStats failed: %v", err)
+		}
+		if want := utcToday.AddDate(0, 0, -(corpusStatsWindowDays - 1)); !articles.since.Equal(want) {
+			t.Errorf("since = %v, want %v", articles.since, want)
+		}
+		last := stats.DailyAdded[len(stats.DailyAdded)-1]
+		if last.Date != "2026-10-14" || last.Count != 3 {
+			t.Errorf("last day = %+v, want 2026-10-14 with 3 articles", last)
+		}
+		if stats.GeneratedAt.Location() != time.UTC {
+			t.Errorf("generated_at location = %v, want UTC", stats.GeneratedAt.Location())
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// summarizeLockTTL bounds how long a summarize call holds its article's lock, should the
+// process die before releasing it. It covers a content fetch and an LLM call at their timeouts.
+const summarizeLockTTL = time.Minute
+
+// ErrSummaryInProgress is returned while another call is regenerating the article's summary
+var ErrSummaryInProgress = errors.New("summary generation already in progress for the article")
+
+// SummarizeResult is the outcome of regenerating an article's summary
+type SummarizeResult struct {
+	ID         string
+	OldSummary string
+	NewSummary string
+}
+
+// summarizeLock serializes summary regeneration per article across instances with a Redis
+// SETNX lock. It is best effort: without Redis, or while Redis fails, calls are not serialized.
+type summarizeLock struct {
+	redisClient *redis.Client
+	log         infra.Logger
+}
+
+// newSummarizeLock creates a lock; redisClient may be nil
+func newSummarizeLock(redisClient *redis.Client, logger infra.Logger) *summarizeLock {
+	return &summarizeLock{
+		redisClient: redisClient,
+		log:         logger,
+	}
+}
+
+// acquire takes the lock of an article. ok is false when another call holds it; otherwise
+// release must be called once the summary is stored.
+func (l *summarizeLock) acquire(ctx context.Context, articleID string) (release func(), ok bool) {
+	if l.redisClient == nil {
+		return func() {}, true
+	}
+
+	key := infra.SummarizeLockKey(articleID)
+	acquired, err := l.redisClient.SetNX(ctx, key, "1", summarizeLockTTL).Result()
+	if err != nil {
+		l.log.Warn("Failed to take summarize lock, continuing without it", map[string]interface{}{
+			"id":    articleID,
+			"error": err.Error(),
+		})
+		return func() {}, true
+	}
+	if !acquired {
+		return nil, false
+	}
+
+	return func() {
+		// The request context may be done by now; the lock must go regardless
+		if err := l.redisClient.Del(context.WithoutCancel(ctx), key).Err(); err != nil {
+			l.log.Warn("Failed to release summarize lock", map[string]interface{}{
+				"id":    articleID,
+				"error": err.Error(),
+			})
+		}
+	}, true
+}
+
+// SummarizeArticle regenerates and stores the summary of an article. The summary is generated
+// from the article's content when content fetching is enabled, fetching it first if it is
+// missing, and from the description otherwise.
+func (s *articleService) SummarizeArticle(ctx context.Context, id string) (*SummarizeResult, error) {
+	release, ok := s.summarizeLock.acquire(ctx, id)
+	if !ok {
+		return nil, ErrSummaryInProgress
+	}
+	defer release()
+
+	article, err := s.articleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	text := article.Description
+	if s.contentFetcher != nil {
+		// The fetched content only feeds this summary; it is stored by loads and creates
+		fetched := []models.Article{*article}
+		s.fetchContent(ctx, fetched)
+		text = s.enrichmentText(&fetched[0])
+	}
+
+	summary, err := s.llmService.GenerateSummary(ctx, article.Title, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.articleRepo.UpdateSummary(ctx, id, summary); err != nil {
+		return nil, fmt.Errorf("failed to store summary: %w", err)
+	}
+
+	s.logger.Info("Regenerated article summary", map[string]interface{}{
+		"id": id,
+	})
+
+	// Filter results and summaries in other languages carry the old summary
+	s.filterCache.invalidate(ctx)
+	s.summaryCache.invalidate(ctx, id)
+
+	return &SummarizeResult{
+		ID:         id,
+		OldSummary: article.Summary,
+		NewSummary: summary,
+	}, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrUnsupportedSummaryLang is returned for a summary language missing from models.SummaryLanguages
+var ErrUnsupportedSummaryLang = errors.New("unsupported summary language")
+
+// summaryLangCache caches article summaries generated in a language other than the stored one.
+// Entries are keyed by article id and language, so no column per language is needed.
+type summaryLangCache struct {
+	redisClient *redis.Client
+	ttl         time.Duration
+	log         infra.Logger
+}
+
+// newSummaryLangCache creates a cache whose entries live for ttl
+func newSummaryLangCache(redisClient *redis.Client, ttl time.Duration, logger infra.Logger) *summaryLangCache {
+	return &summaryLangCache{
+		redisClient: redisClient,
+		ttl:         ttl,
+		log:         logger,
+	}
+}
+
+// get returns the cached summaries in lang by article id; articles without an entry are absent
+func (sc *summaryLangCache) get(ctx context.Context, lang string, articles []models.EnrichedArticle) map[string]string {
+	result := make(map[string]string, len(articles))
+	if sc.redisClient == nil || len(articles) == 0 {
+		return result
+	}
+
+	keys := make([]string, len(articles))
+	for i := range articles {
+		keys[i] = infra.SummaryLangCacheKey(articles[i].ID, lang)
+	}
+
+	values, err := sc.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		sc.log.Warn("Failed to read cached summaries", map[string]interface{}{
+			"lang":  lang,
+			"error": err.Error(),
+		})
+		return result
+	}
+
+	for i, value := range values {
+		if summary, ok := value.(string); ok && summary != "" {
+			result[articles[i].ID] = summary
+		}
+	}
+	return result
+}
+
+// set caches summaries in lang by article id
+func (sc *summaryLangCache) set(ctx context.Context, lang string, summaries map[string]string) {
+	if sc.redisClient == nil || len(summaries) == 0 {
+		return
+	}
+
+	pipe := sc.redisClient.Pipeline()
+	for id, summary := range summaries {
+		pipe.Set(ctx, infra.SummaryLangCacheKey(id, lang), summary, sc.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		sc.log.Warn("Failed to cache summaries", map[string]interface{}{
+			"lang":  lang,
+			"error": err.Error(),
+		})
+	}
+}
+
+// invalidate drops the cached summaries of an article in every language, e.g. after its
+// stored summary was regenerated
+func (sc *summaryLangCache) invalidate(ctx context.Context, articleID string) {
+	if sc.redisClient == nil {
+		return
+	}
+
+	keys := make([]string, 0, len(models.SummaryLanguages))
+	for lang := range models.SummaryLanguages {
+		keys = append(keys, infra.SummaryLangCacheKey(articleID, lang))
+	}
+	if err := sc.redisClient.Del(ctx, keys...).Err(); err != nil {
+		sc.log.Warn("Failed to invalidate cached summaries", map[string]interface{}{
+			"id":    articleID,
+			"error": err.Error(),
+		})
+	}
+}
+
+// localizeSummaries replaces the summaries of articles with summaries in lang, taken from the
+// cache or generated. To protect the token budget at most SummaryLangMaxGenerated summaries
+// are generated per call, for the best ranked articles; the others keep their stored summary.
+// A failed or timed out call also leaves the stored summary. SummaryLang tells which
+// language each article's summary ended up in.
+func (s *articleService) localizeSummaries(ctx context.Context, articles []models.EnrichedArticle, lang string) {
+	for i := range articles {
+		if articles[i].Summary != "" {
+			articles[i].SummaryLang = s.enrichCfg.SummaryLang
+		}
+	}
+	if lang == s.enrichCfg.SummaryLang || len(articles) == 0 {
+		return
+	}
+
+	cached := s.summaryCache.get(ctx, lang, articles)
+
+	var missing []int
+	for i := range articles {
+		if summary, ok := cached[articles[i].ID]; ok {
+			articles[i].Summary = summary
+			articles[i].SummaryLang = lang
+			continue
+		}
+		missing = append(missing, i)
+	}
+
+	if len(missing) > s.enrichCfg.SummaryLangMaxGenerated {
+		s.logger.Info("Summary generation capped for query", map[string]interface{}{
+			"lang":          lang,
+			"missing":       len(missing),
+			"max_generated": s.enrichCfg.SummaryLangMaxGenerated,
+		})
+		missing = missing[:s.enrichCfg.SummaryLangMaxGenerated]
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	var mu sync.Mutex
+	generated := make(map[string]string, len(missing))
+
+	runBounded(len(missing), s.enrichCfg.LazySummaryWorkers, func(task int) {
+		article := &articles[missing[task]]
+
+		summaryCtx, cancel := context.WithTimeout(ctx, s.enrichCfg.LazySummaryTimeout)
+		summary, err := s.llmService.GenerateSummaryIn(summaryCtx, lang, article.Title, s.enrichmentText(&article.Article))
+		cancel()
+		if err != nil {
+			s.logger.Warn("Failed to generate summary in requested language", map[string]interface{}{
+				"id":    article.ID,
+				"lang":  lang,
+				"error": err.Error(),
+			})
+			return
+		}
+		if summary == "" {
+			return
+		}
+
+		// Each task owns its article, so only the shared map needs the lock
+		article.Summary = summary
+		article.SummaryLang = lang
+		mu.Lock()
+		generated[article.ID] = summary
+		mu.Unlock()
+	})
+
+	s.summaryCache.set(ctx, lang, generated)
+}
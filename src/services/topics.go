@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Entity extraction settings for trending topics
+const (
+	// entityBatchSize is the number of titles sent to the LLM per extraction call. Smaller
+	// batches lose fewer articles when a call fails.
+	entityBatchSize = 25
+	// entityCacheTTL is how long the entities of an article title are kept; titles do not change
+	entityCacheTTL = 7 * 24 * time.Hour
+	// topicArticleIDs is the number of article ids listed per topic
+	topicArticleIDs = 3
+)
+
+// TrendingTopics is the result of a trending topics request
+type TrendingTopics struct {
+	Topics []models.TrendingTopic
+	// Partial is true when entities could not be extracted for some of the trending articles
+	Partial bool
+}
+
+// GetTrendingTopics ranks the topics of the top articles trending around lat/lon by the summed
+// trending scores of the articles mentioning them. Entities are extracted from article titles
+// with batched LLM calls and cached per article; a failed batch only leaves its articles out.
+// Complete results are cached, partial ones are not so the next request can fill the gaps.
+func (s *articleService) GetTrendingTopics(ctx context.Context, lat, lon float64, articleLimit, limit int) (*TrendingTopics, error) {
+	cacheKey := infra.TrendingTopicsCacheKey(s.tenant(ctx), math.Round(lat*100)/100, math.Round(lon*100)/100, articleLimit, limit)
+	// Topics follow the trending ranking, which depends on the trending_formula variant
+	if variant := infra.ExperimentVariantFromContext(ctx, infra.ExperimentTrendingFormula); variant != infra.ExperimentControl {
+		cacheKey += ":" + variant
+	}
+	if topics, found := s.topicsCache.get(ctx, cacheKey); found {
+		return &TrendingTopics{Topics: topics}, nil
+	}
+
+	trending, err := s.rankTrending(ctx, models.Location{Latitude: lat, Longitude: lon})
+	if err != nil {
+		return nil, err
+	}
+	if len(trending) > articleLimit {
+		trending = trending[:articleLimit]
+	}
+
+	articles := make([]models.Article, len(trending))
+	scores := make(map[string]float64, len(trending))
+	for i, article := range trending {
+		articles[i] = article.Article
+		scores[article.ID] = article.TrendingScore
+	}
+
+	entities, failed, err := s.articleEntities(ctx, articles)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := rankTopics(articles, scores, entities, limit)
+
+	s.logger.Info("Computed trending topics", map[string]interface{}{
+		"articles": len(articles),
+		"failed":   failed,
+		"topics":   len(topics),
+	})
+
+	if failed == 0 {
+		s.topicsCache.set(ctx, cacheKey, topics)
+	}
+
+	return &TrendingTopics{Topics: topics, Partial: failed > 0}, nil
+}
+
+// articleEntities returns the entities of each article by id, from the cache or extracted in
+// batches, and how many articles could not be covered. An error is returned only when no
+// article has entities because every extraction failed.
+func (s *articleService) articleEntities(ctx context.Context, articles []models.Article) (map[string][]string, int, error) {
+	entities := s.topicsCache.entities(ctx, articles)
+
+	var missing []models.Article
+	for _, article := range articles {
+		if _, found := entities[article.ID]; !found {
+			missing = append(missing, article)
+		}
+	}
+
+	var mu sync.Mutex
+	failed := 0
+	var lastErr error
+
+	batches := (len(missing) + entityBatchSize - 1) / entityBatchSize
+	runBounded(batches, s.enrichCfg.Workers, func(b int) {
+		batch := missing[b*entityBatchSize : min((b+1)*entityBatchSize, len(missing))]
+
+		titles := make([]string, len(batch))
+		for i, article := range batch {
+			titles[i] = article.Title
+		}
+
+		extracted, err := s.llmService.ExtractEntities(ctx, titles)
+		if err != nil {
+			s.logger.Warn("Failed to extract entities for trending topics", map[string]interface{}{
+				"articles": len(batch),
+				"error":    err.Error(),
+			})
+			mu.Lock()
+			failed += len(batch)
+			lastErr = err
+			mu.Unlock()
+			return
+		}
+
+		// Articles the model skipped are cached without entities, like those it found none in
+		found := make(map[string][]string, len(batch))
+		for i, article := range batch {
+			found[article.ID] = extracted[i]
+		}
+		s.topicsCache.setEntities(ctx, found)
+
+		mu.Lock()
+		for id, names := range found {
+			entities[id] = names
+		}
+		mu.Unlock()
+	})
+
+	if failed > 0 && failed == len(articles) {
+		return nil, failed, fmt.Errorf("failed to extract entities: %w", lastErr)
+	}
+	return entities, failed, nil
+}
+
+// rankTopics aggregates the entities of articles into topics. Entities are compared ignoring
+// case and whitespace; a topic is named as in its highest scoring article.
+func rankTopics(articles []models.Article, scores map[string]float64, entities map[string][]string, limit int) []models.TrendingTopic {
+	byKey := make(map[string]*models.TrendingTopic)
+	var order []string
+
+	// articles are sorted by score, so article ids are appended highest scoring first
+	for _, article := range articles {
+		seen := make(map[string]bool)
+		for _, name := range entities[article.ID] {
+			key := strings.ToLower(strings.Join(strings.Fields(name), " "))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			topic, found := byKey[key]
+			if !found {
+				topic = &models.TrendingTopic{Name: name}
+				byKey[key] = topic
+				order = append(order, key)
+			}
+			topic.Score += scores[article.ID]
+			if len(topic.ArticleIDs) < topicArticleIDs {
+				topic.ArticleIDs = append(topic.ArticleIDs, article.ID)
+			}
+		}
+	}
+
+	topics := make([]models.TrendingTopic, 0, len(order))
+	for _, key := range order {
+		topic := *byKey[key]
+		topic.Score = math.Round(topic.Score*10000) / 10000
+		topics = append(topics, topic)
+	}
+
+	// Ties keep first-seen order, which favors topics of higher scoring articles
+	sort.SliceStable(topics, func(i, j int) bool {
+		return topics[i].Score > topics[j].Score
+	})
+
+	if len(topics) > limit {
+		topics = topics[:limit]
+	}
+	return topics
+}
+
+// trendingTopicsCache caches trending topics and the entities extracted per article in Redis
+type trendingTopicsCache struct {
+	redisClient *redis.Client
+	ttl         time.Duration
+	log         infra.Logger
+}
+
+// newTrendingTopicsCache creates a cache whose topic entries live for ttl
+func newTrendingTopicsCache(redisClient *redis.Client, ttl time.Duration, logger infra.Logger) *trendingTopicsCache {
+	return &trendingTopicsCache{
+		redisClient: redisClient,
+		ttl:         ttl,
+		log:         logger,
+	}
+}
+
+// get returns the cached topics for key
+func (tc *trendingTopicsCache) get(ctx context.Context, key string) ([]models.TrendingTopic, bool) {
+	if tc.redisClient == nil {
+		return nil, false
+	}
+
+	val, err := tc.redisClient.Get(ctx, key).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			tc.log.Warn("Failed to read trending topics cache", map[string]interface{}{
+				"cache_key": key,
+				"error":     err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	var topics []models.TrendingTopic
+	if err := json.Unmarshal([]byte(val), &topics); err != nil {
+		tc.redisClient.Del(ctx, key)
+		return nil, false
+	}
+	return topics, true
+}
+
+// set caches topics under key
+func (tc *trendingTopicsCache) set(ctx context.Context, key string, topics []models.TrendingTopic) {
+	if tc.redisClient == nil {
+		return
+	}
+
+	data, err := json.Marshal(topics)
+	if err != nil {
+		return
+	}
+	if err := tc.redisClient.Set(ctx, key, data, tc.ttl).Err(); err != nil {
+		tc.log.Warn("Failed to cache trending topics", map[string]interface{}{
+			"cache_key": key,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// entities returns the cached entities of articles by id; articles without an entry are absent
+func (tc *trendingTopicsCache) entities(ctx context.Context, articles []models.Article) map[string][]string {
+	result := make(map[string][]string, len(articles))
+	if tc.redisClient == nil || len(articles) == 0 {
+		return result
+	}
+
+	keys := make([]string, len(articles))
+	for i, article := range articles {
+		keys[i] = infra.TrendingEntitiesCacheKey(article.ID)
+	}
+
+	values, err := tc.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		tc.log.Warn("Failed to read cached article entities", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return result
+	}
+
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var names []string
+		if err := json.Unmarshal([]byte(str), &names); err == nil {
+			result[articles[i].ID] = names
+		}
+	}
+	return result
+}
+
+// setEntities caches the entities of articles by id
+func (tc *trendingTopicsCache) setEntities(ctx context.Context, entities map[string][]string) {
+	if tc.redisClient == nil || len(entities) == 0 {
+		return
+	}
+
+	pipe := tc.redisClient.Pipeline()
+	for id, names := range entities {
+		if names == nil {
+			names = []string{}
+		}
+		data, err := json.Marshal(names)
+		if err != nil {
+			continue
+		}
+		pipe.Set(ctx, infra.TrendingEntitiesCacheKey(id), data, entityCacheTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		tc.log.Warn("Failed to cache article entities", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
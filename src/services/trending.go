@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -10,55 +11,120 @@ import (
 	"news-inshorts/src/infra"
 	"news-inshorts/src/models"
 	"news-inshorts/src/repositories"
-
-	"github.com/redis/go-redis/v9"
+	"news-inshorts/src/utils"
 )
 
-// TrendingService defines the interface for trending news operations
+// trendingCacheSize is how many of the best trending articles are cached per cell, the
+// largest limit a trending request may ask for
+const trendingCacheSize = 100
+
+// trendingWindow is how far back user events count towards trending scores. Articles without
+// events in it are not candidates.
+const trendingWindow = 7 * 24 * time.Hour
+
+// trendingRequestsTTL keeps the request counts of a day while they are still summed into
+// the popular cells, on the day after
+const trendingRequestsTTL = 48 * time.Hour
+
+// TrendingService defines the interface for trending news operations. Trending results are
+// cached per tenant and geohash cell, so nearby requests of a tenant share an entry.
 type TrendingService interface {
-	ComputeTrendingScore(article models.Article, location models.Location) (float64, error)
-	GetCachedTrending(lat, lon float64, limit int) ([]models.Article, bool)
-	CacheTrending(lat, lon float64, articles []models.Article)
+	ComputeTrendingScore(ctx context.Context, article models.Article, location models.Location) (TrendingScore, error)
+	// Cell returns the geohash cell containing lat/lon at the configured precision
+	Cell(lat, lon float64) string
+	// GetCachedTrending returns the best limit cached trending articles of a cell
+	GetCachedTrending(ctx context.Context, cell string, limit int) ([]models.TrendingArticle, bool)
+	// CacheTrending caches the trending articles of a cell, best first
+	CacheTrending(ctx context.Context, cell string, articles []models.TrendingArticle)
+	// RecordRequest counts a trending request for a cell
+	RecordRequest(ctx context.Context, cell string)
+	// PopularCells returns up to n of the cells with the most trending requests of the tenant
+	// on ctx today and yesterday (UTC), most requested first
+	PopularCells(ctx context.Context, n int) ([]string, error)
+}
+
+// TrendingScore is the trending score of an article and what it was computed from
+type TrendingScore struct {
+	Score float64
+	// EventCounts counts the article's user events in the scoring window by event type
+	EventCounts map[string]int
+	// DistanceKm is the distance between the article and the scored location
+	DistanceKm float64
 }
 
 // trendingService implements TrendingService
 type trendingService struct {
 	userEventRepo repositories.UserEventRepository
 	log           infra.Logger
-	redisClient   *redis.Client
+	cache         CacheStore
 	cacheTTL      time.Duration
-	ctx           context.Context
+	cfg           *infra.TrendingConfig
+	defaultTenant string
+	clock         infra.Clock
 }
 
-// NewTrendingService creates a new instance of TrendingService
-func NewTrendingService(userEventRepo repositories.UserEventRepository, redisClient *redis.Client, cacheTTL time.Duration) TrendingService {
+// NewTrendingService creates a new instance of TrendingService. Scores are computed as of
+// clock's current time; results and request counts are kept in cache under the tenant on the
+// context, or defaultTenant when it carries none.
+func NewTrendingService(userEventRepo repositories.UserEventRepository, cache CacheStore, cacheTTL time.Duration, cfg *infra.TrendingConfig, defaultTenant string, clock infra.Clock, logger infra.Logger) TrendingService {
 	return &trendingService{
 		userEventRepo: userEventRepo,
-		log:           infra.GetLogger(),
-		redisClient:   redisClient,
+		log:           logger,
+		cache:         cache,
 		cacheTTL:      cacheTTL,
-		ctx:           context.Background(),
+		cfg:           cfg,
+		defaultTenant: defaultTenant,
+		clock:         clock,
 	}
 }
 
+// trendingFormula weighs the components of a trending score
+type trendingFormula struct {
+	volume, recency, geo float64
+	// decayVolume halves each event's weight for every engagementHalfLife of its age
+	decayVolume bool
+}
+
+// engagementHalfLife is the age at which an event counts half in decayed volume
+const engagementHalfLife = 24 * time.Hour
+
+// trendingFormulas are the score formulas of the trending_formula experiment by variant.
+// control is the original formula; engagement favors recent interactions over recent
+// publication.
+var trendingFormulas = map[string]trendingFormula{
+	infra.ExperimentControl: {volume: 0.4, recency: 0.4, geo: 0.2},
+	"engagement":            {volume: 0.6, recency: 0.2, geo: 0.2, decayVolume: true},
+}
+
 // ComputeTrendingScore calculates the trending score for an article based on user engagement
-// The score is computed using three factors:
-// - Interaction volume (40%): Number of user events for the article
-// - Recency (40%): How recent the article is
-// - Geographic relevance (20%): Proximity to the query location
-func (s *trendingService) ComputeTrendingScore(article models.Article, location models.Location) (float64, error) {
-	// Query user events for this article from the last 7 days
-	since := time.Now().Add(-7 * 24 * time.Hour)
-	events, err := s.userEventRepo.FindByArticleID(article.ID, since)
+// The score is computed using three factors, weighted by the formula of the request's
+// trending_formula variant (control: 40% / 40% / 20%):
+// - Interaction volume: Number of user events for the article
+// - Recency: How recent the article is
+// - Geographic relevance: Proximity to the query location
+// relevance_score is deliberately not a factor: once rescoring is enabled it already contains
+// engagement, which the volume component counts from the raw events.
+func (s *trendingService) ComputeTrendingScore(ctx context.Context, article models.Article, location models.Location) (TrendingScore, error) {
+	variant := infra.ExperimentVariantFromContext(ctx, infra.ExperimentTrendingFormula)
+	formula, ok := trendingFormulas[variant]
+	if !ok {
+		formula = trendingFormulas[infra.ExperimentControl]
+	}
+
+	now := s.clock.Now()
+
+	// Query user events for this article from the trending window
+	since := now.Add(-trendingWindow)
+	events, err := s.userEventRepo.FindByArticleID(ctx, article.ID, since)
 	if err != nil {
 		s.log.Error("Failed to retrieve user events for trending score", err, map[string]interface{}{
 			"article_id": article.ID,
 		})
-		return 0, fmt.Errorf("failed to retrieve user events: %w", err)
+		return TrendingScore{}, fmt.Errorf("failed to retrieve user events: %w", err)
 	}
 
 	// Calculate article age in hours
-	articleAge := time.Since(article.PublicationDate)
+	articleAge := now.Sub(article.PublicationDate)
 
 	// Calculate distance between article location and query location using Haversine formula
 	distance := s.calculateDistance(
@@ -69,15 +135,15 @@ func (s *trendingService) ComputeTrendingScore(article models.Article, location
 	)
 
 	// Compute individual score components
-	volumeScore := s.computeVolumeScore(len(events))
+	volumeScore := s.computeVolumeScore(events, now, formula.decayVolume)
 	recencyScore := s.computeRecencyScore(articleAge)
 	geoScore := s.computeGeoScore(distance)
 
-	// Weighted combination: 40% volume, 40% recency, 20% geographic relevance
-	trendingScore := (volumeScore * 0.4) + (recencyScore * 0.4) + (geoScore * 0.2)
+	trendingScore := (volumeScore * formula.volume) + (recencyScore * formula.recency) + (geoScore * formula.geo)
 
 	s.log.Debug("Computed trending score", map[string]interface{}{
 		"article_id":     article.ID,
+		"variant":        variant,
 		"event_count":    len(events),
 		"article_age_h":  articleAge.Hours(),
 		"distance_km":    distance,
@@ -87,14 +153,30 @@ func (s *trendingService) ComputeTrendingScore(article models.Article, location
 		"trending_score": trendingScore,
 	})
 
-	return trendingScore, nil
+	eventCounts := make(map[string]int)
+	for _, event := range events {
+		eventCounts[event.EventType]++
+	}
+
+	return TrendingScore{Score: trendingScore, EventCounts: eventCounts, DistanceKm: distance}, nil
 }
 
 // computeVolumeScore calculates the volume component of the trending score
-// Normalizes event count with a cap at 100 events
-func (s *trendingService) computeVolumeScore(eventCount int) float64 {
-	// Normalize to 0-1 range, capping at 100 events
-	return math.Min(float64(eventCount)/100.0, 1.0)
+// Each event is weighted by its type (see models.EventTypeWeights), and halved for every
+// engagementHalfLife of its age at now when decay is set. The weighted volume is normalized
+// with a cap at 100
+func (s *trendingService) computeVolumeScore(events []models.UserEvent, now time.Time, decay bool) float64 {
+	var volume float64
+	for _, event := range events {
+		weight := models.EventTypeWeights[event.EventType]
+		if decay {
+			weight *= math.Pow(0.5, now.Sub(event.Timestamp).Hours()/engagementHalfLife.Hours())
+		}
+		volume += weight
+	}
+
+	// Normalize to 0-1 range, capping at a weighted volume of 100
+	return math.Max(0, math.Min(volume/100.0, 1.0))
 }
 
 // computeRecencyScore calculates the recency component of the trending score
@@ -137,31 +219,40 @@ func (s *trendingService) calculateDistance(lat1, lon1, lat2, lon2 float64) floa
 	return earthRadiusKm * c
 }
 
-// GetCachedTrending retrieves cached trending articles for a location
-func (s *trendingService) GetCachedTrending(lat, lon float64, limit int) ([]models.Article, bool) {
-	cacheKey := s.generateCacheKey(lat, lon, limit)
+// Cell implements TrendingService
+func (s *trendingService) Cell(lat, lon float64) string {
+	return utils.Geohash(lat, lon, s.cfg.GeohashPrecision)
+}
 
-	val, err := s.redisClient.Get(s.ctx, cacheKey).Result()
-	if err == redis.Nil {
-		s.log.Debug("Cache miss for trending articles", map[string]interface{}{
-			"cache_key": cacheKey,
-		})
+// GetCachedTrending retrieves the cached trending articles of a cell
+func (s *trendingService) GetCachedTrending(ctx context.Context, cell string, limit int) ([]models.TrendingArticle, bool) {
+	cacheKey := s.cacheKey(ctx, cell)
+
+	val, found, err := s.cache.Get(ctx, cacheKey)
+	if err != nil {
+		// An open circuit is logged once by the breaker rather than on every request
+		if !errors.Is(err, ErrCircuitOpen) {
+			s.log.Warn("Failed to get cache from Redis", map[string]interface{}{
+				"cache_key": cacheKey,
+				"error":     err.Error(),
+			})
+		}
 		return nil, false
-	} else if err != nil {
-		s.log.Warn("Failed to get cache from Redis", map[string]interface{}{
+	}
+	if !found {
+		s.log.Debug("Cache miss for trending articles", map[string]interface{}{
 			"cache_key": cacheKey,
-			"error":     err.Error(),
 		})
 		return nil, false
 	}
 
-	var articles []models.Article
-	if err := json.Unmarshal([]byte(val), &articles); err != nil {
+	var articles []models.TrendingArticle
+	if err := json.Unmarshal(val, &articles); err != nil {
 		s.log.Warn("Failed to unmarshal cached articles", map[string]interface{}{
 			"cache_key": cacheKey,
 			"error":     err.Error(),
 		})
-		s.redisClient.Del(s.ctx, cacheKey)
+		s.cache.Delete(ctx, cacheKey)
 		return nil, false
 	}
 
@@ -170,12 +261,15 @@ func (s *trendingService) GetCachedTrending(lat, lon float64, limit int) ([]mode
 		"count":     len(articles),
 	})
 
+	if len(articles) > limit {
+		articles = articles[:limit]
+	}
 	return articles, true
 }
 
-// CacheTrending stores trending articles in the cache with TTL
-func (s *trendingService) CacheTrending(lat, lon float64, articles []models.Article) {
-	cacheKey := s.generateCacheKey(lat, lon, len(articles))
+// CacheTrending stores the trending articles of a cell in the cache with TTL
+func (s *trendingService) CacheTrending(ctx context.Context, cell string, articles []models.TrendingArticle) {
+	cacheKey := s.cacheKey(ctx, cell)
 
 	data, err := json.Marshal(articles)
 	if err != nil {
@@ -186,7 +280,10 @@ func (s *trendingService) CacheTrending(lat, lon float64, articles []models.Arti
 		return
 	}
 
-	if err := s.redisClient.Set(s.ctx, cacheKey, data, s.cacheTTL).Err(); err != nil {
+	if err := s.cache.Set(ctx, cacheKey, data, s.cacheTTL); err != nil {
+		if errors.Is(err, ErrCircuitOpen) {
+			return
+		}
 		s.log.Warn("Failed to cache articles in Redis", map[string]interface{}{
 			"cache_key": cacheKey,
 			"error":     err.Error(),
@@ -195,10 +292,44 @@ func (s *trendingService) CacheTrending(lat, lon float64, articles []models.Arti
 	}
 }
 
-// generateCacheKey creates a cache key from rounded coordinates and limit
-func (s *trendingService) generateCacheKey(lat, lon float64, limit int) string {
-	latRounded := math.Round(lat*100) / 100
-	lonRounded := math.Round(lon*100) / 100
+// RecordRequest counts the request in the sorted set of the current UTC day
+func (s *trendingService) RecordRequest(ctx context.Context, cell string) {
+	key := infra.TrendingCellRequestsKey(s.tenant(ctx), s.clock.Now().UTC().Format(time.DateOnly))
+	if err := s.cache.IncrMember(ctx, key, cell, 1, trendingRequestsTTL); err != nil && !errors.Is(err, ErrCircuitOpen) {
+		s.log.Warn("Failed to count trending request", map[string]interface{}{
+			"cell":  cell,
+			"error": err.Error(),
+		})
+	}
+}
+
+// PopularCells sums the request counts of today and yesterday, so the ranking does not start
+// over at midnight
+func (s *trendingService) PopularCells(ctx context.Context, n int) ([]string, error) {
+	today := s.clock.Now().UTC()
+	tenant := s.tenant(ctx)
+	keys := []string{
+		infra.TrendingCellRequestsKey(tenant, today.Format(time.DateOnly)),
+		infra.TrendingCellRequestsKey(tenant, today.AddDate(0, 0, -1).Format(time.DateOnly)),
+	}
+
+	cells, err := s.cache.TopMembers(ctx, keys, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular trending cells: %w", err)
+	}
+	return cells, nil
+}
+
+// cacheKey returns the cache key of a cell for the request's tenant and trending_formula variant
+func (s *trendingService) cacheKey(ctx context.Context, cell string) string {
+	cacheKey := infra.TrendingCacheKey(s.tenant(ctx), cell)
+	if variant := infra.ExperimentVariantFromContext(ctx, infra.ExperimentTrendingFormula); variant != infra.ExperimentControl {
+		cacheKey += ":" + variant
+	}
+	return cacheKey
+}
 
-	return fmt.Sprintf("trending:%.2f:%.2f:%d", latRounded, lonRounded, limit)
+// tenant returns the tenant of ctx, whose results and request counts are cached separately
+func (s *trendingService) tenant(ctx context.Context) string {
+	return infra.TenantFromContext(ctx, s.defaultTenant)
 }
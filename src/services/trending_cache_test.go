@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// failingCacheStore fails every call, like a cache whose backend is down
+type failingCacheStore struct{}
+
+var errCacheDown = errors.New("cache down")
+
+func (failingCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errCacheDown
+}
+
+func (failingCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errCacheDown
+}
+
+func (failingCacheStore) Delete(ctx context.Context, key string) error {
+	return errCacheDown
+}
+
+func (failingCacheStore) IncrMember(ctx context.Context, key, member string, delta float64, ttl time.Duration) error {
+	return errCacheDown
+}
+
+func (failingCacheStore) TopMembers(ctx context.Context, keys []string, n int) ([]string, error) {
+	return nil, errCacheDown
+}
+
+// trendingArticleRepo returns its articles as the recent articles with events
+type trendingArticleRepo struct {
+	repositories.ArticleRepository
+	articles []models.Article
+}
+
+func (r *trendingArticleRepo) FindByIDsPublishedSince(ctx context.Context, ids []string, since time.Time) ([]models.Article, error) {
+	return r.articles, nil
+}
+
+// trendingEventsRepo returns the views of each article
+type trendingEventsRepo struct {
+	repositories.UserEventRepository
+	views map[string]int
+	now   time.Time
+}
+
+func (r *trendingEventsRepo) GetArticleIDsWithEventsSince(ctx context.Context, since time.Time) ([]string, error) {
+	ids := make([]string, 0, len(r.views))
+	for id := range r.views {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (r *trendingEventsRepo) FindByArticleID(ctx context.Context, articleID string, since time.Time) ([]models.UserEvent, error) {
+	events := make([]models.UserEvent, r.views[articleID])
+	for i := range events {
+		events[i] = models.UserEvent{ArticleID: articleID, EventType: models.EventTypeView, Timestamp: r.now}
+	}
+	return events, nil
+}
+
+// TestTrendingWithoutWorkingCache checks that trending results are computed and returned
+// when the cache is missing or failing
+func TestTrendingWithoutWorkingCache(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	published := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		cache func(t *testing.T, logger infra.Logger) CacheStore
+	}{
+		{"no Redis client", func(t *testing.T, logger infra.Logger) CacheStore {
+			return NewCacheStore("trending", nil, 50*time.Millisecond, 2, time.Minute, "", logger)
+		}},
+		{"unreachable Redis", func(t *testing.T, logger infra.Logger) CacheStore {
+			return NewCacheStore("trending", unreachableRedis(t), 50*time.Millisecond, 2, time.Minute, "", logger)
+		}},
+		{"failing cache", func(t *testing.T, logger infra.Logger) CacheStore {
+			return failingCacheStore{}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := infra.NewRecordingLogger()
+			clock := infra.FixedClock(now)
+			trendingCfg := &infra.TrendingConfig{GeohashPrecision: 5, MaxArticleAge: 30 * 24 * time.Hour}
+			events := &trendingEventsRepo{views: map[string]int{"quiet": 1, "busy": 50}, now: now}
+			articles := &trendingArticleRepo{articles: []models.Article{
+				{ID: "quiet", Title: "Quiet story", PublicationDate: published, Latitude: 12.97, Longitude: 77.59},
+				{ID: "busy", Title: "Busy story", PublicationDate: published, Latitude: 12.97, Longitude: 77.59},
+			}}
+
+			trending := NewTrendingService(events, tt.cache(t, logger), time.Minute, trendingCfg, infra.DefaultTenant, clock, logger)
+			svc := NewArticleService(
+				nil, nil, trending, noopWebhooks{}, noopSavedSearches{}, nil, nil, passthroughCategories{}, nil, nil,
+				articles, events, nil, NewJobTracker(time.Hour, clock),
+				&infra.EnrichConfig{}, &infra.ContentConfig{}, &infra.ExportConfig{}, &infra.QueryConfig{},
+				&infra.DedupeConfig{}, trendingCfg, infra.DefaultTenant,
+				nil, 0, 0, 0, clock, logger,
+			)
+
+			// The second request would be served from the cache if it worked
+			for request := 1; request <= 2; request++ {
+				results, err := svc.GetTrendingNews(context.Background(), 12.97, 77.59, 10)
+				if err != nil {
+					t.Fatalf("request %d: GetTrendingNews failed: %v", request, err)
+				}
+				ids := make([]string, 0, len(results))
+				for _, result := range results {
+					ids = append(ids, result.ID)
+				}
+				if want := []string{"busy", "quiet"}; !slices.Equal(ids, want) {
+					t.Errorf("request %d: trending = %v, want %v", request, ids, want)
+				}
+			}
+
+			// Without a cache no requests were counted; precomputation then has nothing to refresh
+			if cells, _ := trending.PopularCells(context.Background(), 5); len(cells) != 0 {
+				t.Errorf("popular cells = %v, want none", cells)
+			}
+		})
+	}
+}
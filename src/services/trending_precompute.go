@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// PrecomputeTrending implements ArticleService. Each cell starts at a random offset within
+// the precompute jitter and at most PrecomputeWorkers cells are ranked at once, so a refresh
+// spreads its queries rather than hitting the database with all of them together.
+func (s *articleService) PrecomputeTrending(ctx context.Context) (int, error) {
+	cells, err := s.trendingService.PopularCells(ctx, s.trendingCfg.PrecomputeCells)
+	if err != nil {
+		return 0, err
+	}
+	if len(cells) == 0 {
+		return 0, nil
+	}
+
+	start := time.Now()
+	var refreshed atomic.Int64
+
+	runBounded(len(cells), s.trendingCfg.PrecomputeWorkers, func(i int) {
+		var offset time.Duration
+		if s.trendingCfg.PrecomputeJitter > 0 {
+			offset = rand.N(s.trendingCfg.PrecomputeJitter)
+		}
+		timer := time.NewTimer(time.Until(start.Add(offset)))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+
+		if _, err := s.refreshTrendingCell(ctx, cells[i]); err != nil {
+			s.logger.Warn("Failed to precompute trending articles", map[string]interface{}{
+				"cell":  cells[i],
+				"error": err.Error(),
+			})
+			return
+		}
+		refreshed.Add(1)
+	})
+
+	s.logger.Info("Precomputed trending articles", map[string]interface{}{
+		"cells":     len(cells),
+		"refreshed": refreshed.Load(),
+		"elapsed":   time.Since(start).String(),
+	})
+
+	return int(refreshed.Load()), ctx.Err()
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// trendingEventRepo returns no events and records the window start it was asked for
+type trendingEventRepo struct {
+	repositories.UserEventRepository
+	since time.Time
+}
+
+func (r *trendingEventRepo) FindByArticleID(ctx context.Context, articleID string, since time.Time) ([]models.UserEvent, error) {
+	r.since = since
+	return nil, nil
+}
+
+// TestTrendingRecencyScore scores articles of different ages at a fixed time. Without events
+// and at the scored location, a control score is 0.4 × 1/(1+age in days) + 0.2 × 1.
+func TestTrendingRecencyScore(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	location := models.Location{Latitude: 12.97, Longitude: 77.59}
+
+	tests := []struct {
+		name        string
+		age         time.Duration
+		wantRecency float64
+	}{
+		{name: "1 hour", age: time.Hour, wantRecency: 24.0 / 25.0},
+		{name: "1 day", age: 24 * time.Hour, wantRecency: 1.0 / 2.0},
+		{name: "7 days", age: 7 * 24 * time.Hour, wantRecency: 1.0 / 8.0},
+		{name: "30 days", age: 30 * 24 * time.Hour, wantRecency: 1.0 / 31.0},
+	}
+
+	previous := math.Inf(1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &trendingEventRepo{}
+			logger := infra.NewRecordingLogger()
+			svc := NewTrendingService(events, NewCacheStore("trending", nil, 0, 0, 0, "", logger), time.Minute,
+				&infra.TrendingConfig{}, infra.DefaultTenant, infra.FixedClock(now), logger)
+
+			score, err := svc.ComputeTrendingScore(context.Background(), models.Article{
+				ID:              "article-1",
+				PublicationDate: now.Add(-tt.age),
+				Latitude:        location.Latitude,
+				Longitude:       location.Longitude,
+			}, location)
+			if err != nil {
+				t.Fatalf("ComputeTrendingScore failed: %v", err)
+			}
+
+			if want := 0.4*tt.wantRecency + 0.2; math.Abs(score.Score-want) > 1e-9 {
+				t.Errorf("score = %v, want %v", score.Score, want)
+			}
+			if score.Score >= previous {
+				t.Errorf("score = %v, want less than %v of the younger article", score.Score, previous)
+			}
+			previous = score.Score
+
+			if want := now.Add(-trendingWindow); !events.since.Equal(want) {
+				t.Errorf("events since %v, want %v", events.since, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+	"news-inshorts/src/repositories"
+)
+
+// ErrVectorReindexRunning is returned when a reindex is requested while another is in progress
+var ErrVectorReindexRunning = errors.New("vector reindex already in progress")
+
+// VectorIndexService reports on and rebuilds the vector index used by semantic search
+type VectorIndexService interface {
+	Status(ctx context.Context) (*models.VectorIndexStatus, error)
+	// StartReindex rebuilds the index in the background and returns a job that can be polled
+	StartReindex() (models.Job, error)
+	// CheckIndex logs a warning when the index is missing or differs from the configuration
+	CheckIndex(ctx context.Context)
+}
+
+// vectorIndexService implements VectorIndexService
+type vectorIndexService struct {
+	repo repositories.VectorIndexRepository
+	jobs *JobTracker
+	log  infra.Logger
+
+	running atomic.Bool
+}
+
+// NewVectorIndexService creates a new instance of VectorIndexService
+func NewVectorIndexService(repo repositories.VectorIndexRepository, jobs *JobTracker, logger infra.Logger) VectorIndexService {
+	return &vectorIndexService{
+		repo: repo,
+		jobs: jobs,
+		log:  logger,
+	}
+}
+
+// Status returns the current state of the index
+func (s *vectorIndexService) Status(ctx context.Context) (*models.VectorIndexStatus, error) {
+	return s.repo.Status(ctx)
+}
+
+// StartReindex launches a rebuild of the index with the configured settings
+func (s *vectorIndexService) StartReindex() (models.Job, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return models.Job{}, ErrVectorReindexRunning
+	}
+
+	job := s.jobs.Start("vector_reindex")
+
+	go func() {
+		defer s.running.Store(false)
+
+		s.log.Info("Starting vector reindex", map[string]interface{}{
+			"job_id": job.ID,
+		})
+
+		// The build outlives the request that started it, so it gets its own context
+		err := s.repo.Rebuild(context.Background())
+		if err != nil {
+			s.log.Error("Vector reindex failed", err, map[string]interface{}{
+				"job_id": job.ID,
+			})
+		} else {
+			s.log.Info("Completed vector reindex", map[string]interface{}{
+				"job_id": job.ID,
+			})
+		}
+		s.jobs.Finish(job.ID, err)
+	}()
+
+	return job, nil
+}
+
+// CheckIndex logs the state of the index so a missing or outdated index is noticed at startup
+func (s *vectorIndexService) CheckIndex(ctx context.Context) {
+	status, err := s.repo.Status(ctx)
+	if err != nil {
+		s.log.Warn("Could not check vector index", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if status.MatchesConfig {
+		s.log.Info("Vector index is up to date", map[string]interface{}{
+			"index":   status.Name,
+			"method":  status.Current.Method,
+			"options": status.Current.Options,
+		})
+		return
+	}
+
+	fields := map[string]interface{}{
+		"index":      status.Name,
+		"exists":     status.Exists,
+		"valid":      status.Valid,
+		"configured": status.Configured,
+	}
+	if status.Current != nil {
+		fields["current"] = *status.Current
+	}
+	s.log.Warn("Vector index is missing or differs from the configuration; semantic search may fall back to sequential scans until POST /api/v1/admin/vector-index/reindex is run", fields)
+}
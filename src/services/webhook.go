@@ -0,0 +1,264 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"news-inshorts/src/infra"
+	"news-inshorts/src/models"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event names
+const (
+	WebhookEventArticlesCreated = "articles.created"
+	WebhookEventTest            = "webhook.test"
+)
+
+// Webhook request headers
+const (
+	WebhookSignatureHeader = "X-Inshorts-Signature"
+	WebhookEventHeader     = "X-Inshorts-Event"
+	WebhookDeliveryHeader  = "X-Inshorts-Delivery"
+)
+
+// webhookBatchSize caps the number of articles in one payload so large loads are split into
+// several deliveries
+const webhookBatchSize = 500
+
+// ErrWebhookTargetNotFound is returned when a test delivery names a target that is not configured
+var ErrWebhookTargetNotFound = errors.New("webhook target not found")
+
+// WebhookArticle is the per-article part of a webhook payload
+type WebhookArticle struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	SourceName string `json:"source_name"`
+}
+
+// WebhookPayload is the JSON body POSTed to webhook targets
+type WebhookPayload struct {
+	Event      string           `json:"event"`
+	DeliveryID string           `json:"delivery_id"`
+	SentAt     time.Time        `json:"sent_at"`
+	Count      int              `json:"count"`
+	Articles   []WebhookArticle `json:"articles"`
+}
+
+// WebhookService notifies downstream systems when articles are stored
+type WebhookService interface {
+	// NotifyArticlesCreated queues deliveries to every enabled target and returns immediately
+	NotifyArticlesCreated(articles []models.Article)
+	// TestDelivery sends a single test payload to target, or to every target when target is
+	// empty, disabled ones included, and reports the outcome
+	TestDelivery(ctx context.Context, target string) ([]models.WebhookDeliveryResult, error)
+}
+
+// webhookService implements WebhookService over HTTP with HMAC-SHA256 signed payloads
+type webhookService struct {
+	cfg        *infra.WebhookConfig
+	httpClient *http.Client
+	log        infra.Logger
+}
+
+// NewWebhookService creates a new instance of WebhookService
+func NewWebhookService(cfg *infra.WebhookConfig, logger infra.Logger) WebhookService {
+	return &webhookService{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		log: logger,
+	}
+}
+
+// NotifyArticlesCreated sends articles to every enabled target in batches of webhookBatchSize.
+// Deliveries run in the background with their own context so they never hold up the caller.
+func (s *webhookService) NotifyArticlesCreated(articles []models.Article) {
+	targets := s.enabledTargets()
+	if len(targets) == 0 || len(articles) == 0 {
+		return
+	}
+
+	for start := 0; start < len(articles); start += webhookBatchSize {
+		batch := articles[start:min(start+webhookBatchSize, len(articles))]
+		payload := newWebhookPayload(WebhookEventArticlesCreated, batch)
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			s.log.Error("Failed to marshal webhook payload", err, map[string]interface{}{
+				"delivery_id": payload.DeliveryID,
+			})
+			continue
+		}
+
+		for _, name := range targets {
+			go s.deliverWithRetry(context.Background(), name, payload, body)
+		}
+	}
+}
+
+// TestDelivery sends one test payload synchronously, without retries
+func (s *webhookService) TestDelivery(ctx context.Context, target string) ([]models.WebhookDeliveryResult, error) {
+	names := s.targetNames()
+	if target != "" {
+		if _, ok := s.cfg.Targets[target]; !ok {
+			return nil, ErrWebhookTargetNotFound
+		}
+		names = []string{target}
+	}
+
+	payload := newWebhookPayload(WebhookEventTest, nil)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	results := make([]models.WebhookDeliveryResult, 0, len(names))
+	for _, name := range names {
+		start := time.Now()
+		statusCode, err := s.deliver(ctx, name, payload, body)
+
+		result := models.WebhookDeliveryResult{
+			Target:     name,
+			Enabled:    !s.cfg.DisabledTargets[name],
+			Delivered:  err == nil,
+			StatusCode: statusCode,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// deliverWithRetry tries a delivery up to MaxAttempts times, doubling the wait between attempts
+func (s *webhookService) deliverWithRetry(ctx context.Context, name string, payload WebhookPayload, body []byte) {
+	backoff := s.cfg.RetryBackoff
+
+	for attempt := 1; ; attempt++ {
+		statusCode, err := s.deliver(ctx, name, payload, body)
+		if err == nil {
+			infra.IncrCounter(infra.MetricWebhookDeliveries, 1)
+			s.log.Debug("Webhook delivered", map[string]interface{}{
+				"target":      name,
+				"delivery_id": payload.DeliveryID,
+				"attempt":     attempt,
+			})
+			return
+		}
+
+		if attempt >= s.cfg.MaxAttempts {
+			infra.IncrCounter(infra.MetricWebhookDeliveryFailures, 1)
+			s.log.Error("Webhook delivery failed, giving up", err, map[string]interface{}{
+				"target":      name,
+				"delivery_id": payload.DeliveryID,
+				"attempts":    attempt,
+				"status_code": statusCode,
+				"count":       payload.Count,
+			})
+			return
+		}
+
+		s.log.Warn("Webhook delivery failed, retrying", map[string]interface{}{
+			"target":      name,
+			"delivery_id": payload.DeliveryID,
+			"attempt":     attempt,
+			"retry_in":    backoff.String(),
+			"error":       err.Error(),
+		})
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// deliver POSTs body to the named target once. Any non-2xx response counts as a failure.
+func (s *webhookService) deliver(ctx context.Context, name string, payload WebhookPayload, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Targets[name], bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, payload.Event)
+	req.Header.Set(WebhookDeliveryHeader, payload.DeliveryID)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+signWebhookBody(s.cfg.Secret, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook target returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// enabledTargets returns the names of targets that receive deliveries, sorted for stable logs
+func (s *webhookService) enabledTargets() []string {
+	names := []string{}
+	for _, name := range s.targetNames() {
+		if !s.cfg.DisabledTargets[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// targetNames returns the names of all configured targets, sorted
+func (s *webhookService) targetNames() []string {
+	names := make([]string, 0, len(s.cfg.Targets))
+	for name := range s.cfg.Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newWebhookPayload builds a payload for event describing articles
+func newWebhookPayload(event string, articles []models.Article) WebhookPayload {
+	items := make([]WebhookArticle, 0, len(articles))
+	for _, article := range articles {
+		items = append(items, WebhookArticle{
+			ID:         article.ID,
+			Title:      article.Title,
+			SourceName: article.SourceName,
+		})
+	}
+
+	return WebhookPayload{
+		Event:      event,
+		DeliveryID: uuid.New().String(),
+		SentAt:     time.Now().UTC(),
+		Count:      len(items),
+		Articles:   items,
+	}
+}
+
+// signWebhookBody returns the hex-encoded HMAC-SHA256 of body under secret
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,9 +1,16 @@
 package types
 
 import (
-	"fmt"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"news-inshorts/src/models"
+
+	"github.com/google/uuid"
 )
 
 // QueryArticlesRequest represents the query parameters for GET /api/v1/news/query
@@ -11,13 +18,39 @@ type QueryArticlesRequest struct {
 	Query    string           `query:"query" validate:"required"`
 	Lat      float64          `query:"lat" validate:"omitempty,min=-90,max=90"`
 	Lon      float64          `query:"lon" validate:"omitempty,min=-180,max=180"`
+	Limit    int              `query:"limit" validate:"omitempty,min=1,max=50"`
 	Location *models.Location `json:"-"` // Computed field, not from query params
+	// MinSimilarity overrides the configured minimum similarity of semantic matches
+	MinSimilarity *float64 `query:"min_similarity" validate:"omitempty,min=0,max=1"`
+	// UserID biases the ranking by the user's category preferences
+	UserID string `query:"user_id"`
+	// SummaryLang asks for summaries in the language with this ISO 639-1 code
+	SummaryLang string `query:"summary_lang"`
+	// NoCorrect runs the query as typed, without spelling correction
+	NoCorrect bool `query:"no_correct"`
 }
 
 func (r *QueryArticlesRequest) Validate() error {
+	var errs ValidationErrors
+
+	// Supported languages are checked by the service, which rejects others with a 400
+	r.SummaryLang = strings.ToLower(strings.TrimSpace(r.SummaryLang))
+
 	// Validate required fields
 	if r.Query == "" {
-		return fmt.Errorf("query parameter is required")
+		errs.Add("query", ValidationCodeRequired, "query parameter is required")
+	}
+
+	// Set default limit if not provided
+	if r.Limit == 0 {
+		r.Limit = 5
+	}
+	if r.Limit < 1 || r.Limit > 50 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 50")
+	}
+
+	if r.MinSimilarity != nil && (*r.MinSimilarity < 0 || *r.MinSimilarity > 1) {
+		errs.Add("min_similarity", ValidationCodeOutOfRange, "min_similarity must be between 0 and 1")
 	}
 
 	// Build Location object if lat/lon are provided
@@ -27,76 +60,290 @@ func (r *QueryArticlesRequest) Validate() error {
 
 	if hasLat || hasLon {
 		// Both lat and lon must be provided together
-		if !hasLat || !hasLon {
-			return fmt.Errorf("both lat and lon must be provided together")
+		if !hasLat {
+			errs.Add("lat", ValidationCodeRequired, "both lat and lon must be provided together")
+		}
+		if !hasLon {
+			errs.Add("lon", ValidationCodeRequired, "both lat and lon must be provided together")
 		}
 		if r.Lat < -90 || r.Lat > 90 {
-			return fmt.Errorf("latitude must be between -90 and 90")
+			errs.Add("lat", ValidationCodeOutOfRange, "latitude must be between -90 and 90")
 		}
 		if r.Lon < -180 || r.Lon > 180 {
-			return fmt.Errorf("longitude must be between -180 and 180")
+			errs.Add("lon", ValidationCodeOutOfRange, "longitude must be between -180 and 180")
 		}
-		r.Location = &models.Location{
-			Latitude:  r.Lat,
-			Longitude: r.Lon,
+		if len(errs) == 0 {
+			r.Location = &models.Location{
+				Latitude:  r.Lat,
+				Longitude: r.Lon,
+			}
 		}
 	}
-	return nil
+	return errs.Err()
 }
 
 // QueryArticlesResponse represents the response for news query endpoint
 type QueryArticlesResponse struct {
+	Articles []models.EnrichedArticle `json:"articles"`
+	// Total is the number of matches before truncation to the requested limit
+	Total int `json:"total"`
+	// Degraded is set when the LLM was unavailable and a rule-based parser analyzed the query
+	Degraded bool `json:"degraded,omitempty"`
+	// QueryTruncated is set when the query was longer than the soft limit and only its start was used
+	QueryTruncated bool `json:"query_truncated,omitempty"`
+	// CorrectedQuery is the query that was run when misspelled words were corrected
+	CorrectedQuery string `json:"corrected_query,omitempty"`
+	// Relaxed lists the constraints relaxed, in order, because the query as stated matched nothing
+	Relaxed []string `json:"relaxed,omitempty"`
+}
+
+// AnalyzeQueryResponse represents the response for GET /api/v1/news/query/analyze
+type AnalyzeQueryResponse struct {
+	// Query is the normalized query that was analyzed
+	Query          string               `json:"query"`
+	QueryTruncated bool                 `json:"query_truncated,omitempty"`
+	CorrectedQuery string               `json:"corrected_query,omitempty"`
+	Analysis       models.QueryAnalysis `json:"analysis"`
+	// Plan lists the filters /news/query would run for the analysis, in order
+	Plan     []models.FilterStep `json:"plan"`
+	CacheHit bool                `json:"cache_hit"`
+	Degraded bool                `json:"degraded,omitempty"`
+}
+
+// TrendingArticlesResponse represents the response for the trending news endpoint
+type TrendingArticlesResponse struct {
+	Articles []models.TrendingArticle `json:"articles"`
+	Total    int                      `json:"total"`
+	// Location is the location the articles were ranked for
+	Location ResolvedLocation `json:"location"`
+}
+
+// LegacyTrendingArticlesResponse is the response for the trending news endpoint with
+// verbose=false: the articles without their trending score, rank, event counts and distance.
+// Deprecated: kept for one release for clients that depend on the old shape.
+type LegacyTrendingArticlesResponse struct {
 	Articles []models.Article `json:"articles"`
+	Total    int              `json:"total"`
+	Location ResolvedLocation `json:"location"`
+}
+
+// TrendingTopicsResponse represents the response for the trending topics endpoint
+type TrendingTopicsResponse struct {
+	Topics []models.TrendingTopic `json:"topics"`
+	Total  int                    `json:"total"`
+	// Partial is set when topics could not be extracted from some trending articles
+	Partial bool `json:"partial,omitempty"`
+	// Location is the location the articles were ranked for
+	Location ResolvedLocation `json:"location"`
+}
+
+// ResolvedLocation is the location used for a request and where it came from: the lat/lon
+// query parameters, the X-User-Location header, the client IP or the configured default
+type ResolvedLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Source    string  `json:"source"`
 }
 
 // LoadDataRequest represents the request body for POST /api/v1/news/load
 type LoadDataRequest struct {
 	Filepath string `json:"filepath" validate:"required"`
+	// DryRun validates the file and checks for duplicate URLs without enriching or storing anything
+	DryRun bool `json:"dry_run"`
+	// DetectDuplicates skips articles whose title closely matches a stored article's title
+	DetectDuplicates bool `json:"detect_duplicates"`
 }
 
 // LoadDataResponse represents the response for data loading endpoint
 type LoadDataResponse struct {
-	Success          bool     `json:"success"`
-	Message          string   `json:"message"`
-	TotalArticles    int      `json:"total_articles"`
-	SuccessCount     int      `json:"success_count"`
-	ErrorCount       int      `json:"error_count"`
-	ValidationErrors []string `json:"validation_errors,omitempty"`
+	Success bool `json:"success"`
+	// ErrorCode and Error are the error envelope; only set when the load failed validation
+	ErrorCode          string            `json:"error_code,omitempty"`
+	Error              string            `json:"error,omitempty"`
+	Message            string            `json:"message"`
+	TotalArticles      int               `json:"total_articles"`
+	SuccessCount       int               `json:"success_count"`
+	ErrorCount         int               `json:"error_count"`
+	ValidationErrors   []ValidationError `json:"validation_errors,omitempty"`
+	EnrichmentFailures []string          `json:"enrichment_failures,omitempty"`
+	// Dry-run results; only set when the request had dry_run
+	DryRun      bool `json:"dry_run,omitempty"`
+	WouldInsert *int `json:"would_insert,omitempty"`
+	WouldSkip   *int `json:"would_skip,omitempty"`
+	// DuplicateURLs lists URLs skipped (or that a dry run would skip) because their canonical
+	// form is already stored or repeated in the file
+	DuplicateURLs []string `json:"duplicate_urls,omitempty"`
+	// SuspectedDuplicates lists articles skipped because their title closely matches a stored
+	// article's; only set when the request had detect_duplicates
+	SuspectedDuplicates []SuspectedDuplicate `json:"suspected_duplicates,omitempty"`
+	// FailedChunks lists chunks that could not be stored because their transaction kept
+	// failing with a serialization failure or deadlock
+	FailedChunks []FailedChunk `json:"failed_chunks,omitempty"`
+}
+
+// FailedChunk is a range of articles from a load whose transaction was given up on
+type FailedChunk struct {
+	// From and To are the positions of the first and one past the last article of the chunk
+	// in the loaded file. Suspected duplicates skipped within that range are not part of it
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	SQLState string `json:"sqlstate"`
+	Error    string `json:"error"`
+}
+
+// SuspectedDuplicate is an article from a load whose title closely matches a stored article
+type SuspectedDuplicate struct {
+	// Index is the position of the article in the loaded file
+	Index      int     `json:"index"`
+	Title      string  `json:"title"`
+	ExistingID string  `json:"existing_id"`
+	Similarity float64 `json:"similarity"`
 }
 
 // FilterArticlesRequest represents the query parameters for GET /api/v1/news/filter
 type FilterArticlesRequest struct {
-	Category       string  `json:"category" query:"category" validate:"omitempty"`
-	Source         string  `json:"source" query:"source" validate:"omitempty"`
-	Lat            float64 `json:"lat" query:"lat" validate:"omitempty,min=-90,max=90"`
-	Lon            float64 `json:"lon" query:"lon" validate:"omitempty,min=-180,max=180"`
-	Radius         float64 `json:"radius" query:"radius" validate:"omitempty,min=0"`
-	ScoreThreshold float64 `json:"score_threshold" query:"score_threshold" validate:"omitempty,min=0,max=1"`
+	// Category and Source take repeated parameters (?category=a&category=b). Comma-separated
+	// values are split too, for clients of the former single-value form.
+	Category []string `json:"category" query:"category"`
+	// CategoryMode is any (articles in at least one category) or all (articles in every
+	// category, the default)
+	CategoryMode string `json:"category_mode" query:"category_mode"`
+	// Source matches source names containing any of the values, ignoring case. Values still
+	// wrapped for ILIKE ('%name%') are unwrapped.
+	Source []string `json:"source" query:"source"`
+	// ExcludeCategory drops articles in any of the categories, ExcludeSource articles whose
+	// source contains any of the names; both are repeatable like Category and Source. Country
+	// and Region match the reverse-geocoded country and region (state or province) of articles,
+	// ignoring case, and are repeatable too.
+	ExcludeCategory []string `json:"exclude_category" query:"exclude_category"`
+	ExcludeSource   []string `json:"exclude_source" query:"exclude_source"`
+	Country         []string `json:"country" query:"country"`
+	Region          []string `json:"region" query:"region"`
+	Lat             float64  `json:"lat" query:"lat" validate:"omitempty,min=-90,max=90"`
+	Lon             float64  `json:"lon" query:"lon" validate:"omitempty,min=-180,max=180"`
+	Radius          float64  `json:"radius" query:"radius" validate:"omitempty,min=0"`
+	ScoreThreshold  float64  `json:"score_threshold" query:"score_threshold" validate:"omitempty,min=0,max=1"`
+	// Sentiment is a comma-separated list of positive, neutral or negative. Articles that were
+	// never classified are excluded when it is set.
+	Sentiment string `json:"sentiment" query:"sentiment" validate:"omitempty"`
+	// CacheBypass skips the result cache, for debugging stale results
+	CacheBypass bool `json:"-" query:"cache_bypass"`
+	// PublishedFrom (inclusive) and PublishedTo (exclusive) bound publication_date; zero values
+	// leave the bound unset. Set by the date range intent of /news/query.
+	PublishedFrom time.Time `json:"-" query:"-"`
+	PublishedTo   time.Time `json:"-" query:"-"`
+	// Place matches articles whose country or region is any of the names. Set by the region
+	// intent of /news/query, where the LLM does not say which of the two a name is.
+	Place []string `json:"-" query:"-"`
+	// IngestedAfter (RFC3339) keeps articles whose created_at is strictly later, oldest first,
+	// for clients polling for new articles. IngestedAfterID, the id of the last article already
+	// seen, also keeps articles created at exactly IngestedAfter with a greater id, so a page
+	// ending partway through a batch inserted together resumes where it stopped.
+	IngestedAfter   string `json:"ingested_after" query:"ingested_after"`
+	IngestedAfterID string `json:"ingested_after_id" query:"ingested_after_id"`
+	// Limit caps the number of articles returned; 0 returns every match
+	Limit int `json:"limit" query:"limit"`
+	// IncludeArchived also searches articles moved to the archive table
+	IncludeArchived bool `json:"include_archived" query:"include_archived"`
 }
 
+// MaxFilterLimit is the largest limit accepted by GET /api/v1/news/filter
+const MaxFilterLimit = 1000
+
+// Category matching modes of FilterArticlesRequest
+const (
+	CategoryModeAny = "any"
+	CategoryModeAll = "all"
+)
+
 // Validate validates the FilterArticlesRequest
 // At least one filter (category, source, lat/lon, or score_threshold) must be provided
 func (r *FilterArticlesRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Category = SplitList(r.Category...)
+	r.Source = sourceNames(SplitList(r.Source...))
+	r.ExcludeCategory = SplitList(r.ExcludeCategory...)
+	r.ExcludeSource = sourceNames(SplitList(r.ExcludeSource...))
+	r.Country = SplitList(r.Country...)
+	r.Region = SplitList(r.Region...)
+
 	// Check that at least one filter is provided
-	if r.Category == "" && r.Source == "" && (r.Lat == 0 || r.Lon == 0) && r.ScoreThreshold == 0 {
-		return fmt.Errorf("at least one filter parameter must be provided: category, source, lat/lon, or score_threshold")
+	if len(r.Category) == 0 && len(r.Source) == 0 && len(r.ExcludeCategory) == 0 && len(r.ExcludeSource) == 0 && len(r.Country) == 0 && len(r.Region) == 0 && (r.Lat == 0 || r.Lon == 0) && r.ScoreThreshold == 0 && r.Sentiment == "" && r.IngestedAfter == "" {
+		errs.Add("", ValidationCodeRequired, "at least one filter parameter must be provided: category, source, exclude_category, exclude_source, country, region, lat/lon, score_threshold, sentiment, or ingested_after")
+	}
+
+	// A value both included and excluded can only produce an empty result. Categories match
+	// exactly, sources ignoring case.
+	for _, category := range r.ExcludeCategory {
+		if slices.Contains(r.Category, category) {
+			errs.Add("exclude_category", ValidationCodeInvalidValue, "category "+category+" is both included and excluded")
+		}
+	}
+	for _, source := range r.ExcludeSource {
+		if slices.ContainsFunc(r.Source, func(s string) bool { return strings.EqualFold(s, source) }) {
+			errs.Add("exclude_source", ValidationCodeInvalidValue, "source "+source+" is both included and excluded")
+		}
+	}
+
+	switch r.CategoryMode {
+	case "":
+		r.CategoryMode = CategoryModeAll
+	case CategoryModeAny, CategoryModeAll:
+	default:
+		errs.Add("category_mode", ValidationCodeInvalidValue, "category_mode must be one of: any, all")
 	}
 
 	// Validate latitude if provided
 	if r.Lat != 0 || r.Lon != 0 {
-		if r.Lat < -90 || r.Lat > 90 || r.Lon < -180 || r.Lon > 180 {
-			return fmt.Errorf("latitude and longitude must be between -90 and 90 and -180 and 180 respectively")
+		if r.Lat < -90 || r.Lat > 90 {
+			errs.Add("lat", ValidationCodeOutOfRange, "latitude must be between -90 and 90")
+		}
+		if r.Lon < -180 || r.Lon > 180 {
+			errs.Add("lon", ValidationCodeOutOfRange, "longitude must be between -180 and 180")
 		}
 	}
 
 	// Validate score threshold if provided
 	if r.ScoreThreshold != 0 {
 		if r.ScoreThreshold < 0 || r.ScoreThreshold > 1 {
-			return fmt.Errorf("score_threshold must be between 0 and 1")
+			errs.Add("score_threshold", ValidationCodeOutOfRange, "score_threshold must be between 0 and 1")
+		}
+	}
+
+	// Validate sentiment values if provided
+	if r.Sentiment != "" {
+		for _, sentiment := range strings.Split(r.Sentiment, ",") {
+			if !models.IsValidSentiment(strings.TrimSpace(sentiment)) {
+				errs.Add("sentiment", ValidationCodeInvalidValue, "sentiment must be one of: "+strings.Join(models.Sentiments, ", "))
+				break
+			}
+		}
+	}
+
+	// Normalize ingested_after to UTC so equal instants share a cache entry
+	r.IngestedAfter = strings.TrimSpace(r.IngestedAfter)
+	r.IngestedAfterID = strings.TrimSpace(r.IngestedAfterID)
+	if r.IngestedAfter != "" {
+		if t, err := time.Parse(time.RFC3339Nano, r.IngestedAfter); err != nil {
+			errs.Add("ingested_after", ValidationCodeInvalidFormat, "ingested_after must be an RFC3339 timestamp")
+		} else {
+			r.IngestedAfter = t.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	if r.IngestedAfterID != "" {
+		if r.IngestedAfter == "" {
+			errs.Add("ingested_after_id", ValidationCodeInvalidValue, "ingested_after_id requires ingested_after")
+		} else if _, err := uuid.Parse(r.IngestedAfterID); err != nil {
+			errs.Add("ingested_after_id", ValidationCodeInvalidFormat, "ingested_after_id must be a valid UUID")
 		}
 	}
 
-	return nil
+	if r.Limit < 0 || r.Limit > MaxFilterLimit {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and "+strconv.Itoa(MaxFilterLimit))
+	}
+
+	return errs.Err()
 }
 
 // FilterArticlesResponse represents the response for the filter articles endpoint
@@ -104,6 +351,132 @@ type FilterArticlesResponse struct {
 	Articles []models.Article `json:"articles"`
 }
 
+// ExportArticlesRequest represents the query parameters for GET /api/v1/news/export. It takes
+// the filter parameters of GET /api/v1/news/filter plus the output format.
+type ExportArticlesRequest struct {
+	Category        []string `query:"category"`
+	CategoryMode    string   `query:"category_mode"`
+	Source          []string `query:"source"`
+	ExcludeCategory []string `query:"exclude_category"`
+	ExcludeSource   []string `query:"exclude_source"`
+	Country         []string `query:"country"`
+	Region          []string `query:"region"`
+	Lat             float64  `query:"lat"`
+	Lon             float64  `query:"lon"`
+	Radius          float64  `query:"radius"`
+	ScoreThreshold  float64  `query:"score_threshold"`
+	Sentiment       string   `query:"sentiment"`
+	IngestedAfter   string   `query:"ingested_after"`
+	IngestedAfterID string   `query:"ingested_after_id"`
+	// Format is csv (default) or ndjson
+	Format string `query:"format"`
+}
+
+// Filter returns the filter part of the request
+func (r *ExportArticlesRequest) Filter() FilterArticlesRequest {
+	return FilterArticlesRequest{
+		Category:        r.Category,
+		CategoryMode:    r.CategoryMode,
+		Source:          r.Source,
+		ExcludeCategory: r.ExcludeCategory,
+		ExcludeSource:   r.ExcludeSource,
+		Country:         r.Country,
+		Region:          r.Region,
+		Lat:             r.Lat,
+		Lon:             r.Lon,
+		Radius:          r.Radius,
+		ScoreThreshold:  r.ScoreThreshold,
+		Sentiment:       r.Sentiment,
+		IngestedAfter:   r.IngestedAfter,
+		IngestedAfterID: r.IngestedAfterID,
+	}
+}
+
+// Validate validates the ExportArticlesRequest using the filter endpoint's rules
+func (r *ExportArticlesRequest) Validate() error {
+	var errs ValidationErrors
+
+	filter := r.Filter()
+	var filterErrs ValidationErrors
+	if errors.As(filter.Validate(), &filterErrs) {
+		errs = append(errs, filterErrs...)
+	}
+	r.Category, r.CategoryMode, r.Source = filter.Category, filter.CategoryMode, filter.Source
+	r.ExcludeCategory, r.ExcludeSource = filter.ExcludeCategory, filter.ExcludeSource
+	r.Country, r.Region = filter.Country, filter.Region
+	r.IngestedAfter, r.IngestedAfterID = filter.IngestedAfter, filter.IngestedAfterID
+
+	if r.Format == "" {
+		r.Format = "csv"
+	}
+	if r.Format != "csv" && r.Format != "ndjson" {
+		errs.Add("format", ValidationCodeInvalidValue, "format must be csv or ndjson")
+	}
+
+	return errs.Err()
+}
+
+// SearchArticlesRequest represents the query parameters for GET /api/v1/news/search
+type SearchArticlesRequest struct {
+	Q        string `query:"q" validate:"required"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Category string `query:"category" validate:"omitempty"`
+	Source   string `query:"source" validate:"omitempty"`
+}
+
+// Validate validates the SearchArticlesRequest
+func (r *SearchArticlesRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Q = strings.TrimSpace(r.Q)
+	if r.Q == "" {
+		errs.Add("q", ValidationCodeRequired, "q parameter is required")
+	}
+
+	// Set default limit if not provided
+	if r.Limit == 0 {
+		r.Limit = 10
+	}
+	if r.Limit < 1 || r.Limit > 100 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 100")
+	}
+
+	r.Category = strings.TrimSpace(r.Category)
+	r.Source = strings.TrimSpace(r.Source)
+
+	return errs.Err()
+}
+
+// ListArticlesRequest represents the query parameters for GET /api/v1/news
+type ListArticlesRequest struct {
+	// Cursor is the next_cursor of the previous page; empty for the first page
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+// Validate validates the ListArticlesRequest. The cursor is opaque and checked when it is used.
+func (r *ListArticlesRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Cursor = strings.TrimSpace(r.Cursor)
+
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Limit < 1 || r.Limit > 100 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 100")
+	}
+
+	return errs.Err()
+}
+
+// ListArticlesResponse represents the response for GET /api/v1/news
+type ListArticlesResponse struct {
+	Articles []models.Article `json:"articles"`
+	// NextCursor fetches the following page; omitted on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
 // CreateArticleRequest represents the request body for POST /api/v1/news
 type CreateArticleRequest struct {
 	Title           string   `json:"title" validate:"required"`
@@ -111,40 +484,42 @@ type CreateArticleRequest struct {
 	URL             string   `json:"url" validate:"required,url"`
 	PublicationDate string   `json:"publication_date" validate:"required"`
 	SourceName      string   `json:"source_name" validate:"required"`
-	Category        []string `json:"category" validate:"required,min=1"`
+	Category        []string `json:"category"`
 	RelevanceScore  float64  `json:"relevance_score" validate:"required,min=0,max=1"`
 	Latitude        float64  `json:"latitude" validate:"required,min=-90,max=90"`
 	Longitude       float64  `json:"longitude" validate:"required,min=-180,max=180"`
 	Summary         string   `json:"summary"`
+	Country         string   `json:"country"`
+	Region          string   `json:"region"`
 }
 
 // Validate validates the CreateArticleRequest
 func (r *CreateArticleRequest) Validate() error {
+	var errs ValidationErrors
+
 	if r.Title == "" {
-		return fmt.Errorf("title is required")
+		errs.Add("title", ValidationCodeRequired, "title is required")
 	}
 	if r.URL == "" {
-		return fmt.Errorf("url is required")
+		errs.Add("url", ValidationCodeRequired, "url is required")
 	}
 	if r.SourceName == "" {
-		return fmt.Errorf("source_name is required")
-	}
-	if len(r.Category) == 0 {
-		return fmt.Errorf("at least one category is required")
+		errs.Add("source_name", ValidationCodeRequired, "source_name is required")
 	}
+	// An empty category list is checked by the service, which may classify the article itself
 	if r.RelevanceScore < 0 || r.RelevanceScore > 1 {
-		return fmt.Errorf("relevance_score must be between 0 and 1")
+		errs.Add("relevance_score", ValidationCodeOutOfRange, "relevance_score must be between 0 and 1")
 	}
 	if r.Latitude < -90 || r.Latitude > 90 {
-		return fmt.Errorf("latitude must be between -90 and 90")
+		errs.Add("latitude", ValidationCodeOutOfRange, "latitude must be between -90 and 90")
 	}
 	if r.Longitude < -180 || r.Longitude > 180 {
-		return fmt.Errorf("longitude must be between -180 and 180")
+		errs.Add("longitude", ValidationCodeOutOfRange, "longitude must be between -180 and 180")
 	}
 	if r.PublicationDate == "" {
-		return fmt.Errorf("publication_date is required")
+		errs.Add("publication_date", ValidationCodeRequired, "publication_date is required")
 	}
-	return nil
+	return errs.Err()
 }
 
 // CreateArticleResponse represents the response for the create article endpoint
@@ -152,6 +527,237 @@ type CreateArticleResponse struct {
 	Success bool           `json:"success"`
 	Message string         `json:"message"`
 	Article models.Article `json:"article"`
+	// AutoAssignedCategories lists the categories picked by the service because the request had none
+	AutoAssignedCategories []string `json:"auto_assigned_categories,omitempty"`
+}
+
+// BackfillRequest represents the request body for POST /api/v1/news/backfill
+type BackfillRequest struct {
+	AfterID     string `json:"after_id"`
+	MaxArticles int    `json:"max_articles" validate:"omitempty,min=0"`
+}
+
+// Validate validates the BackfillRequest
+func (r *BackfillRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.MaxArticles < 0 {
+		errs.Add("max_articles", ValidationCodeOutOfRange, "max_articles must be greater than or equal to 0")
+	}
+	return errs.Err()
+}
+
+// FlushCacheRequest represents the query parameters for POST /api/v1/admin/cache/flush
+type FlushCacheRequest struct {
+	Scope string `query:"scope"`
+}
+
+// Validate validates the FlushCacheRequest
+func (r *FlushCacheRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.Scope == "" {
+		errs.Add("scope", ValidationCodeRequired, "scope is required")
+	} else if !models.IsValidCacheScope(r.Scope) {
+		errs.Add("scope", ValidationCodeInvalidValue, "scope must be one of: "+strings.Join(models.CacheScopes, ", ")+", "+models.CacheScopeAll)
+	}
+	return errs.Err()
+}
+
+// FlushCacheResponse represents the response for POST /api/v1/admin/cache/flush
+type FlushCacheResponse struct {
+	Scope string `json:"scope"`
+	// Deleted is the number of keys deleted per scope
+	Deleted map[string]int `json:"deleted"`
+	Total   int            `json:"total"`
+}
+
+// JobResponse represents the response for endpoints that start or report a background job
+type JobResponse struct {
+	Job models.Job `json:"job"`
+}
+
+// ArticleActionResponse represents the response for article lifecycle endpoints (delete, restore, purge)
+type ArticleActionResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	ID      string `json:"id"`
+}
+
+// SummarizeArticleResponse represents the response for POST /api/v1/news/:id/summarize
+type SummarizeArticleResponse struct {
+	ID string `json:"id"`
+	// OldSummary is the summary that was replaced, empty when the article had none
+	OldSummary string `json:"old_summary"`
+	NewSummary string `json:"new_summary"`
+}
+
+// TestWebhookRequest represents the request body for POST /api/v1/admin/webhooks/test
+type TestWebhookRequest struct {
+	// Target names a single configured target; empty tests every target
+	Target string `json:"target"`
+}
+
+// TestWebhookResponse represents the response for POST /api/v1/admin/webhooks/test
+type TestWebhookResponse struct {
+	Results []models.WebhookDeliveryResult `json:"results"`
+}
+
+// maxSourceNameLength is the length of articles.source_name and source_aliases.alias
+const maxSourceNameLength = 255
+
+// UpsertSourceAliasRequest represents the request for PUT /api/v1/admin/source-aliases/:alias
+type UpsertSourceAliasRequest struct {
+	// Alias comes from the path
+	Alias       string   `json:"-"`
+	SourceNames []string `json:"source_names"`
+}
+
+// Validate validates the UpsertSourceAliasRequest, trimming the names and dropping
+// duplicates that differ only in case
+func (r *UpsertSourceAliasRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Alias = strings.TrimSpace(r.Alias)
+	if r.Alias == "" {
+		errs.Add("alias", ValidationCodeRequired, "alias is required")
+	} else if len(r.Alias) > maxSourceNameLength {
+		errs.Add("alias", ValidationCodeOutOfRange, "alias must be at most "+strconv.Itoa(maxSourceNameLength)+" characters")
+	}
+
+	names := make([]string, 0, len(r.SourceNames))
+	for _, name := range r.SourceNames {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) }) {
+			continue
+		}
+		if len(name) > maxSourceNameLength {
+			errs.Add("source_names", ValidationCodeOutOfRange, "source names must be at most "+strconv.Itoa(maxSourceNameLength)+" characters")
+			break
+		}
+		names = append(names, name)
+	}
+	r.SourceNames = names
+
+	if len(r.SourceNames) == 0 {
+		errs.Add("source_names", ValidationCodeRequired, "at least one source name is required")
+	}
+
+	return errs.Err()
+}
+
+// SourceAliasesResponse represents the response for GET /api/v1/admin/source-aliases
+type SourceAliasesResponse struct {
+	Aliases []models.SourceAlias `json:"aliases"`
+}
+
+// maxCategoryNameLength is the length of categories.name
+const maxCategoryNameLength = 255
+
+// categoryNames trims names and drops blanks and duplicates that differ only in case, adding
+// an error for field when a name is too long
+func categoryNames(field string, names []string, errs *ValidationErrors) []string {
+	cleaned := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.ContainsFunc(cleaned, func(n string) bool { return strings.EqualFold(n, name) }) {
+			continue
+		}
+		if len(name) > maxCategoryNameLength {
+			errs.Add(field, ValidationCodeOutOfRange, field+" must be at most "+strconv.Itoa(maxCategoryNameLength)+" characters")
+			break
+		}
+		cleaned = append(cleaned, name)
+	}
+	return cleaned
+}
+
+// CreateCategoryRequest represents the request for POST /api/v1/admin/categories
+type CreateCategoryRequest struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// Validate validates the CreateCategoryRequest, trimming the name and aliases and dropping
+// aliases that repeat the name or each other
+func (r *CreateCategoryRequest) Validate() error {
+	var errs ValidationErrors
+
+	names := categoryNames("name", []string{r.Name}, &errs)
+	if len(names) == 0 {
+		errs.Add("name", ValidationCodeRequired, "name is required")
+	} else {
+		r.Name = names[0]
+	}
+
+	aliases := categoryNames("aliases", r.Aliases, &errs)
+	r.Aliases = slices.DeleteFunc(aliases, func(alias string) bool { return strings.EqualFold(alias, r.Name) })
+
+	return errs.Err()
+}
+
+// RenameCategoryRequest represents the request for POST /api/v1/admin/categories/:name/rename
+type RenameCategoryRequest struct {
+	// Name comes from the path
+	Name    string `json:"-"`
+	NewName string `json:"new_name"`
+}
+
+// Validate validates the RenameCategoryRequest, trimming the names
+func (r *RenameCategoryRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		errs.Add("name", ValidationCodeRequired, "name is required")
+	}
+
+	names := categoryNames("new_name", []string{r.NewName}, &errs)
+	if len(names) == 0 {
+		errs.Add("new_name", ValidationCodeRequired, "new_name is required")
+	} else {
+		r.NewName = names[0]
+	}
+
+	return errs.Err()
+}
+
+// MergeCategoriesRequest represents the request for POST /api/v1/admin/categories/:name/merge
+type MergeCategoriesRequest struct {
+	// Target comes from the path
+	Target  string   `json:"-"`
+	Sources []string `json:"sources"`
+}
+
+// Validate validates the MergeCategoriesRequest, trimming the names and dropping duplicates
+func (r *MergeCategoriesRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Target = strings.TrimSpace(r.Target)
+	if r.Target == "" {
+		errs.Add("name", ValidationCodeRequired, "name is required")
+	}
+
+	r.Sources = categoryNames("sources", r.Sources, &errs)
+	if len(r.Sources) == 0 {
+		errs.Add("sources", ValidationCodeRequired, "at least one source category is required")
+	} else if slices.ContainsFunc(r.Sources, func(source string) bool { return strings.EqualFold(source, r.Target) }) {
+		errs.Add("sources", ValidationCodeInvalidValue, "a category cannot be merged into itself")
+	}
+
+	return errs.Err()
+}
+
+// CategoriesResponse represents the response for GET /api/v1/admin/categories
+type CategoriesResponse struct {
+	Categories []models.Category `json:"categories"`
+}
+
+// CategoryChangeResponse represents the response for renaming or merging categories
+type CategoryChangeResponse struct {
+	Category models.Category `json:"category"`
+	// ArticlesUpdated is the number of stored articles whose categories were rewritten
+	ArticlesUpdated int64 `json:"articles_updated"`
 }
 
 // GetTrendingRequest represents the query parameters for GET /api/v1/news/trending
@@ -159,6 +765,103 @@ type GetTrendingRequest struct {
 	Lat   float64 `query:"lat" validate:"omitempty,min=-90,max=90"`
 	Lon   float64 `query:"lon" validate:"omitempty,min=-180,max=180"`
 	Limit int     `query:"limit" validate:"omitempty,min=1,max=100"`
+	// Verbose set to false returns the articles without trending data, in the shape used
+	// before it was added. Deprecated: to be removed in the next release.
+	Verbose *bool `query:"verbose"`
+	// UserLocation is the raw X-User-Location header ("lat,lon")
+	UserLocation   string           `json:"-"`
+	HeaderLocation *models.Location `json:"-"` // Computed from UserLocation
+}
+
+// GetTrendingTopicsRequest represents the query parameters for GET /api/v1/news/trending/topics
+type GetTrendingTopicsRequest struct {
+	Lat float64 `query:"lat"`
+	Lon float64 `query:"lon"`
+	// Limit is the number of topics returned
+	Limit int `query:"limit"`
+	// Articles is the number of top trending articles topics are extracted from
+	Articles int `query:"articles"`
+	// UserLocation is the raw X-User-Location header ("lat,lon")
+	UserLocation   string           `json:"-"`
+	HeaderLocation *models.Location `json:"-"` // Computed from UserLocation
+}
+
+// Validate validates the GetTrendingTopicsRequest
+func (r *GetTrendingTopicsRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.Lat < -90 || r.Lat > 90 {
+		errs.Add("lat", ValidationCodeOutOfRange, "latitude must be between -90 and 90")
+	}
+	if r.Lon < -180 || r.Lon > 180 {
+		errs.Add("lon", ValidationCodeOutOfRange, "longitude must be between -180 and 180")
+	}
+
+	if r.UserLocation != "" {
+		r.HeaderLocation = parseUserLocation(r.UserLocation, &errs)
+	}
+
+	if r.Limit == 0 {
+		r.Limit = 10
+	}
+	if r.Limit < 1 || r.Limit > 50 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 50")
+	}
+
+	if r.Articles == 0 {
+		r.Articles = 50
+	}
+	if r.Articles < 1 || r.Articles > 100 {
+		errs.Add("articles", ValidationCodeOutOfRange, "articles must be between 1 and 100")
+	}
+
+	return errs.Err()
+}
+
+// TrendingRequest returns the location part of the request as a GetTrendingRequest
+func (r *GetTrendingTopicsRequest) TrendingRequest() *GetTrendingRequest {
+	return &GetTrendingRequest{Lat: r.Lat, Lon: r.Lon, HeaderLocation: r.HeaderLocation}
+}
+
+// UserLocationHeader carries the client location as "lat,lon" when it is not in the query
+const UserLocationHeader = "X-User-Location"
+
+// HasCoordinates reports whether the request has lat/lon query parameters. 0,0 counts as
+// absent: it is what clients without a location fix send.
+func (r *GetTrendingRequest) HasCoordinates() bool {
+	return r.Lat != 0 || r.Lon != 0
+}
+
+// FeedRequest represents the query parameters for GET /api/v1/news/feed.rss. The feed lists
+// trending articles unless category or source is set, in which case it lists filtered ones.
+type FeedRequest struct {
+	Category string  `query:"category"`
+	Source   string  `query:"source"`
+	Lat      float64 `query:"lat"`
+	Lon      float64 `query:"lon"`
+	Limit    int     `query:"limit"`
+}
+
+// Validate validates the FeedRequest
+func (r *FeedRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.Lat < -90 || r.Lat > 90 {
+		errs.Add("lat", ValidationCodeOutOfRange, "latitude must be between -90 and 90")
+	}
+
+	if r.Lon < -180 || r.Lon > 180 {
+		errs.Add("lon", ValidationCodeOutOfRange, "longitude must be between -180 and 180")
+	}
+
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Limit < 1 || r.Limit > 100 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 100")
+	}
+
+	return errs.Err()
 }
 
 // ErrorResponse represents a standardized error response with error code
@@ -169,20 +872,26 @@ type ErrorResponse struct {
 
 // Validate validates the GetTrendingRequest
 func (r *GetTrendingRequest) Validate() error {
+	var errs ValidationErrors
+
 	// Validate latitude if provided
 	if r.Lat != 0 {
 		if r.Lat < -90 || r.Lat > 90 {
-			return fmt.Errorf("latitude must be between -90 and 90")
+			errs.Add("lat", ValidationCodeOutOfRange, "latitude must be between -90 and 90")
 		}
 	}
 
 	// Validate longitude if provided
 	if r.Lon != 0 {
 		if r.Lon < -180 || r.Lon > 180 {
-			return fmt.Errorf("longitude must be between -180 and 180")
+			errs.Add("lon", ValidationCodeOutOfRange, "longitude must be between -180 and 180")
 		}
 	}
 
+	if r.UserLocation != "" {
+		r.HeaderLocation = parseUserLocation(r.UserLocation, &errs)
+	}
+
 	// Set default limit if not provided
 	if r.Limit == 0 {
 		r.Limit = 10
@@ -190,7 +899,7 @@ func (r *GetTrendingRequest) Validate() error {
 
 	// Validate limit
 	if r.Limit <= 0 {
-		return fmt.Errorf("limit must be greater than 0")
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be greater than 0")
 	}
 
 	// Cap limit at 100
@@ -198,5 +907,233 @@ func (r *GetTrendingRequest) Validate() error {
 		r.Limit = 100
 	}
 
-	return nil
+	return errs.Err()
+}
+
+// parseUserLocation parses an X-User-Location header value, adding an error to errs and
+// returning nil when it is malformed or out of range
+func parseUserLocation(value string, errs *ValidationErrors) *models.Location {
+	latStr, lonStr, ok := strings.Cut(value, ",")
+	if !ok {
+		errs.Add(UserLocationHeader, ValidationCodeInvalidFormat, "X-User-Location must be formatted as lat,lon")
+		return nil
+	}
+
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	lon, lonErr := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if latErr != nil || lonErr != nil || math.IsNaN(lat) || math.IsNaN(lon) {
+		errs.Add(UserLocationHeader, ValidationCodeInvalidFormat, "X-User-Location must be formatted as lat,lon")
+		return nil
+	}
+
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		errs.Add(UserLocationHeader, ValidationCodeOutOfRange, "X-User-Location latitude must be between -90 and 90 and longitude between -180 and 180")
+		return nil
+	}
+
+	return &models.Location{Latitude: lat, Longitude: lon}
+}
+
+// SplitList splits comma-separated values, trims whitespace and drops empty entries
+func SplitList(values ...string) []string {
+	items := []string{}
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// sourceNames unwraps source values that were pre-formatted for ILIKE ('%name%'), which the
+// filter endpoint used to expect; wildcards are now added server-side
+func sourceNames(values []string) []string {
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		if name := strings.Trim(value, "'% "); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// StartExportRequest represents the optional request body for POST /api/v1/admin/exports.
+// Omitted fields fall back to the scheduled snapshot settings.
+type StartExportRequest struct {
+	Mode           string `json:"mode"`
+	IncludeVectors *bool  `json:"include_vectors"`
+}
+
+// Validate validates the StartExportRequest
+func (r *StartExportRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Mode = strings.ToLower(strings.TrimSpace(r.Mode))
+	if r.Mode != "" && r.Mode != models.ExportModeFull && r.Mode != models.ExportModeIncremental {
+		errs.Add("mode", ValidationCodeInvalidValue, "mode must be one of: full, incremental")
+	}
+
+	return errs.Err()
+}
+
+// ExportRunsResponse represents the response for GET /api/v1/admin/exports
+type ExportRunsResponse struct {
+	Runs []models.ExportRun `json:"runs"`
+}
+
+// ListLLMAuditRequest represents the query parameters for GET /api/v1/admin/llm/audit
+type ListLLMAuditRequest struct {
+	// Cursor is the next_cursor of the previous page; empty for the first page
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+// Validate validates the ListLLMAuditRequest. The cursor is opaque and checked when it is used.
+func (r *ListLLMAuditRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Cursor = strings.TrimSpace(r.Cursor)
+
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Limit < 1 || r.Limit > 100 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 100")
+	}
+
+	return errs.Err()
+}
+
+// LLMAuditResponse represents the response for GET /api/v1/admin/llm/audit
+type LLMAuditResponse struct {
+	Records []models.LLMAuditRecord `json:"records"`
+	// NextCursor fetches the following page; omitted on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// PurgeLLMAuditRequest represents the query parameters for DELETE /api/v1/admin/llm/audit
+type PurgeLLMAuditRequest struct {
+	// Before is an RFC 3339 time; records created before it are deleted, every record when empty
+	Before string `query:"before"`
+
+	BeforeTime time.Time `json:"-" query:"-"`
+}
+
+// Validate validates the PurgeLLMAuditRequest and parses Before into BeforeTime
+func (r *PurgeLLMAuditRequest) Validate() error {
+	var errs ValidationErrors
+
+	if before := strings.TrimSpace(r.Before); before != "" {
+		parsed, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			errs.Add("before", ValidationCodeInvalidFormat, "before must be an RFC 3339 time")
+		} else {
+			r.BeforeTime = parsed
+		}
+	}
+
+	return errs.Err()
+}
+
+// PurgeLLMAuditResponse represents the response for DELETE /api/v1/admin/llm/audit
+type PurgeLLMAuditResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// ListClustersRequest represents the query parameters for GET /api/v1/news/clusters
+type ListClustersRequest struct {
+	// Since is an RFC 3339 time; clusters are sized by their members published since then.
+	// Empty means the start of the clustering window.
+	Since      string `query:"since"`
+	MinMembers int    `query:"min_members"`
+	Limit      int    `query:"limit"`
+
+	SinceTime time.Time `json:"-" query:"-"`
+}
+
+// Validate validates the ListClustersRequest, applies defaults and parses Since into SinceTime
+func (r *ListClustersRequest) Validate() error {
+	var errs ValidationErrors
+
+	if since := strings.TrimSpace(r.Since); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			errs.Add("since", ValidationCodeInvalidFormat, "since must be an RFC 3339 time")
+		} else {
+			r.SinceTime = parsed
+		}
+	}
+
+	if r.MinMembers == 0 {
+		r.MinMembers = 1
+	}
+	if r.MinMembers < 1 {
+		errs.Add("min_members", ValidationCodeOutOfRange, "min_members must be at least 1")
+	}
+
+	if r.Limit == 0 {
+		r.Limit = 10
+	}
+	if r.Limit < 1 || r.Limit > 50 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 50")
+	}
+
+	return errs.Err()
+}
+
+// ListClustersResponse represents the response for GET /api/v1/news/clusters
+type ListClustersResponse struct {
+	Clusters []models.StoryCluster `json:"clusters"`
+	Total    int                   `json:"total"`
+}
+
+// MaxTopQueriesWindow is the longest window GET /api/v1/admin/queries/top accepts
+const MaxTopQueriesWindow = 90 * 24 * time.Hour
+
+// TopQueriesRequest represents the query parameters for GET /api/v1/admin/queries/top
+type TopQueriesRequest struct {
+	// Window is a Go duration, e.g. 24h
+	Window string `query:"window"`
+	Limit  int    `query:"limit"`
+	// WindowDuration is Window parsed by Validate
+	WindowDuration time.Duration `query:"-"`
+}
+
+// Validate validates the TopQueriesRequest. window defaults to 24h and limit to 20.
+func (r *TopQueriesRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.Window = strings.TrimSpace(r.Window)
+	if r.Window == "" {
+		r.Window = "24h"
+	}
+	window, err := time.ParseDuration(r.Window)
+	switch {
+	case err != nil:
+		errs.Add("window", ValidationCodeInvalidFormat, "window must be a duration, e.g. 24h")
+	case window <= 0 || window > MaxTopQueriesWindow:
+		errs.Add("window", ValidationCodeOutOfRange, "window must be greater than 0 and at most 2160h (90 days)")
+	default:
+		r.WindowDuration = window
+	}
+
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Limit < 1 || r.Limit > 100 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 100")
+	}
+
+	return errs.Err()
+}
+
+// TopQueriesResponse represents the response for GET /api/v1/admin/queries/top
+type TopQueriesResponse struct {
+	Window string    `json:"window"`
+	Since  time.Time `json:"since"`
+	// Top lists the most frequent queries, ZeroResults the most frequent ones that found nothing
+	Top         []models.QueryCount `json:"top"`
+	ZeroResults []models.QueryCount `json:"zero_results"`
 }
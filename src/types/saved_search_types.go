@@ -0,0 +1,89 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"news-inshorts/src/models"
+)
+
+// maxSavedSearchQueryLength caps saved search queries, which are embedded on creation
+const maxSavedSearchQueryLength = 500
+
+// CreateSavedSearchRequest represents the request body for POST /api/v1/users/:user_id/searches
+type CreateSavedSearchRequest struct {
+	// UserID comes from the path
+	UserID   string   `json:"-"`
+	Query    string   `json:"query"`
+	Category []string `json:"category"`
+	Source   []string `json:"source"`
+}
+
+// Validate validates the CreateSavedSearchRequest, trimming the query and the filters
+func (r *CreateSavedSearchRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.UserID == "" {
+		errs.Add("user_id", ValidationCodeRequired, "user_id is required")
+	}
+
+	r.Query = strings.TrimSpace(r.Query)
+	if r.Query == "" {
+		errs.Add("query", ValidationCodeRequired, "query field is required")
+	} else if len(r.Query) > maxSavedSearchQueryLength {
+		errs.Add("query", ValidationCodeOutOfRange, "query must be at most "+strconv.Itoa(maxSavedSearchQueryLength)+" characters")
+	}
+
+	r.Category = SplitList(r.Category...)
+	r.Source = SplitList(r.Source...)
+	for _, source := range r.Source {
+		if len(source) > maxSourceNameLength {
+			errs.Add("source", ValidationCodeOutOfRange, "source names must be at most "+strconv.Itoa(maxSourceNameLength)+" characters")
+			break
+		}
+	}
+
+	return errs.Err()
+}
+
+// Model returns the saved search described by the request
+func (r *CreateSavedSearchRequest) Model() *models.SavedSearch {
+	return &models.SavedSearch{
+		UserID:   r.UserID,
+		Query:    r.Query,
+		Category: r.Category,
+		Source:   r.Source,
+	}
+}
+
+// SavedSearchesResponse represents the response for GET /api/v1/users/:user_id/searches
+type SavedSearchesResponse struct {
+	Searches []models.SavedSearch `json:"searches"`
+	Total    int                  `json:"total"`
+}
+
+// SavedSearchMatchesRequest represents the query parameters for
+// GET /api/v1/users/:user_id/searches/:id/matches
+type SavedSearchMatchesRequest struct {
+	Limit int `query:"limit"`
+}
+
+// Validate validates the SavedSearchMatchesRequest
+func (r *SavedSearchMatchesRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Limit < 1 || r.Limit > 100 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 100")
+	}
+
+	return errs.Err()
+}
+
+// SavedSearchMatchesResponse represents the response for GET /api/v1/users/:user_id/searches/:id/matches
+type SavedSearchMatchesResponse struct {
+	Matches []models.SavedSearchMatch `json:"matches"`
+	Total   int                       `json:"total"`
+}
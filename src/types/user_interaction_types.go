@@ -2,6 +2,9 @@ package types
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"news-inshorts/src/models"
 )
@@ -10,41 +13,233 @@ import (
 type RecordInteractionRequest struct {
 	UserID    string          `json:"user_id" validate:"required"`
 	ArticleID string          `json:"article_id" validate:"required"`
-	EventType string          `json:"event_type" validate:"required,oneof=view click"`
+	EventType string          `json:"event_type" validate:"required,oneof=view click share bookmark unbookmark dismiss"`
+	Value     *float64        `json:"value,omitempty" validate:"omitempty,min=0"`
 	Location  models.Location `json:"location" validate:"required"`
+	// ClientEventID is an optional idempotency key; the Idempotency-Key header takes precedence
+	ClientEventID string `json:"client_event_id,omitempty"`
 }
 
 // Validate validates the RecordInteractionRequest
 func (r *RecordInteractionRequest) Validate() error {
+	var errs ValidationErrors
+
 	if r.UserID == "" {
-		return fmt.Errorf("user_id field is required")
+		errs.Add("user_id", ValidationCodeRequired, "user_id field is required")
 	}
 
 	if r.ArticleID == "" {
-		return fmt.Errorf("article_id field is required")
+		errs.Add("article_id", ValidationCodeRequired, "article_id field is required")
 	}
 
 	if r.EventType == "" {
-		return fmt.Errorf("event_type field is required")
+		errs.Add("event_type", ValidationCodeRequired, "event_type field is required")
+	} else if !models.IsValidEventType(r.EventType) {
+		errs.Add("event_type", ValidationCodeInvalidValue, fmt.Sprintf("event_type must be one of: %s", strings.Join(models.EventTypes, ", ")))
 	}
 
-	if r.EventType != "view" && r.EventType != "click" {
-		return fmt.Errorf("event_type must be either 'view' or 'click'")
+	if r.Value != nil && *r.Value < 0 {
+		errs.Add("value", ValidationCodeOutOfRange, "value must be greater than or equal to 0")
 	}
 
 	if r.Location.Latitude < -90 || r.Location.Latitude > 90 {
-		return fmt.Errorf("latitude must be between -90 and 90")
+		errs.Add("location.latitude", ValidationCodeOutOfRange, "latitude must be between -90 and 90")
 	}
 
 	if r.Location.Longitude < -180 || r.Location.Longitude > 180 {
-		return fmt.Errorf("longitude must be between -180 and 180")
+		errs.Add("location.longitude", ValidationCodeOutOfRange, "longitude must be between -180 and 180")
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // RecordInteractionResponse represents the response for interaction recording endpoint
 type RecordInteractionResponse struct {
 	Success bool   `json:"success"`
 	EventID string `json:"event_id"`
+	// Deduplicated is set when the idempotency key was seen before and no new event was stored
+	Deduplicated bool `json:"deduplicated,omitempty"`
+}
+
+// MaxInteractionBatchSize is the maximum number of events accepted by POST /api/v1/interactions/batch
+const MaxInteractionBatchSize = 500
+
+// RecordInteractionBatchRequest represents the request body for POST /api/v1/interactions/batch
+type RecordInteractionBatchRequest []RecordInteractionRequest
+
+// Validate checks the batch size; individual events are validated separately so that one bad
+// event does not reject the whole batch
+func (r RecordInteractionBatchRequest) Validate() error {
+	var errs ValidationErrors
+
+	if len(r) == 0 {
+		errs.Add("", ValidationCodeRequired, "at least one event is required")
+	}
+
+	if len(r) > MaxInteractionBatchSize {
+		errs.Add("", ValidationCodeOutOfRange, fmt.Sprintf("at most %d events can be recorded per batch", MaxInteractionBatchSize))
+	}
+
+	return errs.Err()
+}
+
+// RecordInteractionBatchResponse represents the response for the batch interaction endpoint
+type RecordInteractionBatchResponse struct {
+	Success          bool              `json:"success"`
+	TotalEvents      int               `json:"total_events"`
+	SuccessCount     int               `json:"success_count"`
+	ErrorCount       int               `json:"error_count"`
+	ValidationErrors []ValidationError `json:"validation_errors,omitempty"`
+	EventIDs         []string          `json:"event_ids"`
+}
+
+// PurgeUserEventsResponse represents the response for DELETE /api/v1/interactions/users/:user_id
+type PurgeUserEventsResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// GetBookmarksRequest represents the query parameters for GET /api/v1/interactions/users/:user_id/bookmarks
+type GetBookmarksRequest struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+// Validate validates the GetBookmarksRequest
+func (r *GetBookmarksRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Limit < 1 || r.Limit > 100 {
+		errs.Add("limit", ValidationCodeOutOfRange, "limit must be between 1 and 100")
+	}
+
+	if r.Offset < 0 {
+		errs.Add("offset", ValidationCodeOutOfRange, "offset must be greater than or equal to 0")
+	}
+
+	return errs.Err()
+}
+
+// BookmarksResponse represents the response for GET /api/v1/interactions/users/:user_id/bookmarks
+type BookmarksResponse struct {
+	Bookmarks []models.BookmarkedArticle `json:"bookmarks"`
+	// Total is the number of articles the user has bookmarked, across all pages
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// MaxPreferenceCategories is the maximum number of categories a user can weigh
+const MaxPreferenceCategories = 100
+
+// PutPreferencesRequest represents the request body for PUT /api/v1/users/:user_id/preferences
+type PutPreferencesRequest struct {
+	// UserID comes from the path
+	UserID string `json:"-"`
+	// Categories maps category names to weights between -1 (less) and 1 (more)
+	Categories map[string]float64 `json:"categories"`
+}
+
+// Validate validates the PutPreferencesRequest, lowercasing and trimming the categories and
+// dropping zero weights, which are neutral anyway
+func (r *PutPreferencesRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.UserID == "" {
+		errs.Add("user_id", ValidationCodeRequired, "user_id is required")
+	}
+
+	categories := make(map[string]float64, len(r.Categories))
+	for name, weight := range r.Categories {
+		category := strings.ToLower(strings.TrimSpace(name))
+		field := "categories." + name
+		switch {
+		case category == "":
+			errs.Add(field, ValidationCodeRequired, "category names must not be empty")
+		case weight < -1 || weight > 1:
+			errs.Add(field, ValidationCodeOutOfRange, "weight must be between -1 and 1")
+		default:
+			if _, duplicate := categories[category]; duplicate {
+				errs.Add(field, ValidationCodeInvalidValue, "category "+category+" is listed more than once")
+				continue
+			}
+			categories[category] = weight
+		}
+	}
+
+	if len(categories) > MaxPreferenceCategories {
+		errs.Add("categories", ValidationCodeOutOfRange, "at most "+strconv.Itoa(MaxPreferenceCategories)+" categories can be weighed")
+	}
+
+	for category, weight := range categories {
+		if weight == 0 {
+			delete(categories, category)
+		}
+	}
+	r.Categories = categories
+
+	return errs.Err()
+}
+
+// MaxInteractionStatsWindow is the longest window GET /api/v1/admin/stats/interactions accepts.
+// It bounds the series to 2160 hourly buckets.
+const MaxInteractionStatsWindow = 90 * 24 * time.Hour
+
+// InteractionStatsTopLimit is the number of top articles and sources in interaction stats
+const InteractionStatsTopLimit = 10
+
+// InteractionStatsRequest represents the query parameters for GET /api/v1/admin/stats/interactions
+type InteractionStatsRequest struct {
+	// From (inclusive) and To (exclusive) are RFC3339 timestamps
+	From   string `query:"from"`
+	To     string `query:"to"`
+	Bucket string `query:"bucket"`
+	// FromTime and ToTime are From and To parsed by Validate, in UTC
+	FromTime time.Time `query:"-"`
+	ToTime   time.Time `query:"-"`
+}
+
+// Validate validates the InteractionStatsRequest. The window must be non-empty and at most
+// MaxInteractionStatsWindow long; bucket defaults to day.
+func (r *InteractionStatsRequest) Validate() error {
+	var errs ValidationErrors
+
+	r.FromTime = parseStatsTime(&errs, "from", r.From)
+	r.ToTime = parseStatsTime(&errs, "to", r.To)
+	if !r.FromTime.IsZero() && !r.ToTime.IsZero() {
+		if !r.ToTime.After(r.FromTime) {
+			errs.Add("to", ValidationCodeInvalidValue, "to must be later than from")
+		} else if r.ToTime.Sub(r.FromTime) > MaxInteractionStatsWindow {
+			errs.Add("to", ValidationCodeOutOfRange, "the window from from to to must be at most 90 days")
+		}
+	}
+
+	switch r.Bucket {
+	case "":
+		r.Bucket = models.StatsBucketDay
+	case models.StatsBucketHour, models.StatsBucketDay:
+	default:
+		errs.Add("bucket", ValidationCodeInvalidValue, "bucket must be one of: hour, day")
+	}
+
+	return errs.Err()
+}
+
+// parseStatsTime parses a required RFC3339 timestamp, recording a validation error under field
+// and returning the zero time when it is missing or malformed
+func parseStatsTime(errs *ValidationErrors, field, value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		errs.Add(field, ValidationCodeRequired, field+" is required")
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		errs.Add(field, ValidationCodeInvalidFormat, field+" must be an RFC3339 timestamp")
+		return time.Time{}
+	}
+	return t.UTC()
 }
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validation error codes
+const (
+	ValidationCodeRequired      = "REQUIRED"
+	ValidationCodeOutOfRange    = "OUT_OF_RANGE"
+	ValidationCodeInvalidValue  = "INVALID_VALUE"
+	ValidationCodeInvalidFormat = "INVALID_FORMAT"
+)
+
+// ValidationError describes a single invalid field. Index identifies the item within a
+// batch (articles in a load, events in an interaction batch) and is omitted otherwise.
+type ValidationError struct {
+	Index   *int   `json:"index,omitempty"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+// String returns the human-readable form of the error, prefixed with the index if set
+func (e ValidationError) String() string {
+	if e.Index != nil {
+		return fmt.Sprintf("item %d: %s", *e.Index, e.Message)
+	}
+	return e.Message
+}
+
+// ValidationErrors collects the validation errors of a request. It implements error so
+// Validate methods can return it; use Err to avoid returning a non-nil empty value.
+type ValidationErrors []ValidationError
+
+// Add appends an error for field
+func (v *ValidationErrors) Add(field, code, message string) {
+	*v = append(*v, ValidationError{Field: field, Code: code, Message: message})
+}
+
+// Error implements the error interface
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, e := range v {
+		messages = append(messages, e.String())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Err returns v as an error, or nil when there are no errors
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// WithIndex returns a copy of v with every error attributed to the batch item at index
+func (v ValidationErrors) WithIndex(index int) ValidationErrors {
+	indexed := make(ValidationErrors, len(v))
+	for i, e := range v {
+		idx := index
+		e.Index = &idx
+		indexed[i] = e
+	}
+	return indexed
+}
+
+// ValidationErrorResponse is returned with 422 when a request fails validation
+type ValidationErrorResponse struct {
+	ErrorCode        string            `json:"error_code"`
+	Error            string            `json:"error"`
+	ValidationErrors []ValidationError `json:"validation_errors"`
+}
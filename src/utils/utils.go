@@ -1,8 +1,13 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
+	"unicode"
 )
 
 func RemoveTrailingAnd(s string) string {
@@ -20,10 +25,15 @@ func QuoteAndEscapeStrings(input string) []string {
 		return []string{}
 	}
 
-	items := strings.Split(input, ",")
-	quoted := make([]string, 0, len(items))
+	return QuoteStrings(strings.Split(input, ","))
+}
+
+// QuoteStrings trims whitespace, drops empty values, escapes single quotes, and returns a
+// slice of SQL-quoted strings.
+func QuoteStrings(values []string) []string {
+	quoted := make([]string, 0, len(values))
 
-	for _, item := range items {
+	for _, item := range values {
 		item = strings.TrimSpace(item)
 		if item == "" {
 			continue
@@ -36,22 +46,177 @@ func QuoteAndEscapeStrings(input string) []string {
 	return quoted
 }
 
-// FormatStringsForLikeQuery takes an array of strings and returns them formatted
-// for SQL LIKE queries with wildcards. Example: ["abp", "bbc"] -> "'%abp%','%bbc%'"
-func FormatStringsForLikeQuery(values []string) string {
-	if len(values) == 0 {
-		return ""
+// likeEscaper escapes the LIKE wildcards and the default escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// LikeContainsPattern returns a LIKE pattern matching values that contain s. Wildcards in s
+// match literally. Example: "abp_news" -> "%abp\_news%"
+func LikeContainsPattern(s string) string {
+	return "%" + likeEscaper.Replace(strings.TrimSpace(s)) + "%"
+}
+
+// SplitSearchTerms splits a keyword query on whitespace while keeping double-quoted
+// phrases intact. Example: `modi "union budget" 2024` -> ["modi", "union budget", "2024"].
+// An unterminated quote extends to the end of the input.
+func SplitSearchTerms(input string) []string {
+	terms := []string{}
+	var current strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		term := strings.TrimSpace(current.String())
+		if term != "" {
+			terms = append(terms, term)
+		}
+		current.Reset()
 	}
 
-	patterns := make([]string, 0, len(values))
+	for _, r := range input {
+		switch {
+		case r == '"':
+			flush()
+			inQuotes = !inQuotes
+		case !inQuotes && unicode.IsSpace(r):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
 
-	for _, s := range values {
-		s = strings.TrimSpace(s)
-		if s == "" {
-			continue
+	return terms
+}
+
+// HashIdentifier returns a short, stable SHA-256 based fingerprint of a personal identifier
+// (user id, IP address) so it can be logged or stored without the raw value
+func HashIdentifier(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// trackingParams are query parameters that identify a campaign or click rather than content.
+// Parameters starting with utm_ are dropped as well.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_ga":     true,
+	"ref_src": true,
+}
+
+// CanonicalizeURL returns the form of an article URL used to recognize the same story arriving
+// under superficially different URLs: the scheme is upgraded to https, scheme and host are
+// lowercased, default ports, tracking parameters, the fragment and trailing slashes are
+// dropped, and the remaining query parameters are sorted. An error is returned for values that
+// are not absolute http(s) URLs.
+func CanonicalizeURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if (scheme != "http" && scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("not an absolute http(s) url: %q", raw)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+
+	canonical := url.URL{
+		Scheme:   "https",
+		User:     u.User,
+		Host:     host,
+		Path:     strings.TrimRight(u.Path, "/"),
+		RawPath:  strings.TrimRight(u.RawPath, "/"),
+		RawQuery: query.Encode(),
+	}
+	return canonical.String(), nil
+}
+
+// geohashAlphabet is the base32 alphabet of geohashes
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash returns the geohash of the cell of the given length containing lat/lon. Each
+// character narrows the cell by 5 bits, alternately of longitude and latitude.
+func Geohash(lat, lon float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	hash := make([]byte, 0, precision)
+	even := true
+	bit, index := 0, 0
+	for len(hash) < precision {
+		value, bounds := lat, &latRange
+		if even {
+			value, bounds = lon, &lonRange
 		}
-		patterns = append(patterns, fmt.Sprintf("'%s'", "%"+s+"%"))
+		mid := (bounds[0] + bounds[1]) / 2
+		index <<= 1
+		if value >= mid {
+			index |= 1
+			bounds[0] = mid
+		} else {
+			bounds[1] = mid
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			hash = append(hash, geohashAlphabet[index])
+			bit, index = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// GeohashCenter returns the center of a geohash cell, and false when hash is not a geohash
+func GeohashCenter(hash string) (lat, lon float64, ok bool) {
+	if hash == "" {
+		return 0, 0, false
 	}
 
-	return strings.Join(patterns, ",")
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	even := true
+	for _, c := range hash {
+		index := strings.IndexRune(geohashAlphabet, c)
+		if index < 0 {
+			return 0, 0, false
+		}
+		for shift := 4; shift >= 0; shift-- {
+			bounds := &latRange
+			if even {
+				bounds = &lonRange
+			}
+			mid := (bounds[0] + bounds[1]) / 2
+			if index>>shift&1 == 1 {
+				bounds[0] = mid
+			} else {
+				bounds[1] = mid
+			}
+			even = !even
+		}
+	}
+	return (latRange[0] + latRange[1]) / 2, (lonRange[0] + lonRange[1]) / 2, true
 }
@@ -0,0 +1,74 @@
+package utils
+
+import "testing"
+
+func TestCanonicalizeURL(t *testing.T) {
+	const canonical = "https://example.com/news/storm?id=42&page=2"
+
+	messy := []string{
+		"https://example.com/news/storm?id=42&page=2",
+		"http://example.com/news/storm?id=42&page=2",
+		"HTTPS://EXAMPLE.COM/news/storm?id=42&page=2",
+		"https://example.com/news/storm/?id=42&page=2",
+		"https://example.com/news/storm///?id=42&page=2",
+		"https://example.com/news/storm?page=2&id=42",
+		"https://example.com:443/news/storm?id=42&page=2",
+		"http://example.com:80/news/storm?id=42&page=2",
+		"https://example.com/news/storm?id=42&page=2#comments",
+		"https://example.com/news/storm?utm_source=twitter&id=42&utm_medium=social&page=2",
+		"https://example.com/news/storm?id=42&UTM_Campaign=monsoon&page=2",
+		"https://example.com/news/storm?fbclid=abc123&id=42&page=2&gclid=xyz",
+		"https://example.com/news/storm?id=42&page=2&_ga=1.2.3&mc_cid=9&ref_src=twsrc",
+		"  https://example.com/news/storm?id=42&page=2\n",
+		"http://Example.com:80/news/storm/?utm_source=rss&page=2&id=42#top",
+	}
+
+	for _, raw := range messy {
+		t.Run(raw, func(t *testing.T) {
+			got, err := CanonicalizeURL(raw)
+			if err != nil {
+				t.Fatalf("CanonicalizeURL(%q) failed: %v", raw, err)
+			}
+			if got != canonical {
+				t.Errorf("CanonicalizeURL(%q) = %q, want %q", raw, got, canonical)
+			}
+		})
+	}
+}
+
+// TestCanonicalizeURLKeepsContent checks that the parts identifying the content itself survive
+func TestCanonicalizeURLKeepsContent(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://example.com/News/Storm", "https://example.com/News/Storm"},
+		{"https://example.com:8443/news", "https://example.com:8443/news"},
+		{"https://news.example.com/storm", "https://news.example.com/storm"},
+		{"https://example.com/", "https://example.com"},
+		{"https://[2001:DB8::1]/news", "https://[2001:db8::1]/news"},
+		{"https://example.com/news%2Fstorm", "https://example.com/news%2Fstorm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := CanonicalizeURL(tt.raw)
+			if err != nil {
+				t.Fatalf("CanonicalizeURL(%q) failed: %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalizeURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeURLRejectsNonHTTP(t *testing.T) {
+	for _, raw := range []string{"", "example.com/news", "/news/storm", "ftp://example.com/news", "mailto:news@example.com", "https://", "http://exa mple.com"} {
+		t.Run(raw, func(t *testing.T) {
+			if got, err := CanonicalizeURL(raw); err == nil {
+				t.Errorf("CanonicalizeURL(%q) = %q, want an error", raw, got)
+			}
+		})
+	}
+}